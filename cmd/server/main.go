@@ -3,16 +3,40 @@ package main
 import (
 	"context"
 	"log"
+	"net"
 	nethttp "net/http"
 	"os"
 	"os/signal"
+	"strings"
 	"syscall"
 	"time"
 
+	"exchange/internal/adapters/blocklist"
 	"exchange/internal/adapters/config"
 	"exchange/internal/adapters/database"
+	"exchange/internal/adapters/oidc"
+	"exchange/internal/adapters/publisher"
+	"exchange/internal/adapters/sender"
+	"exchange/internal/adapters/storage"
+	"exchange/internal/domain/adjustment"
+	"exchange/internal/domain/audit"
+	"exchange/internal/domain/auth"
+	"exchange/internal/domain/escrow"
+	"exchange/internal/domain/event"
+	"exchange/internal/domain/interest"
+	"exchange/internal/domain/kyc"
+	"exchange/internal/domain/limit"
+	"exchange/internal/domain/payment"
+	"exchange/internal/domain/period"
+	"exchange/internal/domain/reserves"
+	"exchange/internal/domain/risk"
+	"exchange/internal/domain/sanctions"
+	"exchange/internal/domain/snapshot"
 	"exchange/internal/domain/transaction"
+	"exchange/internal/domain/user"
 	"exchange/internal/domain/wallet"
+	"exchange/internal/domain/webhook"
+	"exchange/internal/ports/grpc"
 	"exchange/internal/ports/http"
 	"exchange/internal/ports/persistence"
 	"exchange/internal/usecase"
@@ -39,16 +63,190 @@ func main() {
 
 	walletRepo := persistence.NewPostgresWalletRepository(db)
 	transactionRepo := persistence.NewPostgresTransactionRepository(db)
+	apiKeyRepo := persistence.NewPostgresAPIKeyRepository(db)
+	adjustmentRepo := persistence.NewPostgresAdjustmentRepository(db)
+	auditRepo := persistence.NewPostgresAuditRepository(db)
+	outboxRepo := persistence.NewPostgresOutboxRepository(db)
+	webhookRepo := persistence.NewPostgresWebhookRepository(db)
+	limitRepo := persistence.NewPostgresLimitRepository(db)
+	riskRepo := persistence.NewPostgresRiskRepository(db)
 
 	walletService := wallet.NewWalletService(walletRepo)
 	transactionService := transaction.NewTransactionService(transactionRepo)
+	secrets, err := auth.NewSecretBox(cfg.APIKeys.SecretKey)
+	if err != nil {
+		log.Fatalf("invalid api_keys.secret_key: %v", err)
+	}
+	apiKeyService := auth.NewAPIKeyService(apiKeyRepo, apiKeyRepo, secrets)
+	adjustmentService := adjustment.NewAdjustmentService(adjustmentRepo)
+	auditService := audit.NewAuditService(auditRepo, walletService)
+	eventService := event.NewEventService(outboxRepo)
+
+	tiers := make(map[limit.Tier][]limit.Limit, len(cfg.Limits.Tiers))
+	for tier, limits := range cfg.Limits.Tiers {
+		for _, c := range limits {
+			l := limit.Limit{
+				Operation: limit.Operation(c.Operation),
+				Currency:  strings.ToUpper(c.Currency),
+				Period:    limit.Period(c.Period),
+				MaxAmount: c.MaxAmount,
+				MaxCount:  c.MaxCount,
+			}
+			if err := l.Validate(); err != nil {
+				log.Fatalf("invalid limit %+v of tier %s: %v", c, tier, err)
+			}
+			tiers[limit.Tier(tier)] = append(tiers[limit.Tier(tier)], l)
+		}
+	}
+	limitService := limit.NewLimitService(limitRepo, tiers, limit.Tier(cfg.Limits.DefaultTier))
+
+	rules := make([]risk.Rule, 0, len(cfg.Risk.Rules))
+	for _, c := range cfg.Risk.Rules {
+		r, err := risk.NewRule(risk.RuleConfig{
+			ID:        c.ID,
+			Kind:      risk.Kind(c.Kind),
+			Decision:  risk.Decision(c.Decision),
+			Window:    c.Window,
+			Lookback:  c.Lookback,
+			Count:     c.Count,
+			Ratio:     c.Ratio,
+			MinAmount: c.MinAmount,
+		})
+		if err != nil {
+			log.Fatalf("invalid risk rule %+v: %v", c, err)
+		}
+		rules = append(rules, r)
+	}
+	riskService := risk.NewRiskService(riskRepo, rules)
+	userService := user.NewUserService(persistence.NewPostgresUserRepository(db))
+
+	documents, err := storage.NewLocal(cfg.KYC.StorageDir)
+	if err != nil {
+		log.Fatalf("failed to open KYC document storage: %v", err)
+	}
+	policy := make(kyc.Policy, len(cfg.KYC.Levels))
+	for level, c := range cfg.KYC.Levels {
+		if !kyc.Level(level).Valid() {
+			log.Fatalf("invalid KYC level %q", level)
+		}
+		policy[kyc.Level(level)] = kyc.Caps{MaxBalance: c.MaxBalance, MaxWithdrawal: c.MaxWithdrawal, MaxTransfer: c.MaxTransfer}
+	}
+	kycService := kyc.NewKYCService(persistence.NewPostgresKYCRepository(db), documents, policy)
+
+	var entries []sanctions.Entry
+	if cfg.Sanctions.File != "" {
+		if entries, err = blocklist.Load(cfg.Sanctions.File); err != nil {
+			log.Fatalf("failed to load sanctions list: %v", err)
+		}
+	}
+	threshold := cfg.Sanctions.Threshold
+	if threshold == 0 {
+		threshold = sanctions.DefaultThreshold
+	}
+	sanctionsList, err := sanctions.NewList(entries, threshold)
+	if err != nil {
+		log.Fatalf("invalid sanctions list: %v", err)
+	}
+	sanctionsService := sanctions.NewSanctionsService(persistence.NewPostgresSanctionsRepository(db), sanctionsList)
+	snapshotService := snapshot.NewSnapshotService(persistence.NewPostgresSnapshotRepository(db))
+	periodService := period.NewPeriodService(persistence.NewPostgresPeriodRepository(db))
+	reservesService := reserves.NewReservesService(persistence.NewPostgresReservesRepository(db))
+	escrowService := escrow.NewEscrowService(persistence.NewPostgresEscrowRepository(db))
+	requestService := payment.NewRequestService(persistence.NewPostgresPaymentRequestRepository(db))
+
+	products := make([]interest.Product, 0, len(cfg.Interest.Products))
+	houseAccounts := make([]string, 0, len(cfg.Interest.Products))
+	for _, c := range cfg.Interest.Products {
+		tiers := make([]interest.Tier, 0, len(c.Tiers))
+		for _, t := range c.Tiers {
+			tiers = append(tiers, interest.Tier{From: t.From, APY: t.APY})
+		}
+		p, err := interest.NewProduct(strings.ToUpper(c.Currency), c.HouseAccount, c.MinBalance, tiers)
+		if err != nil {
+			log.Fatalf("invalid interest product %q: %v", c.Currency, err)
+		}
+		products = append(products, p)
+		houseAccounts = append(houseAccounts, p.HouseAccount)
+	}
+	interestService := interest.NewInterestService(persistence.NewPostgresInterestRepository(db), products)
 
 	txManager := persistence.NewPostgresTransactionManager(db)
 
-	walletUC := usecase.NewWalletUseCase(walletService, transactionService, txManager)
+	// Viper lowercases map keys, while currencies are compared in upper case.
+	thresholds := make(map[string]int64, len(cfg.Withdrawals.ApprovalThresholds))
+	for currency, threshold := range cfg.Withdrawals.ApprovalThresholds {
+		thresholds[strings.ToUpper(currency)] = threshold
+	}
+	walletUC := usecase.NewWalletUseCase(usecase.WalletDependencies{
+		Wallets:      walletService,
+		Transactions: transactionService,
+		TxManager:    txManager,
+		Audit:        auditService,
+		Events:       eventService,
+		Limits:       limitService,
+		Risk:         riskService,
+		Users:        userService,
+		KYC:          kycService,
+		Sanctions:    sanctionsService,
+		Snapshots:    snapshotService,
+		Reserves:     reservesService,
+		WithdrawalPolicy: usecase.WithdrawalPolicy{
+			Thresholds:   thresholds,
+			NewWalletAge: cfg.Withdrawals.NewWalletAge,
+			ApprovalTTL:  cfg.Withdrawals.ApprovalTTL,
+		},
+		HouseAccounts: houseAccounts,
+	})
+	transactionUC := usecase.NewTransactionUseCase(transactionService)
+	userUC := usecase.NewUserUseCase(userService, kycService)
+	escrowUC := usecase.NewEscrowUseCase(walletUC, escrowService)
+	interestUC := usecase.NewInterestUseCase(walletUC, interestService)
+	paymentRequestUC := usecase.NewPaymentRequestUseCase(walletUC, requestService)
+	adminUC := usecase.NewAdminUseCase(walletUC, adjustmentService, periodService, cfg.Admin.ApprovalThreshold)
+
+	webhookUC := usecase.NewWebhookUseCase(
+		webhook.NewWebhookService(webhookRepo, webhookRepo, webhook.RetryPolicy{
+			MaxAttempts: cfg.Webhooks.MaxAttempts,
+			BaseDelay:   cfg.Webhooks.BaseDelay,
+			MaxDelay:    cfg.Webhooks.MaxDelay,
+		}),
+		sender.NewHTTP(cfg.Webhooks.Timeout),
+		txManager,
+		cfg.Webhooks.BatchSize,
+		cfg.Webhooks.Timeout,
+	)
+
+	// Webhook deliveries are queued in the relay's transaction; the configured publisher
+	// receives every event as well.
+	eventPublisher := publisher.NewInProcess()
+	eventPublisher.Subscribe(webhookUC.HandleEvent)
+	switch cfg.Events.Publisher {
+	case "file":
+		p, f, err := publisher.OpenFile(cfg.Events.File)
+		if err != nil {
+			log.Fatalf("failed to open event file: %v", err)
+		}
+		defer f.Close()
+		eventPublisher.Subscribe(p.Publish)
+	case "inprocess":
+	default:
+		eventPublisher.Subscribe(publisher.NewWriter(os.Stdout).Publish)
+	}
+	relay := usecase.NewEventRelay(eventService, eventPublisher, txManager, cfg.Events.BatchSize)
 
 	handler := http.NewHandler(walletUC)
-	router := http.NewRouter(handler)
+	authenticator := http.ChainAuthenticator{http.NewAPIKeyAuthenticator(apiKeyService)}
+	grpcAuthenticator := grpc.ChainAuthenticator{grpc.NewAPIKeyAuthenticator(apiKeyService)}
+	if cfg.JWT.JWKS != "" {
+		keys := oidc.NewKeySet(oidc.KeySetConfig{Source: cfg.JWT.JWKS, RefreshInterval: cfg.JWT.RefreshInterval})
+		verifier, err := oidc.NewVerifier(keys, oidc.VerifierConfig{Issuer: cfg.JWT.Issuer, Audience: cfg.JWT.Audience})
+		if err != nil {
+			log.Fatalf("failed to configure JWT authentication: %v", err)
+		}
+		authenticator = append(authenticator, http.NewBearerAuthenticator(verifier))
+		grpcAuthenticator = append(grpcAuthenticator, grpc.NewBearerAuthenticator(verifier))
+	}
+	router := http.NewRouter(authenticator, handler, http.NewAdminHandler(adminUC), http.NewWebhookHandler(webhookUC), http.NewUserHandler(userUC), http.NewEscrowHandler(escrowUC), http.NewInterestHandler(interestUC), http.NewPaymentRequestHandler(paymentRequestUC))
 
 	srv := &nethttp.Server{
 		Addr:         cfg.Server.Address,
@@ -58,6 +256,8 @@ func main() {
 		IdleTimeout:  120 * time.Second,
 	}
 
+	grpcSrv := grpc.NewServer(grpcAuthenticator, grpc.NewHandler(walletUC, transactionUC))
+
 	ctx, cancel := context.WithCancel(context.Background())
 
 	stop := make(chan os.Signal, 1)
@@ -68,6 +268,21 @@ func main() {
 		cancel()
 	}()
 
+	go relay.Run(ctx, cfg.Events.RelayInterval)
+	go webhookUC.Run(ctx, cfg.Webhooks.DispatchInterval)
+	go walletUC.RunWithdrawalExpiry(ctx, cfg.Withdrawals.ExpiryInterval)
+	go escrowUC.RunEscrowExpiry(ctx, cfg.Escrows.ExpiryInterval)
+	go paymentRequestUC.RunPaymentRequestExpiry(ctx, cfg.PaymentRequests.ExpiryInterval)
+	if cfg.Snapshots.Interval > 0 {
+		go walletUC.RunBalanceSnapshots(ctx, cfg.Snapshots.Interval, cfg.Snapshots.Delay)
+	}
+	if cfg.Reserves.Interval > 0 {
+		go walletUC.RunLiabilityPublishing(ctx, cfg.Reserves.Interval, cfg.Reserves.Delay)
+	}
+	if len(products) > 0 {
+		go interestUC.RunInterest(ctx, cfg.Interest.Delay)
+	}
+
 	go func() {
 		log.Printf("Starting server on %s", cfg.Server.Address)
 		if err := srv.ListenAndServe(); err != nil && err != nethttp.ErrServerClosed {
@@ -75,6 +290,17 @@ func main() {
 		}
 	}()
 
+	go func() {
+		lis, err := net.Listen("tcp", cfg.GRPC.Address)
+		if err != nil {
+			log.Fatalf("failed to listen for gRPC: %v", err)
+		}
+		log.Printf("Starting gRPC server on %s", cfg.GRPC.Address)
+		if err := grpcSrv.Serve(lis); err != nil {
+			log.Fatalf("gRPC Serve: %v", err)
+		}
+	}()
+
 	<-ctx.Done()
 
 	log.Println("Shutting down server...")
@@ -84,6 +310,7 @@ func main() {
 	if err := srv.Shutdown(shutdownCtx); err != nil {
 		log.Fatalf("Server Shutdown Failed:%+v", err)
 	}
+	grpcSrv.GracefulStop()
 
 	log.Println("Server exited properly.")
 }
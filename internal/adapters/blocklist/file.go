@@ -0,0 +1,105 @@
+package blocklist
+
+import (
+	"encoding/csv"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"exchange/internal/domain/sanctions"
+)
+
+// csvColumns are the columns of a CSV list, in this order after a header row. Aliases and
+// identifiers hold several values separated by semicolons.
+var csvColumns = []string{"id", "name", "aliases", "identifiers", "program"}
+
+// jsonEntry is an entry of a JSON list, which is an array of these objects.
+type jsonEntry struct {
+	ID          string   `json:"id"`
+	Name        string   `json:"name"`
+	Aliases     []string `json:"aliases"`
+	Identifiers []string `json:"identifiers"`
+	Program     string   `json:"program"`
+}
+
+// Load reads the sanctions list in path, a CSV or JSON file told apart by its extension.
+func Load(path string) ([]sanctions.Entry, error) {
+	f, err := os.Open(path)
+	if err != nil {
+		return nil, err
+	}
+	defer f.Close()
+
+	switch ext := strings.ToLower(filepath.Ext(path)); ext {
+	case ".csv":
+		return readCSV(f)
+	case ".json":
+		return readJSON(f)
+	default:
+		return nil, fmt.Errorf("unsupported sanctions list format %q", ext)
+	}
+}
+
+func readCSV(r io.Reader) ([]sanctions.Entry, error) {
+	reader := csv.NewReader(r)
+	reader.FieldsPerRecord = len(csvColumns)
+	reader.TrimLeadingSpace = true
+
+	header, err := reader.Read()
+	if errors.Is(err, io.EOF) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+	for i, column := range csvColumns {
+		if strings.ToLower(strings.TrimSpace(header[i])) != column {
+			return nil, fmt.Errorf("sanctions list header must be %s", strings.Join(csvColumns, ","))
+		}
+	}
+
+	var entries []sanctions.Entry
+	for {
+		record, err := reader.Read()
+		if errors.Is(err, io.EOF) {
+			return entries, nil
+		}
+		if err != nil {
+			return nil, err
+		}
+		entries = append(entries, sanctions.Entry{
+			ID:          strings.TrimSpace(record[0]),
+			Name:        strings.TrimSpace(record[1]),
+			Aliases:     splitList(record[2]),
+			Identifiers: splitList(record[3]),
+			Program:     strings.TrimSpace(record[4]),
+		})
+	}
+}
+
+func readJSON(r io.Reader) ([]sanctions.Entry, error) {
+	var raw []jsonEntry
+	if err := json.NewDecoder(r).Decode(&raw); err != nil {
+		return nil, err
+	}
+	entries := make([]sanctions.Entry, 0, len(raw))
+	for _, e := range raw {
+		entries = append(entries, sanctions.Entry(e))
+	}
+	return entries, nil
+}
+
+// splitList splits a semicolon separated CSV field, dropping empty values.
+func splitList(field string) []string {
+	var values []string
+	for _, v := range strings.Split(field, ";") {
+		if v = strings.TrimSpace(v); v != "" {
+			values = append(values, v)
+		}
+	}
+	return values
+}
@@ -0,0 +1,53 @@
+package blocklist
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	"exchange/internal/domain/sanctions"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestLoad(t *testing.T) {
+	want := []sanctions.Entry{
+		{ID: "sdn-1", Name: "John Doe", Aliases: []string{"Johnny Doe", "J. Doe"}, Program: "SDN"},
+		{ID: "sdn-2", Name: "Acme Trading LLC", Identifiers: []string{"0xabc123"}, Program: "SDN"},
+	}
+	dir := t.TempDir()
+	files := map[string]string{
+		"list.csv": "id,name,aliases,identifiers,program\n" +
+			"sdn-1,John Doe,Johnny Doe; J. Doe,,SDN\n" +
+			"sdn-2,Acme Trading LLC,,0xabc123,SDN\n",
+		"list.json": `[
+			{"id":"sdn-1","name":"John Doe","aliases":["Johnny Doe","J. Doe"],"program":"SDN"},
+			{"id":"sdn-2","name":"Acme Trading LLC","identifiers":["0xabc123"],"program":"SDN"}
+		]`,
+	}
+	for name, content := range files {
+		path := filepath.Join(dir, name)
+		require.NoError(t, os.WriteFile(path, []byte(content), 0o600))
+
+		entries, err := Load(path)
+		require.NoError(t, err, name)
+		assert.Equal(t, want, entries, name)
+	}
+
+	for name, content := range map[string]string{
+		"bad-header.csv": "name,id,aliases,identifiers,program\n",
+		"short-row.csv":  "id,name,aliases,identifiers,program\nsdn-1,John Doe\n",
+		"object.json":    `{"id":"sdn-1"}`,
+		"list.txt":       "John Doe\n",
+	} {
+		path := filepath.Join(dir, name)
+		require.NoError(t, os.WriteFile(path, []byte(content), 0o600))
+
+		_, err := Load(path)
+		assert.Error(t, err, name)
+	}
+
+	_, err := Load(filepath.Join(dir, "missing.csv"))
+	assert.Error(t, err)
+}
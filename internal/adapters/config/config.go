@@ -1,6 +1,8 @@
 package config
 
 import (
+	"time"
+
 	"github.com/spf13/viper"
 )
 
@@ -16,6 +18,138 @@ type Config struct {
 	Server struct {
 		Address string
 	}
+	GRPC struct {
+		Address string
+	}
+	// APIKeys configures how API key secrets are kept.
+	APIKeys struct {
+		// SecretKey is the hex-encoded 32-byte key API key secrets are encrypted with. Set it
+		// through the API_KEYS_SECRET_KEY environment variable rather than in this file.
+		SecretKey string `mapstructure:"secret_key"`
+	} `mapstructure:"api_keys"`
+	Admin struct {
+		// ApprovalThreshold is the largest adjustment applied without a second admin's approval.
+		ApprovalThreshold int64 `mapstructure:"approval_threshold"`
+	}
+	// JWT enables bearer token authentication when JWKS is set.
+	JWT struct {
+		JWKS            string        // JWKS is the path or URL of the identity provider's key set.
+		Issuer          string        // Issuer is the required iss claim.
+		Audience        string        // Audience is the required aud claim.
+		RefreshInterval time.Duration `mapstructure:"refresh_interval"`
+	}
+	// Events configures delivery of the domain events stored in the outbox.
+	Events struct {
+		Publisher     string        // Publisher is "stdout", "file" or "inprocess", which only feeds webhooks.
+		File          string        // File is the path appended to by the "file" publisher.
+		RelayInterval time.Duration `mapstructure:"relay_interval"`
+		BatchSize     int           `mapstructure:"batch_size"`
+	}
+	// Webhooks configures how deliveries to webhook subscribers are sent and retried.
+	Webhooks struct {
+		MaxAttempts      int           `mapstructure:"max_attempts"` // MaxAttempts is the number of failures after which a delivery is dead.
+		BaseDelay        time.Duration `mapstructure:"base_delay"`   // BaseDelay is the wait after the first failure; it doubles after each further one.
+		MaxDelay         time.Duration `mapstructure:"max_delay"`
+		Timeout          time.Duration // Timeout bounds each request to a subscriber.
+		DispatchInterval time.Duration `mapstructure:"dispatch_interval"`
+		BatchSize        int           `mapstructure:"batch_size"`
+	}
+	// Withdrawals configures which withdrawals wait for an admin's approval.
+	Withdrawals struct {
+		ApprovalThresholds map[string]int64 `mapstructure:"approval_thresholds"` // ApprovalThresholds maps a currency to the largest withdrawal made without approval.
+		NewWalletAge       time.Duration    `mapstructure:"new_wallet_age"`      // NewWalletAge is how long after creation every withdrawal from a wallet needs approval.
+		ApprovalTTL        time.Duration    `mapstructure:"approval_ttl"`        // ApprovalTTL is how long a withdrawal awaits approval before it is cancelled.
+		ExpiryInterval     time.Duration    `mapstructure:"expiry_interval"`
+	}
+	// Limits configures the default withdrawal and transfer limits of each account tier.
+	Limits struct {
+		DefaultTier string                   `mapstructure:"default_tier"` // DefaultTier applies to users without an assigned tier.
+		Tiers       map[string][]LimitConfig // Tiers maps a tier to its limits; users' overrides replace them.
+	}
+	// Risk declares the rules every withdrawal and transfer is assessed against.
+	Risk struct {
+		Rules []RiskRuleConfig
+	}
+	// KYC configures identity verification: where submitted documents are kept and what
+	// users at each level may do.
+	KYC struct {
+		StorageDir string                   `mapstructure:"storage_dir"`
+		Levels     map[string]KYCCapsConfig // Levels maps "none", "basic" or "full" to its caps; missing levels are uncapped.
+	}
+	// Sanctions configures the screening of users and withdrawal destinations.
+	Sanctions struct {
+		File      string  // File is the CSV or JSON sanctions list; empty screens against an empty list.
+		Threshold float64 // Threshold is the name similarity, from 0 to 1, from which a name hits the list.
+	}
+	// Snapshots configures the periodic balance snapshots historical balances start from.
+	Snapshots struct {
+		Interval time.Duration // Interval is the time between snapshots; zero disables them.
+		Delay    time.Duration // Delay is how long after each cutoff its snapshot is taken.
+	}
+	// Reserves configures the periodic publishing of the liability trees behind proofs of
+	// reserves.
+	Reserves struct {
+		Interval time.Duration // Interval is the time between publications; zero disables them.
+		Delay    time.Duration // Delay is how long after each cutoff its tree is built.
+	}
+	// Escrows configures the sweeper settling escrows nobody settled before they expired.
+	Escrows struct {
+		ExpiryInterval time.Duration `mapstructure:"expiry_interval"`
+	}
+	// PaymentRequests configures the sweeper closing payment requests nobody paid before
+	// they expired.
+	PaymentRequests struct {
+		ExpiryInterval time.Duration `mapstructure:"expiry_interval"`
+	} `mapstructure:"payment_requests"`
+	// Interest declares the interest products wallets earn on; currencies without one
+	// earn nothing.
+	Interest struct {
+		Delay    time.Duration // Delay is how long after midnight UTC the previous day is accrued.
+		Products []InterestProductConfig
+	}
+}
+
+// LimitConfig caps the volume and count of one operation in one currency per period.
+type LimitConfig struct {
+	Operation string // Operation is "withdrawal" or "transfer".
+	Currency  string
+	Period    string // Period is "daily" or "monthly".
+	MaxAmount int64  `mapstructure:"max_amount"` // MaxAmount is 0 for no cap on the amount.
+	MaxCount  int    `mapstructure:"max_count"`  // MaxCount is 0 for no cap on the number of operations.
+}
+
+// RiskRuleConfig declares a risk rule; which fields matter depends on its kind.
+type RiskRuleConfig struct {
+	ID        string
+	Kind      string        // Kind is "new_counterparty_velocity", "drain_after_deposit" or "round_trip".
+	Decision  string        // Decision is "review" or "block".
+	Window    time.Duration // Window is how far back the pattern is looked for.
+	Lookback  time.Duration // Lookback is how far back counterparties count as known.
+	Count     int
+	Ratio     float64 // Ratio is the share of the available balance a withdrawal drains.
+	MinAmount int64   `mapstructure:"min_amount"` // MinAmount is the smallest deposit a drain follows.
+}
+
+// KYCCapsConfig caps the wallets of users at a KYC level; 0 leaves a cap off.
+type KYCCapsConfig struct {
+	MaxBalance    int64 `mapstructure:"max_balance"`
+	MaxWithdrawal int64 `mapstructure:"max_withdrawal"` // MaxWithdrawal is the largest single withdrawal.
+	MaxTransfer   int64 `mapstructure:"max_transfer"`   // MaxTransfer is the largest single transfer sent or received.
+}
+
+// InterestProductConfig declares the interest paid on wallets in one currency.
+type InterestProductConfig struct {
+	Currency     string
+	HouseAccount string               `mapstructure:"house_account"` // HouseAccount is the user ID of the wallet interest is paid from.
+	MinBalance   int64                `mapstructure:"min_balance"`   // MinBalance is the smallest end-of-day balance that earns interest.
+	Tiers        []InterestTierConfig // Tiers must start from 0.
+}
+
+// InterestTierConfig is the APY, in percent, earned by the part of a balance from From
+// minor units up to the next tier.
+type InterestTierConfig struct {
+	From int64
+	APY  float64
 }
 
 func LoadConfig() (*Config, error) {
@@ -25,6 +159,9 @@ func LoadConfig() (*Config, error) {
 	if err := viper.ReadInConfig(); err != nil {
 		return nil, err
 	}
+	if err := viper.BindEnv("api_keys.secret_key", "API_KEYS_SECRET_KEY"); err != nil {
+		return nil, err
+	}
 
 	var cfg Config
 	if err := viper.Unmarshal(&cfg); err != nil {
@@ -0,0 +1,276 @@
+package oidc
+
+import (
+	"context"
+	"crypto"
+	"crypto/ecdsa"
+	"crypto/ed25519"
+	"crypto/elliptic"
+	"crypto/rsa"
+	"encoding/base64"
+	"encoding/json"
+	"fmt"
+	"io"
+	"log"
+	"math/big"
+	"net/http"
+	"os"
+	"strings"
+	"sync"
+	"time"
+
+	"exchange/internal/domain/auth"
+)
+
+const (
+	DefaultRefreshInterval    = 15 * time.Minute
+	DefaultMinRefreshInterval = time.Minute
+
+	// maxKeySetSize bounds the JWKS document read from a URL.
+	maxKeySetSize = 1 << 20
+)
+
+type KeySetConfig struct {
+	// Source is the path or http(s) URL of the JWKS document.
+	Source string
+	// RefreshInterval is how long a loaded document is trusted before it is reloaded.
+	RefreshInterval time.Duration
+	// MinRefreshInterval is the shortest time between two reloads, which bounds how often
+	// tokens naming an unknown key ID can make the key set go back to the source.
+	MinRefreshInterval time.Duration
+	HTTPClient         *http.Client
+}
+
+// KeySet caches the signing keys of a JWKS document. Keys are reloaded when the cache
+// is older than RefreshInterval or a token names a key ID the cache does not hold, so
+// keys rotated by the identity provider are picked up without a restart. The document
+// is fetched outside the lock, once for all callers waiting on it, so a slow source
+// never holds up tokens signed with cached keys.
+type KeySet struct {
+	cfg KeySetConfig
+	now func() time.Time
+
+	mu          sync.Mutex
+	keys        map[string]crypto.PublicKey
+	loadedAt    time.Time
+	lastAttempt time.Time
+	lastErr     error
+	loading     chan struct{} // loading is closed when the reload in flight, if any, is done.
+}
+
+func NewKeySet(cfg KeySetConfig) *KeySet {
+	if cfg.RefreshInterval <= 0 {
+		cfg.RefreshInterval = DefaultRefreshInterval
+	}
+	if cfg.MinRefreshInterval <= 0 {
+		cfg.MinRefreshInterval = DefaultMinRefreshInterval
+	}
+	if cfg.HTTPClient == nil {
+		cfg.HTTPClient = &http.Client{Timeout: 10 * time.Second}
+	}
+	return &KeySet{
+		cfg: cfg,
+		now: time.Now,
+	}
+}
+
+// Key returns the public key identified by kid.
+func (s *KeySet) Key(ctx context.Context, kid string) (crypto.PublicKey, error) {
+	for {
+		s.mu.Lock()
+		key, known := s.keys[kid]
+		if s.loading != nil {
+			loading := s.loading
+			s.mu.Unlock()
+			if known {
+				// Another caller is reloading; the cached key stays valid meanwhile.
+				return key, nil
+			}
+			// Wait for the reload in flight rather than starting another one.
+			select {
+			case <-loading:
+				continue
+			case <-ctx.Done():
+				return nil, ctx.Err()
+			}
+		}
+
+		now := s.now()
+		canReload := now.Sub(s.lastAttempt) >= s.cfg.MinRefreshInterval
+		stale := s.keys == nil || now.Sub(s.loadedAt) >= s.cfg.RefreshInterval
+		if canReload && (stale || !known) {
+			s.loading = make(chan struct{})
+			s.lastAttempt = now
+			s.mu.Unlock()
+			s.reload(ctx, now)
+			continue
+		}
+		keys, lastErr := s.keys, s.lastErr
+		s.mu.Unlock()
+
+		if keys == nil {
+			return nil, lastErr
+		}
+		if !known {
+			return nil, auth.ErrUnknownSigningKey
+		}
+		return key, nil
+	}
+}
+
+// reload fetches the document without holding the lock and then swaps in its keys. A
+// failed reload keeps the previous keys until the source recovers.
+func (s *KeySet) reload(ctx context.Context, now time.Time) {
+	// Callers waiting on this reload must not fail because the one that started it left.
+	keys, err := s.load(context.WithoutCancel(ctx))
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if err != nil {
+		s.lastErr = err
+		if s.keys != nil {
+			log.Println("jwks reload failed, using cached keys:", err)
+		}
+	} else {
+		s.keys = keys
+		s.loadedAt = now
+	}
+	close(s.loading)
+	s.loading = nil
+}
+
+func (s *KeySet) load(ctx context.Context) (map[string]crypto.PublicKey, error) {
+	data, err := s.fetch(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("failed to load jwks from %s: %w", s.cfg.Source, err)
+	}
+	keys, err := parseKeySet(data)
+	if err != nil {
+		return nil, fmt.Errorf("failed to parse jwks from %s: %w", s.cfg.Source, err)
+	}
+	return keys, nil
+}
+
+func (s *KeySet) fetch(ctx context.Context) ([]byte, error) {
+	if !strings.HasPrefix(s.cfg.Source, "http://") && !strings.HasPrefix(s.cfg.Source, "https://") {
+		return os.ReadFile(strings.TrimPrefix(s.cfg.Source, "file://"))
+	}
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, s.cfg.Source, nil)
+	if err != nil {
+		return nil, err
+	}
+	resp, err := s.cfg.HTTPClient.Do(req)
+	if err != nil {
+		return nil, err
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusOK {
+		return nil, fmt.Errorf("unexpected status %s", resp.Status)
+	}
+	return io.ReadAll(io.LimitReader(resp.Body, maxKeySetSize))
+}
+
+type jsonWebKey struct {
+	Kty string `json:"kty"`
+	Kid string `json:"kid"`
+	Use string `json:"use"`
+	Crv string `json:"crv"`
+	N   string `json:"n"`
+	E   string `json:"e"`
+	X   string `json:"x"`
+	Y   string `json:"y"`
+}
+
+// parseKeySet decodes the signature keys of a JWKS document. Encryption keys and key
+// types this service cannot verify with are skipped.
+func parseKeySet(data []byte) (map[string]crypto.PublicKey, error) {
+	var doc struct {
+		Keys []jsonWebKey `json:"keys"`
+	}
+	if err := json.Unmarshal(data, &doc); err != nil {
+		return nil, err
+	}
+
+	keys := make(map[string]crypto.PublicKey, len(doc.Keys))
+	for _, jwk := range doc.Keys {
+		if jwk.Use != "" && jwk.Use != "sig" {
+			continue
+		}
+		key, err := jwk.publicKey()
+		if err != nil {
+			return nil, fmt.Errorf("key %q: %w", jwk.Kid, err)
+		}
+		if key != nil {
+			keys[jwk.Kid] = key
+		}
+	}
+	return keys, nil
+}
+
+func (k jsonWebKey) publicKey() (crypto.PublicKey, error) {
+	switch k.Kty {
+	case "RSA":
+		n, err := decodeBigInt(k.N)
+		if err != nil {
+			return nil, err
+		}
+		e, err := decodeBigInt(k.E)
+		if err != nil {
+			return nil, err
+		}
+		if !e.IsInt64() || e.Int64() < 3 || e.Int64() > 1<<31-1 {
+			return nil, fmt.Errorf("invalid RSA exponent")
+		}
+		return &rsa.PublicKey{N: n, E: int(e.Int64())}, nil
+	case "EC":
+		var curve elliptic.Curve
+		switch k.Crv {
+		case "P-256":
+			curve = elliptic.P256()
+		case "P-384":
+			curve = elliptic.P384()
+		case "P-521":
+			curve = elliptic.P521()
+		default:
+			return nil, nil
+		}
+		x, err := decodeBigInt(k.X)
+		if err != nil {
+			return nil, err
+		}
+		y, err := decodeBigInt(k.Y)
+		if err != nil {
+			return nil, err
+		}
+		if !curve.IsOnCurve(x, y) {
+			return nil, fmt.Errorf("point is not on curve %s", k.Crv)
+		}
+		return &ecdsa.PublicKey{Curve: curve, X: x, Y: y}, nil
+	case "OKP":
+		if k.Crv != "Ed25519" {
+			return nil, nil
+		}
+		x, err := base64.RawURLEncoding.DecodeString(k.X)
+		if err != nil {
+			return nil, err
+		}
+		if len(x) != ed25519.PublicKeySize {
+			return nil, fmt.Errorf("invalid Ed25519 key size")
+		}
+		return ed25519.PublicKey(x), nil
+	}
+	return nil, nil
+}
+
+func decodeBigInt(s string) (*big.Int, error) {
+	b, err := base64.RawURLEncoding.DecodeString(s)
+	if err != nil {
+		return nil, err
+	}
+	if len(b) == 0 {
+		return nil, fmt.Errorf("empty key parameter")
+	}
+	return new(big.Int).SetBytes(b), nil
+}
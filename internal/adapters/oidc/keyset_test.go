@@ -0,0 +1,231 @@
+package oidc
+
+import (
+	"context"
+	"crypto"
+	"crypto/ecdsa"
+	"crypto/ed25519"
+	"crypto/elliptic"
+	"crypto/rand"
+	"crypto/rsa"
+	"encoding/base64"
+	"encoding/json"
+	"math/big"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"sync"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"exchange/internal/domain/auth"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func b64(b []byte) string {
+	return base64.RawURLEncoding.EncodeToString(b)
+}
+
+// toJWK encodes the public half of key as a JSON Web Key.
+func toJWK(t *testing.T, kid string, key crypto.PublicKey) map[string]string {
+	t.Helper()
+
+	switch k := key.(type) {
+	case *rsa.PublicKey:
+		return map[string]string{"kty": "RSA", "kid": kid, "use": "sig", "n": b64(k.N.Bytes()), "e": b64(big.NewInt(int64(k.E)).Bytes())}
+	case *ecdsa.PublicKey:
+		size := (k.Curve.Params().BitSize + 7) / 8
+		return map[string]string{"kty": "EC", "kid": kid, "crv": k.Curve.Params().Name, "x": b64(k.X.FillBytes(make([]byte, size))), "y": b64(k.Y.FillBytes(make([]byte, size)))}
+	case ed25519.PublicKey:
+		return map[string]string{"kty": "OKP", "kid": kid, "crv": "Ed25519", "x": b64(k)}
+	}
+	t.Fatalf("unsupported key type %T", key)
+	return nil
+}
+
+func keySetDocument(t *testing.T, keys ...map[string]string) []byte {
+	t.Helper()
+
+	data, err := json.Marshal(map[string]any{"keys": keys})
+	require.NoError(t, err)
+	return data
+}
+
+// jwksServer serves a JWKS document that tests can replace to simulate key rotation.
+type jwksServer struct {
+	*httptest.Server
+	mu       sync.Mutex
+	document []byte
+	requests int
+}
+
+func newJWKSServer(t *testing.T, document []byte) *jwksServer {
+	t.Helper()
+
+	s := &jwksServer{document: document}
+	s.Server = httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		s.mu.Lock()
+		defer s.mu.Unlock()
+		s.requests++
+		if s.document == nil {
+			http.Error(w, "unavailable", http.StatusServiceUnavailable)
+			return
+		}
+		w.Write(s.document)
+	}))
+	t.Cleanup(s.Close)
+	return s
+}
+
+func (s *jwksServer) set(document []byte) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.document = document
+}
+
+func (s *jwksServer) count() int {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return s.requests
+}
+
+func TestKeySet_KeyTypes(t *testing.T) {
+	rsaKey, err := rsa.GenerateKey(rand.Reader, 2048)
+	require.NoError(t, err)
+	ecKey, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	require.NoError(t, err)
+	edKey, _, err := ed25519.GenerateKey(rand.Reader)
+	require.NoError(t, err)
+
+	path := filepath.Join(t.TempDir(), "jwks.json")
+	document := keySetDocument(t,
+		toJWK(t, "rsa", &rsaKey.PublicKey),
+		toJWK(t, "ec", &ecKey.PublicKey),
+		toJWK(t, "ed", edKey),
+		map[string]string{"kty": "RSA", "kid": "enc", "use": "enc", "n": "AQAB", "e": "AQAB"},
+		map[string]string{"kty": "oct", "kid": "hmac", "k": "c2VjcmV0"},
+	)
+	require.NoError(t, os.WriteFile(path, document, 0o600))
+
+	keys := NewKeySet(KeySetConfig{Source: path})
+	ctx := context.Background()
+
+	key, err := keys.Key(ctx, "rsa")
+	require.NoError(t, err)
+	assert.True(t, rsaKey.PublicKey.Equal(key))
+
+	key, err = keys.Key(ctx, "ec")
+	require.NoError(t, err)
+	assert.True(t, ecKey.PublicKey.Equal(key))
+
+	key, err = keys.Key(ctx, "ed")
+	require.NoError(t, err)
+	assert.True(t, edKey.Equal(key))
+
+	for _, kid := range []string{"enc", "hmac", "missing"} {
+		_, err = keys.Key(ctx, kid)
+		assert.ErrorIs(t, err, auth.ErrUnknownSigningKey, kid)
+	}
+}
+
+func TestKeySet_Rotation(t *testing.T) {
+	oldKey, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	require.NoError(t, err)
+	newKey, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	require.NoError(t, err)
+
+	server := newJWKSServer(t, keySetDocument(t, toJWK(t, "old", &oldKey.PublicKey)))
+	now := time.Date(2024, 3, 1, 12, 0, 0, 0, time.UTC)
+	keys := NewKeySet(KeySetConfig{Source: server.URL, RefreshInterval: time.Hour, MinRefreshInterval: time.Minute})
+	keys.now = func() time.Time { return now }
+	ctx := context.Background()
+
+	_, err = keys.Key(ctx, "old")
+	require.NoError(t, err)
+	_, err = keys.Key(ctx, "old")
+	require.NoError(t, err)
+	assert.Equal(t, 1, server.count(), "keys must be served from the cache")
+
+	server.set(keySetDocument(t, toJWK(t, "old", &oldKey.PublicKey), toJWK(t, "new", &newKey.PublicKey)))
+
+	_, err = keys.Key(ctx, "new")
+	assert.ErrorIs(t, err, auth.ErrUnknownSigningKey, "reloads are rate limited")
+	assert.Equal(t, 1, server.count())
+
+	now = now.Add(time.Minute)
+	key, err := keys.Key(ctx, "new")
+	require.NoError(t, err, "an unknown key ID triggers a reload")
+	assert.True(t, newKey.PublicKey.Equal(key))
+	assert.Equal(t, 2, server.count())
+
+	server.set(nil)
+	now = now.Add(2 * time.Hour)
+	_, err = keys.Key(ctx, "old")
+	require.NoError(t, err, "cached keys are kept while the source is unavailable")
+	assert.Equal(t, 3, server.count())
+
+	server.set(keySetDocument(t, toJWK(t, "new", &newKey.PublicKey)))
+	now = now.Add(time.Minute)
+	_, err = keys.Key(ctx, "old")
+	assert.ErrorIs(t, err, auth.ErrUnknownSigningKey, "retired keys are dropped")
+}
+
+func TestKeySet_UnavailableSource(t *testing.T) {
+	server := newJWKSServer(t, nil)
+	keys := NewKeySet(KeySetConfig{Source: server.URL})
+
+	_, err := keys.Key(context.Background(), "any")
+
+	assert.Error(t, err)
+	assert.NotErrorIs(t, err, auth.ErrUnknownSigningKey)
+}
+
+func TestKeySet_ConcurrentReload(t *testing.T) {
+	oldKey, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	require.NoError(t, err)
+	newKey, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	require.NoError(t, err)
+
+	var requests atomic.Int32
+	documents := make(chan []byte, 1)
+	documents <- keySetDocument(t, toJWK(t, "old", &oldKey.PublicKey))
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		requests.Add(1)
+		w.Write(<-documents)
+	}))
+	t.Cleanup(server.Close)
+
+	now := time.Date(2024, 3, 1, 12, 0, 0, 0, time.UTC)
+	keys := NewKeySet(KeySetConfig{Source: server.URL, RefreshInterval: time.Hour, MinRefreshInterval: time.Minute})
+	keys.now = func() time.Time { return now }
+	ctx := context.Background()
+
+	_, err = keys.Key(ctx, "old")
+	require.NoError(t, err)
+	now = now.Add(time.Minute)
+
+	// The source holds the reload triggered by "new" until a document is sent.
+	const waiters = 5
+	results := make(chan error, waiters)
+	for i := 0; i < waiters; i++ {
+		go func() {
+			_, err := keys.Key(ctx, "new")
+			results <- err
+		}()
+	}
+	require.Eventually(t, func() bool { return requests.Load() == 2 }, time.Second, time.Millisecond)
+
+	key, err := keys.Key(ctx, "old")
+	require.NoError(t, err, "cached keys are served while the source is slow")
+	assert.True(t, oldKey.PublicKey.Equal(key))
+
+	documents <- keySetDocument(t, toJWK(t, "old", &oldKey.PublicKey), toJWK(t, "new", &newKey.PublicKey))
+	for i := 0; i < waiters; i++ {
+		assert.NoError(t, <-results)
+	}
+	assert.Equal(t, int32(2), requests.Load(), "callers waiting on one reload share its fetch")
+}
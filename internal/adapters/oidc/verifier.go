@@ -0,0 +1,104 @@
+package oidc
+
+import (
+	"context"
+	"crypto"
+	"errors"
+	"strings"
+	"time"
+
+	"exchange/internal/domain/auth"
+
+	"github.com/golang-jwt/jwt/v5"
+)
+
+// DefaultLeeway is the clock skew tolerated when checking exp, nbf and iat.
+const DefaultLeeway = 30 * time.Second
+
+// signingMethods are the asymmetric algorithms accepted in tokens. Symmetric algorithms
+// are never accepted, so a public key can not be abused as an HMAC secret.
+var signingMethods = []string{"RS256", "RS384", "RS512", "PS256", "PS384", "PS512", "ES256", "ES384", "ES512", "EdDSA"}
+
+type KeyProvider interface {
+	Key(ctx context.Context, kid string) (crypto.PublicKey, error)
+}
+
+// ErrIssuerAudienceRequired is returned by NewVerifier when the issuer or audience is
+// unset; without both, tokens minted by the identity provider for other services would
+// be accepted.
+var ErrIssuerAudienceRequired = errors.New("oidc: issuer and audience are required")
+
+type VerifierConfig struct {
+	// Issuer and Audience must match the iss and aud claims.
+	Issuer   string
+	Audience string
+	Leeway   time.Duration
+}
+
+// Verifier validates OAuth2/OIDC access tokens signed by keys from a KeyProvider and
+// maps their claims to a principal: sub is the user ID, roles the roles, and scope (or
+// scp) the scopes, which grant permissions through auth.PermissionsFromScopes.
+type Verifier struct {
+	keys   KeyProvider
+	parser *jwt.Parser
+}
+
+type tokenClaims struct {
+	jwt.RegisteredClaims
+	Scope string   `json:"scope"`
+	Scp   []string `json:"scp"`
+	Roles []string `json:"roles"`
+}
+
+func NewVerifier(keys KeyProvider, cfg VerifierConfig) (*Verifier, error) {
+	if cfg.Issuer == "" || cfg.Audience == "" {
+		return nil, ErrIssuerAudienceRequired
+	}
+	if cfg.Leeway <= 0 {
+		cfg.Leeway = DefaultLeeway
+	}
+	opts := []jwt.ParserOption{
+		jwt.WithValidMethods(signingMethods),
+		jwt.WithExpirationRequired(),
+		jwt.WithIssuedAt(),
+		jwt.WithLeeway(cfg.Leeway),
+		jwt.WithIssuer(cfg.Issuer),
+		jwt.WithAudience(cfg.Audience),
+	}
+	return &Verifier{
+		keys:   keys,
+		parser: jwt.NewParser(opts...),
+	}, nil
+}
+
+func (v *Verifier) VerifyToken(ctx context.Context, token string) (auth.Principal, error) {
+	var keyErr error
+	var claims tokenClaims
+	_, err := v.parser.ParseWithClaims(token, &claims, func(t *jwt.Token) (interface{}, error) {
+		kid, _ := t.Header["kid"].(string)
+		key, err := v.keys.Key(ctx, kid)
+		keyErr = err
+		return key, err
+	})
+	if err != nil {
+		// Failing to load the keys is not the caller's fault.
+		if keyErr != nil && !errors.Is(keyErr, auth.ErrUnknownSigningKey) {
+			return auth.Principal{}, keyErr
+		}
+		return auth.Principal{}, auth.ErrInvalidToken
+	}
+	if claims.Subject == "" {
+		return auth.Principal{}, auth.ErrInvalidToken
+	}
+
+	scopes := claims.Scp
+	if claims.Scope != "" {
+		scopes = strings.Fields(claims.Scope)
+	}
+	return auth.Principal{
+		UserID:      claims.Subject,
+		Permissions: auth.PermissionsFromScopes(scopes),
+		Roles:       claims.Roles,
+		Scopes:      scopes,
+	}, nil
+}
@@ -0,0 +1,173 @@
+package oidc
+
+import (
+	"context"
+	"crypto"
+	"crypto/ecdsa"
+	"crypto/elliptic"
+	"crypto/rand"
+	"crypto/rsa"
+	"crypto/x509"
+	"errors"
+	"testing"
+	"time"
+
+	"exchange/internal/domain/auth"
+
+	"github.com/golang-jwt/jwt/v5"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+type staticKeys map[string]crypto.PublicKey
+
+func (s staticKeys) Key(ctx context.Context, kid string) (crypto.PublicKey, error) {
+	key, ok := s[kid]
+	if !ok {
+		return nil, auth.ErrUnknownSigningKey
+	}
+	return key, nil
+}
+
+type failingKeys struct{}
+
+func (failingKeys) Key(ctx context.Context, kid string) (crypto.PublicKey, error) {
+	return nil, errors.New("jwks unavailable")
+}
+
+func TestVerifier_VerifyToken(t *testing.T) {
+	ctx := context.Background()
+	rsaKey, err := rsa.GenerateKey(rand.Reader, 2048)
+	require.NoError(t, err)
+	ecKey, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	require.NoError(t, err)
+
+	cfg := VerifierConfig{
+		Issuer:   "https://idp.example.com",
+		Audience: "wallet-api",
+	}
+	verifier, err := NewVerifier(staticKeys{"rsa": &rsaKey.PublicKey, "ec": &ecKey.PublicKey}, cfg)
+	require.NoError(t, err)
+
+	validClaims := func() jwt.MapClaims {
+		now := time.Now()
+		return jwt.MapClaims{
+			"iss":   "https://idp.example.com",
+			"aud":   "wallet-api",
+			"sub":   "user1",
+			"iat":   now.Unix(),
+			"exp":   now.Add(time.Hour).Unix(),
+			"scope": "openid wallet:read wallet:trade",
+			"roles": []string{"customer"},
+		}
+	}
+	mint := func(method jwt.SigningMethod, kid string, key any, claims jwt.MapClaims) string {
+		token := jwt.NewWithClaims(method, claims)
+		token.Header["kid"] = kid
+		signed, err := token.SignedString(key)
+		require.NoError(t, err)
+		return signed
+	}
+
+	t.Run("valid RSA token", func(t *testing.T) {
+		principal, err := verifier.VerifyToken(ctx, mint(jwt.SigningMethodRS256, "rsa", rsaKey, validClaims()))
+
+		require.NoError(t, err)
+		assert.Equal(t, "user1", principal.UserID)
+		assert.Equal(t, []auth.Permission{auth.PermissionRead, auth.PermissionTrade}, principal.Permissions)
+		assert.Equal(t, []string{"customer"}, principal.Roles)
+		assert.Equal(t, []string{"openid", "wallet:read", "wallet:trade"}, principal.Scopes)
+	})
+
+	t.Run("valid EC token with scp claim", func(t *testing.T) {
+		claims := validClaims()
+		delete(claims, "scope")
+		claims["scp"] = []string{"wallet:withdraw"}
+		claims["roles"] = []string{auth.RoleAdmin}
+
+		principal, err := verifier.VerifyToken(ctx, mint(jwt.SigningMethodES256, "ec", ecKey, claims))
+
+		require.NoError(t, err)
+		assert.Equal(t, []auth.Permission{auth.PermissionWithdraw}, principal.Permissions)
+		assert.True(t, principal.HasRole(auth.RoleAdmin))
+	})
+
+	invalid := map[string]func() string{
+		"expired": func() string {
+			claims := validClaims()
+			claims["exp"] = time.Now().Add(-time.Hour).Unix()
+			return mint(jwt.SigningMethodRS256, "rsa", rsaKey, claims)
+		},
+		"missing expiry": func() string {
+			claims := validClaims()
+			delete(claims, "exp")
+			return mint(jwt.SigningMethodRS256, "rsa", rsaKey, claims)
+		},
+		"wrong issuer": func() string {
+			claims := validClaims()
+			claims["iss"] = "https://evil.example.com"
+			return mint(jwt.SigningMethodRS256, "rsa", rsaKey, claims)
+		},
+		"wrong audience": func() string {
+			claims := validClaims()
+			claims["aud"] = "another-api"
+			return mint(jwt.SigningMethodRS256, "rsa", rsaKey, claims)
+		},
+		"missing subject": func() string {
+			claims := validClaims()
+			delete(claims, "sub")
+			return mint(jwt.SigningMethodRS256, "rsa", rsaKey, claims)
+		},
+		"unknown key": func() string {
+			return mint(jwt.SigningMethodRS256, "rotated-out", rsaKey, validClaims())
+		},
+		"signed by another key": func() string {
+			other, err := rsa.GenerateKey(rand.Reader, 2048)
+			require.NoError(t, err)
+			return mint(jwt.SigningMethodRS256, "rsa", other, validClaims())
+		},
+		"key of another type": func() string {
+			return mint(jwt.SigningMethodES256, "rsa", ecKey, validClaims())
+		},
+		"public key as HMAC secret": func() string {
+			der, err := x509.MarshalPKIXPublicKey(&rsaKey.PublicKey)
+			require.NoError(t, err)
+			return mint(jwt.SigningMethodHS256, "rsa", der, validClaims())
+		},
+		"unsigned": func() string {
+			return mint(jwt.SigningMethodNone, "rsa", jwt.UnsafeAllowNoneSignatureType, validClaims())
+		},
+		"malformed": func() string {
+			return "not-a-jwt"
+		},
+	}
+	for name, token := range invalid {
+		t.Run(name, func(t *testing.T) {
+			_, err := verifier.VerifyToken(ctx, token())
+			assert.Equal(t, auth.ErrInvalidToken, err)
+		})
+	}
+
+	t.Run("key source failure", func(t *testing.T) {
+		failing, err := NewVerifier(failingKeys{}, cfg)
+		require.NoError(t, err)
+
+		_, err = failing.VerifyToken(ctx, mint(jwt.SigningMethodRS256, "rsa", rsaKey, validClaims()))
+
+		assert.Error(t, err)
+		assert.NotEqual(t, auth.ErrInvalidToken, err)
+	})
+}
+
+func TestNewVerifier_RequiresIssuerAndAudience(t *testing.T) {
+	for name, cfg := range map[string]VerifierConfig{
+		"no issuer":   {Audience: "wallet-api"},
+		"no audience": {Issuer: "https://idp.example.com"},
+		"neither":     {},
+	} {
+		t.Run(name, func(t *testing.T) {
+			_, err := NewVerifier(staticKeys{}, cfg)
+			assert.ErrorIs(t, err, ErrIssuerAudienceRequired)
+		})
+	}
+}
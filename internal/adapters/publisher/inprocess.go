@@ -0,0 +1,51 @@
+package publisher
+
+import (
+	"context"
+	"sync"
+
+	"exchange/internal/domain/event"
+)
+
+// Handler consumes an event. Returning an error makes the relay deliver the event again.
+type Handler func(ctx context.Context, e event.Event) error
+
+// InProcess delivers events synchronously to handlers registered in the same process.
+type InProcess struct {
+	mu       sync.RWMutex
+	handlers map[event.Type][]Handler
+	all      []Handler
+}
+
+func NewInProcess() *InProcess {
+	return &InProcess{handlers: map[event.Type][]Handler{}}
+}
+
+// Subscribe registers h for events of the given types, or for every event when no type
+// is given.
+func (p *InProcess) Subscribe(h Handler, types ...event.Type) {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	if len(types) == 0 {
+		p.all = append(p.all, h)
+		return
+	}
+	for _, t := range types {
+		p.handlers[t] = append(p.handlers[t], h)
+	}
+}
+
+// Publish calls the handlers in registration order and stops at the first error, so a
+// redelivery may reach handlers that already succeeded.
+func (p *InProcess) Publish(ctx context.Context, e event.Event) error {
+	p.mu.RLock()
+	handlers := append(append([]Handler(nil), p.handlers[e.Type]...), p.all...)
+	p.mu.RUnlock()
+
+	for _, h := range handlers {
+		if err := h(ctx, e); err != nil {
+			return err
+		}
+	}
+	return nil
+}
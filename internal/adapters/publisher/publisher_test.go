@@ -0,0 +1,85 @@
+package publisher
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	"exchange/internal/domain/event"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func testEvent(t *testing.T, id string, eventType event.Type) event.Event {
+	t.Helper()
+	e, err := event.NewEvent(id, eventType, "user1", event.WalletCreated{UserID: "user1", Currency: "USD"})
+	require.NoError(t, err)
+	return e
+}
+
+func TestInProcess_Publish(t *testing.T) {
+	ctx := context.Background()
+	p := NewInProcess()
+
+	var created, all []string
+	p.Subscribe(func(_ context.Context, e event.Event) error {
+		created = append(created, e.ID)
+		return nil
+	}, event.TypeWalletCreated)
+	p.Subscribe(func(_ context.Context, e event.Event) error {
+		all = append(all, e.ID)
+		return nil
+	})
+
+	require.NoError(t, p.Publish(ctx, testEvent(t, "ev1", event.TypeWalletCreated)))
+	require.NoError(t, p.Publish(ctx, testEvent(t, "ev2", event.TypeFundsDeposited)))
+	assert.Equal(t, []string{"ev1"}, created)
+	assert.Equal(t, []string{"ev1", "ev2"}, all)
+
+	p.Subscribe(func(context.Context, event.Event) error { return errors.New("consumer down") }, event.TypeFundsDeposited)
+	assert.EqualError(t, p.Publish(ctx, testEvent(t, "ev3", event.TypeFundsDeposited)), "consumer down")
+}
+
+func TestWriter_Publish(t *testing.T) {
+	var buf bytes.Buffer
+	p := NewWriter(&buf)
+	ctx := context.Background()
+
+	require.NoError(t, p.Publish(ctx, testEvent(t, "ev1", event.TypeWalletCreated)))
+	require.NoError(t, p.Publish(ctx, testEvent(t, "ev2", event.TypeWalletCreated)))
+
+	lines := strings.Split(strings.TrimSpace(buf.String()), "\n")
+	require.Len(t, lines, 2)
+	var line struct {
+		ID      string          `json:"id"`
+		Type    string          `json:"type"`
+		Payload json.RawMessage `json:"payload"`
+	}
+	require.NoError(t, json.Unmarshal([]byte(lines[1]), &line))
+	assert.Equal(t, "ev2", line.ID)
+	assert.Equal(t, "WalletCreated", line.Type)
+	assert.JSONEq(t, `{"user_id":"user1","currency":"USD"}`, string(line.Payload))
+}
+
+func TestOpenFile_Appends(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "events.jsonl")
+	for i := 0; i < 2; i++ {
+		p, f, err := OpenFile(path)
+		require.NoError(t, err)
+		e := testEvent(t, "ev1", event.TypeWalletCreated)
+		e.OccurredAt = time.Unix(0, 0)
+		require.NoError(t, p.Publish(context.Background(), e))
+		require.NoError(t, f.Close())
+	}
+
+	data, err := os.ReadFile(path)
+	require.NoError(t, err)
+	assert.Equal(t, 2, strings.Count(string(data), "\n"))
+}
@@ -0,0 +1,59 @@
+package publisher
+
+import (
+	"context"
+	"encoding/json"
+	"io"
+	"os"
+	"sync"
+	"time"
+
+	"exchange/internal/domain/event"
+)
+
+// Writer publishes events as JSON lines, for local development and debugging.
+type Writer struct {
+	mu sync.Mutex
+	w  io.Writer
+}
+
+func NewWriter(w io.Writer) *Writer {
+	return &Writer{w: w}
+}
+
+// OpenFile returns a Writer appending to the file at path, creating it if needed. The
+// caller must close the returned file.
+func OpenFile(path string) (*Writer, *os.File, error) {
+	f, err := os.OpenFile(path, os.O_APPEND|os.O_CREATE|os.O_WRONLY, 0o644)
+	if err != nil {
+		return nil, nil, err
+	}
+	return NewWriter(f), f, nil
+}
+
+// eventLine is the JSON form of an event written by Writer.
+type eventLine struct {
+	ID          string          `json:"id"`
+	Type        event.Type      `json:"type"`
+	AggregateID string          `json:"aggregate_id"`
+	Payload     json.RawMessage `json:"payload"`
+	OccurredAt  time.Time       `json:"occurred_at"`
+}
+
+func (p *Writer) Publish(_ context.Context, e event.Event) error {
+	line, err := json.Marshal(eventLine{
+		ID:          e.ID,
+		Type:        e.Type,
+		AggregateID: e.AggregateID,
+		Payload:     e.Payload,
+		OccurredAt:  e.OccurredAt,
+	})
+	if err != nil {
+		return err
+	}
+
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	_, err = p.w.Write(append(line, '\n'))
+	return err
+}
@@ -0,0 +1,90 @@
+package sender
+
+import (
+	"bytes"
+	"context"
+	"io"
+	"net"
+	"net/http"
+	"net/netip"
+	"strconv"
+	"syscall"
+	"time"
+
+	"exchange/internal/domain/webhook"
+)
+
+// Headers set on every delivery. Receivers verify HeaderSignature against the raw body
+// and HeaderTimestamp with webhook.Sign.
+const (
+	HeaderEventID   = "X-Webhook-Event-ID"
+	HeaderEventType = "X-Webhook-Event-Type"
+	HeaderTimestamp = "X-Webhook-Timestamp"
+	HeaderSignature = "X-Webhook-Signature"
+
+	DefaultTimeout = 10 * time.Second
+
+	// maxResponseSize bounds how much of a response body is drained so the connection
+	// can be reused.
+	maxResponseSize = 64 << 10
+)
+
+// HTTP POSTs webhook deliveries.
+type HTTP struct {
+	client *http.Client
+	// allow reports whether connections to an address may be opened.
+	allow func(netip.Addr) bool
+}
+
+// NewHTTP returns a sender whose requests give up after timeout. Redirects are not
+// followed, so a subscriber cannot bounce signed payloads to another host. Connections
+// are only opened to public addresses, checked after the host name is resolved so a
+// subscriber can not point its DNS records at internal services once registered.
+func NewHTTP(timeout time.Duration) *HTTP {
+	if timeout <= 0 {
+		timeout = DefaultTimeout
+	}
+	s := &HTTP{allow: webhook.PublicAddress}
+	dialer := &net.Dialer{Timeout: timeout, Control: s.control}
+	transport := http.DefaultTransport.(*http.Transport).Clone()
+	// A proxy would connect on the sender's behalf, out of reach of the address check.
+	transport.Proxy = nil
+	transport.DialContext = dialer.DialContext
+	s.client = &http.Client{
+		Timeout:   timeout,
+		Transport: transport,
+		CheckRedirect: func(*http.Request, []*http.Request) error {
+			return http.ErrUseLastResponse
+		},
+	}
+	return s
+}
+
+// control runs before every connection is made, with the resolved address.
+func (s *HTTP) control(_, address string, _ syscall.RawConn) error {
+	addr, err := netip.ParseAddrPort(address)
+	if err != nil || !s.allow(addr.Addr()) {
+		return webhook.ErrNonPublicAddress
+	}
+	return nil
+}
+
+func (s *HTTP) Send(ctx context.Context, req webhook.Request) (int, error) {
+	httpReq, err := http.NewRequestWithContext(ctx, http.MethodPost, req.URL, bytes.NewReader(req.Body))
+	if err != nil {
+		return 0, err
+	}
+	httpReq.Header.Set("Content-Type", "application/json")
+	httpReq.Header.Set(HeaderEventID, req.EventID)
+	httpReq.Header.Set(HeaderEventType, string(req.EventType))
+	httpReq.Header.Set(HeaderTimestamp, strconv.FormatInt(req.Timestamp, 10))
+	httpReq.Header.Set(HeaderSignature, req.Signature)
+
+	resp, err := s.client.Do(httpReq)
+	if err != nil {
+		return 0, err
+	}
+	defer resp.Body.Close()
+	_, _ = io.Copy(io.Discard, io.LimitReader(resp.Body, maxResponseSize))
+	return resp.StatusCode, nil
+}
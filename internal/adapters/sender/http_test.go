@@ -0,0 +1,100 @@
+package sender
+
+import (
+	"context"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"net/netip"
+	"net/url"
+	"strconv"
+	"testing"
+	"time"
+
+	"exchange/internal/domain/event"
+	"exchange/internal/domain/webhook"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// newLoopbackHTTP returns a sender that may reach the httptest servers on loopback.
+func newLoopbackHTTP(timeout time.Duration) *HTTP {
+	s := NewHTTP(timeout)
+	s.allow = func(addr netip.Addr) bool { return addr.IsLoopback() }
+	return s
+}
+
+func TestHTTP_Send(t *testing.T) {
+	var received *http.Request
+	var body []byte
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		received = r
+		body, _ = io.ReadAll(r.Body)
+		w.WriteHeader(http.StatusAccepted)
+	}))
+	defer srv.Close()
+
+	payload := []byte(`{"id":"ev1"}`)
+	req := webhook.Request{
+		URL:       srv.URL,
+		EventID:   "ev1",
+		EventType: event.TypeFundsDeposited,
+		Timestamp: 1700000000,
+		Signature: webhook.Sign("whsec_test", 1700000000, payload),
+		Body:      payload,
+	}
+
+	status, err := newLoopbackHTTP(time.Second).Send(context.Background(), req)
+
+	require.NoError(t, err)
+	assert.Equal(t, http.StatusAccepted, status)
+	assert.Equal(t, http.MethodPost, received.Method)
+	assert.Equal(t, payload, body)
+	assert.Equal(t, "application/json", received.Header.Get("Content-Type"))
+	assert.Equal(t, "ev1", received.Header.Get(HeaderEventID))
+	assert.Equal(t, "FundsDeposited", received.Header.Get(HeaderEventType))
+	ts, err := strconv.ParseInt(received.Header.Get(HeaderTimestamp), 10, 64)
+	require.NoError(t, err)
+	assert.Equal(t, webhook.Sign("whsec_test", ts, body), received.Header.Get(HeaderSignature))
+}
+
+func TestHTTP_SendFailures(t *testing.T) {
+	redirect := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		http.Redirect(w, r, "http://example.invalid/", http.StatusFound)
+	}))
+	defer redirect.Close()
+
+	status, err := newLoopbackHTTP(time.Second).Send(context.Background(), webhook.Request{URL: redirect.URL})
+	require.NoError(t, err)
+	assert.Equal(t, http.StatusFound, status, "redirects are not followed")
+
+	slow := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		<-r.Context().Done()
+	}))
+	defer slow.Close()
+
+	_, err = newLoopbackHTTP(50*time.Millisecond).Send(context.Background(), webhook.Request{URL: slow.URL})
+	assert.Error(t, err)
+
+	closed := httptest.NewServer(http.NotFoundHandler())
+	closed.Close()
+	_, err = newLoopbackHTTP(time.Second).Send(context.Background(), webhook.Request{URL: closed.URL})
+	assert.Error(t, err)
+}
+
+func TestHTTP_SendNonPublicAddress(t *testing.T) {
+	var called bool
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		called = true
+	}))
+	defer srv.Close()
+	u, err := url.Parse(srv.URL)
+	require.NoError(t, err)
+
+	for _, target := range []string{srv.URL, "http://localhost:" + u.Port(), "http://169.254.169.254/latest/meta-data"} {
+		_, err := NewHTTP(time.Second).Send(context.Background(), webhook.Request{URL: target})
+		assert.ErrorIs(t, err, webhook.ErrNonPublicAddress, target)
+	}
+	assert.False(t, called)
+}
@@ -0,0 +1,71 @@
+package storage
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io/fs"
+	"os"
+	"path/filepath"
+
+	"exchange/internal/domain/kyc"
+)
+
+// Local keeps documents as files in a directory of the local file system.
+type Local struct {
+	dir string
+}
+
+// NewLocal returns a store writing to dir, which is created if it does not exist.
+func NewLocal(dir string) (*Local, error) {
+	if err := os.MkdirAll(dir, 0o700); err != nil {
+		return nil, err
+	}
+	return &Local{dir: dir}, nil
+}
+
+// Put writes content under key, replacing the file atomically so readers never see a
+// partial document.
+func (l *Local) Put(ctx context.Context, key string, content []byte) error {
+	path, err := l.path(key)
+	if err != nil {
+		return err
+	}
+
+	f, err := os.CreateTemp(l.dir, ".upload-*")
+	if err != nil {
+		return err
+	}
+	defer os.Remove(f.Name())
+
+	if _, err := f.Write(content); err != nil {
+		f.Close()
+		return err
+	}
+	if err := f.Close(); err != nil {
+		return err
+	}
+	return os.Rename(f.Name(), path)
+}
+
+func (l *Local) Get(ctx context.Context, key string) ([]byte, error) {
+	path, err := l.path(key)
+	if err != nil {
+		return nil, err
+	}
+
+	content, err := os.ReadFile(path)
+	if errors.Is(err, fs.ErrNotExist) {
+		return nil, kyc.ErrDocumentNotFound
+	}
+	return content, err
+}
+
+// path maps key to a file directly inside the directory; keys naming anything else are
+// rejected.
+func (l *Local) path(key string) (string, error) {
+	if key == "" || key != filepath.Base(key) || key == "." || key == ".." || key[0] == '.' {
+		return "", fmt.Errorf("invalid document key %q", key)
+	}
+	return filepath.Join(l.dir, key), nil
+}
@@ -0,0 +1,40 @@
+package storage
+
+import (
+	"context"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"exchange/internal/domain/kyc"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestLocal(t *testing.T) {
+	ctx := context.Background()
+	dir := filepath.Join(t.TempDir(), "kyc")
+	store, err := NewLocal(dir)
+	require.NoError(t, err)
+
+	require.NoError(t, store.Put(ctx, "kyc1", []byte("scan")))
+	require.NoError(t, store.Put(ctx, "kyc1", []byte("rescan")))
+
+	content, err := store.Get(ctx, "kyc1")
+	require.NoError(t, err)
+	assert.Equal(t, []byte("rescan"), content)
+
+	entries, err := os.ReadDir(dir)
+	require.NoError(t, err)
+	assert.Len(t, entries, 1, "no temporary files are left behind")
+
+	_, err = store.Get(ctx, "kyc2")
+	assert.ErrorIs(t, err, kyc.ErrDocumentNotFound)
+
+	for _, key := range []string{"", "..", "../kyc1", "a/b", ".upload-1"} {
+		assert.Error(t, store.Put(ctx, key, []byte("scan")), key)
+		_, err := store.Get(ctx, key)
+		assert.Error(t, err, key)
+	}
+}
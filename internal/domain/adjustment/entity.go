@@ -0,0 +1,114 @@
+package adjustment
+
+import (
+	"time"
+)
+
+type Direction string
+
+const (
+	DirectionCredit Direction = "credit" // Credit adds funds to the wallet.
+	DirectionDebit  Direction = "debit"  // Debit removes funds from the wallet.
+)
+
+func (d Direction) Valid() bool {
+	return d == DirectionCredit || d == DirectionDebit
+}
+
+// ReasonCode records why back-office staff changed a balance by hand.
+type ReasonCode string
+
+const (
+	ReasonCorrection ReasonCode = "correction" // Fixes a balance that is wrong because of a system error.
+	ReasonReversal   ReasonCode = "reversal"   // Reverses a mistaken deposit or withdrawal.
+	ReasonGoodwill   ReasonCode = "goodwill"   // Compensates the customer.
+	ReasonFeeRefund  ReasonCode = "fee_refund" // Refunds a fee charged outside the wallet.
+	ReasonChargeback ReasonCode = "chargeback" // Recovers funds after a card or bank chargeback.
+)
+
+func (r ReasonCode) Valid() bool {
+	switch r {
+	case ReasonCorrection, ReasonReversal, ReasonGoodwill, ReasonFeeRefund, ReasonChargeback:
+		return true
+	}
+	return false
+}
+
+type Status string
+
+const (
+	StatusPending  Status = "pending"  // Pending adjustments wait for a second admin's approval.
+	StatusApplied  Status = "applied"  // Applied adjustments have changed the wallet balance.
+	StatusRejected Status = "rejected" // Rejected adjustments never change the balance.
+)
+
+type Adjustment struct {
+	ID            string     // ID is the unique adjustment identifier.
+	UserID        string     // UserID is the owner of the adjusted wallet.
+	Direction     Direction  // Direction is whether the wallet is credited or debited.
+	Amount        int64      // Amount is expressed as an integer in the smallest currency unit.
+	Currency      string     // Currency is the currency code of the amount.
+	Reason        ReasonCode // Reason is why the adjustment was made.
+	Note          string     // Note is free text supporting the reason.
+	Status        Status     // Status is where the adjustment is in its approval flow.
+	RequestedBy   string     // RequestedBy is the admin who created the adjustment.
+	DecidedBy     string     // DecidedBy is the admin who approved or rejected it; empty when applied without approval.
+	TransactionID string     // TransactionID is the transaction that applied it.
+	EffectiveAt   time.Time  // EffectiveAt is the accounting date the adjustment is booked on; it is CreatedAt unless back-dated.
+	CreatedAt     time.Time  // CreatedAt is the timestamp when the adjustment was requested.
+	DecidedAt     *time.Time // DecidedAt is set once the adjustment has been applied or rejected.
+}
+
+func NewAdjustment(id, userID string, direction Direction, amount int64, currency string, reason ReasonCode, note, requestedBy string) (Adjustment, error) {
+	if userID == "" {
+		return Adjustment{}, ErrInvalidUserID
+	}
+	if !direction.Valid() {
+		return Adjustment{}, ErrInvalidDirection
+	}
+	if amount <= 0 {
+		return Adjustment{}, ErrInvalidAmount
+	}
+	if !reason.Valid() {
+		return Adjustment{}, ErrInvalidReasonCode
+	}
+	if requestedBy == "" {
+		return Adjustment{}, ErrInvalidRequester
+	}
+	now := time.Now()
+	return Adjustment{
+		ID:          id,
+		UserID:      userID,
+		Direction:   direction,
+		Amount:      amount,
+		Currency:    currency,
+		Reason:      reason,
+		Note:        note,
+		Status:      StatusPending,
+		RequestedBy: requestedBy,
+		EffectiveAt: now,
+		CreatedAt:   now,
+	}, nil
+}
+
+// BackDate books the adjustment on the earlier accounting date effectiveAt, to correct a
+// past period. Adjustments cannot be booked in the future.
+func (a *Adjustment) BackDate(effectiveAt time.Time) error {
+	if effectiveAt.After(a.CreatedAt) {
+		return ErrInvalidEffectiveAt
+	}
+	a.EffectiveAt = effectiveAt
+	return nil
+}
+
+// CanBeDecidedBy reports why adminID may not approve or reject the adjustment, if anything.
+// Enforcing a second admin is what makes the approval four-eyes.
+func (a Adjustment) CanBeDecidedBy(adminID string) error {
+	if a.Status != StatusPending {
+		return ErrAdjustmentNotPending
+	}
+	if adminID == "" || adminID == a.RequestedBy {
+		return ErrSelfApproval
+	}
+	return nil
+}
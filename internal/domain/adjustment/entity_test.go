@@ -0,0 +1,63 @@
+package adjustment
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestNewAdjustment(t *testing.T) {
+	a, err := NewAdjustment("adj1", "user1", DirectionCredit, 500, "USD", ReasonGoodwill, "late payout", "admin1")
+
+	assert.NoError(t, err)
+	assert.Equal(t, StatusPending, a.Status)
+	assert.Equal(t, "admin1", a.RequestedBy)
+	assert.Nil(t, a.DecidedAt)
+
+	tests := []struct {
+		name      string
+		userID    string
+		direction Direction
+		amount    int64
+		reason    ReasonCode
+		requester string
+		err       error
+	}{
+		{"missing user", "", DirectionCredit, 500, ReasonGoodwill, "admin1", ErrInvalidUserID},
+		{"invalid direction", "user1", "sideways", 500, ReasonGoodwill, "admin1", ErrInvalidDirection},
+		{"invalid amount", "user1", DirectionDebit, 0, ReasonGoodwill, "admin1", ErrInvalidAmount},
+		{"missing reason", "user1", DirectionDebit, 500, "", "admin1", ErrInvalidReasonCode},
+		{"unknown reason", "user1", DirectionDebit, 500, "because", "admin1", ErrInvalidReasonCode},
+		{"missing requester", "user1", DirectionDebit, 500, ReasonCorrection, "", ErrInvalidRequester},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			_, err := NewAdjustment("adj1", tt.userID, tt.direction, tt.amount, "USD", tt.reason, "", tt.requester)
+			assert.Equal(t, tt.err, err)
+		})
+	}
+}
+
+func TestAdjustment_BackDate(t *testing.T) {
+	a, err := NewAdjustment("adj1", "user1", DirectionCredit, 500, "USD", ReasonCorrection, "", "admin1")
+	assert.NoError(t, err)
+	assert.Equal(t, a.CreatedAt, a.EffectiveAt)
+
+	past := a.CreatedAt.AddDate(0, -1, 0)
+	assert.NoError(t, a.BackDate(past))
+	assert.Equal(t, past, a.EffectiveAt)
+
+	assert.Equal(t, ErrInvalidEffectiveAt, a.BackDate(a.CreatedAt.Add(time.Hour)))
+	assert.Equal(t, past, a.EffectiveAt)
+}
+
+func TestAdjustment_CanBeDecidedBy(t *testing.T) {
+	a := Adjustment{ID: "adj1", Status: StatusPending, RequestedBy: "admin1"}
+
+	assert.NoError(t, a.CanBeDecidedBy("admin2"))
+	assert.Equal(t, ErrSelfApproval, a.CanBeDecidedBy("admin1"))
+
+	a.Status = StatusApplied
+	assert.Equal(t, ErrAdjustmentNotPending, a.CanBeDecidedBy("admin2"))
+}
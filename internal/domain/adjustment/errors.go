@@ -0,0 +1,17 @@
+package adjustment
+
+import "errors"
+
+var (
+	ErrInvalidUserID        = errors.New("invalid user ID")
+	ErrInvalidDirection     = errors.New("invalid adjustment direction")
+	ErrInvalidAmount        = errors.New("invalid adjustment amount")
+	ErrInvalidReasonCode    = errors.New("invalid adjustment reason code")
+	ErrInvalidRequester     = errors.New("invalid adjustment requester")
+	ErrInvalidStatus        = errors.New("invalid adjustment status")
+	ErrInvalidEffectiveAt   = errors.New("adjustment effective date must not be in the future")
+	ErrAdjustmentNotFound   = errors.New("adjustment not found")
+	ErrAdjustmentNotPending = errors.New("adjustment is not pending")
+	ErrSelfApproval         = errors.New("adjustment must be decided by another admin")
+	ErrDatabaseFailure      = errors.New("database failure")
+)
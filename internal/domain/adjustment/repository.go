@@ -0,0 +1,19 @@
+package adjustment
+
+import (
+	"context"
+)
+
+type AdjustmentRepository interface {
+	CreateAdjustment(ctx context.Context, a Adjustment) error
+
+	GetAdjustmentByID(ctx context.Context, id string) (Adjustment, error)
+
+	// ListAdjustments returns adjustments with the given status, or all of them when status
+	// is empty, newest first.
+	ListAdjustments(ctx context.Context, status Status, limit, offset int) ([]Adjustment, error)
+
+	// DecideAdjustment stores the decision recorded on a and returns ErrAdjustmentNotPending
+	// if the stored adjustment has already been decided.
+	DecideAdjustment(ctx context.Context, a Adjustment) error
+}
@@ -0,0 +1,102 @@
+package adjustment
+
+import (
+	"context"
+	"errors"
+	"time"
+
+	"github.com/gofrs/uuid"
+)
+
+type AdjustmentServiceInterface interface {
+	RequestAdjustment(ctx context.Context, userID string, direction Direction, amount int64, currency string, reason ReasonCode, note, requestedBy string, effectiveAt time.Time) (Adjustment, error)
+	GetAdjustment(ctx context.Context, id string) (Adjustment, error)
+	ListAdjustments(ctx context.Context, status Status, limit, offset int) ([]Adjustment, error)
+	MarkApplied(ctx context.Context, a Adjustment, decidedBy, transactionID string) (Adjustment, error)
+	MarkRejected(ctx context.Context, a Adjustment, decidedBy string) (Adjustment, error)
+}
+
+type AdjustmentService struct {
+	repository AdjustmentRepository
+}
+
+func NewAdjustmentService(repo AdjustmentRepository) *AdjustmentService {
+	return &AdjustmentService{
+		repository: repo,
+	}
+}
+
+// RequestAdjustment records a pending adjustment, back-dated to effectiveAt unless it is
+// zero.
+func (s *AdjustmentService) RequestAdjustment(ctx context.Context, userID string, direction Direction, amount int64, currency string, reason ReasonCode, note, requestedBy string, effectiveAt time.Time) (Adjustment, error) {
+	id, err := uuid.NewV7()
+	if err != nil {
+		return Adjustment{}, err
+	}
+
+	a, err := NewAdjustment(id.String(), userID, direction, amount, currency, reason, note, requestedBy)
+	if err != nil {
+		return Adjustment{}, err
+	}
+	if !effectiveAt.IsZero() {
+		if err := a.BackDate(effectiveAt); err != nil {
+			return Adjustment{}, err
+		}
+	}
+	if err := s.repository.CreateAdjustment(ctx, a); err != nil {
+		return Adjustment{}, err
+	}
+	return a, nil
+}
+
+func (s *AdjustmentService) GetAdjustment(ctx context.Context, id string) (Adjustment, error) {
+	a, err := s.repository.GetAdjustmentByID(ctx, id)
+	if err != nil {
+		if errors.Is(err, ErrAdjustmentNotFound) {
+			return Adjustment{}, ErrAdjustmentNotFound
+		}
+		return Adjustment{}, ErrDatabaseFailure
+	}
+	return a, nil
+}
+
+func (s *AdjustmentService) ListAdjustments(ctx context.Context, status Status, limit, offset int) ([]Adjustment, error) {
+	switch status {
+	case "", StatusPending, StatusApplied, StatusRejected:
+	default:
+		return nil, ErrInvalidStatus
+	}
+
+	adjustments, err := s.repository.ListAdjustments(ctx, status, limit, offset)
+	if err != nil {
+		return nil, ErrDatabaseFailure
+	}
+	return adjustments, nil
+}
+
+// MarkApplied records that a pending adjustment was applied by transactionID. decidedBy
+// is empty when the adjustment did not need approval.
+func (s *AdjustmentService) MarkApplied(ctx context.Context, a Adjustment, decidedBy, transactionID string) (Adjustment, error) {
+	return s.decide(ctx, a, StatusApplied, decidedBy, transactionID)
+}
+
+func (s *AdjustmentService) MarkRejected(ctx context.Context, a Adjustment, decidedBy string) (Adjustment, error) {
+	return s.decide(ctx, a, StatusRejected, decidedBy, "")
+}
+
+func (s *AdjustmentService) decide(ctx context.Context, a Adjustment, status Status, decidedBy, transactionID string) (Adjustment, error) {
+	if a.Status != StatusPending {
+		return Adjustment{}, ErrAdjustmentNotPending
+	}
+
+	now := time.Now()
+	a.Status = status
+	a.DecidedBy = decidedBy
+	a.TransactionID = transactionID
+	a.DecidedAt = &now
+
+	if err := s.repository.DecideAdjustment(ctx, a); err != nil {
+		return Adjustment{}, err
+	}
+	return a, nil
+}
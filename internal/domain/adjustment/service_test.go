@@ -0,0 +1,170 @@
+package adjustment
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+)
+
+type MockAdjustmentRepository struct {
+	mock.Mock
+}
+
+func (m *MockAdjustmentRepository) CreateAdjustment(ctx context.Context, a Adjustment) error {
+	args := m.Called(ctx, a)
+	return args.Error(0)
+}
+
+func (m *MockAdjustmentRepository) GetAdjustmentByID(ctx context.Context, id string) (Adjustment, error) {
+	args := m.Called(ctx, id)
+	return args.Get(0).(Adjustment), args.Error(1)
+}
+
+func (m *MockAdjustmentRepository) ListAdjustments(ctx context.Context, status Status, limit, offset int) ([]Adjustment, error) {
+	args := m.Called(ctx, status, limit, offset)
+	return args.Get(0).([]Adjustment), args.Error(1)
+}
+
+func (m *MockAdjustmentRepository) DecideAdjustment(ctx context.Context, a Adjustment) error {
+	args := m.Called(ctx, a)
+	return args.Error(0)
+}
+
+func TestAdjustmentService_RequestAdjustment(t *testing.T) {
+	ctx := context.Background()
+
+	t.Run("successful request", func(t *testing.T) {
+		mockRepo := new(MockAdjustmentRepository)
+		service := NewAdjustmentService(mockRepo)
+		mockRepo.On("CreateAdjustment", ctx, mock.AnythingOfType("Adjustment")).Return(nil)
+
+		a, err := service.RequestAdjustment(ctx, "user1", DirectionDebit, 300, "USD", ReasonReversal, "duplicate deposit", "admin1", time.Time{})
+
+		assert.NoError(t, err)
+		assert.NotEmpty(t, a.ID)
+		assert.Equal(t, StatusPending, a.Status)
+		assert.Equal(t, a.CreatedAt, a.EffectiveAt)
+		mockRepo.AssertExpectations(t)
+	})
+
+	t.Run("back-dated request", func(t *testing.T) {
+		mockRepo := new(MockAdjustmentRepository)
+		service := NewAdjustmentService(mockRepo)
+		mockRepo.On("CreateAdjustment", ctx, mock.AnythingOfType("Adjustment")).Return(nil)
+		effectiveAt := time.Date(2024, 3, 31, 0, 0, 0, 0, time.UTC)
+
+		a, err := service.RequestAdjustment(ctx, "user1", DirectionCredit, 300, "USD", ReasonCorrection, "", "admin1", effectiveAt)
+
+		assert.NoError(t, err)
+		assert.Equal(t, effectiveAt, a.EffectiveAt)
+	})
+
+	t.Run("future effective date", func(t *testing.T) {
+		mockRepo := new(MockAdjustmentRepository)
+		service := NewAdjustmentService(mockRepo)
+
+		_, err := service.RequestAdjustment(ctx, "user1", DirectionCredit, 300, "USD", ReasonCorrection, "", "admin1", time.Now().Add(time.Hour))
+
+		assert.Equal(t, ErrInvalidEffectiveAt, err)
+		mockRepo.AssertNotCalled(t, "CreateAdjustment", mock.Anything, mock.Anything)
+	})
+
+	t.Run("invalid request is not stored", func(t *testing.T) {
+		mockRepo := new(MockAdjustmentRepository)
+		service := NewAdjustmentService(mockRepo)
+
+		_, err := service.RequestAdjustment(ctx, "user1", DirectionDebit, 300, "USD", "", "", "admin1", time.Time{})
+
+		assert.Equal(t, ErrInvalidReasonCode, err)
+		mockRepo.AssertNotCalled(t, "CreateAdjustment", mock.Anything, mock.Anything)
+	})
+}
+
+func TestAdjustmentService_GetAdjustment(t *testing.T) {
+	mockRepo := new(MockAdjustmentRepository)
+	service := NewAdjustmentService(mockRepo)
+	ctx := context.Background()
+
+	mockRepo.On("GetAdjustmentByID", ctx, "missing").Return(Adjustment{}, ErrAdjustmentNotFound)
+	mockRepo.On("GetAdjustmentByID", ctx, "broken").Return(Adjustment{}, errors.New("connection reset"))
+
+	_, err := service.GetAdjustment(ctx, "missing")
+	assert.Equal(t, ErrAdjustmentNotFound, err)
+
+	_, err = service.GetAdjustment(ctx, "broken")
+	assert.Equal(t, ErrDatabaseFailure, err)
+}
+
+func TestAdjustmentService_ListAdjustments(t *testing.T) {
+	mockRepo := new(MockAdjustmentRepository)
+	service := NewAdjustmentService(mockRepo)
+	ctx := context.Background()
+
+	expected := []Adjustment{{ID: "adj1", Status: StatusPending}}
+	mockRepo.On("ListAdjustments", ctx, StatusPending, 10, 0).Return(expected, nil)
+
+	adjustments, err := service.ListAdjustments(ctx, StatusPending, 10, 0)
+	assert.NoError(t, err)
+	assert.Equal(t, expected, adjustments)
+
+	_, err = service.ListAdjustments(ctx, "approved", 10, 0)
+	assert.Equal(t, ErrInvalidStatus, err)
+}
+
+func TestAdjustmentService_Decide(t *testing.T) {
+	ctx := context.Background()
+	pending := Adjustment{ID: "adj1", Status: StatusPending, RequestedBy: "admin1"}
+
+	t.Run("mark applied", func(t *testing.T) {
+		mockRepo := new(MockAdjustmentRepository)
+		service := NewAdjustmentService(mockRepo)
+		mockRepo.On("DecideAdjustment", ctx, mock.MatchedBy(func(a Adjustment) bool {
+			return a.Status == StatusApplied && a.DecidedBy == "admin2" && a.TransactionID == "tx1" && a.DecidedAt != nil
+		})).Return(nil)
+
+		a, err := service.MarkApplied(ctx, pending, "admin2", "tx1")
+
+		assert.NoError(t, err)
+		assert.Equal(t, StatusApplied, a.Status)
+		mockRepo.AssertExpectations(t)
+	})
+
+	t.Run("mark rejected", func(t *testing.T) {
+		mockRepo := new(MockAdjustmentRepository)
+		service := NewAdjustmentService(mockRepo)
+		mockRepo.On("DecideAdjustment", ctx, mock.MatchedBy(func(a Adjustment) bool {
+			return a.Status == StatusRejected && a.TransactionID == ""
+		})).Return(nil)
+
+		a, err := service.MarkRejected(ctx, pending, "admin2")
+
+		assert.NoError(t, err)
+		assert.Equal(t, StatusRejected, a.Status)
+	})
+
+	t.Run("concurrently decided", func(t *testing.T) {
+		mockRepo := new(MockAdjustmentRepository)
+		service := NewAdjustmentService(mockRepo)
+		mockRepo.On("DecideAdjustment", ctx, mock.Anything).Return(ErrAdjustmentNotPending)
+
+		_, err := service.MarkApplied(ctx, pending, "admin2", "tx1")
+
+		assert.Equal(t, ErrAdjustmentNotPending, err)
+	})
+
+	t.Run("already decided", func(t *testing.T) {
+		mockRepo := new(MockAdjustmentRepository)
+		service := NewAdjustmentService(mockRepo)
+		rejected := pending
+		rejected.Status = StatusRejected
+
+		_, err := service.MarkApplied(ctx, rejected, "admin2", "tx1")
+
+		assert.Equal(t, ErrAdjustmentNotPending, err)
+		mockRepo.AssertNotCalled(t, "DecideAdjustment", mock.Anything, mock.Anything)
+	})
+}
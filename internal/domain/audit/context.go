@@ -0,0 +1,21 @@
+package audit
+
+import "context"
+
+// Metadata describes who caused the actions performed while handling a request.
+type Metadata struct {
+	Actor     string
+	RequestID string
+	SourceIP  string
+}
+
+type metadataContextKey struct{}
+
+func WithMetadata(ctx context.Context, m Metadata) context.Context {
+	return context.WithValue(ctx, metadataContextKey{}, m)
+}
+
+func MetadataFromContext(ctx context.Context) (Metadata, bool) {
+	m, ok := ctx.Value(metadataContextKey{}).(Metadata)
+	return m, ok
+}
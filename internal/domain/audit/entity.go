@@ -0,0 +1,192 @@
+package audit
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"time"
+)
+
+// Action names a state-changing operation recorded in the audit log.
+type Action string
+
+const (
+	ActionWalletCreate      Action = "wallet.create"
+	ActionDeposit           Action = "wallet.deposit"
+	ActionWithdraw          Action = "wallet.withdraw"
+	ActionTransfer          Action = "wallet.transfer"
+	ActionBatchTransfer     Action = "wallet.batch_transfer" // Recorded once per transfer of a batch.
+	ActionSplitTransfer     Action = "wallet.split_transfer" // Recorded once per transfer of a split or collection.
+	ActionReverse           Action = "wallet.reverse"
+	ActionRefund            Action = "wallet.refund"
+	ActionRequestWithdrawal Action = "wallet.request_withdrawal"
+	ActionCancelWithdrawal  Action = "wallet.cancel_withdrawal"
+	ActionApproveWithdrawal Action = "wallet.approve_withdrawal"
+	ActionRejectWithdrawal  Action = "wallet.reject_withdrawal"
+	ActionExpireWithdrawal  Action = "wallet.expire_withdrawal"
+	ActionTransactionStatus Action = "transaction.update_status"
+	ActionAdjustmentRequest Action = "adjustment.request"
+	ActionAdjustmentApprove Action = "adjustment.approve"
+	ActionAdjustmentReject  Action = "adjustment.reject"
+	ActionAPIKeyIssue       Action = "api_key.issue"
+	ActionAPIKeyRevoke      Action = "api_key.revoke"
+	ActionUserStatus        Action = "user.update_status"
+	ActionKYCLevel          Action = "kyc.update_level"
+	ActionKYCApprove        Action = "kyc.approve"
+	ActionKYCReject         Action = "kyc.reject"
+	ActionClearSanctions    Action = "sanctions.clear_case"
+	ActionPeriodClose       Action = "period.close"
+	ActionEscrowCreate      Action = "escrow.create"
+	ActionEscrowRelease     Action = "escrow.release"
+	ActionEscrowRefund      Action = "escrow.refund"
+	ActionEscrowDispute     Action = "escrow.dispute"
+	ActionEscrowResolve     Action = "escrow.resolve"
+	ActionEscrowExpire      Action = "escrow.expire"
+	ActionInterestPayout    Action = "interest.payout"
+	ActionPaymentRequest    Action = "payment_request.create"
+	ActionPaymentPay        Action = "payment_request.pay"
+	ActionPaymentDecline    Action = "payment_request.decline"
+	ActionPaymentCancel     Action = "payment_request.cancel"
+)
+
+func (a Action) Valid() bool {
+	switch a {
+	case ActionWalletCreate, ActionDeposit, ActionWithdraw, ActionTransfer, ActionBatchTransfer, ActionSplitTransfer, ActionReverse, ActionRefund,
+		ActionRequestWithdrawal, ActionCancelWithdrawal, ActionApproveWithdrawal, ActionRejectWithdrawal, ActionExpireWithdrawal,
+		ActionTransactionStatus,
+		ActionAdjustmentRequest, ActionAdjustmentApprove, ActionAdjustmentReject,
+		ActionAPIKeyIssue, ActionAPIKeyRevoke,
+		ActionUserStatus, ActionKYCLevel, ActionKYCApprove, ActionKYCReject, ActionClearSanctions,
+		ActionPeriodClose,
+		ActionEscrowCreate, ActionEscrowRelease, ActionEscrowRefund, ActionEscrowDispute, ActionEscrowResolve, ActionEscrowExpire,
+		ActionInterestPayout,
+		ActionPaymentRequest, ActionPaymentPay, ActionPaymentDecline, ActionPaymentCancel:
+		return true
+	}
+	return false
+}
+
+type Outcome string
+
+const (
+	OutcomeSuccess Outcome = "success"
+	OutcomeFailure Outcome = "failure"
+)
+
+// BalanceChange records a wallet balance before and after an action. Before or After is
+// nil when the balance could not be read, for example because the wallet does not exist.
+type BalanceChange struct {
+	UserID string `json:"user_id"`
+	Before *int64 `json:"before,omitempty"`
+	After  *int64 `json:"after,omitempty"`
+}
+
+type Entry struct {
+	Seq           int64           // Seq is the position of the entry in the chain, starting at 1.
+	Actor         string          // Actor is the authenticated caller; empty for background jobs.
+	Action        Action          // Action is what was attempted.
+	Target        string          // Target identifies a non-wallet object acted on, such as an adjustment or API key.
+	TransactionID string          // TransactionID is the transaction written by the action, if any.
+	RequestID     string          // RequestID correlates the entry with the request that caused it.
+	SourceIP      string          // SourceIP is the address the request came from.
+	Balances      []BalanceChange // Balances lists the wallets touched by the action.
+	Outcome       Outcome         // Outcome is whether the action took effect.
+	Error         string          // Error describes why a failed action did not take effect.
+	CreatedAt     time.Time       // CreatedAt is when the entry was recorded.
+	PrevHash      string          // PrevHash is the Hash of the previous entry; empty for the first one.
+	Hash          string          // Hash covers every other field, chaining the entry to its predecessor.
+}
+
+// NewEntry returns an entry for action on the wallets of userIDs without balances.
+func NewEntry(action Action, userIDs ...string) Entry {
+	e := Entry{Action: action}
+	for _, userID := range userIDs {
+		e.Balances = append(e.Balances, BalanceChange{UserID: userID})
+	}
+	return e
+}
+
+// ComputeHash returns the hex SHA-256 of the entry's fields other than Hash. CreatedAt is
+// hashed at microsecond precision in UTC so the hash survives a database round trip.
+func (e Entry) ComputeHash() string {
+	balances := e.Balances
+	if balances == nil {
+		balances = []BalanceChange{}
+	}
+	payload, _ := json.Marshal(struct {
+		Seq           int64           `json:"seq"`
+		Actor         string          `json:"actor"`
+		Action        Action          `json:"action"`
+		Target        string          `json:"target"`
+		TransactionID string          `json:"transaction_id"`
+		RequestID     string          `json:"request_id"`
+		SourceIP      string          `json:"source_ip"`
+		Balances      []BalanceChange `json:"balances"`
+		Outcome       Outcome         `json:"outcome"`
+		Error         string          `json:"error"`
+		CreatedAt     string          `json:"created_at"`
+		PrevHash      string          `json:"prev_hash"`
+	}{
+		e.Seq, e.Actor, e.Action, e.Target, e.TransactionID, e.RequestID, e.SourceIP, balances,
+		e.Outcome, e.Error, e.CreatedAt.UTC().Truncate(time.Microsecond).Format(time.RFC3339Nano), e.PrevHash,
+	})
+	sum := sha256.Sum256(payload)
+	return hex.EncodeToString(sum[:])
+}
+
+// Seal places e after the entry with sequence number prevSeq and hash prevHash and sets
+// its Hash.
+func (e Entry) Seal(prevSeq int64, prevHash string) Entry {
+	e.Seq = prevSeq + 1
+	e.PrevHash = prevHash
+	e.CreatedAt = e.CreatedAt.UTC().Truncate(time.Microsecond)
+	e.Hash = e.ComputeHash()
+	return e
+}
+
+// Filter narrows an audit log query; zero fields match everything.
+type Filter struct {
+	Actor         string
+	Action        Action
+	UserID        string // UserID matches entries touching the user's wallet.
+	TransactionID string
+	RequestID     string
+	From          time.Time
+	To            time.Time
+}
+
+// ChainReport is the result of verifying the hash chain.
+type ChainReport struct {
+	Entries   int64  // Entries is the number of entries checked.
+	Valid     bool   // Valid reports whether every entry checked out.
+	BrokenAt  int64  // BrokenAt is the sequence number of the first bad entry when not valid.
+	BrokenWhy string // BrokenWhy describes what is wrong with that entry.
+}
+
+// chainVerifier checks entries one at a time in sequence order.
+type chainVerifier struct {
+	report   ChainReport
+	prevSeq  int64
+	prevHash string
+}
+
+func (v *chainVerifier) check(e Entry) bool {
+	v.report.Entries++
+	var why string
+	switch {
+	case e.Seq != v.prevSeq+1:
+		why = "sequence gap"
+	case e.PrevHash != v.prevHash:
+		why = "previous hash mismatch"
+	case e.Hash != e.ComputeHash():
+		why = "hash mismatch"
+	}
+	if why != "" {
+		v.report.BrokenAt = e.Seq
+		v.report.BrokenWhy = why
+		return false
+	}
+	v.prevSeq = e.Seq
+	v.prevHash = e.Hash
+	return true
+}
@@ -0,0 +1,43 @@
+package audit
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestEntry_Seal(t *testing.T) {
+	before, after := int64(100), int64(50)
+	e := NewEntry(ActionWithdraw, "user1")
+	e.Balances[0].Before, e.Balances[0].After = &before, &after
+	e.Actor = "user1"
+	e.Outcome = OutcomeSuccess
+	e.CreatedAt = time.Date(2024, 5, 1, 12, 0, 0, 123456789, time.FixedZone("CST", 8*3600))
+
+	sealed := e.Seal(41, "prev")
+
+	assert.Equal(t, int64(42), sealed.Seq)
+	assert.Equal(t, "prev", sealed.PrevHash)
+	assert.Len(t, sealed.Hash, 64)
+	assert.Equal(t, sealed.Hash, sealed.ComputeHash())
+
+	// The database stores microseconds in UTC; the hash must not depend on the rest.
+	stored := sealed
+	stored.CreatedAt = time.Date(2024, 5, 1, 4, 0, 0, 123456000, time.UTC)
+	assert.Equal(t, sealed.Hash, stored.ComputeHash())
+
+	tampered := sealed
+	tampered.Balances = []BalanceChange{{UserID: "user1", Before: &before, After: &before}}
+	assert.NotEqual(t, sealed.Hash, tampered.ComputeHash())
+
+	relinked := sealed
+	relinked.PrevHash = "other"
+	assert.NotEqual(t, sealed.Hash, relinked.ComputeHash())
+}
+
+func TestAction_Valid(t *testing.T) {
+	assert.True(t, ActionTransfer.Valid())
+	assert.True(t, ActionAPIKeyRevoke.Valid())
+	assert.False(t, Action("wallet.delete").Valid())
+}
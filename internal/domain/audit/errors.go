@@ -0,0 +1,12 @@
+package audit
+
+import "errors"
+
+var (
+	ErrInvalidAction    = errors.New("invalid audit action")
+	ErrInvalidTimeRange = errors.New("invalid time range")
+	ErrDatabaseFailure  = errors.New("database failure")
+
+	// errChainBroken stops streaming at the first entry that fails verification.
+	errChainBroken = errors.New("audit chain broken")
+)
@@ -0,0 +1,17 @@
+package audit
+
+import (
+	"context"
+)
+
+type AuditRepository interface {
+	// AppendEntry seals e after the last stored entry and stores it. Appends are
+	// serialized so the chain never forks.
+	AppendEntry(ctx context.Context, e Entry) (Entry, error)
+
+	// ListEntries returns the entries matching filter, newest first.
+	ListEntries(ctx context.Context, filter Filter, limit, offset int) ([]Entry, error)
+
+	// StreamEntries calls fn for every entry in sequence order and stops at the first error.
+	StreamEntries(ctx context.Context, fn func(Entry) error) error
+}
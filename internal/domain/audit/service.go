@@ -0,0 +1,119 @@
+package audit
+
+import (
+	"context"
+	"errors"
+	"time"
+)
+
+// BalanceReader reads wallet balances for the before and after snapshots.
+type BalanceReader interface {
+	GetBalance(ctx context.Context, userID string) (int64, error)
+}
+
+type AuditServiceInterface interface {
+	Begin(ctx context.Context, action Action, userIDs ...string) Entry
+	RecordSuccess(ctx context.Context, e Entry) error
+	RecordFailure(ctx context.Context, e Entry, cause error) error
+	ListEntries(ctx context.Context, filter Filter, limit, offset int) ([]Entry, error)
+	VerifyChain(ctx context.Context) (ChainReport, error)
+}
+
+type AuditService struct {
+	repository AuditRepository
+	balances   BalanceReader
+	now        func() time.Time
+}
+
+func NewAuditService(repo AuditRepository, balances BalanceReader) *AuditService {
+	return &AuditService{
+		repository: repo,
+		balances:   balances,
+		now:        time.Now,
+	}
+}
+
+// Begin returns an entry for action on the wallets of userIDs with their current
+// balances. Call it in the database transaction of the action, before changing anything.
+func (s *AuditService) Begin(ctx context.Context, action Action, userIDs ...string) Entry {
+	e := NewEntry(action, userIDs...)
+	for i := range e.Balances {
+		e.Balances[i].Before = s.balance(ctx, e.Balances[i].UserID)
+	}
+	return e
+}
+
+// RecordSuccess appends e as a successful action. Call it in the database transaction of
+// the action so the entry is committed or rolled back together with the change.
+func (s *AuditService) RecordSuccess(ctx context.Context, e Entry) error {
+	e.Outcome = OutcomeSuccess
+	return s.append(ctx, e)
+}
+
+// RecordFailure appends e as an action that failed with cause. Call it after the
+// database transaction of the action was rolled back.
+func (s *AuditService) RecordFailure(ctx context.Context, e Entry, cause error) error {
+	e.Outcome = OutcomeFailure
+	if cause != nil {
+		e.Error = cause.Error()
+	}
+	return s.append(ctx, e)
+}
+
+func (s *AuditService) append(ctx context.Context, e Entry) error {
+	if m, ok := MetadataFromContext(ctx); ok {
+		e.Actor = m.Actor
+		e.RequestID = m.RequestID
+		e.SourceIP = m.SourceIP
+	}
+	for i := range e.Balances {
+		e.Balances[i].After = s.balance(ctx, e.Balances[i].UserID)
+	}
+	e.CreatedAt = s.now()
+
+	if _, err := s.repository.AppendEntry(ctx, e); err != nil {
+		return ErrDatabaseFailure
+	}
+	return nil
+}
+
+// balance returns userID's balance, or nil if it cannot be read.
+func (s *AuditService) balance(ctx context.Context, userID string) *int64 {
+	balance, err := s.balances.GetBalance(ctx, userID)
+	if err != nil {
+		return nil
+	}
+	return &balance
+}
+
+func (s *AuditService) ListEntries(ctx context.Context, filter Filter, limit, offset int) ([]Entry, error) {
+	if filter.Action != "" && !filter.Action.Valid() {
+		return nil, ErrInvalidAction
+	}
+	if !filter.From.IsZero() && !filter.To.IsZero() && filter.From.After(filter.To) {
+		return nil, ErrInvalidTimeRange
+	}
+
+	entries, err := s.repository.ListEntries(ctx, filter, limit, offset)
+	if err != nil {
+		return nil, ErrDatabaseFailure
+	}
+	return entries, nil
+}
+
+// VerifyChain recomputes every entry's hash and checks that each one links to its
+// predecessor, reporting the first entry that was altered, removed or inserted.
+func (s *AuditService) VerifyChain(ctx context.Context) (ChainReport, error) {
+	v := chainVerifier{}
+	err := s.repository.StreamEntries(ctx, func(e Entry) error {
+		if !v.check(e) {
+			return errChainBroken
+		}
+		return nil
+	})
+	if err != nil && !errors.Is(err, errChainBroken) {
+		return ChainReport{}, ErrDatabaseFailure
+	}
+	v.report.Valid = err == nil
+	return v.report, nil
+}
@@ -0,0 +1,170 @@
+package audit
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+)
+
+type MockAuditRepository struct {
+	mock.Mock
+}
+
+func (m *MockAuditRepository) AppendEntry(ctx context.Context, e Entry) (Entry, error) {
+	args := m.Called(ctx, e)
+	return args.Get(0).(Entry), args.Error(1)
+}
+
+func (m *MockAuditRepository) ListEntries(ctx context.Context, filter Filter, limit, offset int) ([]Entry, error) {
+	args := m.Called(ctx, filter, limit, offset)
+	return args.Get(0).([]Entry), args.Error(1)
+}
+
+func (m *MockAuditRepository) StreamEntries(ctx context.Context, fn func(Entry) error) error {
+	args := m.Called(ctx, fn)
+	for _, e := range args.Get(0).([]Entry) {
+		if err := fn(e); err != nil {
+			return err
+		}
+	}
+	return args.Error(1)
+}
+
+// balanceMap is a BalanceReader whose balances the test changes between Begin and Record.
+type balanceMap map[string]int64
+
+func (b balanceMap) GetBalance(_ context.Context, userID string) (int64, error) {
+	balance, ok := b[userID]
+	if !ok {
+		return 0, errors.New("wallet not found")
+	}
+	return balance, nil
+}
+
+func TestAuditService_RecordSuccess(t *testing.T) {
+	mockRepo := new(MockAuditRepository)
+	balances := balanceMap{"user1": 100, "user2": 0}
+	service := NewAuditService(mockRepo, balances)
+	ctx := WithMetadata(context.Background(), Metadata{Actor: "user1", RequestID: "req-1", SourceIP: "10.0.0.1"})
+
+	var stored Entry
+	mockRepo.On("AppendEntry", ctx, mock.AnythingOfType("Entry")).
+		Run(func(args mock.Arguments) { stored = args.Get(1).(Entry) }).
+		Return(Entry{}, nil)
+
+	e := service.Begin(ctx, ActionTransfer, "user1", "user2", "ghost")
+	balances["user1"], balances["user2"] = 40, 60
+	e.TransactionID = "tx1"
+	require.NoError(t, service.RecordSuccess(ctx, e))
+
+	assert.Equal(t, OutcomeSuccess, stored.Outcome)
+	assert.Equal(t, "user1", stored.Actor)
+	assert.Equal(t, "req-1", stored.RequestID)
+	assert.Equal(t, "10.0.0.1", stored.SourceIP)
+	assert.Equal(t, "tx1", stored.TransactionID)
+	assert.Equal(t, int64(100), *stored.Balances[0].Before)
+	assert.Equal(t, int64(40), *stored.Balances[0].After)
+	assert.Equal(t, int64(0), *stored.Balances[1].Before)
+	assert.Equal(t, int64(60), *stored.Balances[1].After)
+	assert.Nil(t, stored.Balances[2].Before)
+	assert.Nil(t, stored.Balances[2].After)
+	assert.False(t, stored.CreatedAt.IsZero())
+}
+
+func TestAuditService_RecordFailure(t *testing.T) {
+	mockRepo := new(MockAuditRepository)
+	service := NewAuditService(mockRepo, balanceMap{"user1": 100})
+	ctx := context.Background()
+
+	mockRepo.On("AppendEntry", ctx, mock.MatchedBy(func(e Entry) bool {
+		return e.Outcome == OutcomeFailure && e.Error == "insufficient funds" && e.Actor == ""
+	})).Return(Entry{}, nil).Once()
+	mockRepo.On("AppendEntry", ctx, mock.Anything).Return(Entry{}, errors.New("connection reset")).Once()
+
+	e := service.Begin(ctx, ActionWithdraw, "user1")
+	assert.NoError(t, service.RecordFailure(ctx, e, errors.New("insufficient funds")))
+	assert.Equal(t, ErrDatabaseFailure, service.RecordFailure(ctx, e, errors.New("insufficient funds")))
+	mockRepo.AssertExpectations(t)
+}
+
+func TestAuditService_ListEntries(t *testing.T) {
+	mockRepo := new(MockAuditRepository)
+	service := NewAuditService(mockRepo, balanceMap{})
+	ctx := context.Background()
+	now := time.Now()
+
+	_, err := service.ListEntries(ctx, Filter{Action: "wallet.delete"}, 10, 0)
+	assert.Equal(t, ErrInvalidAction, err)
+
+	_, err = service.ListEntries(ctx, Filter{From: now, To: now.Add(-time.Hour)}, 10, 0)
+	assert.Equal(t, ErrInvalidTimeRange, err)
+
+	mockRepo.On("ListEntries", ctx, Filter{UserID: "user1"}, 10, 0).Return([]Entry{{Seq: 1}}, nil)
+	entries, err := service.ListEntries(ctx, Filter{UserID: "user1"}, 10, 0)
+	assert.NoError(t, err)
+	assert.Len(t, entries, 1)
+}
+
+func TestAuditService_VerifyChain(t *testing.T) {
+	ctx := context.Background()
+	chain := func() []Entry {
+		var entries []Entry
+		prevSeq, prevHash := int64(0), ""
+		for _, action := range []Action{ActionDeposit, ActionTransfer, ActionWithdraw} {
+			e := NewEntry(action, "user1")
+			e.Outcome = OutcomeSuccess
+			e.CreatedAt = time.Now()
+			e = e.Seal(prevSeq, prevHash)
+			entries = append(entries, e)
+			prevSeq, prevHash = e.Seq, e.Hash
+		}
+		return entries
+	}
+
+	tests := []struct {
+		name    string
+		tamper  func([]Entry) []Entry
+		valid   bool
+		broken  int64
+		reason  string
+		checked int64
+	}{
+		{"intact", func(e []Entry) []Entry { return e }, true, 0, "", 3},
+		{"edited entry", func(e []Entry) []Entry { e[1].Outcome = OutcomeFailure; return e }, false, 2, "hash mismatch", 2},
+		{"edited and rehashed entry", func(e []Entry) []Entry {
+			e[1].Actor = "mallory"
+			e[1].Hash = e[1].ComputeHash()
+			return e
+		}, false, 3, "previous hash mismatch", 3},
+		{"deleted entry", func(e []Entry) []Entry { return append(e[:1], e[2:]...) }, false, 3, "sequence gap", 2},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			mockRepo := new(MockAuditRepository)
+			service := NewAuditService(mockRepo, balanceMap{})
+			mockRepo.On("StreamEntries", ctx, mock.Anything).Return(tt.tamper(chain()), nil)
+
+			report, err := service.VerifyChain(ctx)
+
+			require.NoError(t, err)
+			assert.Equal(t, tt.valid, report.Valid)
+			assert.Equal(t, tt.broken, report.BrokenAt)
+			assert.Equal(t, tt.reason, report.BrokenWhy)
+			assert.Equal(t, tt.checked, report.Entries)
+		})
+	}
+
+	t.Run("database failure", func(t *testing.T) {
+		mockRepo := new(MockAuditRepository)
+		service := NewAuditService(mockRepo, balanceMap{})
+		mockRepo.On("StreamEntries", ctx, mock.Anything).Return([]Entry{}, errors.New("connection reset"))
+
+		_, err := service.VerifyChain(ctx)
+		assert.Equal(t, ErrDatabaseFailure, err)
+	})
+}
@@ -0,0 +1,22 @@
+package auth
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestPermissionsFromScopes(t *testing.T) {
+	perms := PermissionsFromScopes([]string{"openid", "wallet:read", "wallet:withdraw", "wallet:admin", "trade"})
+
+	assert.Equal(t, []Permission{PermissionRead, PermissionWithdraw}, perms)
+}
+
+func TestPrincipal_CanActAs(t *testing.T) {
+	user := Principal{UserID: "user1"}
+	admin := Principal{UserID: "ops", Roles: []string{"support", RoleAdmin}}
+
+	assert.True(t, user.CanActAs("user1"))
+	assert.False(t, user.CanActAs("user2"))
+	assert.True(t, admin.CanActAs("user2"))
+}
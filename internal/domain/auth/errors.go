@@ -0,0 +1,19 @@
+package auth
+
+import "errors"
+
+var (
+	ErrUnauthenticated   = errors.New("authentication required")
+	ErrInvalidAPIKey     = errors.New("invalid api key")
+	ErrInvalidSignature  = errors.New("invalid signature")
+	ErrSignatureExpired  = errors.New("signature timestamp outside the allowed window")
+	ErrNonceReused       = errors.New("nonce already used")
+	ErrForbidden         = errors.New("forbidden")
+	ErrInvalidPermission = errors.New("invalid permission")
+	ErrAPIKeyNotFound    = errors.New("api key not found")
+	ErrInvalidUserID     = errors.New("invalid user ID")
+	ErrInvalidToken      = errors.New("invalid bearer token")
+	ErrUnknownSigningKey = errors.New("unknown token signing key")
+	ErrInvalidSecretKey  = errors.New("invalid api key encryption key")
+	ErrSealedSecret      = errors.New("api key secret cannot be decrypted")
+)
@@ -0,0 +1,20 @@
+package auth
+
+import (
+	"context"
+	"time"
+)
+
+type APIKeyRepository interface {
+	CreateAPIKey(ctx context.Context, key APIKey) error
+
+	GetAPIKeyByID(ctx context.Context, id string) (APIKey, error)
+
+	RevokeAPIKey(ctx context.Context, id string, revokedAt time.Time) error
+}
+
+type NonceRepository interface {
+	// RecordNonce stores nonce for keyID until expiresAt and returns ErrNonceReused if it
+	// has already been recorded.
+	RecordNonce(ctx context.Context, keyID, nonce string, expiresAt time.Time) error
+}
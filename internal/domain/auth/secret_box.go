@@ -0,0 +1,57 @@
+package auth
+
+import (
+	"crypto/aes"
+	"crypto/cipher"
+	"crypto/rand"
+	"encoding/hex"
+)
+
+// SecretKeySize is the length in bytes of the key SecretBox encrypts secrets with.
+const SecretKeySize = 32
+
+// SecretBox encrypts API key secrets with AES-256-GCM under a key only the server holds,
+// so a copy of the api_keys table alone cannot be used to sign requests.
+type SecretBox struct {
+	aead cipher.AEAD
+}
+
+// NewSecretBox returns a SecretBox for the hex-encoded key, SecretKeySize bytes long.
+func NewSecretBox(hexKey string) (*SecretBox, error) {
+	key, err := hex.DecodeString(hexKey)
+	if err != nil || len(key) != SecretKeySize {
+		return nil, ErrInvalidSecretKey
+	}
+	block, err := aes.NewCipher(key)
+	if err != nil {
+		return nil, err
+	}
+	aead, err := cipher.NewGCM(block)
+	if err != nil {
+		return nil, err
+	}
+	return &SecretBox{aead: aead}, nil
+}
+
+// Seal encrypts the secret of keyID. The key ID is authenticated along with it, so a
+// sealed secret cannot be moved to another key's row.
+func (b *SecretBox) Seal(keyID, secret string) ([]byte, error) {
+	nonce := make([]byte, b.aead.NonceSize())
+	if _, err := rand.Read(nonce); err != nil {
+		return nil, err
+	}
+	return b.aead.Seal(nonce, nonce, []byte(secret), []byte(keyID)), nil
+}
+
+// Open decrypts the secret Seal sealed for keyID.
+func (b *SecretBox) Open(keyID string, sealed []byte) (string, error) {
+	if len(sealed) < b.aead.NonceSize() {
+		return "", ErrSealedSecret
+	}
+	nonce, ciphertext := sealed[:b.aead.NonceSize()], sealed[b.aead.NonceSize():]
+	secret, err := b.aead.Open(nil, nonce, ciphertext, []byte(keyID))
+	if err != nil {
+		return "", ErrSealedSecret
+	}
+	return string(secret), nil
+}
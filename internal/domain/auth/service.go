@@ -0,0 +1,138 @@
+package auth
+
+import (
+	"context"
+	"crypto/hmac"
+	"crypto/rand"
+	"encoding/hex"
+	"errors"
+	"time"
+)
+
+// DefaultSignatureWindow is how far a request timestamp may drift from the server clock.
+// Nonces are remembered for the same duration, which bounds replay protection storage.
+const DefaultSignatureWindow = 5 * time.Minute
+
+type APIKeyServiceInterface interface {
+	IssueAPIKey(ctx context.Context, userID string, permissions []Permission) (APIKey, string, error)
+	RevokeAPIKey(ctx context.Context, id string) error
+	Authenticate(ctx context.Context, req SignedRequest) (Principal, error)
+}
+
+// TokenVerifier validates bearer tokens issued by an external identity provider.
+type TokenVerifier interface {
+	VerifyToken(ctx context.Context, token string) (Principal, error)
+}
+
+type APIKeyService struct {
+	repository APIKeyRepository
+	nonces     NonceRepository
+	secrets    *SecretBox
+	window     time.Duration
+	now        func() time.Time
+}
+
+// NewAPIKeyService stores the secrets of the keys it issues sealed by secrets, whose key
+// the server must keep out of the database.
+func NewAPIKeyService(repo APIKeyRepository, nonces NonceRepository, secrets *SecretBox) *APIKeyService {
+	return &APIKeyService{
+		repository: repo,
+		nonces:     nonces,
+		secrets:    secrets,
+		window:     DefaultSignatureWindow,
+		now:        time.Now,
+	}
+}
+
+// IssueAPIKey creates a key for userID and returns it with its secret. The secret is
+// only shown here; the repository keeps it sealed.
+func (s *APIKeyService) IssueAPIKey(ctx context.Context, userID string, permissions []Permission) (APIKey, string, error) {
+	if userID == "" {
+		return APIKey{}, "", ErrInvalidUserID
+	}
+	if len(permissions) == 0 {
+		return APIKey{}, "", ErrInvalidPermission
+	}
+	for _, p := range permissions {
+		if !p.Valid() {
+			return APIKey{}, "", ErrInvalidPermission
+		}
+	}
+
+	id, err := randomHex(16)
+	if err != nil {
+		return APIKey{}, "", err
+	}
+	secret, err := randomHex(32)
+	if err != nil {
+		return APIKey{}, "", err
+	}
+
+	key := APIKey{
+		ID:          "ak_" + id,
+		UserID:      userID,
+		Permissions: permissions,
+		CreatedAt:   s.now(),
+	}
+	if key.Secret, err = s.secrets.Seal(key.ID, secret); err != nil {
+		return APIKey{}, "", err
+	}
+	if err := s.repository.CreateAPIKey(ctx, key); err != nil {
+		return APIKey{}, "", err
+	}
+	return key, secret, nil
+}
+
+func (s *APIKeyService) RevokeAPIKey(ctx context.Context, id string) error {
+	return s.repository.RevokeAPIKey(ctx, id, s.now())
+}
+
+// Authenticate verifies the signature of req and consumes its nonce.
+func (s *APIKeyService) Authenticate(ctx context.Context, req SignedRequest) (Principal, error) {
+	if req.KeyID == "" || req.Signature == "" || req.Nonce == "" {
+		return Principal{}, ErrUnauthenticated
+	}
+
+	key, err := s.repository.GetAPIKeyByID(ctx, req.KeyID)
+	if err != nil {
+		if errors.Is(err, ErrAPIKeyNotFound) {
+			return Principal{}, ErrInvalidAPIKey
+		}
+		return Principal{}, err
+	}
+	if key.Revoked() {
+		return Principal{}, ErrInvalidAPIKey
+	}
+
+	now := s.now()
+	if req.Timestamp.Before(now.Add(-s.window)) || req.Timestamp.After(now.Add(s.window)) {
+		return Principal{}, ErrSignatureExpired
+	}
+
+	secret, err := s.secrets.Open(key.ID, key.Secret)
+	if err != nil {
+		return Principal{}, err
+	}
+	expected := SignRequest(secret, req.Payload)
+	if !hmac.Equal([]byte(expected), []byte(req.Signature)) {
+		return Principal{}, ErrInvalidSignature
+	}
+
+	// The nonce only needs to be remembered while its timestamp is still acceptable.
+	if err := s.nonces.RecordNonce(ctx, key.ID, req.Nonce, req.Timestamp.Add(s.window)); err != nil {
+		return Principal{}, err
+	}
+
+	return Principal{
+		UserID:      key.UserID,
+		Permissions: key.Permissions,
+	}, nil
+}
+
+func randomHex(n int) (string, error) {
+	b := make([]byte, n)
+	if _, err := rand.Read(b); err != nil {
+		return "", err
+	}
+	return hex.EncodeToString(b), nil
+}
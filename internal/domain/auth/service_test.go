@@ -0,0 +1,218 @@
+package auth
+
+import (
+	"context"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+)
+
+type MockAPIKeyRepository struct {
+	mock.Mock
+}
+
+func (m *MockAPIKeyRepository) CreateAPIKey(ctx context.Context, key APIKey) error {
+	args := m.Called(ctx, key)
+	return args.Error(0)
+}
+
+func (m *MockAPIKeyRepository) GetAPIKeyByID(ctx context.Context, id string) (APIKey, error) {
+	args := m.Called(ctx, id)
+	return args.Get(0).(APIKey), args.Error(1)
+}
+
+func (m *MockAPIKeyRepository) RevokeAPIKey(ctx context.Context, id string, revokedAt time.Time) error {
+	args := m.Called(ctx, id, revokedAt)
+	return args.Error(0)
+}
+
+type MockNonceRepository struct {
+	mock.Mock
+}
+
+func (m *MockNonceRepository) RecordNonce(ctx context.Context, keyID, nonce string, expiresAt time.Time) error {
+	args := m.Called(ctx, keyID, nonce, expiresAt)
+	return args.Error(0)
+}
+
+// testSecrets seals secrets under a fixed key.
+func testSecrets(t *testing.T) *SecretBox {
+	t.Helper()
+	box, err := NewSecretBox(strings.Repeat("ab", SecretKeySize))
+	require.NoError(t, err)
+	return box
+}
+
+func TestAPIKeyService_IssueAPIKey(t *testing.T) {
+	ctx := context.Background()
+	secrets := testSecrets(t)
+
+	t.Run("successful issue", func(t *testing.T) {
+		mockRepo := new(MockAPIKeyRepository)
+		service := NewAPIKeyService(mockRepo, new(MockNonceRepository), secrets)
+
+		var stored APIKey
+		mockRepo.On("CreateAPIKey", ctx, mock.Anything).Run(func(args mock.Arguments) {
+			stored = args.Get(1).(APIKey)
+		}).Return(nil)
+
+		key, secret, err := service.IssueAPIKey(ctx, "user1", []Permission{PermissionRead, PermissionTrade})
+
+		assert.NoError(t, err)
+		assert.Equal(t, "user1", key.UserID)
+		assert.NotEmpty(t, secret)
+		assert.NotContains(t, string(stored.Secret), secret, "the secret itself must not be stored")
+		opened, err := secrets.Open(key.ID, stored.Secret)
+		assert.NoError(t, err)
+		assert.Equal(t, secret, opened)
+		mockRepo.AssertExpectations(t)
+	})
+
+	t.Run("invalid permission", func(t *testing.T) {
+		service := NewAPIKeyService(new(MockAPIKeyRepository), new(MockNonceRepository), secrets)
+
+		_, _, err := service.IssueAPIKey(ctx, "user1", []Permission{"admin"})
+
+		assert.Equal(t, ErrInvalidPermission, err)
+	})
+
+	t.Run("invalid user id", func(t *testing.T) {
+		service := NewAPIKeyService(new(MockAPIKeyRepository), new(MockNonceRepository), secrets)
+
+		_, _, err := service.IssueAPIKey(ctx, "", []Permission{PermissionRead})
+
+		assert.Equal(t, ErrInvalidUserID, err)
+	})
+}
+
+func TestAPIKeyService_Authenticate(t *testing.T) {
+	ctx := context.Background()
+	now := time.Date(2024, 3, 1, 12, 0, 0, 0, time.UTC)
+	secret := "s3cret"
+	secrets := testSecrets(t)
+	sealed, err := secrets.Seal("ak_1", secret)
+	require.NoError(t, err)
+	key := APIKey{
+		ID:          "ak_1",
+		UserID:      "user1",
+		Secret:      sealed,
+		Permissions: []Permission{PermissionRead},
+	}
+
+	newService := func() (*APIKeyService, *MockAPIKeyRepository, *MockNonceRepository) {
+		mockRepo := new(MockAPIKeyRepository)
+		mockNonces := new(MockNonceRepository)
+		service := NewAPIKeyService(mockRepo, mockNonces, secrets)
+		service.now = func() time.Time { return now }
+		return service, mockRepo, mockNonces
+	}
+	signed := func(ts time.Time, nonce string) SignedRequest {
+		payload := CanonicalRequest(ts.Unix(), nonce, "GET", "/wallet/user1/balance", nil)
+		return SignedRequest{
+			KeyID:     key.ID,
+			Timestamp: ts,
+			Nonce:     nonce,
+			Signature: SignRequest(secret, payload),
+			Payload:   payload,
+		}
+	}
+
+	t.Run("valid signature", func(t *testing.T) {
+		service, mockRepo, mockNonces := newService()
+		mockRepo.On("GetAPIKeyByID", ctx, key.ID).Return(key, nil)
+		mockNonces.On("RecordNonce", ctx, key.ID, "n1", now.Add(DefaultSignatureWindow)).Return(nil)
+
+		principal, err := service.Authenticate(ctx, signed(now, "n1"))
+
+		assert.NoError(t, err)
+		assert.Equal(t, "user1", principal.UserID)
+		assert.True(t, principal.HasPermission(PermissionRead))
+		assert.False(t, principal.HasPermission(PermissionWithdraw))
+		mockNonces.AssertExpectations(t)
+	})
+
+	t.Run("tampered payload", func(t *testing.T) {
+		service, mockRepo, _ := newService()
+		mockRepo.On("GetAPIKeyByID", ctx, key.ID).Return(key, nil)
+
+		req := signed(now, "n2")
+		req.Payload = CanonicalRequest(now.Unix(), "n2", "GET", "/wallet/user2/balance", nil)
+		_, err := service.Authenticate(ctx, req)
+
+		assert.Equal(t, ErrInvalidSignature, err)
+	})
+
+	t.Run("stale timestamp", func(t *testing.T) {
+		service, mockRepo, _ := newService()
+		mockRepo.On("GetAPIKeyByID", ctx, key.ID).Return(key, nil)
+
+		_, err := service.Authenticate(ctx, signed(now.Add(-10*time.Minute), "n3"))
+
+		assert.Equal(t, ErrSignatureExpired, err)
+	})
+
+	t.Run("replayed nonce", func(t *testing.T) {
+		service, mockRepo, mockNonces := newService()
+		mockRepo.On("GetAPIKeyByID", ctx, key.ID).Return(key, nil)
+		mockNonces.On("RecordNonce", ctx, key.ID, "n4", mock.Anything).Return(ErrNonceReused)
+
+		_, err := service.Authenticate(ctx, signed(now, "n4"))
+
+		assert.Equal(t, ErrNonceReused, err)
+	})
+
+	t.Run("revoked key", func(t *testing.T) {
+		service, mockRepo, _ := newService()
+		revoked := key
+		revokedAt := now.Add(-time.Hour)
+		revoked.RevokedAt = &revokedAt
+		mockRepo.On("GetAPIKeyByID", ctx, key.ID).Return(revoked, nil)
+
+		_, err := service.Authenticate(ctx, signed(now, "n5"))
+
+		assert.Equal(t, ErrInvalidAPIKey, err)
+	})
+
+	t.Run("unknown key", func(t *testing.T) {
+		service, mockRepo, _ := newService()
+		mockRepo.On("GetAPIKeyByID", ctx, key.ID).Return(APIKey{}, ErrAPIKeyNotFound)
+
+		_, err := service.Authenticate(ctx, signed(now, "n6"))
+
+		assert.Equal(t, ErrInvalidAPIKey, err)
+	})
+
+	t.Run("signed with the stored secret", func(t *testing.T) {
+		service, mockRepo, _ := newService()
+		mockRepo.On("GetAPIKeyByID", ctx, key.ID).Return(key, nil)
+
+		req := signed(now, "n7")
+		req.Signature = SignRequest(string(key.Secret), req.Payload)
+		_, err := service.Authenticate(ctx, req)
+
+		assert.Equal(t, ErrInvalidSignature, err, "a copy of the api_keys table must not be enough to sign")
+	})
+
+	t.Run("secret sealed for another key", func(t *testing.T) {
+		service, mockRepo, _ := newService()
+		moved := key
+		moved.Secret, err = secrets.Seal("ak_2", secret)
+		require.NoError(t, err)
+		mockRepo.On("GetAPIKeyByID", ctx, key.ID).Return(moved, nil)
+
+		_, err := service.Authenticate(ctx, signed(now, "n8"))
+
+		assert.Equal(t, ErrSealedSecret, err)
+	})
+}
+
+func TestNewSecretBox(t *testing.T) {
+	for _, key := range []string{"", "not hex", strings.Repeat("ab", SecretKeySize-1)} {
+		_, err := NewSecretBox(key)
+		assert.Equal(t, ErrInvalidSecretKey, err, key)
+	}
+}
@@ -0,0 +1,182 @@
+package escrow
+
+import "time"
+
+// AccountPrefix starts the user ID of the system wallets that hold escrowed funds, one
+// per currency.
+const AccountPrefix = "escrow:"
+
+// AccountID returns the user ID of the system wallet holding escrowed funds in currency.
+func AccountID(currency string) string {
+	return AccountPrefix + currency
+}
+
+type Status string
+
+const (
+	StatusFunded   Status = "funded"   // Funded escrows hold the payer's funds until they are released, refunded or disputed.
+	StatusDisputed Status = "disputed" // Disputed escrows no longer expire and wait for an admin to resolve them.
+	StatusReleased Status = "released" // Released escrows paid the funds to the payee.
+	StatusRefunded Status = "refunded" // Refunded escrows returned the funds to the payer.
+)
+
+func (s Status) Valid() bool {
+	switch s {
+	case StatusFunded, StatusDisputed, StatusReleased, StatusRefunded:
+		return true
+	}
+	return false
+}
+
+// Settled reports whether the funds have left the escrow.
+func (s Status) Settled() bool {
+	return s == StatusReleased || s == StatusRefunded
+}
+
+// ExpiryAction is what happens to the funds of an escrow nobody settled before it expired.
+type ExpiryAction string
+
+const (
+	ExpiryRelease ExpiryAction = "release" // Release pays the payee, as if the payer had confirmed.
+	ExpiryRefund  ExpiryAction = "refund"  // Refund returns the funds to the payer.
+)
+
+func (a ExpiryAction) Valid() bool {
+	return a == ExpiryRelease || a == ExpiryRefund
+}
+
+// Status returns the status an escrow settles in when a takes effect.
+func (a ExpiryAction) Status() Status {
+	if a == ExpiryRefund {
+		return StatusRefunded
+	}
+	return StatusReleased
+}
+
+// Escrow holds a payer's funds in the escrow account of its currency until the payer
+// releases them to the payee, the payee refunds them, or the escrow expires.
+type Escrow struct {
+	ID         string       // ID is the unique escrow identifier.
+	PayerID    string       // PayerID is the user whose wallet funded the escrow.
+	PayeeID    string       // PayeeID is the user paid on release.
+	Amount     int64        // Amount is expressed as an integer in the smallest currency unit.
+	Currency   string       // Currency is the currency code of the amount.
+	Conditions string       // Conditions describes what the payer expects before releasing the funds.
+	OnExpiry   ExpiryAction // OnExpiry is what happens to the funds at ExpiresAt.
+	ExpiresAt  time.Time    // ExpiresAt is when a funded escrow settles by itself.
+	Status     Status       // Status is where the escrow is in its lifecycle.
+
+	DisputedBy    string // DisputedBy is the party that disputed the escrow, if any.
+	DisputeReason string // DisputeReason is why it was disputed.
+
+	FundingTransactionID    string     // FundingTransactionID moved the funds into the escrow account.
+	SettlementTransactionID string     // SettlementTransactionID moved them out, once settled.
+	CreatedAt               time.Time  // CreatedAt is when the escrow was funded.
+	UpdatedAt               time.Time  // UpdatedAt is when the status last changed.
+	SettledAt               *time.Time // SettledAt is set once the escrow has been released or refunded.
+}
+
+// NewEscrow returns a funded escrow of amount from payerID to payeeID, expiring at
+// expiresAt. An empty onExpiry releases the funds, as the payee is paid unless the payer
+// objects in time.
+func NewEscrow(id, payerID, payeeID string, amount int64, currency, conditions string, onExpiry ExpiryAction, expiresAt, now time.Time) (Escrow, error) {
+	if payerID == "" || payeeID == "" {
+		return Escrow{}, ErrInvalidParty
+	}
+	if payerID == payeeID {
+		return Escrow{}, ErrSameParty
+	}
+	if amount <= 0 {
+		return Escrow{}, ErrInvalidAmount
+	}
+	if currency == "" {
+		return Escrow{}, ErrInvalidCurrency
+	}
+	if onExpiry == "" {
+		onExpiry = ExpiryRelease
+	}
+	if !onExpiry.Valid() {
+		return Escrow{}, ErrInvalidExpiryAction
+	}
+	if !expiresAt.After(now) {
+		return Escrow{}, ErrInvalidExpiry
+	}
+	return Escrow{
+		ID:         id,
+		PayerID:    payerID,
+		PayeeID:    payeeID,
+		Amount:     amount,
+		Currency:   currency,
+		Conditions: conditions,
+		OnExpiry:   onExpiry,
+		ExpiresAt:  expiresAt,
+		Status:     StatusFunded,
+		CreatedAt:  now,
+		UpdatedAt:  now,
+	}, nil
+}
+
+// Expired reports whether the escrow is funded and past its expiry at now.
+func (e Escrow) Expired(now time.Time) bool {
+	return e.Status == StatusFunded && !now.Before(e.ExpiresAt)
+}
+
+// Recipient returns who receives the funds when the escrow settles in status to.
+func (e Escrow) Recipient(to Status) string {
+	if to == StatusRefunded {
+		return e.PayerID
+	}
+	return e.PayeeID
+}
+
+// Settle releases or refunds a funded escrow by transactionID. A disputed escrow can only
+// be resolved.
+func (e *Escrow) Settle(to Status, transactionID string, now time.Time) error {
+	if e.Status == StatusDisputed {
+		return ErrEscrowDisputed
+	}
+	return e.Resolve(to, transactionID, now)
+}
+
+// Resolve releases or refunds a funded or disputed escrow by transactionID.
+func (e *Escrow) Resolve(to Status, transactionID string, now time.Time) error {
+	if !to.Settled() {
+		return ErrInvalidStatus
+	}
+	if e.Status.Settled() {
+		return ErrEscrowSettled
+	}
+	e.Status = to
+	e.SettlementTransactionID = transactionID
+	e.UpdatedAt = now
+	e.SettledAt = &now
+	return nil
+}
+
+// Dispute stops a funded escrow from expiring until an admin resolves it. Only its
+// parties can dispute it.
+func (e *Escrow) Dispute(userID, reason string, now time.Time) error {
+	if userID != e.PayerID && userID != e.PayeeID {
+		return ErrNotParty
+	}
+	if reason == "" {
+		return ErrInvalidReason
+	}
+	switch e.Status {
+	case StatusDisputed:
+		return ErrEscrowDisputed
+	case StatusReleased, StatusRefunded:
+		return ErrEscrowSettled
+	}
+	e.Status = StatusDisputed
+	e.DisputedBy = userID
+	e.DisputeReason = reason
+	e.UpdatedAt = now
+	return nil
+}
+
+// Filter narrows a listing of escrows. Zero-valued fields match every escrow.
+type Filter struct {
+	UserID string // UserID matches escrows the user pays or is paid by.
+	Status Status // Status matches escrows in this status.
+}
@@ -0,0 +1,127 @@
+package escrow
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestNewEscrow(t *testing.T) {
+	now := time.Date(2024, 3, 1, 9, 0, 0, 0, time.UTC)
+	expiresAt := now.Add(72 * time.Hour)
+
+	e, err := NewEscrow("esc-1", "buyer", "seller", 5000, "USD", "goods delivered", "", expiresAt, now)
+
+	require.NoError(t, err)
+	assert.Equal(t, StatusFunded, e.Status)
+	assert.Equal(t, ExpiryRelease, e.OnExpiry)
+	assert.Equal(t, expiresAt, e.ExpiresAt)
+	assert.Equal(t, now, e.CreatedAt)
+
+	tests := []struct {
+		name      string
+		payerID   string
+		payeeID   string
+		amount    int64
+		currency  string
+		onExpiry  ExpiryAction
+		expiresAt time.Time
+		err       error
+	}{
+		{"missing payer", "", "seller", 5000, "USD", ExpiryRelease, expiresAt, ErrInvalidParty},
+		{"missing payee", "buyer", "", 5000, "USD", ExpiryRelease, expiresAt, ErrInvalidParty},
+		{"paying oneself", "buyer", "buyer", 5000, "USD", ExpiryRelease, expiresAt, ErrSameParty},
+		{"zero amount", "buyer", "seller", 0, "USD", ExpiryRelease, expiresAt, ErrInvalidAmount},
+		{"missing currency", "buyer", "seller", 5000, "", ExpiryRelease, expiresAt, ErrInvalidCurrency},
+		{"unknown expiry action", "buyer", "seller", 5000, "USD", "keep", expiresAt, ErrInvalidExpiryAction},
+		{"already expired", "buyer", "seller", 5000, "USD", ExpiryRefund, now, ErrInvalidExpiry},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			_, err := NewEscrow("esc-1", tt.payerID, tt.payeeID, tt.amount, tt.currency, "", tt.onExpiry, tt.expiresAt, now)
+			assert.Equal(t, tt.err, err)
+		})
+	}
+}
+
+func newTestEscrow(t *testing.T) Escrow {
+	t.Helper()
+	now := time.Date(2024, 3, 1, 9, 0, 0, 0, time.UTC)
+	e, err := NewEscrow("esc-1", "buyer", "seller", 5000, "USD", "goods delivered", ExpiryRefund, now.Add(time.Hour), now)
+	require.NoError(t, err)
+	return e
+}
+
+func TestEscrow_Expired(t *testing.T) {
+	e := newTestEscrow(t)
+
+	assert.False(t, e.Expired(e.ExpiresAt.Add(-time.Second)))
+	assert.True(t, e.Expired(e.ExpiresAt))
+
+	e.Status = StatusDisputed
+	assert.False(t, e.Expired(e.ExpiresAt.Add(time.Hour)))
+}
+
+func TestEscrow_Settle(t *testing.T) {
+	now := time.Date(2024, 3, 1, 9, 30, 0, 0, time.UTC)
+
+	t.Run("release", func(t *testing.T) {
+		e := newTestEscrow(t)
+
+		require.NoError(t, e.Settle(StatusReleased, "tx-2", now))
+
+		assert.Equal(t, StatusReleased, e.Status)
+		assert.Equal(t, "tx-2", e.SettlementTransactionID)
+		assert.Equal(t, &now, e.SettledAt)
+		assert.Equal(t, "seller", e.Recipient(e.Status))
+	})
+
+	t.Run("refund", func(t *testing.T) {
+		e := newTestEscrow(t)
+
+		require.NoError(t, e.Settle(StatusRefunded, "tx-2", now))
+
+		assert.Equal(t, "buyer", e.Recipient(e.Status))
+	})
+
+	t.Run("not a settled status", func(t *testing.T) {
+		e := newTestEscrow(t)
+		assert.Equal(t, ErrInvalidStatus, e.Settle(StatusDisputed, "tx-2", now))
+	})
+
+	t.Run("already settled", func(t *testing.T) {
+		e := newTestEscrow(t)
+		require.NoError(t, e.Settle(StatusReleased, "tx-2", now))
+
+		assert.Equal(t, ErrEscrowSettled, e.Settle(StatusRefunded, "tx-3", now))
+	})
+
+	t.Run("disputed", func(t *testing.T) {
+		e := newTestEscrow(t)
+		require.NoError(t, e.Dispute("buyer", "never arrived", now))
+
+		assert.Equal(t, ErrEscrowDisputed, e.Settle(StatusReleased, "tx-2", now))
+		assert.NoError(t, e.Resolve(StatusRefunded, "tx-2", now))
+		assert.Equal(t, StatusRefunded, e.Status)
+	})
+}
+
+func TestEscrow_Dispute(t *testing.T) {
+	now := time.Date(2024, 3, 1, 9, 30, 0, 0, time.UTC)
+
+	e := newTestEscrow(t)
+	require.NoError(t, e.Dispute("seller", "buyer will not confirm", now))
+	assert.Equal(t, StatusDisputed, e.Status)
+	assert.Equal(t, "seller", e.DisputedBy)
+	assert.Equal(t, "buyer will not confirm", e.DisputeReason)
+	assert.Equal(t, ErrEscrowDisputed, e.Dispute("buyer", "again", now))
+
+	e = newTestEscrow(t)
+	assert.Equal(t, ErrNotParty, e.Dispute("stranger", "why not", now))
+	assert.Equal(t, ErrInvalidReason, e.Dispute("buyer", "", now))
+
+	require.NoError(t, e.Settle(StatusReleased, "tx-2", now))
+	assert.Equal(t, ErrEscrowSettled, e.Dispute("buyer", "too late", now))
+}
@@ -0,0 +1,20 @@
+package escrow
+
+import "errors"
+
+var (
+	ErrInvalidParty        = errors.New("invalid escrow party")
+	ErrSameParty           = errors.New("escrow payer and payee must differ")
+	ErrInvalidAmount       = errors.New("invalid escrow amount")
+	ErrInvalidCurrency     = errors.New("invalid escrow currency")
+	ErrCurrencyMismatch    = errors.New("escrow currency does not match the wallets of its parties")
+	ErrInvalidExpiry       = errors.New("escrow must expire in the future")
+	ErrInvalidExpiryAction = errors.New("invalid escrow expiry action")
+	ErrInvalidStatus       = errors.New("invalid escrow status")
+	ErrInvalidReason       = errors.New("dispute reason is required")
+	ErrNotParty            = errors.New("only the payer or payee can dispute an escrow")
+	ErrEscrowNotFound      = errors.New("escrow not found")
+	ErrEscrowSettled       = errors.New("escrow is already settled")
+	ErrEscrowDisputed      = errors.New("escrow is disputed and awaits an admin")
+	ErrDatabaseFailure     = errors.New("database failure")
+)
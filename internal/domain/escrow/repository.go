@@ -0,0 +1,22 @@
+package escrow
+
+import (
+	"context"
+	"time"
+)
+
+type EscrowRepository interface {
+	CreateEscrow(ctx context.Context, e Escrow) error
+	GetEscrowByID(ctx context.Context, id string) (Escrow, error)
+
+	// ListEscrows returns the escrows matching filter, newest first.
+	ListEscrows(ctx context.Context, filter Filter, limit, offset int) ([]Escrow, error)
+
+	// ListExpired returns up to limit funded escrows expiring at or before now, soonest
+	// first.
+	ListExpired(ctx context.Context, now time.Time, limit int) ([]Escrow, error)
+
+	// UpdateEscrow stores the new status of e, or returns ErrEscrowSettled if the stored
+	// escrow is no longer in status from.
+	UpdateEscrow(ctx context.Context, e Escrow, from Status) error
+}
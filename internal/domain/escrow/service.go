@@ -0,0 +1,115 @@
+package escrow
+
+import (
+	"context"
+	"errors"
+	"time"
+
+	"github.com/gofrs/uuid"
+)
+
+type EscrowServiceInterface interface {
+	New(payerID, payeeID string, amount int64, currency, conditions string, onExpiry ExpiryAction, expiresAt time.Time) (Escrow, error)
+	Create(ctx context.Context, e Escrow) error
+	GetEscrow(ctx context.Context, id string) (Escrow, error)
+	ListEscrows(ctx context.Context, filter Filter, limit, offset int) ([]Escrow, error)
+	ListExpired(ctx context.Context, now time.Time, limit int) ([]Escrow, error)
+	Settle(ctx context.Context, e Escrow, to Status, transactionID string) (Escrow, error)
+	Resolve(ctx context.Context, e Escrow, to Status, transactionID string) (Escrow, error)
+	Dispute(ctx context.Context, e Escrow, userID, reason string) (Escrow, error)
+}
+
+type EscrowService struct {
+	repository EscrowRepository
+	now        func() time.Time
+}
+
+func NewEscrowService(repo EscrowRepository) *EscrowService {
+	return &EscrowService{
+		repository: repo,
+		now:        time.Now,
+	}
+}
+
+// New returns a validated escrow with a fresh ID; it is stored by Create once the funds
+// have been moved into the escrow account.
+func (s *EscrowService) New(payerID, payeeID string, amount int64, currency, conditions string, onExpiry ExpiryAction, expiresAt time.Time) (Escrow, error) {
+	id, err := uuid.NewV7()
+	if err != nil {
+		return Escrow{}, err
+	}
+	return NewEscrow(id.String(), payerID, payeeID, amount, currency, conditions, onExpiry, expiresAt, s.now())
+}
+
+func (s *EscrowService) Create(ctx context.Context, e Escrow) error {
+	if err := s.repository.CreateEscrow(ctx, e); err != nil {
+		return ErrDatabaseFailure
+	}
+	return nil
+}
+
+func (s *EscrowService) GetEscrow(ctx context.Context, id string) (Escrow, error) {
+	e, err := s.repository.GetEscrowByID(ctx, id)
+	if err != nil {
+		if errors.Is(err, ErrEscrowNotFound) {
+			return Escrow{}, ErrEscrowNotFound
+		}
+		return Escrow{}, ErrDatabaseFailure
+	}
+	return e, nil
+}
+
+func (s *EscrowService) ListEscrows(ctx context.Context, filter Filter, limit, offset int) ([]Escrow, error) {
+	if filter.Status != "" && !filter.Status.Valid() {
+		return nil, ErrInvalidStatus
+	}
+	escrows, err := s.repository.ListEscrows(ctx, filter, limit, offset)
+	if err != nil {
+		return nil, ErrDatabaseFailure
+	}
+	return escrows, nil
+}
+
+func (s *EscrowService) ListExpired(ctx context.Context, now time.Time, limit int) ([]Escrow, error) {
+	escrows, err := s.repository.ListExpired(ctx, now, limit)
+	if err != nil {
+		return nil, ErrDatabaseFailure
+	}
+	return escrows, nil
+}
+
+// Settle records that a funded escrow was released or refunded by transactionID.
+func (s *EscrowService) Settle(ctx context.Context, e Escrow, to Status, transactionID string) (Escrow, error) {
+	return s.update(ctx, e, func(e *Escrow) error {
+		return e.Settle(to, transactionID, s.now())
+	})
+}
+
+// Resolve records that an admin released or refunded a funded or disputed escrow by
+// transactionID.
+func (s *EscrowService) Resolve(ctx context.Context, e Escrow, to Status, transactionID string) (Escrow, error) {
+	return s.update(ctx, e, func(e *Escrow) error {
+		return e.Resolve(to, transactionID, s.now())
+	})
+}
+
+func (s *EscrowService) Dispute(ctx context.Context, e Escrow, userID, reason string) (Escrow, error) {
+	return s.update(ctx, e, func(e *Escrow) error {
+		return e.Dispute(userID, reason, s.now())
+	})
+}
+
+// update applies fn to e and stores the result, unless e changed status in the meantime.
+func (s *EscrowService) update(ctx context.Context, e Escrow, fn func(e *Escrow) error) (Escrow, error) {
+	from := e.Status
+	if err := fn(&e); err != nil {
+		return Escrow{}, err
+	}
+	if err := s.repository.UpdateEscrow(ctx, e, from); err != nil {
+		if errors.Is(err, ErrEscrowSettled) {
+			return Escrow{}, ErrEscrowSettled
+		}
+		return Escrow{}, ErrDatabaseFailure
+	}
+	return e, nil
+}
@@ -0,0 +1,161 @@
+package escrow
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+)
+
+type MockEscrowRepository struct {
+	mock.Mock
+}
+
+func (m *MockEscrowRepository) CreateEscrow(ctx context.Context, e Escrow) error {
+	args := m.Called(ctx, e)
+	return args.Error(0)
+}
+
+func (m *MockEscrowRepository) GetEscrowByID(ctx context.Context, id string) (Escrow, error) {
+	args := m.Called(ctx, id)
+	return args.Get(0).(Escrow), args.Error(1)
+}
+
+func (m *MockEscrowRepository) ListEscrows(ctx context.Context, filter Filter, limit, offset int) ([]Escrow, error) {
+	args := m.Called(ctx, filter, limit, offset)
+	return args.Get(0).([]Escrow), args.Error(1)
+}
+
+func (m *MockEscrowRepository) ListExpired(ctx context.Context, now time.Time, limit int) ([]Escrow, error) {
+	args := m.Called(ctx, now, limit)
+	return args.Get(0).([]Escrow), args.Error(1)
+}
+
+func (m *MockEscrowRepository) UpdateEscrow(ctx context.Context, e Escrow, from Status) error {
+	args := m.Called(ctx, e, from)
+	return args.Error(0)
+}
+
+func newTestService() (*EscrowService, *MockEscrowRepository) {
+	repo := new(MockEscrowRepository)
+	service := NewEscrowService(repo)
+	service.now = func() time.Time { return time.Date(2024, 3, 1, 9, 0, 0, 0, time.UTC) }
+	return service, repo
+}
+
+func TestEscrowService_New(t *testing.T) {
+	service, _ := newTestService()
+
+	e, err := service.New("buyer", "seller", 5000, "USD", "", ExpiryRefund, time.Date(2024, 3, 4, 9, 0, 0, 0, time.UTC))
+
+	require.NoError(t, err)
+	assert.NotEmpty(t, e.ID)
+	assert.Equal(t, StatusFunded, e.Status)
+
+	_, err = service.New("buyer", "seller", 5000, "USD", "", ExpiryRefund, time.Date(2024, 2, 1, 0, 0, 0, 0, time.UTC))
+	assert.Equal(t, ErrInvalidExpiry, err)
+}
+
+func TestEscrowService_GetEscrow(t *testing.T) {
+	ctx := context.Background()
+
+	service, repo := newTestService()
+	repo.On("GetEscrowByID", ctx, "missing").Return(Escrow{}, ErrEscrowNotFound)
+	repo.On("GetEscrowByID", ctx, "broken").Return(Escrow{}, errors.New("connection reset"))
+
+	_, err := service.GetEscrow(ctx, "missing")
+	assert.Equal(t, ErrEscrowNotFound, err)
+	_, err = service.GetEscrow(ctx, "broken")
+	assert.Equal(t, ErrDatabaseFailure, err)
+}
+
+func TestEscrowService_ListEscrows(t *testing.T) {
+	ctx := context.Background()
+
+	service, repo := newTestService()
+	repo.On("ListEscrows", ctx, Filter{UserID: "buyer", Status: StatusFunded}, 10, 0).Return([]Escrow{{ID: "esc-1"}}, nil)
+
+	escrows, err := service.ListEscrows(ctx, Filter{UserID: "buyer", Status: StatusFunded}, 10, 0)
+	require.NoError(t, err)
+	assert.Len(t, escrows, 1)
+
+	_, err = service.ListEscrows(ctx, Filter{Status: "open"}, 10, 0)
+	assert.Equal(t, ErrInvalidStatus, err)
+}
+
+func TestEscrowService_Settle(t *testing.T) {
+	ctx := context.Background()
+	funded := Escrow{ID: "esc-1", PayerID: "buyer", PayeeID: "seller", Amount: 5000, Currency: "USD", Status: StatusFunded}
+
+	t.Run("success", func(t *testing.T) {
+		service, repo := newTestService()
+		repo.On("UpdateEscrow", ctx, mock.AnythingOfType("Escrow"), StatusFunded).Return(nil)
+
+		e, err := service.Settle(ctx, funded, StatusReleased, "tx-2")
+
+		require.NoError(t, err)
+		assert.Equal(t, StatusReleased, e.Status)
+		assert.Equal(t, "tx-2", e.SettlementTransactionID)
+	})
+
+	t.Run("settled in the meantime", func(t *testing.T) {
+		service, repo := newTestService()
+		repo.On("UpdateEscrow", ctx, mock.AnythingOfType("Escrow"), StatusFunded).Return(ErrEscrowSettled)
+
+		_, err := service.Settle(ctx, funded, StatusReleased, "tx-2")
+
+		assert.Equal(t, ErrEscrowSettled, err)
+	})
+
+	t.Run("disputed", func(t *testing.T) {
+		service, repo := newTestService()
+		disputed := funded
+		disputed.Status = StatusDisputed
+
+		_, err := service.Settle(ctx, disputed, StatusReleased, "tx-2")
+
+		assert.Equal(t, ErrEscrowDisputed, err)
+		repo.AssertNotCalled(t, "UpdateEscrow", mock.Anything, mock.Anything, mock.Anything)
+	})
+
+	t.Run("resolve disputed", func(t *testing.T) {
+		service, repo := newTestService()
+		disputed := funded
+		disputed.Status = StatusDisputed
+		repo.On("UpdateEscrow", ctx, mock.AnythingOfType("Escrow"), StatusDisputed).Return(nil)
+
+		e, err := service.Resolve(ctx, disputed, StatusRefunded, "tx-2")
+
+		require.NoError(t, err)
+		assert.Equal(t, StatusRefunded, e.Status)
+	})
+
+	t.Run("repository failure", func(t *testing.T) {
+		service, repo := newTestService()
+		repo.On("UpdateEscrow", ctx, mock.Anything, mock.Anything).Return(errors.New("connection reset"))
+
+		_, err := service.Settle(ctx, funded, StatusReleased, "tx-2")
+
+		assert.Equal(t, ErrDatabaseFailure, err)
+	})
+}
+
+func TestEscrowService_Dispute(t *testing.T) {
+	ctx := context.Background()
+	funded := Escrow{ID: "esc-1", PayerID: "buyer", PayeeID: "seller", Amount: 5000, Currency: "USD", Status: StatusFunded}
+
+	service, repo := newTestService()
+	repo.On("UpdateEscrow", ctx, mock.AnythingOfType("Escrow"), StatusFunded).Return(nil)
+
+	e, err := service.Dispute(ctx, funded, "buyer", "never arrived")
+
+	require.NoError(t, err)
+	assert.Equal(t, StatusDisputed, e.Status)
+
+	_, err = service.Dispute(ctx, funded, "stranger", "why not")
+	assert.Equal(t, ErrNotParty, err)
+}
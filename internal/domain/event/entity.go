@@ -0,0 +1,110 @@
+package event
+
+import (
+	"encoding/json"
+	"time"
+)
+
+// Type names a domain event. Consumers dispatch on it, so values must never change.
+type Type string
+
+const (
+	TypeWalletCreated    Type = "WalletCreated"
+	TypeFundsDeposited   Type = "FundsDeposited"   // Funds entered a wallet from outside, including credit adjustments.
+	TypeFundsWithdrawn   Type = "FundsWithdrawn"   // Funds left a wallet to outside, including debit adjustments.
+	TypeFundsTransferred Type = "FundsTransferred" // Funds moved between two wallets.
+)
+
+func (t Type) Valid() bool {
+	switch t {
+	case TypeWalletCreated, TypeFundsDeposited, TypeFundsWithdrawn, TypeFundsTransferred:
+		return true
+	}
+	return false
+}
+
+// Event is a domain event stored in the outbox until the relay has delivered it.
+type Event struct {
+	Seq         int64           // Seq orders events in the outbox; it is assigned when the event is stored.
+	ID          string          // ID is unique per event so consumers can drop redeliveries.
+	Type        Type            // Type tells consumers how to decode Payload.
+	AggregateID string          // AggregateID is the user ID of the wallet the event is about.
+	Payload     json.RawMessage // Payload is the JSON encoding of the struct matching Type.
+	OccurredAt  time.Time       // OccurredAt is when the change happened.
+	PublishedAt *time.Time      // PublishedAt is set once the event has been delivered.
+	Attempts    int             // Attempts counts failed deliveries.
+	LastError   string          // LastError describes the last failed delivery.
+}
+
+func NewEvent(id string, t Type, aggregateID string, payload any) (Event, error) {
+	if id == "" {
+		return Event{}, ErrInvalidEventID
+	}
+	if !t.Valid() {
+		return Event{}, ErrInvalidEventType
+	}
+	if aggregateID == "" {
+		return Event{}, ErrInvalidAggregateID
+	}
+	data, err := json.Marshal(payload)
+	if err != nil {
+		return Event{}, err
+	}
+	return Event{
+		ID:          id,
+		Type:        t,
+		AggregateID: aggregateID,
+		Payload:     data,
+		OccurredAt:  time.Now(),
+	}, nil
+}
+
+// UserIDs returns the users whose wallets the event is about: the aggregate and, for
+// transfers, the recipient.
+func (e Event) UserIDs() []string {
+	if e.Type == TypeFundsTransferred {
+		var p FundsTransferred
+		if err := json.Unmarshal(e.Payload, &p); err == nil && p.ToUserID != "" && p.ToUserID != e.AggregateID {
+			return []string{e.AggregateID, p.ToUserID}
+		}
+	}
+	return []string{e.AggregateID}
+}
+
+// WalletCreated is the payload of TypeWalletCreated.
+type WalletCreated struct {
+	UserID   string `json:"user_id"`
+	Currency string `json:"currency"`
+}
+
+// FundsDeposited is the payload of TypeFundsDeposited.
+type FundsDeposited struct {
+	UserID                string `json:"user_id"`
+	Amount                int64  `json:"amount"`
+	Currency              string `json:"currency"`
+	TransactionID         string `json:"transaction_id"`
+	TransactionType       string `json:"transaction_type"`
+	OriginalTransactionID string `json:"original_transaction_id,omitempty"` // Set for reversals.
+}
+
+// FundsWithdrawn is the payload of TypeFundsWithdrawn.
+type FundsWithdrawn struct {
+	UserID                string `json:"user_id"`
+	Amount                int64  `json:"amount"`
+	Currency              string `json:"currency"`
+	TransactionID         string `json:"transaction_id"`
+	TransactionType       string `json:"transaction_type"`
+	OriginalTransactionID string `json:"original_transaction_id,omitempty"` // Set for reversals.
+}
+
+// FundsTransferred is the payload of TypeFundsTransferred.
+type FundsTransferred struct {
+	FromUserID            string `json:"from_user_id"`
+	ToUserID              string `json:"to_user_id"`
+	Amount                int64  `json:"amount"`
+	Currency              string `json:"currency"`
+	TransactionID         string `json:"transaction_id"`
+	BatchID               string `json:"batch_id,omitempty"`
+	TransactionType       string `json:"transaction_type,omitempty"`        // Set for reversals and refunds; empty for transfers.
+	OriginalTransactionID string `json:"original_transaction_id,omitempty"` // Set for reversals and refunds.
+}
@@ -0,0 +1,50 @@
+package event
+
+import (
+	"encoding/json"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestNewEvent(t *testing.T) {
+	e, err := NewEvent("ev1", TypeFundsTransferred, "user1", FundsTransferred{FromUserID: "user1", ToUserID: "user2", Amount: 300, Currency: "USD", TransactionID: "tx1"})
+	require.NoError(t, err)
+	assert.Equal(t, "user1", e.AggregateID)
+	assert.False(t, e.OccurredAt.IsZero())
+	assert.Nil(t, e.PublishedAt)
+
+	var payload FundsTransferred
+	require.NoError(t, json.Unmarshal(e.Payload, &payload))
+	assert.Equal(t, "user2", payload.ToUserID)
+	assert.NotContains(t, string(e.Payload), "batch_id")
+
+	tests := []struct {
+		name        string
+		id          string
+		eventType   Type
+		aggregateID string
+		err         error
+	}{
+		{"missing id", "", TypeWalletCreated, "user1", ErrInvalidEventID},
+		{"unknown type", "ev1", "WalletDeleted", "user1", ErrInvalidEventType},
+		{"missing aggregate", "ev1", TypeWalletCreated, "", ErrInvalidAggregateID},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			_, err := NewEvent(tt.id, tt.eventType, tt.aggregateID, WalletCreated{})
+			assert.Equal(t, tt.err, err)
+		})
+	}
+}
+
+func TestEvent_UserIDs(t *testing.T) {
+	transfer, err := NewEvent("ev1", TypeFundsTransferred, "user1", FundsTransferred{FromUserID: "user1", ToUserID: "user2"})
+	require.NoError(t, err)
+	assert.Equal(t, []string{"user1", "user2"}, transfer.UserIDs())
+
+	deposit, err := NewEvent("ev2", TypeFundsDeposited, "user1", FundsDeposited{UserID: "user1"})
+	require.NoError(t, err)
+	assert.Equal(t, []string{"user1"}, deposit.UserIDs())
+}
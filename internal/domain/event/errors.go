@@ -0,0 +1,10 @@
+package event
+
+import "errors"
+
+var (
+	ErrInvalidEventID     = errors.New("invalid event ID")
+	ErrInvalidEventType   = errors.New("invalid event type")
+	ErrInvalidAggregateID = errors.New("invalid aggregate ID")
+	ErrDatabaseFailure    = errors.New("database failure")
+)
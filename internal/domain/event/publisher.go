@@ -0,0 +1,9 @@
+package event
+
+import "context"
+
+// Publisher delivers events to consumers. The relay calls it at least once per event, so
+// consumers must tolerate duplicates; Event.ID identifies them.
+type Publisher interface {
+	Publish(ctx context.Context, e Event) error
+}
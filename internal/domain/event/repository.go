@@ -0,0 +1,21 @@
+package event
+
+import (
+	"context"
+	"time"
+)
+
+// OutboxRepository stores events until they are delivered. Events must be appended in
+// the database transaction of the change they describe.
+type OutboxRepository interface {
+	AppendEvent(ctx context.Context, e Event) error
+
+	// ListPendingEvents returns up to limit undelivered events in Seq order and locks them
+	// until the surrounding transaction ends; events locked by another relay are skipped.
+	ListPendingEvents(ctx context.Context, limit int) ([]Event, error)
+
+	MarkEventPublished(ctx context.Context, id string, publishedAt time.Time) error
+
+	// RecordEventFailure counts a failed delivery of event id and keeps its error.
+	RecordEventFailure(ctx context.Context, id, lastError string) error
+}
@@ -0,0 +1,65 @@
+package event
+
+import (
+	"context"
+	"time"
+
+	"github.com/gofrs/uuid"
+)
+
+type EventServiceInterface interface {
+	Record(ctx context.Context, t Type, aggregateID string, payload any) error
+	ListPendingEvents(ctx context.Context, limit int) ([]Event, error)
+	MarkPublished(ctx context.Context, e Event) error
+	MarkFailed(ctx context.Context, e Event, cause error) error
+}
+
+type EventService struct {
+	repository OutboxRepository
+}
+
+func NewEventService(repo OutboxRepository) *EventService {
+	return &EventService{
+		repository: repo,
+	}
+}
+
+// Record appends an event to the outbox. Call it in the database transaction of the
+// change so the event is stored if and only if the change commits.
+func (s *EventService) Record(ctx context.Context, t Type, aggregateID string, payload any) error {
+	id, err := uuid.NewV7()
+	if err != nil {
+		return err
+	}
+
+	e, err := NewEvent(id.String(), t, aggregateID, payload)
+	if err != nil {
+		return err
+	}
+	if err := s.repository.AppendEvent(ctx, e); err != nil {
+		return ErrDatabaseFailure
+	}
+	return nil
+}
+
+func (s *EventService) ListPendingEvents(ctx context.Context, limit int) ([]Event, error) {
+	events, err := s.repository.ListPendingEvents(ctx, limit)
+	if err != nil {
+		return nil, ErrDatabaseFailure
+	}
+	return events, nil
+}
+
+func (s *EventService) MarkPublished(ctx context.Context, e Event) error {
+	if err := s.repository.MarkEventPublished(ctx, e.ID, time.Now()); err != nil {
+		return ErrDatabaseFailure
+	}
+	return nil
+}
+
+func (s *EventService) MarkFailed(ctx context.Context, e Event, cause error) error {
+	if err := s.repository.RecordEventFailure(ctx, e.ID, cause.Error()); err != nil {
+		return ErrDatabaseFailure
+	}
+	return nil
+}
@@ -0,0 +1,84 @@
+package event
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+)
+
+type MockOutboxRepository struct {
+	mock.Mock
+}
+
+func (m *MockOutboxRepository) AppendEvent(ctx context.Context, e Event) error {
+	args := m.Called(ctx, e)
+	return args.Error(0)
+}
+
+func (m *MockOutboxRepository) ListPendingEvents(ctx context.Context, limit int) ([]Event, error) {
+	args := m.Called(ctx, limit)
+	return args.Get(0).([]Event), args.Error(1)
+}
+
+func (m *MockOutboxRepository) MarkEventPublished(ctx context.Context, id string, publishedAt time.Time) error {
+	args := m.Called(ctx, id, publishedAt)
+	return args.Error(0)
+}
+
+func (m *MockOutboxRepository) RecordEventFailure(ctx context.Context, id, lastError string) error {
+	args := m.Called(ctx, id, lastError)
+	return args.Error(0)
+}
+
+func TestEventService_Record(t *testing.T) {
+	ctx := context.Background()
+
+	t.Run("stores the event", func(t *testing.T) {
+		mockRepo := new(MockOutboxRepository)
+		service := NewEventService(mockRepo)
+		mockRepo.On("AppendEvent", ctx, mock.MatchedBy(func(e Event) bool {
+			return e.ID != "" && e.Type == TypeFundsDeposited && e.AggregateID == "user1"
+		})).Return(nil)
+
+		err := service.Record(ctx, TypeFundsDeposited, "user1", FundsDeposited{UserID: "user1", Amount: 100, Currency: "USD"})
+
+		assert.NoError(t, err)
+		mockRepo.AssertExpectations(t)
+	})
+
+	t.Run("invalid event is not stored", func(t *testing.T) {
+		mockRepo := new(MockOutboxRepository)
+		service := NewEventService(mockRepo)
+
+		err := service.Record(ctx, "FundsLost", "user1", nil)
+
+		assert.Equal(t, ErrInvalidEventType, err)
+		mockRepo.AssertNotCalled(t, "AppendEvent", mock.Anything, mock.Anything)
+	})
+
+	t.Run("database failure", func(t *testing.T) {
+		mockRepo := new(MockOutboxRepository)
+		service := NewEventService(mockRepo)
+		mockRepo.On("AppendEvent", ctx, mock.Anything).Return(errors.New("connection reset"))
+
+		err := service.Record(ctx, TypeWalletCreated, "user1", WalletCreated{UserID: "user1", Currency: "USD"})
+
+		assert.Equal(t, ErrDatabaseFailure, err)
+	})
+}
+
+func TestEventService_MarkFailed(t *testing.T) {
+	mockRepo := new(MockOutboxRepository)
+	service := NewEventService(mockRepo)
+	ctx := context.Background()
+	mockRepo.On("RecordEventFailure", ctx, "ev1", "broker unavailable").Return(nil)
+
+	err := service.MarkFailed(ctx, Event{ID: "ev1"}, errors.New("broker unavailable"))
+
+	assert.NoError(t, err)
+	mockRepo.AssertExpectations(t)
+}
@@ -0,0 +1,137 @@
+package interest
+
+import (
+	"math"
+	"time"
+)
+
+// MicrosPerUnit is the number of micros, the unit accruals are kept in, in one minor unit
+// of a currency. Daily interest is mostly fractions of a minor unit, so it is only rounded
+// down to whole units when paid out, and the rest carries over to the next payout.
+const MicrosPerUnit = 1_000_000
+
+// DaysPerYear is the number of daily accruals an APY compounds over.
+const DaysPerYear = 365
+
+// Tier is the APY, in percent, earned by the part of a balance from From minor units up
+// to the next tier.
+type Tier struct {
+	From int64
+	APY  float64
+}
+
+// Product is the interest paid on wallets in Currency, accrued daily on end-of-day
+// balances of at least MinBalance and paid monthly out of the HouseAccount wallet.
+type Product struct {
+	Currency     string
+	HouseAccount string
+	MinBalance   int64
+	Tiers        []Tier // Tiers start at 0 and rise by From.
+
+	dailyRates []float64
+}
+
+func NewProduct(currency, houseAccount string, minBalance int64, tiers []Tier) (Product, error) {
+	if currency == "" || houseAccount == "" || minBalance < 0 {
+		return Product{}, ErrInvalidProduct
+	}
+	if len(tiers) == 0 || tiers[0].From != 0 {
+		return Product{}, ErrInvalidTiers
+	}
+	rates := make([]float64, len(tiers))
+	for i, t := range tiers {
+		if i > 0 && t.From <= tiers[i-1].From {
+			return Product{}, ErrInvalidTiers
+		}
+		if t.APY < 0 || t.APY > 100 || math.IsNaN(t.APY) {
+			return Product{}, ErrInvalidTiers
+		}
+		rates[i] = math.Pow(1+t.APY/100, 1.0/DaysPerYear) - 1
+	}
+	return Product{
+		Currency:     currency,
+		HouseAccount: houseAccount,
+		MinBalance:   minBalance,
+		Tiers:        tiers,
+		dailyRates:   rates,
+	}, nil
+}
+
+// DailyMicros returns the interest, in micros, a day at balance earns: each tier's part
+// of the balance earns the daily rate that compounds to the tier's APY over a year.
+// Balances below MinBalance earn nothing.
+func (p Product) DailyMicros(balance int64) int64 {
+	if balance <= 0 || balance < p.MinBalance {
+		return 0
+	}
+	var micros float64
+	for i, t := range p.Tiers {
+		if balance <= t.From {
+			break
+		}
+		part := balance - t.From
+		if i+1 < len(p.Tiers) {
+			part = min(part, p.Tiers[i+1].From-t.From)
+		}
+		micros += float64(part) * p.dailyRates[i] * MicrosPerUnit
+	}
+	return int64(micros)
+}
+
+// Accrual is the interest one wallet earned over Day, a UTC calendar day, on its
+// end-of-day Balance.
+type Accrual struct {
+	UserID    string
+	Currency  string
+	Day       time.Time
+	Balance   int64
+	Micros    int64
+	CreatedAt time.Time
+}
+
+// Total is the interest one wallet accrued over a period.
+type Total struct {
+	UserID   string
+	Currency string
+	Micros   int64
+}
+
+// Payout is the interest paid to a wallet for Month, the first instant of a UTC calendar
+// month. Accrued is what the month accrued plus what the previous payout carried over;
+// Amount is its whole minor units and Carry the micros left for the next payout.
+type Payout struct {
+	UserID        string
+	Currency      string
+	Month         time.Time
+	Accrued       int64
+	Amount        int64
+	Carry         int64
+	TransactionID string // TransactionID is the INTEREST transaction, empty when Amount is 0.
+	CreatedAt     time.Time
+}
+
+// NewPayout returns the payout of accrued micros for month, keeping the fraction of a
+// minor unit as the carry.
+func NewPayout(userID, currency string, month time.Time, accrued int64, now time.Time) Payout {
+	return Payout{
+		UserID:    userID,
+		Currency:  currency,
+		Month:     month,
+		Accrued:   accrued,
+		Amount:    accrued / MicrosPerUnit,
+		Carry:     accrued % MicrosPerUnit,
+		CreatedAt: now,
+	}
+}
+
+// Day returns the UTC calendar day containing t.
+func Day(t time.Time) time.Time {
+	y, m, d := t.UTC().Date()
+	return time.Date(y, m, d, 0, 0, 0, 0, time.UTC)
+}
+
+// Month returns the UTC calendar month containing t.
+func Month(t time.Time) time.Time {
+	y, m, _ := t.UTC().Date()
+	return time.Date(y, m, 1, 0, 0, 0, 0, time.UTC)
+}
@@ -0,0 +1,82 @@
+package interest
+
+import (
+	"math"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestNewProduct(t *testing.T) {
+	_, err := NewProduct("USD", "house", 0, []Tier{{From: 0, APY: 2}, {From: 1000000, APY: 5}})
+	assert.NoError(t, err)
+
+	tests := []struct {
+		name         string
+		currency     string
+		houseAccount string
+		minBalance   int64
+		tiers        []Tier
+		err          error
+	}{
+		{name: "missing currency", houseAccount: "house", tiers: []Tier{{APY: 2}}, err: ErrInvalidProduct},
+		{name: "missing house account", currency: "USD", tiers: []Tier{{APY: 2}}, err: ErrInvalidProduct},
+		{name: "negative minimum balance", currency: "USD", houseAccount: "house", minBalance: -1, tiers: []Tier{{APY: 2}}, err: ErrInvalidProduct},
+		{name: "no tiers", currency: "USD", houseAccount: "house", err: ErrInvalidTiers},
+		{name: "first tier above 0", currency: "USD", houseAccount: "house", tiers: []Tier{{From: 100, APY: 2}}, err: ErrInvalidTiers},
+		{name: "tiers out of order", currency: "USD", houseAccount: "house", tiers: []Tier{{APY: 2}, {From: 500, APY: 3}, {From: 500, APY: 4}}, err: ErrInvalidTiers},
+		{name: "negative rate", currency: "USD", houseAccount: "house", tiers: []Tier{{APY: -1}}, err: ErrInvalidTiers},
+		{name: "rate above 100%", currency: "USD", houseAccount: "house", tiers: []Tier{{APY: 101}}, err: ErrInvalidTiers},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			_, err := NewProduct(tt.currency, tt.houseAccount, tt.minBalance, tt.tiers)
+			assert.Equal(t, tt.err, err)
+		})
+	}
+}
+
+func TestProduct_DailyMicros(t *testing.T) {
+	flat, err := NewProduct("USD", "house", 0, []Tier{{APY: 3.65}})
+	require.NoError(t, err)
+	tiered, err := NewProduct("USD", "house", 5000, []Tier{{APY: 2}, {From: 1000000, APY: 5}})
+	require.NoError(t, err)
+
+	t.Run("compounds to the APY over a year", func(t *testing.T) {
+		micros := flat.DailyMicros(1000000)
+
+		assert.Equal(t, int64(98223050), micros)
+		rate := float64(micros) / MicrosPerUnit / 1000000
+		assert.InDelta(t, 1.0365, math.Pow(1+rate, DaysPerYear), 1e-9)
+	})
+
+	t.Run("each tier earns its rate on its part", func(t *testing.T) {
+		assert.InDelta(t, 54255245, tiered.DailyMicros(1000000), 1)
+		assert.InDelta(t, 54255245+133680617, tiered.DailyMicros(2000000), 1)
+	})
+
+	t.Run("minimum balance", func(t *testing.T) {
+		assert.Zero(t, tiered.DailyMicros(4999))
+		assert.Equal(t, int64(271276), tiered.DailyMicros(5000))
+		assert.Zero(t, flat.DailyMicros(-100))
+	})
+}
+
+func TestNewPayout(t *testing.T) {
+	month := time.Date(2024, 3, 1, 0, 0, 0, 0, time.UTC)
+
+	p := NewPayout("user1", "USD", month, 2_750_000, month)
+
+	assert.Equal(t, int64(2), p.Amount)
+	assert.Equal(t, int64(750_000), p.Carry)
+}
+
+func TestDayAndMonth(t *testing.T) {
+	at := time.Date(2024, 3, 15, 23, 30, 0, 0, time.FixedZone("UTC-2", -2*60*60))
+
+	assert.Equal(t, time.Date(2024, 3, 16, 0, 0, 0, 0, time.UTC), Day(at))
+	assert.Equal(t, time.Date(2024, 3, 1, 0, 0, 0, 0, time.UTC), Month(at))
+}
@@ -0,0 +1,15 @@
+package interest
+
+import "errors"
+
+var (
+	ErrInvalidProduct  = errors.New("invalid interest product")
+	ErrInvalidTiers    = errors.New("interest tiers must start at 0 and rise, with rates from 0 to 100%")
+	ErrInvalidDay      = errors.New("interest accrues only for finished days")
+	ErrInvalidMonth    = errors.New("interest is paid only for finished months")
+	ErrNoProduct       = errors.New("no interest product for the currency")
+	ErrAlreadyAccrued  = errors.New("interest already accrued for the day")
+	ErrAlreadyPaid     = errors.New("interest already paid for the month")
+	ErrPayoutNotFound  = errors.New("interest payout not found")
+	ErrDatabaseFailure = errors.New("database failure")
+)
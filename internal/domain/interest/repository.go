@@ -0,0 +1,28 @@
+package interest
+
+import (
+	"context"
+	"time"
+)
+
+type InterestRepository interface {
+	// CreateAccrual stores a, or returns ErrAlreadyAccrued if its wallet has already
+	// accrued interest for its day.
+	CreateAccrual(ctx context.Context, a Accrual) error
+
+	// ListAccrualTotals returns the interest accrued by each wallet over the days from
+	// from up to to.
+	ListAccrualTotals(ctx context.Context, from, to time.Time) ([]Total, error)
+
+	// CreatePayout stores p, or returns ErrAlreadyPaid if its wallet has already been paid
+	// for its month.
+	CreatePayout(ctx context.Context, p Payout) error
+	GetPayout(ctx context.Context, userID string, month time.Time) (Payout, error)
+
+	// GetLatestPayout returns the payout of userID for the latest month before month, or
+	// ErrPayoutNotFound.
+	GetLatestPayout(ctx context.Context, userID string, month time.Time) (Payout, error)
+
+	// ListPayouts returns the payouts of userID, newest first.
+	ListPayouts(ctx context.Context, userID string, limit, offset int) ([]Payout, error)
+}
@@ -0,0 +1,132 @@
+package interest
+
+import (
+	"context"
+	"errors"
+	"time"
+)
+
+type InterestServiceInterface interface {
+	Product(currency string) (Product, bool)
+	Accrue(ctx context.Context, userID, currency string, day time.Time, balance int64) (Accrual, error)
+	ListAccrualTotals(ctx context.Context, month time.Time) ([]Total, error)
+	NewPayout(ctx context.Context, total Total, month time.Time) (Payout, error)
+	RecordPayout(ctx context.Context, p Payout) error
+	GetPayout(ctx context.Context, userID string, month time.Time) (Payout, error)
+	ListPayouts(ctx context.Context, userID string, limit, offset int) ([]Payout, error)
+}
+
+type InterestService struct {
+	repository InterestRepository
+	products   map[string]Product
+	now        func() time.Time
+}
+
+func NewInterestService(repo InterestRepository, products []Product) *InterestService {
+	byCurrency := make(map[string]Product, len(products))
+	for _, p := range products {
+		byCurrency[p.Currency] = p
+	}
+	return &InterestService{
+		repository: repo,
+		products:   byCurrency,
+		now:        time.Now,
+	}
+}
+
+// Product returns the interest product of currency, if there is one.
+func (s *InterestService) Product(currency string) (Product, bool) {
+	p, ok := s.products[currency]
+	return p, ok
+}
+
+// Accrue records the interest userID's wallet earned over day on its end-of-day balance.
+// Nothing is recorded, and a zero accrual returned, when the balance earns nothing or the
+// wallet is the product's house account.
+func (s *InterestService) Accrue(ctx context.Context, userID, currency string, day time.Time, balance int64) (Accrual, error) {
+	if !day.Equal(Day(day)) || day.AddDate(0, 0, 1).After(s.now()) {
+		return Accrual{}, ErrInvalidDay
+	}
+	p, ok := s.products[currency]
+	if !ok {
+		return Accrual{}, ErrNoProduct
+	}
+
+	a := Accrual{
+		UserID:    userID,
+		Currency:  currency,
+		Day:       day,
+		Balance:   balance,
+		CreatedAt: s.now(),
+	}
+	if userID == p.HouseAccount {
+		return a, nil
+	}
+	a.Micros = p.DailyMicros(balance)
+	if a.Micros == 0 {
+		return a, nil
+	}
+
+	if err := s.repository.CreateAccrual(ctx, a); err != nil {
+		if errors.Is(err, ErrAlreadyAccrued) {
+			return Accrual{}, ErrAlreadyAccrued
+		}
+		return Accrual{}, ErrDatabaseFailure
+	}
+	return a, nil
+}
+
+// ListAccrualTotals returns the interest each wallet accrued over month.
+func (s *InterestService) ListAccrualTotals(ctx context.Context, month time.Time) ([]Total, error) {
+	if !month.Equal(Month(month)) || month.AddDate(0, 1, 0).After(s.now()) {
+		return nil, ErrInvalidMonth
+	}
+	totals, err := s.repository.ListAccrualTotals(ctx, month, month.AddDate(0, 1, 0))
+	if err != nil {
+		return nil, ErrDatabaseFailure
+	}
+	return totals, nil
+}
+
+// NewPayout returns the payout of total for month, adding what the wallet's previous
+// payout carried over; it is stored by RecordPayout once the amount has been paid.
+func (s *InterestService) NewPayout(ctx context.Context, total Total, month time.Time) (Payout, error) {
+	accrued := total.Micros
+	previous, err := s.repository.GetLatestPayout(ctx, total.UserID, month)
+	switch {
+	case err == nil:
+		accrued += previous.Carry
+	case !errors.Is(err, ErrPayoutNotFound):
+		return Payout{}, ErrDatabaseFailure
+	}
+	return NewPayout(total.UserID, total.Currency, month, accrued, s.now()), nil
+}
+
+func (s *InterestService) RecordPayout(ctx context.Context, p Payout) error {
+	if err := s.repository.CreatePayout(ctx, p); err != nil {
+		if errors.Is(err, ErrAlreadyPaid) {
+			return ErrAlreadyPaid
+		}
+		return ErrDatabaseFailure
+	}
+	return nil
+}
+
+func (s *InterestService) GetPayout(ctx context.Context, userID string, month time.Time) (Payout, error) {
+	p, err := s.repository.GetPayout(ctx, userID, month)
+	if err != nil {
+		if errors.Is(err, ErrPayoutNotFound) {
+			return Payout{}, ErrPayoutNotFound
+		}
+		return Payout{}, ErrDatabaseFailure
+	}
+	return p, nil
+}
+
+func (s *InterestService) ListPayouts(ctx context.Context, userID string, limit, offset int) ([]Payout, error) {
+	payouts, err := s.repository.ListPayouts(ctx, userID, limit, offset)
+	if err != nil {
+		return nil, ErrDatabaseFailure
+	}
+	return payouts, nil
+}
@@ -0,0 +1,170 @@
+package interest
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+)
+
+type MockInterestRepository struct {
+	mock.Mock
+}
+
+func (m *MockInterestRepository) CreateAccrual(ctx context.Context, a Accrual) error {
+	args := m.Called(ctx, a)
+	return args.Error(0)
+}
+
+func (m *MockInterestRepository) ListAccrualTotals(ctx context.Context, from, to time.Time) ([]Total, error) {
+	args := m.Called(ctx, from, to)
+	return args.Get(0).([]Total), args.Error(1)
+}
+
+func (m *MockInterestRepository) CreatePayout(ctx context.Context, p Payout) error {
+	args := m.Called(ctx, p)
+	return args.Error(0)
+}
+
+func (m *MockInterestRepository) GetPayout(ctx context.Context, userID string, month time.Time) (Payout, error) {
+	args := m.Called(ctx, userID, month)
+	return args.Get(0).(Payout), args.Error(1)
+}
+
+func (m *MockInterestRepository) GetLatestPayout(ctx context.Context, userID string, month time.Time) (Payout, error) {
+	args := m.Called(ctx, userID, month)
+	return args.Get(0).(Payout), args.Error(1)
+}
+
+func (m *MockInterestRepository) ListPayouts(ctx context.Context, userID string, limit, offset int) ([]Payout, error) {
+	args := m.Called(ctx, userID, limit, offset)
+	return args.Get(0).([]Payout), args.Error(1)
+}
+
+func newTestService(t *testing.T) (*InterestService, *MockInterestRepository) {
+	product, err := NewProduct("USD", "house", 100, []Tier{{APY: 3.65}})
+	require.NoError(t, err)
+
+	repo := new(MockInterestRepository)
+	service := NewInterestService(repo, []Product{product})
+	service.now = func() time.Time { return time.Date(2024, 3, 16, 0, 5, 0, 0, time.UTC) }
+	return service, repo
+}
+
+func TestInterestService_Accrue(t *testing.T) {
+	ctx := context.Background()
+	day := time.Date(2024, 3, 15, 0, 0, 0, 0, time.UTC)
+
+	t.Run("records the day's interest", func(t *testing.T) {
+		service, repo := newTestService(t)
+		repo.On("CreateAccrual", ctx, mock.MatchedBy(func(a Accrual) bool {
+			return a.UserID == "user1" && a.Day.Equal(day) && a.Micros == 98223050
+		})).Return(nil)
+
+		a, err := service.Accrue(ctx, "user1", "USD", day, 1000000)
+
+		require.NoError(t, err)
+		assert.Equal(t, int64(98223050), a.Micros)
+		repo.AssertExpectations(t)
+	})
+
+	t.Run("rerun of the same day", func(t *testing.T) {
+		service, repo := newTestService(t)
+		repo.On("CreateAccrual", ctx, mock.Anything).Return(ErrAlreadyAccrued)
+
+		_, err := service.Accrue(ctx, "user1", "USD", day, 1000000)
+
+		assert.Equal(t, ErrAlreadyAccrued, err)
+	})
+
+	t.Run("nothing earned", func(t *testing.T) {
+		service, repo := newTestService(t)
+
+		for _, userID := range []string{"user1", "house"} {
+			balance := int64(50)
+			if userID == "house" {
+				balance = 1000000
+			}
+			a, err := service.Accrue(ctx, userID, "USD", day, balance)
+
+			require.NoError(t, err)
+			assert.Zero(t, a.Micros)
+		}
+		repo.AssertNotCalled(t, "CreateAccrual", mock.Anything, mock.Anything)
+	})
+
+	t.Run("invalid days", func(t *testing.T) {
+		service, _ := newTestService(t)
+
+		_, err := service.Accrue(ctx, "user1", "USD", day.Add(time.Hour), 1000000)
+		assert.Equal(t, ErrInvalidDay, err)
+
+		_, err = service.Accrue(ctx, "user1", "USD", day.AddDate(0, 0, 1), 1000000)
+		assert.Equal(t, ErrInvalidDay, err)
+
+		_, err = service.Accrue(ctx, "user1", "EUR", day, 1000000)
+		assert.Equal(t, ErrNoProduct, err)
+	})
+}
+
+func TestInterestService_Payouts(t *testing.T) {
+	ctx := context.Background()
+	month := time.Date(2024, 2, 1, 0, 0, 0, 0, time.UTC)
+
+	t.Run("totals of a finished month", func(t *testing.T) {
+		service, repo := newTestService(t)
+		repo.On("ListAccrualTotals", ctx, month, month.AddDate(0, 1, 0)).Return([]Total{{UserID: "user1", Currency: "USD", Micros: 5}}, nil)
+
+		totals, err := service.ListAccrualTotals(ctx, month)
+
+		require.NoError(t, err)
+		assert.Len(t, totals, 1)
+
+		_, err = service.ListAccrualTotals(ctx, month.AddDate(0, 1, 0))
+		assert.Equal(t, ErrInvalidMonth, err)
+	})
+
+	t.Run("carry of the previous payout", func(t *testing.T) {
+		service, repo := newTestService(t)
+		repo.On("GetLatestPayout", ctx, "user1", month).Return(Payout{Carry: 600_000}, nil)
+
+		p, err := service.NewPayout(ctx, Total{UserID: "user1", Currency: "USD", Micros: 2_500_000}, month)
+
+		require.NoError(t, err)
+		assert.Equal(t, int64(3_100_000), p.Accrued)
+		assert.Equal(t, int64(3), p.Amount)
+		assert.Equal(t, int64(100_000), p.Carry)
+	})
+
+	t.Run("first payout", func(t *testing.T) {
+		service, repo := newTestService(t)
+		repo.On("GetLatestPayout", ctx, "user1", month).Return(Payout{}, ErrPayoutNotFound)
+
+		p, err := service.NewPayout(ctx, Total{UserID: "user1", Currency: "USD", Micros: 2_500_000}, month)
+
+		require.NoError(t, err)
+		assert.Equal(t, int64(2), p.Amount)
+	})
+
+	t.Run("month already paid", func(t *testing.T) {
+		service, repo := newTestService(t)
+		repo.On("CreatePayout", ctx, mock.Anything).Return(ErrAlreadyPaid)
+
+		err := service.RecordPayout(ctx, Payout{UserID: "user1", Month: month})
+
+		assert.Equal(t, ErrAlreadyPaid, err)
+	})
+
+	t.Run("database failure", func(t *testing.T) {
+		service, repo := newTestService(t)
+		repo.On("GetPayout", ctx, "user1", month).Return(Payout{}, errors.New("connection reset"))
+
+		_, err := service.GetPayout(ctx, "user1", month)
+
+		assert.Equal(t, ErrDatabaseFailure, err)
+	})
+}
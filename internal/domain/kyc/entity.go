@@ -0,0 +1,159 @@
+package kyc
+
+import (
+	"strings"
+	"time"
+)
+
+// MaxDocumentSize is the largest document a user may submit, in bytes.
+const MaxDocumentSize = 5 << 20
+
+// Level is how far a user's identity has been verified.
+type Level string
+
+const (
+	LevelNone  Level = "none"  // None is every user until a submission is approved.
+	LevelBasic Level = "basic" // Basic users have proven their identity.
+	LevelFull  Level = "full"  // Full users have also proven their address and source of funds.
+)
+
+func (l Level) Valid() bool {
+	return l == LevelNone || l == LevelBasic || l == LevelFull
+}
+
+// rank orders levels so that approving a submission never lowers a user's level.
+func (l Level) rank() int {
+	switch l {
+	case LevelBasic:
+		return 1
+	case LevelFull:
+		return 2
+	}
+	return 0
+}
+
+// Caps bounds what users at a level may do with their wallet. Amounts are expressed in the
+// smallest unit of the wallet's currency, and zero leaves them uncapped.
+type Caps struct {
+	MaxBalance    int64 // MaxBalance is the most the wallet may hold.
+	MaxWithdrawal int64 // MaxWithdrawal is the largest single withdrawal.
+	MaxTransfer   int64 // MaxTransfer is the largest single transfer sent or received.
+}
+
+func (c Caps) CheckBalance(balance int64) error {
+	if c.MaxBalance > 0 && balance > c.MaxBalance {
+		return ErrBalanceCapExceeded
+	}
+	return nil
+}
+
+func (c Caps) CheckWithdrawal(amount int64) error {
+	if c.MaxWithdrawal > 0 && amount > c.MaxWithdrawal {
+		return ErrWithdrawalCapExceeded
+	}
+	return nil
+}
+
+func (c Caps) CheckTransfer(amount int64) error {
+	if c.MaxTransfer > 0 && amount > c.MaxTransfer {
+		return ErrTransferCapExceeded
+	}
+	return nil
+}
+
+// Policy maps each level to its caps; levels missing from it are uncapped.
+type Policy map[Level]Caps
+
+// LevelChange is an entry in a user's verification history.
+type LevelChange struct {
+	ID           string    // ID is the unique change identifier.
+	UserID       string    // UserID is the verified user.
+	From         Level     // From is the level before the change.
+	To           Level     // To is the level after the change.
+	Reason       string    // Reason explains the change.
+	ChangedBy    string    // ChangedBy is the admin who approved the submission or set the level.
+	SubmissionID string    // SubmissionID is the approved submission; empty when an admin set the level.
+	CreatedAt    time.Time // CreatedAt is when the level changed.
+}
+
+type SubmissionStatus string
+
+const (
+	SubmissionPending  SubmissionStatus = "pending"  // Pending submissions wait for an admin's review.
+	SubmissionApproved SubmissionStatus = "approved" // Approved submissions raised the user to their level.
+	SubmissionRejected SubmissionStatus = "rejected" // Rejected submissions left the level unchanged.
+)
+
+func (s SubmissionStatus) Valid() bool {
+	return s == SubmissionPending || s == SubmissionApproved || s == SubmissionRejected
+}
+
+// Submission is a document a user sent to reach a verification level.
+type Submission struct {
+	ID           string           // ID is the unique submission identifier, also the key of the stored document.
+	UserID       string           // UserID is the user asking to be verified.
+	Level        Level            // Level is the level the user asks for.
+	DocumentType string           // DocumentType names the document, such as "passport" or "utility_bill".
+	FileName     string           // FileName is the name of the uploaded file.
+	ContentType  string           // ContentType is the media type of the uploaded file.
+	Size         int64            // Size is the length of the document in bytes.
+	Status       SubmissionStatus // Status is where the submission is in its review.
+	Reason       string           // Reason is why the submission was rejected.
+	ReviewedBy   string           // ReviewedBy is the admin who approved or rejected it.
+	CreatedAt    time.Time        // CreatedAt is when the document was submitted.
+	ReviewedAt   *time.Time       // ReviewedAt is set once the submission has been reviewed.
+}
+
+func NewSubmission(id, userID string, level Level, documentType, fileName, contentType string, size int64) (Submission, error) {
+	if strings.TrimSpace(userID) == "" {
+		return Submission{}, ErrInvalidUserID
+	}
+	if level != LevelBasic && level != LevelFull {
+		return Submission{}, ErrInvalidLevel
+	}
+	if strings.TrimSpace(documentType) == "" || size <= 0 {
+		return Submission{}, ErrInvalidDocument
+	}
+	if size > MaxDocumentSize {
+		return Submission{}, ErrDocumentTooLarge
+	}
+	if contentType == "" {
+		contentType = "application/octet-stream"
+	}
+	return Submission{
+		ID:           id,
+		UserID:       userID,
+		Level:        level,
+		DocumentType: documentType,
+		FileName:     fileName,
+		ContentType:  contentType,
+		Size:         size,
+		Status:       SubmissionPending,
+		CreatedAt:    time.Now(),
+	}, nil
+}
+
+// Review records reviewerID's decision on a pending submission. Rejections need a reason.
+func (s *Submission) Review(reviewerID string, approved bool, reason string) error {
+	if s.Status != SubmissionPending {
+		return ErrSubmissionNotPending
+	}
+	if !approved && strings.TrimSpace(reason) == "" {
+		return ErrReasonRequired
+	}
+	now := time.Now()
+	s.Status = SubmissionRejected
+	if approved {
+		s.Status = SubmissionApproved
+	}
+	s.Reason = reason
+	s.ReviewedBy = reviewerID
+	s.ReviewedAt = &now
+	return nil
+}
+
+// Filter narrows ListSubmissions; zero fields match everything.
+type Filter struct {
+	UserID string
+	Status SubmissionStatus
+}
@@ -0,0 +1,60 @@
+package kyc
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestCaps(t *testing.T) {
+	caps := Caps{MaxBalance: 1000, MaxWithdrawal: 200}
+
+	assert.NoError(t, caps.CheckBalance(1000))
+	assert.ErrorIs(t, caps.CheckBalance(1001), ErrBalanceCapExceeded)
+	assert.NoError(t, caps.CheckWithdrawal(200))
+	assert.ErrorIs(t, caps.CheckWithdrawal(201), ErrWithdrawalCapExceeded)
+	assert.NoError(t, caps.CheckTransfer(1<<40), "zero leaves transfers uncapped")
+	assert.ErrorIs(t, Caps{MaxTransfer: 100}.CheckTransfer(101), ErrTransferCapExceeded)
+}
+
+func TestNewSubmission(t *testing.T) {
+	s, err := NewSubmission("kyc1", "user1", LevelBasic, "passport", "passport.png", "", 10)
+	require.NoError(t, err)
+	assert.Equal(t, SubmissionPending, s.Status)
+	assert.Equal(t, "application/octet-stream", s.ContentType)
+
+	tests := []struct {
+		name         string
+		userID       string
+		level        Level
+		documentType string
+		size         int64
+		err          error
+	}{
+		{"missing user", "", LevelBasic, "passport", 10, ErrInvalidUserID},
+		{"level none", "user1", LevelNone, "passport", 10, ErrInvalidLevel},
+		{"unknown level", "user1", "gold", "passport", 10, ErrInvalidLevel},
+		{"missing document type", "user1", LevelFull, " ", 10, ErrInvalidDocument},
+		{"empty document", "user1", LevelFull, "passport", 0, ErrInvalidDocument},
+		{"document too large", "user1", LevelFull, "passport", MaxDocumentSize + 1, ErrDocumentTooLarge},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			_, err := NewSubmission("kyc1", tt.userID, tt.level, tt.documentType, "", "", tt.size)
+			assert.ErrorIs(t, err, tt.err)
+		})
+	}
+}
+
+func TestSubmission_Review(t *testing.T) {
+	s, err := NewSubmission("kyc1", "user1", LevelBasic, "passport", "", "", 10)
+	require.NoError(t, err)
+
+	assert.ErrorIs(t, s.Review("ops", false, " "), ErrReasonRequired)
+	require.NoError(t, s.Review("ops", true, ""))
+	assert.Equal(t, SubmissionApproved, s.Status)
+	assert.Equal(t, "ops", s.ReviewedBy)
+	assert.NotNil(t, s.ReviewedAt)
+	assert.ErrorIs(t, s.Review("ops", true, ""), ErrSubmissionNotPending)
+}
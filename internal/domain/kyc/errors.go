@@ -0,0 +1,20 @@
+package kyc
+
+import "errors"
+
+var (
+	ErrInvalidUserID         = errors.New("invalid user ID")
+	ErrInvalidLevel          = errors.New("invalid KYC level")
+	ErrInvalidStatus         = errors.New("invalid KYC submission status")
+	ErrInvalidDocument       = errors.New("invalid KYC document")
+	ErrDocumentTooLarge      = errors.New("KYC document is too large")
+	ErrReasonRequired        = errors.New("a reason is required")
+	ErrSubmissionNotFound    = errors.New("KYC submission not found")
+	ErrSubmissionNotPending  = errors.New("KYC submission has already been reviewed")
+	ErrDocumentNotFound      = errors.New("KYC document not found")
+	ErrBalanceCapExceeded    = errors.New("balance would exceed the cap of the KYC level")
+	ErrWithdrawalCapExceeded = errors.New("withdrawal exceeds the cap of the KYC level")
+	ErrTransferCapExceeded   = errors.New("transfer exceeds the cap of the KYC level")
+	ErrStorageFailure        = errors.New("document storage failure")
+	ErrDatabaseFailure       = errors.New("database failure")
+)
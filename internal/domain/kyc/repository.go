@@ -0,0 +1,26 @@
+package kyc
+
+import "context"
+
+type KYCRepository interface {
+	// GetLevel returns LevelNone for a user who has never been verified or is unknown; the
+	// operation being checked reports a missing user itself.
+	GetLevel(ctx context.Context, userID string) (Level, error)
+
+	// SetLevel stores the user's new level and appends the change to their history.
+	SetLevel(ctx context.Context, change LevelChange) error
+
+	// ListLevelChanges returns the user's verification history, oldest first.
+	ListLevelChanges(ctx context.Context, userID string) ([]LevelChange, error)
+
+	CreateSubmission(ctx context.Context, s Submission) error
+
+	GetSubmissionByID(ctx context.Context, id string) (Submission, error)
+
+	// ListSubmissions returns the submissions matching filter, newest first.
+	ListSubmissions(ctx context.Context, filter Filter, limit, offset int) ([]Submission, error)
+
+	// ReviewSubmission stores the decision recorded on s and returns ErrSubmissionNotPending
+	// if the stored submission has already been reviewed.
+	ReviewSubmission(ctx context.Context, s Submission) error
+}
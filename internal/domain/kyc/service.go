@@ -0,0 +1,192 @@
+package kyc
+
+import (
+	"context"
+	"errors"
+	"time"
+
+	"github.com/gofrs/uuid"
+)
+
+type KYCServiceInterface interface {
+	GetLevel(ctx context.Context, userID string) (Level, error)
+	GetCaps(ctx context.Context, userID string) (Caps, error)
+	CapsOf(level Level) Caps
+	ListLevelChanges(ctx context.Context, userID string) ([]LevelChange, error)
+	SetLevel(ctx context.Context, userID string, level Level, changedBy, reason string) (LevelChange, error)
+	Submit(ctx context.Context, userID string, level Level, documentType, fileName, contentType string, content []byte) (Submission, error)
+	GetSubmission(ctx context.Context, id string) (Submission, error)
+	ListSubmissions(ctx context.Context, filter Filter, limit, offset int) ([]Submission, error)
+	GetDocument(ctx context.Context, s Submission) ([]byte, error)
+	Review(ctx context.Context, id, reviewerID string, approved bool, reason string) (Submission, error)
+}
+
+type KYCService struct {
+	repository KYCRepository
+	documents  DocumentStore
+	policy     Policy
+}
+
+// NewKYCService returns a KYCService keeping submitted documents in documents and capping
+// users by the policy of their level.
+func NewKYCService(repo KYCRepository, documents DocumentStore, policy Policy) *KYCService {
+	return &KYCService{
+		repository: repo,
+		documents:  documents,
+		policy:     policy,
+	}
+}
+
+func (s *KYCService) GetLevel(ctx context.Context, userID string) (Level, error) {
+	level, err := s.repository.GetLevel(ctx, userID)
+	if err != nil {
+		return "", ErrDatabaseFailure
+	}
+	return level, nil
+}
+
+// GetCaps returns the caps of userID's current level.
+func (s *KYCService) GetCaps(ctx context.Context, userID string) (Caps, error) {
+	level, err := s.GetLevel(ctx, userID)
+	if err != nil {
+		return Caps{}, err
+	}
+	return s.CapsOf(level), nil
+}
+
+func (s *KYCService) CapsOf(level Level) Caps {
+	return s.policy[level]
+}
+
+func (s *KYCService) ListLevelChanges(ctx context.Context, userID string) ([]LevelChange, error) {
+	changes, err := s.repository.ListLevelChanges(ctx, userID)
+	if err != nil {
+		return nil, ErrDatabaseFailure
+	}
+	return changes, nil
+}
+
+// SetLevel moves userID to level by hand, for instance to downgrade a user whose documents
+// expired, and records the change in their history.
+func (s *KYCService) SetLevel(ctx context.Context, userID string, level Level, changedBy, reason string) (LevelChange, error) {
+	if !level.Valid() {
+		return LevelChange{}, ErrInvalidLevel
+	}
+	if reason == "" {
+		return LevelChange{}, ErrReasonRequired
+	}
+	from, err := s.GetLevel(ctx, userID)
+	if err != nil {
+		return LevelChange{}, err
+	}
+	return s.changeLevel(ctx, LevelChange{UserID: userID, From: from, To: level, Reason: reason, ChangedBy: changedBy})
+}
+
+// Submit stores content and records a pending submission asking for level.
+func (s *KYCService) Submit(ctx context.Context, userID string, level Level, documentType, fileName, contentType string, content []byte) (Submission, error) {
+	id, err := uuid.NewV7()
+	if err != nil {
+		return Submission{}, err
+	}
+	sub, err := NewSubmission(id.String(), userID, level, documentType, fileName, contentType, int64(len(content)))
+	if err != nil {
+		return Submission{}, err
+	}
+
+	if err := s.documents.Put(ctx, sub.ID, content); err != nil {
+		return Submission{}, ErrStorageFailure
+	}
+	if err := s.repository.CreateSubmission(ctx, sub); err != nil {
+		return Submission{}, ErrDatabaseFailure
+	}
+	return sub, nil
+}
+
+func (s *KYCService) GetSubmission(ctx context.Context, id string) (Submission, error) {
+	sub, err := s.repository.GetSubmissionByID(ctx, id)
+	if err != nil {
+		if errors.Is(err, ErrSubmissionNotFound) {
+			return Submission{}, ErrSubmissionNotFound
+		}
+		return Submission{}, ErrDatabaseFailure
+	}
+	return sub, nil
+}
+
+func (s *KYCService) ListSubmissions(ctx context.Context, filter Filter, limit, offset int) ([]Submission, error) {
+	if filter.Status != "" && !filter.Status.Valid() {
+		return nil, ErrInvalidStatus
+	}
+	submissions, err := s.repository.ListSubmissions(ctx, filter, limit, offset)
+	if err != nil {
+		return nil, ErrDatabaseFailure
+	}
+	return submissions, nil
+}
+
+// GetDocument returns the document submitted with sub.
+func (s *KYCService) GetDocument(ctx context.Context, sub Submission) ([]byte, error) {
+	content, err := s.documents.Get(ctx, sub.ID)
+	if err != nil {
+		if errors.Is(err, ErrDocumentNotFound) {
+			return nil, ErrDocumentNotFound
+		}
+		return nil, ErrStorageFailure
+	}
+	return content, nil
+}
+
+// Review approves or, for reason, rejects a pending submission. Approval raises the user
+// to the submission's level; it never lowers a user already verified further. Callers
+// should run it inside a database transaction so the decision and the level change commit
+// together.
+func (s *KYCService) Review(ctx context.Context, id, reviewerID string, approved bool, reason string) (Submission, error) {
+	sub, err := s.GetSubmission(ctx, id)
+	if err != nil {
+		return Submission{}, err
+	}
+	if err := sub.Review(reviewerID, approved, reason); err != nil {
+		return Submission{}, err
+	}
+	if err := s.repository.ReviewSubmission(ctx, sub); err != nil {
+		if errors.Is(err, ErrSubmissionNotPending) {
+			return Submission{}, ErrSubmissionNotPending
+		}
+		return Submission{}, ErrDatabaseFailure
+	}
+
+	if approved {
+		from, err := s.GetLevel(ctx, sub.UserID)
+		if err != nil {
+			return Submission{}, err
+		}
+		if sub.Level.rank() > from.rank() {
+			_, err := s.changeLevel(ctx, LevelChange{
+				UserID:       sub.UserID,
+				From:         from,
+				To:           sub.Level,
+				Reason:       "submission approved",
+				ChangedBy:    reviewerID,
+				SubmissionID: sub.ID,
+			})
+			if err != nil {
+				return Submission{}, err
+			}
+		}
+	}
+	return sub, nil
+}
+
+// changeLevel fills in the change's ID and time and stores it.
+func (s *KYCService) changeLevel(ctx context.Context, change LevelChange) (LevelChange, error) {
+	id, err := uuid.NewV7()
+	if err != nil {
+		return LevelChange{}, err
+	}
+	change.ID = id.String()
+	change.CreatedAt = time.Now()
+	if err := s.repository.SetLevel(ctx, change); err != nil {
+		return LevelChange{}, ErrDatabaseFailure
+	}
+	return change, nil
+}
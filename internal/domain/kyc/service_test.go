@@ -0,0 +1,213 @@
+package kyc
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+)
+
+type MockKYCRepository struct {
+	mock.Mock
+}
+
+func (m *MockKYCRepository) GetLevel(ctx context.Context, userID string) (Level, error) {
+	args := m.Called(ctx, userID)
+	return args.Get(0).(Level), args.Error(1)
+}
+
+func (m *MockKYCRepository) SetLevel(ctx context.Context, change LevelChange) error {
+	args := m.Called(ctx, change)
+	return args.Error(0)
+}
+
+func (m *MockKYCRepository) ListLevelChanges(ctx context.Context, userID string) ([]LevelChange, error) {
+	args := m.Called(ctx, userID)
+	return args.Get(0).([]LevelChange), args.Error(1)
+}
+
+func (m *MockKYCRepository) CreateSubmission(ctx context.Context, s Submission) error {
+	args := m.Called(ctx, s)
+	return args.Error(0)
+}
+
+func (m *MockKYCRepository) GetSubmissionByID(ctx context.Context, id string) (Submission, error) {
+	args := m.Called(ctx, id)
+	return args.Get(0).(Submission), args.Error(1)
+}
+
+func (m *MockKYCRepository) ListSubmissions(ctx context.Context, filter Filter, limit, offset int) ([]Submission, error) {
+	args := m.Called(ctx, filter, limit, offset)
+	return args.Get(0).([]Submission), args.Error(1)
+}
+
+func (m *MockKYCRepository) ReviewSubmission(ctx context.Context, s Submission) error {
+	args := m.Called(ctx, s)
+	return args.Error(0)
+}
+
+// memoryStore keeps documents in a map.
+type memoryStore map[string][]byte
+
+func (s memoryStore) Put(ctx context.Context, key string, content []byte) error {
+	s[key] = content
+	return nil
+}
+
+func (s memoryStore) Get(ctx context.Context, key string) ([]byte, error) {
+	content, ok := s[key]
+	if !ok {
+		return nil, ErrDocumentNotFound
+	}
+	return content, nil
+}
+
+var testPolicy = Policy{LevelNone: {MaxBalance: 1000}, LevelBasic: {MaxBalance: 100000}}
+
+func TestKYCService_GetCaps(t *testing.T) {
+	ctx := context.Background()
+	mockRepo := new(MockKYCRepository)
+	service := NewKYCService(mockRepo, memoryStore{}, testPolicy)
+	mockRepo.On("GetLevel", ctx, "user1").Return(LevelNone, nil)
+	mockRepo.On("GetLevel", ctx, "user2").Return(LevelFull, nil)
+	mockRepo.On("GetLevel", ctx, "user3").Return(Level(""), errors.New("connection reset"))
+
+	caps, err := service.GetCaps(ctx, "user1")
+	require.NoError(t, err)
+	assert.Equal(t, int64(1000), caps.MaxBalance)
+
+	caps, err = service.GetCaps(ctx, "user2")
+	require.NoError(t, err)
+	assert.Equal(t, Caps{}, caps, "levels missing from the policy are uncapped")
+
+	_, err = service.GetCaps(ctx, "user3")
+	assert.ErrorIs(t, err, ErrDatabaseFailure)
+}
+
+func TestKYCService_Submit(t *testing.T) {
+	ctx := context.Background()
+
+	t.Run("stores the document", func(t *testing.T) {
+		mockRepo := new(MockKYCRepository)
+		store := memoryStore{}
+		service := NewKYCService(mockRepo, store, testPolicy)
+		mockRepo.On("CreateSubmission", ctx, mock.AnythingOfType("Submission")).Return(nil)
+
+		s, err := service.Submit(ctx, "user1", LevelBasic, "passport", "passport.png", "image/png", []byte("scan"))
+
+		require.NoError(t, err)
+		assert.Equal(t, int64(4), s.Size)
+		assert.Equal(t, []byte("scan"), store[s.ID])
+		content, err := service.GetDocument(ctx, s)
+		require.NoError(t, err)
+		assert.Equal(t, []byte("scan"), content)
+	})
+
+	t.Run("invalid submission is not stored", func(t *testing.T) {
+		mockRepo := new(MockKYCRepository)
+		store := memoryStore{}
+		service := NewKYCService(mockRepo, store, testPolicy)
+
+		_, err := service.Submit(ctx, "user1", LevelNone, "passport", "", "", []byte("scan"))
+
+		assert.ErrorIs(t, err, ErrInvalidLevel)
+		assert.Empty(t, store)
+		mockRepo.AssertNotCalled(t, "CreateSubmission", mock.Anything, mock.Anything)
+	})
+}
+
+func TestKYCService_Review(t *testing.T) {
+	ctx := context.Background()
+	pending := func(level Level) Submission {
+		s, err := NewSubmission("kyc1", "user1", level, "passport", "", "", 10)
+		require.NoError(t, err)
+		return s
+	}
+
+	t.Run("approval raises the level", func(t *testing.T) {
+		mockRepo := new(MockKYCRepository)
+		service := NewKYCService(mockRepo, memoryStore{}, testPolicy)
+		mockRepo.On("GetSubmissionByID", ctx, "kyc1").Return(pending(LevelFull), nil)
+		mockRepo.On("ReviewSubmission", ctx, mock.AnythingOfType("Submission")).Return(nil)
+		mockRepo.On("GetLevel", ctx, "user1").Return(LevelBasic, nil)
+		mockRepo.On("SetLevel", ctx, mock.MatchedBy(func(c LevelChange) bool {
+			return c.From == LevelBasic && c.To == LevelFull && c.SubmissionID == "kyc1" && c.ChangedBy == "ops"
+		})).Return(nil)
+
+		s, err := service.Review(ctx, "kyc1", "ops", true, "")
+
+		require.NoError(t, err)
+		assert.Equal(t, SubmissionApproved, s.Status)
+		mockRepo.AssertExpectations(t)
+	})
+
+	t.Run("approval never lowers the level", func(t *testing.T) {
+		mockRepo := new(MockKYCRepository)
+		service := NewKYCService(mockRepo, memoryStore{}, testPolicy)
+		mockRepo.On("GetSubmissionByID", ctx, "kyc1").Return(pending(LevelBasic), nil)
+		mockRepo.On("ReviewSubmission", ctx, mock.AnythingOfType("Submission")).Return(nil)
+		mockRepo.On("GetLevel", ctx, "user1").Return(LevelFull, nil)
+
+		_, err := service.Review(ctx, "kyc1", "ops", true, "")
+
+		require.NoError(t, err)
+		mockRepo.AssertNotCalled(t, "SetLevel", mock.Anything, mock.Anything)
+	})
+
+	t.Run("rejection keeps the level", func(t *testing.T) {
+		mockRepo := new(MockKYCRepository)
+		service := NewKYCService(mockRepo, memoryStore{}, testPolicy)
+		mockRepo.On("GetSubmissionByID", ctx, "kyc1").Return(pending(LevelBasic), nil)
+		mockRepo.On("ReviewSubmission", ctx, mock.AnythingOfType("Submission")).Return(nil)
+
+		s, err := service.Review(ctx, "kyc1", "ops", false, "blurry scan")
+
+		require.NoError(t, err)
+		assert.Equal(t, "blurry scan", s.Reason)
+		mockRepo.AssertNotCalled(t, "GetLevel", mock.Anything, mock.Anything)
+	})
+
+	t.Run("reviewed concurrently", func(t *testing.T) {
+		mockRepo := new(MockKYCRepository)
+		service := NewKYCService(mockRepo, memoryStore{}, testPolicy)
+		mockRepo.On("GetSubmissionByID", ctx, "kyc1").Return(pending(LevelBasic), nil)
+		mockRepo.On("ReviewSubmission", ctx, mock.AnythingOfType("Submission")).Return(ErrSubmissionNotPending)
+
+		_, err := service.Review(ctx, "kyc1", "ops", true, "")
+
+		assert.ErrorIs(t, err, ErrSubmissionNotPending)
+	})
+
+	t.Run("unknown submission", func(t *testing.T) {
+		mockRepo := new(MockKYCRepository)
+		service := NewKYCService(mockRepo, memoryStore{}, testPolicy)
+		mockRepo.On("GetSubmissionByID", ctx, "kyc1").Return(Submission{}, ErrSubmissionNotFound)
+
+		_, err := service.Review(ctx, "kyc1", "ops", true, "")
+
+		assert.ErrorIs(t, err, ErrSubmissionNotFound)
+	})
+}
+
+func TestKYCService_SetLevel(t *testing.T) {
+	ctx := context.Background()
+	mockRepo := new(MockKYCRepository)
+	service := NewKYCService(mockRepo, memoryStore{}, testPolicy)
+	mockRepo.On("GetLevel", ctx, "user1").Return(LevelFull, nil)
+	mockRepo.On("SetLevel", ctx, mock.AnythingOfType("LevelChange")).Return(nil)
+
+	change, err := service.SetLevel(ctx, "user1", LevelNone, "ops", "document expired")
+
+	require.NoError(t, err)
+	assert.Equal(t, LevelFull, change.From)
+	assert.Equal(t, LevelNone, change.To)
+	assert.NotEmpty(t, change.ID)
+
+	_, err = service.SetLevel(ctx, "user1", LevelNone, "ops", "")
+	assert.ErrorIs(t, err, ErrReasonRequired)
+	_, err = service.SetLevel(ctx, "user1", "gold", "ops", "upgrade")
+	assert.ErrorIs(t, err, ErrInvalidLevel)
+}
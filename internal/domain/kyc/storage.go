@@ -0,0 +1,11 @@
+package kyc
+
+import "context"
+
+// DocumentStore keeps the documents users submit for verification.
+type DocumentStore interface {
+	Put(ctx context.Context, key string, content []byte) error
+
+	// Get returns ErrDocumentNotFound when nothing is stored under key.
+	Get(ctx context.Context, key string) ([]byte, error)
+}
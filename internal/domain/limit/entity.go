@@ -0,0 +1,138 @@
+package limit
+
+import (
+	"fmt"
+	"strings"
+	"time"
+)
+
+// Operation is the kind of outgoing money movement a limit caps.
+type Operation string
+
+const (
+	OperationWithdrawal Operation = "withdrawal" // Withdrawal covers immediate and asynchronous withdrawals.
+	OperationTransfer   Operation = "transfer"   // Transfer covers outgoing transfers, including batch transfers.
+)
+
+func (o Operation) Valid() bool {
+	return o == OperationWithdrawal || o == OperationTransfer
+}
+
+// Period is the calendar window, in UTC, over which a limit adds up usage.
+type Period string
+
+const (
+	PeriodDaily   Period = "daily"
+	PeriodMonthly Period = "monthly"
+)
+
+func (p Period) Valid() bool {
+	return p == PeriodDaily || p == PeriodMonthly
+}
+
+// Start returns the start of the period containing t.
+func (p Period) Start(t time.Time) time.Time {
+	t = t.UTC()
+	if p == PeriodMonthly {
+		return time.Date(t.Year(), t.Month(), 1, 0, 0, 0, 0, time.UTC)
+	}
+	return time.Date(t.Year(), t.Month(), t.Day(), 0, 0, 0, 0, time.UTC)
+}
+
+// End returns the start of the period following the one containing t.
+func (p Period) End(t time.Time) time.Time {
+	start := p.Start(t)
+	if p == PeriodMonthly {
+		return start.AddDate(0, 1, 0)
+	}
+	return start.AddDate(0, 0, 1)
+}
+
+// Tier is an account tier; every tier has its own default limits.
+type Tier string
+
+// Limit caps the volume and count of one operation in one currency per period.
+type Limit struct {
+	Operation Operation // Operation is the capped kind of money movement.
+	Currency  string    // Currency is the currency code the limit applies to.
+	Period    Period    // Period is the window usage is added up over.
+	MaxAmount int64     // MaxAmount caps the total amount per period; 0 means no cap.
+	MaxCount  int       // MaxCount caps the number of operations per period; 0 means no cap.
+}
+
+func (l Limit) Validate() error {
+	if !l.Operation.Valid() || !l.Period.Valid() || l.Currency == "" || l.MaxAmount < 0 || l.MaxCount < 0 {
+		return ErrInvalidLimit
+	}
+	return nil
+}
+
+// Applies reports whether l caps op in currency.
+func (l Limit) Applies(op Operation, currency string) bool {
+	return l.Operation == op && strings.EqualFold(l.Currency, currency)
+}
+
+// sameKey reports whether l and other cap the same operation, currency and period, so
+// that one overrides the other.
+func (l Limit) sameKey(other Limit) bool {
+	return l.Applies(other.Operation, other.Currency) && l.Period == other.Period
+}
+
+// Usage is how much of a limit's period has been used up.
+type Usage struct {
+	Amount int64 // Amount is the total amount moved so far in the period.
+	Count  int   // Count is the number of operations so far in the period.
+}
+
+// Allowance is a limit together with its usage in the current period.
+type Allowance struct {
+	Limit
+	Used     Usage
+	ResetsAt time.Time // ResetsAt is when the current period ends.
+}
+
+func NewAllowance(l Limit, used Usage, now time.Time) Allowance {
+	return Allowance{Limit: l, Used: used, ResetsAt: l.Period.End(now)}
+}
+
+// RemainingAmount returns how much more may be moved this period, or -1 when the amount
+// is not capped.
+func (a Allowance) RemainingAmount() int64 {
+	if a.MaxAmount == 0 {
+		return -1
+	}
+	return max(a.MaxAmount-a.Used.Amount, 0)
+}
+
+// RemainingCount returns how many more operations are allowed this period, or -1 when
+// their number is not capped.
+func (a Allowance) RemainingCount() int {
+	if a.MaxCount == 0 {
+		return -1
+	}
+	return max(a.MaxCount-a.Used.Count, 0)
+}
+
+// Check returns an *ExceededError if one more operation of amount would exceed the
+// allowance.
+func (a Allowance) Check(amount int64) error {
+	if (a.MaxAmount > 0 && a.Used.Amount+amount > a.MaxAmount) || (a.MaxCount > 0 && a.Used.Count+1 > a.MaxCount) {
+		return &ExceededError{Allowance: a}
+	}
+	return nil
+}
+
+// ExceededError reports the limit an operation would exceed and what is left of it. It
+// matches ErrLimitExceeded with errors.Is.
+type ExceededError struct {
+	Allowance Allowance
+}
+
+func (e *ExceededError) Error() string {
+	a := e.Allowance
+	return fmt.Sprintf("%s %s %s limit exceeded", a.Period, a.Operation, a.Currency)
+}
+
+func (e *ExceededError) Is(target error) bool {
+	return target == ErrLimitExceeded
+}
@@ -0,0 +1,56 @@
+package limit
+
+import (
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestPeriod(t *testing.T) {
+	now := time.Date(2024, 2, 29, 15, 30, 0, 0, time.FixedZone("UTC+8", 8*60*60))
+
+	assert.Equal(t, time.Date(2024, 2, 29, 0, 0, 0, 0, time.UTC), PeriodDaily.Start(now))
+	assert.Equal(t, time.Date(2024, 3, 1, 0, 0, 0, 0, time.UTC), PeriodDaily.End(now))
+	assert.Equal(t, time.Date(2024, 2, 1, 0, 0, 0, 0, time.UTC), PeriodMonthly.Start(now))
+	assert.Equal(t, time.Date(2024, 3, 1, 0, 0, 0, 0, time.UTC), PeriodMonthly.End(now))
+}
+
+func TestLimit_Validate(t *testing.T) {
+	valid := Limit{Operation: OperationWithdrawal, Currency: "USD", Period: PeriodDaily, MaxAmount: 1000}
+	assert.NoError(t, valid.Validate())
+
+	for name, l := range map[string]Limit{
+		"unknown operation": {Operation: "deposit", Currency: "USD", Period: PeriodDaily},
+		"unknown period":    {Operation: OperationTransfer, Currency: "USD", Period: "weekly"},
+		"missing currency":  {Operation: OperationTransfer, Period: PeriodDaily},
+		"negative amount":   {Operation: OperationTransfer, Currency: "USD", Period: PeriodDaily, MaxAmount: -1},
+	} {
+		assert.ErrorIs(t, l.Validate(), ErrInvalidLimit, name)
+	}
+}
+
+func TestAllowance_Check(t *testing.T) {
+	now := time.Now()
+	l := Limit{Operation: OperationTransfer, Currency: "USD", Period: PeriodDaily, MaxAmount: 1000, MaxCount: 3}
+
+	a := NewAllowance(l, Usage{Amount: 700, Count: 1}, now)
+	assert.Equal(t, int64(300), a.RemainingAmount())
+	assert.Equal(t, 2, a.RemainingCount())
+	assert.NoError(t, a.Check(300))
+
+	err := a.Check(301)
+	assert.ErrorIs(t, err, ErrLimitExceeded)
+	var exceeded *ExceededError
+	require.True(t, errors.As(err, &exceeded))
+	assert.Equal(t, int64(300), exceeded.Allowance.RemainingAmount())
+
+	a = NewAllowance(l, Usage{Amount: 100, Count: 3}, now)
+	assert.ErrorIs(t, a.Check(1), ErrLimitExceeded, "count is used up")
+
+	uncapped := NewAllowance(Limit{Operation: OperationTransfer, Currency: "USD", Period: PeriodDaily, MaxCount: 5}, Usage{Amount: 1 << 40}, now)
+	assert.Equal(t, int64(-1), uncapped.RemainingAmount())
+	assert.NoError(t, uncapped.Check(1000))
+}
@@ -0,0 +1,9 @@
+package limit
+
+import "errors"
+
+var (
+	ErrInvalidLimit    = errors.New("invalid limit")
+	ErrLimitExceeded   = errors.New("limit exceeded")
+	ErrDatabaseFailure = errors.New("database failure")
+)
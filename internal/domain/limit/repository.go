@@ -0,0 +1,13 @@
+package limit
+
+import (
+	"context"
+)
+
+type LimitRepository interface {
+	// GetTier returns the tier assigned to userID, or "" when none is.
+	GetTier(ctx context.Context, userID string) (Tier, error)
+
+	// ListOverrides returns the limits set for userID in place of their tier's defaults.
+	ListOverrides(ctx context.Context, userID string) ([]Limit, error)
+}
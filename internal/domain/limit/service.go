@@ -0,0 +1,53 @@
+package limit
+
+import (
+	"context"
+)
+
+type LimitServiceInterface interface {
+	GetLimits(ctx context.Context, userID string) ([]Limit, error)
+}
+
+type LimitService struct {
+	repository  LimitRepository
+	tiers       map[Tier][]Limit
+	defaultTier Tier
+}
+
+// NewLimitService returns a LimitService giving each user the limits of their tier in
+// tiers, or of defaultTier when they have none, with their overrides applied.
+func NewLimitService(repo LimitRepository, tiers map[Tier][]Limit, defaultTier Tier) *LimitService {
+	return &LimitService{
+		repository:  repo,
+		tiers:       tiers,
+		defaultTier: defaultTier,
+	}
+}
+
+// GetLimits returns the limits that apply to userID. An override replaces the tier's
+// default for the same operation, currency and period.
+func (s *LimitService) GetLimits(ctx context.Context, userID string) ([]Limit, error) {
+	tier, err := s.repository.GetTier(ctx, userID)
+	if err != nil {
+		return nil, ErrDatabaseFailure
+	}
+	if tier == "" {
+		tier = s.defaultTier
+	}
+	overrides, err := s.repository.ListOverrides(ctx, userID)
+	if err != nil {
+		return nil, ErrDatabaseFailure
+	}
+
+	var limits []Limit
+	for _, l := range s.tiers[tier] {
+		overridden := false
+		for _, o := range overrides {
+			overridden = overridden || o.sameKey(l)
+		}
+		if !overridden {
+			limits = append(limits, l)
+		}
+	}
+	return append(limits, overrides...), nil
+}
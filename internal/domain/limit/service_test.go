@@ -0,0 +1,75 @@
+package limit
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+)
+
+type MockLimitRepository struct {
+	mock.Mock
+}
+
+func (m *MockLimitRepository) GetTier(ctx context.Context, userID string) (Tier, error) {
+	args := m.Called(ctx, userID)
+	return args.Get(0).(Tier), args.Error(1)
+}
+
+func (m *MockLimitRepository) ListOverrides(ctx context.Context, userID string) ([]Limit, error) {
+	args := m.Called(ctx, userID)
+	return args.Get(0).([]Limit), args.Error(1)
+}
+
+func TestLimitService_GetLimits(t *testing.T) {
+	ctx := context.Background()
+	daily := Limit{Operation: OperationWithdrawal, Currency: "USD", Period: PeriodDaily, MaxAmount: 1000}
+	monthly := Limit{Operation: OperationWithdrawal, Currency: "USD", Period: PeriodMonthly, MaxAmount: 10000}
+	verified := Limit{Operation: OperationWithdrawal, Currency: "USD", Period: PeriodDaily, MaxAmount: 50000}
+	tiers := map[Tier][]Limit{"standard": {daily, monthly}, "verified": {verified}}
+
+	t.Run("default tier", func(t *testing.T) {
+		mockRepo := new(MockLimitRepository)
+		mockRepo.On("GetTier", ctx, "user1").Return(Tier(""), nil)
+		mockRepo.On("ListOverrides", ctx, "user1").Return([]Limit(nil), nil)
+
+		limits, err := NewLimitService(mockRepo, tiers, "standard").GetLimits(ctx, "user1")
+
+		assert.NoError(t, err)
+		assert.Equal(t, []Limit{daily, monthly}, limits)
+	})
+
+	t.Run("assigned tier", func(t *testing.T) {
+		mockRepo := new(MockLimitRepository)
+		mockRepo.On("GetTier", ctx, "user1").Return(Tier("verified"), nil)
+		mockRepo.On("ListOverrides", ctx, "user1").Return([]Limit(nil), nil)
+
+		limits, err := NewLimitService(mockRepo, tiers, "standard").GetLimits(ctx, "user1")
+
+		assert.NoError(t, err)
+		assert.Equal(t, []Limit{verified}, limits)
+	})
+
+	t.Run("override replaces the default", func(t *testing.T) {
+		override := Limit{Operation: OperationWithdrawal, Currency: "usd", Period: PeriodDaily, MaxAmount: 2000, MaxCount: 5}
+		mockRepo := new(MockLimitRepository)
+		mockRepo.On("GetTier", ctx, "user1").Return(Tier(""), nil)
+		mockRepo.On("ListOverrides", ctx, "user1").Return([]Limit{override}, nil)
+
+		limits, err := NewLimitService(mockRepo, tiers, "standard").GetLimits(ctx, "user1")
+
+		assert.NoError(t, err)
+		assert.Equal(t, []Limit{monthly, override}, limits)
+	})
+
+	t.Run("repository failure", func(t *testing.T) {
+		mockRepo := new(MockLimitRepository)
+		mockRepo.On("GetTier", ctx, "user1").Return(Tier(""), errors.New("connection reset"))
+
+		_, err := NewLimitService(mockRepo, tiers, "standard").GetLimits(ctx, "user1")
+
+		assert.ErrorIs(t, err, ErrDatabaseFailure)
+	})
+}
@@ -0,0 +1,174 @@
+package payment
+
+import (
+	"time"
+	"unicode/utf8"
+)
+
+// MaxMemoLength is the longest memo, in characters, a payment request may carry.
+const MaxMemoLength = 280
+
+type Status string
+
+const (
+	StatusPending   Status = "pending"   // Pending requests wait for the payer to pay or decline them.
+	StatusPaid      Status = "paid"      // Paid requests were transferred to the requester.
+	StatusDeclined  Status = "declined"  // Declined requests were refused by their payer.
+	StatusCancelled Status = "cancelled" // Cancelled requests were withdrawn by their requester.
+	StatusExpired   Status = "expired"   // Expired requests were not paid before ExpiresAt.
+)
+
+func (s Status) Valid() bool {
+	switch s {
+	case StatusPending, StatusPaid, StatusDeclined, StatusCancelled, StatusExpired:
+		return true
+	}
+	return false
+}
+
+// Request asks a payer to transfer an amount to the requester. A request without a payer
+// is open: anyone but the requester who has its link may pay it.
+type Request struct {
+	ID          string    // ID is the unique payment request identifier.
+	Token       string    // Token identifies the request in its shareable link.
+	RequesterID string    // RequesterID is the user asking to be paid.
+	PayerID     string    // PayerID is the user asked to pay, or empty for an open request.
+	Amount      int64     // Amount is expressed as an integer in the smallest currency unit.
+	Currency    string    // Currency is the currency code of the amount.
+	Memo        string    // Memo tells the payer what the request is for.
+	ExpiresAt   time.Time // ExpiresAt is when a pending request expires.
+	Status      Status    // Status is where the request is in its lifecycle.
+
+	PaidBy        string     // PaidBy is the user who paid the request, once paid.
+	TransactionID string     // TransactionID is the transfer that paid it.
+	CreatedAt     time.Time  // CreatedAt is when the request was made.
+	UpdatedAt     time.Time  // UpdatedAt is when the status last changed.
+	ClosedAt      *time.Time // ClosedAt is set once the request is no longer pending.
+}
+
+// NewRequest returns a pending request from requesterID to payerID, or an open one when
+// payerID is empty, expiring at expiresAt.
+func NewRequest(id, token, requesterID, payerID string, amount int64, currency, memo string, expiresAt, now time.Time) (Request, error) {
+	if requesterID == "" {
+		return Request{}, ErrInvalidParty
+	}
+	if payerID == requesterID {
+		return Request{}, ErrSameParty
+	}
+	if amount <= 0 {
+		return Request{}, ErrInvalidAmount
+	}
+	if currency == "" {
+		return Request{}, ErrInvalidCurrency
+	}
+	if utf8.RuneCountInString(memo) > MaxMemoLength {
+		return Request{}, ErrInvalidMemo
+	}
+	if !expiresAt.After(now) {
+		return Request{}, ErrInvalidExpiry
+	}
+	return Request{
+		ID:          id,
+		Token:       token,
+		RequesterID: requesterID,
+		PayerID:     payerID,
+		Amount:      amount,
+		Currency:    currency,
+		Memo:        memo,
+		ExpiresAt:   expiresAt,
+		Status:      StatusPending,
+		CreatedAt:   now,
+		UpdatedAt:   now,
+	}, nil
+}
+
+// Open reports whether anyone with the link may pay the request.
+func (r Request) Open() bool {
+	return r.PayerID == ""
+}
+
+// Expired reports whether the request is pending and past its expiry at now.
+func (r Request) Expired(now time.Time) bool {
+	return r.Status == StatusPending && !now.Before(r.ExpiresAt)
+}
+
+// Payable reports why userID cannot pay the request at now, if they cannot.
+func (r Request) Payable(userID string, now time.Time) error {
+	if err := r.pending(now); err != nil {
+		return err
+	}
+	if userID == r.RequesterID {
+		return ErrSameParty
+	}
+	if !r.Open() && userID != r.PayerID {
+		return ErrNotPayer
+	}
+	return nil
+}
+
+// Pay records that userID paid the request by transactionID.
+func (r *Request) Pay(userID, transactionID string, now time.Time) error {
+	if err := r.Payable(userID, now); err != nil {
+		return err
+	}
+	r.PaidBy = userID
+	r.TransactionID = transactionID
+	r.close(StatusPaid, now)
+	return nil
+}
+
+// Decline lets the payer refuse the request. Open requests have nobody to decline them;
+// their requester cancels them instead.
+func (r *Request) Decline(userID string, now time.Time) error {
+	if err := r.pending(now); err != nil {
+		return err
+	}
+	if r.Open() || userID != r.PayerID {
+		return ErrNotPayer
+	}
+	r.close(StatusDeclined, now)
+	return nil
+}
+
+// Cancel lets the requester withdraw the request.
+func (r *Request) Cancel(userID string, now time.Time) error {
+	if err := r.pending(now); err != nil {
+		return err
+	}
+	if userID != r.RequesterID {
+		return ErrNotRequester
+	}
+	r.close(StatusCancelled, now)
+	return nil
+}
+
+// Expire closes a pending request that is past its expiry.
+func (r *Request) Expire(now time.Time) error {
+	if !r.Expired(now) {
+		return ErrRequestClosed
+	}
+	r.close(StatusExpired, now)
+	return nil
+}
+
+func (r Request) pending(now time.Time) error {
+	if r.Status != StatusPending {
+		return ErrRequestClosed
+	}
+	if r.Expired(now) {
+		return ErrRequestExpired
+	}
+	return nil
+}
+
+func (r *Request) close(to Status, now time.Time) {
+	r.Status = to
+	r.UpdatedAt = now
+	r.ClosedAt = &now
+}
+
+// Filter narrows a listing of payment requests. Zero-valued fields match every request.
+type Filter struct {
+	UserID string // UserID matches requests the user made or was asked to pay.
+	Status Status // Status matches requests in this status.
+}
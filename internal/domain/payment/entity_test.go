@@ -0,0 +1,137 @@
+package payment
+
+import (
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestNewRequest(t *testing.T) {
+	now := time.Date(2024, 3, 1, 9, 0, 0, 0, time.UTC)
+	expiresAt := now.Add(72 * time.Hour)
+
+	r, err := NewRequest("req-1", "token", "alice", "bob", 5000, "USD", "dinner", expiresAt, now)
+
+	require.NoError(t, err)
+	assert.Equal(t, StatusPending, r.Status)
+	assert.False(t, r.Open())
+	assert.Equal(t, expiresAt, r.ExpiresAt)
+	assert.Equal(t, now, r.CreatedAt)
+
+	open, err := NewRequest("req-2", "token", "alice", "", 5000, "USD", "", expiresAt, now)
+	require.NoError(t, err)
+	assert.True(t, open.Open())
+
+	tests := []struct {
+		name        string
+		requesterID string
+		payerID     string
+		amount      int64
+		currency    string
+		memo        string
+		expiresAt   time.Time
+		err         error
+	}{
+		{"missing requester", "", "bob", 5000, "USD", "", expiresAt, ErrInvalidParty},
+		{"requesting from oneself", "alice", "alice", 5000, "USD", "", expiresAt, ErrSameParty},
+		{"zero amount", "alice", "bob", 0, "USD", "", expiresAt, ErrInvalidAmount},
+		{"missing currency", "alice", "bob", 5000, "", "", expiresAt, ErrInvalidCurrency},
+		{"memo too long", "alice", "bob", 5000, "USD", strings.Repeat("x", MaxMemoLength+1), expiresAt, ErrInvalidMemo},
+		{"already expired", "alice", "bob", 5000, "USD", "", now, ErrInvalidExpiry},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			_, err := NewRequest("req-1", "token", tt.requesterID, tt.payerID, tt.amount, tt.currency, tt.memo, tt.expiresAt, now)
+			assert.Equal(t, tt.err, err)
+		})
+	}
+}
+
+func newTestRequest(t *testing.T, payerID string) Request {
+	t.Helper()
+	now := time.Date(2024, 3, 1, 9, 0, 0, 0, time.UTC)
+	r, err := NewRequest("req-1", "token", "alice", payerID, 5000, "USD", "dinner", now.Add(time.Hour), now)
+	require.NoError(t, err)
+	return r
+}
+
+func TestRequest_Pay(t *testing.T) {
+	now := time.Date(2024, 3, 1, 9, 30, 0, 0, time.UTC)
+
+	t.Run("by the payer", func(t *testing.T) {
+		r := newTestRequest(t, "bob")
+
+		require.NoError(t, r.Pay("bob", "tx-1", now))
+		assert.Equal(t, StatusPaid, r.Status)
+		assert.Equal(t, "bob", r.PaidBy)
+		assert.Equal(t, "tx-1", r.TransactionID)
+		require.NotNil(t, r.ClosedAt)
+	})
+
+	t.Run("open request by anyone", func(t *testing.T) {
+		r := newTestRequest(t, "")
+
+		require.NoError(t, r.Pay("carol", "tx-1", now))
+		assert.Equal(t, "carol", r.PaidBy)
+	})
+
+	t.Run("by someone else", func(t *testing.T) {
+		r := newTestRequest(t, "bob")
+
+		assert.Equal(t, ErrNotPayer, r.Pay("carol", "tx-1", now))
+	})
+
+	t.Run("by the requester", func(t *testing.T) {
+		r := newTestRequest(t, "")
+
+		assert.Equal(t, ErrSameParty, r.Pay("alice", "tx-1", now))
+	})
+
+	t.Run("expired", func(t *testing.T) {
+		r := newTestRequest(t, "bob")
+
+		assert.Equal(t, ErrRequestExpired, r.Pay("bob", "tx-1", r.ExpiresAt))
+	})
+
+	t.Run("already paid", func(t *testing.T) {
+		r := newTestRequest(t, "bob")
+		require.NoError(t, r.Pay("bob", "tx-1", now))
+
+		assert.Equal(t, ErrRequestClosed, r.Pay("bob", "tx-2", now))
+	})
+}
+
+func TestRequest_Decline(t *testing.T) {
+	now := time.Date(2024, 3, 1, 9, 30, 0, 0, time.UTC)
+
+	r := newTestRequest(t, "bob")
+	assert.Equal(t, ErrNotPayer, r.Decline("alice", now))
+	require.NoError(t, r.Decline("bob", now))
+	assert.Equal(t, StatusDeclined, r.Status)
+	assert.Equal(t, ErrRequestClosed, r.Decline("bob", now))
+
+	open := newTestRequest(t, "")
+	assert.Equal(t, ErrNotPayer, open.Decline("carol", now))
+}
+
+func TestRequest_Cancel(t *testing.T) {
+	now := time.Date(2024, 3, 1, 9, 30, 0, 0, time.UTC)
+
+	r := newTestRequest(t, "bob")
+	assert.Equal(t, ErrNotRequester, r.Cancel("bob", now))
+	require.NoError(t, r.Cancel("alice", now))
+	assert.Equal(t, StatusCancelled, r.Status)
+	assert.Equal(t, ErrRequestClosed, r.Pay("bob", "tx-1", now))
+}
+
+func TestRequest_Expire(t *testing.T) {
+	r := newTestRequest(t, "bob")
+
+	assert.Equal(t, ErrRequestClosed, r.Expire(r.ExpiresAt.Add(-time.Second)))
+	require.NoError(t, r.Expire(r.ExpiresAt))
+	assert.Equal(t, StatusExpired, r.Status)
+	assert.False(t, r.Expired(r.ExpiresAt))
+}
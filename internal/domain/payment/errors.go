@@ -0,0 +1,20 @@
+package payment
+
+import "errors"
+
+var (
+	ErrInvalidParty     = errors.New("invalid payment request party")
+	ErrSameParty        = errors.New("payment request requester and payer must differ")
+	ErrInvalidAmount    = errors.New("invalid payment request amount")
+	ErrInvalidCurrency  = errors.New("invalid payment request currency")
+	ErrCurrencyMismatch = errors.New("payment request currency does not match the wallet")
+	ErrInvalidMemo      = errors.New("payment request memo is too long")
+	ErrInvalidExpiry    = errors.New("payment request must expire in the future")
+	ErrInvalidStatus    = errors.New("invalid payment request status")
+	ErrNotPayer         = errors.New("only the payer of a payment request can pay or decline it")
+	ErrNotRequester     = errors.New("only the requester can cancel a payment request")
+	ErrRequestNotFound  = errors.New("payment request not found")
+	ErrRequestClosed    = errors.New("payment request is no longer pending")
+	ErrRequestExpired   = errors.New("payment request has expired")
+	ErrDatabaseFailure  = errors.New("database failure")
+)
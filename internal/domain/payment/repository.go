@@ -0,0 +1,23 @@
+package payment
+
+import (
+	"context"
+	"time"
+)
+
+type RequestRepository interface {
+	CreateRequest(ctx context.Context, r Request) error
+	GetRequestByID(ctx context.Context, id string) (Request, error)
+	GetRequestByToken(ctx context.Context, token string) (Request, error)
+
+	// ListRequests returns the requests matching filter, newest first.
+	ListRequests(ctx context.Context, filter Filter, limit, offset int) ([]Request, error)
+
+	// ListExpired returns up to limit pending requests expiring at or before now, soonest
+	// first.
+	ListExpired(ctx context.Context, now time.Time, limit int) ([]Request, error)
+
+	// UpdateRequest stores the new status of r, or returns ErrRequestClosed if the stored
+	// request is no longer in status from.
+	UpdateRequest(ctx context.Context, r Request, from Status) error
+}
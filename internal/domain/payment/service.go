@@ -0,0 +1,146 @@
+package payment
+
+import (
+	"context"
+	"crypto/rand"
+	"encoding/hex"
+	"errors"
+	"time"
+
+	"github.com/gofrs/uuid"
+)
+
+// tokenBytes is the length of the random token in a request's shareable link.
+const tokenBytes = 24
+
+type RequestServiceInterface interface {
+	Create(ctx context.Context, requesterID, payerID string, amount int64, currency, memo string, expiresAt time.Time) (Request, error)
+	GetRequest(ctx context.Context, id string) (Request, error)
+	GetRequestByToken(ctx context.Context, token string) (Request, error)
+	ListRequests(ctx context.Context, filter Filter, limit, offset int) ([]Request, error)
+	ListExpired(ctx context.Context, now time.Time, limit int) ([]Request, error)
+	Payable(r Request, userID string) error
+	Pay(ctx context.Context, r Request, userID, transactionID string) (Request, error)
+	Decline(ctx context.Context, r Request, userID string) (Request, error)
+	Cancel(ctx context.Context, r Request, userID string) (Request, error)
+	Expire(ctx context.Context, r Request, now time.Time) (Request, error)
+}
+
+type RequestService struct {
+	repository RequestRepository
+	now        func() time.Time
+}
+
+func NewRequestService(repo RequestRepository) *RequestService {
+	return &RequestService{
+		repository: repo,
+		now:        time.Now,
+	}
+}
+
+// Create stores a pending request with a fresh ID and link token.
+func (s *RequestService) Create(ctx context.Context, requesterID, payerID string, amount int64, currency, memo string, expiresAt time.Time) (Request, error) {
+	id, err := uuid.NewV7()
+	if err != nil {
+		return Request{}, err
+	}
+	token := make([]byte, tokenBytes)
+	if _, err := rand.Read(token); err != nil {
+		return Request{}, err
+	}
+
+	r, err := NewRequest(id.String(), hex.EncodeToString(token), requesterID, payerID, amount, currency, memo, expiresAt, s.now())
+	if err != nil {
+		return Request{}, err
+	}
+	if err := s.repository.CreateRequest(ctx, r); err != nil {
+		return Request{}, ErrDatabaseFailure
+	}
+	return r, nil
+}
+
+func (s *RequestService) GetRequest(ctx context.Context, id string) (Request, error) {
+	return s.get(s.repository.GetRequestByID(ctx, id))
+}
+
+func (s *RequestService) GetRequestByToken(ctx context.Context, token string) (Request, error) {
+	if token == "" {
+		return Request{}, ErrRequestNotFound
+	}
+	return s.get(s.repository.GetRequestByToken(ctx, token))
+}
+
+func (s *RequestService) get(r Request, err error) (Request, error) {
+	if err != nil {
+		if errors.Is(err, ErrRequestNotFound) {
+			return Request{}, ErrRequestNotFound
+		}
+		return Request{}, ErrDatabaseFailure
+	}
+	return r, nil
+}
+
+func (s *RequestService) ListRequests(ctx context.Context, filter Filter, limit, offset int) ([]Request, error) {
+	if filter.Status != "" && !filter.Status.Valid() {
+		return nil, ErrInvalidStatus
+	}
+	requests, err := s.repository.ListRequests(ctx, filter, limit, offset)
+	if err != nil {
+		return nil, ErrDatabaseFailure
+	}
+	return requests, nil
+}
+
+func (s *RequestService) ListExpired(ctx context.Context, now time.Time, limit int) ([]Request, error) {
+	requests, err := s.repository.ListExpired(ctx, now, limit)
+	if err != nil {
+		return nil, ErrDatabaseFailure
+	}
+	return requests, nil
+}
+
+// Payable reports why userID cannot pay r now, if they cannot.
+func (s *RequestService) Payable(r Request, userID string) error {
+	return r.Payable(userID, s.now())
+}
+
+// Pay records that userID paid r by transactionID.
+func (s *RequestService) Pay(ctx context.Context, r Request, userID, transactionID string) (Request, error) {
+	return s.update(ctx, r, func(r *Request) error {
+		return r.Pay(userID, transactionID, s.now())
+	})
+}
+
+func (s *RequestService) Decline(ctx context.Context, r Request, userID string) (Request, error) {
+	return s.update(ctx, r, func(r *Request) error {
+		return r.Decline(userID, s.now())
+	})
+}
+
+func (s *RequestService) Cancel(ctx context.Context, r Request, userID string) (Request, error) {
+	return s.update(ctx, r, func(r *Request) error {
+		return r.Cancel(userID, s.now())
+	})
+}
+
+// Expire closes r if it expired by now, the time its expiry was checked against.
+func (s *RequestService) Expire(ctx context.Context, r Request, now time.Time) (Request, error) {
+	return s.update(ctx, r, func(r *Request) error {
+		return r.Expire(now)
+	})
+}
+
+// update applies fn to r and stores the result, unless r changed status in the meantime.
+func (s *RequestService) update(ctx context.Context, r Request, fn func(r *Request) error) (Request, error) {
+	from := r.Status
+	if err := fn(&r); err != nil {
+		return Request{}, err
+	}
+	if err := s.repository.UpdateRequest(ctx, r, from); err != nil {
+		if errors.Is(err, ErrRequestClosed) {
+			return Request{}, ErrRequestClosed
+		}
+		return Request{}, ErrDatabaseFailure
+	}
+	return r, nil
+}
@@ -0,0 +1,148 @@
+package payment
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+)
+
+type MockRequestRepository struct {
+	mock.Mock
+}
+
+func (m *MockRequestRepository) CreateRequest(ctx context.Context, r Request) error {
+	args := m.Called(ctx, r)
+	return args.Error(0)
+}
+
+func (m *MockRequestRepository) GetRequestByID(ctx context.Context, id string) (Request, error) {
+	args := m.Called(ctx, id)
+	return args.Get(0).(Request), args.Error(1)
+}
+
+func (m *MockRequestRepository) GetRequestByToken(ctx context.Context, token string) (Request, error) {
+	args := m.Called(ctx, token)
+	return args.Get(0).(Request), args.Error(1)
+}
+
+func (m *MockRequestRepository) ListRequests(ctx context.Context, filter Filter, limit, offset int) ([]Request, error) {
+	args := m.Called(ctx, filter, limit, offset)
+	return args.Get(0).([]Request), args.Error(1)
+}
+
+func (m *MockRequestRepository) ListExpired(ctx context.Context, now time.Time, limit int) ([]Request, error) {
+	args := m.Called(ctx, now, limit)
+	return args.Get(0).([]Request), args.Error(1)
+}
+
+func (m *MockRequestRepository) UpdateRequest(ctx context.Context, r Request, from Status) error {
+	args := m.Called(ctx, r, from)
+	return args.Error(0)
+}
+
+func newTestService() (*RequestService, *MockRequestRepository) {
+	repo := new(MockRequestRepository)
+	service := NewRequestService(repo)
+	service.now = func() time.Time { return time.Date(2024, 3, 1, 9, 30, 0, 0, time.UTC) }
+	return service, repo
+}
+
+func TestRequestService_Create(t *testing.T) {
+	ctx := context.Background()
+	service, repo := newTestService()
+	repo.On("CreateRequest", ctx, mock.Anything).Return(nil)
+
+	r, err := service.Create(ctx, "alice", "bob", 5000, "USD", "dinner", time.Date(2024, 3, 8, 0, 0, 0, 0, time.UTC))
+
+	require.NoError(t, err)
+	assert.NotEmpty(t, r.ID)
+	assert.Len(t, r.Token, 2*tokenBytes)
+	assert.Equal(t, StatusPending, r.Status)
+	repo.AssertCalled(t, "CreateRequest", ctx, r)
+
+	other, err := service.Create(ctx, "alice", "", 5000, "USD", "", time.Date(2024, 3, 8, 0, 0, 0, 0, time.UTC))
+	require.NoError(t, err)
+	assert.NotEqual(t, r.Token, other.Token)
+
+	_, err = service.Create(ctx, "alice", "bob", 5000, "USD", "", time.Date(2024, 2, 1, 0, 0, 0, 0, time.UTC))
+	assert.Equal(t, ErrInvalidExpiry, err)
+}
+
+func TestRequestService_GetRequestByToken(t *testing.T) {
+	ctx := context.Background()
+	service, repo := newTestService()
+	repo.On("GetRequestByToken", ctx, "unknown").Return(Request{}, ErrRequestNotFound)
+	repo.On("GetRequestByToken", ctx, "broken").Return(Request{}, errors.New("connection reset"))
+
+	_, err := service.GetRequestByToken(ctx, "")
+	assert.Equal(t, ErrRequestNotFound, err)
+	_, err = service.GetRequestByToken(ctx, "unknown")
+	assert.Equal(t, ErrRequestNotFound, err)
+	_, err = service.GetRequestByToken(ctx, "broken")
+	assert.Equal(t, ErrDatabaseFailure, err)
+}
+
+func TestRequestService_ListRequests(t *testing.T) {
+	ctx := context.Background()
+	service, repo := newTestService()
+	filter := Filter{UserID: "alice", Status: StatusPending}
+	repo.On("ListRequests", ctx, filter, 10, 0).Return([]Request{{ID: "req-1"}}, nil)
+
+	requests, err := service.ListRequests(ctx, filter, 10, 0)
+
+	require.NoError(t, err)
+	assert.Len(t, requests, 1)
+
+	_, err = service.ListRequests(ctx, Filter{Status: "lost"}, 10, 0)
+	assert.Equal(t, ErrInvalidStatus, err)
+}
+
+func TestRequestService_Pay(t *testing.T) {
+	ctx := context.Background()
+	start := time.Date(2024, 3, 1, 9, 0, 0, 0, time.UTC)
+	pending, err := NewRequest("req-1", "token", "alice", "bob", 5000, "USD", "", start.Add(time.Hour), start)
+	require.NoError(t, err)
+
+	t.Run("success", func(t *testing.T) {
+		service, repo := newTestService()
+		repo.On("UpdateRequest", ctx, mock.Anything, StatusPending).Return(nil)
+
+		r, err := service.Pay(ctx, pending, "bob", "tx-1")
+
+		require.NoError(t, err)
+		assert.Equal(t, StatusPaid, r.Status)
+		assert.Equal(t, "tx-1", r.TransactionID)
+	})
+
+	t.Run("closed in the meantime", func(t *testing.T) {
+		service, repo := newTestService()
+		repo.On("UpdateRequest", ctx, mock.Anything, StatusPending).Return(ErrRequestClosed)
+
+		_, err := service.Pay(ctx, pending, "bob", "tx-1")
+
+		assert.Equal(t, ErrRequestClosed, err)
+	})
+
+	t.Run("not the payer", func(t *testing.T) {
+		service, repo := newTestService()
+
+		_, err := service.Pay(ctx, pending, "carol", "tx-1")
+
+		assert.Equal(t, ErrNotPayer, err)
+		repo.AssertNotCalled(t, "UpdateRequest", mock.Anything, mock.Anything, mock.Anything)
+	})
+
+	t.Run("repository failure", func(t *testing.T) {
+		service, repo := newTestService()
+		repo.On("UpdateRequest", ctx, mock.Anything, StatusPending).Return(errors.New("connection reset"))
+
+		_, err := service.Pay(ctx, pending, "bob", "tx-1")
+
+		assert.Equal(t, ErrDatabaseFailure, err)
+	})
+}
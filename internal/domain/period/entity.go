@@ -0,0 +1,32 @@
+package period
+
+import "time"
+
+// Close locks the calendar month [Start, End), in UTC, and every month before it: once
+// closed, no correction may be booked on a date inside them.
+type Close struct {
+	Start    time.Time // Start is the first instant of the closed month.
+	End      time.Time // End is the first instant of the following month.
+	ClosedBy string    // ClosedBy is the admin who closed the month.
+	ClosedAt time.Time
+}
+
+// NewClose closes the month containing month on behalf of adminID. A month can only be
+// closed once it is over at now.
+func NewClose(month time.Time, adminID string, now time.Time) (Close, error) {
+	if adminID == "" {
+		return Close{}, ErrInvalidAdmin
+	}
+	month = month.UTC()
+	start := time.Date(month.Year(), month.Month(), 1, 0, 0, 0, 0, time.UTC)
+	end := start.AddDate(0, 1, 0)
+	if now.Before(end) {
+		return Close{}, ErrPeriodNotEnded
+	}
+	return Close{
+		Start:    start,
+		End:      end,
+		ClosedBy: adminID,
+		ClosedAt: now,
+	}, nil
+}
@@ -0,0 +1,37 @@
+package period
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestNewClose(t *testing.T) {
+	now := time.Date(2024, 4, 2, 9, 0, 0, 0, time.UTC)
+
+	c, err := NewClose(time.Date(2024, 3, 17, 15, 0, 0, 0, time.UTC), "admin1", now)
+
+	assert.NoError(t, err)
+	assert.Equal(t, time.Date(2024, 3, 1, 0, 0, 0, 0, time.UTC), c.Start)
+	assert.Equal(t, time.Date(2024, 4, 1, 0, 0, 0, 0, time.UTC), c.End)
+	assert.Equal(t, "admin1", c.ClosedBy)
+	assert.Equal(t, now, c.ClosedAt)
+
+	tests := []struct {
+		name    string
+		month   time.Time
+		adminID string
+		err     error
+	}{
+		{"missing admin", time.Date(2024, 3, 1, 0, 0, 0, 0, time.UTC), "", ErrInvalidAdmin},
+		{"current month", time.Date(2024, 4, 1, 0, 0, 0, 0, time.UTC), "admin1", ErrPeriodNotEnded},
+		{"future month", time.Date(2024, 6, 1, 0, 0, 0, 0, time.UTC), "admin1", ErrPeriodNotEnded},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			_, err := NewClose(tt.month, tt.adminID, now)
+			assert.Equal(t, tt.err, err)
+		})
+	}
+}
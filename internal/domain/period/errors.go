@@ -0,0 +1,12 @@
+package period
+
+import "errors"
+
+var (
+	ErrInvalidAdmin    = errors.New("closing a period requires an admin")
+	ErrPeriodNotEnded  = errors.New("period has not ended yet")
+	ErrAlreadyClosed   = errors.New("period is already closed")
+	ErrPeriodClosed    = errors.New("period is closed")
+	ErrCloseNotFound   = errors.New("no period has been closed")
+	ErrDatabaseFailure = errors.New("database failure")
+)
@@ -0,0 +1,14 @@
+package period
+
+import "context"
+
+type PeriodRepository interface {
+	// CreateClose stores c, or returns ErrAlreadyClosed if its month was closed before.
+	CreateClose(ctx context.Context, c Close) error
+
+	// GetLatestClose returns the close of the latest closed month, or ErrCloseNotFound.
+	GetLatestClose(ctx context.Context) (Close, error)
+
+	// ListCloses returns the closes, latest month first.
+	ListCloses(ctx context.Context, limit, offset int) ([]Close, error)
+}
@@ -0,0 +1,73 @@
+package period
+
+import (
+	"context"
+	"errors"
+	"time"
+)
+
+type PeriodServiceInterface interface {
+	Close(ctx context.Context, month time.Time, adminID string) (Close, error)
+	ListCloses(ctx context.Context, limit, offset int) ([]Close, error)
+	CheckOpen(ctx context.Context, at time.Time) error
+}
+
+type PeriodService struct {
+	repository PeriodRepository
+	now        func() time.Time
+}
+
+func NewPeriodService(repo PeriodRepository) *PeriodService {
+	return &PeriodService{
+		repository: repo,
+		now:        time.Now,
+	}
+}
+
+// Close closes the month containing month. Months are closed in order, so a month at or
+// before the latest closed one is already closed.
+func (s *PeriodService) Close(ctx context.Context, month time.Time, adminID string) (Close, error) {
+	c, err := NewClose(month, adminID, s.now())
+	if err != nil {
+		return Close{}, err
+	}
+
+	latest, err := s.repository.GetLatestClose(ctx)
+	switch {
+	case err == nil && !c.End.After(latest.End):
+		return Close{}, ErrAlreadyClosed
+	case err != nil && !errors.Is(err, ErrCloseNotFound):
+		return Close{}, ErrDatabaseFailure
+	}
+
+	if err := s.repository.CreateClose(ctx, c); err != nil {
+		if errors.Is(err, ErrAlreadyClosed) {
+			return Close{}, ErrAlreadyClosed
+		}
+		return Close{}, ErrDatabaseFailure
+	}
+	return c, nil
+}
+
+func (s *PeriodService) ListCloses(ctx context.Context, limit, offset int) ([]Close, error) {
+	closes, err := s.repository.ListCloses(ctx, limit, offset)
+	if err != nil {
+		return nil, ErrDatabaseFailure
+	}
+	return closes, nil
+}
+
+// CheckOpen returns ErrPeriodClosed if at falls in a closed month.
+func (s *PeriodService) CheckOpen(ctx context.Context, at time.Time) error {
+	latest, err := s.repository.GetLatestClose(ctx)
+	if err != nil {
+		if errors.Is(err, ErrCloseNotFound) {
+			return nil
+		}
+		return ErrDatabaseFailure
+	}
+	if at.Before(latest.End) {
+		return ErrPeriodClosed
+	}
+	return nil
+}
@@ -0,0 +1,140 @@
+package period
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+)
+
+type MockPeriodRepository struct {
+	mock.Mock
+}
+
+func (m *MockPeriodRepository) CreateClose(ctx context.Context, c Close) error {
+	args := m.Called(ctx, c)
+	return args.Error(0)
+}
+
+func (m *MockPeriodRepository) GetLatestClose(ctx context.Context) (Close, error) {
+	args := m.Called(ctx)
+	return args.Get(0).(Close), args.Error(1)
+}
+
+func (m *MockPeriodRepository) ListCloses(ctx context.Context, limit, offset int) ([]Close, error) {
+	args := m.Called(ctx, limit, offset)
+	return args.Get(0).([]Close), args.Error(1)
+}
+
+func newTestService() (*PeriodService, *MockPeriodRepository) {
+	repo := new(MockPeriodRepository)
+	service := NewPeriodService(repo)
+	service.now = func() time.Time { return time.Date(2024, 4, 2, 9, 0, 0, 0, time.UTC) }
+	return service, repo
+}
+
+func TestPeriodService_Close(t *testing.T) {
+	ctx := context.Background()
+	march := time.Date(2024, 3, 1, 0, 0, 0, 0, time.UTC)
+	february := Close{Start: time.Date(2024, 2, 1, 0, 0, 0, 0, time.UTC), End: march, ClosedBy: "admin1"}
+
+	t.Run("first close", func(t *testing.T) {
+		service, repo := newTestService()
+		repo.On("GetLatestClose", ctx).Return(Close{}, ErrCloseNotFound)
+		repo.On("CreateClose", ctx, mock.AnythingOfType("Close")).Return(nil)
+
+		c, err := service.Close(ctx, march, "admin1")
+
+		assert.NoError(t, err)
+		assert.Equal(t, march, c.Start)
+		repo.AssertExpectations(t)
+	})
+
+	t.Run("next month", func(t *testing.T) {
+		service, repo := newTestService()
+		repo.On("GetLatestClose", ctx).Return(february, nil)
+		repo.On("CreateClose", ctx, mock.AnythingOfType("Close")).Return(nil)
+
+		_, err := service.Close(ctx, march, "admin1")
+
+		assert.NoError(t, err)
+	})
+
+	t.Run("month already closed", func(t *testing.T) {
+		service, repo := newTestService()
+		repo.On("GetLatestClose", ctx).Return(february, nil)
+
+		_, err := service.Close(ctx, february.Start, "admin1")
+
+		assert.Equal(t, ErrAlreadyClosed, err)
+		repo.AssertNotCalled(t, "CreateClose", mock.Anything, mock.Anything)
+	})
+
+	t.Run("month before the latest close", func(t *testing.T) {
+		service, repo := newTestService()
+		repo.On("GetLatestClose", ctx).Return(february, nil)
+
+		_, err := service.Close(ctx, time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC), "admin1")
+
+		assert.Equal(t, ErrAlreadyClosed, err)
+	})
+
+	t.Run("month not over", func(t *testing.T) {
+		service, repo := newTestService()
+
+		_, err := service.Close(ctx, time.Date(2024, 4, 1, 0, 0, 0, 0, time.UTC), "admin1")
+
+		assert.Equal(t, ErrPeriodNotEnded, err)
+		repo.AssertNotCalled(t, "GetLatestClose", mock.Anything)
+	})
+
+	t.Run("concurrent close", func(t *testing.T) {
+		service, repo := newTestService()
+		repo.On("GetLatestClose", ctx).Return(february, nil)
+		repo.On("CreateClose", ctx, mock.AnythingOfType("Close")).Return(ErrAlreadyClosed)
+
+		_, err := service.Close(ctx, march, "admin1")
+
+		assert.Equal(t, ErrAlreadyClosed, err)
+	})
+
+	t.Run("repository failure", func(t *testing.T) {
+		service, repo := newTestService()
+		repo.On("GetLatestClose", ctx).Return(Close{}, errors.New("connection reset"))
+
+		_, err := service.Close(ctx, march, "admin1")
+
+		assert.Equal(t, ErrDatabaseFailure, err)
+	})
+}
+
+func TestPeriodService_CheckOpen(t *testing.T) {
+	ctx := context.Background()
+	february := Close{Start: time.Date(2024, 2, 1, 0, 0, 0, 0, time.UTC), End: time.Date(2024, 3, 1, 0, 0, 0, 0, time.UTC)}
+
+	t.Run("nothing closed", func(t *testing.T) {
+		service, repo := newTestService()
+		repo.On("GetLatestClose", ctx).Return(Close{}, ErrCloseNotFound)
+
+		assert.NoError(t, service.CheckOpen(ctx, time.Date(2020, 1, 1, 0, 0, 0, 0, time.UTC)))
+	})
+
+	t.Run("closed and open dates", func(t *testing.T) {
+		service, repo := newTestService()
+		repo.On("GetLatestClose", ctx).Return(february, nil)
+
+		assert.Equal(t, ErrPeriodClosed, service.CheckOpen(ctx, february.End.Add(-time.Second)))
+		assert.Equal(t, ErrPeriodClosed, service.CheckOpen(ctx, time.Date(2023, 12, 31, 0, 0, 0, 0, time.UTC)))
+		assert.NoError(t, service.CheckOpen(ctx, february.End))
+	})
+
+	t.Run("repository failure", func(t *testing.T) {
+		service, repo := newTestService()
+		repo.On("GetLatestClose", ctx).Return(Close{}, errors.New("connection reset"))
+
+		assert.Equal(t, ErrDatabaseFailure, service.CheckOpen(ctx, february.End))
+	})
+}
@@ -0,0 +1,176 @@
+package reserves
+
+import (
+	"cmp"
+	"crypto/sha256"
+	"encoding/hex"
+	"slices"
+	"strconv"
+	"time"
+)
+
+// Leaf is the liability towards one wallet: its balance at the time of the tree. The
+// nonce is handed out only with the wallet's own proof, so the leaf hashes a user sees
+// in their path reveal nothing about other wallets.
+type Leaf struct {
+	UserID  string
+	Balance int64
+	Nonce   string
+}
+
+// NewLeaf returns the leaf of userID's wallet. Liabilities cannot be negative.
+func NewLeaf(userID string, balance int64, nonce string) (Leaf, error) {
+	if userID == "" || nonce == "" || balance < 0 {
+		return Leaf{}, ErrInvalidLeaf
+	}
+	return Leaf{UserID: userID, Balance: balance, Nonce: nonce}, nil
+}
+
+// Hash returns the hex SHA-256 of "nonce:currency:balance:user_id".
+func (l Leaf) Hash(currency string) string {
+	return hash(l.Nonce + ":" + currency + ":" + strconv.FormatInt(l.Balance, 10) + ":" + l.UserID)
+}
+
+// Root is the published commitment to the liabilities in one currency: the root hash of
+// the tree and the total of every balance below it.
+type Root struct {
+	Currency string
+	Hash     string
+	Total    int64
+	Wallets  int
+	TakenAt  time.Time
+}
+
+// Step is the sibling of a node on the path from a leaf to the root.
+type Step struct {
+	Hash string
+	Sum  int64
+	Left bool // Left is true when the sibling is the left child of the parent.
+}
+
+// Proof shows that Leaf is included in the tree committed to by Root.
+type Proof struct {
+	Root Root
+	Leaf Leaf
+	Path []Step
+}
+
+// Verify recomputes the root from the leaf and its path. Sibling sums must not be
+// negative, or a negative subtree could hide liabilities from the total.
+func (p Proof) Verify() bool {
+	n := node{hash: p.Leaf.Hash(p.Root.Currency), sum: p.Leaf.Balance}
+	for _, s := range p.Path {
+		if s.Sum < 0 {
+			return false
+		}
+		sibling := node{hash: s.Hash, sum: s.Sum}
+		if s.Left {
+			n = parent(sibling, n)
+		} else {
+			n = parent(n, sibling)
+		}
+	}
+	return n.hash == p.Root.Hash && n.sum == p.Root.Total
+}
+
+// Tree is a Merkle sum tree over the liabilities in one currency. Every node carries the
+// sum of the balances below it, and a parent hashes both children's hashes and sums, so
+// a proof commits a user's balance to the published total as well as to the root hash.
+// Leaves are ordered by hash, which keeps a wallet's position from revealing anything.
+type Tree struct {
+	currency string
+	takenAt  time.Time
+	leaves   []Leaf
+	levels   [][]node
+}
+
+type node struct {
+	hash string
+	sum  int64
+}
+
+// NewTree builds the tree of currency at takenAt over leaves. A node without a sibling
+// moves up a level unchanged.
+func NewTree(currency string, takenAt time.Time, leaves []Leaf) (*Tree, error) {
+	if len(leaves) == 0 {
+		return nil, ErrEmptyTree
+	}
+	type hashed struct {
+		leaf Leaf
+		node node
+	}
+	sorted := make([]hashed, len(leaves))
+	for i, l := range leaves {
+		if l.UserID == "" || l.Nonce == "" || l.Balance < 0 {
+			return nil, ErrInvalidLeaf
+		}
+		sorted[i] = hashed{leaf: l, node: node{hash: l.Hash(currency), sum: l.Balance}}
+	}
+	slices.SortFunc(sorted, func(a, b hashed) int { return cmp.Compare(a.node.hash, b.node.hash) })
+
+	ordered := make([]Leaf, len(sorted))
+	level := make([]node, len(sorted))
+	for i, h := range sorted {
+		ordered[i], level[i] = h.leaf, h.node
+	}
+
+	levels := [][]node{level}
+	for len(level) > 1 {
+		next := make([]node, 0, (len(level)+1)/2)
+		for i := 0; i < len(level); i += 2 {
+			if i+1 == len(level) {
+				next = append(next, level[i])
+				continue
+			}
+			next = append(next, parent(level[i], level[i+1]))
+		}
+		levels = append(levels, next)
+		level = next
+	}
+	return &Tree{currency: currency, takenAt: takenAt, leaves: ordered, levels: levels}, nil
+}
+
+func (t *Tree) Root() Root {
+	top := t.levels[len(t.levels)-1][0]
+	return Root{
+		Currency: t.currency,
+		Hash:     top.hash,
+		Total:    top.sum,
+		Wallets:  len(t.leaves),
+		TakenAt:  t.takenAt,
+	}
+}
+
+// Leaves returns the leaves in tree order.
+func (t *Tree) Leaves() []Leaf {
+	return slices.Clone(t.leaves)
+}
+
+// Prove returns the inclusion proof of userID's leaf, or ErrLeafNotFound.
+func (t *Tree) Prove(userID string) (Proof, error) {
+	i := slices.IndexFunc(t.leaves, func(l Leaf) bool { return l.UserID == userID })
+	if i < 0 {
+		return Proof{}, ErrLeafNotFound
+	}
+	proof := Proof{Root: t.Root(), Leaf: t.leaves[i]}
+	for _, level := range t.levels[:len(t.levels)-1] {
+		if sibling := i ^ 1; sibling < len(level) {
+			proof.Path = append(proof.Path, Step{Hash: level[sibling].hash, Sum: level[sibling].sum, Left: sibling < i})
+		}
+		i /= 2
+	}
+	return proof, nil
+}
+
+// parent hashes "left_hash:left_sum:right_hash:right_sum".
+func parent(left, right node) node {
+	return node{
+		hash: hash(left.hash + ":" + strconv.FormatInt(left.sum, 10) + ":" + right.hash + ":" + strconv.FormatInt(right.sum, 10)),
+		sum:  left.sum + right.sum,
+	}
+}
+
+func hash(s string) string {
+	sum := sha256.Sum256([]byte(s))
+	return hex.EncodeToString(sum[:])
+}
@@ -0,0 +1,146 @@
+package reserves
+
+import (
+	"fmt"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func testLeaves(n int) []Leaf {
+	leaves := make([]Leaf, n)
+	for i := range leaves {
+		leaves[i] = Leaf{UserID: fmt.Sprintf("user%d", i), Balance: int64(100 * (i + 1)), Nonce: fmt.Sprintf("nonce%d", i)}
+	}
+	return leaves
+}
+
+func TestNewLeaf(t *testing.T) {
+	l, err := NewLeaf("user1", 0, "abc")
+	assert.NoError(t, err)
+	assert.Equal(t, Leaf{UserID: "user1", Nonce: "abc"}, l)
+
+	_, err = NewLeaf("user1", -1, "abc")
+	assert.Equal(t, ErrInvalidLeaf, err)
+	_, err = NewLeaf("", 100, "abc")
+	assert.Equal(t, ErrInvalidLeaf, err)
+	_, err = NewLeaf("user1", 100, "")
+	assert.Equal(t, ErrInvalidLeaf, err)
+}
+
+func TestLeaf_Hash(t *testing.T) {
+	l := Leaf{UserID: "user1", Balance: 1500, Nonce: "abc"}
+
+	assert.Equal(t, hash("abc:USD:1500:user1"), l.Hash("USD"))
+	assert.NotEqual(t, l.Hash("USD"), l.Hash("EUR"))
+}
+
+func TestNewTree(t *testing.T) {
+	takenAt := time.Date(2024, 3, 1, 0, 0, 0, 0, time.UTC)
+
+	t.Run("single leaf", func(t *testing.T) {
+		l := Leaf{UserID: "user1", Balance: 1500, Nonce: "abc"}
+
+		tree, err := NewTree("USD", takenAt, []Leaf{l})
+
+		require.NoError(t, err)
+		assert.Equal(t, Root{Currency: "USD", Hash: l.Hash("USD"), Total: 1500, Wallets: 1, TakenAt: takenAt}, tree.Root())
+	})
+
+	t.Run("sums every balance", func(t *testing.T) {
+		tree, err := NewTree("USD", takenAt, testLeaves(5))
+
+		require.NoError(t, err)
+		assert.Equal(t, int64(1500), tree.Root().Total)
+		assert.Equal(t, 5, tree.Root().Wallets)
+	})
+
+	t.Run("independent of input order", func(t *testing.T) {
+		leaves := testLeaves(4)
+		a, err := NewTree("USD", takenAt, leaves)
+		require.NoError(t, err)
+		b, err := NewTree("USD", takenAt, []Leaf{leaves[3], leaves[1], leaves[0], leaves[2]})
+		require.NoError(t, err)
+
+		assert.Equal(t, a.Root(), b.Root())
+		assert.Equal(t, a.Leaves(), b.Leaves())
+	})
+
+	t.Run("changed balance changes the root", func(t *testing.T) {
+		leaves := testLeaves(4)
+		a, err := NewTree("USD", takenAt, leaves)
+		require.NoError(t, err)
+		leaves[2].Balance++
+		b, err := NewTree("USD", takenAt, leaves)
+		require.NoError(t, err)
+
+		assert.NotEqual(t, a.Root().Hash, b.Root().Hash)
+	})
+
+	t.Run("no leaves", func(t *testing.T) {
+		_, err := NewTree("USD", takenAt, nil)
+		assert.Equal(t, ErrEmptyTree, err)
+	})
+
+	t.Run("negative balance", func(t *testing.T) {
+		_, err := NewTree("USD", takenAt, []Leaf{{UserID: "user1", Balance: -1, Nonce: "abc"}})
+		assert.Equal(t, ErrInvalidLeaf, err)
+	})
+}
+
+func TestTree_Prove(t *testing.T) {
+	takenAt := time.Date(2024, 3, 1, 0, 0, 0, 0, time.UTC)
+
+	for _, n := range []int{1, 2, 3, 5, 8, 13} {
+		t.Run(fmt.Sprintf("%d leaves", n), func(t *testing.T) {
+			tree, err := NewTree("USD", takenAt, testLeaves(n))
+			require.NoError(t, err)
+
+			for _, l := range testLeaves(n) {
+				proof, err := tree.Prove(l.UserID)
+				require.NoError(t, err)
+				assert.Equal(t, l, proof.Leaf)
+				assert.Equal(t, tree.Root(), proof.Root)
+				assert.True(t, proof.Verify(), l.UserID)
+			}
+		})
+	}
+
+	t.Run("unknown user", func(t *testing.T) {
+		tree, err := NewTree("USD", takenAt, testLeaves(3))
+		require.NoError(t, err)
+
+		_, err = tree.Prove("nobody")
+		assert.Equal(t, ErrLeafNotFound, err)
+	})
+}
+
+func TestProof_Verify(t *testing.T) {
+	tree, err := NewTree("USD", time.Date(2024, 3, 1, 0, 0, 0, 0, time.UTC), testLeaves(5))
+	require.NoError(t, err)
+	proof, err := tree.Prove("user2")
+	require.NoError(t, err)
+
+	tests := []struct {
+		name   string
+		tamper func(p *Proof)
+	}{
+		{"inflated balance", func(p *Proof) { p.Leaf.Balance++ }},
+		{"other user", func(p *Proof) { p.Leaf.UserID = "user3" }},
+		{"other currency", func(p *Proof) { p.Root.Currency = "EUR" }},
+		{"understated total", func(p *Proof) { p.Root.Total-- }},
+		{"sibling sum shifted", func(p *Proof) { p.Path[0].Sum--; p.Path[1].Sum++ }},
+		{"negative sibling", func(p *Proof) { p.Path[0].Sum = -p.Path[0].Sum }},
+		{"swapped side", func(p *Proof) { p.Path[0].Left = !p.Path[0].Left }},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			p := proof
+			p.Path = append([]Step(nil), proof.Path...)
+			tt.tamper(&p)
+			assert.False(t, p.Verify())
+		})
+	}
+}
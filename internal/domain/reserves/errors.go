@@ -0,0 +1,12 @@
+package reserves
+
+import "errors"
+
+var (
+	ErrInvalidLeaf      = errors.New("invalid liability leaf")
+	ErrEmptyTree        = errors.New("liability tree has no leaves")
+	ErrAlreadyPublished = errors.New("liabilities are already published for this time")
+	ErrRootNotFound     = errors.New("no liabilities have been published")
+	ErrLeafNotFound     = errors.New("wallet is not included in the published liabilities")
+	ErrDatabaseFailure  = errors.New("database failure")
+)
@@ -0,0 +1,21 @@
+package reserves
+
+import (
+	"context"
+	"time"
+)
+
+type ReservesRepository interface {
+	// CreateTree stores root with the leaves it was built from, or returns
+	// ErrAlreadyPublished when its currency already has a root taken at the same time.
+	CreateTree(ctx context.Context, root Root, leaves []Leaf) error
+
+	// GetLatestRoot returns the newest root of currency, or ErrRootNotFound.
+	GetLatestRoot(ctx context.Context, currency string) (Root, error)
+
+	// ListLatestRoots returns the newest root of every currency, ordered by currency.
+	ListLatestRoots(ctx context.Context) ([]Root, error)
+
+	// ListLeaves returns the leaves of the root of currency taken at takenAt.
+	ListLeaves(ctx context.Context, currency string, takenAt time.Time) ([]Leaf, error)
+}
@@ -0,0 +1,98 @@
+package reserves
+
+import (
+	"context"
+	"crypto/rand"
+	"encoding/hex"
+	"errors"
+	"time"
+)
+
+type ReservesServiceInterface interface {
+	Publish(ctx context.Context, currency string, takenAt time.Time, balances map[string]int64) (Root, error)
+	LatestRoots(ctx context.Context) ([]Root, error)
+	Prove(ctx context.Context, userID, currency string) (Proof, error)
+}
+
+type ReservesService struct {
+	repository ReservesRepository
+}
+
+func NewReservesService(repo ReservesRepository) *ReservesService {
+	return &ReservesService{
+		repository: repo,
+	}
+}
+
+// Publish builds the tree of currency at takenAt over balances, keyed by user ID, and
+// stores its root and leaves. Each leaf gets a fresh random nonce.
+func (s *ReservesService) Publish(ctx context.Context, currency string, takenAt time.Time, balances map[string]int64) (Root, error) {
+	if currency == "" || takenAt.IsZero() {
+		return Root{}, ErrInvalidLeaf
+	}
+	leaves := make([]Leaf, 0, len(balances))
+	for userID, balance := range balances {
+		nonce := make([]byte, 16)
+		if _, err := rand.Read(nonce); err != nil {
+			return Root{}, err
+		}
+		l, err := NewLeaf(userID, balance, hex.EncodeToString(nonce))
+		if err != nil {
+			return Root{}, err
+		}
+		leaves = append(leaves, l)
+	}
+	tree, err := NewTree(currency, takenAt, leaves)
+	if err != nil {
+		return Root{}, err
+	}
+
+	root := tree.Root()
+	if err := s.repository.CreateTree(ctx, root, tree.Leaves()); err != nil {
+		if errors.Is(err, ErrAlreadyPublished) {
+			return Root{}, ErrAlreadyPublished
+		}
+		return Root{}, ErrDatabaseFailure
+	}
+	return root, nil
+}
+
+// LatestRoots returns the newest root of every currency.
+func (s *ReservesService) LatestRoots(ctx context.Context) ([]Root, error) {
+	roots, err := s.repository.ListLatestRoots(ctx)
+	if err != nil {
+		return nil, ErrDatabaseFailure
+	}
+	return roots, nil
+}
+
+// Prove returns the inclusion proof of userID in the newest tree of currency. The tree is
+// rebuilt from the stored leaves, and a tree that no longer matches its published root
+// is reported as a database failure rather than handed out.
+func (s *ReservesService) Prove(ctx context.Context, userID, currency string) (Proof, error) {
+	root, err := s.repository.GetLatestRoot(ctx, currency)
+	if err != nil {
+		if errors.Is(err, ErrRootNotFound) {
+			return Proof{}, ErrRootNotFound
+		}
+		return Proof{}, ErrDatabaseFailure
+	}
+	leaves, err := s.repository.ListLeaves(ctx, currency, root.TakenAt)
+	if err != nil {
+		return Proof{}, ErrDatabaseFailure
+	}
+	tree, err := NewTree(currency, root.TakenAt, leaves)
+	if err != nil {
+		return Proof{}, ErrDatabaseFailure
+	}
+	if rebuilt := tree.Root(); rebuilt.Hash != root.Hash || rebuilt.Total != root.Total {
+		return Proof{}, ErrDatabaseFailure
+	}
+
+	proof, err := tree.Prove(userID)
+	if err != nil {
+		return Proof{}, err
+	}
+	proof.Root = root
+	return proof, nil
+}
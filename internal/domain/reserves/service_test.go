@@ -0,0 +1,150 @@
+package reserves
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+)
+
+type MockReservesRepository struct {
+	mock.Mock
+}
+
+func (m *MockReservesRepository) CreateTree(ctx context.Context, root Root, leaves []Leaf) error {
+	args := m.Called(ctx, root, leaves)
+	return args.Error(0)
+}
+
+func (m *MockReservesRepository) GetLatestRoot(ctx context.Context, currency string) (Root, error) {
+	args := m.Called(ctx, currency)
+	return args.Get(0).(Root), args.Error(1)
+}
+
+func (m *MockReservesRepository) ListLatestRoots(ctx context.Context) ([]Root, error) {
+	args := m.Called(ctx)
+	return args.Get(0).([]Root), args.Error(1)
+}
+
+func (m *MockReservesRepository) ListLeaves(ctx context.Context, currency string, takenAt time.Time) ([]Leaf, error) {
+	args := m.Called(ctx, currency, takenAt)
+	return args.Get(0).([]Leaf), args.Error(1)
+}
+
+func TestReservesService_Publish(t *testing.T) {
+	ctx := context.Background()
+	takenAt := time.Date(2024, 3, 1, 0, 0, 0, 0, time.UTC)
+	balances := map[string]int64{"user1": 1500, "user2": 0, "user3": 250}
+
+	t.Run("success", func(t *testing.T) {
+		repo := new(MockReservesRepository)
+		service := NewReservesService(repo)
+		var stored []Leaf
+		repo.On("CreateTree", ctx, mock.AnythingOfType("Root"), mock.AnythingOfType("[]reserves.Leaf")).
+			Run(func(args mock.Arguments) { stored = args.Get(2).([]Leaf) }).
+			Return(nil)
+
+		root, err := service.Publish(ctx, "USD", takenAt, balances)
+
+		require.NoError(t, err)
+		assert.Equal(t, int64(1750), root.Total)
+		assert.Equal(t, 3, root.Wallets)
+		assert.Equal(t, takenAt, root.TakenAt)
+		require.Len(t, stored, 3)
+		assert.NotEqual(t, stored[0].Nonce, stored[1].Nonce)
+
+		tree, err := NewTree("USD", takenAt, stored)
+		require.NoError(t, err)
+		assert.Equal(t, root, tree.Root())
+	})
+
+	t.Run("negative balance", func(t *testing.T) {
+		repo := new(MockReservesRepository)
+		service := NewReservesService(repo)
+
+		_, err := service.Publish(ctx, "USD", takenAt, map[string]int64{"user1": -5})
+
+		assert.Equal(t, ErrInvalidLeaf, err)
+		repo.AssertNotCalled(t, "CreateTree", mock.Anything, mock.Anything, mock.Anything)
+	})
+
+	t.Run("already published", func(t *testing.T) {
+		repo := new(MockReservesRepository)
+		service := NewReservesService(repo)
+		repo.On("CreateTree", ctx, mock.Anything, mock.Anything).Return(ErrAlreadyPublished)
+
+		_, err := service.Publish(ctx, "USD", takenAt, balances)
+
+		assert.Equal(t, ErrAlreadyPublished, err)
+	})
+
+	t.Run("repository failure", func(t *testing.T) {
+		repo := new(MockReservesRepository)
+		service := NewReservesService(repo)
+		repo.On("CreateTree", ctx, mock.Anything, mock.Anything).Return(errors.New("connection reset"))
+
+		_, err := service.Publish(ctx, "USD", takenAt, balances)
+
+		assert.Equal(t, ErrDatabaseFailure, err)
+	})
+}
+
+func TestReservesService_Prove(t *testing.T) {
+	ctx := context.Background()
+	takenAt := time.Date(2024, 3, 1, 0, 0, 0, 0, time.UTC)
+	leaves := testLeaves(5)
+	tree, err := NewTree("USD", takenAt, leaves)
+	require.NoError(t, err)
+	root := tree.Root()
+
+	t.Run("success", func(t *testing.T) {
+		repo := new(MockReservesRepository)
+		service := NewReservesService(repo)
+		repo.On("GetLatestRoot", ctx, "USD").Return(root, nil)
+		repo.On("ListLeaves", ctx, "USD", takenAt).Return(leaves, nil)
+
+		proof, err := service.Prove(ctx, "user3", "USD")
+
+		require.NoError(t, err)
+		assert.Equal(t, "user3", proof.Leaf.UserID)
+		assert.True(t, proof.Verify())
+	})
+
+	t.Run("nothing published", func(t *testing.T) {
+		repo := new(MockReservesRepository)
+		service := NewReservesService(repo)
+		repo.On("GetLatestRoot", ctx, "USD").Return(Root{}, ErrRootNotFound)
+
+		_, err := service.Prove(ctx, "user3", "USD")
+
+		assert.Equal(t, ErrRootNotFound, err)
+	})
+
+	t.Run("wallet not included", func(t *testing.T) {
+		repo := new(MockReservesRepository)
+		service := NewReservesService(repo)
+		repo.On("GetLatestRoot", ctx, "USD").Return(root, nil)
+		repo.On("ListLeaves", ctx, "USD", takenAt).Return(leaves, nil)
+
+		_, err := service.Prove(ctx, "newcomer", "USD")
+
+		assert.Equal(t, ErrLeafNotFound, err)
+	})
+
+	t.Run("leaves do not match the root", func(t *testing.T) {
+		repo := new(MockReservesRepository)
+		service := NewReservesService(repo)
+		tampered := append([]Leaf(nil), leaves...)
+		tampered[0].Balance = 0
+		repo.On("GetLatestRoot", ctx, "USD").Return(root, nil)
+		repo.On("ListLeaves", ctx, "USD", takenAt).Return(tampered, nil)
+
+		_, err := service.Prove(ctx, "user3", "USD")
+
+		assert.Equal(t, ErrDatabaseFailure, err)
+	})
+}
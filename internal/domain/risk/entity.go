@@ -0,0 +1,71 @@
+package risk
+
+import (
+	"time"
+)
+
+// Decision is the outcome of assessing a request against the risk rules.
+type Decision string
+
+const (
+	DecisionAllow  Decision = "allow"  // Allow lets the request through.
+	DecisionReview Decision = "review" // Review lets it through only after an admin has looked at it where the operation supports that.
+	DecisionBlock  Decision = "block"  // Block rejects the request.
+)
+
+func (d Decision) Valid() bool {
+	return d == DecisionAllow || d == DecisionReview || d == DecisionBlock
+}
+
+// severity orders decisions so that the strictest one triggered wins.
+func (d Decision) severity() int {
+	switch d {
+	case DecisionReview:
+		return 1
+	case DecisionBlock:
+		return 2
+	}
+	return 0
+}
+
+// Operation is the kind of outgoing money movement being assessed.
+type Operation string
+
+const (
+	OperationWithdrawal Operation = "withdrawal"
+	OperationTransfer   Operation = "transfer"
+)
+
+func (o Operation) Valid() bool {
+	return o == OperationWithdrawal || o == OperationTransfer
+}
+
+// Request is a withdrawal or transfer about to be executed.
+type Request struct {
+	UserID         string    // UserID is the owner of the wallet the funds leave.
+	Operation      Operation // Operation is what the user is doing.
+	CounterpartyID string    // CounterpartyID is the recipient of a transfer; empty for withdrawals.
+	Destination    string    // Destination is where a withdrawal is paid out, if given; empty for transfers.
+	Amount         int64     // Amount is expressed as an integer in the smallest currency unit.
+	Currency       string    // Currency is the currency code of the amount.
+	At             time.Time // At is when the request was made.
+}
+
+// Assessment records the decision taken on a request and the rules that led to it.
+type Assessment struct {
+	ID             string    // ID is the unique assessment identifier.
+	UserID         string    // UserID is the owner of the wallet the funds leave.
+	Operation      Operation // Operation is what the user was doing.
+	CounterpartyID string    // CounterpartyID is the recipient of a transfer; empty for withdrawals.
+	Amount         int64     // Amount is expressed as an integer in the smallest currency unit.
+	Currency       string    // Currency is the currency code of the amount.
+	Decision       Decision  // Decision is the strictest decision of the triggered rules, or allow.
+	RuleIDs        []string  // RuleIDs are the triggered rules in the order they are declared.
+	CreatedAt      time.Time // CreatedAt is the timestamp when the request was assessed.
+}
+
+// Filter narrows ListAssessments; zero fields match everything.
+type Filter struct {
+	UserID   string
+	Decision Decision
+}
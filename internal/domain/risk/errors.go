@@ -0,0 +1,11 @@
+package risk
+
+import "errors"
+
+var (
+	ErrUnknownRuleKind = errors.New("unknown risk rule kind")
+	ErrInvalidRule     = errors.New("invalid risk rule")
+	ErrInvalidDecision = errors.New("invalid risk decision")
+	ErrBlocked         = errors.New("blocked by risk rules")
+	ErrDatabaseFailure = errors.New("database failure")
+)
@@ -0,0 +1,22 @@
+package risk
+
+import (
+	"context"
+	"time"
+
+	"exchange/internal/domain/transaction"
+)
+
+type RiskRepository interface {
+	// GetAvailableBalance returns the part of userID's balance not held for pending withdrawals.
+	GetAvailableBalance(ctx context.Context, userID string) (int64, error)
+
+	// ListTransactionsSince returns the transactions sent or received by userID and created
+	// at or after since, oldest first, leaving out failed and cancelled ones.
+	ListTransactionsSince(ctx context.Context, userID string, since time.Time) ([]transaction.Transaction, error)
+
+	CreateAssessment(ctx context.Context, a Assessment) error
+
+	// ListAssessments returns the assessments matching filter, newest first.
+	ListAssessments(ctx context.Context, filter Filter, limit, offset int) ([]Assessment, error)
+}
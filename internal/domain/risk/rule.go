@@ -0,0 +1,188 @@
+package risk
+
+import (
+	"time"
+
+	"exchange/internal/domain/transaction"
+)
+
+// Facts is what rules know about a request beyond the request itself.
+type Facts struct {
+	// Available is the part of the user's balance not held for pending withdrawals.
+	Available int64
+	// Recent are the user's transactions, sent or received, created within the longest
+	// lookback of the rules, oldest first. Failed and cancelled ones are left out.
+	Recent []transaction.Transaction
+}
+
+// Rule flags requests matching a pattern. New kinds of rules are added by implementing
+// Rule and registering a constructor in ruleKinds.
+type Rule interface {
+	// ID identifies the rule in assessments.
+	ID() string
+	// Decision is what the rule asks for when it matches.
+	Decision() Decision
+	// Lookback is how far back Facts.Recent must reach for the rule.
+	Lookback() time.Duration
+	// Matches reports whether req matches the rule.
+	Matches(req Request, facts Facts) bool
+}
+
+// Kind names a type of rule in the configuration.
+type Kind string
+
+const (
+	// KindNewCounterpartyVelocity matches more than Count transfers within Window to
+	// counterparties the user first sent funds to within Window, looking back Lookback
+	// for earlier transfers.
+	KindNewCounterpartyVelocity Kind = "new_counterparty_velocity"
+	// KindDrainAfterDeposit matches withdrawals of more than Ratio of the available
+	// balance within Window of a deposit of at least MinAmount.
+	KindDrainAfterDeposit Kind = "drain_after_deposit"
+	// KindRoundTrip matches transfers to a counterparty who has sent the user funds at
+	// least Count times within Window.
+	KindRoundTrip Kind = "round_trip"
+)
+
+// RuleConfig declares a rule; which fields matter depends on Kind.
+type RuleConfig struct {
+	ID        string
+	Kind      Kind
+	Decision  Decision
+	Window    time.Duration
+	Lookback  time.Duration
+	Count     int
+	Ratio     float64
+	MinAmount int64
+}
+
+var ruleKinds = map[Kind]func(c RuleConfig) (Rule, error){
+	KindNewCounterpartyVelocity: newCounterpartyVelocity,
+	KindDrainAfterDeposit:       newDrainAfterDeposit,
+	KindRoundTrip:               newRoundTrip,
+}
+
+// NewRule builds the rule declared by c.
+func NewRule(c RuleConfig) (Rule, error) {
+	build, ok := ruleKinds[c.Kind]
+	if !ok {
+		return nil, ErrUnknownRuleKind
+	}
+	if c.ID == "" || !c.Decision.Valid() || c.Decision == DecisionAllow || c.Window <= 0 {
+		return nil, ErrInvalidRule
+	}
+	return build(c)
+}
+
+// baseRule implements the parts of Rule every kind shares.
+type baseRule struct {
+	id       string
+	decision Decision
+	window   time.Duration
+}
+
+func (r baseRule) ID() string              { return r.id }
+func (r baseRule) Decision() Decision      { return r.decision }
+func (r baseRule) Lookback() time.Duration { return r.window }
+
+type counterpartyVelocity struct {
+	baseRule
+	lookback time.Duration
+	count    int
+}
+
+func newCounterpartyVelocity(c RuleConfig) (Rule, error) {
+	if c.Count <= 0 || c.Lookback < c.Window {
+		return nil, ErrInvalidRule
+	}
+	return counterpartyVelocity{baseRule{c.ID, c.Decision, c.Window}, c.Lookback, c.Count}, nil
+}
+
+func (r counterpartyVelocity) Lookback() time.Duration { return r.lookback }
+
+func (r counterpartyVelocity) Matches(req Request, facts Facts) bool {
+	if req.Operation != OperationTransfer {
+		return false
+	}
+	since := req.At.Add(-r.window)
+
+	// Recent is oldest first, so the first transfer seen to a counterparty is the first
+	// one within the lookback.
+	first := map[string]time.Time{}
+	var inWindow []string
+	for _, tx := range facts.Recent {
+		if tx.Type != transaction.TransactionTypeTransfer || tx.FromUserID != req.UserID {
+			continue
+		}
+		if _, ok := first[tx.ToUserID]; !ok {
+			first[tx.ToUserID] = tx.CreatedAt
+		}
+		if !tx.CreatedAt.Before(since) {
+			inWindow = append(inWindow, tx.ToUserID)
+		}
+	}
+	if _, ok := first[req.CounterpartyID]; !ok {
+		first[req.CounterpartyID] = req.At
+	}
+	inWindow = append(inWindow, req.CounterpartyID)
+
+	n := 0
+	for _, counterparty := range inWindow {
+		if !first[counterparty].Before(since) {
+			n++
+		}
+	}
+	return n > r.count
+}
+
+type drainAfterDeposit struct {
+	baseRule
+	ratio     float64
+	minAmount int64
+}
+
+func newDrainAfterDeposit(c RuleConfig) (Rule, error) {
+	if c.Ratio <= 0 || c.Ratio > 1 || c.MinAmount < 0 {
+		return nil, ErrInvalidRule
+	}
+	return drainAfterDeposit{baseRule{c.ID, c.Decision, c.Window}, c.Ratio, c.MinAmount}, nil
+}
+
+func (r drainAfterDeposit) Matches(req Request, facts Facts) bool {
+	if req.Operation != OperationWithdrawal || float64(req.Amount) <= r.ratio*float64(facts.Available) {
+		return false
+	}
+	since := req.At.Add(-r.window)
+	for _, tx := range facts.Recent {
+		if tx.Type == transaction.TransactionTypeDeposit && tx.ToUserID == req.UserID && tx.Amount >= r.minAmount && !tx.CreatedAt.Before(since) {
+			return true
+		}
+	}
+	return false
+}
+
+type roundTrip struct {
+	baseRule
+	count int
+}
+
+func newRoundTrip(c RuleConfig) (Rule, error) {
+	if c.Count <= 0 {
+		return nil, ErrInvalidRule
+	}
+	return roundTrip{baseRule{c.ID, c.Decision, c.Window}, c.Count}, nil
+}
+
+func (r roundTrip) Matches(req Request, facts Facts) bool {
+	if req.Operation != OperationTransfer {
+		return false
+	}
+	since := req.At.Add(-r.window)
+	n := 0
+	for _, tx := range facts.Recent {
+		if tx.Type == transaction.TransactionTypeTransfer && tx.FromUserID == req.CounterpartyID && tx.ToUserID == req.UserID && !tx.CreatedAt.Before(since) {
+			n++
+		}
+	}
+	return n >= r.count
+}
@@ -0,0 +1,84 @@
+package risk
+
+import (
+	"testing"
+	"time"
+
+	"exchange/internal/domain/transaction"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestNewRule(t *testing.T) {
+	r, err := NewRule(RuleConfig{ID: "round-trip", Kind: KindRoundTrip, Decision: DecisionBlock, Window: time.Hour, Count: 1})
+	require.NoError(t, err)
+	assert.Equal(t, "round-trip", r.ID())
+	assert.Equal(t, DecisionBlock, r.Decision())
+	assert.Equal(t, time.Hour, r.Lookback())
+
+	tests := []struct {
+		name string
+		c    RuleConfig
+		err  error
+	}{
+		{"unknown kind", RuleConfig{ID: "r", Kind: "moon_phase", Decision: DecisionBlock, Window: time.Hour}, ErrUnknownRuleKind},
+		{"missing id", RuleConfig{Kind: KindRoundTrip, Decision: DecisionBlock, Window: time.Hour, Count: 1}, ErrInvalidRule},
+		{"allow decision", RuleConfig{ID: "r", Kind: KindRoundTrip, Decision: DecisionAllow, Window: time.Hour, Count: 1}, ErrInvalidRule},
+		{"missing window", RuleConfig{ID: "r", Kind: KindRoundTrip, Decision: DecisionBlock, Count: 1}, ErrInvalidRule},
+		{"lookback shorter than window", RuleConfig{ID: "r", Kind: KindNewCounterpartyVelocity, Decision: DecisionReview, Window: time.Hour, Lookback: time.Minute, Count: 3}, ErrInvalidRule},
+		{"ratio above one", RuleConfig{ID: "r", Kind: KindDrainAfterDeposit, Decision: DecisionReview, Window: time.Hour, Ratio: 1.5}, ErrInvalidRule},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			_, err := NewRule(tt.c)
+			assert.ErrorIs(t, err, tt.err)
+		})
+	}
+}
+
+func TestRules_Matches(t *testing.T) {
+	now := time.Now()
+	transfer := func(from, to string, ago time.Duration) transaction.Transaction {
+		return transaction.Transaction{FromUserID: from, ToUserID: to, Amount: 100, Type: transaction.TransactionTypeTransfer, CreatedAt: now.Add(-ago)}
+	}
+	deposit := func(amount int64, ago time.Duration) transaction.Transaction {
+		return transaction.Transaction{ToUserID: "user1", Amount: amount, Type: transaction.TransactionTypeDeposit, CreatedAt: now.Add(-ago)}
+	}
+	toUser := func(counterparty string) Request {
+		return Request{UserID: "user1", Operation: OperationTransfer, CounterpartyID: counterparty, Amount: 100, At: now}
+	}
+	withdrawal := Request{UserID: "user1", Operation: OperationWithdrawal, Amount: 950, At: now}
+
+	velocity, err := NewRule(RuleConfig{ID: "velocity", Kind: KindNewCounterpartyVelocity, Decision: DecisionReview, Window: time.Hour, Lookback: 30 * 24 * time.Hour, Count: 2})
+	require.NoError(t, err)
+	drain, err := NewRule(RuleConfig{ID: "drain", Kind: KindDrainAfterDeposit, Decision: DecisionReview, Window: 24 * time.Hour, Ratio: 0.9, MinAmount: 500})
+	require.NoError(t, err)
+	trip, err := NewRule(RuleConfig{ID: "trip", Kind: KindRoundTrip, Decision: DecisionBlock, Window: 24 * time.Hour, Count: 1})
+	require.NoError(t, err)
+
+	tests := []struct {
+		name  string
+		rule  Rule
+		req   Request
+		facts Facts
+		want  bool
+	}{
+		{"third new counterparty within the window", velocity, toUser("c"), Facts{Recent: []transaction.Transaction{transfer("user1", "a", time.Minute), transfer("user1", "b", time.Minute)}}, true},
+		{"known counterparties", velocity, toUser("c"), Facts{Recent: []transaction.Transaction{transfer("user1", "a", 48*time.Hour), transfer("user1", "b", 48*time.Hour), transfer("user1", "a", time.Minute), transfer("user1", "b", time.Minute)}}, false},
+		{"new counterparties outside the window", velocity, toUser("c"), Facts{Recent: []transaction.Transaction{transfer("user1", "a", 2*time.Hour), transfer("user1", "b", 2*time.Hour)}}, false},
+		{"velocity ignores withdrawals", velocity, withdrawal, Facts{Recent: []transaction.Transaction{transfer("user1", "a", time.Minute), transfer("user1", "b", time.Minute)}}, false},
+		{"drain after a large deposit", drain, withdrawal, Facts{Available: 1000, Recent: []transaction.Transaction{deposit(800, time.Hour)}}, true},
+		{"drain after a small deposit", drain, withdrawal, Facts{Available: 1000, Recent: []transaction.Transaction{deposit(100, time.Hour)}}, false},
+		{"partial withdrawal after a deposit", drain, withdrawal, Facts{Available: 2000, Recent: []transaction.Transaction{deposit(800, time.Hour)}}, false},
+		{"drain after an old deposit", drain, withdrawal, Facts{Available: 1000, Recent: []transaction.Transaction{deposit(800, 48*time.Hour)}}, false},
+		{"round trip", trip, toUser("a"), Facts{Recent: []transaction.Transaction{transfer("a", "user1", time.Hour)}}, true},
+		{"transfer to another counterparty", trip, toUser("b"), Facts{Recent: []transaction.Transaction{transfer("a", "user1", time.Hour)}}, false},
+		{"same direction twice", trip, toUser("a"), Facts{Recent: []transaction.Transaction{transfer("user1", "a", time.Hour)}}, false},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			assert.Equal(t, tt.want, tt.rule.Matches(tt.req, tt.facts))
+		})
+	}
+}
@@ -0,0 +1,90 @@
+package risk
+
+import (
+	"context"
+	"time"
+
+	"github.com/gofrs/uuid"
+)
+
+type RiskServiceInterface interface {
+	Assess(ctx context.Context, req Request) (Assessment, error)
+	ListAssessments(ctx context.Context, filter Filter, limit, offset int) ([]Assessment, error)
+}
+
+type RiskService struct {
+	repository RiskRepository
+	rules      []Rule
+	lookback   time.Duration
+}
+
+func NewRiskService(repo RiskRepository, rules []Rule) *RiskService {
+	var lookback time.Duration
+	for _, r := range rules {
+		lookback = max(lookback, r.Lookback())
+	}
+	return &RiskService{
+		repository: repo,
+		rules:      rules,
+		lookback:   lookback,
+	}
+}
+
+// Assess evaluates every rule against req and the user's recent transactions and records
+// the resulting assessment. The decision is the strictest one of the matching rules, or
+// allow when none match.
+func (s *RiskService) Assess(ctx context.Context, req Request) (Assessment, error) {
+	if req.At.IsZero() {
+		req.At = time.Now()
+	}
+	id, err := uuid.NewV7()
+	if err != nil {
+		return Assessment{}, err
+	}
+	a := Assessment{
+		ID:             id.String(),
+		UserID:         req.UserID,
+		Operation:      req.Operation,
+		CounterpartyID: req.CounterpartyID,
+		Amount:         req.Amount,
+		Currency:       req.Currency,
+		Decision:       DecisionAllow,
+		CreatedAt:      req.At,
+	}
+
+	if len(s.rules) > 0 {
+		var facts Facts
+		if facts.Available, err = s.repository.GetAvailableBalance(ctx, req.UserID); err != nil {
+			return Assessment{}, ErrDatabaseFailure
+		}
+		if facts.Recent, err = s.repository.ListTransactionsSince(ctx, req.UserID, req.At.Add(-s.lookback)); err != nil {
+			return Assessment{}, ErrDatabaseFailure
+		}
+
+		for _, r := range s.rules {
+			if !r.Matches(req, facts) {
+				continue
+			}
+			a.RuleIDs = append(a.RuleIDs, r.ID())
+			if r.Decision().severity() > a.Decision.severity() {
+				a.Decision = r.Decision()
+			}
+		}
+	}
+
+	if err := s.repository.CreateAssessment(ctx, a); err != nil {
+		return Assessment{}, ErrDatabaseFailure
+	}
+	return a, nil
+}
+
+func (s *RiskService) ListAssessments(ctx context.Context, filter Filter, limit, offset int) ([]Assessment, error) {
+	if filter.Decision != "" && !filter.Decision.Valid() {
+		return nil, ErrInvalidDecision
+	}
+	assessments, err := s.repository.ListAssessments(ctx, filter, limit, offset)
+	if err != nil {
+		return nil, ErrDatabaseFailure
+	}
+	return assessments, nil
+}
@@ -0,0 +1,119 @@
+package risk
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"exchange/internal/domain/transaction"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+)
+
+type MockRiskRepository struct {
+	mock.Mock
+}
+
+func (m *MockRiskRepository) GetAvailableBalance(ctx context.Context, userID string) (int64, error) {
+	args := m.Called(ctx, userID)
+	return args.Get(0).(int64), args.Error(1)
+}
+
+func (m *MockRiskRepository) ListTransactionsSince(ctx context.Context, userID string, since time.Time) ([]transaction.Transaction, error) {
+	args := m.Called(ctx, userID, since)
+	return args.Get(0).([]transaction.Transaction), args.Error(1)
+}
+
+func (m *MockRiskRepository) CreateAssessment(ctx context.Context, a Assessment) error {
+	args := m.Called(ctx, a)
+	return args.Error(0)
+}
+
+func (m *MockRiskRepository) ListAssessments(ctx context.Context, filter Filter, limit, offset int) ([]Assessment, error) {
+	args := m.Called(ctx, filter, limit, offset)
+	return args.Get(0).([]Assessment), args.Error(1)
+}
+
+func TestRiskService_Assess(t *testing.T) {
+	ctx := context.Background()
+	now := time.Now()
+	review, err := NewRule(RuleConfig{ID: "drain", Kind: KindDrainAfterDeposit, Decision: DecisionReview, Window: time.Hour, Ratio: 0.5})
+	require.NoError(t, err)
+	block, err := NewRule(RuleConfig{ID: "trip", Kind: KindRoundTrip, Decision: DecisionBlock, Window: 24 * time.Hour, Count: 1})
+	require.NoError(t, err)
+	service := NewRiskService(nil, []Rule{review, block})
+	assert.Equal(t, 24*time.Hour, service.lookback, "the longest lookback of the rules")
+
+	t.Run("no rule matches", func(t *testing.T) {
+		mockRepo := new(MockRiskRepository)
+		mockRepo.On("GetAvailableBalance", ctx, "user1").Return(int64(1000), nil)
+		mockRepo.On("ListTransactionsSince", ctx, "user1", now.Add(-24*time.Hour)).Return([]transaction.Transaction(nil), nil)
+		mockRepo.On("CreateAssessment", ctx, mock.AnythingOfType("Assessment")).Return(nil)
+
+		a, err := NewRiskService(mockRepo, []Rule{review, block}).Assess(ctx, Request{UserID: "user1", Operation: OperationTransfer, CounterpartyID: "user2", Amount: 100, At: now})
+
+		require.NoError(t, err)
+		assert.NotEmpty(t, a.ID)
+		assert.Equal(t, DecisionAllow, a.Decision)
+		assert.Empty(t, a.RuleIDs)
+		mockRepo.AssertExpectations(t)
+	})
+
+	t.Run("strictest decision wins", func(t *testing.T) {
+		mockRepo := new(MockRiskRepository)
+		mockRepo.On("GetAvailableBalance", ctx, "user1").Return(int64(1000), nil)
+		mockRepo.On("ListTransactionsSince", ctx, "user1", now.Add(-24*time.Hour)).Return([]transaction.Transaction{
+			{FromUserID: "user2", ToUserID: "user1", Amount: 100, Type: transaction.TransactionTypeTransfer, CreatedAt: now.Add(-time.Minute)},
+			{ToUserID: "user1", Amount: 900, Type: transaction.TransactionTypeDeposit, CreatedAt: now.Add(-time.Minute)},
+		}, nil)
+		var recorded Assessment
+		mockRepo.On("CreateAssessment", ctx, mock.AnythingOfType("Assessment")).Run(func(args mock.Arguments) { recorded = args.Get(1).(Assessment) }).Return(nil)
+
+		a, err := NewRiskService(mockRepo, []Rule{block, review}).Assess(ctx, Request{UserID: "user1", Operation: OperationTransfer, CounterpartyID: "user2", Amount: 100, At: now})
+
+		require.NoError(t, err)
+		assert.Equal(t, DecisionBlock, a.Decision)
+		assert.Equal(t, []string{"trip"}, a.RuleIDs)
+		assert.Equal(t, a, recorded)
+	})
+
+	t.Run("without rules", func(t *testing.T) {
+		mockRepo := new(MockRiskRepository)
+		mockRepo.On("CreateAssessment", ctx, mock.AnythingOfType("Assessment")).Return(nil)
+
+		a, err := NewRiskService(mockRepo, nil).Assess(ctx, Request{UserID: "user1", Operation: OperationWithdrawal, Amount: 100})
+
+		require.NoError(t, err)
+		assert.Equal(t, DecisionAllow, a.Decision)
+		assert.False(t, a.CreatedAt.IsZero())
+		mockRepo.AssertNotCalled(t, "ListTransactionsSince", mock.Anything, mock.Anything, mock.Anything)
+	})
+
+	t.Run("repository failure", func(t *testing.T) {
+		mockRepo := new(MockRiskRepository)
+		mockRepo.On("GetAvailableBalance", ctx, "user1").Return(int64(0), errors.New("connection reset"))
+
+		_, err := NewRiskService(mockRepo, []Rule{review}).Assess(ctx, Request{UserID: "user1", Operation: OperationWithdrawal, Amount: 100, At: now})
+
+		assert.ErrorIs(t, err, ErrDatabaseFailure)
+		mockRepo.AssertNotCalled(t, "CreateAssessment", mock.Anything, mock.Anything)
+	})
+}
+
+func TestRiskService_ListAssessments(t *testing.T) {
+	ctx := context.Background()
+	mockRepo := new(MockRiskRepository)
+	service := NewRiskService(mockRepo, nil)
+	filter := Filter{UserID: "user1", Decision: DecisionBlock}
+	mockRepo.On("ListAssessments", ctx, filter, 10, 0).Return([]Assessment{{ID: "ra1"}}, nil)
+
+	assessments, err := service.ListAssessments(ctx, filter, 10, 0)
+	assert.NoError(t, err)
+	assert.Len(t, assessments, 1)
+
+	_, err = service.ListAssessments(ctx, Filter{Decision: "maybe"}, 10, 0)
+	assert.ErrorIs(t, err, ErrInvalidDecision)
+}
@@ -0,0 +1,109 @@
+package sanctions
+
+import (
+	"strings"
+	"time"
+)
+
+// Entry is a person or organisation on the sanctions list.
+type Entry struct {
+	ID          string   // ID is the entry's identifier on the list.
+	Name        string   // Name is the primary name of the sanctioned party.
+	Aliases     []string // Aliases are other names the party is known by.
+	Identifiers []string // Identifiers are account numbers or addresses matched exactly, such as a crypto address.
+	Program     string   // Program is the sanctions programme or list the entry comes from.
+}
+
+// SubjectType tells what was screened.
+type SubjectType string
+
+const (
+	SubjectUser        SubjectType = "user"        // User is a registered user, screened by name.
+	SubjectDestination SubjectType = "destination" // Destination is where a withdrawal is paid out.
+)
+
+func (t SubjectType) Valid() bool {
+	return t == SubjectUser || t == SubjectDestination
+}
+
+// Subject is a party to an operation screened against the list.
+type Subject struct {
+	Type SubjectType
+	ID   string // ID is the user ID, or the destination itself.
+	Name string // Name is what is compared with the names on the list.
+}
+
+// Match is the best hit of a subject on the list.
+type Match struct {
+	EntryID   string
+	EntryName string
+	Score     float64 // Score is how similar the names are, from 0 to 1; an identifier match scores 1.
+}
+
+// CaseStatus tells whether a compliance case still blocks its subject.
+type CaseStatus string
+
+const (
+	CaseOpen    CaseStatus = "open"    // Open blocks the subject until an admin looks at the case.
+	CaseCleared CaseStatus = "cleared" // Cleared marks the hit as a false positive.
+)
+
+func (s CaseStatus) Valid() bool {
+	return s == CaseOpen || s == CaseCleared
+}
+
+// Case records a hit of a subject on the sanctions list. While it is open, operations
+// involving the subject are blocked; once cleared, the same subject no longer hits the
+// same entry.
+type Case struct {
+	ID          string      // ID is the unique case identifier.
+	SubjectType SubjectType // SubjectType tells what hit the list.
+	SubjectID   string      // SubjectID is the user ID, or the destination.
+	SubjectName string      // SubjectName is the name that matched.
+	EntryID     string      // EntryID is the matched entry on the list.
+	EntryName   string      // EntryName is the primary name of the matched entry.
+	Score       float64     // Score is how similar the names are, from 0 to 1.
+	Operation   string      // Operation is what the subject was blocked from, such as "transfer".
+	Status      CaseStatus  // Status is open until an admin clears the case.
+	Reason      string      // Reason is why the case was cleared.
+	ClearedBy   string      // ClearedBy is the admin who cleared the case.
+	CreatedAt   time.Time   // CreatedAt is when the hit happened.
+	ClearedAt   *time.Time  // ClearedAt is when the case was cleared.
+}
+
+func NewCase(id string, subject Subject, m Match, operation string) Case {
+	return Case{
+		ID:          id,
+		SubjectType: subject.Type,
+		SubjectID:   subject.ID,
+		SubjectName: subject.Name,
+		EntryID:     m.EntryID,
+		EntryName:   m.EntryName,
+		Score:       m.Score,
+		Operation:   operation,
+		Status:      CaseOpen,
+		CreatedAt:   time.Now(),
+	}
+}
+
+// Clear marks an open case as a false positive; it needs a reason.
+func (c *Case) Clear(adminID, reason string) error {
+	if c.Status != CaseOpen {
+		return ErrCaseNotOpen
+	}
+	if strings.TrimSpace(reason) == "" {
+		return ErrReasonRequired
+	}
+	now := time.Now()
+	c.Status = CaseCleared
+	c.Reason = reason
+	c.ClearedBy = adminID
+	c.ClearedAt = &now
+	return nil
+}
+
+// Filter narrows ListCases; zero fields match everything.
+type Filter struct {
+	SubjectID string
+	Status    CaseStatus
+}
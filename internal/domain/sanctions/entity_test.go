@@ -0,0 +1,21 @@
+package sanctions
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestCase_Clear(t *testing.T) {
+	c := NewCase("case1", Subject{Type: SubjectUser, ID: "user1", Name: "Jon Doe"}, Match{EntryID: "sdn-1", EntryName: "John Doe", Score: 0.9}, "transfer")
+	assert.Equal(t, CaseOpen, c.Status)
+
+	assert.ErrorIs(t, c.Clear("admin", " "), ErrReasonRequired)
+	require.NoError(t, c.Clear("admin", "different date of birth"))
+	assert.Equal(t, CaseCleared, c.Status)
+	assert.Equal(t, "admin", c.ClearedBy)
+	assert.NotNil(t, c.ClearedAt)
+
+	assert.ErrorIs(t, c.Clear("admin", "again"), ErrCaseNotOpen)
+}
@@ -0,0 +1,15 @@
+package sanctions
+
+import "errors"
+
+var (
+	ErrInvalidEntry     = errors.New("invalid sanctions list entry")
+	ErrDuplicateEntry   = errors.New("duplicate sanctions list entry")
+	ErrInvalidThreshold = errors.New("sanctions match threshold must be between 0 and 1")
+	ErrInvalidStatus    = errors.New("invalid compliance case status")
+	ErrBlocked          = errors.New("blocked by sanctions screening")
+	ErrReasonRequired   = errors.New("clearing a compliance case requires a reason")
+	ErrCaseNotFound     = errors.New("compliance case not found")
+	ErrCaseNotOpen      = errors.New("compliance case is not open")
+	ErrDatabaseFailure  = errors.New("database failure")
+)
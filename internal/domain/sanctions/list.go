@@ -0,0 +1,112 @@
+package sanctions
+
+import (
+	"slices"
+	"strings"
+	"unicode"
+)
+
+// DefaultThreshold is the name similarity from which a subject hits an entry when the
+// configuration does not set one.
+const DefaultThreshold = 0.85
+
+// List is the sanctions list subjects are screened against.
+type List struct {
+	entries   []Entry
+	threshold float64
+	names     [][]string // names holds the normalized name and aliases of each entry.
+}
+
+// NewList validates entries and returns a list on which a name hits an entry when it is
+// at least threshold similar to the entry's name or one of its aliases.
+func NewList(entries []Entry, threshold float64) (*List, error) {
+	if threshold <= 0 || threshold > 1 {
+		return nil, ErrInvalidThreshold
+	}
+	l := &List{
+		entries:   entries,
+		threshold: threshold,
+		names:     make([][]string, len(entries)),
+	}
+	seen := make(map[string]bool, len(entries))
+	for i, e := range entries {
+		if strings.TrimSpace(e.ID) == "" || normalize(e.Name) == "" {
+			return nil, ErrInvalidEntry
+		}
+		if seen[e.ID] {
+			return nil, ErrDuplicateEntry
+		}
+		seen[e.ID] = true
+		for _, name := range append([]string{e.Name}, e.Aliases...) {
+			if n := normalize(name); n != "" {
+				l.names[i] = append(l.names[i], n)
+			}
+		}
+	}
+	return l, nil
+}
+
+// Match returns the entry subject resembles most, if it hits any. Destinations also hit
+// an entry holding the same identifier, ignoring case and surrounding spaces.
+func (l *List) Match(subject Subject) (Match, bool) {
+	name := normalize(subject.Name)
+	var best Match
+	for i, e := range l.entries {
+		if subject.Type == SubjectDestination {
+			for _, identifier := range e.Identifiers {
+				if strings.EqualFold(strings.TrimSpace(identifier), strings.TrimSpace(subject.Name)) {
+					return Match{EntryID: e.ID, EntryName: e.Name, Score: 1}, true
+				}
+			}
+		}
+		if name == "" {
+			continue
+		}
+		for _, n := range l.names[i] {
+			if score := similarity(name, n); score > best.Score {
+				best = Match{EntryID: e.ID, EntryName: e.Name, Score: score}
+			}
+		}
+	}
+	return best, best.Score >= l.threshold
+}
+
+// normalize lower-cases name, drops punctuation and sorts its words, so that
+// "Doe, John" and "john doe" compare equal.
+func normalize(name string) string {
+	words := strings.FieldsFunc(strings.ToLower(name), func(r rune) bool {
+		return !unicode.IsLetter(r) && !unicode.IsDigit(r)
+	})
+	slices.Sort(words)
+	return strings.Join(words, " ")
+}
+
+// similarity is 1 minus the edit distance between a and b relative to the longer one.
+func similarity(a, b string) float64 {
+	ra, rb := []rune(a), []rune(b)
+	longest := max(len(ra), len(rb))
+	if longest == 0 {
+		return 1
+	}
+	return 1 - float64(levenshtein(ra, rb))/float64(longest)
+}
+
+func levenshtein(a, b []rune) int {
+	prev := make([]int, len(b)+1)
+	curr := make([]int, len(b)+1)
+	for j := range prev {
+		prev[j] = j
+	}
+	for i := 1; i <= len(a); i++ {
+		curr[0] = i
+		for j := 1; j <= len(b); j++ {
+			cost := 1
+			if a[i-1] == b[j-1] {
+				cost = 0
+			}
+			curr[j] = min(prev[j]+1, curr[j-1]+1, prev[j-1]+cost)
+		}
+		prev, curr = curr, prev
+	}
+	return prev[len(b)]
+}
@@ -0,0 +1,52 @@
+package sanctions
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestNewList(t *testing.T) {
+	_, err := NewList(nil, 0)
+	assert.ErrorIs(t, err, ErrInvalidThreshold)
+	_, err = NewList(nil, 1.5)
+	assert.ErrorIs(t, err, ErrInvalidThreshold)
+	_, err = NewList([]Entry{{ID: "sdn-1", Name: " - "}}, DefaultThreshold)
+	assert.ErrorIs(t, err, ErrInvalidEntry)
+	_, err = NewList([]Entry{{ID: "sdn-1", Name: "John Doe"}, {ID: "sdn-1", Name: "Jane Doe"}}, DefaultThreshold)
+	assert.ErrorIs(t, err, ErrDuplicateEntry)
+}
+
+func TestList_Match(t *testing.T) {
+	list, err := NewList([]Entry{
+		{ID: "sdn-1", Name: "Ivan Petrovich Sidorov", Aliases: []string{"Vanya Sidorov"}},
+		{ID: "sdn-2", Name: "Acme Trading LLC", Identifiers: []string{"0xABC123"}},
+	}, DefaultThreshold)
+	require.NoError(t, err)
+
+	tests := []struct {
+		name    string
+		subject Subject
+		entryID string
+		hit     bool
+	}{
+		{"exact name", Subject{Type: SubjectUser, Name: "Ivan Petrovich Sidorov"}, "sdn-1", true},
+		{"reordered with punctuation", Subject{Type: SubjectUser, Name: "SIDOROV, Ivan Petrovich"}, "sdn-1", true},
+		{"misspelled", Subject{Type: SubjectUser, Name: "Ivan Petrovich Sidorow"}, "sdn-1", true},
+		{"alias", Subject{Type: SubjectUser, Name: "vanya sidorov"}, "sdn-1", true},
+		{"different person", Subject{Type: SubjectUser, Name: "Ivan Smith"}, "", false},
+		{"destination name", Subject{Type: SubjectDestination, Name: "ACME Trading, LLC"}, "sdn-2", true},
+		{"destination identifier", Subject{Type: SubjectDestination, Name: " 0xabc123 "}, "sdn-2", true},
+		{"identifiers only match destinations", Subject{Type: SubjectUser, Name: "0xabc123"}, "", false},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			m, ok := list.Match(tt.subject)
+			assert.Equal(t, tt.hit, ok)
+			if tt.hit {
+				assert.Equal(t, tt.entryID, m.EntryID)
+			}
+		})
+	}
+}
@@ -0,0 +1,18 @@
+package sanctions
+
+import "context"
+
+type SanctionsRepository interface {
+	CreateCase(ctx context.Context, c Case) error
+	GetCaseByID(ctx context.Context, id string) (Case, error)
+
+	// FindCase returns the latest case of the subject hitting entryID, or ErrCaseNotFound.
+	FindCase(ctx context.Context, subjectType SubjectType, subjectID, entryID string) (Case, error)
+
+	// ListCases returns the cases matching filter, newest first.
+	ListCases(ctx context.Context, filter Filter, limit, offset int) ([]Case, error)
+
+	// ClearCase stores a cleared case, or returns ErrCaseNotOpen if it was cleared in the
+	// meantime.
+	ClearCase(ctx context.Context, c Case) error
+}
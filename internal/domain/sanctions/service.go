@@ -0,0 +1,104 @@
+package sanctions
+
+import (
+	"context"
+	"errors"
+
+	"github.com/gofrs/uuid"
+)
+
+type SanctionsServiceInterface interface {
+	Screen(ctx context.Context, operation string, subjects ...Subject) error
+	GetCase(ctx context.Context, id string) (Case, error)
+	ListCases(ctx context.Context, filter Filter, limit, offset int) ([]Case, error)
+	ClearCase(ctx context.Context, id, adminID, reason string) (Case, error)
+}
+
+type SanctionsService struct {
+	repository SanctionsRepository
+	list       *List
+}
+
+func NewSanctionsService(repo SanctionsRepository, list *List) *SanctionsService {
+	return &SanctionsService{
+		repository: repo,
+		list:       list,
+	}
+}
+
+// Screen checks every subject of operation against the list and returns ErrBlocked when
+// any of them hits an entry it has not been cleared for. A new hit opens a compliance
+// case; a subject whose case is still open is blocked without opening another one.
+func (s *SanctionsService) Screen(ctx context.Context, operation string, subjects ...Subject) error {
+	blocked := false
+	for _, subject := range subjects {
+		m, ok := s.list.Match(subject)
+		if !ok {
+			continue
+		}
+		c, err := s.repository.FindCase(ctx, subject.Type, subject.ID, m.EntryID)
+		switch {
+		case err == nil && c.Status == CaseCleared:
+			continue
+		case err == nil:
+			blocked = true
+			continue
+		case !errors.Is(err, ErrCaseNotFound):
+			return ErrDatabaseFailure
+		}
+
+		id, err := uuid.NewV7()
+		if err != nil {
+			return err
+		}
+		if err := s.repository.CreateCase(ctx, NewCase(id.String(), subject, m, operation)); err != nil {
+			return ErrDatabaseFailure
+		}
+		blocked = true
+	}
+	if blocked {
+		return ErrBlocked
+	}
+	return nil
+}
+
+func (s *SanctionsService) GetCase(ctx context.Context, id string) (Case, error) {
+	c, err := s.repository.GetCaseByID(ctx, id)
+	if err != nil {
+		if errors.Is(err, ErrCaseNotFound) {
+			return Case{}, ErrCaseNotFound
+		}
+		return Case{}, ErrDatabaseFailure
+	}
+	return c, nil
+}
+
+func (s *SanctionsService) ListCases(ctx context.Context, filter Filter, limit, offset int) ([]Case, error) {
+	if filter.Status != "" && !filter.Status.Valid() {
+		return nil, ErrInvalidStatus
+	}
+	cases, err := s.repository.ListCases(ctx, filter, limit, offset)
+	if err != nil {
+		return nil, ErrDatabaseFailure
+	}
+	return cases, nil
+}
+
+// ClearCase marks an open case as a false positive, so its subject no longer hits the
+// same entry.
+func (s *SanctionsService) ClearCase(ctx context.Context, id, adminID, reason string) (Case, error) {
+	c, err := s.GetCase(ctx, id)
+	if err != nil {
+		return Case{}, err
+	}
+	if err := c.Clear(adminID, reason); err != nil {
+		return Case{}, err
+	}
+	if err := s.repository.ClearCase(ctx, c); err != nil {
+		if errors.Is(err, ErrCaseNotOpen) {
+			return Case{}, ErrCaseNotOpen
+		}
+		return Case{}, ErrDatabaseFailure
+	}
+	return c, nil
+}
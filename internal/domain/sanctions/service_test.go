@@ -0,0 +1,127 @@
+package sanctions
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+)
+
+type MockSanctionsRepository struct {
+	mock.Mock
+}
+
+func (m *MockSanctionsRepository) CreateCase(ctx context.Context, c Case) error {
+	args := m.Called(ctx, c)
+	return args.Error(0)
+}
+
+func (m *MockSanctionsRepository) GetCaseByID(ctx context.Context, id string) (Case, error) {
+	args := m.Called(ctx, id)
+	return args.Get(0).(Case), args.Error(1)
+}
+
+func (m *MockSanctionsRepository) FindCase(ctx context.Context, subjectType SubjectType, subjectID, entryID string) (Case, error) {
+	args := m.Called(ctx, subjectType, subjectID, entryID)
+	return args.Get(0).(Case), args.Error(1)
+}
+
+func (m *MockSanctionsRepository) ListCases(ctx context.Context, filter Filter, limit, offset int) ([]Case, error) {
+	args := m.Called(ctx, filter, limit, offset)
+	return args.Get(0).([]Case), args.Error(1)
+}
+
+func (m *MockSanctionsRepository) ClearCase(ctx context.Context, c Case) error {
+	args := m.Called(ctx, c)
+	return args.Error(0)
+}
+
+func newTestService(t *testing.T) (*SanctionsService, *MockSanctionsRepository) {
+	list, err := NewList([]Entry{{ID: "sdn-1", Name: "John Doe"}}, DefaultThreshold)
+	require.NoError(t, err)
+	repo := new(MockSanctionsRepository)
+	return NewSanctionsService(repo, list), repo
+}
+
+func TestSanctionsService_Screen(t *testing.T) {
+	ctx := context.Background()
+	sender := Subject{Type: SubjectUser, ID: "user1", Name: "Alice Smith"}
+	hit := Subject{Type: SubjectUser, ID: "user2", Name: "Jon Doe"}
+
+	t.Run("no hit", func(t *testing.T) {
+		service, repo := newTestService(t)
+		assert.NoError(t, service.Screen(ctx, "transfer", sender))
+		repo.AssertExpectations(t)
+	})
+
+	t.Run("new hit opens a case", func(t *testing.T) {
+		service, repo := newTestService(t)
+		repo.On("FindCase", ctx, SubjectUser, "user2", "sdn-1").Return(Case{}, ErrCaseNotFound)
+		repo.On("CreateCase", ctx, mock.MatchedBy(func(c Case) bool {
+			return c.SubjectID == "user2" && c.EntryID == "sdn-1" && c.Operation == "transfer" && c.Status == CaseOpen
+		})).Return(nil)
+
+		assert.ErrorIs(t, service.Screen(ctx, "transfer", sender, hit), ErrBlocked)
+		repo.AssertExpectations(t)
+	})
+
+	t.Run("open case blocks without a new case", func(t *testing.T) {
+		service, repo := newTestService(t)
+		repo.On("FindCase", ctx, SubjectUser, "user2", "sdn-1").Return(Case{ID: "case1", Status: CaseOpen}, nil)
+
+		assert.ErrorIs(t, service.Screen(ctx, "transfer", hit), ErrBlocked)
+		repo.AssertNotCalled(t, "CreateCase", mock.Anything, mock.Anything)
+	})
+
+	t.Run("cleared case lets the subject through", func(t *testing.T) {
+		service, repo := newTestService(t)
+		repo.On("FindCase", ctx, SubjectUser, "user2", "sdn-1").Return(Case{ID: "case1", Status: CaseCleared}, nil)
+
+		assert.NoError(t, service.Screen(ctx, "transfer", hit))
+	})
+
+	t.Run("database failure", func(t *testing.T) {
+		service, repo := newTestService(t)
+		repo.On("FindCase", ctx, SubjectUser, "user2", "sdn-1").Return(Case{}, errors.New("connection reset"))
+
+		assert.ErrorIs(t, service.Screen(ctx, "transfer", hit), ErrDatabaseFailure)
+	})
+}
+
+func TestSanctionsService_ClearCase(t *testing.T) {
+	ctx := context.Background()
+	open := Case{ID: "case1", SubjectID: "user2", EntryID: "sdn-1", Status: CaseOpen}
+
+	t.Run("clears an open case", func(t *testing.T) {
+		service, repo := newTestService(t)
+		repo.On("GetCaseByID", ctx, "case1").Return(open, nil)
+		repo.On("ClearCase", ctx, mock.MatchedBy(func(c Case) bool {
+			return c.Status == CaseCleared && c.ClearedBy == "admin" && c.Reason == "different person"
+		})).Return(nil)
+
+		c, err := service.ClearCase(ctx, "case1", "admin", "different person")
+		require.NoError(t, err)
+		assert.Equal(t, CaseCleared, c.Status)
+		repo.AssertExpectations(t)
+	})
+
+	t.Run("cleared concurrently", func(t *testing.T) {
+		service, repo := newTestService(t)
+		repo.On("GetCaseByID", ctx, "case1").Return(open, nil)
+		repo.On("ClearCase", ctx, mock.Anything).Return(ErrCaseNotOpen)
+
+		_, err := service.ClearCase(ctx, "case1", "admin", "different person")
+		assert.ErrorIs(t, err, ErrCaseNotOpen)
+	})
+
+	t.Run("unknown case", func(t *testing.T) {
+		service, repo := newTestService(t)
+		repo.On("GetCaseByID", ctx, "missing").Return(Case{}, ErrCaseNotFound)
+
+		_, err := service.ClearCase(ctx, "missing", "admin", "different person")
+		assert.ErrorIs(t, err, ErrCaseNotFound)
+	})
+}
@@ -0,0 +1,22 @@
+package snapshot
+
+import "time"
+
+// Snapshot is the balance of a wallet at TakenAt: the net amount of every completed
+// transaction created before TakenAt. Historical balances replay only the transactions
+// since the nearest snapshot instead of the whole history.
+type Snapshot struct {
+	UserID   string
+	Currency string
+	Balance  int64
+	TakenAt  time.Time
+}
+
+// Total sums the snapshots of every wallet holding Currency taken at TakenAt. Together
+// the totals of one cutoff are the end-of-day record finance reconciles against.
+type Total struct {
+	Currency string
+	Balance  int64
+	Wallets  int
+	TakenAt  time.Time
+}
@@ -0,0 +1,9 @@
+package snapshot
+
+import "errors"
+
+var (
+	ErrInvalidSnapshot  = errors.New("invalid balance snapshot")
+	ErrSnapshotNotFound = errors.New("balance snapshot not found")
+	ErrDatabaseFailure  = errors.New("database failure")
+)
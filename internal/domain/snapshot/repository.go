@@ -0,0 +1,22 @@
+package snapshot
+
+import (
+	"context"
+	"time"
+)
+
+type SnapshotRepository interface {
+	// CreateSnapshot stores s unless the wallet already has a snapshot taken at the same
+	// time, so a rerun of the same cutoff is a no-op.
+	CreateSnapshot(ctx context.Context, s Snapshot) error
+
+	// GetLatestSnapshot returns the newest snapshot of userID taken at or before at, or
+	// ErrSnapshotNotFound.
+	GetLatestSnapshot(ctx context.Context, userID string, at time.Time) (Snapshot, error)
+
+	// CreateTotal stores t unless its currency already has a total taken at the same time.
+	CreateTotal(ctx context.Context, t Total) error
+
+	// ListTotals returns the totals taken exactly at at, ordered by currency.
+	ListTotals(ctx context.Context, at time.Time) ([]Total, error)
+}
@@ -0,0 +1,70 @@
+package snapshot
+
+import (
+	"context"
+	"errors"
+	"time"
+)
+
+type SnapshotServiceInterface interface {
+	Record(ctx context.Context, s Snapshot) error
+	Latest(ctx context.Context, userID string, at time.Time) (Snapshot, error)
+	RecordTotal(ctx context.Context, t Total) error
+	Totals(ctx context.Context, at time.Time) ([]Total, error)
+}
+
+type SnapshotService struct {
+	repository SnapshotRepository
+}
+
+func NewSnapshotService(repo SnapshotRepository) *SnapshotService {
+	return &SnapshotService{
+		repository: repo,
+	}
+}
+
+func (s *SnapshotService) Record(ctx context.Context, snap Snapshot) error {
+	if snap.UserID == "" || snap.TakenAt.IsZero() {
+		return ErrInvalidSnapshot
+	}
+	if err := s.repository.CreateSnapshot(ctx, snap); err != nil {
+		return ErrDatabaseFailure
+	}
+	return nil
+}
+
+// Latest returns the newest snapshot of userID taken at or before at, or
+// ErrSnapshotNotFound when there is none.
+func (s *SnapshotService) Latest(ctx context.Context, userID string, at time.Time) (Snapshot, error) {
+	snap, err := s.repository.GetLatestSnapshot(ctx, userID, at)
+	if err != nil {
+		if errors.Is(err, ErrSnapshotNotFound) {
+			return Snapshot{}, ErrSnapshotNotFound
+		}
+		return Snapshot{}, ErrDatabaseFailure
+	}
+	return snap, nil
+}
+
+func (s *SnapshotService) RecordTotal(ctx context.Context, t Total) error {
+	if t.Currency == "" || t.TakenAt.IsZero() {
+		return ErrInvalidSnapshot
+	}
+	if err := s.repository.CreateTotal(ctx, t); err != nil {
+		return ErrDatabaseFailure
+	}
+	return nil
+}
+
+// Totals returns the per-currency totals taken at at, or ErrSnapshotNotFound when no
+// snapshot was taken then.
+func (s *SnapshotService) Totals(ctx context.Context, at time.Time) ([]Total, error) {
+	totals, err := s.repository.ListTotals(ctx, at)
+	if err != nil {
+		return nil, ErrDatabaseFailure
+	}
+	if len(totals) == 0 {
+		return nil, ErrSnapshotNotFound
+	}
+	return totals, nil
+}
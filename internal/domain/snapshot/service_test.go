@@ -0,0 +1,170 @@
+package snapshot
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+)
+
+type MockSnapshotRepository struct {
+	mock.Mock
+}
+
+func (m *MockSnapshotRepository) CreateSnapshot(ctx context.Context, s Snapshot) error {
+	args := m.Called(ctx, s)
+	return args.Error(0)
+}
+
+func (m *MockSnapshotRepository) GetLatestSnapshot(ctx context.Context, userID string, at time.Time) (Snapshot, error) {
+	args := m.Called(ctx, userID, at)
+	return args.Get(0).(Snapshot), args.Error(1)
+}
+
+func (m *MockSnapshotRepository) CreateTotal(ctx context.Context, t Total) error {
+	args := m.Called(ctx, t)
+	return args.Error(0)
+}
+
+func (m *MockSnapshotRepository) ListTotals(ctx context.Context, at time.Time) ([]Total, error) {
+	args := m.Called(ctx, at)
+	return args.Get(0).([]Total), args.Error(1)
+}
+
+func TestSnapshotService_Record(t *testing.T) {
+	ctx := context.Background()
+	takenAt := time.Date(2024, 3, 1, 0, 0, 0, 0, time.UTC)
+
+	t.Run("stores snapshot", func(t *testing.T) {
+		repo := new(MockSnapshotRepository)
+		service := NewSnapshotService(repo)
+		snap := Snapshot{UserID: "user1", Currency: "USD", Balance: 500, TakenAt: takenAt}
+		repo.On("CreateSnapshot", ctx, snap).Return(nil)
+
+		assert.NoError(t, service.Record(ctx, snap))
+		repo.AssertExpectations(t)
+	})
+
+	t.Run("missing user", func(t *testing.T) {
+		service := NewSnapshotService(new(MockSnapshotRepository))
+
+		err := service.Record(ctx, Snapshot{TakenAt: takenAt})
+
+		assert.Equal(t, ErrInvalidSnapshot, err)
+	})
+
+	t.Run("missing time", func(t *testing.T) {
+		service := NewSnapshotService(new(MockSnapshotRepository))
+
+		err := service.Record(ctx, Snapshot{UserID: "user1"})
+
+		assert.Equal(t, ErrInvalidSnapshot, err)
+	})
+
+	t.Run("repository failure", func(t *testing.T) {
+		repo := new(MockSnapshotRepository)
+		service := NewSnapshotService(repo)
+		snap := Snapshot{UserID: "user1", TakenAt: takenAt}
+		repo.On("CreateSnapshot", ctx, snap).Return(errors.New("connection reset"))
+
+		assert.Equal(t, ErrDatabaseFailure, service.Record(ctx, snap))
+	})
+}
+
+func TestSnapshotService_Latest(t *testing.T) {
+	ctx := context.Background()
+	at := time.Date(2024, 3, 15, 12, 0, 0, 0, time.UTC)
+
+	t.Run("found", func(t *testing.T) {
+		repo := new(MockSnapshotRepository)
+		service := NewSnapshotService(repo)
+		snap := Snapshot{UserID: "user1", Balance: 500, TakenAt: time.Date(2024, 3, 15, 0, 0, 0, 0, time.UTC)}
+		repo.On("GetLatestSnapshot", ctx, "user1", at).Return(snap, nil)
+
+		got, err := service.Latest(ctx, "user1", at)
+
+		assert.NoError(t, err)
+		assert.Equal(t, snap, got)
+	})
+
+	t.Run("not found", func(t *testing.T) {
+		repo := new(MockSnapshotRepository)
+		service := NewSnapshotService(repo)
+		repo.On("GetLatestSnapshot", ctx, "user1", at).Return(Snapshot{}, ErrSnapshotNotFound)
+
+		_, err := service.Latest(ctx, "user1", at)
+
+		assert.Equal(t, ErrSnapshotNotFound, err)
+	})
+
+	t.Run("repository failure", func(t *testing.T) {
+		repo := new(MockSnapshotRepository)
+		service := NewSnapshotService(repo)
+		repo.On("GetLatestSnapshot", ctx, "user1", at).Return(Snapshot{}, errors.New("connection reset"))
+
+		_, err := service.Latest(ctx, "user1", at)
+
+		assert.Equal(t, ErrDatabaseFailure, err)
+	})
+}
+
+func TestSnapshotService_RecordTotal(t *testing.T) {
+	ctx := context.Background()
+	takenAt := time.Date(2024, 3, 1, 0, 0, 0, 0, time.UTC)
+
+	t.Run("stores total", func(t *testing.T) {
+		repo := new(MockSnapshotRepository)
+		service := NewSnapshotService(repo)
+		total := Total{Currency: "USD", Balance: 1500, Wallets: 2, TakenAt: takenAt}
+		repo.On("CreateTotal", ctx, total).Return(nil)
+
+		assert.NoError(t, service.RecordTotal(ctx, total))
+		repo.AssertExpectations(t)
+	})
+
+	t.Run("missing currency", func(t *testing.T) {
+		service := NewSnapshotService(new(MockSnapshotRepository))
+
+		assert.Equal(t, ErrInvalidSnapshot, service.RecordTotal(ctx, Total{TakenAt: takenAt}))
+	})
+}
+
+func TestSnapshotService_Totals(t *testing.T) {
+	ctx := context.Background()
+	at := time.Date(2024, 3, 1, 0, 0, 0, 0, time.UTC)
+
+	t.Run("found", func(t *testing.T) {
+		repo := new(MockSnapshotRepository)
+		service := NewSnapshotService(repo)
+		totals := []Total{{Currency: "EUR", Balance: 10, Wallets: 1, TakenAt: at}, {Currency: "USD", Balance: 1500, Wallets: 2, TakenAt: at}}
+		repo.On("ListTotals", ctx, at).Return(totals, nil)
+
+		got, err := service.Totals(ctx, at)
+
+		assert.NoError(t, err)
+		assert.Equal(t, totals, got)
+	})
+
+	t.Run("no snapshot at that time", func(t *testing.T) {
+		repo := new(MockSnapshotRepository)
+		service := NewSnapshotService(repo)
+		repo.On("ListTotals", ctx, at).Return([]Total(nil), nil)
+
+		_, err := service.Totals(ctx, at)
+
+		assert.Equal(t, ErrSnapshotNotFound, err)
+	})
+
+	t.Run("repository failure", func(t *testing.T) {
+		repo := new(MockSnapshotRepository)
+		service := NewSnapshotService(repo)
+		repo.On("ListTotals", ctx, at).Return([]Total(nil), errors.New("connection reset"))
+
+		_, err := service.Totals(ctx, at)
+
+		assert.Equal(t, ErrDatabaseFailure, err)
+	})
+}
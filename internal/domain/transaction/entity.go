@@ -1,6 +1,9 @@
 package transaction
 
 import (
+	"cmp"
+	"math"
+	"slices"
 	"time"
 )
 
@@ -10,16 +13,160 @@ const (
 	TransactionTypeDeposit  TransactionType = "DEPOSIT"
 	TransactionTypeWithdraw TransactionType = "WITHDRAW"
 	TransactionTypeTransfer TransactionType = "TRANSFER"
+	// TransactionTypeAdjustment is a manual credit (ToUserID set) or debit (FromUserID set)
+	// made by back-office staff.
+	TransactionTypeAdjustment TransactionType = "ADJUSTMENT"
+	// TransactionTypeReversal undoes all or part of any other transaction, for example one
+	// made in error, by moving the funds back. OriginalID names the undone transaction.
+	TransactionTypeReversal TransactionType = "REVERSAL"
+	// TransactionTypeRefund returns all or part of a transfer from its recipient to its
+	// sender. OriginalID names the refunded transfer.
+	TransactionTypeRefund TransactionType = "REFUND"
+	// TransactionTypeEscrow moves funds between a wallet and the escrow account of their
+	// currency: from the payer when an escrow is funded, to the payer or payee when it settles.
+	TransactionTypeEscrow TransactionType = "ESCROW"
+	// TransactionTypeInterest pays the interest a wallet accrued over a month from the house
+	// account of its currency's interest product.
+	TransactionTypeInterest TransactionType = "INTEREST"
 )
 
+func (t TransactionType) Valid() bool {
+	switch t {
+	case TransactionTypeDeposit, TransactionTypeWithdraw, TransactionTypeTransfer, TransactionTypeAdjustment,
+		TransactionTypeReversal, TransactionTypeRefund, TransactionTypeEscrow, TransactionTypeInterest:
+		return true
+	}
+	return false
+}
+
+// Status is where a transaction is in its lifecycle. Most transactions are completed as
+// soon as they are logged; asynchronous ones, such as withdrawals paid out by a bank or
+// waiting for an admin's approval, hold the funds until they reach a final status.
+type Status string
+
+const (
+	StatusAwaitingApproval Status = "awaiting_approval" // Awaiting approval transactions wait for an admin to approve or reject them.
+	StatusPending          Status = "pending"           // Pending transactions have been accepted but not started.
+	StatusProcessing       Status = "processing"        // Processing transactions have been handed to an external system.
+	StatusCompleted        Status = "completed"         // Completed transactions have moved the funds.
+	StatusFailed           Status = "failed"            // Failed transactions were given up on and never move the funds.
+	StatusCancelled        Status = "cancelled"         // Cancelled transactions were withdrawn by the user before processing.
+)
+
+func (s Status) Valid() bool {
+	switch s {
+	case StatusAwaitingApproval, StatusPending, StatusProcessing, StatusCompleted, StatusFailed, StatusCancelled:
+		return true
+	}
+	return false
+}
+
+// Final reports whether no further transition is possible from s.
+func (s Status) Final() bool {
+	return s == StatusCompleted || s == StatusFailed || s == StatusCancelled
+}
+
+// CanTransitionTo reports whether a transaction in status s may move to status to.
+// Transactions awaiting approval complete when approved, fail when rejected and are
+// cancelled when they expire or by the user. Pending transactions may start processing,
+// complete, fail or be cancelled; processing ones may only complete or fail.
+func (s Status) CanTransitionTo(to Status) bool {
+	switch s {
+	case StatusAwaitingApproval:
+		return to == StatusCompleted || to == StatusFailed || to == StatusCancelled
+	case StatusPending:
+		return to == StatusProcessing || to == StatusCompleted || to == StatusFailed || to == StatusCancelled
+	case StatusProcessing:
+		return to == StatusCompleted || to == StatusFailed
+	}
+	return false
+}
+
 type Transaction struct {
 	ID         string          // Unique transaction identifier
 	FromUserID string          // Source user ID
 	ToUserID   string          // Target user ID
 	Amount     int64           // Transaction amount, expressed as an integer in the smallest currency unit
 	Currency   string          // Currency code (e.g., "USD", "TWD")
-	Type       TransactionType // Transaction type (DEPOSIT, WITHDRAW, TRANSFER)
-	CreatedAt  time.Time       // Transaction creation time
+	Type       TransactionType // Transaction type (DEPOSIT, WITHDRAW, TRANSFER, ADJUSTMENT, REVERSAL, REFUND, ESCROW, INTEREST)
+	BatchID    string          // Batch the transaction was created in, empty for single operations
+	OriginalID string          // Transaction undone by a REVERSAL or REFUND, empty otherwise
+	Status     Status          // Where the transaction is in its lifecycle
+	// StatusReason explains the current status, such as why the transaction needs approval
+	// or why it was rejected; empty when there is nothing to add.
+	StatusReason string
+	CreatedAt    time.Time // Transaction creation time, or the date a back-dated transaction is booked on
+
+	ProcessingAt *time.Time // Set once the transaction has started processing
+	CompletedAt  *time.Time // Set once the transaction has completed
+	FailedAt     *time.Time // Set once the transaction has failed
+	CancelledAt  *time.Time // Set once the transaction has been cancelled
+}
+
+// Cursor is a position in a history ordered newest first, by creation time and then by ID.
+type Cursor struct {
+	CreatedAt time.Time
+	ID        string
+}
+
+// SearchFilter narrows a search across all users' transactions. Zero-valued fields match
+// every transaction.
+type SearchFilter struct {
+	UserID    string          // UserID matches transactions sent or received by the user.
+	Type      TransactionType // Type matches transactions of this type.
+	BatchID   string          // BatchID matches transactions created in this batch.
+	From      time.Time       // From matches transactions created at or after it.
+	To        time.Time       // To matches transactions created before it.
+	MinAmount int64           // MinAmount matches transactions of at least this amount.
+	MaxAmount int64           // MaxAmount matches transactions of at most this amount.
+	Status    Status          // Status matches transactions in this status.
+}
+
+// Option sets optional attributes of a transaction when it is logged.
+type Option func(*Transaction)
+
+// WithBatchID records that the transaction belongs to the batch batchID.
+func WithBatchID(batchID string) Option {
+	return func(t *Transaction) {
+		t.BatchID = batchID
+	}
+}
+
+// WithOriginalID records that the transaction undoes the transaction originalID.
+func WithOriginalID(originalID string) Option {
+	return func(t *Transaction) {
+		t.OriginalID = originalID
+	}
+}
+
+// EffectiveAt books the transaction on at instead of now, for example an adjustment
+// back-dated to correct an earlier period.
+func EffectiveAt(at time.Time) Option {
+	return func(t *Transaction) {
+		t.CreatedAt = at
+		if t.CompletedAt != nil {
+			t.CompletedAt = &at
+		}
+	}
+}
+
+// AwaitingApproval logs the transaction as waiting for an admin's approval for reason; the
+// caller holds the funds until it is approved, rejected or expires.
+func AwaitingApproval(reason string) Option {
+	return func(t *Transaction) {
+		t.Status = StatusAwaitingApproval
+		t.StatusReason = reason
+		t.CompletedAt = nil
+	}
+}
+
+// AsPending logs the transaction as pending instead of completed; the caller holds the
+// funds until it reaches a final status.
+func AsPending() Option {
+	return func(t *Transaction) {
+		t.Status = StatusPending
+		t.CompletedAt = nil
+	}
 }
 
 func NewTransaction(id, fromUserID, toUserID string, amount int64, currency string, tType TransactionType) (Transaction, error) {
@@ -29,13 +176,199 @@ func NewTransaction(id, fromUserID, toUserID string, amount int64, currency stri
 	if amount <= 0 {
 		return Transaction{}, ErrInvalidTransactionAmount
 	}
+	now := time.Now()
 	return Transaction{
-		ID:         id,
-		FromUserID: fromUserID,
-		ToUserID:   toUserID,
-		Amount:     amount,
-		Currency:   currency,
-		Type:       tType,
-		CreatedAt:  time.Now(),
+		ID:          id,
+		FromUserID:  fromUserID,
+		ToUserID:    toUserID,
+		Amount:      amount,
+		Currency:    currency,
+		Type:        tType,
+		Status:      StatusCompleted,
+		CreatedAt:   now,
+		CompletedAt: &now,
 	}, nil
 }
+
+// TransitionTo moves the transaction to status to at time at, recording when it happened
+// and why.
+func (t *Transaction) TransitionTo(to Status, reason string, at time.Time) error {
+	if !to.Valid() {
+		return ErrInvalidStatus
+	}
+	if !t.Status.CanTransitionTo(to) {
+		return ErrInvalidStatusTransition
+	}
+
+	t.Status = to
+	t.StatusReason = reason
+	switch to {
+	case StatusProcessing:
+		t.ProcessingAt = &at
+	case StatusCompleted:
+		t.CompletedAt = &at
+	case StatusFailed:
+		t.FailedAt = &at
+	case StatusCancelled:
+		t.CancelledAt = &at
+	}
+	return nil
+}
+
+// Cursor returns the position of t in a history ordered newest first.
+func (t Transaction) Cursor() Cursor {
+	return Cursor{CreatedAt: t.CreatedAt, ID: t.ID}
+}
+
+// BookedAt returns when the transaction reached the books: when it completed, or when it
+// was created if it has not completed.
+func (t Transaction) BookedAt() time.Time {
+	if t.CompletedAt != nil {
+		return *t.CompletedAt
+	}
+	return t.CreatedAt
+}
+
+// SignedAmountFor returns the amount as seen from userID's wallet: positive when
+// the user is credited, negative when the user is debited.
+func (t Transaction) SignedAmountFor(userID string) int64 {
+	var amount int64
+	if t.ToUserID == userID {
+		amount += t.Amount
+	}
+	if t.FromUserID == userID {
+		amount -= t.Amount
+	}
+	return amount
+}
+
+// UndoableBy reports whether a transaction of type tType may undo t: a REFUND undoes a
+// completed transfer and a REVERSAL undoes anything completed but another reversal or refund,
+// or an escrow movement, which only settling the escrow undoes.
+func (t Transaction) UndoableBy(tType TransactionType) bool {
+	if t.Status != StatusCompleted {
+		return false
+	}
+	switch tType {
+	case TransactionTypeRefund:
+		return t.Type == TransactionTypeTransfer
+	case TransactionTypeReversal:
+		return t.Type != TransactionTypeReversal && t.Type != TransactionTypeRefund && t.Type != TransactionTypeEscrow
+	}
+	return false
+}
+
+// UndoneAmount returns how much the reversals and refunds undos of a transaction have
+// moved back.
+func UndoneAmount(undos []Transaction) int64 {
+	var amount int64
+	for _, u := range undos {
+		amount += u.Amount
+	}
+	return amount
+}
+
+// Share is one party's part of a split or collection: either a fixed Amount or
+// BasisPoints, hundredths of a percent, of the total.
+type Share struct {
+	UserID      string
+	Amount      int64
+	BasisPoints int64
+}
+
+// RemainderRule decides which shares get the minor units left over when percentages of a
+// total are rounded down.
+type RemainderRule string
+
+const (
+	RemainderLargest RemainderRule = "largest" // Largest gives a unit each to the shares rounding cut the most from, earliest first on ties.
+	RemainderFirst   RemainderRule = "first"   // First gives every unit left over to the first share.
+	RemainderLast    RemainderRule = "last"    // Last gives every unit left over to the last share.
+)
+
+func (r RemainderRule) Valid() bool {
+	return r == RemainderLargest || r == RemainderFirst || r == RemainderLast
+}
+
+// basisPointsPerWhole is the number of basis points in 100%.
+const basisPointsPerWhole = 10000
+
+// Allocate returns the amount of each of shares of total. Shares have either all fixed
+// amounts, which must add up to total unless total is 0, or all basis points, which must
+// add up to 100% of a positive total and are rounded down to minor units; rule, by default
+// RemainderLargest, hands out the units rounding left over.
+func Allocate(total int64, shares []Share, rule RemainderRule) ([]int64, error) {
+	if len(shares) == 0 {
+		return nil, ErrEmptySplit
+	}
+	if rule == "" {
+		rule = RemainderLargest
+	}
+	if !rule.Valid() {
+		return nil, ErrInvalidRemainderRule
+	}
+
+	byAmount := shares[0].Amount != 0
+	amounts := make([]int64, len(shares))
+	var sum int64
+	for i, s := range shares {
+		part := s.BasisPoints
+		if byAmount {
+			part = s.Amount
+		}
+		if part <= 0 || (byAmount && s.BasisPoints != 0) || (!byAmount && s.Amount != 0) {
+			return nil, ErrInvalidShare
+		}
+		if sum > math.MaxInt64-part {
+			return nil, ErrSharesMismatch
+		}
+		sum += part
+		amounts[i] = part
+	}
+
+	if byAmount {
+		if total != 0 && total != sum {
+			return nil, ErrSharesMismatch
+		}
+		return amounts, nil
+	}
+	if total <= 0 {
+		return nil, ErrInvalidTransactionAmount
+	}
+	if sum != basisPointsPerWhole {
+		return nil, ErrSharesMismatch
+	}
+
+	// total*bp/10000 is worked out as whole*bp + part*bp/10000 so it cannot overflow.
+	whole, part := total/basisPointsPerWhole, total%basisPointsPerWhole
+	cut := make([]int64, len(shares))
+	left := total
+	for i, s := range shares {
+		amounts[i] = whole*s.BasisPoints + part*s.BasisPoints/basisPointsPerWhole
+		cut[i] = part * s.BasisPoints % basisPointsPerWhole
+		left -= amounts[i]
+	}
+
+	switch rule {
+	case RemainderFirst:
+		amounts[0] += left
+	case RemainderLast:
+		amounts[len(amounts)-1] += left
+	case RemainderLargest:
+		order := make([]int, len(shares))
+		for i := range order {
+			order[i] = i
+		}
+		slices.SortStableFunc(order, func(a, b int) int { return cmp.Compare(cut[b], cut[a]) })
+		for _, i := range order[:left] {
+			amounts[i]++
+		}
+	}
+
+	for _, amount := range amounts {
+		if amount == 0 {
+			return nil, ErrShareTooSmall
+		}
+	}
+	return amounts, nil
+}
@@ -1,6 +1,7 @@
 package transaction
 
 import (
+	"math"
 	"testing"
 	"time"
 
@@ -98,6 +99,7 @@ func TestNewTransaction(t *testing.T) {
 				assert.Equal(t, tt.amount, tx.Amount, "Amount should match")
 				assert.Equal(t, tt.currency, tx.Currency, "Currency should match")
 				assert.Equal(t, tt.tType, tx.Type, "TransactionType should match")
+				assert.Equal(t, StatusCompleted, tx.Status, "Transactions are completed when created")
 
 				// 驗證 CreatedAt 是否在合理的時間範圍內
 				now := time.Now()
@@ -111,4 +113,231 @@ func TestTransactionTypes(t *testing.T) {
 	assert.Equal(t, "DEPOSIT", string(TransactionTypeDeposit), "TransactionTypeDeposit should be 'DEPOSIT'")
 	assert.Equal(t, "WITHDRAW", string(TransactionTypeWithdraw), "TransactionTypeWithdraw should be 'WITHDRAW'")
 	assert.Equal(t, "TRANSFER", string(TransactionTypeTransfer), "TransactionTypeTransfer should be 'TRANSFER'")
+	assert.True(t, TransactionTypeReversal.Valid())
+	assert.True(t, TransactionTypeRefund.Valid())
+	assert.True(t, TransactionTypeInterest.Valid())
+}
+
+func TestTransaction_UndoableBy(t *testing.T) {
+	completed := func(tType TransactionType) Transaction {
+		return Transaction{Type: tType, Status: StatusCompleted}
+	}
+	for _, tType := range []TransactionType{TransactionTypeDeposit, TransactionTypeWithdraw, TransactionTypeTransfer, TransactionTypeAdjustment} {
+		assert.True(t, completed(tType).UndoableBy(TransactionTypeReversal), "%s can be reversed", tType)
+	}
+	assert.True(t, completed(TransactionTypeTransfer).UndoableBy(TransactionTypeRefund))
+	assert.False(t, completed(TransactionTypeDeposit).UndoableBy(TransactionTypeRefund))
+	assert.False(t, completed(TransactionTypeRefund).UndoableBy(TransactionTypeReversal))
+	assert.False(t, completed(TransactionTypeReversal).UndoableBy(TransactionTypeReversal))
+	assert.False(t, completed(TransactionTypeEscrow).UndoableBy(TransactionTypeReversal))
+	assert.False(t, completed(TransactionTypeTransfer).UndoableBy(TransactionTypeTransfer))
+	assert.False(t, Transaction{Type: TransactionTypeWithdraw, Status: StatusPending}.UndoableBy(TransactionTypeReversal))
+}
+
+func TestTransaction_TransitionTo(t *testing.T) {
+	at := time.Date(2024, 3, 1, 12, 0, 0, 0, time.UTC)
+
+	tx, err := NewTransaction("tx1", "user1", "", 500, "USD", TransactionTypeWithdraw)
+	assert.NoError(t, err)
+	AsPending()(&tx)
+	assert.Equal(t, StatusPending, tx.Status)
+	assert.Nil(t, tx.CompletedAt)
+
+	assert.NoError(t, tx.TransitionTo(StatusProcessing, "", at))
+	assert.Equal(t, &at, tx.ProcessingAt)
+	assert.Equal(t, ErrInvalidStatusTransition, tx.TransitionTo(StatusCancelled, "", at), "processing withdrawals cannot be cancelled")
+	assert.NoError(t, tx.TransitionTo(StatusCompleted, "", at.Add(time.Hour)))
+	assert.Equal(t, StatusCompleted, tx.Status)
+	assert.Equal(t, at.Add(time.Hour), *tx.CompletedAt)
+	assert.Equal(t, ErrInvalidStatusTransition, tx.TransitionTo(StatusFailed, "", at), "final statuses do not change")
+	assert.Equal(t, ErrInvalidStatus, tx.TransitionTo("settled", "", at))
+}
+
+func TestTransaction_BookedAt(t *testing.T) {
+	at := time.Date(2024, 3, 1, 12, 0, 0, 0, time.UTC)
+	tx := Transaction{Status: StatusPending, CreatedAt: at}
+	assert.Equal(t, at, tx.BookedAt(), "not completed yet")
+
+	assert.NoError(t, tx.TransitionTo(StatusCompleted, "", at.Add(48*time.Hour)))
+	assert.Equal(t, at.Add(48*time.Hour), tx.BookedAt())
+}
+
+func TestAwaitingApproval(t *testing.T) {
+	tx, err := NewTransaction("tx1", "user1", "", 500, "USD", TransactionTypeWithdraw)
+	assert.NoError(t, err)
+	AwaitingApproval("amount above the approval threshold")(&tx)
+
+	assert.Equal(t, StatusAwaitingApproval, tx.Status)
+	assert.Equal(t, "amount above the approval threshold", tx.StatusReason)
+	assert.Nil(t, tx.CompletedAt)
+	assert.Equal(t, ErrInvalidStatusTransition, tx.TransitionTo(StatusProcessing, "", time.Now()), "approval comes first")
+	assert.NoError(t, tx.TransitionTo(StatusFailed, "unverified bank account", time.Now()))
+	assert.Equal(t, "unverified bank account", tx.StatusReason)
+	assert.NotNil(t, tx.FailedAt)
+}
+
+func TestStatus_CanTransitionTo(t *testing.T) {
+	for _, to := range []Status{StatusProcessing, StatusCompleted, StatusFailed, StatusCancelled} {
+		assert.True(t, StatusPending.CanTransitionTo(to), "pending to %s", to)
+	}
+	assert.True(t, StatusProcessing.CanTransitionTo(StatusCompleted))
+	assert.True(t, StatusProcessing.CanTransitionTo(StatusFailed))
+	assert.False(t, StatusProcessing.CanTransitionTo(StatusCancelled))
+	assert.False(t, StatusProcessing.CanTransitionTo(StatusPending))
+	for _, from := range []Status{StatusCompleted, StatusFailed, StatusCancelled} {
+		assert.True(t, from.Final())
+		assert.False(t, from.CanTransitionTo(StatusPending), "%s is final", from)
+	}
+}
+
+func TestTransaction_SignedAmountFor(t *testing.T) {
+	tests := []struct {
+		name     string
+		tx       Transaction
+		userID   string
+		expected int64
+	}{
+		{
+			name:     "deposit credits the receiver",
+			tx:       Transaction{ToUserID: "user1", Amount: 1000, Type: TransactionTypeDeposit},
+			userID:   "user1",
+			expected: 1000,
+		},
+		{
+			name:     "withdraw debits the sender",
+			tx:       Transaction{FromUserID: "user1", Amount: 500, Type: TransactionTypeWithdraw},
+			userID:   "user1",
+			expected: -500,
+		},
+		{
+			name:     "transfer seen from the receiver",
+			tx:       Transaction{FromUserID: "user1", ToUserID: "user2", Amount: 300, Type: TransactionTypeTransfer},
+			userID:   "user2",
+			expected: 300,
+		},
+		{
+			name:     "unrelated user",
+			tx:       Transaction{FromUserID: "user1", ToUserID: "user2", Amount: 300, Type: TransactionTypeTransfer},
+			userID:   "user3",
+			expected: 0,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			assert.Equal(t, tt.expected, tt.tx.SignedAmountFor(tt.userID))
+		})
+	}
+}
+
+func TestAllocate(t *testing.T) {
+	thirds := []Share{{UserID: "a", BasisPoints: 3333}, {UserID: "b", BasisPoints: 3333}, {UserID: "c", BasisPoints: 3334}}
+
+	tests := []struct {
+		name     string
+		total    int64
+		shares   []Share
+		rule     RemainderRule
+		expected []int64
+		err      error
+	}{
+		{
+			name:     "fixed amounts",
+			shares:   []Share{{UserID: "a", Amount: 300}, {UserID: "b", Amount: 700}},
+			expected: []int64{300, 700},
+		},
+		{
+			name:   "fixed amounts short of the total",
+			total:  1500,
+			shares: []Share{{UserID: "a", Amount: 300}, {UserID: "b", Amount: 700}},
+			err:    ErrSharesMismatch,
+		},
+		{
+			name:     "percentages without rounding",
+			total:    1000,
+			shares:   []Share{{UserID: "a", BasisPoints: 2500}, {UserID: "b", BasisPoints: 7500}},
+			expected: []int64{250, 750},
+		},
+		{
+			name:     "remainder to the largest cuts",
+			total:    100,
+			shares:   thirds,
+			expected: []int64{33, 33, 34},
+		},
+		{
+			name:     "remainder to the largest cuts, earliest first",
+			total:    200,
+			shares:   thirds,
+			rule:     RemainderLargest,
+			expected: []int64{67, 66, 67},
+		},
+		{
+			name:     "remainder to the first share",
+			total:    200,
+			shares:   thirds,
+			rule:     RemainderFirst,
+			expected: []int64{68, 66, 66},
+		},
+		{
+			name:     "remainder to the last share",
+			total:    200,
+			shares:   thirds,
+			rule:     RemainderLast,
+			expected: []int64{66, 66, 68},
+		},
+		{
+			name:     "large totals",
+			total:    math.MaxInt64,
+			shares:   []Share{{UserID: "a", BasisPoints: 5000}, {UserID: "b", BasisPoints: 5000}},
+			expected: []int64{math.MaxInt64/2 + 1, math.MaxInt64 / 2},
+		},
+		{
+			name:   "percentages short of 100",
+			total:  1000,
+			shares: []Share{{UserID: "a", BasisPoints: 5000}},
+			err:    ErrSharesMismatch,
+		},
+		{
+			name:   "percentages of nothing",
+			shares: thirds,
+			err:    ErrInvalidTransactionAmount,
+		},
+		{
+			name:   "share rounding down to nothing",
+			total:  1,
+			shares: []Share{{UserID: "a", BasisPoints: 5000}, {UserID: "b", BasisPoints: 5000}},
+			err:    ErrShareTooSmall,
+		},
+		{
+			name:   "amounts mixed with percentages",
+			total:  1000,
+			shares: []Share{{UserID: "a", Amount: 500}, {UserID: "b", BasisPoints: 5000}},
+			err:    ErrInvalidShare,
+		},
+		{
+			name:   "negative amount",
+			shares: []Share{{UserID: "a", Amount: -500}},
+			err:    ErrInvalidShare,
+		},
+		{
+			name: "no shares",
+			err:  ErrEmptySplit,
+		},
+		{
+			name:   "unknown remainder rule",
+			total:  100,
+			shares: thirds,
+			rule:   "random",
+			err:    ErrInvalidRemainderRule,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			amounts, err := Allocate(tt.total, tt.shares, tt.rule)
+
+			assert.Equal(t, tt.err, err)
+			assert.Equal(t, tt.expected, amounts)
+		})
+	}
 }
@@ -9,4 +9,22 @@ var (
 	ErrInvalidUserID            = errors.New("invalid user ID")
 	ErrInvalidTransactionID     = errors.New("invalid transaction ID")
 	ErrDatabaseFailure          = errors.New("database failure")
+	ErrInvalidTimeRange         = errors.New("invalid time range")
+	ErrInvalidAmountRange       = errors.New("invalid amount range")
+	ErrEmptyBatch               = errors.New("batch contains no transfers")
+	ErrBatchTooLarge            = errors.New("batch contains too many transfers")
+	ErrInvalidBatchMode         = errors.New("invalid batch mode")
+	ErrBatchRolledBack          = errors.New("rolled back because another transfer in the batch failed")
+	ErrEmptySplit               = errors.New("split has no shares")
+	ErrInvalidShare             = errors.New("shares must all have either a positive amount or a positive percentage")
+	ErrSharesMismatch           = errors.New("shares do not add up to the total")
+	ErrShareTooSmall            = errors.New("a share rounds down to nothing")
+	ErrInvalidRemainderRule     = errors.New("invalid remainder rule")
+	ErrInvalidSplitDirection    = errors.New("invalid split direction")
+	ErrNotUndoable              = errors.New("transaction cannot be undone this way")
+	ErrUndoExceedsOriginal      = errors.New("amount exceeds what is left of the original transaction")
+	ErrInvalidStatus            = errors.New("invalid transaction status")
+	ErrInvalidStatusTransition  = errors.New("transaction cannot move to this status")
+	ErrNotAwaitingApproval      = errors.New("transaction is not awaiting approval")
+	ErrReasonRequired           = errors.New("a reason is required")
 )
@@ -2,6 +2,7 @@ package transaction
 
 import (
 	"context"
+	"time"
 )
 
 type TransactionRepository interface {
@@ -9,5 +10,46 @@ type TransactionRepository interface {
 
 	GetTransactionByID(ctx context.Context, id string) (Transaction, error)
 
+	// LockTransactionByID is GetTransactionByID that also locks the transaction until the
+	// surrounding database transaction ends, so concurrent reversals and refunds of it are
+	// checked one after the other.
+	LockTransactionByID(ctx context.Context, id string) (Transaction, error)
+
+	// UpdateTransactionStatus stores the status of tx, its reason and the time it moved to it. Callers
+	// lock tx with LockTransactionByID first.
+	UpdateTransactionStatus(ctx context.Context, tx Transaction) error
+
+	// ListTransactionsByOriginalID returns the reversals and refunds of a transaction, oldest first.
+	ListTransactionsByOriginalID(ctx context.Context, originalID string) ([]Transaction, error)
+
 	ListTransactionsByUserID(ctx context.Context, userID string, limit, offset int) ([]Transaction, error)
+
+	// ListTransactionsByUserIDBefore returns up to limit transactions of userID that come after
+	// before in their history, newest first. Unlike an offset, the cursor does not shift when
+	// transactions are logged between two pages.
+	ListTransactionsByUserIDBefore(ctx context.Context, userID string, before Cursor, limit int) ([]Transaction, error)
+
+	// StreamTransactionsByUserID calls fn for every completed transaction of userID created in
+	// [from, to), oldest first, without loading the whole result set into memory.
+	StreamTransactionsByUserID(ctx context.Context, userID string, from, to time.Time, fn func(Transaction) error) error
+
+	// SearchTransactions returns the transactions matching filter across all users, newest first.
+	SearchTransactions(ctx context.Context, filter SearchFilter, limit, offset int) ([]Transaction, error)
+
+	// SumNetAmountSince returns credits minus debits of userID for completed transactions
+	// created at or after since.
+	SumNetAmountSince(ctx context.Context, userID string, since time.Time) (int64, error)
+
+	// SumNetAmountBetween returns credits minus debits of userID for completed transactions
+	// created in [from, to).
+	SumNetAmountBetween(ctx context.Context, userID string, from, to time.Time) (int64, error)
+
+	// SumNetFlowBetween returns, per currency, what completed transactions created in
+	// [from, to) credited to wallets minus what they debited. Transfers cancel out, so it
+	// is the change of the currency's total balance over the range.
+	SumNetFlowBetween(ctx context.Context, from, to time.Time) (map[string]int64, error)
+
+	// SumOutgoingSince returns the total amount and number of tType transactions sent by
+	// userID in currency and created at or after since, leaving out failed and cancelled ones.
+	SumOutgoingSince(ctx context.Context, userID string, tType TransactionType, currency string, since time.Time) (int64, int, error)
 }
@@ -3,14 +3,26 @@ package transaction
 import (
 	"context"
 	"errors"
+	"time"
 
 	"github.com/gofrs/uuid"
 )
 
 type TransactionServiceInterface interface {
-	LogTransaction(ctx context.Context, fromUserID, toUserID string, amount int64, currency string, tType TransactionType) (Transaction, error)
+	LogTransaction(ctx context.Context, fromUserID, toUserID string, amount int64, currency string, tType TransactionType, opts ...Option) (Transaction, error)
 	GetTransactionHistory(ctx context.Context, userID string, limit, offset int) ([]Transaction, error)
+	GetTransactionHistoryBefore(ctx context.Context, userID string, before Cursor, limit int) ([]Transaction, error)
 	GetTransactionByID(ctx context.Context, id string) (Transaction, error)
+	ListUndos(ctx context.Context, originalID string) ([]Transaction, error)
+	LogUndo(ctx context.Context, originalID string, tType TransactionType, amount int64) (Transaction, error)
+	TransitionStatus(ctx context.Context, id string, to Status, reason string) (Transaction, error)
+	ReviewTransaction(ctx context.Context, id string, approved bool, reason string) (Transaction, error)
+	StreamTransactionHistory(ctx context.Context, userID string, from, to time.Time, fn func(Transaction) error) error
+	GetNetAmountSince(ctx context.Context, userID string, since time.Time) (int64, error)
+	GetNetAmountBetween(ctx context.Context, userID string, from, to time.Time) (int64, error)
+	GetNetFlowBetween(ctx context.Context, from, to time.Time) (map[string]int64, error)
+	GetOutgoingSince(ctx context.Context, userID string, tType TransactionType, currency string, since time.Time) (int64, int, error)
+	SearchTransactions(ctx context.Context, filter SearchFilter, limit, offset int) ([]Transaction, error)
 }
 
 type TransactionService struct {
@@ -23,12 +35,12 @@ func NewTransactionService(repo TransactionRepository) *TransactionService {
 	}
 }
 
-func (s *TransactionService) LogTransaction(ctx context.Context, fromUserID, toUserID string, amount int64, currency string, tType TransactionType) (Transaction, error) {
+func (s *TransactionService) LogTransaction(ctx context.Context, fromUserID, toUserID string, amount int64, currency string, tType TransactionType, opts ...Option) (Transaction, error) {
 	if amount <= 0 {
 		return Transaction{}, ErrInvalidTransactionAmount
 	}
 
-	if tType != TransactionTypeDeposit && tType != TransactionTypeWithdraw && tType != TransactionTypeTransfer {
+	if !tType.Valid() {
 		return Transaction{}, ErrInvalidTransactionType
 	}
 
@@ -38,6 +50,13 @@ func (s *TransactionService) LogTransaction(ctx context.Context, fromUserID, toU
 	}
 
 	tx, err := NewTransaction(id, fromUserID, toUserID, amount, currency, tType)
+	if err != nil {
+		return Transaction{}, err
+	}
+	for _, opt := range opts {
+		opt(&tx)
+	}
+
 	if err := s.repository.CreateTransaction(ctx, tx); err != nil {
 		return Transaction{}, err
 	}
@@ -58,6 +77,21 @@ func (s *TransactionService) GetTransactionHistory(ctx context.Context, userID s
 	return txs, nil
 }
 
+// GetTransactionHistoryBefore returns the page of userID's history that follows before,
+// newest first.
+func (s *TransactionService) GetTransactionHistoryBefore(ctx context.Context, userID string, before Cursor, limit int) ([]Transaction, error) {
+	if userID == "" {
+		return nil, ErrInvalidUserID
+	}
+
+	txs, err := s.repository.ListTransactionsByUserIDBefore(ctx, userID, before, limit)
+	if err != nil {
+		return nil, ErrDatabaseFailure
+	}
+
+	return txs, nil
+}
+
 func (s *TransactionService) GetTransactionByID(ctx context.Context, id string) (Transaction, error) {
 	if id == "" {
 		return Transaction{}, ErrInvalidTransactionID
@@ -73,6 +107,202 @@ func (s *TransactionService) GetTransactionByID(ctx context.Context, id string)
 	return tx, nil
 }
 
+// ListUndos returns the reversals and refunds of the transaction originalID.
+func (s *TransactionService) ListUndos(ctx context.Context, originalID string) ([]Transaction, error) {
+	if originalID == "" {
+		return nil, ErrInvalidTransactionID
+	}
+
+	undos, err := s.repository.ListTransactionsByOriginalID(ctx, originalID)
+	if err != nil {
+		return nil, ErrDatabaseFailure
+	}
+	return undos, nil
+}
+
+// LogUndo logs a REVERSAL or REFUND moving amount of the transaction originalID back from
+// its recipient to its sender; an amount of 0 moves back whatever is left. Together with
+// earlier reversals and refunds, it never exceeds the original amount. The caller moves
+// the funds in the same database transaction.
+func (s *TransactionService) LogUndo(ctx context.Context, originalID string, tType TransactionType, amount int64) (Transaction, error) {
+	if originalID == "" {
+		return Transaction{}, ErrInvalidTransactionID
+	}
+	if amount < 0 {
+		return Transaction{}, ErrInvalidTransactionAmount
+	}
+
+	original, err := s.repository.LockTransactionByID(ctx, originalID)
+	if err != nil {
+		if errors.Is(err, ErrTransactionNotFound) {
+			return Transaction{}, ErrTransactionNotFound
+		}
+		return Transaction{}, ErrDatabaseFailure
+	}
+	if !original.UndoableBy(tType) {
+		return Transaction{}, ErrNotUndoable
+	}
+
+	undos, err := s.repository.ListTransactionsByOriginalID(ctx, originalID)
+	if err != nil {
+		return Transaction{}, ErrDatabaseFailure
+	}
+	left := original.Amount - UndoneAmount(undos)
+	if amount == 0 {
+		amount = left
+	}
+	if amount == 0 || amount > left {
+		return Transaction{}, ErrUndoExceedsOriginal
+	}
+
+	return s.LogTransaction(ctx, original.ToUserID, original.FromUserID, amount, original.Currency, tType, WithOriginalID(original.ID))
+}
+
+// TransitionStatus moves the transaction id to status to for reason, which may be empty.
+// The caller holds, captures or releases the funds in the same database transaction.
+func (s *TransactionService) TransitionStatus(ctx context.Context, id string, to Status, reason string) (Transaction, error) {
+	if !to.Valid() {
+		return Transaction{}, ErrInvalidStatus
+	}
+	return s.transition(ctx, id, func(tx *Transaction) error {
+		return tx.TransitionTo(to, reason, time.Now())
+	})
+}
+
+// ReviewTransaction completes a transaction awaiting approval when approved and fails it
+// otherwise. A rejection must give its reason.
+func (s *TransactionService) ReviewTransaction(ctx context.Context, id string, approved bool, reason string) (Transaction, error) {
+	if !approved && reason == "" {
+		return Transaction{}, ErrReasonRequired
+	}
+	return s.transition(ctx, id, func(tx *Transaction) error {
+		if tx.Status != StatusAwaitingApproval {
+			return ErrNotAwaitingApproval
+		}
+		to := StatusFailed
+		if approved {
+			to = StatusCompleted
+		}
+		return tx.TransitionTo(to, reason, time.Now())
+	})
+}
+
+// transition locks the transaction id, applies fn to it and stores its new status.
+func (s *TransactionService) transition(ctx context.Context, id string, fn func(tx *Transaction) error) (Transaction, error) {
+	if id == "" {
+		return Transaction{}, ErrInvalidTransactionID
+	}
+
+	tx, err := s.repository.LockTransactionByID(ctx, id)
+	if err != nil {
+		if errors.Is(err, ErrTransactionNotFound) {
+			return Transaction{}, ErrTransactionNotFound
+		}
+		return Transaction{}, ErrDatabaseFailure
+	}
+	if err := fn(&tx); err != nil {
+		return Transaction{}, err
+	}
+
+	if err := s.repository.UpdateTransactionStatus(ctx, tx); err != nil {
+		return Transaction{}, ErrDatabaseFailure
+	}
+	return tx, nil
+}
+
+func (s *TransactionService) StreamTransactionHistory(ctx context.Context, userID string, from, to time.Time, fn func(Transaction) error) error {
+	if userID == "" {
+		return ErrInvalidUserID
+	}
+	if !from.Before(to) {
+		return ErrInvalidTimeRange
+	}
+
+	return s.repository.StreamTransactionsByUserID(ctx, userID, from, to, fn)
+}
+
+func (s *TransactionService) GetNetAmountSince(ctx context.Context, userID string, since time.Time) (int64, error) {
+	if userID == "" {
+		return 0, ErrInvalidUserID
+	}
+
+	net, err := s.repository.SumNetAmountSince(ctx, userID, since)
+	if err != nil {
+		return 0, ErrDatabaseFailure
+	}
+	return net, nil
+}
+
+func (s *TransactionService) GetNetAmountBetween(ctx context.Context, userID string, from, to time.Time) (int64, error) {
+	if userID == "" {
+		return 0, ErrInvalidUserID
+	}
+	if to.Before(from) {
+		return 0, ErrInvalidTimeRange
+	}
+
+	net, err := s.repository.SumNetAmountBetween(ctx, userID, from, to)
+	if err != nil {
+		return 0, ErrDatabaseFailure
+	}
+	return net, nil
+}
+
+func (s *TransactionService) GetNetFlowBetween(ctx context.Context, from, to time.Time) (map[string]int64, error) {
+	if to.Before(from) {
+		return nil, ErrInvalidTimeRange
+	}
+
+	flows, err := s.repository.SumNetFlowBetween(ctx, from, to)
+	if err != nil {
+		return nil, ErrDatabaseFailure
+	}
+	return flows, nil
+}
+
+// GetOutgoingSince returns the total amount and number of tType transactions userID has sent
+// in currency since since that have not failed or been cancelled.
+func (s *TransactionService) GetOutgoingSince(ctx context.Context, userID string, tType TransactionType, currency string, since time.Time) (int64, int, error) {
+	if userID == "" {
+		return 0, 0, ErrInvalidUserID
+	}
+	if !tType.Valid() {
+		return 0, 0, ErrInvalidTransactionType
+	}
+
+	amount, count, err := s.repository.SumOutgoingSince(ctx, userID, tType, currency, since)
+	if err != nil {
+		return 0, 0, ErrDatabaseFailure
+	}
+	return amount, count, nil
+}
+
+func (s *TransactionService) SearchTransactions(ctx context.Context, filter SearchFilter, limit, offset int) ([]Transaction, error) {
+	if filter.Type != "" && !filter.Type.Valid() {
+		return nil, ErrInvalidTransactionType
+	}
+	if filter.Status != "" && !filter.Status.Valid() {
+		return nil, ErrInvalidStatus
+	}
+	if !filter.From.IsZero() && !filter.To.IsZero() && !filter.From.Before(filter.To) {
+		return nil, ErrInvalidTimeRange
+	}
+	if filter.MinAmount < 0 || filter.MaxAmount < 0 || (filter.MaxAmount > 0 && filter.MinAmount > filter.MaxAmount) {
+		return nil, ErrInvalidAmountRange
+	}
+
+	txs, err := s.repository.SearchTransactions(ctx, filter, limit, offset)
+	if err != nil {
+		return nil, ErrDatabaseFailure
+	}
+	return txs, nil
+}
+
+// NewBatchID returns an identifier for a group of transactions created together.
+func NewBatchID() (string, error) {
+	return generateTransactionID()
+}
+
 func generateTransactionID() (string, error) {
 	id, err := uuid.NewV7()
 	return id.String(), err
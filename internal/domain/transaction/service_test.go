@@ -2,6 +2,7 @@ package transaction
 
 import (
 	"context"
+	"errors"
 	"testing"
 	"time"
 
@@ -23,11 +24,68 @@ func (m *MockTransactionRepository) ListTransactionsByUserID(ctx context.Context
 	return args.Get(0).([]Transaction), args.Error(1)
 }
 
+func (m *MockTransactionRepository) ListTransactionsByUserIDBefore(ctx context.Context, userID string, before Cursor, limit int) ([]Transaction, error) {
+	args := m.Called(ctx, userID, before, limit)
+	return args.Get(0).([]Transaction), args.Error(1)
+}
+
 func (m *MockTransactionRepository) GetTransactionByID(ctx context.Context, id string) (Transaction, error) {
 	args := m.Called(ctx, id)
 	return args.Get(0).(Transaction), args.Error(1)
 }
 
+func (m *MockTransactionRepository) LockTransactionByID(ctx context.Context, id string) (Transaction, error) {
+	args := m.Called(ctx, id)
+	return args.Get(0).(Transaction), args.Error(1)
+}
+
+func (m *MockTransactionRepository) UpdateTransactionStatus(ctx context.Context, tx Transaction) error {
+	args := m.Called(ctx, tx)
+	return args.Error(0)
+}
+
+func (m *MockTransactionRepository) ListTransactionsByOriginalID(ctx context.Context, originalID string) ([]Transaction, error) {
+	args := m.Called(ctx, originalID)
+	return args.Get(0).([]Transaction), args.Error(1)
+}
+
+func (m *MockTransactionRepository) StreamTransactionsByUserID(ctx context.Context, userID string, from, to time.Time, fn func(Transaction) error) error {
+	args := m.Called(ctx, userID, from, to, fn)
+	if txs, ok := args.Get(0).([]Transaction); ok {
+		for _, tx := range txs {
+			if err := fn(tx); err != nil {
+				return err
+			}
+		}
+	}
+	return args.Error(1)
+}
+
+func (m *MockTransactionRepository) SearchTransactions(ctx context.Context, filter SearchFilter, limit, offset int) ([]Transaction, error) {
+	args := m.Called(ctx, filter, limit, offset)
+	return args.Get(0).([]Transaction), args.Error(1)
+}
+
+func (m *MockTransactionRepository) SumNetAmountSince(ctx context.Context, userID string, since time.Time) (int64, error) {
+	args := m.Called(ctx, userID, since)
+	return args.Get(0).(int64), args.Error(1)
+}
+
+func (m *MockTransactionRepository) SumNetAmountBetween(ctx context.Context, userID string, from, to time.Time) (int64, error) {
+	args := m.Called(ctx, userID, from, to)
+	return args.Get(0).(int64), args.Error(1)
+}
+
+func (m *MockTransactionRepository) SumNetFlowBetween(ctx context.Context, from, to time.Time) (map[string]int64, error) {
+	args := m.Called(ctx, from, to)
+	return args.Get(0).(map[string]int64), args.Error(1)
+}
+
+func (m *MockTransactionRepository) SumOutgoingSince(ctx context.Context, userID string, tType TransactionType, currency string, since time.Time) (int64, int, error) {
+	args := m.Called(ctx, userID, tType, currency, since)
+	return args.Get(0).(int64), args.Int(1), args.Error(2)
+}
+
 func TestTransactionService_LogTransaction(t *testing.T) {
 	mockRepo := new(MockTransactionRepository)
 	service := NewTransactionService(mockRepo)
@@ -145,6 +203,20 @@ func TestTransactionService_LogTransaction(t *testing.T) {
 		mockRepo.AssertExpectations(t)
 	})
 
+	t.Run("successful log transaction with batch id", func(t *testing.T) {
+		mockRepo.On("CreateTransaction", mock.Anything, mock.MatchedBy(func(tx Transaction) bool {
+			return tx.BatchID == "batch1" && tx.Amount == 700
+		})).Return(nil)
+
+		tx, err := service.LogTransaction(ctx, "user1", "user2", 700, "USD", TransactionTypeTransfer, WithBatchID("batch1"))
+
+		assert.NoError(t, err)
+		assert.Equal(t, "batch1", tx.BatchID)
+		assert.NotEmpty(t, tx.ID)
+
+		mockRepo.AssertExpectations(t)
+	})
+
 	t.Run("invalid amount", func(t *testing.T) {
 		fromUserID := "user1"
 		toUserID := "user2"
@@ -258,6 +330,32 @@ func TestTransactionService_GetTransactionHistory(t *testing.T) {
 	})
 }
 
+func TestTransactionService_GetTransactionHistoryBefore(t *testing.T) {
+	mockRepo := new(MockTransactionRepository)
+	service := NewTransactionService(mockRepo)
+
+	ctx := context.Background()
+	last := Transaction{ID: "tx2", ToUserID: "user1", Amount: 500, Currency: "USD", Type: TransactionTypeDeposit, CreatedAt: time.Now()}
+
+	t.Run("continues after the cursor", func(t *testing.T) {
+		expectedTxs := []Transaction{{ID: "tx1", ToUserID: "user1", Amount: 1000, Currency: "USD", Type: TransactionTypeDeposit, CreatedAt: last.CreatedAt}}
+		mockRepo.On("ListTransactionsByUserIDBefore", ctx, "user1", Cursor{CreatedAt: last.CreatedAt, ID: "tx2"}, 10).Return(expectedTxs, nil)
+
+		txs, err := service.GetTransactionHistoryBefore(ctx, "user1", last.Cursor(), 10)
+
+		assert.NoError(t, err)
+		assert.Equal(t, expectedTxs, txs)
+		mockRepo.AssertExpectations(t)
+	})
+
+	t.Run("invalid user ID", func(t *testing.T) {
+		txs, err := service.GetTransactionHistoryBefore(ctx, "", last.Cursor(), 10)
+
+		assert.Equal(t, ErrInvalidUserID, err)
+		assert.Nil(t, txs)
+	})
+}
+
 func TestTransactionService_GetTransactionByID(t *testing.T) {
 	mockRepo := new(MockTransactionRepository)
 	service := NewTransactionService(mockRepo)
@@ -311,3 +409,387 @@ func TestTransactionService_GetTransactionByID(t *testing.T) {
 		mockRepo.AssertExpectations(t)
 	})
 }
+
+func TestTransactionService_LogUndo(t *testing.T) {
+	ctx := context.Background()
+	transfer := Transaction{ID: "tx1", FromUserID: "user1", ToUserID: "user2", Amount: 1000, Currency: "USD", Type: TransactionTypeTransfer, Status: StatusCompleted}
+	deposit := Transaction{ID: "tx2", ToUserID: "user1", Amount: 500, Currency: "USD", Type: TransactionTypeDeposit, Status: StatusCompleted}
+	refund := Transaction{ID: "tx3", FromUserID: "user2", ToUserID: "user1", Amount: 300, Currency: "USD", Type: TransactionTypeRefund, OriginalID: "tx1", Status: StatusCompleted}
+	pending := Transaction{ID: "tx4", FromUserID: "user1", Amount: 500, Currency: "USD", Type: TransactionTypeWithdraw, Status: StatusPending}
+
+	newService := func() (*TransactionService, *MockTransactionRepository) {
+		mockRepo := new(MockTransactionRepository)
+		mockRepo.On("LockTransactionByID", ctx, "tx1").Return(transfer, nil)
+		mockRepo.On("LockTransactionByID", ctx, "tx2").Return(deposit, nil)
+		mockRepo.On("LockTransactionByID", ctx, "tx3").Return(refund, nil)
+		mockRepo.On("LockTransactionByID", ctx, "tx4").Return(pending, nil)
+		mockRepo.On("LockTransactionByID", ctx, "missing").Return(Transaction{}, ErrTransactionNotFound)
+		mockRepo.On("ListTransactionsByOriginalID", ctx, "tx1").Return([]Transaction{refund}, nil)
+		mockRepo.On("ListTransactionsByOriginalID", ctx, "tx2").Return([]Transaction(nil), nil)
+		return NewTransactionService(mockRepo), mockRepo
+	}
+
+	t.Run("partial refund moves the funds back", func(t *testing.T) {
+		service, mockRepo := newService()
+		mockRepo.On("CreateTransaction", ctx, mock.AnythingOfType("Transaction")).Return(nil)
+
+		tx, err := service.LogUndo(ctx, "tx1", TransactionTypeRefund, 200)
+
+		assert.NoError(t, err)
+		assert.Equal(t, "user2", tx.FromUserID)
+		assert.Equal(t, "user1", tx.ToUserID)
+		assert.Equal(t, int64(200), tx.Amount)
+		assert.Equal(t, "USD", tx.Currency)
+		assert.Equal(t, TransactionTypeRefund, tx.Type)
+		assert.Equal(t, "tx1", tx.OriginalID)
+	})
+
+	t.Run("zero amount undoes what is left", func(t *testing.T) {
+		service, mockRepo := newService()
+		mockRepo.On("CreateTransaction", ctx, mock.AnythingOfType("Transaction")).Return(nil)
+
+		tx, err := service.LogUndo(ctx, "tx1", TransactionTypeReversal, 0)
+		assert.NoError(t, err)
+		assert.Equal(t, int64(700), tx.Amount)
+
+		tx, err = service.LogUndo(ctx, "tx2", TransactionTypeReversal, 0)
+		assert.NoError(t, err)
+		assert.Equal(t, "user1", tx.FromUserID)
+		assert.Empty(t, tx.ToUserID)
+		assert.Equal(t, int64(500), tx.Amount)
+	})
+
+	t.Run("rejected undos", func(t *testing.T) {
+		service, mockRepo := newService()
+
+		_, err := service.LogUndo(ctx, "tx1", TransactionTypeRefund, 701)
+		assert.Equal(t, ErrUndoExceedsOriginal, err)
+
+		_, err = service.LogUndo(ctx, "tx2", TransactionTypeRefund, 100)
+		assert.Equal(t, ErrNotUndoable, err, "only transfers are refunded")
+
+		_, err = service.LogUndo(ctx, "tx3", TransactionTypeReversal, 100)
+		assert.Equal(t, ErrNotUndoable, err, "refunds are not undone")
+
+		_, err = service.LogUndo(ctx, "tx4", TransactionTypeReversal, 100)
+		assert.Equal(t, ErrNotUndoable, err, "pending transactions are not undone")
+
+		_, err = service.LogUndo(ctx, "tx1", TransactionTypeTransfer, 100)
+		assert.Equal(t, ErrNotUndoable, err)
+
+		_, err = service.LogUndo(ctx, "tx1", TransactionTypeRefund, -1)
+		assert.Equal(t, ErrInvalidTransactionAmount, err)
+
+		_, err = service.LogUndo(ctx, "missing", TransactionTypeRefund, 100)
+		assert.Equal(t, ErrTransactionNotFound, err)
+
+		mockRepo.AssertNotCalled(t, "CreateTransaction", mock.Anything, mock.Anything)
+	})
+
+	t.Run("fully refunded", func(t *testing.T) {
+		mockRepo := new(MockTransactionRepository)
+		service := NewTransactionService(mockRepo)
+		mockRepo.On("LockTransactionByID", ctx, "tx1").Return(transfer, nil)
+		mockRepo.On("ListTransactionsByOriginalID", ctx, "tx1").Return([]Transaction{refund, {Amount: 700}}, nil)
+
+		_, err := service.LogUndo(ctx, "tx1", TransactionTypeRefund, 0)
+		assert.Equal(t, ErrUndoExceedsOriginal, err)
+	})
+}
+
+func TestTransactionService_TransitionStatus(t *testing.T) {
+	ctx := context.Background()
+	pending := Transaction{ID: "tx1", FromUserID: "user1", Amount: 500, Currency: "USD", Type: TransactionTypeWithdraw, Status: StatusPending}
+	completed := Transaction{ID: "tx2", ToUserID: "user1", Amount: 500, Currency: "USD", Type: TransactionTypeDeposit, Status: StatusCompleted}
+
+	newService := func() (*TransactionService, *MockTransactionRepository) {
+		mockRepo := new(MockTransactionRepository)
+		mockRepo.On("LockTransactionByID", ctx, "tx1").Return(pending, nil)
+		mockRepo.On("LockTransactionByID", ctx, "tx2").Return(completed, nil)
+		mockRepo.On("LockTransactionByID", ctx, "missing").Return(Transaction{}, ErrTransactionNotFound)
+		return NewTransactionService(mockRepo), mockRepo
+	}
+
+	t.Run("records the transition", func(t *testing.T) {
+		service, mockRepo := newService()
+		mockRepo.On("UpdateTransactionStatus", ctx, mock.MatchedBy(func(tx Transaction) bool {
+			return tx.ID == "tx1" && tx.Status == StatusProcessing && tx.ProcessingAt != nil
+		})).Return(nil)
+
+		tx, err := service.TransitionStatus(ctx, "tx1", StatusProcessing, "")
+
+		assert.NoError(t, err)
+		assert.Equal(t, StatusProcessing, tx.Status)
+		assert.WithinDuration(t, time.Now(), *tx.ProcessingAt, time.Second)
+		mockRepo.AssertCalled(t, "UpdateTransactionStatus", ctx, tx)
+	})
+
+	t.Run("rejected transitions", func(t *testing.T) {
+		service, mockRepo := newService()
+
+		_, err := service.TransitionStatus(ctx, "tx2", StatusCancelled, "")
+		assert.Equal(t, ErrInvalidStatusTransition, err)
+
+		_, err = service.TransitionStatus(ctx, "tx1", "settled", "")
+		assert.Equal(t, ErrInvalidStatus, err)
+
+		_, err = service.TransitionStatus(ctx, "missing", StatusFailed, "")
+		assert.Equal(t, ErrTransactionNotFound, err)
+
+		mockRepo.AssertNotCalled(t, "UpdateTransactionStatus", mock.Anything, mock.Anything)
+	})
+
+	t.Run("repository failure", func(t *testing.T) {
+		service, mockRepo := newService()
+		mockRepo.On("UpdateTransactionStatus", ctx, mock.Anything).Return(errors.New("connection reset"))
+
+		_, err := service.TransitionStatus(ctx, "tx1", StatusFailed, "")
+
+		assert.Equal(t, ErrDatabaseFailure, err)
+	})
+}
+
+func TestTransactionService_ReviewTransaction(t *testing.T) {
+	ctx := context.Background()
+	awaiting := Transaction{ID: "tx1", FromUserID: "user1", Amount: 5000, Currency: "USD", Type: TransactionTypeWithdraw, Status: StatusAwaitingApproval, StatusReason: "amount above the approval threshold"}
+	pending := Transaction{ID: "tx2", FromUserID: "user1", Amount: 500, Currency: "USD", Type: TransactionTypeWithdraw, Status: StatusPending}
+
+	newService := func() (*TransactionService, *MockTransactionRepository) {
+		mockRepo := new(MockTransactionRepository)
+		mockRepo.On("LockTransactionByID", ctx, "tx1").Return(awaiting, nil)
+		mockRepo.On("LockTransactionByID", ctx, "tx2").Return(pending, nil)
+		mockRepo.On("UpdateTransactionStatus", ctx, mock.Anything).Return(nil)
+		return NewTransactionService(mockRepo), mockRepo
+	}
+
+	t.Run("approval completes the transaction", func(t *testing.T) {
+		service, _ := newService()
+
+		tx, err := service.ReviewTransaction(ctx, "tx1", true, "")
+
+		assert.NoError(t, err)
+		assert.Equal(t, StatusCompleted, tx.Status)
+		assert.Empty(t, tx.StatusReason)
+		assert.NotNil(t, tx.CompletedAt)
+	})
+
+	t.Run("rejection fails it with the reason", func(t *testing.T) {
+		service, _ := newService()
+
+		tx, err := service.ReviewTransaction(ctx, "tx1", false, "unverified bank account")
+
+		assert.NoError(t, err)
+		assert.Equal(t, StatusFailed, tx.Status)
+		assert.Equal(t, "unverified bank account", tx.StatusReason)
+	})
+
+	t.Run("rejected reviews", func(t *testing.T) {
+		service, mockRepo := newService()
+
+		_, err := service.ReviewTransaction(ctx, "tx1", false, "")
+		assert.Equal(t, ErrReasonRequired, err)
+
+		_, err = service.ReviewTransaction(ctx, "tx2", true, "")
+		assert.Equal(t, ErrNotAwaitingApproval, err)
+
+		mockRepo.AssertNotCalled(t, "UpdateTransactionStatus", mock.Anything, mock.Anything)
+	})
+}
+
+func TestTransactionService_StreamTransactionHistory(t *testing.T) {
+	mockRepo := new(MockTransactionRepository)
+	service := NewTransactionService(mockRepo)
+
+	ctx := context.Background()
+	from := time.Date(2024, 3, 1, 0, 0, 0, 0, time.UTC)
+	to := time.Date(2024, 4, 1, 0, 0, 0, 0, time.UTC)
+
+	t.Run("successful stream", func(t *testing.T) {
+		userID := "user1"
+		expectedTxs := []Transaction{
+			{ID: "tx1", ToUserID: userID, Amount: 1000, Currency: "USD", Type: TransactionTypeDeposit},
+			{ID: "tx2", FromUserID: userID, Amount: 300, Currency: "USD", Type: TransactionTypeWithdraw},
+		}
+
+		mockRepo.On("StreamTransactionsByUserID", ctx, userID, from, to, mock.Anything).Return(expectedTxs, nil)
+
+		var got []Transaction
+		err := service.StreamTransactionHistory(ctx, userID, from, to, func(tx Transaction) error {
+			got = append(got, tx)
+			return nil
+		})
+
+		assert.NoError(t, err)
+		assert.Equal(t, expectedTxs, got)
+		mockRepo.AssertExpectations(t)
+	})
+
+	t.Run("invalid user ID", func(t *testing.T) {
+		err := service.StreamTransactionHistory(ctx, "", from, to, func(Transaction) error { return nil })
+
+		assert.Equal(t, ErrInvalidUserID, err)
+	})
+
+	t.Run("invalid time range", func(t *testing.T) {
+		err := service.StreamTransactionHistory(ctx, "user1", to, from, func(Transaction) error { return nil })
+
+		assert.Equal(t, ErrInvalidTimeRange, err)
+	})
+}
+
+func TestTransactionService_GetNetAmountSince(t *testing.T) {
+	mockRepo := new(MockTransactionRepository)
+	service := NewTransactionService(mockRepo)
+
+	ctx := context.Background()
+	since := time.Date(2024, 3, 1, 0, 0, 0, 0, time.UTC)
+
+	t.Run("successful sum", func(t *testing.T) {
+		mockRepo.On("SumNetAmountSince", ctx, "user1", since).Return(int64(700), nil)
+
+		net, err := service.GetNetAmountSince(ctx, "user1", since)
+
+		assert.NoError(t, err)
+		assert.Equal(t, int64(700), net)
+		mockRepo.AssertExpectations(t)
+	})
+
+	t.Run("repository failure", func(t *testing.T) {
+		mockRepo.On("SumNetAmountSince", ctx, "user2", since).Return(int64(0), errors.New("connection reset"))
+
+		net, err := service.GetNetAmountSince(ctx, "user2", since)
+
+		assert.Equal(t, ErrDatabaseFailure, err)
+		assert.Equal(t, int64(0), net)
+		mockRepo.AssertExpectations(t)
+	})
+}
+
+func TestTransactionService_GetNetAmountBetween(t *testing.T) {
+	mockRepo := new(MockTransactionRepository)
+	service := NewTransactionService(mockRepo)
+
+	ctx := context.Background()
+	from := time.Date(2024, 3, 1, 0, 0, 0, 0, time.UTC)
+	to := time.Date(2024, 3, 31, 23, 59, 0, 0, time.UTC)
+
+	t.Run("successful sum", func(t *testing.T) {
+		mockRepo.On("SumNetAmountBetween", ctx, "user1", from, to).Return(int64(-300), nil)
+
+		net, err := service.GetNetAmountBetween(ctx, "user1", from, to)
+
+		assert.NoError(t, err)
+		assert.Equal(t, int64(-300), net)
+		mockRepo.AssertExpectations(t)
+	})
+
+	t.Run("reversed range", func(t *testing.T) {
+		_, err := service.GetNetAmountBetween(ctx, "user1", to, from)
+
+		assert.Equal(t, ErrInvalidTimeRange, err)
+	})
+
+	t.Run("repository failure", func(t *testing.T) {
+		mockRepo.On("SumNetAmountBetween", ctx, "user2", from, to).Return(int64(0), errors.New("connection reset"))
+
+		_, err := service.GetNetAmountBetween(ctx, "user2", from, to)
+
+		assert.Equal(t, ErrDatabaseFailure, err)
+	})
+}
+
+func TestTransactionService_GetNetFlowBetween(t *testing.T) {
+	mockRepo := new(MockTransactionRepository)
+	service := NewTransactionService(mockRepo)
+
+	ctx := context.Background()
+	from := time.Date(2024, 3, 1, 0, 0, 0, 0, time.UTC)
+	to := from.AddDate(0, 0, 1)
+
+	t.Run("successful sum", func(t *testing.T) {
+		mockRepo.On("SumNetFlowBetween", ctx, from, to).Return(map[string]int64{"USD": 700, "EUR": -50}, nil)
+
+		flows, err := service.GetNetFlowBetween(ctx, from, to)
+
+		assert.NoError(t, err)
+		assert.Equal(t, map[string]int64{"USD": 700, "EUR": -50}, flows)
+	})
+
+	t.Run("reversed range", func(t *testing.T) {
+		_, err := service.GetNetFlowBetween(ctx, to, from)
+
+		assert.Equal(t, ErrInvalidTimeRange, err)
+	})
+
+	t.Run("repository failure", func(t *testing.T) {
+		mockRepo.On("SumNetFlowBetween", ctx, to, to).Return(map[string]int64(nil), errors.New("connection reset"))
+
+		_, err := service.GetNetFlowBetween(ctx, to, to)
+
+		assert.Equal(t, ErrDatabaseFailure, err)
+	})
+}
+
+func TestTransactionService_GetOutgoingSince(t *testing.T) {
+	mockRepo := new(MockTransactionRepository)
+	service := NewTransactionService(mockRepo)
+	ctx := context.Background()
+	since := time.Now().Add(-24 * time.Hour)
+
+	mockRepo.On("SumOutgoingSince", ctx, "user1", TransactionTypeWithdraw, "USD", since).Return(int64(700), 2, nil)
+	amount, count, err := service.GetOutgoingSince(ctx, "user1", TransactionTypeWithdraw, "USD", since)
+	assert.NoError(t, err)
+	assert.Equal(t, int64(700), amount)
+	assert.Equal(t, 2, count)
+
+	_, _, err = service.GetOutgoingSince(ctx, "user1", "GIFT", "USD", since)
+	assert.Equal(t, ErrInvalidTransactionType, err)
+
+	mockRepo.On("SumOutgoingSince", ctx, "user2", TransactionTypeTransfer, "USD", since).Return(int64(0), 0, errors.New("connection reset"))
+	_, _, err = service.GetOutgoingSince(ctx, "user2", TransactionTypeTransfer, "USD", since)
+	assert.Equal(t, ErrDatabaseFailure, err)
+	mockRepo.AssertExpectations(t)
+}
+
+func TestTransactionService_SearchTransactions(t *testing.T) {
+	mockRepo := new(MockTransactionRepository)
+	service := NewTransactionService(mockRepo)
+
+	ctx := context.Background()
+	from := time.Date(2024, 3, 1, 0, 0, 0, 0, time.UTC)
+
+	t.Run("successful search", func(t *testing.T) {
+		filter := SearchFilter{Type: TransactionTypeAdjustment, From: from, To: from.AddDate(0, 1, 0), MinAmount: 100}
+		expected := []Transaction{{ID: "tx1", Type: TransactionTypeAdjustment, Amount: 500}}
+		mockRepo.On("SearchTransactions", ctx, filter, 10, 0).Return(expected, nil)
+
+		txs, err := service.SearchTransactions(ctx, filter, 10, 0)
+
+		assert.NoError(t, err)
+		assert.Equal(t, expected, txs)
+		mockRepo.AssertExpectations(t)
+	})
+
+	t.Run("invalid filters", func(t *testing.T) {
+		_, err := service.SearchTransactions(ctx, SearchFilter{Type: "CHARGEBACK"}, 10, 0)
+		assert.Equal(t, ErrInvalidTransactionType, err)
+
+		_, err = service.SearchTransactions(ctx, SearchFilter{Status: "settled"}, 10, 0)
+		assert.Equal(t, ErrInvalidStatus, err)
+
+		_, err = service.SearchTransactions(ctx, SearchFilter{From: from, To: from}, 10, 0)
+		assert.Equal(t, ErrInvalidTimeRange, err)
+
+		_, err = service.SearchTransactions(ctx, SearchFilter{MinAmount: 500, MaxAmount: 100}, 10, 0)
+		assert.Equal(t, ErrInvalidAmountRange, err)
+	})
+
+	t.Run("repository failure", func(t *testing.T) {
+		filter := SearchFilter{UserID: "user2"}
+		mockRepo.On("SearchTransactions", ctx, filter, 10, 0).Return([]Transaction(nil), errors.New("connection reset"))
+
+		_, err := service.SearchTransactions(ctx, filter, 10, 0)
+
+		assert.Equal(t, ErrDatabaseFailure, err)
+	})
+}
@@ -0,0 +1,77 @@
+package user
+
+import (
+	"net/mail"
+	"strings"
+	"time"
+)
+
+// Status tells whether a user may open wallets and take part in transfers.
+type Status string
+
+const (
+	StatusActive      Status = "active"
+	StatusDeactivated Status = "deactivated"
+)
+
+func (s Status) Valid() bool {
+	return s == StatusActive || s == StatusDeactivated
+}
+
+// User is a registered owner of wallets.
+type User struct {
+	ID        string    // ID is the user's identifier, also the user ID of their wallet.
+	Name      string    // Name is the user's display name.
+	Email     string    // Email is unique among users and stored in lower case.
+	Status    Status    // Status is active unless an admin deactivated the user.
+	CreatedAt time.Time // CreatedAt is when the user registered.
+	UpdatedAt time.Time // UpdatedAt is when the profile or status last changed.
+}
+
+func NewUser(id, name, email string) (User, error) {
+	if strings.TrimSpace(id) == "" {
+		return User{}, ErrInvalidUserID
+	}
+	u := User{ID: id, Status: StatusActive}
+	if err := u.UpdateProfile(name, email); err != nil {
+		return User{}, err
+	}
+	u.CreatedAt = u.UpdatedAt
+	return u, nil
+}
+
+// UpdateProfile replaces the name and email; an empty value keeps the current one.
+func (u *User) UpdateProfile(name, email string) error {
+	if name = strings.TrimSpace(name); name == "" {
+		name = u.Name
+	}
+	if name == "" {
+		return ErrInvalidName
+	}
+	if email == "" {
+		email = u.Email
+	}
+	email, err := normalizeEmail(email)
+	if err != nil {
+		return err
+	}
+	u.Name = name
+	u.Email = email
+	u.UpdatedAt = time.Now()
+	return nil
+}
+
+func (u User) Active() bool {
+	return u.Status == StatusActive
+}
+
+// normalizeEmail accepts a bare address such as "alice@example.com", without a display
+// name, and returns it in lower case.
+func normalizeEmail(email string) (string, error) {
+	email = strings.TrimSpace(email)
+	addr, err := mail.ParseAddress(email)
+	if err != nil || addr.Address != email {
+		return "", ErrInvalidEmail
+	}
+	return strings.ToLower(email), nil
+}
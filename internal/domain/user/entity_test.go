@@ -0,0 +1,52 @@
+package user
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestNewUser(t *testing.T) {
+	u, err := NewUser("user1", " Alice Johnson ", "Alice.Johnson@Example.com")
+	require.NoError(t, err)
+	assert.Equal(t, "Alice Johnson", u.Name)
+	assert.Equal(t, "alice.johnson@example.com", u.Email)
+	assert.Equal(t, StatusActive, u.Status)
+	assert.True(t, u.Active())
+	assert.Equal(t, u.CreatedAt, u.UpdatedAt)
+
+	tests := []struct {
+		name             string
+		id, uName, email string
+		err              error
+	}{
+		{"missing id", "", "Alice", "alice@example.com", ErrInvalidUserID},
+		{"missing name", "user1", " ", "alice@example.com", ErrInvalidName},
+		{"missing email", "user1", "Alice", "", ErrInvalidEmail},
+		{"malformed email", "user1", "Alice", "alice@", ErrInvalidEmail},
+		{"email with display name", "user1", "Alice", "Alice <alice@example.com>", ErrInvalidEmail},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			_, err := NewUser(tt.id, tt.uName, tt.email)
+			assert.ErrorIs(t, err, tt.err)
+		})
+	}
+}
+
+func TestUser_UpdateProfile(t *testing.T) {
+	u, err := NewUser("user1", "Alice", "alice@example.com")
+	require.NoError(t, err)
+
+	require.NoError(t, u.UpdateProfile("", "ALICE@example.org"))
+	assert.Equal(t, "Alice", u.Name)
+	assert.Equal(t, "alice@example.org", u.Email)
+
+	require.NoError(t, u.UpdateProfile("Alice Johnson", ""))
+	assert.Equal(t, "Alice Johnson", u.Name)
+	assert.Equal(t, "alice@example.org", u.Email)
+
+	assert.ErrorIs(t, u.UpdateProfile("", "not an email"), ErrInvalidEmail)
+	assert.Equal(t, "alice@example.org", u.Email, "a rejected update changes nothing")
+}
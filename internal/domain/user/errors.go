@@ -0,0 +1,15 @@
+package user
+
+import "errors"
+
+var (
+	ErrInvalidUserID   = errors.New("invalid user ID")
+	ErrInvalidName     = errors.New("invalid user name")
+	ErrInvalidEmail    = errors.New("invalid email address")
+	ErrInvalidStatus   = errors.New("invalid user status")
+	ErrUserNotFound    = errors.New("user not found")
+	ErrUserExists      = errors.New("user already registered")
+	ErrEmailTaken      = errors.New("email address already registered")
+	ErrUserDeactivated = errors.New("user is deactivated")
+	ErrDatabaseFailure = errors.New("database failure")
+)
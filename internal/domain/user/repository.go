@@ -0,0 +1,13 @@
+package user
+
+import "context"
+
+type UserRepository interface {
+	// CreateUser returns ErrUserExists or ErrEmailTaken when the ID or email is in use.
+	CreateUser(ctx context.Context, u User) error
+
+	GetUserByID(ctx context.Context, id string) (User, error)
+
+	// UpdateUser returns ErrEmailTaken when another user has the email.
+	UpdateUser(ctx context.Context, u User) error
+}
@@ -0,0 +1,102 @@
+package user
+
+import (
+	"context"
+	"errors"
+	"time"
+)
+
+type UserServiceInterface interface {
+	Register(ctx context.Context, id, name, email string) (User, error)
+	GetUser(ctx context.Context, id string) (User, error)
+	UpdateProfile(ctx context.Context, id, name, email string) (User, error)
+	SetStatus(ctx context.Context, id string, status Status) (User, error)
+	CheckActive(ctx context.Context, id string) error
+}
+
+type UserService struct {
+	repository UserRepository
+}
+
+func NewUserService(repo UserRepository) *UserService {
+	return &UserService{
+		repository: repo,
+	}
+}
+
+func (s *UserService) Register(ctx context.Context, id, name, email string) (User, error) {
+	u, err := NewUser(id, name, email)
+	if err != nil {
+		return User{}, err
+	}
+	if err := s.repository.CreateUser(ctx, u); err != nil {
+		if errors.Is(err, ErrUserExists) || errors.Is(err, ErrEmailTaken) {
+			return User{}, err
+		}
+		return User{}, ErrDatabaseFailure
+	}
+	return u, nil
+}
+
+func (s *UserService) GetUser(ctx context.Context, id string) (User, error) {
+	u, err := s.repository.GetUserByID(ctx, id)
+	if err != nil {
+		if errors.Is(err, ErrUserNotFound) {
+			return User{}, ErrUserNotFound
+		}
+		return User{}, ErrDatabaseFailure
+	}
+	return u, nil
+}
+
+// UpdateProfile changes the user's name and email; an empty value keeps the current one.
+func (s *UserService) UpdateProfile(ctx context.Context, id, name, email string) (User, error) {
+	return s.update(ctx, id, func(u *User) error {
+		return u.UpdateProfile(name, email)
+	})
+}
+
+// SetStatus activates or deactivates the user. Deactivated users keep their wallets but
+// cannot open new ones or take part in transfers.
+func (s *UserService) SetStatus(ctx context.Context, id string, status Status) (User, error) {
+	if !status.Valid() {
+		return User{}, ErrInvalidStatus
+	}
+	return s.update(ctx, id, func(u *User) error {
+		if u.Status != status {
+			u.Status = status
+			u.UpdatedAt = time.Now()
+		}
+		return nil
+	})
+}
+
+// CheckActive returns ErrUserNotFound for an unknown user and ErrUserDeactivated for a
+// deactivated one.
+func (s *UserService) CheckActive(ctx context.Context, id string) error {
+	u, err := s.GetUser(ctx, id)
+	if err != nil {
+		return err
+	}
+	if !u.Active() {
+		return ErrUserDeactivated
+	}
+	return nil
+}
+
+func (s *UserService) update(ctx context.Context, id string, fn func(u *User) error) (User, error) {
+	u, err := s.GetUser(ctx, id)
+	if err != nil {
+		return User{}, err
+	}
+	if err := fn(&u); err != nil {
+		return User{}, err
+	}
+	if err := s.repository.UpdateUser(ctx, u); err != nil {
+		if errors.Is(err, ErrEmailTaken) {
+			return User{}, ErrEmailTaken
+		}
+		return User{}, ErrDatabaseFailure
+	}
+	return u, nil
+}
@@ -0,0 +1,138 @@
+package user
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+)
+
+type MockUserRepository struct {
+	mock.Mock
+}
+
+func (m *MockUserRepository) CreateUser(ctx context.Context, u User) error {
+	args := m.Called(ctx, u)
+	return args.Error(0)
+}
+
+func (m *MockUserRepository) GetUserByID(ctx context.Context, id string) (User, error) {
+	args := m.Called(ctx, id)
+	return args.Get(0).(User), args.Error(1)
+}
+
+func (m *MockUserRepository) UpdateUser(ctx context.Context, u User) error {
+	args := m.Called(ctx, u)
+	return args.Error(0)
+}
+
+func TestUserService_Register(t *testing.T) {
+	ctx := context.Background()
+
+	t.Run("success", func(t *testing.T) {
+		mockRepo := new(MockUserRepository)
+		mockRepo.On("CreateUser", ctx, mock.AnythingOfType("User")).Return(nil)
+
+		u, err := NewUserService(mockRepo).Register(ctx, "user1", "Alice", "alice@example.com")
+
+		require.NoError(t, err)
+		assert.Equal(t, "user1", u.ID)
+		mockRepo.AssertExpectations(t)
+	})
+
+	t.Run("email taken", func(t *testing.T) {
+		mockRepo := new(MockUserRepository)
+		mockRepo.On("CreateUser", ctx, mock.AnythingOfType("User")).Return(ErrEmailTaken)
+
+		_, err := NewUserService(mockRepo).Register(ctx, "user1", "Alice", "alice@example.com")
+
+		assert.ErrorIs(t, err, ErrEmailTaken)
+	})
+
+	t.Run("invalid email", func(t *testing.T) {
+		mockRepo := new(MockUserRepository)
+
+		_, err := NewUserService(mockRepo).Register(ctx, "user1", "Alice", "alice")
+
+		assert.ErrorIs(t, err, ErrInvalidEmail)
+		mockRepo.AssertNotCalled(t, "CreateUser", mock.Anything, mock.Anything)
+	})
+
+	t.Run("database failure", func(t *testing.T) {
+		mockRepo := new(MockUserRepository)
+		mockRepo.On("CreateUser", ctx, mock.AnythingOfType("User")).Return(errors.New("connection reset"))
+
+		_, err := NewUserService(mockRepo).Register(ctx, "user1", "Alice", "alice@example.com")
+
+		assert.ErrorIs(t, err, ErrDatabaseFailure)
+	})
+}
+
+func TestUserService_UpdateProfile(t *testing.T) {
+	ctx := context.Background()
+	alice := User{ID: "user1", Name: "Alice", Email: "alice@example.com", Status: StatusActive}
+
+	t.Run("success", func(t *testing.T) {
+		mockRepo := new(MockUserRepository)
+		mockRepo.On("GetUserByID", ctx, "user1").Return(alice, nil)
+		mockRepo.On("UpdateUser", ctx, mock.MatchedBy(func(u User) bool { return u.Name == "Alice Johnson" && u.Email == "alice@example.com" })).Return(nil)
+
+		u, err := NewUserService(mockRepo).UpdateProfile(ctx, "user1", "Alice Johnson", "")
+
+		require.NoError(t, err)
+		assert.Equal(t, "Alice Johnson", u.Name)
+		mockRepo.AssertExpectations(t)
+	})
+
+	t.Run("unknown user", func(t *testing.T) {
+		mockRepo := new(MockUserRepository)
+		mockRepo.On("GetUserByID", ctx, "nobody").Return(User{}, ErrUserNotFound)
+
+		_, err := NewUserService(mockRepo).UpdateProfile(ctx, "nobody", "Nobody", "")
+
+		assert.ErrorIs(t, err, ErrUserNotFound)
+	})
+
+	t.Run("email taken", func(t *testing.T) {
+		mockRepo := new(MockUserRepository)
+		mockRepo.On("GetUserByID", ctx, "user1").Return(alice, nil)
+		mockRepo.On("UpdateUser", ctx, mock.AnythingOfType("User")).Return(ErrEmailTaken)
+
+		_, err := NewUserService(mockRepo).UpdateProfile(ctx, "user1", "", "bob.smith@example.com")
+
+		assert.ErrorIs(t, err, ErrEmailTaken)
+	})
+}
+
+func TestUserService_SetStatus(t *testing.T) {
+	ctx := context.Background()
+	mockRepo := new(MockUserRepository)
+	service := NewUserService(mockRepo)
+	mockRepo.On("GetUserByID", ctx, "user1").Return(User{ID: "user1", Status: StatusActive}, nil)
+	mockRepo.On("UpdateUser", ctx, mock.MatchedBy(func(u User) bool { return u.Status == StatusDeactivated })).Return(nil)
+
+	u, err := service.SetStatus(ctx, "user1", StatusDeactivated)
+	require.NoError(t, err)
+	assert.False(t, u.Active())
+
+	_, err = service.SetStatus(ctx, "user1", "suspended")
+	assert.ErrorIs(t, err, ErrInvalidStatus)
+}
+
+func TestUserService_CheckActive(t *testing.T) {
+	ctx := context.Background()
+	mockRepo := new(MockUserRepository)
+	service := NewUserService(mockRepo)
+	mockRepo.On("GetUserByID", ctx, "user1").Return(User{ID: "user1", Status: StatusActive}, nil)
+	mockRepo.On("GetUserByID", ctx, "user2").Return(User{ID: "user2", Status: StatusDeactivated}, nil)
+	mockRepo.On("GetUserByID", ctx, "nobody").Return(User{}, ErrUserNotFound)
+	mockRepo.On("GetUserByID", ctx, "broken").Return(User{}, errors.New("connection reset"))
+
+	assert.NoError(t, service.CheckActive(ctx, "user1"))
+	assert.ErrorIs(t, service.CheckActive(ctx, "user2"), ErrUserDeactivated)
+	assert.ErrorIs(t, service.CheckActive(ctx, "nobody"), ErrUserNotFound)
+	assert.ErrorIs(t, service.CheckActive(ctx, "broken"), ErrDatabaseFailure)
+}
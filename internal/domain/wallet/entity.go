@@ -7,11 +7,20 @@ import (
 type Wallet struct {
 	UserID    string    // UserID is the unique identifier for the user (UUID).
 	Balance   int64     // Balance is the current balance of the wallet in the smallest unit of the currency.
+	Held      int64     // Held is the part of the balance reserved for pending withdrawals.
 	Currency  string    // Currency is the type of currency the wallet holds (e.g., USD, EUR).
 	CreatedAt time.Time // CreatedAt is the timestamp when the wallet was created.
 	UpdatedAt time.Time // UpdatedAt is the timestamp when the wallet was last updated.
 }
 
+// SearchFilter narrows a wallet search. Zero-valued fields match every wallet.
+type SearchFilter struct {
+	UserIDPrefix string // UserIDPrefix matches wallets whose user ID starts with it.
+	Currency     string // Currency matches wallets holding this currency.
+	MinBalance   *int64 // MinBalance matches wallets with at least this balance.
+	MaxBalance   *int64 // MaxBalance matches wallets with at most this balance.
+}
+
 func NewWallet(userID, currency string) Wallet {
 	return Wallet{
 		UserID:    userID,
@@ -31,3 +40,27 @@ func (w *Wallet) SubtractBalance(amount int64) {
 	w.Balance -= amount
 	w.UpdatedAt = time.Now()
 }
+
+// Available returns the part of the balance that is not held.
+func (w Wallet) Available() int64 {
+	return w.Balance - w.Held
+}
+
+// Hold reserves amount of the balance, for example until a withdrawal is paid out.
+func (w *Wallet) Hold(amount int64) {
+	w.Held += amount
+	w.UpdatedAt = time.Now()
+}
+
+// Release returns amount of the held funds to the available balance.
+func (w *Wallet) Release(amount int64) {
+	w.Held -= amount
+	w.UpdatedAt = time.Now()
+}
+
+// Capture removes amount of the held funds from the wallet.
+func (w *Wallet) Capture(amount int64) {
+	w.Held -= amount
+	w.Balance -= amount
+	w.UpdatedAt = time.Now()
+}
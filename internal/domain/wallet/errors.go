@@ -7,4 +7,6 @@ var (
 	ErrInsufficientFunds = errors.New("insufficient funds")
 	ErrInvalidAmount     = errors.New("invalid amount")
 	ErrDatabaseFailure   = errors.New("database failure")
+	ErrInvalidFilter     = errors.New("invalid wallet search filter")
+	ErrHeldFundsExceeded = errors.New("amount exceeds the held funds")
 )
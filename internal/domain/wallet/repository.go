@@ -9,5 +9,13 @@ type WalletRepository interface {
 
 	GetWalletByUserID(ctx context.Context, userID string) (Wallet, error)
 
+	// LockWalletByUserID is GetWalletByUserID that also locks the wallet until the
+	// surrounding database transaction ends, so concurrent checks against it run one after
+	// the other.
+	LockWalletByUserID(ctx context.Context, userID string) (Wallet, error)
+
 	UpdateWallet(ctx context.Context, w Wallet) error
+
+	// SearchWallets returns the wallets matching filter ordered by user ID.
+	SearchWallets(ctx context.Context, filter SearchFilter, limit, offset int) ([]Wallet, error)
 }
@@ -8,7 +8,13 @@ type WalletServiceInterface interface {
 	CreateNewWallet(ctx context.Context, userID, currency string) (Wallet, error)
 	Deposit(ctx context.Context, userID string, amount int64) error
 	Withdraw(ctx context.Context, userID string, amount int64) error
+	Hold(ctx context.Context, userID string, amount int64) error
+	Release(ctx context.Context, userID string, amount int64) error
+	Capture(ctx context.Context, userID string, amount int64) error
 	GetBalance(ctx context.Context, userID string) (int64, error)
+	GetWallet(ctx context.Context, userID string) (Wallet, error)
+	LockWallet(ctx context.Context, userID string) (Wallet, error)
+	SearchWallets(ctx context.Context, filter SearchFilter, limit, offset int) ([]Wallet, error)
 }
 
 type WalletService struct {
@@ -65,7 +71,7 @@ func (s *WalletService) Withdraw(ctx context.Context, userID string, amount int6
 		return err
 	}
 
-	if w.Balance < amount {
+	if w.Available() < amount {
 		return ErrInsufficientFunds
 	}
 
@@ -78,6 +84,61 @@ func (s *WalletService) Withdraw(ctx context.Context, userID string, amount int6
 	return nil
 }
 
+// Hold reserves amount of userID's available balance so it can no longer be withdrawn or
+// transferred, until it is released or captured.
+func (s *WalletService) Hold(ctx context.Context, userID string, amount int64) error {
+	return s.update(ctx, userID, amount, func(w *Wallet) error {
+		if w.Available() < amount {
+			return ErrInsufficientFunds
+		}
+		w.Hold(amount)
+		return nil
+	})
+}
+
+// Release makes amount of userID's held funds available again.
+func (s *WalletService) Release(ctx context.Context, userID string, amount int64) error {
+	return s.update(ctx, userID, amount, func(w *Wallet) error {
+		if w.Held < amount {
+			return ErrHeldFundsExceeded
+		}
+		w.Release(amount)
+		return nil
+	})
+}
+
+// Capture takes amount of userID's held funds out of the wallet.
+func (s *WalletService) Capture(ctx context.Context, userID string, amount int64) error {
+	return s.update(ctx, userID, amount, func(w *Wallet) error {
+		if w.Held < amount {
+			return ErrHeldFundsExceeded
+		}
+		w.Capture(amount)
+		return nil
+	})
+}
+
+// update applies fn to userID's wallet and stores the result.
+func (s *WalletService) update(ctx context.Context, userID string, amount int64, fn func(w *Wallet) error) error {
+	if amount <= 0 {
+		return ErrInvalidAmount
+	}
+
+	w, err := s.repository.GetWalletByUserID(ctx, userID)
+	if err != nil {
+		if err == ErrWalletNotFound {
+			return ErrWalletNotFound
+		}
+		return err
+	}
+
+	if err := fn(&w); err != nil {
+		return err
+	}
+
+	return s.repository.UpdateWallet(ctx, w)
+}
+
 func (s *WalletService) GetBalance(ctx context.Context, userID string) (int64, error) {
 	w, err := s.repository.GetWalletByUserID(ctx, userID)
 	if err != nil {
@@ -88,3 +149,39 @@ func (s *WalletService) GetBalance(ctx context.Context, userID string) (int64, e
 	}
 	return w.Balance, nil
 }
+
+func (s *WalletService) GetWallet(ctx context.Context, userID string) (Wallet, error) {
+	w, err := s.repository.GetWalletByUserID(ctx, userID)
+	if err != nil {
+		if err == ErrWalletNotFound {
+			return Wallet{}, ErrWalletNotFound
+		}
+		return Wallet{}, ErrDatabaseFailure
+	}
+	return w, nil
+}
+
+// LockWallet returns userID's wallet and locks it until the surrounding database
+// transaction ends; callers must run it inside one.
+func (s *WalletService) LockWallet(ctx context.Context, userID string) (Wallet, error) {
+	w, err := s.repository.LockWalletByUserID(ctx, userID)
+	if err != nil {
+		if err == ErrWalletNotFound {
+			return Wallet{}, ErrWalletNotFound
+		}
+		return Wallet{}, ErrDatabaseFailure
+	}
+	return w, nil
+}
+
+func (s *WalletService) SearchWallets(ctx context.Context, filter SearchFilter, limit, offset int) ([]Wallet, error) {
+	if filter.MinBalance != nil && filter.MaxBalance != nil && *filter.MinBalance > *filter.MaxBalance {
+		return nil, ErrInvalidFilter
+	}
+
+	wallets, err := s.repository.SearchWallets(ctx, filter, limit, offset)
+	if err != nil {
+		return nil, ErrDatabaseFailure
+	}
+	return wallets, nil
+}
@@ -2,6 +2,7 @@ package wallet
 
 import (
 	"context"
+	"errors"
 	"testing"
 	"time"
 
@@ -23,11 +24,21 @@ func (m *MockWalletRepository) GetWalletByUserID(ctx context.Context, userID str
 	return args.Get(0).(Wallet), args.Error(1)
 }
 
+func (m *MockWalletRepository) LockWalletByUserID(ctx context.Context, userID string) (Wallet, error) {
+	args := m.Called(ctx, userID)
+	return args.Get(0).(Wallet), args.Error(1)
+}
+
 func (m *MockWalletRepository) UpdateWallet(ctx context.Context, w Wallet) error {
 	args := m.Called(ctx, w)
 	return args.Error(0)
 }
 
+func (m *MockWalletRepository) SearchWallets(ctx context.Context, filter SearchFilter, limit, offset int) ([]Wallet, error) {
+	args := m.Called(ctx, filter, limit, offset)
+	return args.Get(0).([]Wallet), args.Error(1)
+}
+
 func TestWalletService_CreateNewWallet(t *testing.T) {
 	mockRepo := new(MockWalletRepository)
 	service := NewWalletService(mockRepo)
@@ -211,6 +222,54 @@ func TestWalletService_Withdraw(t *testing.T) {
 	})
 }
 
+func TestWalletService_Holds(t *testing.T) {
+	ctx := context.Background()
+	existingWallet := Wallet{UserID: "user123", Balance: 1000, Held: 300, Currency: "USD"}
+
+	newService := func() (*WalletService, *MockWalletRepository) {
+		mockRepo := new(MockWalletRepository)
+		mockRepo.On("GetWalletByUserID", ctx, "user123").Return(existingWallet, nil)
+		return NewWalletService(mockRepo), mockRepo
+	}
+	stores := func(balance, held int64) interface{} {
+		return mock.MatchedBy(func(w Wallet) bool { return w.Balance == balance && w.Held == held })
+	}
+
+	t.Run("hold reserves available funds", func(t *testing.T) {
+		service, mockRepo := newService()
+		mockRepo.On("UpdateWallet", ctx, stores(1000, 1000)).Return(nil)
+
+		assert.NoError(t, service.Hold(ctx, "user123", 700))
+		assert.Equal(t, ErrInsufficientFunds, service.Hold(ctx, "user123", 701))
+		mockRepo.AssertNumberOfCalls(t, "UpdateWallet", 1)
+	})
+
+	t.Run("held funds cannot be withdrawn", func(t *testing.T) {
+		service, _ := newService()
+
+		assert.Equal(t, ErrInsufficientFunds, service.Withdraw(ctx, "user123", 800))
+	})
+
+	t.Run("release makes held funds available", func(t *testing.T) {
+		service, mockRepo := newService()
+		mockRepo.On("UpdateWallet", ctx, stores(1000, 100)).Return(nil)
+
+		assert.NoError(t, service.Release(ctx, "user123", 200))
+		assert.Equal(t, ErrHeldFundsExceeded, service.Release(ctx, "user123", 301))
+		mockRepo.AssertNumberOfCalls(t, "UpdateWallet", 1)
+	})
+
+	t.Run("capture takes held funds out", func(t *testing.T) {
+		service, mockRepo := newService()
+		mockRepo.On("UpdateWallet", ctx, stores(700, 0)).Return(nil)
+
+		assert.NoError(t, service.Capture(ctx, "user123", 300))
+		assert.Equal(t, ErrHeldFundsExceeded, service.Capture(ctx, "user123", 301))
+		assert.Equal(t, ErrInvalidAmount, service.Capture(ctx, "user123", 0))
+		mockRepo.AssertNumberOfCalls(t, "UpdateWallet", 1)
+	})
+}
+
 // TestWalletService_GetBalance 測試 GetBalance 方法
 func TestWalletService_GetBalance(t *testing.T) {
 	mockRepo := new(MockWalletRepository)
@@ -252,3 +311,93 @@ func TestWalletService_GetBalance(t *testing.T) {
 		mockRepo.AssertExpectations(t)
 	})
 }
+
+func TestWalletService_GetWallet(t *testing.T) {
+	mockRepo := new(MockWalletRepository)
+	service := NewWalletService(mockRepo)
+
+	ctx := context.Background()
+
+	existingWallet := Wallet{
+		UserID:    "user123",
+		Balance:   1000,
+		Currency:  "USD",
+		CreatedAt: time.Now(),
+		UpdatedAt: time.Now(),
+	}
+
+	t.Run("successful get wallet", func(t *testing.T) {
+		mockRepo.On("GetWalletByUserID", ctx, "user123").Return(existingWallet, nil)
+
+		w, err := service.GetWallet(ctx, "user123")
+
+		assert.NoError(t, err)
+		assert.Equal(t, existingWallet, w)
+		mockRepo.AssertExpectations(t)
+	})
+
+	t.Run("wallet not found", func(t *testing.T) {
+		mockRepo.On("GetWalletByUserID", ctx, "userempty").Return(Wallet{}, ErrWalletNotFound)
+
+		w, err := service.GetWallet(ctx, "userempty")
+
+		assert.Equal(t, ErrWalletNotFound, err)
+		assert.Equal(t, Wallet{}, w)
+		mockRepo.AssertExpectations(t)
+	})
+}
+
+func TestWalletService_LockWallet(t *testing.T) {
+	mockRepo := new(MockWalletRepository)
+	service := NewWalletService(mockRepo)
+	ctx := context.Background()
+
+	mockRepo.On("LockWalletByUserID", ctx, "user123").Return(Wallet{UserID: "user123", Balance: 1000}, nil)
+	w, err := service.LockWallet(ctx, "user123")
+	assert.NoError(t, err)
+	assert.Equal(t, int64(1000), w.Balance)
+
+	mockRepo.On("LockWalletByUserID", ctx, "userempty").Return(Wallet{}, ErrWalletNotFound)
+	_, err = service.LockWallet(ctx, "userempty")
+	assert.Equal(t, ErrWalletNotFound, err)
+
+	mockRepo.On("LockWalletByUserID", ctx, "userbroken").Return(Wallet{}, errors.New("connection reset"))
+	_, err = service.LockWallet(ctx, "userbroken")
+	assert.Equal(t, ErrDatabaseFailure, err)
+	mockRepo.AssertExpectations(t)
+}
+
+func TestWalletService_SearchWallets(t *testing.T) {
+	mockRepo := new(MockWalletRepository)
+	service := NewWalletService(mockRepo)
+
+	ctx := context.Background()
+	low, high := int64(100), int64(5000)
+
+	t.Run("successful search", func(t *testing.T) {
+		filter := SearchFilter{UserIDPrefix: "user", MinBalance: &low, MaxBalance: &high}
+		expected := []Wallet{{UserID: "user123", Balance: 1000, Currency: "USD"}}
+		mockRepo.On("SearchWallets", ctx, filter, 20, 0).Return(expected, nil)
+
+		wallets, err := service.SearchWallets(ctx, filter, 20, 0)
+
+		assert.NoError(t, err)
+		assert.Equal(t, expected, wallets)
+		mockRepo.AssertExpectations(t)
+	})
+
+	t.Run("inverted balance range", func(t *testing.T) {
+		_, err := service.SearchWallets(ctx, SearchFilter{MinBalance: &high, MaxBalance: &low}, 20, 0)
+
+		assert.Equal(t, ErrInvalidFilter, err)
+	})
+
+	t.Run("repository failure", func(t *testing.T) {
+		filter := SearchFilter{Currency: "EUR"}
+		mockRepo.On("SearchWallets", ctx, filter, 20, 0).Return([]Wallet(nil), errors.New("connection reset"))
+
+		_, err := service.SearchWallets(ctx, filter, 20, 0)
+
+		assert.Equal(t, ErrDatabaseFailure, err)
+	})
+}
@@ -0,0 +1,268 @@
+package webhook
+
+import (
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"net/netip"
+	"net/url"
+	"slices"
+	"strconv"
+	"strings"
+	"time"
+
+	"exchange/internal/domain/event"
+)
+
+// Subscription asks for the events about a user's wallet to be POSTed to URL.
+type Subscription struct {
+	ID         string       // ID is the unique subscription identifier.
+	UserID     string       // UserID is the API client that owns the subscription.
+	URL        string       // URL receives the deliveries.
+	EventTypes []event.Type // EventTypes are the events delivered.
+	Secret     string       // Secret keys the HMAC signature of every delivery.
+	CreatedAt  time.Time    // CreatedAt is when the subscription was created.
+}
+
+func NewSubscription(id, userID, rawURL string, eventTypes []event.Type, secret string) (Subscription, error) {
+	if userID == "" {
+		return Subscription{}, ErrInvalidUserID
+	}
+	u, err := url.Parse(rawURL)
+	if err != nil || (u.Scheme != "http" && u.Scheme != "https") || u.Hostname() == "" || !publicHost(u.Hostname()) {
+		return Subscription{}, ErrInvalidURL
+	}
+	if len(eventTypes) == 0 {
+		return Subscription{}, ErrInvalidEventTypes
+	}
+	var types []event.Type
+	for _, t := range eventTypes {
+		if !t.Valid() {
+			return Subscription{}, ErrInvalidEventTypes
+		}
+		if !slices.Contains(types, t) {
+			types = append(types, t)
+		}
+	}
+	return Subscription{
+		ID:         id,
+		UserID:     userID,
+		URL:        u.String(),
+		EventTypes: types,
+		Secret:     secret,
+		CreatedAt:  time.Now(),
+	}, nil
+}
+
+// reservedPrefixes are the non-public ranges not covered by the netip.Addr predicates.
+var reservedPrefixes = []netip.Prefix{
+	netip.MustParsePrefix("0.0.0.0/8"),      // "this network"
+	netip.MustParsePrefix("100.64.0.0/10"),  // carrier-grade NAT
+	netip.MustParsePrefix("192.0.0.0/24"),   // IETF protocol assignments
+	netip.MustParsePrefix("198.18.0.0/15"),  // benchmarking
+	netip.MustParsePrefix("240.0.0.0/4"),    // reserved, including broadcast
+	netip.MustParsePrefix("64:ff9b::/96"),   // NAT64, which reaches IPv4 hosts
+	netip.MustParsePrefix("64:ff9b:1::/48"), // local-use NAT64
+}
+
+// PublicAddress reports whether deliveries may be sent to ip. Loopback, private,
+// link-local (including the 169.254.169.254 cloud metadata service), multicast and
+// other reserved addresses are refused, so subscribers can not make the server reach
+// internal services.
+func PublicAddress(ip netip.Addr) bool {
+	ip = ip.Unmap()
+	if !ip.IsGlobalUnicast() || ip.IsPrivate() {
+		return false
+	}
+	for _, prefix := range reservedPrefixes {
+		if prefix.Contains(ip) {
+			return false
+		}
+	}
+	return true
+}
+
+// publicHost rejects hosts that are known not to be public without resolving them.
+// Names are resolved, and their addresses checked, by the sender on every delivery.
+func publicHost(host string) bool {
+	if ip, err := netip.ParseAddr(host); err == nil {
+		return PublicAddress(ip)
+	}
+	host = strings.ToLower(strings.TrimSuffix(host, "."))
+	return host != "localhost" && !strings.HasSuffix(host, ".localhost")
+}
+
+// Matches reports whether e is of a subscribed type and about the owner's wallet.
+func (s Subscription) Matches(e event.Event) bool {
+	return slices.Contains(s.EventTypes, e.Type) && slices.Contains(e.UserIDs(), s.UserID)
+}
+
+type DeliveryStatus string
+
+const (
+	DeliveryPending   DeliveryStatus = "pending"   // Pending deliveries are sent when NextAttemptAt is reached.
+	DeliverySucceeded DeliveryStatus = "succeeded" // Succeeded deliveries got a 2xx response.
+	DeliveryDead      DeliveryStatus = "dead"      // Dead deliveries failed too often and are only sent again on replay.
+)
+
+func (s DeliveryStatus) Valid() bool {
+	return s == DeliveryPending || s == DeliverySucceeded || s == DeliveryDead
+}
+
+// Delivery is one event to be sent to one subscription.
+type Delivery struct {
+	ID             string         // ID is the unique delivery identifier.
+	SubscriptionID string         // SubscriptionID is where the event is sent.
+	UserID         string         // UserID is the owner of the subscription.
+	EventID        string         // EventID identifies the event; receivers use it to drop duplicates.
+	EventType      event.Type     // EventType is the type of the event.
+	Body           []byte         // Body is the JSON POSTed to the subscription URL.
+	Status         DeliveryStatus // Status is where the delivery is in its retry cycle.
+	Attempts       int            // Attempts counts the attempts since the delivery was created or replayed.
+	NextAttemptAt  time.Time      // NextAttemptAt is when a pending delivery is due.
+	LastError      string         // LastError describes the last failed attempt.
+	CreatedAt      time.Time      // CreatedAt is when the delivery was created.
+	UpdatedAt      time.Time      // UpdatedAt is when the delivery last changed.
+}
+
+// body is the JSON document POSTed for an event.
+type body struct {
+	ID         string          `json:"id"`
+	Type       event.Type      `json:"type"`
+	OccurredAt time.Time       `json:"occurred_at"`
+	Data       json.RawMessage `json:"data"`
+}
+
+// NewDelivery returns a delivery of e to s that is due immediately.
+func NewDelivery(id string, s Subscription, e event.Event) (Delivery, error) {
+	data, err := json.Marshal(body{ID: e.ID, Type: e.Type, OccurredAt: e.OccurredAt.UTC(), Data: e.Payload})
+	if err != nil {
+		return Delivery{}, err
+	}
+	now := time.Now()
+	return Delivery{
+		ID:             id,
+		SubscriptionID: s.ID,
+		UserID:         s.UserID,
+		EventID:        e.ID,
+		EventType:      e.Type,
+		Body:           data,
+		Status:         DeliveryPending,
+		NextAttemptAt:  now,
+		CreatedAt:      now,
+		UpdatedAt:      now,
+	}, nil
+}
+
+// Record applies the outcome of attempt a: success ends the delivery, failures are
+// retried with exponential backoff until policy.MaxAttempts is reached.
+func (d Delivery) Record(a Attempt, policy RetryPolicy) Delivery {
+	d.Attempts++
+	d.UpdatedAt = a.AttemptedAt
+	if a.Succeeded() {
+		d.Status = DeliverySucceeded
+		d.LastError = ""
+		return d
+	}
+
+	d.LastError = a.Error
+	if d.Attempts >= policy.MaxAttempts {
+		d.Status = DeliveryDead
+		return d
+	}
+	d.NextAttemptAt = a.AttemptedAt.Add(policy.Delay(d.Attempts))
+	return d
+}
+
+// Claim holds a pending delivery back from other dispatchers while it is sent. It
+// becomes due again once lease has passed, so a delivery whose attempt was never
+// recorded, because its dispatcher stopped, is retried.
+func (d Delivery) Claim(now time.Time, lease time.Duration) Delivery {
+	d.NextAttemptAt = now.Add(lease)
+	return d
+}
+
+// Replay makes a delivery that is not pending due again with a fresh retry budget.
+func (d Delivery) Replay(now time.Time) (Delivery, error) {
+	if d.Status == DeliveryPending {
+		return Delivery{}, ErrDeliveryPending
+	}
+	d.Status = DeliveryPending
+	d.Attempts = 0
+	d.NextAttemptAt = now
+	d.UpdatedAt = now
+	return d, nil
+}
+
+// Attempt records one POST of a delivery.
+type Attempt struct {
+	DeliveryID  string        // DeliveryID is the delivery that was sent.
+	AttemptedAt time.Time     // AttemptedAt is when the request was sent.
+	StatusCode  int           // StatusCode is the response status, or 0 if no response was received.
+	Error       string        // Error describes why the attempt failed.
+	Duration    time.Duration // Duration is how long the request took.
+}
+
+func (a Attempt) Succeeded() bool {
+	return a.StatusCode >= 200 && a.StatusCode < 300
+}
+
+// RetryPolicy bounds how often and how fast failed deliveries are retried.
+type RetryPolicy struct {
+	MaxAttempts int           // MaxAttempts is the number of failures after which a delivery is dead.
+	BaseDelay   time.Duration // BaseDelay is the wait after the first failure; it doubles after each further one.
+	MaxDelay    time.Duration // MaxDelay caps the wait between attempts.
+}
+
+// DefaultRetryPolicy retries for about a day before giving up.
+var DefaultRetryPolicy = RetryPolicy{MaxAttempts: 10, BaseDelay: 30 * time.Second, MaxDelay: 6 * time.Hour}
+
+// Delay returns the wait after the given number of failed attempts.
+func (p RetryPolicy) Delay(failures int) time.Duration {
+	delay := p.BaseDelay
+	for i := 1; i < failures && delay < p.MaxDelay; i++ {
+		delay *= 2
+	}
+	return min(delay, p.MaxDelay)
+}
+
+// Request is a signed delivery ready to be sent.
+type Request struct {
+	URL       string
+	EventID   string
+	EventType event.Type
+	Timestamp int64
+	Signature string
+	Body      []byte
+}
+
+// NewRequest signs d with the subscription secret at the given unix time.
+func NewRequest(s Subscription, d Delivery, timestamp int64) Request {
+	return Request{
+		URL:       s.URL,
+		EventID:   d.EventID,
+		EventType: d.EventType,
+		Timestamp: timestamp,
+		Signature: Sign(s.Secret, timestamp, d.Body),
+		Body:      d.Body,
+	}
+}
+
+// Sign returns the hex HMAC-SHA256 of "{timestamp}.{body}" keyed with secret. Receivers
+// recompute it to check that a delivery came from us and was not altered or replayed
+// late.
+func Sign(secret string, timestamp int64, body []byte) string {
+	mac := hmac.New(sha256.New, []byte(secret))
+	mac.Write([]byte(strconv.FormatInt(timestamp, 10)))
+	mac.Write([]byte("."))
+	mac.Write(body)
+	return hex.EncodeToString(mac.Sum(nil))
+}
+
+// DeliveryFilter narrows a delivery listing; zero fields match everything.
+type DeliveryFilter struct {
+	UserID         string
+	SubscriptionID string
+	Status         DeliveryStatus
+}
@@ -0,0 +1,138 @@
+package webhook
+
+import (
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"net/netip"
+	"testing"
+	"time"
+
+	"exchange/internal/domain/event"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestNewSubscription(t *testing.T) {
+	s, err := NewSubscription("sub1", "user1", "https://partner.test/hooks", []event.Type{event.TypeFundsDeposited, event.TypeFundsDeposited}, "secret")
+	require.NoError(t, err)
+	assert.Equal(t, []event.Type{event.TypeFundsDeposited}, s.EventTypes)
+
+	tests := []struct {
+		name   string
+		userID string
+		url    string
+		types  []event.Type
+		err    error
+	}{
+		{"missing user", "", "https://partner.test", []event.Type{event.TypeFundsDeposited}, ErrInvalidUserID},
+		{"relative url", "user1", "/hooks", []event.Type{event.TypeFundsDeposited}, ErrInvalidURL},
+		{"unsupported scheme", "user1", "ftp://partner.test", []event.Type{event.TypeFundsDeposited}, ErrInvalidURL},
+		{"localhost", "user1", "http://localhost:8080/hooks", []event.Type{event.TypeFundsDeposited}, ErrInvalidURL},
+		{"loopback address", "user1", "http://127.0.0.1/hooks", []event.Type{event.TypeFundsDeposited}, ErrInvalidURL},
+		{"private address", "user1", "https://10.0.0.5/hooks", []event.Type{event.TypeFundsDeposited}, ErrInvalidURL},
+		{"metadata service", "user1", "http://169.254.169.254/latest/meta-data", []event.Type{event.TypeFundsDeposited}, ErrInvalidURL},
+		{"ipv6 loopback", "user1", "http://[::1]/hooks", []event.Type{event.TypeFundsDeposited}, ErrInvalidURL},
+		{"no event types", "user1", "https://partner.test", nil, ErrInvalidEventTypes},
+		{"unknown event type", "user1", "https://partner.test", []event.Type{"FundsLost"}, ErrInvalidEventTypes},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			_, err := NewSubscription("sub1", tt.userID, tt.url, tt.types, "secret")
+			assert.Equal(t, tt.err, err)
+		})
+	}
+}
+
+func TestPublicAddress(t *testing.T) {
+	for _, addr := range []string{"93.184.216.34", "2606:2800:220:1:248:1893:25c8:1946"} {
+		assert.True(t, PublicAddress(netip.MustParseAddr(addr)), addr)
+	}
+	for _, addr := range []string{
+		"0.0.0.0", "127.0.0.1", "10.1.2.3", "172.16.0.1", "192.168.1.1", "169.254.169.254", "100.64.0.1",
+		"224.0.0.1", "255.255.255.255", "::", "::1", "fe80::1", "fd00::1", "::ffff:127.0.0.1", "64:ff9b::a00:1",
+	} {
+		assert.False(t, PublicAddress(netip.MustParseAddr(addr)), addr)
+	}
+}
+
+func TestSubscription_Matches(t *testing.T) {
+	s := Subscription{UserID: "user2", EventTypes: []event.Type{event.TypeFundsTransferred}}
+	incoming, err := event.NewEvent("ev1", event.TypeFundsTransferred, "user1", event.FundsTransferred{FromUserID: "user1", ToUserID: "user2"})
+	require.NoError(t, err)
+	unrelated, err := event.NewEvent("ev2", event.TypeFundsTransferred, "user1", event.FundsTransferred{FromUserID: "user1", ToUserID: "user3"})
+	require.NoError(t, err)
+	deposit, err := event.NewEvent("ev3", event.TypeFundsDeposited, "user2", event.FundsDeposited{UserID: "user2"})
+	require.NoError(t, err)
+
+	assert.True(t, s.Matches(incoming))
+	assert.False(t, s.Matches(unrelated))
+	assert.False(t, s.Matches(deposit))
+}
+
+func TestNewDelivery(t *testing.T) {
+	e, err := event.NewEvent("ev1", event.TypeFundsDeposited, "user1", event.FundsDeposited{UserID: "user1", Amount: 100})
+	require.NoError(t, err)
+
+	d, err := NewDelivery("d1", Subscription{ID: "sub1", UserID: "user1"}, e)
+	require.NoError(t, err)
+	assert.Equal(t, DeliveryPending, d.Status)
+	assert.Equal(t, "user1", d.UserID)
+
+	var body struct {
+		ID   string          `json:"id"`
+		Type string          `json:"type"`
+		Data json.RawMessage `json:"data"`
+	}
+	require.NoError(t, json.Unmarshal(d.Body, &body))
+	assert.Equal(t, "ev1", body.ID)
+	assert.Equal(t, "FundsDeposited", body.Type)
+	assert.JSONEq(t, string(e.Payload), string(body.Data))
+}
+
+func TestDelivery_Record(t *testing.T) {
+	policy := RetryPolicy{MaxAttempts: 3, BaseDelay: time.Minute, MaxDelay: 90 * time.Second}
+	now := time.Now()
+	failed := Attempt{AttemptedAt: now, StatusCode: 503, Error: "503 Service Unavailable"}
+
+	d := Delivery{Status: DeliveryPending}
+	d = d.Record(failed, policy)
+	assert.Equal(t, DeliveryPending, d.Status)
+	assert.Equal(t, now.Add(time.Minute), d.NextAttemptAt)
+	assert.Equal(t, "503 Service Unavailable", d.LastError)
+
+	d = d.Record(failed, policy)
+	assert.Equal(t, now.Add(90*time.Second), d.NextAttemptAt, "the delay is capped")
+
+	d = d.Record(failed, policy)
+	assert.Equal(t, DeliveryDead, d.Status)
+	assert.Equal(t, 3, d.Attempts)
+
+	d, err := d.Replay(now)
+	require.NoError(t, err)
+	assert.Equal(t, DeliveryPending, d.Status)
+	assert.Zero(t, d.Attempts)
+
+	_, err = d.Replay(now)
+	assert.Equal(t, ErrDeliveryPending, err)
+
+	d = d.Record(Attempt{AttemptedAt: now, StatusCode: 204}, policy)
+	assert.Equal(t, DeliverySucceeded, d.Status)
+	assert.Empty(t, d.LastError)
+}
+
+func TestRetryPolicy_Delay(t *testing.T) {
+	assert.Equal(t, 30*time.Second, DefaultRetryPolicy.Delay(1))
+	assert.Equal(t, 4*time.Minute, DefaultRetryPolicy.Delay(4))
+	assert.Equal(t, 6*time.Hour, DefaultRetryPolicy.Delay(30))
+}
+
+func TestSign(t *testing.T) {
+	mac := hmac.New(sha256.New, []byte("whsec_test"))
+	mac.Write([]byte(`1700000000.{"id":"ev1"}`))
+
+	assert.Equal(t, hex.EncodeToString(mac.Sum(nil)), Sign("whsec_test", 1700000000, []byte(`{"id":"ev1"}`)))
+	assert.NotEqual(t, Sign("whsec_test", 1700000000, []byte(`{"id":"ev1"}`)), Sign("whsec_test", 1700000001, []byte(`{"id":"ev1"}`)))
+}
@@ -0,0 +1,15 @@
+package webhook
+
+import "errors"
+
+var (
+	ErrInvalidUserID        = errors.New("invalid user ID")
+	ErrInvalidURL           = errors.New("invalid webhook URL")
+	ErrNonPublicAddress     = errors.New("webhook URL resolves to a non-public address")
+	ErrInvalidEventTypes    = errors.New("invalid webhook event types")
+	ErrInvalidStatus        = errors.New("invalid delivery status")
+	ErrSubscriptionNotFound = errors.New("webhook subscription not found")
+	ErrDeliveryNotFound     = errors.New("webhook delivery not found")
+	ErrDeliveryPending      = errors.New("webhook delivery is already pending")
+	ErrDatabaseFailure      = errors.New("database failure")
+)
@@ -0,0 +1,44 @@
+package webhook
+
+import (
+	"context"
+	"time"
+
+	"exchange/internal/domain/event"
+)
+
+type SubscriptionRepository interface {
+	CreateSubscription(ctx context.Context, s Subscription) error
+
+	GetSubscriptionByID(ctx context.Context, id string) (Subscription, error)
+
+	// ListSubscriptionsByUserID returns the user's subscriptions, oldest first.
+	ListSubscriptionsByUserID(ctx context.Context, userID string) ([]Subscription, error)
+
+	// ListSubscriptionsByEventType returns every subscription to events of type t.
+	ListSubscriptionsByEventType(ctx context.Context, t event.Type) ([]Subscription, error)
+
+	// DeleteSubscription removes a subscription together with its deliveries.
+	DeleteSubscription(ctx context.Context, id string) error
+}
+
+type DeliveryRepository interface {
+	CreateDelivery(ctx context.Context, d Delivery) error
+
+	GetDeliveryByID(ctx context.Context, id string) (Delivery, error)
+
+	// ListDeliveries returns the deliveries matching filter, newest first.
+	ListDeliveries(ctx context.Context, filter DeliveryFilter, limit, offset int) ([]Delivery, error)
+
+	// ListDueDeliveries returns up to limit pending deliveries due at now, oldest first,
+	// and locks them until the surrounding transaction ends; deliveries locked by another
+	// dispatcher are skipped.
+	ListDueDeliveries(ctx context.Context, now time.Time, limit int) ([]Delivery, error)
+
+	UpdateDelivery(ctx context.Context, d Delivery) error
+
+	RecordAttempt(ctx context.Context, a Attempt) error
+
+	// ListAttempts returns the attempts of a delivery, oldest first.
+	ListAttempts(ctx context.Context, deliveryID string) ([]Attempt, error)
+}
@@ -0,0 +1,9 @@
+package webhook
+
+import "context"
+
+// Sender POSTs signed deliveries to subscribers.
+type Sender interface {
+	// Send returns the response status code, or an error if no response was received.
+	Send(ctx context.Context, req Request) (int, error)
+}
@@ -0,0 +1,214 @@
+package webhook
+
+import (
+	"context"
+	"crypto/rand"
+	"encoding/hex"
+	"errors"
+	"time"
+
+	"exchange/internal/domain/event"
+
+	"github.com/gofrs/uuid"
+)
+
+type WebhookServiceInterface interface {
+	CreateSubscription(ctx context.Context, userID, url string, eventTypes []event.Type) (Subscription, error)
+	GetSubscription(ctx context.Context, id string) (Subscription, error)
+	ListSubscriptions(ctx context.Context, userID string) ([]Subscription, error)
+	DeleteSubscription(ctx context.Context, userID, id string) error
+	Enqueue(ctx context.Context, e event.Event) error
+	GetDelivery(ctx context.Context, userID, id string) (Delivery, error)
+	ListDeliveries(ctx context.Context, filter DeliveryFilter, limit, offset int) ([]Delivery, error)
+	ListAttempts(ctx context.Context, userID, deliveryID string) ([]Attempt, error)
+	ReplayDelivery(ctx context.Context, userID, id string) (Delivery, error)
+	ClaimDueDeliveries(ctx context.Context, limit int, lease time.Duration) ([]Delivery, error)
+	RecordAttempt(ctx context.Context, d Delivery, a Attempt) (Delivery, error)
+}
+
+type WebhookService struct {
+	subscriptions SubscriptionRepository
+	deliveries    DeliveryRepository
+	policy        RetryPolicy
+	now           func() time.Time
+}
+
+func NewWebhookService(subs SubscriptionRepository, deliveries DeliveryRepository, policy RetryPolicy) *WebhookService {
+	return &WebhookService{
+		subscriptions: subs,
+		deliveries:    deliveries,
+		policy:        policy,
+		now:           time.Now,
+	}
+}
+
+// CreateSubscription registers url for userID's events of the given types. The returned
+// subscription carries the generated signing secret.
+func (s *WebhookService) CreateSubscription(ctx context.Context, userID, url string, eventTypes []event.Type) (Subscription, error) {
+	id, err := uuid.NewV7()
+	if err != nil {
+		return Subscription{}, err
+	}
+	secret := make([]byte, 32)
+	if _, err := rand.Read(secret); err != nil {
+		return Subscription{}, err
+	}
+
+	sub, err := NewSubscription(id.String(), userID, url, eventTypes, "whsec_"+hex.EncodeToString(secret))
+	if err != nil {
+		return Subscription{}, err
+	}
+	if err := s.subscriptions.CreateSubscription(ctx, sub); err != nil {
+		return Subscription{}, ErrDatabaseFailure
+	}
+	return sub, nil
+}
+
+func (s *WebhookService) GetSubscription(ctx context.Context, id string) (Subscription, error) {
+	sub, err := s.subscriptions.GetSubscriptionByID(ctx, id)
+	if err != nil {
+		if errors.Is(err, ErrSubscriptionNotFound) {
+			return Subscription{}, ErrSubscriptionNotFound
+		}
+		return Subscription{}, ErrDatabaseFailure
+	}
+	return sub, nil
+}
+
+func (s *WebhookService) ListSubscriptions(ctx context.Context, userID string) ([]Subscription, error) {
+	subs, err := s.subscriptions.ListSubscriptionsByUserID(ctx, userID)
+	if err != nil {
+		return nil, ErrDatabaseFailure
+	}
+	return subs, nil
+}
+
+// DeleteSubscription removes one of userID's subscriptions. Other users' subscriptions
+// are reported as not found.
+func (s *WebhookService) DeleteSubscription(ctx context.Context, userID, id string) error {
+	sub, err := s.GetSubscription(ctx, id)
+	if err != nil {
+		return err
+	}
+	if sub.UserID != userID {
+		return ErrSubscriptionNotFound
+	}
+	if err := s.subscriptions.DeleteSubscription(ctx, id); err != nil {
+		return ErrDatabaseFailure
+	}
+	return nil
+}
+
+// Enqueue creates a delivery of e for every matching subscription. Call it in the
+// database transaction that consumes e from the outbox.
+func (s *WebhookService) Enqueue(ctx context.Context, e event.Event) error {
+	subs, err := s.subscriptions.ListSubscriptionsByEventType(ctx, e.Type)
+	if err != nil {
+		return ErrDatabaseFailure
+	}
+
+	for _, sub := range subs {
+		if !sub.Matches(e) {
+			continue
+		}
+		id, err := uuid.NewV7()
+		if err != nil {
+			return err
+		}
+		d, err := NewDelivery(id.String(), sub, e)
+		if err != nil {
+			return err
+		}
+		if err := s.deliveries.CreateDelivery(ctx, d); err != nil {
+			return ErrDatabaseFailure
+		}
+	}
+	return nil
+}
+
+// GetDelivery returns one of userID's deliveries. Other users' deliveries are reported
+// as not found.
+func (s *WebhookService) GetDelivery(ctx context.Context, userID, id string) (Delivery, error) {
+	d, err := s.deliveries.GetDeliveryByID(ctx, id)
+	if err != nil {
+		if errors.Is(err, ErrDeliveryNotFound) {
+			return Delivery{}, ErrDeliveryNotFound
+		}
+		return Delivery{}, ErrDatabaseFailure
+	}
+	if d.UserID != userID {
+		return Delivery{}, ErrDeliveryNotFound
+	}
+	return d, nil
+}
+
+func (s *WebhookService) ListDeliveries(ctx context.Context, filter DeliveryFilter, limit, offset int) ([]Delivery, error) {
+	if filter.Status != "" && !filter.Status.Valid() {
+		return nil, ErrInvalidStatus
+	}
+
+	deliveries, err := s.deliveries.ListDeliveries(ctx, filter, limit, offset)
+	if err != nil {
+		return nil, ErrDatabaseFailure
+	}
+	return deliveries, nil
+}
+
+func (s *WebhookService) ListAttempts(ctx context.Context, userID, deliveryID string) ([]Attempt, error) {
+	if _, err := s.GetDelivery(ctx, userID, deliveryID); err != nil {
+		return nil, err
+	}
+
+	attempts, err := s.deliveries.ListAttempts(ctx, deliveryID)
+	if err != nil {
+		return nil, ErrDatabaseFailure
+	}
+	return attempts, nil
+}
+
+// ReplayDelivery sends one of userID's succeeded or dead deliveries again.
+func (s *WebhookService) ReplayDelivery(ctx context.Context, userID, id string) (Delivery, error) {
+	d, err := s.GetDelivery(ctx, userID, id)
+	if err != nil {
+		return Delivery{}, err
+	}
+	d, err = d.Replay(s.now())
+	if err != nil {
+		return Delivery{}, err
+	}
+	if err := s.deliveries.UpdateDelivery(ctx, d); err != nil {
+		return Delivery{}, ErrDatabaseFailure
+	}
+	return d, nil
+}
+
+// ClaimDueDeliveries returns up to limit due deliveries and claims them for lease, so
+// that they can be sent outside the transaction without another dispatcher picking them
+// up meanwhile.
+func (s *WebhookService) ClaimDueDeliveries(ctx context.Context, limit int, lease time.Duration) ([]Delivery, error) {
+	now := s.now()
+	deliveries, err := s.deliveries.ListDueDeliveries(ctx, now, limit)
+	if err != nil {
+		return nil, ErrDatabaseFailure
+	}
+	for i, d := range deliveries {
+		deliveries[i] = d.Claim(now, lease)
+		if err := s.deliveries.UpdateDelivery(ctx, deliveries[i]); err != nil {
+			return nil, ErrDatabaseFailure
+		}
+	}
+	return deliveries, nil
+}
+
+// RecordAttempt stores attempt a of d and schedules the next one according to the retry
+// policy.
+func (s *WebhookService) RecordAttempt(ctx context.Context, d Delivery, a Attempt) (Delivery, error) {
+	d = d.Record(a, s.policy)
+	if err := s.deliveries.RecordAttempt(ctx, a); err != nil {
+		return Delivery{}, ErrDatabaseFailure
+	}
+	if err := s.deliveries.UpdateDelivery(ctx, d); err != nil {
+		return Delivery{}, ErrDatabaseFailure
+	}
+	return d, nil
+}
@@ -0,0 +1,179 @@
+package webhook
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"exchange/internal/domain/event"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+)
+
+type MockSubscriptionRepository struct {
+	mock.Mock
+}
+
+func (m *MockSubscriptionRepository) CreateSubscription(ctx context.Context, s Subscription) error {
+	args := m.Called(ctx, s)
+	return args.Error(0)
+}
+
+func (m *MockSubscriptionRepository) GetSubscriptionByID(ctx context.Context, id string) (Subscription, error) {
+	args := m.Called(ctx, id)
+	return args.Get(0).(Subscription), args.Error(1)
+}
+
+func (m *MockSubscriptionRepository) ListSubscriptionsByUserID(ctx context.Context, userID string) ([]Subscription, error) {
+	args := m.Called(ctx, userID)
+	return args.Get(0).([]Subscription), args.Error(1)
+}
+
+func (m *MockSubscriptionRepository) ListSubscriptionsByEventType(ctx context.Context, t event.Type) ([]Subscription, error) {
+	args := m.Called(ctx, t)
+	return args.Get(0).([]Subscription), args.Error(1)
+}
+
+func (m *MockSubscriptionRepository) DeleteSubscription(ctx context.Context, id string) error {
+	args := m.Called(ctx, id)
+	return args.Error(0)
+}
+
+type MockDeliveryRepository struct {
+	mock.Mock
+}
+
+func (m *MockDeliveryRepository) CreateDelivery(ctx context.Context, d Delivery) error {
+	args := m.Called(ctx, d)
+	return args.Error(0)
+}
+
+func (m *MockDeliveryRepository) GetDeliveryByID(ctx context.Context, id string) (Delivery, error) {
+	args := m.Called(ctx, id)
+	return args.Get(0).(Delivery), args.Error(1)
+}
+
+func (m *MockDeliveryRepository) ListDeliveries(ctx context.Context, filter DeliveryFilter, limit, offset int) ([]Delivery, error) {
+	args := m.Called(ctx, filter, limit, offset)
+	return args.Get(0).([]Delivery), args.Error(1)
+}
+
+func (m *MockDeliveryRepository) ListDueDeliveries(ctx context.Context, now time.Time, limit int) ([]Delivery, error) {
+	args := m.Called(ctx, now, limit)
+	return args.Get(0).([]Delivery), args.Error(1)
+}
+
+func (m *MockDeliveryRepository) UpdateDelivery(ctx context.Context, d Delivery) error {
+	args := m.Called(ctx, d)
+	return args.Error(0)
+}
+
+func (m *MockDeliveryRepository) RecordAttempt(ctx context.Context, a Attempt) error {
+	args := m.Called(ctx, a)
+	return args.Error(0)
+}
+
+func (m *MockDeliveryRepository) ListAttempts(ctx context.Context, deliveryID string) ([]Attempt, error) {
+	args := m.Called(ctx, deliveryID)
+	return args.Get(0).([]Attempt), args.Error(1)
+}
+
+func TestWebhookService_CreateSubscription(t *testing.T) {
+	mockSubs := new(MockSubscriptionRepository)
+	service := NewWebhookService(mockSubs, new(MockDeliveryRepository), DefaultRetryPolicy)
+	ctx := context.Background()
+	mockSubs.On("CreateSubscription", ctx, mock.AnythingOfType("Subscription")).Return(nil)
+
+	sub, err := service.CreateSubscription(ctx, "user1", "https://partner.test/hooks", []event.Type{event.TypeFundsDeposited})
+
+	require.NoError(t, err)
+	assert.NotEmpty(t, sub.ID)
+	assert.Regexp(t, `^whsec_[0-9a-f]{64}$`, sub.Secret)
+
+	_, err = service.CreateSubscription(ctx, "user1", "not a url", []event.Type{event.TypeFundsDeposited})
+	assert.Equal(t, ErrInvalidURL, err)
+	mockSubs.AssertNumberOfCalls(t, "CreateSubscription", 1)
+}
+
+func TestWebhookService_Enqueue(t *testing.T) {
+	mockSubs := new(MockSubscriptionRepository)
+	mockDeliveries := new(MockDeliveryRepository)
+	service := NewWebhookService(mockSubs, mockDeliveries, DefaultRetryPolicy)
+	ctx := context.Background()
+
+	e, err := event.NewEvent("ev1", event.TypeFundsTransferred, "user1", event.FundsTransferred{FromUserID: "user1", ToUserID: "user2"})
+	require.NoError(t, err)
+	mockSubs.On("ListSubscriptionsByEventType", ctx, event.TypeFundsTransferred).Return([]Subscription{
+		{ID: "sub-recipient", UserID: "user2", EventTypes: []event.Type{event.TypeFundsTransferred}},
+		{ID: "sub-other", UserID: "user3", EventTypes: []event.Type{event.TypeFundsTransferred}},
+	}, nil)
+	mockDeliveries.On("CreateDelivery", ctx, mock.MatchedBy(func(d Delivery) bool {
+		return d.SubscriptionID == "sub-recipient" && d.EventID == "ev1" && d.UserID == "user2"
+	})).Return(nil).Once()
+
+	assert.NoError(t, service.Enqueue(ctx, e))
+	mockDeliveries.AssertExpectations(t)
+}
+
+func TestWebhookService_OwnerScoping(t *testing.T) {
+	mockSubs := new(MockSubscriptionRepository)
+	mockDeliveries := new(MockDeliveryRepository)
+	service := NewWebhookService(mockSubs, mockDeliveries, DefaultRetryPolicy)
+	ctx := context.Background()
+
+	mockSubs.On("GetSubscriptionByID", ctx, "sub1").Return(Subscription{ID: "sub1", UserID: "user1"}, nil)
+	mockDeliveries.On("GetDeliveryByID", ctx, "d1").Return(Delivery{ID: "d1", UserID: "user1", Status: DeliveryDead, Attempts: 10}, nil)
+
+	assert.Equal(t, ErrSubscriptionNotFound, service.DeleteSubscription(ctx, "user2", "sub1"))
+	_, err := service.ReplayDelivery(ctx, "user2", "d1")
+	assert.Equal(t, ErrDeliveryNotFound, err)
+	_, err = service.ListAttempts(ctx, "user2", "d1")
+	assert.Equal(t, ErrDeliveryNotFound, err)
+
+	mockDeliveries.On("UpdateDelivery", ctx, mock.MatchedBy(func(d Delivery) bool {
+		return d.Status == DeliveryPending && d.Attempts == 0
+	})).Return(nil)
+	d, err := service.ReplayDelivery(ctx, "user1", "d1")
+	require.NoError(t, err)
+	assert.Equal(t, DeliveryPending, d.Status)
+	mockSubs.AssertNotCalled(t, "DeleteSubscription", mock.Anything, mock.Anything)
+}
+
+func TestWebhookService_RecordAttempt(t *testing.T) {
+	mockDeliveries := new(MockDeliveryRepository)
+	service := NewWebhookService(new(MockSubscriptionRepository), mockDeliveries, RetryPolicy{MaxAttempts: 1, BaseDelay: time.Second, MaxDelay: time.Second})
+	ctx := context.Background()
+	attempt := Attempt{DeliveryID: "d1", AttemptedAt: time.Now(), Error: "connection refused"}
+
+	mockDeliveries.On("RecordAttempt", ctx, attempt).Return(nil)
+	mockDeliveries.On("UpdateDelivery", ctx, mock.AnythingOfType("Delivery")).Return(nil)
+
+	d, err := service.RecordAttempt(ctx, Delivery{ID: "d1", Status: DeliveryPending}, attempt)
+
+	require.NoError(t, err)
+	assert.Equal(t, DeliveryDead, d.Status)
+	mockDeliveries.AssertExpectations(t)
+}
+
+func TestWebhookService_ClaimDueDeliveries(t *testing.T) {
+	mockDeliveries := new(MockDeliveryRepository)
+	service := NewWebhookService(new(MockSubscriptionRepository), mockDeliveries, DefaultRetryPolicy)
+	now := time.Date(2024, 3, 1, 12, 0, 0, 0, time.UTC)
+	service.now = func() time.Time { return now }
+	ctx := context.Background()
+	due := Delivery{ID: "d1", Status: DeliveryPending, NextAttemptAt: now.Add(-time.Second)}
+
+	mockDeliveries.On("ListDueDeliveries", ctx, now, 10).Return([]Delivery{due}, nil)
+	mockDeliveries.On("UpdateDelivery", ctx, mock.MatchedBy(func(d Delivery) bool {
+		return d.ID == "d1" && d.NextAttemptAt.Equal(now.Add(time.Minute))
+	})).Return(nil)
+
+	claimed, err := service.ClaimDueDeliveries(ctx, 10, time.Minute)
+
+	require.NoError(t, err)
+	require.Len(t, claimed, 1)
+	assert.Equal(t, now.Add(time.Minute), claimed[0].NextAttemptAt, "claimed deliveries are not due again until the lease ends")
+	mockDeliveries.AssertExpectations(t)
+}
@@ -0,0 +1,186 @@
+package grpc
+
+import (
+	"context"
+	"strconv"
+	"strings"
+	"time"
+
+	"exchange/internal/domain/auth"
+
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/metadata"
+	"google.golang.org/protobuf/proto"
+)
+
+// Metadata keys carrying credentials, matching the HTTP headers.
+const (
+	apiKeyKey        = "x-api-key"
+	timestampKey     = "x-timestamp"
+	nonceKey         = "x-nonce"
+	signatureKey     = "x-signature"
+	authorizationKey = "authorization"
+)
+
+// Authenticator identifies the principal behind a call from its metadata and request.
+type Authenticator interface {
+	Authenticate(ctx context.Context, method string, req any) (auth.Principal, error)
+}
+
+// APIKeyAuthenticator verifies calls signed with an API key secret. Clients send the key
+// ID, a unix timestamp, a unique nonce and the hex HMAC-SHA256 of auth.CanonicalRequest in
+// the x-api-key, x-timestamp, x-nonce and x-signature metadata. The request is signed as
+// a POST of the full method name, e.g. "/wallet.v1.WalletService/Deposit", whose body is
+// the serialized request message.
+type APIKeyAuthenticator struct {
+	service auth.APIKeyServiceInterface
+}
+
+func NewAPIKeyAuthenticator(service auth.APIKeyServiceInterface) *APIKeyAuthenticator {
+	return &APIKeyAuthenticator{
+		service: service,
+	}
+}
+
+func (a *APIKeyAuthenticator) Authenticate(ctx context.Context, method string, req any) (auth.Principal, error) {
+	keyID := incoming(ctx, apiKeyKey)
+	if keyID == "" {
+		return auth.Principal{}, auth.ErrUnauthenticated
+	}
+
+	timestamp, err := strconv.ParseInt(incoming(ctx, timestampKey), 10, 64)
+	if err != nil {
+		return auth.Principal{}, auth.ErrInvalidSignature
+	}
+	nonce := incoming(ctx, nonceKey)
+
+	msg, ok := req.(proto.Message)
+	if !ok {
+		return auth.Principal{}, auth.ErrInvalidSignature
+	}
+	body, err := proto.MarshalOptions{Deterministic: true}.Marshal(msg)
+	if err != nil {
+		return auth.Principal{}, auth.ErrInvalidSignature
+	}
+
+	return a.service.Authenticate(ctx, auth.SignedRequest{
+		KeyID:     keyID,
+		Timestamp: time.Unix(timestamp, 0),
+		Nonce:     nonce,
+		Signature: incoming(ctx, signatureKey),
+		Payload:   auth.CanonicalRequest(timestamp, nonce, "POST", method, body),
+	})
+}
+
+// BearerAuthenticator verifies OAuth2/OIDC access tokens sent as "authorization: Bearer
+// <token>" metadata.
+type BearerAuthenticator struct {
+	verifier auth.TokenVerifier
+}
+
+func NewBearerAuthenticator(verifier auth.TokenVerifier) *BearerAuthenticator {
+	return &BearerAuthenticator{
+		verifier: verifier,
+	}
+}
+
+func (a *BearerAuthenticator) Authenticate(ctx context.Context, _ string, _ any) (auth.Principal, error) {
+	header := incoming(ctx, authorizationKey)
+	if header == "" {
+		return auth.Principal{}, auth.ErrUnauthenticated
+	}
+	scheme, token, ok := strings.Cut(header, " ")
+	if !ok || !strings.EqualFold(scheme, "Bearer") || token == "" {
+		return auth.Principal{}, auth.ErrInvalidToken
+	}
+	return a.verifier.VerifyToken(ctx, strings.TrimSpace(token))
+}
+
+// ChainAuthenticator tries each authenticator in turn and uses the first one for which
+// the call carries credentials.
+type ChainAuthenticator []Authenticator
+
+func (c ChainAuthenticator) Authenticate(ctx context.Context, method string, req any) (auth.Principal, error) {
+	for _, authenticator := range c {
+		principal, err := authenticator.Authenticate(ctx, method, req)
+		if err != auth.ErrUnauthenticated {
+			return principal, err
+		}
+	}
+	return auth.Principal{}, auth.ErrUnauthenticated
+}
+
+// incoming returns the first value of the incoming metadata key, or "".
+func incoming(ctx context.Context, key string) string {
+	if values := metadata.ValueFromIncomingContext(ctx, key); len(values) > 0 {
+		return values[0]
+	}
+	return ""
+}
+
+// authUnaryInterceptor rejects calls that authenticator cannot identify and stores the
+// principal of the others in the call context.
+func authUnaryInterceptor(authenticator Authenticator) grpc.UnaryServerInterceptor {
+	return func(ctx context.Context, req any, info *grpc.UnaryServerInfo, handler grpc.UnaryHandler) (any, error) {
+		principal, err := authenticator.Authenticate(ctx, info.FullMethod, req)
+		if err != nil {
+			return nil, toStatusError(err)
+		}
+		return handler(auth.WithPrincipal(ctx, principal), req)
+	}
+}
+
+// authStreamInterceptor authenticates streaming calls once their request message has been
+// received, since API key signatures cover it.
+func authStreamInterceptor(authenticator Authenticator) grpc.StreamServerInterceptor {
+	return func(srv any, ss grpc.ServerStream, info *grpc.StreamServerInfo, handler grpc.StreamHandler) error {
+		return handler(srv, &authenticatedStream{ServerStream: ss, ctx: ss.Context(), method: info.FullMethod, authenticator: authenticator})
+	}
+}
+
+type authenticatedStream struct {
+	grpc.ServerStream
+	ctx           context.Context
+	method        string
+	authenticator Authenticator
+	authenticated bool
+}
+
+func (s *authenticatedStream) Context() context.Context {
+	return s.ctx
+}
+
+func (s *authenticatedStream) RecvMsg(m any) error {
+	if err := s.ServerStream.RecvMsg(m); err != nil {
+		return err
+	}
+	if s.authenticated {
+		return nil
+	}
+	principal, err := s.authenticator.Authenticate(s.ctx, s.method, m)
+	if err != nil {
+		return toStatusError(err)
+	}
+	s.ctx = auth.WithPrincipal(s.ctx, principal)
+	s.authenticated = true
+	return nil
+}
+
+// actingUserID resolves the wallet a call acts on: the authenticated principal's own
+// unless the client names another one, which only admins may do.
+func actingUserID(ctx context.Context, requested string, perm auth.Permission) (string, error) {
+	principal, ok := auth.PrincipalFromContext(ctx)
+	if !ok {
+		return "", auth.ErrUnauthenticated
+	}
+	if !principal.HasPermission(perm) {
+		return "", auth.ErrForbidden
+	}
+	if requested == "" {
+		return principal.UserID, nil
+	}
+	if !principal.CanActAs(requested) {
+		return "", auth.ErrForbidden
+	}
+	return requested, nil
+}
@@ -0,0 +1,204 @@
+package grpc
+
+//go:generate protoc --proto_path=proto --go_out=walletpb --go_opt=paths=source_relative --go-grpc_out=walletpb --go-grpc_opt=paths=source_relative wallet.proto
+
+import (
+	"context"
+	"errors"
+	"log"
+
+	"exchange/internal/domain/auth"
+	"exchange/internal/domain/kyc"
+	"exchange/internal/domain/limit"
+	"exchange/internal/domain/risk"
+	"exchange/internal/domain/sanctions"
+	"exchange/internal/domain/transaction"
+	"exchange/internal/domain/user"
+	"exchange/internal/domain/wallet"
+	"exchange/internal/ports/grpc/walletpb"
+	"exchange/internal/usecase"
+
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+// historyPageSize is how many transactions are read from the use case per page while
+// streaming a history.
+const historyPageSize = 100
+
+type Handler struct {
+	walletpb.UnimplementedWalletServiceServer
+
+	WalletUC      *usecase.WalletUseCase
+	TransactionUC *usecase.TransactionUseCase
+}
+
+func NewHandler(walletUC *usecase.WalletUseCase, transactionUC *usecase.TransactionUseCase) *Handler {
+	return &Handler{
+		WalletUC:      walletUC,
+		TransactionUC: transactionUC,
+	}
+}
+
+func (h *Handler) Deposit(ctx context.Context, req *walletpb.DepositRequest) (*walletpb.DepositResponse, error) {
+	userID, err := actingUserID(ctx, req.GetUserId(), auth.PermissionTrade)
+	if err != nil {
+		return nil, toStatusError(err)
+	}
+	if err := h.WalletUC.Deposit(ctx, userID, req.GetAmount(), req.GetCurrency()); err != nil {
+		return nil, toStatusError(err)
+	}
+	return &walletpb.DepositResponse{}, nil
+}
+
+func (h *Handler) Withdraw(ctx context.Context, req *walletpb.WithdrawRequest) (*walletpb.WithdrawResponse, error) {
+	userID, err := actingUserID(ctx, req.GetUserId(), auth.PermissionWithdraw)
+	if err != nil {
+		return nil, toStatusError(err)
+	}
+	tx, err := h.WalletUC.Withdraw(ctx, userID, req.GetAmount(), req.GetCurrency(), "")
+	if err != nil {
+		return nil, toStatusError(err)
+	}
+	return &walletpb.WithdrawResponse{
+		TransactionId: tx.ID,
+		Status:        string(tx.Status),
+	}, nil
+}
+
+func (h *Handler) Transfer(ctx context.Context, req *walletpb.TransferRequest) (*walletpb.TransferResponse, error) {
+	fromUserID, err := actingUserID(ctx, req.GetFromUserId(), auth.PermissionTrade)
+	if err != nil {
+		return nil, toStatusError(err)
+	}
+	if err := h.WalletUC.Transfer(ctx, fromUserID, req.GetToUserId(), req.GetAmount(), req.GetCurrency()); err != nil {
+		return nil, toStatusError(err)
+	}
+	return &walletpb.TransferResponse{}, nil
+}
+
+func (h *Handler) GetBalance(ctx context.Context, req *walletpb.GetBalanceRequest) (*walletpb.GetBalanceResponse, error) {
+	userID, err := actingUserID(ctx, req.GetUserId(), auth.PermissionRead)
+	if err != nil {
+		return nil, toStatusError(err)
+	}
+	wlt, err := h.WalletUC.GetWallet(ctx, userID)
+	if err != nil {
+		return nil, toStatusError(err)
+	}
+	return &walletpb.GetBalanceResponse{
+		UserId:    userID,
+		Balance:   wlt.Balance,
+		Held:      wlt.Held,
+		Available: wlt.Available(),
+	}, nil
+}
+
+func (h *Handler) GetTransactionHistory(req *walletpb.GetTransactionHistoryRequest, stream walletpb.WalletService_GetTransactionHistoryServer) error {
+	if req.GetLimit() < 0 || req.GetOffset() < 0 {
+		return status.Error(codes.InvalidArgument, "limit and offset must not be negative")
+	}
+
+	ctx := stream.Context()
+	userID, err := actingUserID(ctx, req.GetUserId(), auth.PermissionRead)
+	if err != nil {
+		return toStatusError(err)
+	}
+	// Only the first page is read by offset; later pages continue from the last
+	// transaction sent, so transactions logged while streaming are not sent twice.
+	remaining := int(req.GetLimit())
+	var next *transaction.Cursor
+	for {
+		pageSize := historyPageSize
+		if req.GetLimit() > 0 && remaining < pageSize {
+			pageSize = remaining
+		}
+
+		var txs []transaction.Transaction
+		if next == nil {
+			txs, err = h.TransactionUC.GetTransactionHistory(ctx, userID, pageSize, int(req.GetOffset()))
+		} else {
+			txs, err = h.TransactionUC.GetTransactionHistoryBefore(ctx, userID, *next, pageSize)
+		}
+		if err != nil {
+			return toStatusError(err)
+		}
+
+		for _, tx := range txs {
+			if err := stream.Send(toProtoTransaction(tx)); err != nil {
+				return err
+			}
+		}
+
+		remaining -= len(txs)
+		if len(txs) < pageSize || (req.GetLimit() > 0 && remaining <= 0) {
+			return nil
+		}
+		cursor := txs[len(txs)-1].Cursor()
+		next = &cursor
+	}
+}
+
+func toProtoTransaction(tx transaction.Transaction) *walletpb.Transaction {
+	return &walletpb.Transaction{
+		Id:         tx.ID,
+		FromUserId: tx.FromUserID,
+		ToUserId:   tx.ToUserID,
+		Amount:     tx.Amount,
+		Currency:   tx.Currency,
+		Type:       string(tx.Type),
+		CreatedAt:  tx.CreatedAt.Unix(),
+		Status:     string(tx.Status),
+		OriginalId: tx.OriginalID,
+		BatchId:    tx.BatchID,
+	}
+}
+
+// toStatusError maps the domain sentinel errors to gRPC status codes, mirroring
+// handleError in the HTTP port.
+func toStatusError(err error) error {
+	switch {
+	case errors.Is(err, wallet.ErrWalletNotFound):
+		return status.Error(codes.NotFound, "wallet not found")
+	case errors.Is(err, wallet.ErrInsufficientFunds):
+		return status.Error(codes.FailedPrecondition, "insufficient funds")
+	case errors.Is(err, wallet.ErrInvalidAmount):
+		return status.Error(codes.InvalidArgument, "invalid amount")
+	case errors.Is(err, transaction.ErrInvalidTransactionAmount):
+		return status.Error(codes.InvalidArgument, "invalid transaction amount")
+	case errors.Is(err, transaction.ErrInvalidTransactionType):
+		return status.Error(codes.InvalidArgument, "invalid transaction type")
+	case errors.Is(err, transaction.ErrTransactionNotFound):
+		return status.Error(codes.NotFound, "transaction not found")
+	case errors.Is(err, transaction.ErrInvalidUserID):
+		return status.Error(codes.InvalidArgument, "invalid user id")
+	case errors.Is(err, transaction.ErrInvalidTransactionID):
+		return status.Error(codes.InvalidArgument, "invalid transaction id")
+	case errors.Is(err, transaction.ErrInvalidTimeRange):
+		return status.Error(codes.InvalidArgument, "invalid time range")
+	case errors.Is(err, user.ErrUserNotFound):
+		return status.Error(codes.NotFound, "user not found")
+	case errors.Is(err, user.ErrUserDeactivated):
+		return status.Error(codes.FailedPrecondition, "user is deactivated")
+	case errors.Is(err, kyc.ErrBalanceCapExceeded), errors.Is(err, kyc.ErrWithdrawalCapExceeded), errors.Is(err, kyc.ErrTransferCapExceeded):
+		return status.Error(codes.PermissionDenied, err.Error())
+	case errors.Is(err, limit.ErrLimitExceeded):
+		return status.Error(codes.ResourceExhausted, err.Error())
+	case errors.Is(err, risk.ErrBlocked):
+		return status.Error(codes.PermissionDenied, "blocked by risk rules")
+	case errors.Is(err, sanctions.ErrBlocked):
+		return status.Error(codes.PermissionDenied, "blocked by sanctions screening")
+	case errors.Is(err, auth.ErrUnauthenticated), errors.Is(err, auth.ErrInvalidAPIKey), errors.Is(err, auth.ErrInvalidSignature),
+		errors.Is(err, auth.ErrSignatureExpired), errors.Is(err, auth.ErrNonceReused), errors.Is(err, auth.ErrInvalidToken):
+		return status.Error(codes.Unauthenticated, "unauthenticated")
+	case errors.Is(err, auth.ErrForbidden):
+		return status.Error(codes.PermissionDenied, "forbidden")
+	case errors.Is(err, context.Canceled):
+		return status.Error(codes.Canceled, "request canceled")
+	case errors.Is(err, context.DeadlineExceeded):
+		return status.Error(codes.DeadlineExceeded, "deadline exceeded")
+	default:
+		log.Println("error:", err)
+		return status.Error(codes.Internal, "internal server error")
+	}
+}
@@ -0,0 +1,666 @@
+package grpc
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"net"
+	"strconv"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	"exchange/internal/domain/audit"
+	"exchange/internal/domain/auth"
+	"exchange/internal/domain/event"
+	"exchange/internal/domain/kyc"
+	"exchange/internal/domain/limit"
+	"exchange/internal/domain/reserves"
+	"exchange/internal/domain/risk"
+	"exchange/internal/domain/sanctions"
+	"exchange/internal/domain/snapshot"
+	"exchange/internal/domain/transaction"
+	"exchange/internal/domain/user"
+	"exchange/internal/domain/wallet"
+	"exchange/internal/ports/grpc/walletpb"
+	"exchange/internal/usecase"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/credentials/insecure"
+	"google.golang.org/grpc/metadata"
+	"google.golang.org/grpc/status"
+	"google.golang.org/grpc/test/bufconn"
+	"google.golang.org/protobuf/proto"
+)
+
+// stubWalletService implements only the calls exercised here; anything else panics
+// through the nil embedded interface.
+type stubWalletService struct {
+	usecase.WalletServiceInterface
+	balances map[string]int64
+	held     map[string]int64
+}
+
+func (s *stubWalletService) Deposit(ctx context.Context, userID string, amount int64) error {
+	if amount <= 0 {
+		return wallet.ErrInvalidAmount
+	}
+	if _, ok := s.balances[userID]; !ok {
+		return wallet.ErrWalletNotFound
+	}
+	s.balances[userID] += amount
+	return nil
+}
+
+func (s *stubWalletService) Withdraw(ctx context.Context, userID string, amount int64) error {
+	if _, ok := s.balances[userID]; !ok {
+		return wallet.ErrWalletNotFound
+	}
+	if s.balances[userID] < amount {
+		return wallet.ErrInsufficientFunds
+	}
+	s.balances[userID] -= amount
+	return nil
+}
+
+func (s *stubWalletService) GetWallet(ctx context.Context, userID string) (wallet.Wallet, error) {
+	balance, ok := s.balances[userID]
+	if !ok {
+		return wallet.Wallet{}, wallet.ErrWalletNotFound
+	}
+	return wallet.Wallet{UserID: userID, Balance: balance, Held: s.held[userID], Currency: "USD"}, nil
+}
+
+func (s *stubWalletService) GetBalance(ctx context.Context, userID string) (int64, error) {
+	balance, ok := s.balances[userID]
+	if !ok {
+		return 0, wallet.ErrWalletNotFound
+	}
+	return balance, nil
+}
+
+type stubTransactionService struct {
+	usecase.TransactionServiceInterface
+	mu      sync.Mutex
+	history []transaction.Transaction // history is newest first.
+	// arriving is put on top of history once the first page has been read, like
+	// transactions logged while a history streams.
+	arriving []transaction.Transaction
+}
+
+func (s *stubTransactionService) LogTransaction(ctx context.Context, fromUserID, toUserID string, amount int64, currency string, tType transaction.TransactionType, opts ...transaction.Option) (transaction.Transaction, error) {
+	return transaction.Transaction{ID: "tx", FromUserID: fromUserID, ToUserID: toUserID, Amount: amount, Currency: currency, Type: tType, Status: transaction.StatusCompleted}, nil
+}
+
+func (s *stubTransactionService) GetTransactionHistory(ctx context.Context, userID string, limit, offset int) ([]transaction.Transaction, error) {
+	if userID == "" {
+		return nil, transaction.ErrInvalidUserID
+	}
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	page := s.page(offset, limit)
+	if len(s.arriving) > 0 {
+		s.history = append(s.arriving, s.history...)
+		s.arriving = nil
+	}
+	return page, nil
+}
+
+func (s *stubTransactionService) GetTransactionHistoryBefore(ctx context.Context, userID string, before transaction.Cursor, limit int) ([]transaction.Transaction, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	for i, tx := range s.history {
+		if tx.ID == before.ID {
+			return s.page(i+1, limit), nil
+		}
+	}
+	return nil, nil
+}
+
+func (s *stubTransactionService) page(offset, limit int) []transaction.Transaction {
+	if offset >= len(s.history) {
+		return nil
+	}
+	end := offset + limit
+	if end > len(s.history) {
+		end = len(s.history)
+	}
+	return s.history[offset:end]
+}
+
+// stubAuditService keeps the recorded entries so tests can check the request metadata.
+type stubAuditService struct {
+	mu      sync.Mutex
+	entries []audit.Entry
+}
+
+func (s *stubAuditService) Begin(_ context.Context, action audit.Action, userIDs ...string) audit.Entry {
+	return audit.NewEntry(action, userIDs...)
+}
+
+func (s *stubAuditService) RecordSuccess(ctx context.Context, e audit.Entry) error {
+	return s.record(ctx, e, audit.OutcomeSuccess)
+}
+
+func (s *stubAuditService) RecordFailure(ctx context.Context, e audit.Entry, _ error) error {
+	return s.record(ctx, e, audit.OutcomeFailure)
+}
+
+func (s *stubAuditService) record(ctx context.Context, e audit.Entry, outcome audit.Outcome) error {
+	m, _ := audit.MetadataFromContext(ctx)
+	e.Actor, e.RequestID, e.SourceIP, e.Outcome = m.Actor, m.RequestID, m.SourceIP, outcome
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.entries = append(s.entries, e)
+	return nil
+}
+
+func (s *stubAuditService) ListEntries(_ context.Context, _ audit.Filter, _, _ int) ([]audit.Entry, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return append([]audit.Entry(nil), s.entries...), nil
+}
+
+func (s *stubAuditService) VerifyChain(_ context.Context) (audit.ChainReport, error) {
+	return audit.ChainReport{Valid: true}, nil
+}
+
+// discardOutbox accepts events and never has any pending.
+type discardOutbox struct{}
+
+func (discardOutbox) AppendEvent(context.Context, event.Event) error { return nil }
+
+func (discardOutbox) ListPendingEvents(context.Context, int) ([]event.Event, error) { return nil, nil }
+
+func (discardOutbox) MarkEventPublished(context.Context, string, time.Time) error { return nil }
+
+func (discardOutbox) RecordEventFailure(context.Context, string, string) error { return nil }
+
+// noLimits leaves every user unlimited.
+type noLimits struct{}
+
+func (noLimits) GetLimits(context.Context, string) ([]limit.Limit, error) { return nil, nil }
+
+// allowAll lets every request through without recording it.
+type allowAll struct{}
+
+func (allowAll) Assess(context.Context, risk.Request) (risk.Assessment, error) {
+	return risk.Assessment{Decision: risk.DecisionAllow}, nil
+}
+
+func (allowAll) ListAssessments(context.Context, risk.Filter, int, int) ([]risk.Assessment, error) {
+	return nil, nil
+}
+
+// activeUsers treats every user id as a registered, active user.
+type activeUsers struct{}
+
+func (activeUsers) Register(_ context.Context, id, name, email string) (user.User, error) {
+	return user.NewUser(id, name, email)
+}
+
+func (activeUsers) GetUser(_ context.Context, id string) (user.User, error) {
+	return user.User{ID: id, Status: user.StatusActive}, nil
+}
+
+func (u activeUsers) UpdateProfile(ctx context.Context, id, _, _ string) (user.User, error) {
+	return u.GetUser(ctx, id)
+}
+
+func (u activeUsers) SetStatus(ctx context.Context, id string, _ user.Status) (user.User, error) {
+	return u.GetUser(ctx, id)
+}
+
+func (activeUsers) CheckActive(context.Context, string) error {
+	return nil
+}
+
+// uncapped treats every user as fully verified.
+type uncapped struct{}
+
+func (uncapped) GetLevel(context.Context, string) (kyc.Level, error) {
+	return kyc.LevelFull, nil
+}
+
+func (uncapped) GetCaps(context.Context, string) (kyc.Caps, error) {
+	return kyc.Caps{}, nil
+}
+
+func (uncapped) CapsOf(kyc.Level) kyc.Caps {
+	return kyc.Caps{}
+}
+
+func (uncapped) ListLevelChanges(context.Context, string) ([]kyc.LevelChange, error) {
+	return nil, nil
+}
+
+func (uncapped) SetLevel(_ context.Context, userID string, level kyc.Level, changedBy, reason string) (kyc.LevelChange, error) {
+	return kyc.LevelChange{UserID: userID, To: level, ChangedBy: changedBy, Reason: reason}, nil
+}
+
+func (uncapped) Submit(_ context.Context, userID string, level kyc.Level, documentType, fileName, contentType string, content []byte) (kyc.Submission, error) {
+	return kyc.NewSubmission("kyc1", userID, level, documentType, fileName, contentType, int64(len(content)))
+}
+
+func (uncapped) GetSubmission(context.Context, string) (kyc.Submission, error) {
+	return kyc.Submission{}, kyc.ErrSubmissionNotFound
+}
+
+func (uncapped) ListSubmissions(context.Context, kyc.Filter, int, int) ([]kyc.Submission, error) {
+	return nil, nil
+}
+
+func (uncapped) GetDocument(context.Context, kyc.Submission) ([]byte, error) {
+	return nil, kyc.ErrDocumentNotFound
+}
+
+func (uncapped) Review(context.Context, string, string, bool, string) (kyc.Submission, error) {
+	return kyc.Submission{}, kyc.ErrSubmissionNotFound
+}
+
+// unlisted lets every party through the sanctions screening.
+type unlisted struct{}
+
+func (unlisted) Screen(context.Context, string, ...sanctions.Subject) error {
+	return nil
+}
+
+func (unlisted) GetCase(context.Context, string) (sanctions.Case, error) {
+	return sanctions.Case{}, sanctions.ErrCaseNotFound
+}
+
+func (unlisted) ListCases(context.Context, sanctions.Filter, int, int) ([]sanctions.Case, error) {
+	return nil, nil
+}
+
+func (unlisted) ClearCase(context.Context, string, string, string) (sanctions.Case, error) {
+	return sanctions.Case{}, sanctions.ErrCaseNotFound
+}
+
+type noSnapshots struct{}
+
+func (noSnapshots) Record(context.Context, snapshot.Snapshot) error {
+	return nil
+}
+
+func (noSnapshots) Latest(context.Context, string, time.Time) (snapshot.Snapshot, error) {
+	return snapshot.Snapshot{}, snapshot.ErrSnapshotNotFound
+}
+
+func (noSnapshots) RecordTotal(context.Context, snapshot.Total) error {
+	return nil
+}
+
+func (noSnapshots) Totals(context.Context, time.Time) ([]snapshot.Total, error) {
+	return nil, snapshot.ErrSnapshotNotFound
+}
+
+type noReserves struct{}
+
+func (noReserves) Publish(context.Context, string, time.Time, map[string]int64) (reserves.Root, error) {
+	return reserves.Root{}, nil
+}
+
+func (noReserves) LatestRoots(context.Context) ([]reserves.Root, error) {
+	return nil, nil
+}
+
+func (noReserves) Prove(context.Context, string, string) (reserves.Proof, error) {
+	return reserves.Proof{}, reserves.ErrRootNotFound
+}
+
+type passthroughTransactionManager struct{}
+
+func (passthroughTransactionManager) Do(ctx context.Context, fn func(ctx context.Context) error) error {
+	return fn(ctx)
+}
+
+func (passthroughTransactionManager) ReadOnly(ctx context.Context, fn func(ctx context.Context) error) error {
+	return fn(ctx)
+}
+
+// stubVerifier accepts the bearer tokens it maps to principals.
+type stubVerifier map[string]auth.Principal
+
+func (v stubVerifier) VerifyToken(_ context.Context, token string) (auth.Principal, error) {
+	principal, ok := v[token]
+	if !ok {
+		return auth.Principal{}, auth.ErrInvalidToken
+	}
+	return principal, nil
+}
+
+var allPermissions = []auth.Permission{auth.PermissionRead, auth.PermissionTrade, auth.PermissionWithdraw}
+
+// testTokens are the bearer tokens of the test server: "user1" may do anything with
+// user1's wallet, "reader" may only read it and "admin" may act on every wallet.
+var testTokens = stubVerifier{
+	"user1":  {UserID: "user1", Permissions: allPermissions},
+	"reader": {UserID: "user1", Permissions: []auth.Permission{auth.PermissionRead}},
+	"admin":  {UserID: "ops", Roles: []string{auth.RoleAdmin}, Permissions: allPermissions},
+}
+
+func bearer(ctx context.Context, token string) context.Context {
+	return metadata.AppendToOutgoingContext(ctx, authorizationKey, "Bearer "+token)
+}
+
+type memoryAPIKeyRepository struct {
+	mu     sync.Mutex
+	keys   map[string]auth.APIKey
+	nonces map[string]bool
+}
+
+func (r *memoryAPIKeyRepository) CreateAPIKey(_ context.Context, key auth.APIKey) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	r.keys[key.ID] = key
+	return nil
+}
+
+func (r *memoryAPIKeyRepository) GetAPIKeyByID(_ context.Context, id string) (auth.APIKey, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	key, ok := r.keys[id]
+	if !ok {
+		return auth.APIKey{}, auth.ErrAPIKeyNotFound
+	}
+	return key, nil
+}
+
+func (r *memoryAPIKeyRepository) RevokeAPIKey(context.Context, string, time.Time) error {
+	return nil
+}
+
+func (r *memoryAPIKeyRepository) RecordNonce(_ context.Context, keyID, nonce string, _ time.Time) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	if r.nonces[keyID+"/"+nonce] {
+		return auth.ErrNonceReused
+	}
+	r.nonces[keyID+"/"+nonce] = true
+	return nil
+}
+
+// testKey is an API key of the test server and its secret.
+type testKey struct {
+	id, secret string
+}
+
+// sign adds the metadata of a call to method with req signed by k under nonce.
+func (k testKey) sign(t *testing.T, ctx context.Context, method string, req proto.Message, nonce string) context.Context {
+	t.Helper()
+	body, err := proto.MarshalOptions{Deterministic: true}.Marshal(req)
+	require.NoError(t, err)
+	timestamp := time.Now().Unix()
+	payload := auth.CanonicalRequest(timestamp, nonce, "POST", method, body)
+	return metadata.AppendToOutgoingContext(ctx,
+		apiKeyKey, k.id,
+		timestampKey, strconv.FormatInt(timestamp, 10),
+		nonceKey, nonce,
+		signatureKey, auth.SignRequest(k.secret, payload),
+	)
+}
+
+func newTestClient(t *testing.T, transactionService *stubTransactionService) (walletpb.WalletServiceClient, *stubAuditService, testKey) {
+	t.Helper()
+
+	walletService := &stubWalletService{balances: map[string]int64{"user1": 1000, "user2": 0}, held: map[string]int64{"user2": 100}}
+	auditService := &stubAuditService{}
+	walletUC := usecase.NewWalletUseCase(usecase.WalletDependencies{
+		Wallets:      walletService,
+		Transactions: transactionService,
+		TxManager:    passthroughTransactionManager{},
+		Audit:        auditService,
+		Events:       event.NewEventService(discardOutbox{}),
+		Limits:       noLimits{},
+		Risk:         allowAll{},
+		Users:        activeUsers{},
+		KYC:          uncapped{},
+		Sanctions:    unlisted{},
+		Snapshots:    noSnapshots{},
+		Reserves:     noReserves{},
+	})
+	transactionUC := usecase.NewTransactionUseCase(transactionService)
+
+	apiKeyRepo := &memoryAPIKeyRepository{keys: map[string]auth.APIKey{}, nonces: map[string]bool{}}
+	secrets, err := auth.NewSecretBox(strings.Repeat("ab", auth.SecretKeySize))
+	require.NoError(t, err)
+	apiKeyService := auth.NewAPIKeyService(apiKeyRepo, apiKeyRepo, secrets)
+	key, secret, err := apiKeyService.IssueAPIKey(context.Background(), "user1", allPermissions)
+	require.NoError(t, err)
+	authenticator := ChainAuthenticator{NewAPIKeyAuthenticator(apiKeyService), NewBearerAuthenticator(testTokens)}
+
+	lis := bufconn.Listen(1024 * 1024)
+	srv := NewServer(authenticator, NewHandler(walletUC, transactionUC))
+	go srv.Serve(lis)
+	t.Cleanup(srv.Stop)
+
+	conn, err := grpc.NewClient("passthrough:///bufnet",
+		grpc.WithContextDialer(func(ctx context.Context, _ string) (net.Conn, error) { return lis.DialContext(ctx) }),
+		grpc.WithTransportCredentials(insecure.NewCredentials()),
+	)
+	require.NoError(t, err)
+	t.Cleanup(func() { conn.Close() })
+
+	return walletpb.NewWalletServiceClient(conn), auditService, testKey{id: key.ID, secret: secret}
+}
+
+func TestHandler_MoneyMovement(t *testing.T) {
+	client, auditService, _ := newTestClient(t, new(stubTransactionService))
+	ctx := bearer(metadata.AppendToOutgoingContext(context.Background(), requestIDKey, "req-1"), "admin")
+
+	_, err := client.Deposit(ctx, &walletpb.DepositRequest{UserId: "user1", Amount: 500, Currency: "USD"})
+	require.NoError(t, err)
+
+	_, err = client.Transfer(ctx, &walletpb.TransferRequest{FromUserId: "user1", ToUserId: "user2", Amount: 300, Currency: "USD"})
+	require.NoError(t, err)
+
+	resp, err := client.GetBalance(ctx, &walletpb.GetBalanceRequest{UserId: "user2"})
+	require.NoError(t, err)
+	assert.Equal(t, int64(300), resp.GetBalance())
+	assert.Equal(t, int64(100), resp.GetHeld())
+	assert.Equal(t, int64(200), resp.GetAvailable())
+
+	_, err = client.Withdraw(ctx, &walletpb.WithdrawRequest{UserId: "user2", Amount: 1000, Currency: "USD"})
+	assert.Equal(t, codes.FailedPrecondition, status.Code(err))
+
+	withdrawal, err := client.Withdraw(ctx, &walletpb.WithdrawRequest{UserId: "user1", Amount: 50, Currency: "USD"})
+	require.NoError(t, err)
+	assert.Equal(t, "tx", withdrawal.GetTransactionId())
+	assert.Equal(t, string(transaction.StatusCompleted), withdrawal.GetStatus())
+
+	_, err = client.GetBalance(ctx, &walletpb.GetBalanceRequest{UserId: "nobody"})
+	assert.Equal(t, codes.NotFound, status.Code(err))
+
+	entries, _ := auditService.ListEntries(ctx, audit.Filter{}, 0, 0)
+	require.Len(t, entries, 4)
+	assert.Equal(t, audit.ActionTransfer, entries[1].Action)
+	assert.Equal(t, audit.OutcomeFailure, entries[2].Outcome)
+	for _, e := range entries {
+		assert.Equal(t, "ops", e.Actor)
+		assert.Equal(t, "req-1", e.RequestID)
+		assert.NotEmpty(t, e.SourceIP)
+	}
+}
+
+func TestHandler_GetTransactionHistory(t *testing.T) {
+	history := make([]transaction.Transaction, 250)
+	for i := range history {
+		history[i] = transaction.Transaction{ID: fmt.Sprintf("tx%d", i), ToUserID: "user1", Amount: int64(i + 1), Currency: "USD", Type: transaction.TransactionTypeDeposit, Status: transaction.StatusCompleted}
+	}
+	history[1].Type, history[1].OriginalID, history[1].BatchID = transaction.TransactionTypeReversal, "tx0", "batch1"
+	history[2].Status = transaction.StatusPending
+	client, _, _ := newTestClient(t, &stubTransactionService{history: history})
+
+	receive := func(req *walletpb.GetTransactionHistoryRequest) ([]*walletpb.Transaction, error) {
+		stream, err := client.GetTransactionHistory(bearer(context.Background(), "admin"), req)
+		if err != nil {
+			return nil, err
+		}
+		var txs []*walletpb.Transaction
+		for {
+			tx, err := stream.Recv()
+			if errors.Is(err, io.EOF) {
+				return txs, nil
+			}
+			if err != nil {
+				return txs, err
+			}
+			txs = append(txs, tx)
+		}
+	}
+
+	t.Run("streams the whole history across pages", func(t *testing.T) {
+		txs, err := receive(&walletpb.GetTransactionHistoryRequest{UserId: "user1"})
+		require.NoError(t, err)
+		assert.Len(t, txs, 250)
+		assert.Equal(t, int64(250), txs[249].GetAmount())
+	})
+
+	t.Run("carries status, original and batch", func(t *testing.T) {
+		txs, err := receive(&walletpb.GetTransactionHistoryRequest{UserId: "user1", Limit: 3})
+		require.NoError(t, err)
+		require.Len(t, txs, 3)
+		assert.Equal(t, "completed", txs[0].GetStatus())
+		assert.Equal(t, "tx0", txs[1].GetOriginalId())
+		assert.Equal(t, "batch1", txs[1].GetBatchId())
+		assert.Equal(t, "pending", txs[2].GetStatus())
+	})
+
+	t.Run("honours limit and offset", func(t *testing.T) {
+		txs, err := receive(&walletpb.GetTransactionHistoryRequest{UserId: "user1", Limit: 120, Offset: 10})
+		require.NoError(t, err)
+		assert.Len(t, txs, 120)
+		assert.Equal(t, int64(11), txs[0].GetAmount())
+	})
+
+	t.Run("defaults to the caller's history", func(t *testing.T) {
+		stream, err := client.GetTransactionHistory(bearer(context.Background(), "user1"), &walletpb.GetTransactionHistoryRequest{Limit: 5})
+		require.NoError(t, err)
+		tx, err := stream.Recv()
+		require.NoError(t, err)
+		assert.Equal(t, "user1", tx.GetToUserId())
+	})
+}
+
+func TestHandler_GetTransactionHistoryWhileLogging(t *testing.T) {
+	history := make([]transaction.Transaction, 150)
+	for i := range history {
+		history[i] = transaction.Transaction{ID: fmt.Sprintf("tx%d", i), ToUserID: "user1", Amount: int64(i + 1), Currency: "USD", Type: transaction.TransactionTypeDeposit}
+	}
+	arriving := []transaction.Transaction{{ID: "tx-new", ToUserID: "user1", Amount: 1, Currency: "USD", Type: transaction.TransactionTypeDeposit}}
+	client, _, _ := newTestClient(t, &stubTransactionService{history: history, arriving: arriving})
+
+	stream, err := client.GetTransactionHistory(bearer(context.Background(), "admin"), &walletpb.GetTransactionHistoryRequest{UserId: "user1"})
+	require.NoError(t, err)
+	var ids []string
+	for {
+		tx, err := stream.Recv()
+		if errors.Is(err, io.EOF) {
+			break
+		}
+		require.NoError(t, err)
+		ids = append(ids, tx.GetId())
+	}
+
+	require.Len(t, ids, 150, "a transaction logged during the stream must not push an old one onto the next page again")
+	assert.Equal(t, "tx100", ids[100])
+	assert.Equal(t, "tx149", ids[149])
+}
+
+func TestHandler_Authentication(t *testing.T) {
+	client, _, key := newTestClient(t, new(stubTransactionService))
+	ctx := context.Background()
+
+	t.Run("without credentials", func(t *testing.T) {
+		_, err := client.Deposit(ctx, &walletpb.DepositRequest{UserId: "user1", Amount: 500, Currency: "USD"})
+		assert.Equal(t, codes.Unauthenticated, status.Code(err))
+
+		stream, err := client.GetTransactionHistory(ctx, &walletpb.GetTransactionHistoryRequest{UserId: "user1"})
+		require.NoError(t, err)
+		_, err = stream.Recv()
+		assert.Equal(t, codes.Unauthenticated, status.Code(err))
+	})
+
+	t.Run("invalid token", func(t *testing.T) {
+		_, err := client.GetBalance(bearer(ctx, "forged"), &walletpb.GetBalanceRequest{})
+		assert.Equal(t, codes.Unauthenticated, status.Code(err))
+	})
+
+	t.Run("defaults to the caller's wallet", func(t *testing.T) {
+		resp, err := client.GetBalance(bearer(ctx, "reader"), &walletpb.GetBalanceRequest{})
+		require.NoError(t, err)
+		assert.Equal(t, "user1", resp.GetUserId())
+	})
+
+	t.Run("missing permission", func(t *testing.T) {
+		_, err := client.Deposit(bearer(ctx, "reader"), &walletpb.DepositRequest{Amount: 500, Currency: "USD"})
+		assert.Equal(t, codes.PermissionDenied, status.Code(err))
+	})
+
+	t.Run("another user's wallet", func(t *testing.T) {
+		_, err := client.Transfer(bearer(ctx, "user1"), &walletpb.TransferRequest{FromUserId: "user2", ToUserId: "user1", Amount: 100, Currency: "USD"})
+		assert.Equal(t, codes.PermissionDenied, status.Code(err))
+
+		stream, err := client.GetTransactionHistory(bearer(ctx, "user1"), &walletpb.GetTransactionHistoryRequest{UserId: "user2"})
+		require.NoError(t, err)
+		_, err = stream.Recv()
+		assert.Equal(t, codes.PermissionDenied, status.Code(err))
+	})
+
+	t.Run("signed with an API key", func(t *testing.T) {
+		req := &walletpb.DepositRequest{Amount: 500, Currency: "USD"}
+		signed := key.sign(t, ctx, walletpb.WalletService_Deposit_FullMethodName, req, "nonce-1")
+
+		_, err := client.Deposit(signed, req)
+		require.NoError(t, err)
+
+		_, err = client.Deposit(signed, req)
+		assert.Equal(t, codes.Unauthenticated, status.Code(err), "a replayed nonce must be rejected")
+
+		tampered := key.sign(t, ctx, walletpb.WalletService_Deposit_FullMethodName, req, "nonce-2")
+		_, err = client.Deposit(tampered, &walletpb.DepositRequest{Amount: 50000, Currency: "USD"})
+		assert.Equal(t, codes.Unauthenticated, status.Code(err), "the signature must cover the request")
+	})
+
+	t.Run("streaming call signed with an API key", func(t *testing.T) {
+		req := &walletpb.GetTransactionHistoryRequest{UserId: "user1"}
+		signed := key.sign(t, ctx, walletpb.WalletService_GetTransactionHistory_FullMethodName, req, "nonce-3")
+
+		stream, err := client.GetTransactionHistory(signed, req)
+		require.NoError(t, err)
+		_, err = stream.Recv()
+		assert.ErrorIs(t, err, io.EOF)
+	})
+}
+
+func TestToStatusError(t *testing.T) {
+	tests := []struct {
+		err  error
+		code codes.Code
+	}{
+		{wallet.ErrWalletNotFound, codes.NotFound},
+		{wallet.ErrInsufficientFunds, codes.FailedPrecondition},
+		{wallet.ErrInvalidAmount, codes.InvalidArgument},
+		{transaction.ErrTransactionNotFound, codes.NotFound},
+		{transaction.ErrInvalidUserID, codes.InvalidArgument},
+		{transaction.ErrInvalidTimeRange, codes.InvalidArgument},
+		{user.ErrUserNotFound, codes.NotFound},
+		{user.ErrUserDeactivated, codes.FailedPrecondition},
+		{kyc.ErrWithdrawalCapExceeded, codes.PermissionDenied},
+		{sanctions.ErrBlocked, codes.PermissionDenied},
+		{context.DeadlineExceeded, codes.DeadlineExceeded},
+		{errors.New("boom"), codes.Internal},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.err.Error(), func(t *testing.T) {
+			assert.Equal(t, tt.code, status.Code(toStatusError(tt.err)))
+		})
+	}
+}
@@ -0,0 +1,58 @@
+package grpc
+
+import (
+	"context"
+	"net"
+
+	"exchange/internal/domain/audit"
+	"exchange/internal/domain/auth"
+	"exchange/internal/ports/grpc/walletpb"
+
+	"github.com/gofrs/uuid"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/metadata"
+	"google.golang.org/grpc/peer"
+)
+
+// requestIDKey is the metadata key carrying the request ID, matching the HTTP header.
+const requestIDKey = "x-request-id"
+
+// NewServer serves h to callers that authenticator identifies, with the same credentials
+// the HTTP API accepts.
+func NewServer(authenticator Authenticator, h *Handler, opts ...grpc.ServerOption) *grpc.Server {
+	opts = append([]grpc.ServerOption{
+		grpc.ChainUnaryInterceptor(authUnaryInterceptor(authenticator), auditMetadataInterceptor),
+		grpc.ChainStreamInterceptor(authStreamInterceptor(authenticator)),
+	}, opts...)
+	srv := grpc.NewServer(opts...)
+	walletpb.RegisterWalletServiceServer(srv, h)
+	return srv
+}
+
+// auditMetadataInterceptor records the caller, the request ID and the peer address for
+// the audit log.
+func auditMetadataInterceptor(ctx context.Context, req any, _ *grpc.UnaryServerInfo, handler grpc.UnaryHandler) (any, error) {
+	var m audit.Metadata
+	if principal, ok := auth.PrincipalFromContext(ctx); ok {
+		m.Actor = principal.UserID
+	}
+	if md, ok := metadata.FromIncomingContext(ctx); ok {
+		if ids := md.Get(requestIDKey); len(ids) > 0 && len(ids[0]) <= 128 {
+			m.RequestID = ids[0]
+		}
+	}
+	if m.RequestID == "" {
+		id, err := uuid.NewV4()
+		if err != nil {
+			return nil, toStatusError(err)
+		}
+		m.RequestID = id.String()
+	}
+	if p, ok := peer.FromContext(ctx); ok && p.Addr != nil {
+		m.SourceIP = p.Addr.String()
+		if host, _, err := net.SplitHostPort(m.SourceIP); err == nil {
+			m.SourceIP = host
+		}
+	}
+	return handler(audit.WithMetadata(ctx, m), req)
+}
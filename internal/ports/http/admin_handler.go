@@ -0,0 +1,683 @@
+package http
+
+import (
+	"encoding/json"
+	"mime"
+	"net/http"
+	"strconv"
+	"strings"
+	"time"
+
+	"exchange/internal/domain/adjustment"
+	"exchange/internal/domain/audit"
+	"exchange/internal/domain/auth"
+	"exchange/internal/domain/kyc"
+	"exchange/internal/domain/risk"
+	"exchange/internal/domain/sanctions"
+	"exchange/internal/domain/transaction"
+	"exchange/internal/domain/user"
+	"exchange/internal/domain/wallet"
+	"exchange/internal/usecase"
+)
+
+// AdminHandler serves the back-office API under /admin. Every route requires the admin role.
+type AdminHandler struct {
+	AdminUC *usecase.AdminUseCase
+}
+
+func NewAdminHandler(adminUC *usecase.AdminUseCase) *AdminHandler {
+	return &AdminHandler{
+		AdminUC: adminUC,
+	}
+}
+
+func (h *AdminHandler) RegisterRoutes(mux *http.ServeMux) {
+	mux.HandleFunc("/admin/adjustments", requireRole(auth.RoleAdmin, h.adjustmentsHandler))
+	mux.HandleFunc("/admin/adjustments/", requireRole(auth.RoleAdmin, h.adjustmentHandler))
+	mux.HandleFunc("/admin/wallets", requireRole(auth.RoleAdmin, h.searchWalletsHandler))
+	mux.HandleFunc("/admin/transactions", requireRole(auth.RoleAdmin, h.searchTransactionsHandler))
+	mux.HandleFunc("/admin/transactions/", requireRole(auth.RoleAdmin, h.transactionHandler))
+	mux.HandleFunc("/admin/audit", requireRole(auth.RoleAdmin, h.listAuditEntriesHandler))
+	mux.HandleFunc("/admin/audit/verify", requireRole(auth.RoleAdmin, h.verifyAuditLogHandler))
+	mux.HandleFunc("/admin/users/", requireRole(auth.RoleAdmin, h.userHandler))
+	mux.HandleFunc("/admin/kyc/submissions", requireRole(auth.RoleAdmin, h.listKYCSubmissionsHandler))
+	mux.HandleFunc("/admin/kyc/submissions/", requireRole(auth.RoleAdmin, h.kycSubmissionHandler))
+	mux.HandleFunc("/admin/risk/assessments", requireRole(auth.RoleAdmin, h.listRiskAssessmentsHandler))
+	mux.HandleFunc("/admin/sanctions/cases", requireRole(auth.RoleAdmin, h.listSanctionsCasesHandler))
+	mux.HandleFunc("/admin/sanctions/cases/", requireRole(auth.RoleAdmin, h.sanctionsCaseHandler))
+	mux.HandleFunc("/admin/periods", requireRole(auth.RoleAdmin, h.listPeriodClosesHandler))
+	mux.HandleFunc("/admin/periods/", requireRole(auth.RoleAdmin, h.periodHandler))
+	mux.HandleFunc("/admin/reports/eod", requireRole(auth.RoleAdmin, h.eodReportHandler))
+}
+
+// requireRole rejects requests whose principal lacks role.
+func requireRole(role string, next http.HandlerFunc) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		principal, ok := auth.PrincipalFromContext(r.Context())
+		if !ok {
+			handleError(w, auth.ErrUnauthenticated)
+			return
+		}
+		if !principal.HasRole(role) {
+			handleError(w, auth.ErrForbidden)
+			return
+		}
+		next(w, r)
+	}
+}
+
+// adminID returns the ID of the admin making the request; requireRole guarantees one.
+func adminID(r *http.Request) string {
+	principal, _ := auth.PrincipalFromContext(r.Context())
+	return principal.UserID
+}
+
+func (h *AdminHandler) adjustmentsHandler(w http.ResponseWriter, r *http.Request) {
+	// GET  /admin/adjustments?status=pending&limit=10&offset=0
+	// POST /admin/adjustments
+	switch r.Method {
+	case http.MethodGet:
+		h.listAdjustmentsHandler(w, r)
+	case http.MethodPost:
+		h.requestAdjustmentHandler(w, r)
+	default:
+		http.Error(w, "method not allowed", http.StatusMethodNotAllowed)
+	}
+}
+
+func (h *AdminHandler) requestAdjustmentHandler(w http.ResponseWriter, r *http.Request) {
+	var req AdjustmentRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		http.Error(w, "invalid request body", http.StatusBadRequest)
+		return
+	}
+
+	var effectiveAt time.Time
+	if req.EffectiveAt != "" {
+		var err error
+		if effectiveAt, err = parseStatementTime(req.EffectiveAt, false); err != nil {
+			http.Error(w, "invalid effective_at value", http.StatusBadRequest)
+			return
+		}
+	}
+
+	ctx := r.Context()
+	a, err := h.AdminUC.RequestAdjustment(ctx, adminID(r), usecase.AdjustmentRequest{
+		UserID:      req.UserID,
+		Direction:   adjustment.Direction(req.Direction),
+		Amount:      req.Amount,
+		Currency:    req.Currency,
+		Reason:      adjustment.ReasonCode(req.ReasonCode),
+		Note:        req.Note,
+		EffectiveAt: effectiveAt,
+	})
+	if err != nil {
+		handleError(w, err)
+		return
+	}
+
+	writeJSONStatus(w, http.StatusCreated, newAdjustmentResponse(a))
+}
+
+func (h *AdminHandler) listAdjustmentsHandler(w http.ResponseWriter, r *http.Request) {
+	query := r.URL.Query()
+	limit, offset, err := parsePagination(query)
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+
+	ctx := r.Context()
+	adjustments, err := h.AdminUC.ListAdjustments(ctx, adjustment.Status(query.Get("status")), limit, offset)
+	if err != nil {
+		handleError(w, err)
+		return
+	}
+
+	resp := make([]AdjustmentResponse, 0, len(adjustments))
+	for _, a := range adjustments {
+		resp = append(resp, newAdjustmentResponse(a))
+	}
+	writeJSON(w, resp)
+}
+
+func (h *AdminHandler) adjustmentHandler(w http.ResponseWriter, r *http.Request) {
+	// GET  /admin/adjustments/{id}
+	// POST /admin/adjustments/{id}/approve
+	// POST /admin/adjustments/{id}/reject
+	segments := strings.Split(strings.TrimPrefix(r.URL.Path, "/admin/adjustments/"), "/")
+	id := segments[0]
+	ctx := r.Context()
+
+	var a adjustment.Adjustment
+	var err error
+	switch {
+	case len(segments) == 1 && r.Method == http.MethodGet:
+		a, err = h.AdminUC.GetAdjustment(ctx, id)
+	case len(segments) == 2 && segments[1] == "approve" && r.Method == http.MethodPost:
+		a, err = h.AdminUC.ApproveAdjustment(ctx, adminID(r), id)
+	case len(segments) == 2 && segments[1] == "reject" && r.Method == http.MethodPost:
+		a, err = h.AdminUC.RejectAdjustment(ctx, adminID(r), id)
+	default:
+		http.Error(w, "not found", http.StatusNotFound)
+		return
+	}
+	if err != nil {
+		handleError(w, err)
+		return
+	}
+
+	writeJSON(w, newAdjustmentResponse(a))
+}
+
+func (h *AdminHandler) searchWalletsHandler(w http.ResponseWriter, r *http.Request) {
+	// GET /admin/wallets?user_id_prefix=&currency=&min_balance=&max_balance=&limit=10&offset=0
+	if r.Method != http.MethodGet {
+		http.Error(w, "method not allowed", http.StatusMethodNotAllowed)
+		return
+	}
+
+	query := r.URL.Query()
+	limit, offset, err := parsePagination(query)
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+
+	filter := wallet.SearchFilter{
+		UserIDPrefix: query.Get("user_id_prefix"),
+		Currency:     query.Get("currency"),
+	}
+	for name, dst := range map[string]**int64{"min_balance": &filter.MinBalance, "max_balance": &filter.MaxBalance} {
+		if value := query.Get(name); value != "" {
+			balance, err := strconv.ParseInt(value, 10, 64)
+			if err != nil {
+				http.Error(w, "invalid "+name+" value", http.StatusBadRequest)
+				return
+			}
+			*dst = &balance
+		}
+	}
+
+	ctx := r.Context()
+	wallets, err := h.AdminUC.SearchWallets(ctx, filter, limit, offset)
+	if err != nil {
+		handleError(w, err)
+		return
+	}
+
+	resp := make([]WalletResponse, 0, len(wallets))
+	for _, wal := range wallets {
+		resp = append(resp, newWalletResponse(wal))
+	}
+	writeJSON(w, resp)
+}
+
+func (h *AdminHandler) searchTransactionsHandler(w http.ResponseWriter, r *http.Request) {
+	// GET /admin/transactions?user_id=&type=&batch_id=&status=&from=&to=&min_amount=&max_amount=&limit=10&offset=0
+	if r.Method != http.MethodGet {
+		http.Error(w, "method not allowed", http.StatusMethodNotAllowed)
+		return
+	}
+
+	query := r.URL.Query()
+	limit, offset, err := parsePagination(query)
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+
+	filter := transaction.SearchFilter{
+		UserID:  query.Get("user_id"),
+		Type:    transaction.TransactionType(strings.ToUpper(query.Get("type"))),
+		BatchID: query.Get("batch_id"),
+		Status:  transaction.Status(query.Get("status")),
+	}
+	if fromStr := query.Get("from"); fromStr != "" {
+		if filter.From, err = parseStatementTime(fromStr, false); err != nil {
+			http.Error(w, "invalid from value", http.StatusBadRequest)
+			return
+		}
+	}
+	if toStr := query.Get("to"); toStr != "" {
+		if filter.To, err = parseStatementTime(toStr, true); err != nil {
+			http.Error(w, "invalid to value", http.StatusBadRequest)
+			return
+		}
+	}
+	for name, dst := range map[string]*int64{"min_amount": &filter.MinAmount, "max_amount": &filter.MaxAmount} {
+		if value := query.Get(name); value != "" {
+			if *dst, err = strconv.ParseInt(value, 10, 64); err != nil {
+				http.Error(w, "invalid "+name+" value", http.StatusBadRequest)
+				return
+			}
+		}
+	}
+
+	ctx := r.Context()
+	txs, err := h.AdminUC.SearchTransactions(ctx, filter, limit, offset)
+	if err != nil {
+		handleError(w, err)
+		return
+	}
+
+	resp := make([]TransactionResponse, 0, len(txs))
+	for _, tx := range txs {
+		resp = append(resp, newTransactionResponse(tx))
+	}
+	writeJSON(w, resp)
+}
+
+func (h *AdminHandler) transactionHandler(w http.ResponseWriter, r *http.Request) {
+	// POST /admin/transactions/{id}/reverse
+	// POST /admin/transactions/{id}/status
+	// POST /admin/transactions/{id}/approve
+	// POST /admin/transactions/{id}/reject
+	segments := strings.Split(strings.TrimPrefix(r.URL.Path, "/admin/transactions/"), "/")
+	if len(segments) != 2 || segments[0] == "" {
+		http.Error(w, "not found", http.StatusNotFound)
+		return
+	}
+	switch segments[1] {
+	case "reverse", "status", "approve", "reject":
+	default:
+		http.Error(w, "not found", http.StatusNotFound)
+		return
+	}
+	if r.Method != http.MethodPost {
+		http.Error(w, "method not allowed", http.StatusMethodNotAllowed)
+		return
+	}
+
+	ctx := r.Context()
+	switch segments[1] {
+	case "status":
+		var req TransactionStatusRequest
+		if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+			http.Error(w, "invalid request body", http.StatusBadRequest)
+			return
+		}
+		tx, err := h.AdminUC.UpdateTransactionStatus(ctx, segments[0], transaction.Status(req.Status))
+		if err != nil {
+			handleError(w, err)
+			return
+		}
+		writeJSON(w, newTransactionResponse(tx))
+		return
+	case "approve", "reject":
+		var req WithdrawalReviewRequest
+		if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+			http.Error(w, "invalid request body", http.StatusBadRequest)
+			return
+		}
+		tx, err := h.AdminUC.ReviewWithdrawal(ctx, segments[0], segments[1] == "approve", req.Reason)
+		if err != nil {
+			handleError(w, err)
+			return
+		}
+		writeJSON(w, newTransactionResponse(tx))
+		return
+	}
+
+	var req ReversalRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		http.Error(w, "invalid request body", http.StatusBadRequest)
+		return
+	}
+
+	tx, err := h.AdminUC.ReverseTransaction(ctx, segments[0], req.Amount)
+	if err != nil {
+		handleError(w, err)
+		return
+	}
+
+	writeJSONStatus(w, http.StatusCreated, newTransactionResponse(tx))
+}
+
+func (h *AdminHandler) userHandler(w http.ResponseWriter, r *http.Request) {
+	// POST /admin/users/{user_id}/status
+	// POST /admin/users/{user_id}/kyc
+	segments := strings.Split(strings.TrimPrefix(r.URL.Path, "/admin/users/"), "/")
+	if len(segments) != 2 || segments[0] == "" || (segments[1] != "status" && segments[1] != "kyc") {
+		http.Error(w, "not found", http.StatusNotFound)
+		return
+	}
+	if r.Method != http.MethodPost {
+		http.Error(w, "method not allowed", http.StatusMethodNotAllowed)
+		return
+	}
+
+	ctx := r.Context()
+	if segments[1] == "kyc" {
+		var req KYCLevelRequest
+		if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+			http.Error(w, "invalid request body", http.StatusBadRequest)
+			return
+		}
+		c, err := h.AdminUC.SetKYCLevel(ctx, adminID(r), segments[0], kyc.Level(req.Level), req.Reason)
+		if err != nil {
+			handleError(w, err)
+			return
+		}
+		writeJSON(w, newKYCLevelChangeResponse(c))
+		return
+	}
+
+	var req UserStatusRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		http.Error(w, "invalid request body", http.StatusBadRequest)
+		return
+	}
+
+	u, err := h.AdminUC.SetUserStatus(ctx, segments[0], user.Status(req.Status))
+	if err != nil {
+		handleError(w, err)
+		return
+	}
+	writeJSON(w, newUserResponse(u))
+}
+
+func (h *AdminHandler) listKYCSubmissionsHandler(w http.ResponseWriter, r *http.Request) {
+	// GET /admin/kyc/submissions?user_id=&status=pending&limit=10&offset=0
+	if r.Method != http.MethodGet {
+		http.Error(w, "method not allowed", http.StatusMethodNotAllowed)
+		return
+	}
+
+	query := r.URL.Query()
+	limit, offset, err := parsePagination(query)
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+
+	filter := kyc.Filter{
+		UserID: query.Get("user_id"),
+		Status: kyc.SubmissionStatus(query.Get("status")),
+	}
+
+	submissions, err := h.AdminUC.ListKYCSubmissions(r.Context(), filter, limit, offset)
+	if err != nil {
+		handleError(w, err)
+		return
+	}
+
+	resp := make([]KYCSubmissionResponse, 0, len(submissions))
+	for _, s := range submissions {
+		resp = append(resp, newKYCSubmissionResponse(s))
+	}
+	writeJSON(w, resp)
+}
+
+func (h *AdminHandler) kycSubmissionHandler(w http.ResponseWriter, r *http.Request) {
+	// GET  /admin/kyc/submissions/{id}/document
+	// POST /admin/kyc/submissions/{id}/approve
+	// POST /admin/kyc/submissions/{id}/reject
+	segments := strings.Split(strings.TrimPrefix(r.URL.Path, "/admin/kyc/submissions/"), "/")
+	if len(segments) != 2 || segments[0] == "" {
+		http.Error(w, "not found", http.StatusNotFound)
+		return
+	}
+	id := segments[0]
+	ctx := r.Context()
+
+	switch {
+	case segments[1] == "document" && r.Method == http.MethodGet:
+		s, content, err := h.AdminUC.GetKYCDocument(ctx, id)
+		if err != nil {
+			handleError(w, err)
+			return
+		}
+		w.Header().Set("Content-Type", s.ContentType)
+		w.Header().Set("Content-Disposition", mime.FormatMediaType("attachment", map[string]string{"filename": s.FileName}))
+		w.Write(content)
+	case (segments[1] == "approve" || segments[1] == "reject") && r.Method == http.MethodPost:
+		var req KYCReviewRequest
+		if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+			http.Error(w, "invalid request body", http.StatusBadRequest)
+			return
+		}
+		s, err := h.AdminUC.ReviewKYCSubmission(ctx, adminID(r), id, segments[1] == "approve", req.Reason)
+		if err != nil {
+			handleError(w, err)
+			return
+		}
+		writeJSON(w, newKYCSubmissionResponse(s))
+	default:
+		http.Error(w, "not found", http.StatusNotFound)
+	}
+}
+
+func (h *AdminHandler) listSanctionsCasesHandler(w http.ResponseWriter, r *http.Request) {
+	// GET /admin/sanctions/cases?subject_id=&status=open&limit=10&offset=0
+	if r.Method != http.MethodGet {
+		http.Error(w, "method not allowed", http.StatusMethodNotAllowed)
+		return
+	}
+
+	query := r.URL.Query()
+	limit, offset, err := parsePagination(query)
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+
+	filter := sanctions.Filter{
+		SubjectID: query.Get("subject_id"),
+		Status:    sanctions.CaseStatus(query.Get("status")),
+	}
+
+	cases, err := h.AdminUC.ListSanctionsCases(r.Context(), filter, limit, offset)
+	if err != nil {
+		handleError(w, err)
+		return
+	}
+
+	resp := make([]SanctionsCaseResponse, 0, len(cases))
+	for _, c := range cases {
+		resp = append(resp, newSanctionsCaseResponse(c))
+	}
+	writeJSON(w, resp)
+}
+
+func (h *AdminHandler) sanctionsCaseHandler(w http.ResponseWriter, r *http.Request) {
+	// POST /admin/sanctions/cases/{id}/clear
+	segments := strings.Split(strings.TrimPrefix(r.URL.Path, "/admin/sanctions/cases/"), "/")
+	if len(segments) != 2 || segments[0] == "" || segments[1] != "clear" {
+		http.Error(w, "not found", http.StatusNotFound)
+		return
+	}
+	if r.Method != http.MethodPost {
+		http.Error(w, "method not allowed", http.StatusMethodNotAllowed)
+		return
+	}
+
+	var req SanctionsClearRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		http.Error(w, "invalid request body", http.StatusBadRequest)
+		return
+	}
+
+	c, err := h.AdminUC.ClearSanctionsCase(r.Context(), adminID(r), segments[0], req.Reason)
+	if err != nil {
+		handleError(w, err)
+		return
+	}
+	writeJSON(w, newSanctionsCaseResponse(c))
+}
+
+func (h *AdminHandler) listPeriodClosesHandler(w http.ResponseWriter, r *http.Request) {
+	// GET /admin/periods?limit=10&offset=0
+	if r.Method != http.MethodGet {
+		http.Error(w, "method not allowed", http.StatusMethodNotAllowed)
+		return
+	}
+
+	limit, offset, err := parsePagination(r.URL.Query())
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+
+	closes, err := h.AdminUC.ListPeriodCloses(r.Context(), limit, offset)
+	if err != nil {
+		handleError(w, err)
+		return
+	}
+
+	resp := make([]PeriodCloseResponse, 0, len(closes))
+	for _, c := range closes {
+		resp = append(resp, newPeriodCloseResponse(c))
+	}
+	writeJSON(w, resp)
+}
+
+func (h *AdminHandler) periodHandler(w http.ResponseWriter, r *http.Request) {
+	// POST /admin/periods/{month}/close
+	segments := strings.Split(strings.TrimPrefix(r.URL.Path, "/admin/periods/"), "/")
+	if len(segments) != 2 || segments[1] != "close" {
+		http.Error(w, "not found", http.StatusNotFound)
+		return
+	}
+	if r.Method != http.MethodPost {
+		http.Error(w, "method not allowed", http.StatusMethodNotAllowed)
+		return
+	}
+
+	month, err := time.Parse("2006-01", segments[0])
+	if err != nil {
+		http.Error(w, "invalid month, expected YYYY-MM", http.StatusBadRequest)
+		return
+	}
+
+	c, err := h.AdminUC.ClosePeriod(r.Context(), adminID(r), month)
+	if err != nil {
+		handleError(w, err)
+		return
+	}
+	writeJSON(w, newPeriodCloseResponse(c))
+}
+
+func (h *AdminHandler) eodReportHandler(w http.ResponseWriter, r *http.Request) {
+	// GET /admin/reports/eod?date=2024-03-15
+	if r.Method != http.MethodGet {
+		http.Error(w, "method not allowed", http.StatusMethodNotAllowed)
+		return
+	}
+
+	day, err := time.Parse("2006-01-02", r.URL.Query().Get("date"))
+	if err != nil {
+		http.Error(w, "invalid date value, expected YYYY-MM-DD", http.StatusBadRequest)
+		return
+	}
+
+	report, err := h.AdminUC.ReconcileDay(r.Context(), day)
+	if err != nil {
+		handleError(w, err)
+		return
+	}
+	writeJSON(w, newReconciliationResponse(report))
+}
+
+func (h *AdminHandler) listAuditEntriesHandler(w http.ResponseWriter, r *http.Request) {
+	// GET /admin/audit?actor=&action=&user_id=&transaction_id=&request_id=&from=&to=&limit=10&offset=0
+	if r.Method != http.MethodGet {
+		http.Error(w, "method not allowed", http.StatusMethodNotAllowed)
+		return
+	}
+
+	query := r.URL.Query()
+	limit, offset, err := parsePagination(query)
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+
+	filter := audit.Filter{
+		Actor:         query.Get("actor"),
+		Action:        audit.Action(query.Get("action")),
+		UserID:        query.Get("user_id"),
+		TransactionID: query.Get("transaction_id"),
+		RequestID:     query.Get("request_id"),
+	}
+	if fromStr := query.Get("from"); fromStr != "" {
+		if filter.From, err = parseStatementTime(fromStr, false); err != nil {
+			http.Error(w, "invalid from value", http.StatusBadRequest)
+			return
+		}
+	}
+	if toStr := query.Get("to"); toStr != "" {
+		if filter.To, err = parseStatementTime(toStr, true); err != nil {
+			http.Error(w, "invalid to value", http.StatusBadRequest)
+			return
+		}
+	}
+
+	ctx := r.Context()
+	entries, err := h.AdminUC.ListAuditEntries(ctx, filter, limit, offset)
+	if err != nil {
+		handleError(w, err)
+		return
+	}
+
+	resp := make([]AuditEntryResponse, 0, len(entries))
+	for _, e := range entries {
+		resp = append(resp, newAuditEntryResponse(e))
+	}
+	writeJSON(w, resp)
+}
+
+func (h *AdminHandler) verifyAuditLogHandler(w http.ResponseWriter, r *http.Request) {
+	// GET /admin/audit/verify
+	if r.Method != http.MethodGet {
+		http.Error(w, "method not allowed", http.StatusMethodNotAllowed)
+		return
+	}
+
+	ctx := r.Context()
+	report, err := h.AdminUC.VerifyAuditLog(ctx)
+	if err != nil {
+		handleError(w, err)
+		return
+	}
+
+	writeJSON(w, AuditChainResponse{
+		Entries:   report.Entries,
+		Valid:     report.Valid,
+		BrokenAt:  report.BrokenAt,
+		BrokenWhy: report.BrokenWhy,
+	})
+}
+
+func (h *AdminHandler) listRiskAssessmentsHandler(w http.ResponseWriter, r *http.Request) {
+	// GET /admin/risk/assessments?user_id=&decision=review&limit=10&offset=0
+	if r.Method != http.MethodGet {
+		http.Error(w, "method not allowed", http.StatusMethodNotAllowed)
+		return
+	}
+
+	query := r.URL.Query()
+	limit, offset, err := parsePagination(query)
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+
+	filter := risk.Filter{
+		UserID:   query.Get("user_id"),
+		Decision: risk.Decision(query.Get("decision")),
+	}
+
+	ctx := r.Context()
+	assessments, err := h.AdminUC.ListRiskAssessments(ctx, filter, limit, offset)
+	if err != nil {
+		handleError(w, err)
+		return
+	}
+
+	resp := make([]RiskAssessmentResponse, 0, len(assessments))
+	for _, a := range assessments {
+		resp = append(resp, newRiskAssessmentResponse(a))
+	}
+	writeJSON(w, resp)
+}
@@ -0,0 +1,179 @@
+package http
+
+import (
+	"bytes"
+	"io"
+	"net/http"
+	"slices"
+	"strconv"
+	"strings"
+	"time"
+
+	"exchange/internal/domain/auth"
+)
+
+const (
+	HeaderAPIKey    = "X-API-Key"
+	HeaderTimestamp = "X-Timestamp"
+	HeaderNonce     = "X-Nonce"
+	HeaderSignature = "X-Signature"
+
+	// maxSignedBodySize bounds how much of a request body is buffered to verify its signature.
+	maxSignedBodySize = 1 << 20
+)
+
+// Authenticator identifies the principal behind a request.
+type Authenticator interface {
+	Authenticate(r *http.Request) (auth.Principal, error)
+}
+
+// APIKeyAuthenticator verifies requests signed with an API key secret. Clients send the
+// key ID, a unix timestamp, a unique nonce and the hex HMAC-SHA256 of
+// auth.CanonicalRequest in the X-API-Key, X-Timestamp, X-Nonce and X-Signature headers.
+type APIKeyAuthenticator struct {
+	service auth.APIKeyServiceInterface
+}
+
+func NewAPIKeyAuthenticator(service auth.APIKeyServiceInterface) *APIKeyAuthenticator {
+	return &APIKeyAuthenticator{
+		service: service,
+	}
+}
+
+func (a *APIKeyAuthenticator) Authenticate(r *http.Request) (auth.Principal, error) {
+	keyID := r.Header.Get(HeaderAPIKey)
+	if keyID == "" {
+		return auth.Principal{}, auth.ErrUnauthenticated
+	}
+
+	timestamp, err := strconv.ParseInt(r.Header.Get(HeaderTimestamp), 10, 64)
+	if err != nil {
+		return auth.Principal{}, auth.ErrInvalidSignature
+	}
+	nonce := r.Header.Get(HeaderNonce)
+
+	var body []byte
+	if r.Body != nil {
+		body, err = io.ReadAll(io.LimitReader(r.Body, maxSignedBodySize))
+		if err != nil {
+			return auth.Principal{}, auth.ErrInvalidSignature
+		}
+		r.Body.Close()
+		r.Body = io.NopCloser(bytes.NewReader(body))
+	}
+
+	return a.service.Authenticate(r.Context(), auth.SignedRequest{
+		KeyID:     keyID,
+		Timestamp: time.Unix(timestamp, 0),
+		Nonce:     nonce,
+		Signature: r.Header.Get(HeaderSignature),
+		Payload:   auth.CanonicalRequest(timestamp, nonce, r.Method, r.URL.RequestURI(), body),
+	})
+}
+
+// BearerAuthenticator verifies OAuth2/OIDC access tokens sent as
+// "Authorization: Bearer <token>".
+type BearerAuthenticator struct {
+	verifier auth.TokenVerifier
+}
+
+func NewBearerAuthenticator(verifier auth.TokenVerifier) *BearerAuthenticator {
+	return &BearerAuthenticator{
+		verifier: verifier,
+	}
+}
+
+func (a *BearerAuthenticator) Authenticate(r *http.Request) (auth.Principal, error) {
+	header := r.Header.Get("Authorization")
+	if header == "" {
+		return auth.Principal{}, auth.ErrUnauthenticated
+	}
+	scheme, token, ok := strings.Cut(header, " ")
+	if !ok || !strings.EqualFold(scheme, "Bearer") || token == "" {
+		return auth.Principal{}, auth.ErrInvalidToken
+	}
+	return a.verifier.VerifyToken(r.Context(), strings.TrimSpace(token))
+}
+
+// ChainAuthenticator tries each authenticator in turn and uses the first one for which
+// the request carries credentials.
+type ChainAuthenticator []Authenticator
+
+func (c ChainAuthenticator) Authenticate(r *http.Request) (auth.Principal, error) {
+	for _, authenticator := range c {
+		principal, err := authenticator.Authenticate(r)
+		if err != auth.ErrUnauthenticated {
+			return principal, err
+		}
+	}
+	return auth.Principal{}, auth.ErrUnauthenticated
+}
+
+// RequireAuthentication rejects requests that authenticator cannot identify and stores
+// the principal of the others in the request context. Requests to publicPaths are
+// passed through unauthenticated; a public path ending in a slash, like a ServeMux
+// pattern, covers every path below it.
+func RequireAuthentication(authenticator Authenticator, next http.Handler, publicPaths ...string) http.Handler {
+	public := make(map[string]bool, len(publicPaths))
+	var publicPrefixes []string
+	for _, p := range publicPaths {
+		if strings.HasSuffix(p, "/") {
+			publicPrefixes = append(publicPrefixes, p)
+			continue
+		}
+		public[p] = true
+	}
+	isPublic := func(path string) bool {
+		return public[path] || slices.ContainsFunc(publicPrefixes, func(prefix string) bool {
+			return strings.HasPrefix(path, prefix)
+		})
+	}
+
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if isPublic(r.URL.Path) {
+			next.ServeHTTP(w, r)
+			return
+		}
+
+		principal, err := authenticator.Authenticate(r)
+		if err != nil {
+			handleError(w, err)
+			return
+		}
+		next.ServeHTTP(w, r.WithContext(auth.WithPrincipal(r.Context(), principal)))
+	})
+}
+
+// actingUserID resolves the wallet a request acts on: the authenticated principal's own
+// unless the client names another one, which only admins may do.
+func actingUserID(r *http.Request, requested string, perm auth.Permission) (string, error) {
+	principal, ok := auth.PrincipalFromContext(r.Context())
+	if !ok {
+		return "", auth.ErrUnauthenticated
+	}
+	if !principal.HasPermission(perm) {
+		return "", auth.ErrForbidden
+	}
+	if requested == "" {
+		return principal.UserID, nil
+	}
+	if !principal.CanActAs(requested) {
+		return "", auth.ErrForbidden
+	}
+	return requested, nil
+}
+
+// authorizeParty checks that the request may act with perm on one of the wallets of
+// userIDs; empty user IDs, such as the missing side of a deposit, never match.
+func authorizeParty(r *http.Request, perm auth.Permission, userIDs ...string) error {
+	err := auth.ErrForbidden
+	for _, userID := range userIDs {
+		if userID == "" {
+			continue
+		}
+		if _, err = actingUserID(r, userID, perm); err == nil {
+			return nil
+		}
+	}
+	return err
+}
@@ -1,5 +1,26 @@
 package http
 
+import (
+	"time"
+
+	"exchange/internal/domain/adjustment"
+	"exchange/internal/domain/audit"
+	"exchange/internal/domain/escrow"
+	"exchange/internal/domain/interest"
+	"exchange/internal/domain/kyc"
+	"exchange/internal/domain/limit"
+	"exchange/internal/domain/payment"
+	"exchange/internal/domain/period"
+	"exchange/internal/domain/reserves"
+	"exchange/internal/domain/risk"
+	"exchange/internal/domain/sanctions"
+	"exchange/internal/domain/transaction"
+	"exchange/internal/domain/user"
+	"exchange/internal/domain/wallet"
+	"exchange/internal/domain/webhook"
+	"exchange/internal/usecase"
+)
+
 type DepositRequest struct {
 	UserID   string `json:"user_id"`
 	Amount   int64  `json:"amount"`
@@ -7,9 +28,10 @@ type DepositRequest struct {
 }
 
 type WithdrawRequest struct {
-	UserID   string `json:"user_id"`
-	Amount   int64  `json:"amount"`
-	Currency string `json:"currency"`
+	UserID      string `json:"user_id"`
+	Amount      int64  `json:"amount"`
+	Currency    string `json:"currency"`
+	Destination string `json:"destination,omitempty"`
 }
 
 type TransferRequest struct {
@@ -19,9 +41,117 @@ type TransferRequest struct {
 	Currency   string `json:"currency"`
 }
 
+type BatchTransferRequest struct {
+	Mode      string            `json:"mode"`
+	Transfers []TransferRequest `json:"transfers"`
+}
+
+type BatchTransferItemResponse struct {
+	Index         int    `json:"index"`
+	Status        string `json:"status"`
+	TransactionID string `json:"transaction_id,omitempty"`
+	ErrorCode     string `json:"error_code,omitempty"`
+	Error         string `json:"error,omitempty"`
+}
+
+type BatchTransferResponse struct {
+	BatchID   string                      `json:"batch_id"`
+	Mode      string                      `json:"mode"`
+	Succeeded int                         `json:"succeeded"`
+	Failed    int                         `json:"failed"`
+	Results   []BatchTransferItemResponse `json:"results"`
+}
+
+// SplitShareRequest is one user's share of a split transfer: either a fixed amount or a
+// percentage, with at most two decimals, of the total.
+type SplitShareRequest struct {
+	UserID  string  `json:"user_id"`
+	Amount  int64   `json:"amount,omitempty"`
+	Percent float64 `json:"percent,omitempty"`
+}
+
+type SplitTransferRequest struct {
+	Direction string              `json:"direction"`
+	UserID    string              `json:"user_id"`
+	Amount    int64               `json:"amount"`
+	Currency  string              `json:"currency"`
+	Remainder string              `json:"remainder"`
+	Shares    []SplitShareRequest `json:"shares"`
+}
+
+type SplitTransferItemResponse struct {
+	FromUserID    string `json:"from_user_id"`
+	ToUserID      string `json:"to_user_id"`
+	Amount        int64  `json:"amount"`
+	TransactionID string `json:"transaction_id"`
+}
+
+type SplitTransferResponse struct {
+	GroupID   string                      `json:"group_id"`
+	Direction string                      `json:"direction"`
+	Transfers []SplitTransferItemResponse `json:"transfers"`
+}
+
+type StatusResponse struct {
+	Status        string `json:"status"`
+	TransactionID string `json:"transaction_id,omitempty"` // Set when the request awaits approval.
+}
+
 type BalanceResponse struct {
 	UserID  string `json:"user_id"`
 	Balance int64  `json:"balance"`
+	// Held is the part of the balance reserved for pending withdrawals.
+	Held      int64 `json:"held"`
+	Available int64 `json:"available"`
+	// AsOf is set when the balance was requested for a past point in time.
+	AsOf string `json:"as_of,omitempty"`
+}
+
+// LimitResponse is a limit with its usage in the current period. MaxAmount and MaxCount are
+// 0 when not capped, in which case the matching remaining field is left out.
+type LimitResponse struct {
+	Operation       string `json:"operation"`
+	Currency        string `json:"currency"`
+	Period          string `json:"period"`
+	MaxAmount       int64  `json:"max_amount"`
+	MaxCount        int    `json:"max_count"`
+	UsedAmount      int64  `json:"used_amount"`
+	UsedCount       int    `json:"used_count"`
+	RemainingAmount *int64 `json:"remaining_amount,omitempty"`
+	RemainingCount  *int   `json:"remaining_count,omitempty"`
+	ResetsAt        string `json:"resets_at"`
+}
+
+func newLimitResponse(a limit.Allowance) LimitResponse {
+	resp := LimitResponse{
+		Operation:  string(a.Operation),
+		Currency:   a.Currency,
+		Period:     string(a.Period),
+		MaxAmount:  a.MaxAmount,
+		MaxCount:   a.MaxCount,
+		UsedAmount: a.Used.Amount,
+		UsedCount:  a.Used.Count,
+		ResetsAt:   a.ResetsAt.Format("2006-01-02 15:04:05"),
+	}
+	if remaining := a.RemainingAmount(); remaining >= 0 {
+		resp.RemainingAmount = &remaining
+	}
+	if remaining := a.RemainingCount(); remaining >= 0 {
+		resp.RemainingCount = &remaining
+	}
+	return resp
+}
+
+type LimitsResponse struct {
+	UserID string          `json:"user_id"`
+	Limits []LimitResponse `json:"limits"`
+}
+
+// LimitExceededResponse names the limit a withdrawal or transfer would have exceeded and
+// what is left of it.
+type LimitExceededResponse struct {
+	Error string        `json:"error"`
+	Limit LimitResponse `json:"limit"`
 }
 
 type TransactionResponse struct {
@@ -31,5 +161,789 @@ type TransactionResponse struct {
 	Amount     int64  `json:"amount"`
 	Currency   string `json:"currency"`
 	Type       string `json:"type"`
-	CreatedAt  string `json:"created_at"`
+	BatchID    string `json:"batch_id,omitempty"`
+	// OriginalTransactionID is the transaction undone by a REVERSAL or REFUND.
+	OriginalTransactionID string `json:"original_transaction_id,omitempty"`
+	Status                string `json:"status"`
+	StatusReason          string `json:"status_reason,omitempty"` // StatusReason explains why the transaction awaits approval, failed or was cancelled.
+	CreatedAt             string `json:"created_at"`
+	ProcessingAt          string `json:"processing_at,omitempty"`
+	CompletedAt           string `json:"completed_at,omitempty"`
+	FailedAt              string `json:"failed_at,omitempty"`
+	CancelledAt           string `json:"cancelled_at,omitempty"`
+}
+
+func newTransactionResponse(tx transaction.Transaction) TransactionResponse {
+	return TransactionResponse{
+		ID:         tx.ID,
+		FromUserID: tx.FromUserID,
+		ToUserID:   tx.ToUserID,
+		Amount:     tx.Amount,
+		Currency:   tx.Currency,
+		Type:       string(tx.Type),
+		BatchID:    tx.BatchID,
+		Status:     string(tx.Status),
+		CreatedAt:  tx.CreatedAt.Format("2006-01-02 15:04:05"),
+
+		OriginalTransactionID: tx.OriginalID,
+		StatusReason:          tx.StatusReason,
+		ProcessingAt:          formatOptionalTime(tx.ProcessingAt),
+		CompletedAt:           formatOptionalTime(tx.CompletedAt),
+		FailedAt:              formatOptionalTime(tx.FailedAt),
+		CancelledAt:           formatOptionalTime(tx.CancelledAt),
+	}
+}
+
+// formatOptionalTime formats t like the other timestamps, or returns "" when it is unset.
+func formatOptionalTime(t *time.Time) string {
+	if t == nil {
+		return ""
+	}
+	return t.Format("2006-01-02 15:04:05")
+}
+
+type TransactionStatusRequest struct {
+	Status string `json:"status"`
+}
+
+// WithdrawalReviewRequest approves or rejects a withdrawal awaiting approval; rejecting
+// requires a reason.
+type WithdrawalReviewRequest struct {
+	Reason string `json:"reason"`
+}
+
+type RefundRequest struct {
+	// Amount to refund; 0 refunds whatever is left of the transfer.
+	Amount int64 `json:"amount"`
+}
+
+type ReversalRequest struct {
+	// Amount to reverse; 0 reverses whatever is left of the transaction.
+	Amount int64 `json:"amount"`
+}
+
+type TransactionDetailsResponse struct {
+	TransactionResponse
+	// UndoneAmount is the part of the amount moved back by reversals and refunds.
+	UndoneAmount int64                 `json:"undone_amount"`
+	Undos        []TransactionResponse `json:"undos"`
+}
+
+func newTransactionDetailsResponse(d usecase.TransactionDetails) TransactionDetailsResponse {
+	undos := make([]TransactionResponse, 0, len(d.Undos))
+	for _, u := range d.Undos {
+		undos = append(undos, newTransactionResponse(u))
+	}
+	return TransactionDetailsResponse{
+		TransactionResponse: newTransactionResponse(d.Transaction),
+		UndoneAmount:        d.UndoneAmount(),
+		Undos:               undos,
+	}
+}
+
+type AdjustmentRequest struct {
+	UserID     string `json:"user_id"`
+	Direction  string `json:"direction"`
+	Amount     int64  `json:"amount"`
+	Currency   string `json:"currency"`
+	ReasonCode string `json:"reason_code"`
+	Note       string `json:"note"`
+	// EffectiveAt back-dates the adjustment, as an RFC 3339 timestamp or a YYYY-MM-DD date.
+	EffectiveAt string `json:"effective_at,omitempty"`
+}
+
+type AdjustmentResponse struct {
+	ID            string `json:"id"`
+	UserID        string `json:"user_id"`
+	Direction     string `json:"direction"`
+	Amount        int64  `json:"amount"`
+	Currency      string `json:"currency"`
+	ReasonCode    string `json:"reason_code"`
+	Note          string `json:"note,omitempty"`
+	Status        string `json:"status"`
+	RequestedBy   string `json:"requested_by"`
+	DecidedBy     string `json:"decided_by,omitempty"`
+	TransactionID string `json:"transaction_id,omitempty"`
+	EffectiveAt   string `json:"effective_at"`
+	CreatedAt     string `json:"created_at"`
+	DecidedAt     string `json:"decided_at,omitempty"`
+}
+
+func newAdjustmentResponse(a adjustment.Adjustment) AdjustmentResponse {
+	resp := AdjustmentResponse{
+		ID:            a.ID,
+		UserID:        a.UserID,
+		Direction:     string(a.Direction),
+		Amount:        a.Amount,
+		Currency:      a.Currency,
+		ReasonCode:    string(a.Reason),
+		Note:          a.Note,
+		Status:        string(a.Status),
+		RequestedBy:   a.RequestedBy,
+		DecidedBy:     a.DecidedBy,
+		TransactionID: a.TransactionID,
+		EffectiveAt:   a.EffectiveAt.Format("2006-01-02 15:04:05"),
+		CreatedAt:     a.CreatedAt.Format("2006-01-02 15:04:05"),
+	}
+	if a.DecidedAt != nil {
+		resp.DecidedAt = a.DecidedAt.Format("2006-01-02 15:04:05")
+	}
+	return resp
+}
+
+type WalletResponse struct {
+	UserID    string `json:"user_id"`
+	Balance   int64  `json:"balance"`
+	Held      int64  `json:"held"`
+	Currency  string `json:"currency"`
+	CreatedAt string `json:"created_at"`
+	UpdatedAt string `json:"updated_at"`
+}
+
+func newWalletResponse(w wallet.Wallet) WalletResponse {
+	return WalletResponse{
+		UserID:    w.UserID,
+		Balance:   w.Balance,
+		Held:      w.Held,
+		Currency:  w.Currency,
+		CreatedAt: w.CreatedAt.Format("2006-01-02 15:04:05"),
+		UpdatedAt: w.UpdatedAt.Format("2006-01-02 15:04:05"),
+	}
+}
+
+type BalanceChangeResponse struct {
+	UserID string `json:"user_id"`
+	Before *int64 `json:"before,omitempty"`
+	After  *int64 `json:"after,omitempty"`
+}
+
+type AuditEntryResponse struct {
+	Seq           int64                   `json:"seq"`
+	Actor         string                  `json:"actor,omitempty"`
+	Action        string                  `json:"action"`
+	Target        string                  `json:"target,omitempty"`
+	TransactionID string                  `json:"transaction_id,omitempty"`
+	RequestID     string                  `json:"request_id,omitempty"`
+	SourceIP      string                  `json:"source_ip,omitempty"`
+	Balances      []BalanceChangeResponse `json:"balances"`
+	Outcome       string                  `json:"outcome"`
+	Error         string                  `json:"error,omitempty"`
+	CreatedAt     string                  `json:"created_at"`
+	PrevHash      string                  `json:"prev_hash"`
+	Hash          string                  `json:"hash"`
+}
+
+func newAuditEntryResponse(e audit.Entry) AuditEntryResponse {
+	balances := make([]BalanceChangeResponse, 0, len(e.Balances))
+	for _, b := range e.Balances {
+		balances = append(balances, BalanceChangeResponse{UserID: b.UserID, Before: b.Before, After: b.After})
+	}
+	return AuditEntryResponse{
+		Seq:           e.Seq,
+		Actor:         e.Actor,
+		Action:        string(e.Action),
+		Target:        e.Target,
+		TransactionID: e.TransactionID,
+		RequestID:     e.RequestID,
+		SourceIP:      e.SourceIP,
+		Balances:      balances,
+		Outcome:       string(e.Outcome),
+		Error:         e.Error,
+		CreatedAt:     e.CreatedAt.Format("2006-01-02 15:04:05"),
+		PrevHash:      e.PrevHash,
+		Hash:          e.Hash,
+	}
+}
+
+type RiskAssessmentResponse struct {
+	ID             string   `json:"id"`
+	UserID         string   `json:"user_id"`
+	Operation      string   `json:"operation"`
+	CounterpartyID string   `json:"counterparty_id,omitempty"`
+	Amount         int64    `json:"amount"`
+	Currency       string   `json:"currency"`
+	Decision       string   `json:"decision"`
+	RuleIDs        []string `json:"rule_ids"`
+	CreatedAt      string   `json:"created_at"`
+}
+
+func newRiskAssessmentResponse(a risk.Assessment) RiskAssessmentResponse {
+	ruleIDs := a.RuleIDs
+	if ruleIDs == nil {
+		ruleIDs = []string{}
+	}
+	return RiskAssessmentResponse{
+		ID:             a.ID,
+		UserID:         a.UserID,
+		Operation:      string(a.Operation),
+		CounterpartyID: a.CounterpartyID,
+		Amount:         a.Amount,
+		Currency:       a.Currency,
+		Decision:       string(a.Decision),
+		RuleIDs:        ruleIDs,
+		CreatedAt:      a.CreatedAt.Format("2006-01-02 15:04:05"),
+	}
+}
+
+type AuditChainResponse struct {
+	Entries   int64  `json:"entries"`
+	Valid     bool   `json:"valid"`
+	BrokenAt  int64  `json:"broken_at,omitempty"`
+	BrokenWhy string `json:"broken_why,omitempty"`
+}
+
+// RegisterUserRequest registers the profile of UserID, which defaults to the caller.
+type RegisterUserRequest struct {
+	UserID string `json:"user_id"`
+	Name   string `json:"name"`
+	Email  string `json:"email"`
+}
+
+// UpdateUserRequest changes a profile; empty fields are left unchanged.
+type UpdateUserRequest struct {
+	Name  string `json:"name"`
+	Email string `json:"email"`
+}
+
+type UserStatusRequest struct {
+	Status string `json:"status"`
+}
+
+type UserResponse struct {
+	UserID    string `json:"user_id"`
+	Name      string `json:"name"`
+	Email     string `json:"email"`
+	Status    string `json:"status"`
+	CreatedAt string `json:"created_at"`
+	UpdatedAt string `json:"updated_at"`
+}
+
+func newUserResponse(u user.User) UserResponse {
+	return UserResponse{
+		UserID:    u.ID,
+		Name:      u.Name,
+		Email:     u.Email,
+		Status:    string(u.Status),
+		CreatedAt: u.CreatedAt.Format("2006-01-02 15:04:05"),
+		UpdatedAt: u.UpdatedAt.Format("2006-01-02 15:04:05"),
+	}
+}
+
+// KYCSubmissionRequest uploads a document, encoded in base64, asking for Level.
+type KYCSubmissionRequest struct {
+	Level        string `json:"level"`
+	DocumentType string `json:"document_type"`
+	FileName     string `json:"file_name"`
+	ContentType  string `json:"content_type"`
+	Content      []byte `json:"content"`
+}
+
+// KYCReviewRequest approves or rejects a KYC submission; rejecting requires a reason.
+type KYCReviewRequest struct {
+	Reason string `json:"reason"`
+}
+
+// KYCLevelRequest sets a user's KYC level by hand.
+type KYCLevelRequest struct {
+	Level  string `json:"level"`
+	Reason string `json:"reason"`
+}
+
+type KYCCapsResponse struct {
+	MaxBalance    int64 `json:"max_balance,omitempty"`
+	MaxWithdrawal int64 `json:"max_withdrawal,omitempty"`
+	MaxTransfer   int64 `json:"max_transfer,omitempty"`
+}
+
+type KYCLevelChangeResponse struct {
+	ID           string `json:"id"`
+	UserID       string `json:"user_id"`
+	From         string `json:"from"`
+	To           string `json:"to"`
+	Reason       string `json:"reason"`
+	ChangedBy    string `json:"changed_by"`
+	SubmissionID string `json:"submission_id,omitempty"`
+	CreatedAt    string `json:"created_at"`
+}
+
+func newKYCLevelChangeResponse(c kyc.LevelChange) KYCLevelChangeResponse {
+	return KYCLevelChangeResponse{
+		ID:           c.ID,
+		UserID:       c.UserID,
+		From:         string(c.From),
+		To:           string(c.To),
+		Reason:       c.Reason,
+		ChangedBy:    c.ChangedBy,
+		SubmissionID: c.SubmissionID,
+		CreatedAt:    c.CreatedAt.Format("2006-01-02 15:04:05"),
+	}
+}
+
+type KYCSubmissionResponse struct {
+	ID           string `json:"id"`
+	UserID       string `json:"user_id"`
+	Level        string `json:"level"`
+	DocumentType string `json:"document_type"`
+	FileName     string `json:"file_name,omitempty"`
+	ContentType  string `json:"content_type"`
+	Size         int64  `json:"size"`
+	Status       string `json:"status"`
+	Reason       string `json:"reason,omitempty"`
+	ReviewedBy   string `json:"reviewed_by,omitempty"`
+	CreatedAt    string `json:"created_at"`
+	ReviewedAt   string `json:"reviewed_at,omitempty"`
+}
+
+func newKYCSubmissionResponse(s kyc.Submission) KYCSubmissionResponse {
+	resp := KYCSubmissionResponse{
+		ID:           s.ID,
+		UserID:       s.UserID,
+		Level:        string(s.Level),
+		DocumentType: s.DocumentType,
+		FileName:     s.FileName,
+		ContentType:  s.ContentType,
+		Size:         s.Size,
+		Status:       string(s.Status),
+		Reason:       s.Reason,
+		ReviewedBy:   s.ReviewedBy,
+		CreatedAt:    s.CreatedAt.Format("2006-01-02 15:04:05"),
+	}
+	if s.ReviewedAt != nil {
+		resp.ReviewedAt = s.ReviewedAt.Format("2006-01-02 15:04:05")
+	}
+	return resp
+}
+
+// KYCResponse is a user's verification level, the caps it puts on their wallet and how
+// they got there.
+type KYCResponse struct {
+	UserID      string                   `json:"user_id"`
+	Level       string                   `json:"level"`
+	Caps        KYCCapsResponse          `json:"caps"`
+	History     []KYCLevelChangeResponse `json:"history"`
+	Submissions []KYCSubmissionResponse  `json:"submissions"`
+}
+
+func newKYCResponse(userID string, status usecase.KYCStatus) KYCResponse {
+	resp := KYCResponse{
+		UserID: userID,
+		Level:  string(status.Level),
+		Caps: KYCCapsResponse{
+			MaxBalance:    status.Caps.MaxBalance,
+			MaxWithdrawal: status.Caps.MaxWithdrawal,
+			MaxTransfer:   status.Caps.MaxTransfer,
+		},
+		History:     make([]KYCLevelChangeResponse, 0, len(status.History)),
+		Submissions: make([]KYCSubmissionResponse, 0, len(status.Submissions)),
+	}
+	for _, c := range status.History {
+		resp.History = append(resp.History, newKYCLevelChangeResponse(c))
+	}
+	for _, s := range status.Submissions {
+		resp.Submissions = append(resp.Submissions, newKYCSubmissionResponse(s))
+	}
+	return resp
+}
+
+// SanctionsClearRequest marks a compliance case as a false positive.
+type SanctionsClearRequest struct {
+	Reason string `json:"reason"`
+}
+
+type SanctionsCaseResponse struct {
+	ID          string  `json:"id"`
+	SubjectType string  `json:"subject_type"`
+	SubjectID   string  `json:"subject_id"`
+	SubjectName string  `json:"subject_name"`
+	EntryID     string  `json:"entry_id"`
+	EntryName   string  `json:"entry_name"`
+	Score       float64 `json:"score"`
+	Operation   string  `json:"operation"`
+	Status      string  `json:"status"`
+	Reason      string  `json:"reason,omitempty"`
+	ClearedBy   string  `json:"cleared_by,omitempty"`
+	CreatedAt   string  `json:"created_at"`
+	ClearedAt   string  `json:"cleared_at,omitempty"`
+}
+
+func newSanctionsCaseResponse(c sanctions.Case) SanctionsCaseResponse {
+	resp := SanctionsCaseResponse{
+		ID:          c.ID,
+		SubjectType: string(c.SubjectType),
+		SubjectID:   c.SubjectID,
+		SubjectName: c.SubjectName,
+		EntryID:     c.EntryID,
+		EntryName:   c.EntryName,
+		Score:       c.Score,
+		Operation:   c.Operation,
+		Status:      string(c.Status),
+		Reason:      c.Reason,
+		ClearedBy:   c.ClearedBy,
+		CreatedAt:   c.CreatedAt.Format("2006-01-02 15:04:05"),
+	}
+	if c.ClearedAt != nil {
+		resp.ClearedAt = c.ClearedAt.Format("2006-01-02 15:04:05")
+	}
+	return resp
+}
+
+// PeriodCloseResponse describes a closed month.
+type PeriodCloseResponse struct {
+	Month    string `json:"month"`
+	Start    string `json:"start"`
+	End      string `json:"end"`
+	ClosedBy string `json:"closed_by"`
+	ClosedAt string `json:"closed_at"`
+}
+
+func newPeriodCloseResponse(c period.Close) PeriodCloseResponse {
+	return PeriodCloseResponse{
+		Month:    c.Start.Format("2006-01"),
+		Start:    c.Start.Format("2006-01-02 15:04:05"),
+		End:      c.End.Format("2006-01-02 15:04:05"),
+		ClosedBy: c.ClosedBy,
+		ClosedAt: c.ClosedAt.Format("2006-01-02 15:04:05"),
+	}
+}
+
+// ReconciliationLineResponse compares one currency's snapshot totals with its net flow.
+type ReconciliationLineResponse struct {
+	Currency   string `json:"currency"`
+	Opening    int64  `json:"opening"`
+	Closing    int64  `json:"closing"`
+	NetFlow    int64  `json:"net_flow"`
+	Difference int64  `json:"difference"`
+}
+
+type ReconciliationResponse struct {
+	Date       string                       `json:"date"`
+	Balanced   bool                         `json:"balanced"`
+	Currencies []ReconciliationLineResponse `json:"currencies"`
+}
+
+func newReconciliationResponse(r usecase.Reconciliation) ReconciliationResponse {
+	resp := ReconciliationResponse{
+		Date:       r.Day.Format("2006-01-02"),
+		Balanced:   r.Balanced,
+		Currencies: make([]ReconciliationLineResponse, 0, len(r.Lines)),
+	}
+	for _, l := range r.Lines {
+		resp.Currencies = append(resp.Currencies, ReconciliationLineResponse{
+			Currency:   l.Currency,
+			Opening:    l.Opening,
+			Closing:    l.Closing,
+			NetFlow:    l.NetFlow,
+			Difference: l.Difference,
+		})
+	}
+	return resp
+}
+
+// LiabilityRootResponse is the published commitment to the liabilities in one currency.
+type LiabilityRootResponse struct {
+	Currency string `json:"currency"`
+	RootHash string `json:"root_hash"`
+	Total    int64  `json:"total"`
+	Wallets  int    `json:"wallets"`
+	TakenAt  string `json:"taken_at"`
+}
+
+func newLiabilityRootResponse(root reserves.Root) LiabilityRootResponse {
+	return LiabilityRootResponse{
+		Currency: root.Currency,
+		RootHash: root.Hash,
+		Total:    root.Total,
+		Wallets:  root.Wallets,
+		TakenAt:  root.TakenAt.Format("2006-01-02 15:04:05"),
+	}
+}
+
+type ReservesProofStepResponse struct {
+	Hash string `json:"hash"`
+	Sum  int64  `json:"sum"`
+	Side string `json:"side"`
+}
+
+// ReservesProofResponse carries everything needed to recompute the root from the
+// user's own leaf, bottom step first.
+type ReservesProofResponse struct {
+	UserID   string                      `json:"user_id"`
+	Currency string                      `json:"currency"`
+	Balance  int64                       `json:"balance"`
+	Nonce    string                      `json:"nonce"`
+	LeafHash string                      `json:"leaf_hash"`
+	Path     []ReservesProofStepResponse `json:"path"`
+	Root     LiabilityRootResponse       `json:"root"`
+}
+
+func newReservesProofResponse(p reserves.Proof) ReservesProofResponse {
+	resp := ReservesProofResponse{
+		UserID:   p.Leaf.UserID,
+		Currency: p.Root.Currency,
+		Balance:  p.Leaf.Balance,
+		Nonce:    p.Leaf.Nonce,
+		LeafHash: p.Leaf.Hash(p.Root.Currency),
+		Path:     make([]ReservesProofStepResponse, 0, len(p.Path)),
+		Root:     newLiabilityRootResponse(p.Root),
+	}
+	for _, s := range p.Path {
+		side := "right"
+		if s.Left {
+			side = "left"
+		}
+		resp.Path = append(resp.Path, ReservesProofStepResponse{Hash: s.Hash, Sum: s.Sum, Side: side})
+	}
+	return resp
+}
+
+type WebhookSubscriptionRequest struct {
+	UserID     string   `json:"user_id"`
+	URL        string   `json:"url"`
+	EventTypes []string `json:"event_types"`
+}
+
+type WebhookSubscriptionResponse struct {
+	ID         string   `json:"id"`
+	UserID     string   `json:"user_id"`
+	URL        string   `json:"url"`
+	EventTypes []string `json:"event_types"`
+	Secret     string   `json:"secret,omitempty"`
+	CreatedAt  string   `json:"created_at"`
+}
+
+func newWebhookSubscriptionResponse(s webhook.Subscription) WebhookSubscriptionResponse {
+	eventTypes := make([]string, 0, len(s.EventTypes))
+	for _, t := range s.EventTypes {
+		eventTypes = append(eventTypes, string(t))
+	}
+	return WebhookSubscriptionResponse{
+		ID:         s.ID,
+		UserID:     s.UserID,
+		URL:        s.URL,
+		EventTypes: eventTypes,
+		CreatedAt:  s.CreatedAt.Format("2006-01-02 15:04:05"),
+	}
+}
+
+type WebhookDeliveryResponse struct {
+	ID             string `json:"id"`
+	SubscriptionID string `json:"subscription_id"`
+	EventID        string `json:"event_id"`
+	EventType      string `json:"event_type"`
+	Status         string `json:"status"`
+	Attempts       int    `json:"attempts"`
+	NextAttemptAt  string `json:"next_attempt_at,omitempty"`
+	LastError      string `json:"last_error,omitempty"`
+	CreatedAt      string `json:"created_at"`
+	UpdatedAt      string `json:"updated_at"`
+}
+
+func newWebhookDeliveryResponse(d webhook.Delivery) WebhookDeliveryResponse {
+	resp := WebhookDeliveryResponse{
+		ID:             d.ID,
+		SubscriptionID: d.SubscriptionID,
+		EventID:        d.EventID,
+		EventType:      string(d.EventType),
+		Status:         string(d.Status),
+		Attempts:       d.Attempts,
+		LastError:      d.LastError,
+		CreatedAt:      d.CreatedAt.Format("2006-01-02 15:04:05"),
+		UpdatedAt:      d.UpdatedAt.Format("2006-01-02 15:04:05"),
+	}
+	if d.Status == webhook.DeliveryPending {
+		resp.NextAttemptAt = d.NextAttemptAt.Format("2006-01-02 15:04:05")
+	}
+	return resp
+}
+
+type WebhookAttemptResponse struct {
+	AttemptedAt string `json:"attempted_at"`
+	StatusCode  int    `json:"status_code,omitempty"`
+	Error       string `json:"error,omitempty"`
+	DurationMs  int64  `json:"duration_ms"`
+}
+
+func newWebhookAttemptResponse(a webhook.Attempt) WebhookAttemptResponse {
+	return WebhookAttemptResponse{
+		AttemptedAt: a.AttemptedAt.Format("2006-01-02 15:04:05"),
+		StatusCode:  a.StatusCode,
+		Error:       a.Error,
+		DurationMs:  a.Duration.Milliseconds(),
+	}
+}
+
+// EscrowRequest funds an escrow from the payer's wallet. OnExpiry is "release" or
+// "refund" and defaults to "release".
+type EscrowRequest struct {
+	PayerID    string    `json:"payer_id"`
+	PayeeID    string    `json:"payee_id"`
+	Amount     int64     `json:"amount"`
+	Currency   string    `json:"currency"`
+	Conditions string    `json:"conditions,omitempty"`
+	OnExpiry   string    `json:"on_expiry,omitempty"`
+	ExpiresAt  time.Time `json:"expires_at"`
+}
+
+// EscrowDisputeRequest disputes an escrow on behalf of one of its parties.
+type EscrowDisputeRequest struct {
+	UserID string `json:"user_id"`
+	Reason string `json:"reason"`
+}
+
+// EscrowResolveRequest settles a disputed escrow; Outcome is "release" or "refund".
+type EscrowResolveRequest struct {
+	Outcome string `json:"outcome"`
+}
+
+type EscrowResponse struct {
+	ID                      string `json:"id"`
+	PayerID                 string `json:"payer_id"`
+	PayeeID                 string `json:"payee_id"`
+	Amount                  int64  `json:"amount"`
+	Currency                string `json:"currency"`
+	Conditions              string `json:"conditions,omitempty"`
+	OnExpiry                string `json:"on_expiry"`
+	ExpiresAt               string `json:"expires_at"`
+	Status                  string `json:"status"`
+	DisputedBy              string `json:"disputed_by,omitempty"`
+	DisputeReason           string `json:"dispute_reason,omitempty"`
+	FundingTransactionID    string `json:"funding_transaction_id"`
+	SettlementTransactionID string `json:"settlement_transaction_id,omitempty"`
+	CreatedAt               string `json:"created_at"`
+	UpdatedAt               string `json:"updated_at"`
+	SettledAt               string `json:"settled_at,omitempty"`
+}
+
+func newEscrowResponse(e escrow.Escrow) EscrowResponse {
+	resp := EscrowResponse{
+		ID:                      e.ID,
+		PayerID:                 e.PayerID,
+		PayeeID:                 e.PayeeID,
+		Amount:                  e.Amount,
+		Currency:                e.Currency,
+		Conditions:              e.Conditions,
+		OnExpiry:                string(e.OnExpiry),
+		ExpiresAt:               e.ExpiresAt.Format("2006-01-02 15:04:05"),
+		Status:                  string(e.Status),
+		DisputedBy:              e.DisputedBy,
+		DisputeReason:           e.DisputeReason,
+		FundingTransactionID:    e.FundingTransactionID,
+		SettlementTransactionID: e.SettlementTransactionID,
+		CreatedAt:               e.CreatedAt.Format("2006-01-02 15:04:05"),
+		UpdatedAt:               e.UpdatedAt.Format("2006-01-02 15:04:05"),
+	}
+	if e.SettledAt != nil {
+		resp.SettledAt = e.SettledAt.Format("2006-01-02 15:04:05")
+	}
+	return resp
+}
+
+// InterestPayoutResponse is the interest paid to a wallet for one month. Accrued and
+// Carry are in micros, millionths of a minor unit; Amount is what was credited.
+type InterestPayoutResponse struct {
+	UserID        string `json:"user_id"`
+	Currency      string `json:"currency"`
+	Month         string `json:"month"`
+	AccruedMicros int64  `json:"accrued_micros"`
+	Amount        int64  `json:"amount"`
+	CarryMicros   int64  `json:"carry_micros"`
+	TransactionID string `json:"transaction_id,omitempty"`
+	CreatedAt     string `json:"created_at"`
+}
+
+func newInterestPayoutResponse(p interest.Payout) InterestPayoutResponse {
+	return InterestPayoutResponse{
+		UserID:        p.UserID,
+		Currency:      p.Currency,
+		Month:         p.Month.Format("2006-01"),
+		AccruedMicros: p.Accrued,
+		Amount:        p.Amount,
+		CarryMicros:   p.Carry,
+		TransactionID: p.TransactionID,
+		CreatedAt:     p.CreatedAt.Format("2006-01-02 15:04:05"),
+	}
+}
+
+// InterestAccrualRunResponse reports how many wallets a run accrued interest for.
+type InterestAccrualRunResponse struct {
+	Date    string `json:"date"`
+	Accrued int    `json:"accrued"`
+}
+
+// PaymentRequestRequest asks a payer for money. Leaving PayerID empty makes an open
+// request that anyone with its link may pay.
+type PaymentRequestRequest struct {
+	RequesterID string    `json:"requester_id"`
+	PayerID     string    `json:"payer_id,omitempty"`
+	Amount      int64     `json:"amount"`
+	Currency    string    `json:"currency"`
+	Memo        string    `json:"memo,omitempty"`
+	ExpiresAt   time.Time `json:"expires_at"`
+}
+
+// PaymentRequestResponse is a payment request as its parties see it. Link is the
+// shareable path that previews the request without credentials.
+type PaymentRequestResponse struct {
+	ID            string `json:"id"`
+	RequesterID   string `json:"requester_id"`
+	PayerID       string `json:"payer_id,omitempty"`
+	Amount        int64  `json:"amount"`
+	Currency      string `json:"currency"`
+	Memo          string `json:"memo,omitempty"`
+	ExpiresAt     string `json:"expires_at"`
+	Status        string `json:"status"`
+	PaidBy        string `json:"paid_by,omitempty"`
+	TransactionID string `json:"transaction_id,omitempty"`
+	Link          string `json:"link"`
+	CreatedAt     string `json:"created_at"`
+	UpdatedAt     string `json:"updated_at"`
+	ClosedAt      string `json:"closed_at,omitempty"`
+}
+
+func newPaymentRequestResponse(r payment.Request) PaymentRequestResponse {
+	resp := PaymentRequestResponse{
+		ID:            r.ID,
+		RequesterID:   r.RequesterID,
+		PayerID:       r.PayerID,
+		Amount:        r.Amount,
+		Currency:      r.Currency,
+		Memo:          r.Memo,
+		ExpiresAt:     r.ExpiresAt.Format("2006-01-02 15:04:05"),
+		Status:        string(r.Status),
+		PaidBy:        r.PaidBy,
+		TransactionID: r.TransactionID,
+		Link:          "/pay/" + r.Token,
+		CreatedAt:     r.CreatedAt.Format("2006-01-02 15:04:05"),
+		UpdatedAt:     r.UpdatedAt.Format("2006-01-02 15:04:05"),
+	}
+	if r.ClosedAt != nil {
+		resp.ClosedAt = r.ClosedAt.Format("2006-01-02 15:04:05")
+	}
+	return resp
+}
+
+// PaymentRequestPreviewResponse is what a shareable link reveals about its request. It
+// leaves out the token and the payer.
+type PaymentRequestPreviewResponse struct {
+	ID          string `json:"id"`
+	RequesterID string `json:"requester_id"`
+	Amount      int64  `json:"amount"`
+	Currency    string `json:"currency"`
+	Memo        string `json:"memo,omitempty"`
+	Status      string `json:"status"`
+	Open        bool   `json:"open"`
+	ExpiresAt   string `json:"expires_at"`
+}
+
+func newPaymentRequestPreviewResponse(r payment.Request) PaymentRequestPreviewResponse {
+	return PaymentRequestPreviewResponse{
+		ID:          r.ID,
+		RequesterID: r.RequesterID,
+		Amount:      r.Amount,
+		Currency:    r.Currency,
+		Memo:        r.Memo,
+		Status:      string(r.Status),
+		Open:        r.Open(),
+		ExpiresAt:   r.ExpiresAt.Format("2006-01-02 15:04:05"),
+	}
 }
@@ -0,0 +1,212 @@
+package http
+
+import (
+	"encoding/json"
+	"net/http"
+	"strings"
+
+	"exchange/internal/domain/auth"
+	"exchange/internal/domain/escrow"
+	"exchange/internal/usecase"
+)
+
+// EscrowHandler serves escrows under /escrows and their resolution under /admin/escrows.
+// The payer releases an escrow and the payee refunds it; either can dispute it, after which
+// only an admin can settle it.
+type EscrowHandler struct {
+	EscrowUC *usecase.EscrowUseCase
+}
+
+func NewEscrowHandler(escrowUC *usecase.EscrowUseCase) *EscrowHandler {
+	return &EscrowHandler{
+		EscrowUC: escrowUC,
+	}
+}
+
+func (h *EscrowHandler) RegisterRoutes(mux *http.ServeMux) {
+	mux.HandleFunc("/escrows", h.escrowsHandler)
+	mux.HandleFunc("/escrows/", h.escrowHandler)
+	mux.HandleFunc("/admin/escrows/", requireRole(auth.RoleAdmin, h.resolveEscrowHandler))
+}
+
+func (h *EscrowHandler) escrowsHandler(w http.ResponseWriter, r *http.Request) {
+	// GET  /escrows?user_id=&status=funded&limit=10&offset=0
+	// POST /escrows
+	switch r.Method {
+	case http.MethodGet:
+		h.listEscrowsHandler(w, r)
+	case http.MethodPost:
+		h.createEscrowHandler(w, r)
+	default:
+		http.Error(w, "method not allowed", http.StatusMethodNotAllowed)
+	}
+}
+
+func (h *EscrowHandler) createEscrowHandler(w http.ResponseWriter, r *http.Request) {
+	var req EscrowRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		http.Error(w, "invalid request body", http.StatusBadRequest)
+		return
+	}
+
+	payerID, err := actingUserID(r, req.PayerID, auth.PermissionTrade)
+	if err != nil {
+		handleError(w, err)
+		return
+	}
+
+	ctx := r.Context()
+	e, err := h.EscrowUC.CreateEscrow(ctx, usecase.EscrowRequest{
+		PayerID:    payerID,
+		PayeeID:    req.PayeeID,
+		Amount:     req.Amount,
+		Currency:   req.Currency,
+		Conditions: req.Conditions,
+		OnExpiry:   escrow.ExpiryAction(req.OnExpiry),
+		ExpiresAt:  req.ExpiresAt,
+	})
+	if err != nil {
+		handleError(w, err)
+		return
+	}
+	writeJSONStatus(w, http.StatusCreated, newEscrowResponse(e))
+}
+
+func (h *EscrowHandler) listEscrowsHandler(w http.ResponseWriter, r *http.Request) {
+	query := r.URL.Query()
+	limit, offset, err := parsePagination(query)
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+
+	userID, err := actingUserID(r, query.Get("user_id"), auth.PermissionRead)
+	if err != nil {
+		handleError(w, err)
+		return
+	}
+
+	ctx := r.Context()
+	escrows, err := h.EscrowUC.ListEscrows(ctx, escrow.Filter{
+		UserID: userID,
+		Status: escrow.Status(query.Get("status")),
+	}, limit, offset)
+	if err != nil {
+		handleError(w, err)
+		return
+	}
+
+	resp := make([]EscrowResponse, 0, len(escrows))
+	for _, e := range escrows {
+		resp = append(resp, newEscrowResponse(e))
+	}
+	writeJSON(w, resp)
+}
+
+func (h *EscrowHandler) escrowHandler(w http.ResponseWriter, r *http.Request) {
+	// GET  /escrows/{id}
+	// POST /escrows/{id}/release
+	// POST /escrows/{id}/refund
+	// POST /escrows/{id}/dispute
+	segments := strings.Split(strings.TrimPrefix(r.URL.Path, "/escrows/"), "/")
+	if len(segments) > 2 || segments[0] == "" {
+		http.Error(w, "not found", http.StatusNotFound)
+		return
+	}
+	id := segments[0]
+
+	ctx := r.Context()
+	e, err := h.EscrowUC.GetEscrow(ctx, id)
+	if err != nil {
+		handleError(w, err)
+		return
+	}
+
+	if len(segments) == 1 {
+		if r.Method != http.MethodGet {
+			http.Error(w, "method not allowed", http.StatusMethodNotAllowed)
+			return
+		}
+		if err := authorizeParty(r, auth.PermissionRead, e.PayerID, e.PayeeID); err != nil {
+			handleError(w, err)
+			return
+		}
+		writeJSON(w, newEscrowResponse(e))
+		return
+	}
+
+	if r.Method != http.MethodPost {
+		http.Error(w, "method not allowed", http.StatusMethodNotAllowed)
+		return
+	}
+	switch segments[1] {
+	case "release":
+		if err := authorizeParty(r, auth.PermissionTrade, e.PayerID); err != nil {
+			handleError(w, err)
+			return
+		}
+		e, err = h.EscrowUC.ReleaseEscrow(ctx, id)
+	case "refund":
+		if err := authorizeParty(r, auth.PermissionTrade, e.PayeeID); err != nil {
+			handleError(w, err)
+			return
+		}
+		e, err = h.EscrowUC.RefundEscrow(ctx, id)
+	case "dispute":
+		var req EscrowDisputeRequest
+		if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+			http.Error(w, "invalid request body", http.StatusBadRequest)
+			return
+		}
+		userID, err := actingUserID(r, req.UserID, auth.PermissionTrade)
+		if err != nil {
+			handleError(w, err)
+			return
+		}
+		e, err = h.EscrowUC.DisputeEscrow(ctx, id, userID, req.Reason)
+		if err != nil {
+			handleError(w, err)
+			return
+		}
+	default:
+		http.Error(w, "not found", http.StatusNotFound)
+		return
+	}
+	if err != nil {
+		handleError(w, err)
+		return
+	}
+	writeJSON(w, newEscrowResponse(e))
+}
+
+func (h *EscrowHandler) resolveEscrowHandler(w http.ResponseWriter, r *http.Request) {
+	// POST /admin/escrows/{id}/resolve
+	id, ok := strings.CutSuffix(strings.TrimPrefix(r.URL.Path, "/admin/escrows/"), "/resolve")
+	if !ok || id == "" || strings.Contains(id, "/") {
+		http.Error(w, "not found", http.StatusNotFound)
+		return
+	}
+	if r.Method != http.MethodPost {
+		http.Error(w, "method not allowed", http.StatusMethodNotAllowed)
+		return
+	}
+
+	var req EscrowResolveRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		http.Error(w, "invalid request body", http.StatusBadRequest)
+		return
+	}
+	outcome := escrow.ExpiryAction(req.Outcome)
+	if !outcome.Valid() {
+		handleError(w, escrow.ErrInvalidStatus)
+		return
+	}
+
+	ctx := r.Context()
+	e, err := h.EscrowUC.ResolveEscrow(ctx, id, outcome == escrow.ExpiryRelease)
+	if err != nil {
+		handleError(w, err)
+		return
+	}
+	writeJSON(w, newEscrowResponse(e))
+}
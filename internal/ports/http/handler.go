@@ -2,13 +2,32 @@ package http
 
 import (
 	"encoding/json"
+	"errors"
 	"log"
+	"math"
 	"net/http"
+	"net/url"
 	"strconv"
 	"strings"
+	"time"
 
+	"exchange/internal/domain/adjustment"
+	"exchange/internal/domain/audit"
+	"exchange/internal/domain/auth"
+	"exchange/internal/domain/escrow"
+	"exchange/internal/domain/interest"
+	"exchange/internal/domain/kyc"
+	"exchange/internal/domain/limit"
+	"exchange/internal/domain/payment"
+	"exchange/internal/domain/period"
+	"exchange/internal/domain/reserves"
+	"exchange/internal/domain/risk"
+	"exchange/internal/domain/sanctions"
+	"exchange/internal/domain/snapshot"
 	"exchange/internal/domain/transaction"
+	"exchange/internal/domain/user"
 	"exchange/internal/domain/wallet"
+	"exchange/internal/domain/webhook"
 	"exchange/internal/usecase"
 )
 
@@ -25,8 +44,14 @@ func NewHandler(walletUC *usecase.WalletUseCase) *Handler {
 func (h *Handler) RegisterRoutes(mux *http.ServeMux) {
 	mux.HandleFunc("/wallet/deposit", h.depositHandler)
 	mux.HandleFunc("/wallet/withdraw", h.withdrawHandler)
+	mux.HandleFunc("/wallet/withdrawals", h.requestWithdrawalHandler)
 	mux.HandleFunc("/wallet/transfer", h.transferHandler)
+	mux.HandleFunc("/wallet/transfers/batch", h.batchTransferHandler)
+	mux.HandleFunc("/wallet/transfers/split", h.splitTransferHandler)
 	mux.HandleFunc("/wallet/", h.userWalletHandler)
+	mux.HandleFunc("/transactions/", h.transactionHandler)
+	mux.HandleFunc("/reserves", h.reservesHandler)
+	mux.HandleFunc("/openapi.json", h.openAPIHandler)
 }
 
 func (h *Handler) depositHandler(w http.ResponseWriter, r *http.Request) {
@@ -41,14 +66,19 @@ func (h *Handler) depositHandler(w http.ResponseWriter, r *http.Request) {
 		return
 	}
 
+	userID, err := actingUserID(r, req.UserID, auth.PermissionTrade)
+	if err != nil {
+		handleError(w, err)
+		return
+	}
+
 	ctx := r.Context()
-	if err := h.WalletUC.Deposit(ctx, req.UserID, req.Amount, req.Currency); err != nil {
+	if err := h.WalletUC.Deposit(ctx, userID, req.Amount, req.Currency); err != nil {
 		handleError(w, err)
 		return
 	}
 
-	w.WriteHeader(http.StatusOK)
-	w.Write([]byte(`{"status":"success"}`))
+	writeJSON(w, StatusResponse{Status: "success"})
 }
 
 func (h *Handler) withdrawHandler(w http.ResponseWriter, r *http.Request) {
@@ -63,14 +93,51 @@ func (h *Handler) withdrawHandler(w http.ResponseWriter, r *http.Request) {
 		return
 	}
 
+	userID, err := actingUserID(r, req.UserID, auth.PermissionWithdraw)
+	if err != nil {
+		handleError(w, err)
+		return
+	}
+
 	ctx := r.Context()
-	if err := h.WalletUC.Withdraw(ctx, req.UserID, req.Amount, req.Currency); err != nil {
+	tx, err := h.WalletUC.Withdraw(ctx, userID, req.Amount, req.Currency, req.Destination)
+	if err != nil {
+		handleError(w, err)
+		return
+	}
+
+	if tx.Status == transaction.StatusAwaitingApproval {
+		writeJSONStatus(w, http.StatusAccepted, StatusResponse{Status: "pending_approval", TransactionID: tx.ID})
+		return
+	}
+	writeJSON(w, StatusResponse{Status: "success"})
+}
+
+func (h *Handler) requestWithdrawalHandler(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodPost {
+		http.Error(w, "method not allowed", http.StatusMethodNotAllowed)
+		return
+	}
+
+	var req WithdrawRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		http.Error(w, "invalid request body", http.StatusBadRequest)
+		return
+	}
+
+	userID, err := actingUserID(r, req.UserID, auth.PermissionWithdraw)
+	if err != nil {
+		handleError(w, err)
+		return
+	}
+
+	tx, err := h.WalletUC.RequestWithdrawal(r.Context(), userID, req.Amount, req.Currency, req.Destination)
+	if err != nil {
 		handleError(w, err)
 		return
 	}
 
-	w.WriteHeader(http.StatusOK)
-	w.Write([]byte(`{"status":"success"}`))
+	writeJSONStatus(w, http.StatusCreated, newTransactionResponse(tx))
 }
 
 func (h *Handler) transferHandler(w http.ResponseWriter, r *http.Request) {
@@ -85,26 +152,177 @@ func (h *Handler) transferHandler(w http.ResponseWriter, r *http.Request) {
 		return
 	}
 
+	fromUserID, err := actingUserID(r, req.FromUserID, auth.PermissionTrade)
+	if err != nil {
+		handleError(w, err)
+		return
+	}
+
+	ctx := r.Context()
+	if err := h.WalletUC.Transfer(ctx, fromUserID, req.ToUserID, req.Amount, req.Currency); err != nil {
+		handleError(w, err)
+		return
+	}
+
+	writeJSON(w, StatusResponse{Status: "success"})
+}
+
+func (h *Handler) batchTransferHandler(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodPost {
+		http.Error(w, "method not allowed", http.StatusMethodNotAllowed)
+		return
+	}
+
+	var req BatchTransferRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		http.Error(w, "invalid request body", http.StatusBadRequest)
+		return
+	}
+
+	items := make([]usecase.TransferItem, 0, len(req.Transfers))
+	for _, t := range req.Transfers {
+		fromUserID, err := actingUserID(r, t.FromUserID, auth.PermissionTrade)
+		if err != nil {
+			handleError(w, err)
+			return
+		}
+		items = append(items, usecase.TransferItem{
+			FromUserID: fromUserID,
+			ToUserID:   t.ToUserID,
+			Amount:     t.Amount,
+			Currency:   t.Currency,
+		})
+	}
+
+	ctx := r.Context()
+	result, err := h.WalletUC.BatchTransfer(ctx, usecase.BatchMode(req.Mode), items)
+	if err != nil {
+		handleError(w, err)
+		return
+	}
+
+	resp := BatchTransferResponse{
+		BatchID: result.BatchID,
+		Mode:    string(result.Mode),
+		Failed:  result.Failed(),
+		Results: make([]BatchTransferItemResponse, 0, len(result.Results)),
+	}
+	resp.Succeeded = len(result.Results) - resp.Failed
+	for i, item := range result.Results {
+		itemResp := BatchTransferItemResponse{
+			Index:         i,
+			Status:        "succeeded",
+			TransactionID: item.TransactionID,
+		}
+		if item.Err != nil {
+			itemResp.Status = "failed"
+			if item.Err == transaction.ErrBatchRolledBack {
+				itemResp.Status = "rolled_back"
+			}
+			itemResp.ErrorCode = errorCode(item.Err)
+			itemResp.Error = item.Err.Error()
+			if itemResp.ErrorCode == "internal_error" {
+				log.Println("error:", item.Err)
+				itemResp.Error = "internal server error"
+			}
+		}
+		resp.Results = append(resp.Results, itemResp)
+	}
+
+	status := http.StatusOK
+	if result.Mode == usecase.BatchModeAtomic && resp.Failed > 0 {
+		status = http.StatusUnprocessableEntity
+	}
+	writeJSONStatus(w, status, resp)
+}
+
+func (h *Handler) splitTransferHandler(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodPost {
+		http.Error(w, "method not allowed", http.StatusMethodNotAllowed)
+		return
+	}
+
+	var req SplitTransferRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		http.Error(w, "invalid request body", http.StatusBadRequest)
+		return
+	}
+
+	// Paying moves funds out of the acting user's wallet and collecting out of every
+	// share's, so the caller must be able to trade for whoever pays.
+	direction := usecase.SplitDirection(req.Direction)
+	userID := req.UserID
+	if direction != usecase.SplitCollect || userID == "" {
+		var err error
+		if userID, err = actingUserID(r, req.UserID, auth.PermissionTrade); err != nil {
+			handleError(w, err)
+			return
+		}
+	}
+
+	shares := make([]transaction.Share, 0, len(req.Shares))
+	for _, s := range req.Shares {
+		if direction == usecase.SplitCollect {
+			if _, err := actingUserID(r, s.UserID, auth.PermissionTrade); err != nil {
+				handleError(w, err)
+				return
+			}
+		}
+		basisPoints := math.Round(s.Percent * 100)
+		if math.Abs(s.Percent*100-basisPoints) > 1e-6 {
+			handleError(w, transaction.ErrInvalidShare)
+			return
+		}
+		shares = append(shares, transaction.Share{UserID: s.UserID, Amount: s.Amount, BasisPoints: int64(basisPoints)})
+	}
+
 	ctx := r.Context()
-	if err := h.WalletUC.Transfer(ctx, req.FromUserID, req.ToUserID, req.Amount, req.Currency); err != nil {
+	result, err := h.WalletUC.SplitTransfer(ctx, usecase.SplitRequest{
+		Direction: direction,
+		UserID:    userID,
+		Amount:    req.Amount,
+		Currency:  req.Currency,
+		Shares:    shares,
+		Remainder: transaction.RemainderRule(req.Remainder),
+	})
+	if err != nil {
 		handleError(w, err)
 		return
 	}
 
-	w.WriteHeader(http.StatusOK)
-	w.Write([]byte(`{"status":"success"}`))
+	resp := SplitTransferResponse{
+		GroupID:   result.GroupID,
+		Direction: string(result.Direction),
+		Transfers: make([]SplitTransferItemResponse, 0, len(result.Transfers)),
+	}
+	for _, t := range result.Transfers {
+		resp.Transfers = append(resp.Transfers, SplitTransferItemResponse{
+			FromUserID:    t.FromUserID,
+			ToUserID:      t.ToUserID,
+			Amount:        t.Amount,
+			TransactionID: t.TransactionID,
+		})
+	}
+	writeJSON(w, resp)
 }
 
 func (h *Handler) userWalletHandler(w http.ResponseWriter, r *http.Request) {
 	// GET /wallet/{user_id}/balance
 	// GET /wallet/{user_id}/transactions?limit=10&offset=0
+	// GET /wallet/{user_id}/statement?from=&to=&format=csv|jsonl|html
+	// GET /wallet/{user_id}/limits
+	// GET /wallet/{user_id}/reserves-proof
 	segments := strings.Split(strings.TrimPrefix(r.URL.Path, "/wallet/"), "/")
 	if len(segments) == 0 {
 		http.Error(w, "user_id not provided", http.StatusBadRequest)
 		return
 	}
 
-	userID := segments[0]
+	userID, err := actingUserID(r, segments[0], auth.PermissionRead)
+	if err != nil {
+		handleError(w, err)
+		return
+	}
 
 	if len(segments) == 2 && segments[1] == "balance" && r.Method == http.MethodGet {
 		h.getBalanceHandler(w, r, userID)
@@ -116,71 +334,267 @@ func (h *Handler) userWalletHandler(w http.ResponseWriter, r *http.Request) {
 		return
 	}
 
+	if len(segments) == 2 && segments[1] == "statement" && r.Method == http.MethodGet {
+		h.getStatementHandler(w, r, userID)
+		return
+	}
+
+	if len(segments) == 2 && segments[1] == "limits" && r.Method == http.MethodGet {
+		h.getLimitsHandler(w, r, userID)
+		return
+	}
+
+	if len(segments) == 2 && segments[1] == "reserves-proof" && r.Method == http.MethodGet {
+		h.getReservesProofHandler(w, r, userID)
+		return
+	}
+
 	http.Error(w, "not found", http.StatusNotFound)
 }
 
+func (h *Handler) transactionHandler(w http.ResponseWriter, r *http.Request) {
+	// GET  /transactions/{id}
+	// POST /transactions/{id}/refund
+	// POST /transactions/{id}/cancel
+	segments := strings.Split(strings.TrimPrefix(r.URL.Path, "/transactions/"), "/")
+	if segments[0] == "" || len(segments) > 2 || (len(segments) == 2 && segments[1] != "refund" && segments[1] != "cancel") {
+		http.Error(w, "not found", http.StatusNotFound)
+		return
+	}
+
+	ctx := r.Context()
+	details, err := h.WalletUC.GetTransaction(ctx, segments[0])
+	if err != nil {
+		handleError(w, err)
+		return
+	}
+	tx := details.Transaction
+
+	switch {
+	case len(segments) == 1 && r.Method == http.MethodGet:
+		if err := authorizeParty(r, auth.PermissionRead, tx.FromUserID, tx.ToUserID); err != nil {
+			handleError(w, err)
+			return
+		}
+		writeJSON(w, newTransactionDetailsResponse(details))
+	case segments[1] == "refund" && r.Method == http.MethodPost:
+		var req RefundRequest
+		if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+			http.Error(w, "invalid request body", http.StatusBadRequest)
+			return
+		}
+		// Refunds are paid by the recipient of the transfer.
+		if err := authorizeParty(r, auth.PermissionTrade, tx.ToUserID); err != nil {
+			handleError(w, err)
+			return
+		}
+		refund, err := h.WalletUC.Refund(ctx, tx.ID, req.Amount)
+		if err != nil {
+			handleError(w, err)
+			return
+		}
+		writeJSONStatus(w, http.StatusCreated, newTransactionResponse(refund))
+	case segments[1] == "cancel" && r.Method == http.MethodPost:
+		if tx.Type != transaction.TransactionTypeWithdraw {
+			handleError(w, transaction.ErrInvalidStatusTransition)
+			return
+		}
+		if err := authorizeParty(r, auth.PermissionWithdraw, tx.FromUserID); err != nil {
+			handleError(w, err)
+			return
+		}
+		cancelled, err := h.WalletUC.CancelWithdrawal(ctx, tx.ID)
+		if err != nil {
+			handleError(w, err)
+			return
+		}
+		writeJSON(w, newTransactionResponse(cancelled))
+	default:
+		http.Error(w, "method not allowed", http.StatusMethodNotAllowed)
+	}
+}
+
 func (h *Handler) getBalanceHandler(w http.ResponseWriter, r *http.Request, userID string) {
 	ctx := r.Context()
-	balance, err := h.WalletUC.GetBalance(ctx, userID)
+	if asOf := r.URL.Query().Get("as_of"); asOf != "" {
+		h.getHistoricalBalance(w, r, userID, asOf)
+		return
+	}
+
+	wlt, err := h.WalletUC.GetWallet(ctx, userID)
 	if err != nil {
 		handleError(w, err)
 		return
 	}
 
 	resp := BalanceResponse{
-		UserID:  userID,
-		Balance: balance,
+		UserID:    userID,
+		Balance:   wlt.Balance,
+		Held:      wlt.Held,
+		Available: wlt.Available(),
+	}
+	writeJSON(w, resp)
+}
+
+// getHistoricalBalance answers a balance query with as_of. Holds are not tracked over time,
+// so the whole historical balance is reported as available.
+func (h *Handler) getHistoricalBalance(w http.ResponseWriter, r *http.Request, userID, asOf string) {
+	at, err := parseStatementTime(asOf, true)
+	if err != nil {
+		http.Error(w, "invalid as_of value", http.StatusBadRequest)
+		return
+	}
+
+	snap, err := h.WalletUC.BalanceAt(r.Context(), userID, at)
+	if err != nil {
+		handleError(w, err)
+		return
+	}
+
+	writeJSON(w, BalanceResponse{
+		UserID:    userID,
+		Balance:   snap.Balance,
+		Available: snap.Balance,
+		AsOf:      snap.TakenAt.Format("2006-01-02 15:04:05"),
+	})
+}
+
+func (h *Handler) getLimitsHandler(w http.ResponseWriter, r *http.Request, userID string) {
+	allowances, err := h.WalletUC.GetLimits(r.Context(), userID)
+	if err != nil {
+		handleError(w, err)
+		return
+	}
+
+	resp := LimitsResponse{UserID: userID, Limits: make([]LimitResponse, 0, len(allowances))}
+	for _, a := range allowances {
+		resp.Limits = append(resp.Limits, newLimitResponse(a))
+	}
+	writeJSON(w, resp)
+}
+
+func (h *Handler) getReservesProofHandler(w http.ResponseWriter, r *http.Request, userID string) {
+	proof, err := h.WalletUC.ReservesProof(r.Context(), userID)
+	if err != nil {
+		handleError(w, err)
+		return
+	}
+	writeJSON(w, newReservesProofResponse(proof))
+}
+
+// reservesHandler publishes the newest liability root of every currency.
+func (h *Handler) reservesHandler(w http.ResponseWriter, r *http.Request) {
+	// GET /reserves
+	if r.Method != http.MethodGet {
+		http.Error(w, "method not allowed", http.StatusMethodNotAllowed)
+		return
+	}
+
+	roots, err := h.WalletUC.LiabilityRoots(r.Context())
+	if err != nil {
+		handleError(w, err)
+		return
+	}
+
+	resp := make([]LiabilityRootResponse, 0, len(roots))
+	for _, root := range roots {
+		resp = append(resp, newLiabilityRootResponse(root))
 	}
 	writeJSON(w, resp)
 }
 
 func (h *Handler) getTransactionsHandler(w http.ResponseWriter, r *http.Request, userID string) {
 	ctx := r.Context()
-	limitStr := r.URL.Query().Get("limit")
-	offsetStr := r.URL.Query().Get("offset")
+	limit, offset, err := parsePagination(r.URL.Query())
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+
+	txs, err := h.WalletUC.GetTransactionHistory(ctx, userID, limit, offset)
+	if err != nil {
+		handleError(w, err)
+		return
+	}
+
+	resp := make([]TransactionResponse, 0, len(txs))
+	for _, tx := range txs {
+		resp = append(resp, newTransactionResponse(tx))
+	}
+
+	writeJSON(w, resp)
+}
+
+func (h *Handler) getStatementHandler(w http.ResponseWriter, r *http.Request, userID string) {
+	ctx := r.Context()
+	query := r.URL.Query()
 
-	limit := 10
-	offset := 0
+	to := time.Now()
+	from := time.Date(to.Year(), to.Month(), 1, 0, 0, 0, 0, to.Location())
 	var err error
-	if limitStr != "" {
-		limit, err = strconv.Atoi(limitStr)
+	if fromStr := query.Get("from"); fromStr != "" {
+		from, err = parseStatementTime(fromStr, false)
 		if err != nil {
-			http.Error(w, "invalid limit value", http.StatusBadRequest)
+			http.Error(w, "invalid from value", http.StatusBadRequest)
 			return
 		}
 	}
-	if offsetStr != "" {
-		offset, err = strconv.Atoi(offsetStr)
+	if toStr := query.Get("to"); toStr != "" {
+		to, err = parseStatementTime(toStr, true)
 		if err != nil {
-			http.Error(w, "invalid offset value", http.StatusBadRequest)
+			http.Error(w, "invalid to value", http.StatusBadRequest)
 			return
 		}
 	}
 
-	txs, err := h.WalletUC.GetTransactionHistory(ctx, userID, limit, offset)
+	format := query.Get("format")
+	if format == "" {
+		format = StatementFormatCSV
+	}
+	sw, err := newStatementWriter(w, format, userID, from, to)
 	if err != nil {
-		handleError(w, err)
+		http.Error(w, "invalid format value", http.StatusBadRequest)
 		return
 	}
 
-	resp := make([]TransactionResponse, 0, len(txs))
-	for _, tx := range txs {
-		resp = append(resp, TransactionResponse{
-			ID:         tx.ID,
-			FromUserID: tx.FromUserID,
-			ToUserID:   tx.ToUserID,
-			Amount:     tx.Amount,
-			Currency:   tx.Currency,
-			Type:       string(tx.Type),
-			CreatedAt:  tx.CreatedAt.Format("2006-01-02 15:04:05"),
-		})
+	// A long period can take longer to stream than the server's write timeout allows.
+	_ = http.NewResponseController(w).SetWriteDeadline(time.Now().Add(statementWriteTimeout))
+	if err := h.WalletUC.ExportStatement(ctx, userID, from, to, sw); err != nil {
+		if !sw.Started() {
+			handleError(w, err)
+			return
+		}
+		// Headers are already sent; the truncated body is all the client will see.
+		log.Println("statement export aborted:", err)
 	}
+}
 
-	writeJSON(w, resp)
+// parsePagination reads the limit and offset query parameters, defaulting to the first
+// ten results.
+func parsePagination(query url.Values) (limit, offset int, err error) {
+	limit, offset = 10, 0
+	if limitStr := query.Get("limit"); limitStr != "" {
+		limit, err = strconv.Atoi(limitStr)
+		if err != nil || limit < 0 {
+			return 0, 0, errors.New("invalid limit value")
+		}
+	}
+	if offsetStr := query.Get("offset"); offsetStr != "" {
+		offset, err = strconv.Atoi(offsetStr)
+		if err != nil || offset < 0 {
+			return 0, 0, errors.New("invalid offset value")
+		}
+	}
+	return limit, offset, nil
 }
 
 func handleError(w http.ResponseWriter, err error) {
 	log.Println("error:", err)
+	var exceeded *limit.ExceededError
+	if errors.As(err, &exceeded) {
+		writeJSONStatus(w, http.StatusUnprocessableEntity, LimitExceededResponse{Error: exceeded.Error(), Limit: newLimitResponse(exceeded.Allowance)})
+		return
+	}
 	switch err {
 	case wallet.ErrWalletNotFound:
 		http.Error(w, "wallet not found", http.StatusNotFound)
@@ -198,13 +612,162 @@ func handleError(w http.ResponseWriter, err error) {
 		http.Error(w, "invalid user id", http.StatusBadRequest)
 	case transaction.ErrInvalidTransactionID:
 		http.Error(w, "invalid transaction id", http.StatusBadRequest)
+	case transaction.ErrInvalidTimeRange:
+		http.Error(w, "invalid time range", http.StatusBadRequest)
+	case transaction.ErrEmptyBatch:
+		http.Error(w, "batch contains no transfers", http.StatusBadRequest)
+	case transaction.ErrBatchTooLarge:
+		http.Error(w, "batch contains too many transfers", http.StatusBadRequest)
+	case transaction.ErrInvalidBatchMode:
+		http.Error(w, "invalid batch mode", http.StatusBadRequest)
+	case transaction.ErrEmptySplit, transaction.ErrInvalidShare, transaction.ErrSharesMismatch, transaction.ErrShareTooSmall,
+		transaction.ErrInvalidRemainderRule, transaction.ErrInvalidSplitDirection:
+		http.Error(w, err.Error(), http.StatusBadRequest)
+	case transaction.ErrInvalidStatus:
+		http.Error(w, "invalid transaction status", http.StatusBadRequest)
+	case transaction.ErrInvalidStatusTransition:
+		http.Error(w, "transaction cannot move to this status", http.StatusConflict)
+	case transaction.ErrNotAwaitingApproval:
+		http.Error(w, "transaction is not awaiting approval", http.StatusConflict)
+	case transaction.ErrReasonRequired:
+		http.Error(w, "reason is required", http.StatusBadRequest)
+	case transaction.ErrNotUndoable:
+		http.Error(w, "transaction cannot be undone this way", http.StatusConflict)
+	case transaction.ErrUndoExceedsOriginal:
+		http.Error(w, "amount exceeds what is left of the original transaction", http.StatusBadRequest)
+	case transaction.ErrInvalidAmountRange:
+		http.Error(w, "invalid amount range", http.StatusBadRequest)
+	case wallet.ErrInvalidFilter:
+		http.Error(w, "invalid wallet search filter", http.StatusBadRequest)
+	case adjustment.ErrInvalidUserID, adjustment.ErrInvalidDirection, adjustment.ErrInvalidAmount,
+		adjustment.ErrInvalidReasonCode, adjustment.ErrInvalidStatus, adjustment.ErrInvalidEffectiveAt:
+		http.Error(w, err.Error(), http.StatusBadRequest)
+	case adjustment.ErrAdjustmentNotFound:
+		http.Error(w, "adjustment not found", http.StatusNotFound)
+	case adjustment.ErrAdjustmentNotPending:
+		http.Error(w, "adjustment is not pending", http.StatusConflict)
+	case adjustment.ErrSelfApproval:
+		http.Error(w, "adjustment must be decided by another admin", http.StatusForbidden)
+	case audit.ErrInvalidAction:
+		http.Error(w, "invalid audit action", http.StatusBadRequest)
+	case audit.ErrInvalidTimeRange:
+		http.Error(w, "invalid time range", http.StatusBadRequest)
+	case webhook.ErrInvalidUserID, webhook.ErrInvalidURL, webhook.ErrInvalidEventTypes, webhook.ErrInvalidStatus:
+		http.Error(w, err.Error(), http.StatusBadRequest)
+	case webhook.ErrSubscriptionNotFound:
+		http.Error(w, "webhook subscription not found", http.StatusNotFound)
+	case webhook.ErrDeliveryNotFound:
+		http.Error(w, "webhook delivery not found", http.StatusNotFound)
+	case webhook.ErrDeliveryPending:
+		http.Error(w, "webhook delivery is already pending", http.StatusConflict)
+	case user.ErrInvalidUserID, user.ErrInvalidName, user.ErrInvalidEmail, user.ErrInvalidStatus:
+		http.Error(w, err.Error(), http.StatusBadRequest)
+	case user.ErrUserNotFound:
+		http.Error(w, "user not found", http.StatusNotFound)
+	case user.ErrUserExists, user.ErrEmailTaken, user.ErrUserDeactivated:
+		http.Error(w, err.Error(), http.StatusConflict)
+	case kyc.ErrInvalidLevel, kyc.ErrInvalidStatus, kyc.ErrInvalidDocument, kyc.ErrReasonRequired:
+		http.Error(w, err.Error(), http.StatusBadRequest)
+	case kyc.ErrDocumentTooLarge:
+		http.Error(w, err.Error(), http.StatusRequestEntityTooLarge)
+	case kyc.ErrSubmissionNotFound, kyc.ErrDocumentNotFound:
+		http.Error(w, err.Error(), http.StatusNotFound)
+	case kyc.ErrSubmissionNotPending:
+		http.Error(w, err.Error(), http.StatusConflict)
+	case kyc.ErrBalanceCapExceeded, kyc.ErrWithdrawalCapExceeded, kyc.ErrTransferCapExceeded:
+		http.Error(w, err.Error(), http.StatusForbidden)
+	case risk.ErrBlocked:
+		http.Error(w, "blocked by risk rules", http.StatusForbidden)
+	case sanctions.ErrBlocked:
+		http.Error(w, "blocked by sanctions screening", http.StatusForbidden)
+	case sanctions.ErrInvalidStatus, sanctions.ErrReasonRequired:
+		http.Error(w, err.Error(), http.StatusBadRequest)
+	case sanctions.ErrCaseNotFound:
+		http.Error(w, err.Error(), http.StatusNotFound)
+	case sanctions.ErrCaseNotOpen:
+		http.Error(w, err.Error(), http.StatusConflict)
+	case period.ErrInvalidAdmin, period.ErrPeriodNotEnded:
+		http.Error(w, err.Error(), http.StatusBadRequest)
+	case period.ErrPeriodClosed, period.ErrAlreadyClosed:
+		http.Error(w, err.Error(), http.StatusConflict)
+	case snapshot.ErrSnapshotNotFound, reserves.ErrRootNotFound, reserves.ErrLeafNotFound:
+		http.Error(w, err.Error(), http.StatusNotFound)
+	case escrow.ErrInvalidParty, escrow.ErrSameParty, escrow.ErrInvalidAmount, escrow.ErrInvalidCurrency, escrow.ErrCurrencyMismatch,
+		escrow.ErrInvalidExpiry, escrow.ErrInvalidExpiryAction, escrow.ErrInvalidStatus, escrow.ErrInvalidReason:
+		http.Error(w, err.Error(), http.StatusBadRequest)
+	case escrow.ErrNotParty:
+		http.Error(w, err.Error(), http.StatusForbidden)
+	case escrow.ErrEscrowNotFound:
+		http.Error(w, err.Error(), http.StatusNotFound)
+	case escrow.ErrEscrowSettled, escrow.ErrEscrowDisputed:
+		http.Error(w, err.Error(), http.StatusConflict)
+	case interest.ErrInvalidDay, interest.ErrInvalidMonth:
+		http.Error(w, err.Error(), http.StatusBadRequest)
+	case payment.ErrInvalidParty, payment.ErrSameParty, payment.ErrInvalidAmount, payment.ErrInvalidCurrency, payment.ErrCurrencyMismatch,
+		payment.ErrInvalidMemo, payment.ErrInvalidExpiry, payment.ErrInvalidStatus:
+		http.Error(w, err.Error(), http.StatusBadRequest)
+	case payment.ErrNotPayer, payment.ErrNotRequester:
+		http.Error(w, err.Error(), http.StatusForbidden)
+	case payment.ErrRequestNotFound:
+		http.Error(w, err.Error(), http.StatusNotFound)
+	case payment.ErrRequestClosed, payment.ErrRequestExpired:
+		http.Error(w, err.Error(), http.StatusConflict)
+	case risk.ErrInvalidDecision:
+		http.Error(w, "invalid risk decision", http.StatusBadRequest)
+	case auth.ErrUnauthenticated, auth.ErrInvalidAPIKey, auth.ErrInvalidSignature, auth.ErrSignatureExpired, auth.ErrNonceReused, auth.ErrInvalidToken:
+		http.Error(w, "unauthorized", http.StatusUnauthorized)
+	case auth.ErrForbidden:
+		http.Error(w, "forbidden", http.StatusForbidden)
+	case auth.ErrInvalidPermission:
+		http.Error(w, "invalid permission", http.StatusBadRequest)
 	default:
 		http.Error(w, "internal server error", http.StatusInternalServerError)
 	}
 }
 
+// errorCode returns a stable machine-readable code for the domain errors reported
+// inside a response body rather than as the response status.
+func errorCode(err error) string {
+	if errors.Is(err, limit.ErrLimitExceeded) {
+		return "limit_exceeded"
+	}
+	switch err {
+	case wallet.ErrWalletNotFound:
+		return "wallet_not_found"
+	case wallet.ErrInsufficientFunds:
+		return "insufficient_funds"
+	case wallet.ErrInvalidAmount:
+		return "invalid_amount"
+	case transaction.ErrInvalidTransactionAmount:
+		return "invalid_transaction_amount"
+	case transaction.ErrInvalidTransactionType:
+		return "invalid_transaction_type"
+	case transaction.ErrInvalidUserID:
+		return "invalid_user_id"
+	case transaction.ErrBatchRolledBack:
+		return "batch_rolled_back"
+	case risk.ErrBlocked:
+		return "risk_blocked"
+	case sanctions.ErrBlocked:
+		return "sanctions_blocked"
+	case kyc.ErrBalanceCapExceeded, kyc.ErrWithdrawalCapExceeded, kyc.ErrTransferCapExceeded:
+		return "kyc_cap_exceeded"
+	case user.ErrUserNotFound:
+		return "user_not_found"
+	case user.ErrUserDeactivated:
+		return "user_deactivated"
+	default:
+		return "internal_error"
+	}
+}
+
 func writeJSON(w http.ResponseWriter, data interface{}) {
+	writeJSONStatus(w, http.StatusOK, data)
+}
+
+func writeJSONStatus(w http.ResponseWriter, status int, data interface{}) {
 	w.Header().Set("Content-Type", "application/json; charset=utf-8")
+	w.WriteHeader(status)
 	if err := json.NewEncoder(w).Encode(data); err != nil {
 		http.Error(w, "failed to encode response", http.StatusInternalServerError)
 	}
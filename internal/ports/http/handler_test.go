@@ -0,0 +1,1995 @@
+package http
+
+import (
+	"bytes"
+	"context"
+	"crypto"
+	"crypto/ecdsa"
+	"crypto/elliptic"
+	"crypto/rand"
+	"encoding/json"
+	"fmt"
+	"io"
+	"maps"
+	"net/http"
+	"net/http/httptest"
+	"slices"
+	"sort"
+	"strings"
+	"sync"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"exchange/internal/adapters/oidc"
+	"exchange/internal/domain/adjustment"
+	"exchange/internal/domain/audit"
+	"exchange/internal/domain/auth"
+	"exchange/internal/domain/escrow"
+	"exchange/internal/domain/event"
+	"exchange/internal/domain/interest"
+	"exchange/internal/domain/kyc"
+	"exchange/internal/domain/limit"
+	"exchange/internal/domain/payment"
+	"exchange/internal/domain/period"
+	"exchange/internal/domain/reserves"
+	"exchange/internal/domain/risk"
+	"exchange/internal/domain/sanctions"
+	"exchange/internal/domain/snapshot"
+	"exchange/internal/domain/transaction"
+	"exchange/internal/domain/user"
+	"exchange/internal/domain/wallet"
+	"exchange/internal/domain/webhook"
+	"exchange/internal/usecase"
+
+	"github.com/getkin/kin-openapi/openapi3"
+	"github.com/getkin/kin-openapi/openapi3filter"
+	"github.com/getkin/kin-openapi/routers"
+	"github.com/getkin/kin-openapi/routers/legacy"
+	"github.com/golang-jwt/jwt/v5"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func init() {
+	openapi3filter.RegisterBodyDecoder("text/html", openapi3filter.PlainBodyDecoder)
+	openapi3filter.RegisterBodyDecoder("application/x-ndjson", openapi3filter.PlainBodyDecoder)
+}
+
+type memoryWalletRepository struct {
+	mu      sync.Mutex
+	wallets map[string]wallet.Wallet
+}
+
+func (r *memoryWalletRepository) CreateWallet(ctx context.Context, w wallet.Wallet) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	r.wallets[w.UserID] = w
+	return nil
+}
+
+func (r *memoryWalletRepository) GetWalletByUserID(ctx context.Context, userID string) (wallet.Wallet, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	w, ok := r.wallets[userID]
+	if !ok {
+		return wallet.Wallet{}, wallet.ErrWalletNotFound
+	}
+	return w, nil
+}
+
+func (r *memoryWalletRepository) LockWalletByUserID(ctx context.Context, userID string) (wallet.Wallet, error) {
+	return r.GetWalletByUserID(ctx, userID)
+}
+
+func (r *memoryWalletRepository) UpdateWallet(ctx context.Context, w wallet.Wallet) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	if _, ok := r.wallets[w.UserID]; !ok {
+		return wallet.ErrWalletNotFound
+	}
+	r.wallets[w.UserID] = w
+	return nil
+}
+
+func (r *memoryWalletRepository) SearchWallets(ctx context.Context, filter wallet.SearchFilter, limit, offset int) ([]wallet.Wallet, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	var results []wallet.Wallet
+	for _, w := range r.wallets {
+		if strings.HasPrefix(w.UserID, filter.UserIDPrefix) &&
+			(filter.Currency == "" || w.Currency == filter.Currency) &&
+			(filter.MinBalance == nil || w.Balance >= *filter.MinBalance) &&
+			(filter.MaxBalance == nil || w.Balance <= *filter.MaxBalance) {
+			results = append(results, w)
+		}
+	}
+	sort.Slice(results, func(i, j int) bool { return results[i].UserID < results[j].UserID })
+	return page(results, limit, offset), nil
+}
+
+// page returns the slice of items selected by limit and offset.
+func page[T any](items []T, limit, offset int) []T {
+	if offset >= len(items) {
+		return nil
+	}
+	items = items[offset:]
+	if limit < len(items) {
+		items = items[:limit]
+	}
+	return items
+}
+
+type memoryTransactionRepository struct {
+	mu  sync.Mutex
+	txs []transaction.Transaction
+}
+
+func (r *memoryTransactionRepository) CreateTransaction(ctx context.Context, tx transaction.Transaction) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	r.txs = append(r.txs, tx)
+	return nil
+}
+
+func (r *memoryTransactionRepository) GetTransactionByID(ctx context.Context, id string) (transaction.Transaction, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	for _, tx := range r.txs {
+		if tx.ID == id {
+			return tx, nil
+		}
+	}
+	return transaction.Transaction{}, transaction.ErrTransactionNotFound
+}
+
+func (r *memoryTransactionRepository) LockTransactionByID(ctx context.Context, id string) (transaction.Transaction, error) {
+	return r.GetTransactionByID(ctx, id)
+}
+
+func (r *memoryTransactionRepository) UpdateTransactionStatus(ctx context.Context, tx transaction.Transaction) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	for i := range r.txs {
+		if r.txs[i].ID == tx.ID {
+			r.txs[i] = tx
+			return nil
+		}
+	}
+	return transaction.ErrTransactionNotFound
+}
+
+func (r *memoryTransactionRepository) ListTransactionsByOriginalID(ctx context.Context, originalID string) ([]transaction.Transaction, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	var results []transaction.Transaction
+	for _, tx := range r.txs {
+		if tx.OriginalID == originalID {
+			results = append(results, tx)
+		}
+	}
+	return results, nil
+}
+
+func (r *memoryTransactionRepository) userTransactions(userID string) []transaction.Transaction {
+	var results []transaction.Transaction
+	for _, tx := range r.txs {
+		if tx.FromUserID == userID || tx.ToUserID == userID {
+			results = append(results, tx)
+		}
+	}
+	sort.SliceStable(results, func(i, j int) bool { return results[i].CreatedAt.Before(results[j].CreatedAt) })
+	return results
+}
+
+func (r *memoryTransactionRepository) ListTransactionsByUserID(ctx context.Context, userID string, limit, offset int) ([]transaction.Transaction, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	txs := r.userTransactions(userID)
+	var results []transaction.Transaction
+	for i := len(txs) - 1 - offset; i >= 0 && len(results) < limit; i-- {
+		results = append(results, txs[i])
+	}
+	return results, nil
+}
+
+func (r *memoryTransactionRepository) ListTransactionsByUserIDBefore(ctx context.Context, userID string, before transaction.Cursor, limit int) ([]transaction.Transaction, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	txs := r.userTransactions(userID)
+	var results []transaction.Transaction
+	for i := len(txs) - 1; i >= 0 && len(results) < limit; i-- {
+		tx := txs[i]
+		if tx.CreatedAt.Before(before.CreatedAt) || (tx.CreatedAt.Equal(before.CreatedAt) && tx.ID < before.ID) {
+			results = append(results, tx)
+		}
+	}
+	return results, nil
+}
+
+func (r *memoryTransactionRepository) StreamTransactionsByUserID(ctx context.Context, userID string, from, to time.Time, fn func(transaction.Transaction) error) error {
+	r.mu.Lock()
+	txs := r.userTransactions(userID)
+	r.mu.Unlock()
+	sort.SliceStable(txs, func(i, j int) bool { return txs[i].BookedAt().Before(txs[j].BookedAt()) })
+	for _, tx := range txs {
+		if tx.Status != transaction.StatusCompleted || tx.BookedAt().Before(from) || !tx.BookedAt().Before(to) {
+			continue
+		}
+		if err := fn(tx); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func (r *memoryTransactionRepository) SumNetAmountSince(ctx context.Context, userID string, since time.Time) (int64, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	var net int64
+	for _, tx := range r.userTransactions(userID) {
+		if tx.Status == transaction.StatusCompleted && !tx.BookedAt().Before(since) {
+			net += tx.SignedAmountFor(userID)
+		}
+	}
+	return net, nil
+}
+
+func (r *memoryTransactionRepository) SumNetAmountBetween(ctx context.Context, userID string, from, to time.Time) (int64, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	var net int64
+	for _, tx := range r.userTransactions(userID) {
+		if tx.Status == transaction.StatusCompleted && !tx.BookedAt().Before(from) && tx.BookedAt().Before(to) {
+			net += tx.SignedAmountFor(userID)
+		}
+	}
+	return net, nil
+}
+
+func (r *memoryTransactionRepository) SumNetFlowBetween(ctx context.Context, from, to time.Time) (map[string]int64, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	flows := make(map[string]int64)
+	for _, tx := range r.txs {
+		if tx.Status != transaction.StatusCompleted || tx.BookedAt().Before(from) || !tx.BookedAt().Before(to) {
+			continue
+		}
+		if tx.ToUserID != "" {
+			flows[tx.Currency] += tx.Amount
+		}
+		if tx.FromUserID != "" {
+			flows[tx.Currency] -= tx.Amount
+		}
+	}
+	return flows, nil
+}
+
+func (r *memoryTransactionRepository) SumOutgoingSince(ctx context.Context, userID string, tType transaction.TransactionType, currency string, since time.Time) (int64, int, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	var amount int64
+	var count int
+	for _, tx := range r.txs {
+		if tx.FromUserID != userID || tx.Type != tType || !strings.EqualFold(tx.Currency, currency) || tx.CreatedAt.Before(since) {
+			continue
+		}
+		if tx.Status != transaction.StatusFailed && tx.Status != transaction.StatusCancelled {
+			amount += tx.Amount
+			count++
+		}
+	}
+	return amount, count, nil
+}
+
+func (r *memoryTransactionRepository) SearchTransactions(ctx context.Context, filter transaction.SearchFilter, limit, offset int) ([]transaction.Transaction, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	var results []transaction.Transaction
+	for i := len(r.txs) - 1; i >= 0; i-- {
+		tx := r.txs[i]
+		if (filter.UserID == "" || tx.FromUserID == filter.UserID || tx.ToUserID == filter.UserID) &&
+			(filter.Type == "" || tx.Type == filter.Type) &&
+			(filter.BatchID == "" || tx.BatchID == filter.BatchID) &&
+			(filter.Status == "" || tx.Status == filter.Status) &&
+			(filter.From.IsZero() || !tx.CreatedAt.Before(filter.From)) &&
+			(filter.To.IsZero() || tx.CreatedAt.Before(filter.To)) &&
+			tx.Amount >= filter.MinAmount &&
+			(filter.MaxAmount == 0 || tx.Amount <= filter.MaxAmount) {
+			results = append(results, tx)
+		}
+	}
+	return page(results, limit, offset), nil
+}
+
+type memoryLimitRepository struct {
+	tiers     map[string]limit.Tier
+	overrides map[string][]limit.Limit
+}
+
+func (r *memoryLimitRepository) GetTier(ctx context.Context, userID string) (limit.Tier, error) {
+	return r.tiers[userID], nil
+}
+
+func (r *memoryLimitRepository) ListOverrides(ctx context.Context, userID string) ([]limit.Limit, error) {
+	return r.overrides[userID], nil
+}
+
+// memoryRiskRepository reads the facts of assessments from the wallet and transaction
+// repositories.
+type memoryRiskRepository struct {
+	mu           sync.Mutex
+	wallets      *memoryWalletRepository
+	transactions *memoryTransactionRepository
+	assessments  []risk.Assessment
+}
+
+func (r *memoryRiskRepository) GetAvailableBalance(ctx context.Context, userID string) (int64, error) {
+	r.wallets.mu.Lock()
+	defer r.wallets.mu.Unlock()
+	w := r.wallets.wallets[userID]
+	return w.Balance - w.Held, nil
+}
+
+func (r *memoryRiskRepository) ListTransactionsSince(ctx context.Context, userID string, since time.Time) ([]transaction.Transaction, error) {
+	r.transactions.mu.Lock()
+	defer r.transactions.mu.Unlock()
+	var results []transaction.Transaction
+	for _, tx := range r.transactions.txs {
+		if (tx.FromUserID == userID || tx.ToUserID == userID) && !tx.BookedAt().Before(since) &&
+			tx.Status != transaction.StatusFailed && tx.Status != transaction.StatusCancelled {
+			results = append(results, tx)
+		}
+	}
+	return results, nil
+}
+
+func (r *memoryRiskRepository) CreateAssessment(ctx context.Context, a risk.Assessment) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	r.assessments = append(r.assessments, a)
+	return nil
+}
+
+func (r *memoryRiskRepository) ListAssessments(ctx context.Context, filter risk.Filter, limit, offset int) ([]risk.Assessment, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	var results []risk.Assessment
+	for i := len(r.assessments) - 1; i >= 0; i-- {
+		a := r.assessments[i]
+		if (filter.UserID == "" || a.UserID == filter.UserID) && (filter.Decision == "" || a.Decision == filter.Decision) {
+			results = append(results, a)
+		}
+	}
+	return page(results, limit, offset), nil
+}
+
+type memoryAdjustmentRepository struct {
+	mu          sync.Mutex
+	adjustments []adjustment.Adjustment
+}
+
+func (r *memoryAdjustmentRepository) CreateAdjustment(ctx context.Context, a adjustment.Adjustment) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	r.adjustments = append(r.adjustments, a)
+	return nil
+}
+
+func (r *memoryAdjustmentRepository) GetAdjustmentByID(ctx context.Context, id string) (adjustment.Adjustment, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	for _, a := range r.adjustments {
+		if a.ID == id {
+			return a, nil
+		}
+	}
+	return adjustment.Adjustment{}, adjustment.ErrAdjustmentNotFound
+}
+
+func (r *memoryAdjustmentRepository) ListAdjustments(ctx context.Context, status adjustment.Status, limit, offset int) ([]adjustment.Adjustment, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	var results []adjustment.Adjustment
+	for i := len(r.adjustments) - 1; i >= 0; i-- {
+		if status == "" || r.adjustments[i].Status == status {
+			results = append(results, r.adjustments[i])
+		}
+	}
+	return page(results, limit, offset), nil
+}
+
+func (r *memoryAdjustmentRepository) DecideAdjustment(ctx context.Context, a adjustment.Adjustment) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	for i := range r.adjustments {
+		if r.adjustments[i].ID == a.ID {
+			if r.adjustments[i].Status != adjustment.StatusPending {
+				return adjustment.ErrAdjustmentNotPending
+			}
+			r.adjustments[i] = a
+			return nil
+		}
+	}
+	return adjustment.ErrAdjustmentNotPending
+}
+
+type memoryAuditRepository struct {
+	mu      sync.Mutex
+	entries []audit.Entry
+}
+
+func (r *memoryAuditRepository) AppendEntry(ctx context.Context, e audit.Entry) (audit.Entry, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	var prevSeq int64
+	var prevHash string
+	if n := len(r.entries); n > 0 {
+		prevSeq, prevHash = r.entries[n-1].Seq, r.entries[n-1].Hash
+	}
+	e = e.Seal(prevSeq, prevHash)
+	r.entries = append(r.entries, e)
+	return e, nil
+}
+
+func (r *memoryAuditRepository) ListEntries(ctx context.Context, filter audit.Filter, limit, offset int) ([]audit.Entry, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	var results []audit.Entry
+	for i := len(r.entries) - 1; i >= 0; i-- {
+		e := r.entries[i]
+		touches := filter.UserID == ""
+		for _, b := range e.Balances {
+			touches = touches || b.UserID == filter.UserID
+		}
+		if touches && (filter.Action == "" || e.Action == filter.Action) && (filter.RequestID == "" || e.RequestID == filter.RequestID) {
+			results = append(results, e)
+		}
+	}
+	return page(results, limit, offset), nil
+}
+
+func (r *memoryAuditRepository) StreamEntries(ctx context.Context, fn func(audit.Entry) error) error {
+	r.mu.Lock()
+	entries := append([]audit.Entry(nil), r.entries...)
+	r.mu.Unlock()
+	for _, e := range entries {
+		if err := fn(e); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+type memoryOutboxRepository struct {
+	mu     sync.Mutex
+	events []event.Event
+}
+
+func (r *memoryOutboxRepository) AppendEvent(ctx context.Context, e event.Event) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	e.Seq = int64(len(r.events) + 1)
+	r.events = append(r.events, e)
+	return nil
+}
+
+func (r *memoryOutboxRepository) ListPendingEvents(ctx context.Context, limit int) ([]event.Event, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	var results []event.Event
+	for _, e := range r.events {
+		if e.PublishedAt == nil {
+			results = append(results, e)
+		}
+	}
+	return page(results, limit, 0), nil
+}
+
+func (r *memoryOutboxRepository) MarkEventPublished(ctx context.Context, id string, publishedAt time.Time) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	for i := range r.events {
+		if r.events[i].ID == id {
+			r.events[i].PublishedAt = &publishedAt
+		}
+	}
+	return nil
+}
+
+func (r *memoryOutboxRepository) RecordEventFailure(ctx context.Context, id, lastError string) error {
+	return nil
+}
+
+type memoryWebhookRepository struct {
+	mu            sync.Mutex
+	subscriptions []webhook.Subscription
+	deliveries    []webhook.Delivery
+	attempts      []webhook.Attempt
+}
+
+func (r *memoryWebhookRepository) CreateSubscription(ctx context.Context, sub webhook.Subscription) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	r.subscriptions = append(r.subscriptions, sub)
+	return nil
+}
+
+func (r *memoryWebhookRepository) GetSubscriptionByID(ctx context.Context, id string) (webhook.Subscription, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	for _, sub := range r.subscriptions {
+		if sub.ID == id {
+			return sub, nil
+		}
+	}
+	return webhook.Subscription{}, webhook.ErrSubscriptionNotFound
+}
+
+func (r *memoryWebhookRepository) ListSubscriptionsByUserID(ctx context.Context, userID string) ([]webhook.Subscription, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	var results []webhook.Subscription
+	for _, sub := range r.subscriptions {
+		if sub.UserID == userID {
+			results = append(results, sub)
+		}
+	}
+	return results, nil
+}
+
+func (r *memoryWebhookRepository) ListSubscriptionsByEventType(ctx context.Context, t event.Type) ([]webhook.Subscription, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	var results []webhook.Subscription
+	for _, sub := range r.subscriptions {
+		if slices.Contains(sub.EventTypes, t) {
+			results = append(results, sub)
+		}
+	}
+	return results, nil
+}
+
+func (r *memoryWebhookRepository) DeleteSubscription(ctx context.Context, id string) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	r.subscriptions = slices.DeleteFunc(r.subscriptions, func(sub webhook.Subscription) bool { return sub.ID == id })
+	r.deliveries = slices.DeleteFunc(r.deliveries, func(d webhook.Delivery) bool { return d.SubscriptionID == id })
+	return nil
+}
+
+func (r *memoryWebhookRepository) CreateDelivery(ctx context.Context, d webhook.Delivery) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	r.deliveries = append(r.deliveries, d)
+	return nil
+}
+
+func (r *memoryWebhookRepository) GetDeliveryByID(ctx context.Context, id string) (webhook.Delivery, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	for _, d := range r.deliveries {
+		if d.ID == id {
+			return d, nil
+		}
+	}
+	return webhook.Delivery{}, webhook.ErrDeliveryNotFound
+}
+
+func (r *memoryWebhookRepository) ListDeliveries(ctx context.Context, filter webhook.DeliveryFilter, limit, offset int) ([]webhook.Delivery, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	var results []webhook.Delivery
+	for _, d := range r.deliveries {
+		if (filter.UserID == "" || d.UserID == filter.UserID) &&
+			(filter.SubscriptionID == "" || d.SubscriptionID == filter.SubscriptionID) &&
+			(filter.Status == "" || d.Status == filter.Status) {
+			results = append(results, d)
+		}
+	}
+	return page(results, limit, offset), nil
+}
+
+func (r *memoryWebhookRepository) ListDueDeliveries(ctx context.Context, now time.Time, limit int) ([]webhook.Delivery, error) {
+	return nil, nil
+}
+
+func (r *memoryWebhookRepository) UpdateDelivery(ctx context.Context, d webhook.Delivery) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	for i := range r.deliveries {
+		if r.deliveries[i].ID == d.ID {
+			r.deliveries[i] = d
+			return nil
+		}
+	}
+	return webhook.ErrDeliveryNotFound
+}
+
+func (r *memoryWebhookRepository) RecordAttempt(ctx context.Context, a webhook.Attempt) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	r.attempts = append(r.attempts, a)
+	return nil
+}
+
+func (r *memoryWebhookRepository) ListAttempts(ctx context.Context, deliveryID string) ([]webhook.Attempt, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	var results []webhook.Attempt
+	for _, a := range r.attempts {
+		if a.DeliveryID == deliveryID {
+			results = append(results, a)
+		}
+	}
+	return results, nil
+}
+
+type memoryUserRepository struct {
+	mu    sync.Mutex
+	users map[string]user.User
+}
+
+func (r *memoryUserRepository) CreateUser(ctx context.Context, u user.User) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	if _, ok := r.users[u.ID]; ok {
+		return user.ErrUserExists
+	}
+	for _, other := range r.users {
+		if other.Email == u.Email {
+			return user.ErrEmailTaken
+		}
+	}
+	r.users[u.ID] = u
+	return nil
+}
+
+func (r *memoryUserRepository) GetUserByID(ctx context.Context, id string) (user.User, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	u, ok := r.users[id]
+	if !ok {
+		return user.User{}, user.ErrUserNotFound
+	}
+	return u, nil
+}
+
+func (r *memoryUserRepository) UpdateUser(ctx context.Context, u user.User) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	if _, ok := r.users[u.ID]; !ok {
+		return user.ErrUserNotFound
+	}
+	for _, other := range r.users {
+		if other.ID != u.ID && other.Email == u.Email {
+			return user.ErrEmailTaken
+		}
+	}
+	r.users[u.ID] = u
+	return nil
+}
+
+// memoryKYCRepository keeps the KYC levels, their history and the submissions in memory;
+// users without a level are unverified.
+type memoryKYCRepository struct {
+	mu          sync.Mutex
+	levels      map[string]kyc.Level
+	changes     []kyc.LevelChange
+	submissions []kyc.Submission
+}
+
+func (r *memoryKYCRepository) GetLevel(ctx context.Context, userID string) (kyc.Level, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	if level, ok := r.levels[userID]; ok {
+		return level, nil
+	}
+	return kyc.LevelNone, nil
+}
+
+func (r *memoryKYCRepository) SetLevel(ctx context.Context, change kyc.LevelChange) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	r.levels[change.UserID] = change.To
+	r.changes = append(r.changes, change)
+	return nil
+}
+
+func (r *memoryKYCRepository) ListLevelChanges(ctx context.Context, userID string) ([]kyc.LevelChange, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	var results []kyc.LevelChange
+	for _, c := range r.changes {
+		if c.UserID == userID {
+			results = append(results, c)
+		}
+	}
+	return results, nil
+}
+
+func (r *memoryKYCRepository) CreateSubmission(ctx context.Context, s kyc.Submission) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	r.submissions = append(r.submissions, s)
+	return nil
+}
+
+func (r *memoryKYCRepository) GetSubmissionByID(ctx context.Context, id string) (kyc.Submission, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	for _, s := range r.submissions {
+		if s.ID == id {
+			return s, nil
+		}
+	}
+	return kyc.Submission{}, kyc.ErrSubmissionNotFound
+}
+
+func (r *memoryKYCRepository) ListSubmissions(ctx context.Context, filter kyc.Filter, limit, offset int) ([]kyc.Submission, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	var results []kyc.Submission
+	for i := len(r.submissions) - 1; i >= 0; i-- {
+		s := r.submissions[i]
+		if (filter.UserID == "" || s.UserID == filter.UserID) && (filter.Status == "" || s.Status == filter.Status) {
+			results = append(results, s)
+		}
+	}
+	return page(results, limit, offset), nil
+}
+
+func (r *memoryKYCRepository) ReviewSubmission(ctx context.Context, s kyc.Submission) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	for i, stored := range r.submissions {
+		if stored.ID == s.ID {
+			if stored.Status != kyc.SubmissionPending {
+				return kyc.ErrSubmissionNotPending
+			}
+			r.submissions[i] = s
+			return nil
+		}
+	}
+	return kyc.ErrSubmissionNotFound
+}
+
+type memoryDocumentStore struct {
+	mu        sync.Mutex
+	documents map[string][]byte
+}
+
+func (s *memoryDocumentStore) Put(ctx context.Context, key string, content []byte) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.documents[key] = content
+	return nil
+}
+
+func (s *memoryDocumentStore) Get(ctx context.Context, key string) ([]byte, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	content, ok := s.documents[key]
+	if !ok {
+		return nil, kyc.ErrDocumentNotFound
+	}
+	return content, nil
+}
+
+// memorySnapshotRepository keeps the balance snapshots and their totals in memory.
+type memorySnapshotRepository struct {
+	mu        sync.Mutex
+	snapshots []snapshot.Snapshot
+	totals    []snapshot.Total
+}
+
+func (r *memorySnapshotRepository) CreateSnapshot(ctx context.Context, s snapshot.Snapshot) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	for _, stored := range r.snapshots {
+		if stored.UserID == s.UserID && stored.TakenAt.Equal(s.TakenAt) {
+			return nil
+		}
+	}
+	r.snapshots = append(r.snapshots, s)
+	return nil
+}
+
+func (r *memorySnapshotRepository) GetLatestSnapshot(ctx context.Context, userID string, at time.Time) (snapshot.Snapshot, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	var latest snapshot.Snapshot
+	found := false
+	for _, s := range r.snapshots {
+		if s.UserID == userID && !s.TakenAt.After(at) && (!found || s.TakenAt.After(latest.TakenAt)) {
+			latest, found = s, true
+		}
+	}
+	if !found {
+		return snapshot.Snapshot{}, snapshot.ErrSnapshotNotFound
+	}
+	return latest, nil
+}
+
+func (r *memorySnapshotRepository) CreateTotal(ctx context.Context, t snapshot.Total) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	for _, stored := range r.totals {
+		if stored.Currency == t.Currency && stored.TakenAt.Equal(t.TakenAt) {
+			return nil
+		}
+	}
+	r.totals = append(r.totals, t)
+	return nil
+}
+
+func (r *memorySnapshotRepository) ListTotals(ctx context.Context, at time.Time) ([]snapshot.Total, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	var totals []snapshot.Total
+	for _, t := range r.totals {
+		if t.TakenAt.Equal(at) {
+			totals = append(totals, t)
+		}
+	}
+	return totals, nil
+}
+
+// memoryPeriodRepository keeps the closed months in memory, oldest first.
+type memoryPeriodRepository struct {
+	mu     sync.Mutex
+	closes []period.Close
+}
+
+func (r *memoryPeriodRepository) CreateClose(ctx context.Context, c period.Close) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	for _, stored := range r.closes {
+		if stored.Start.Equal(c.Start) {
+			return period.ErrAlreadyClosed
+		}
+	}
+	r.closes = append(r.closes, c)
+	return nil
+}
+
+func (r *memoryPeriodRepository) GetLatestClose(ctx context.Context) (period.Close, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	if len(r.closes) == 0 {
+		return period.Close{}, period.ErrCloseNotFound
+	}
+	return r.closes[len(r.closes)-1], nil
+}
+
+func (r *memoryPeriodRepository) ListCloses(ctx context.Context, limit, offset int) ([]period.Close, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	closes := slices.Clone(r.closes)
+	slices.Reverse(closes)
+	return page(closes, limit, offset), nil
+}
+
+// memoryReservesRepository keeps the published liability trees in memory, oldest first.
+type memoryReservesRepository struct {
+	mu     sync.Mutex
+	roots  []reserves.Root
+	leaves map[string][]reserves.Leaf
+}
+
+func (r *memoryReservesRepository) CreateTree(ctx context.Context, root reserves.Root, leaves []reserves.Leaf) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	key := root.Currency + "@" + root.TakenAt.String()
+	if _, ok := r.leaves[key]; ok {
+		return reserves.ErrAlreadyPublished
+	}
+	if r.leaves == nil {
+		r.leaves = make(map[string][]reserves.Leaf)
+	}
+	r.roots = append(r.roots, root)
+	r.leaves[key] = leaves
+	return nil
+}
+
+func (r *memoryReservesRepository) GetLatestRoot(ctx context.Context, currency string) (reserves.Root, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	for _, root := range slices.Backward(r.roots) {
+		if root.Currency == currency {
+			return root, nil
+		}
+	}
+	return reserves.Root{}, reserves.ErrRootNotFound
+}
+
+func (r *memoryReservesRepository) ListLatestRoots(ctx context.Context) ([]reserves.Root, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	latest := map[string]reserves.Root{}
+	for _, root := range r.roots {
+		latest[root.Currency] = root
+	}
+	var roots []reserves.Root
+	for _, currency := range slices.Sorted(maps.Keys(latest)) {
+		roots = append(roots, latest[currency])
+	}
+	return roots, nil
+}
+
+func (r *memoryReservesRepository) ListLeaves(ctx context.Context, currency string, takenAt time.Time) ([]reserves.Leaf, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	return r.leaves[currency+"@"+takenAt.String()], nil
+}
+
+// memoryEscrowRepository keeps escrows in memory, oldest first.
+type memoryEscrowRepository struct {
+	mu      sync.Mutex
+	escrows []escrow.Escrow
+}
+
+func (r *memoryEscrowRepository) CreateEscrow(ctx context.Context, e escrow.Escrow) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	r.escrows = append(r.escrows, e)
+	return nil
+}
+
+func (r *memoryEscrowRepository) GetEscrowByID(ctx context.Context, id string) (escrow.Escrow, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	for _, e := range r.escrows {
+		if e.ID == id {
+			return e, nil
+		}
+	}
+	return escrow.Escrow{}, escrow.ErrEscrowNotFound
+}
+
+func (r *memoryEscrowRepository) ListEscrows(ctx context.Context, filter escrow.Filter, limit, offset int) ([]escrow.Escrow, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	var results []escrow.Escrow
+	for _, e := range slices.Backward(r.escrows) {
+		if (filter.UserID == "" || e.PayerID == filter.UserID || e.PayeeID == filter.UserID) && (filter.Status == "" || e.Status == filter.Status) {
+			results = append(results, e)
+		}
+	}
+	return page(results, limit, offset), nil
+}
+
+func (r *memoryEscrowRepository) ListExpired(ctx context.Context, now time.Time, limit int) ([]escrow.Escrow, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	var results []escrow.Escrow
+	for _, e := range r.escrows {
+		if e.Expired(now) {
+			results = append(results, e)
+		}
+	}
+	return page(results, limit, 0), nil
+}
+
+func (r *memoryEscrowRepository) UpdateEscrow(ctx context.Context, e escrow.Escrow, from escrow.Status) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	for i, stored := range r.escrows {
+		if stored.ID == e.ID {
+			if stored.Status != from {
+				return escrow.ErrEscrowSettled
+			}
+			r.escrows[i] = e
+			return nil
+		}
+	}
+	return escrow.ErrEscrowNotFound
+}
+
+// memoryInterestRepository keeps accruals and payouts in memory, oldest first.
+type memoryInterestRepository struct {
+	mu       sync.Mutex
+	accruals []interest.Accrual
+	payouts  []interest.Payout
+}
+
+func (r *memoryInterestRepository) CreateAccrual(ctx context.Context, a interest.Accrual) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	for _, stored := range r.accruals {
+		if stored.UserID == a.UserID && stored.Day.Equal(a.Day) {
+			return interest.ErrAlreadyAccrued
+		}
+	}
+	r.accruals = append(r.accruals, a)
+	return nil
+}
+
+func (r *memoryInterestRepository) ListAccrualTotals(ctx context.Context, from, to time.Time) ([]interest.Total, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	var totals []interest.Total
+	for _, a := range r.accruals {
+		if a.Day.Before(from) || !a.Day.Before(to) {
+			continue
+		}
+		i := slices.IndexFunc(totals, func(t interest.Total) bool { return t.UserID == a.UserID })
+		if i < 0 {
+			totals = append(totals, interest.Total{UserID: a.UserID, Currency: a.Currency})
+			i = len(totals) - 1
+		}
+		totals[i].Micros += a.Micros
+	}
+	return totals, nil
+}
+
+func (r *memoryInterestRepository) CreatePayout(ctx context.Context, p interest.Payout) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	for _, stored := range r.payouts {
+		if stored.UserID == p.UserID && stored.Month.Equal(p.Month) {
+			return interest.ErrAlreadyPaid
+		}
+	}
+	r.payouts = append(r.payouts, p)
+	return nil
+}
+
+func (r *memoryInterestRepository) GetPayout(ctx context.Context, userID string, month time.Time) (interest.Payout, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	for _, p := range r.payouts {
+		if p.UserID == userID && p.Month.Equal(month) {
+			return p, nil
+		}
+	}
+	return interest.Payout{}, interest.ErrPayoutNotFound
+}
+
+func (r *memoryInterestRepository) GetLatestPayout(ctx context.Context, userID string, month time.Time) (interest.Payout, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	for _, p := range slices.Backward(r.payouts) {
+		if p.UserID == userID && p.Month.Before(month) {
+			return p, nil
+		}
+	}
+	return interest.Payout{}, interest.ErrPayoutNotFound
+}
+
+func (r *memoryInterestRepository) ListPayouts(ctx context.Context, userID string, limit, offset int) ([]interest.Payout, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	var results []interest.Payout
+	for _, p := range slices.Backward(r.payouts) {
+		if userID == "" || p.UserID == userID {
+			results = append(results, p)
+		}
+	}
+	return page(results, limit, offset), nil
+}
+
+// memoryPaymentRequestRepository keeps payment requests in memory, oldest first.
+type memoryPaymentRequestRepository struct {
+	mu       sync.Mutex
+	requests []payment.Request
+}
+
+func (r *memoryPaymentRequestRepository) CreateRequest(ctx context.Context, p payment.Request) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	r.requests = append(r.requests, p)
+	return nil
+}
+
+func (r *memoryPaymentRequestRepository) GetRequestByID(ctx context.Context, id string) (payment.Request, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	for _, p := range r.requests {
+		if p.ID == id {
+			return p, nil
+		}
+	}
+	return payment.Request{}, payment.ErrRequestNotFound
+}
+
+func (r *memoryPaymentRequestRepository) GetRequestByToken(ctx context.Context, token string) (payment.Request, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	for _, p := range r.requests {
+		if p.Token == token {
+			return p, nil
+		}
+	}
+	return payment.Request{}, payment.ErrRequestNotFound
+}
+
+func (r *memoryPaymentRequestRepository) ListRequests(ctx context.Context, filter payment.Filter, limit, offset int) ([]payment.Request, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	var results []payment.Request
+	for _, p := range slices.Backward(r.requests) {
+		party := p.RequesterID == filter.UserID || p.PayerID == filter.UserID || p.PaidBy == filter.UserID
+		if (filter.UserID == "" || party) && (filter.Status == "" || p.Status == filter.Status) {
+			results = append(results, p)
+		}
+	}
+	return page(results, limit, offset), nil
+}
+
+func (r *memoryPaymentRequestRepository) ListExpired(ctx context.Context, now time.Time, limit int) ([]payment.Request, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	var results []payment.Request
+	for _, p := range r.requests {
+		if p.Expired(now) {
+			results = append(results, p)
+		}
+	}
+	return page(results, limit, 0), nil
+}
+
+func (r *memoryPaymentRequestRepository) UpdateRequest(ctx context.Context, p payment.Request, from payment.Status) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	for i, stored := range r.requests {
+		if stored.ID == p.ID {
+			if stored.Status != from {
+				return payment.ErrRequestClosed
+			}
+			r.requests[i] = p
+			return nil
+		}
+	}
+	return payment.ErrRequestNotFound
+}
+
+// memorySanctionsRepository keeps the compliance cases in memory, oldest first.
+type memorySanctionsRepository struct {
+	mu    sync.Mutex
+	cases []sanctions.Case
+}
+
+func (r *memorySanctionsRepository) CreateCase(ctx context.Context, c sanctions.Case) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	r.cases = append(r.cases, c)
+	return nil
+}
+
+func (r *memorySanctionsRepository) GetCaseByID(ctx context.Context, id string) (sanctions.Case, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	for _, c := range r.cases {
+		if c.ID == id {
+			return c, nil
+		}
+	}
+	return sanctions.Case{}, sanctions.ErrCaseNotFound
+}
+
+func (r *memorySanctionsRepository) FindCase(ctx context.Context, subjectType sanctions.SubjectType, subjectID, entryID string) (sanctions.Case, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	for i := len(r.cases) - 1; i >= 0; i-- {
+		c := r.cases[i]
+		if c.SubjectType == subjectType && c.SubjectID == subjectID && c.EntryID == entryID {
+			return c, nil
+		}
+	}
+	return sanctions.Case{}, sanctions.ErrCaseNotFound
+}
+
+func (r *memorySanctionsRepository) ListCases(ctx context.Context, filter sanctions.Filter, limit, offset int) ([]sanctions.Case, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	var results []sanctions.Case
+	for i := len(r.cases) - 1; i >= 0; i-- {
+		c := r.cases[i]
+		if (filter.SubjectID == "" || c.SubjectID == filter.SubjectID) && (filter.Status == "" || c.Status == filter.Status) {
+			results = append(results, c)
+		}
+	}
+	return page(results, limit, offset), nil
+}
+
+func (r *memorySanctionsRepository) ClearCase(ctx context.Context, c sanctions.Case) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	for i, stored := range r.cases {
+		if stored.ID == c.ID {
+			if stored.Status != sanctions.CaseOpen {
+				return sanctions.ErrCaseNotOpen
+			}
+			r.cases[i] = c
+			return nil
+		}
+	}
+	return sanctions.ErrCaseNotFound
+}
+
+type passthroughTransactionManager struct{}
+
+func (passthroughTransactionManager) Do(ctx context.Context, fn func(ctx context.Context) error) error {
+	return fn(ctx)
+}
+
+func (passthroughTransactionManager) ReadOnly(ctx context.Context, fn func(ctx context.Context) error) error {
+	return fn(ctx)
+}
+
+type memoryAPIKeyRepository struct {
+	mu     sync.Mutex
+	keys   map[string]auth.APIKey
+	nonces map[string]bool
+}
+
+func (r *memoryAPIKeyRepository) CreateAPIKey(ctx context.Context, key auth.APIKey) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	r.keys[key.ID] = key
+	return nil
+}
+
+func (r *memoryAPIKeyRepository) GetAPIKeyByID(ctx context.Context, id string) (auth.APIKey, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	key, ok := r.keys[id]
+	if !ok {
+		return auth.APIKey{}, auth.ErrAPIKeyNotFound
+	}
+	return key, nil
+}
+
+func (r *memoryAPIKeyRepository) RevokeAPIKey(ctx context.Context, id string, revokedAt time.Time) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	key, ok := r.keys[id]
+	if !ok {
+		return auth.ErrAPIKeyNotFound
+	}
+	key.RevokedAt = &revokedAt
+	r.keys[id] = key
+	return nil
+}
+
+func (r *memoryAPIKeyRepository) RecordNonce(ctx context.Context, keyID, nonce string, expiresAt time.Time) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	if r.nonces[keyID+"/"+nonce] {
+		return auth.ErrNonceReused
+	}
+	r.nonces[keyID+"/"+nonce] = true
+	return nil
+}
+
+var nonceSeq atomic.Int64
+
+type staticKeys map[string]crypto.PublicKey
+
+func (s staticKeys) Key(ctx context.Context, kid string) (crypto.PublicKey, error) {
+	key, ok := s[kid]
+	if !ok {
+		return nil, auth.ErrUnknownSigningKey
+	}
+	return key, nil
+}
+
+// testCredentials holds either an API key or a bearer token.
+type testCredentials struct {
+	keyID  string
+	secret string
+	token  string
+}
+
+// sign adds the bearer token or the API key signature headers for body to req.
+func (c testCredentials) sign(req *http.Request, body string) {
+	if c.token != "" {
+		req.Header.Set("Authorization", "Bearer "+c.token)
+		return
+	}
+
+	timestamp := time.Now().Unix()
+	nonce := fmt.Sprintf("nonce-%d", nonceSeq.Add(1))
+	payload := auth.CanonicalRequest(timestamp, nonce, req.Method, req.URL.RequestURI(), []byte(body))
+
+	req.Header.Set(HeaderAPIKey, c.keyID)
+	req.Header.Set(HeaderTimestamp, fmt.Sprint(timestamp))
+	req.Header.Set(HeaderNonce, nonce)
+	req.Header.Set(HeaderSignature, auth.SignRequest(c.secret, payload))
+}
+
+// newTestHandler wires the real services to in-memory repositories seeded with two wallets,
+// three completed transactions ("tx-transfer" from user2 to user1 and the deposits
+// "tx-deposit" to user1 and "tx-user2" to user2), the pending withdrawals "tx-pending" of
+// user1 and "tx-payout" of user2 and the withdrawals "tx-approve" of user1 and "tx-reject"
+// of user2 awaiting approval, whose funds are held, user2's withdrawal "tx-settled" of 500,
+// created on 2024-06-29 and completed on 2024-07-01, and two pending adjustments requested
+// by the admin "ops", "adj-approve" and "adj-reject".
+// Adjustments above 1000 and withdrawals above 5000 USD need approval. Every user may make
+// 1000 USD withdrawals a month, and user2, whose "tx-transfer" counts, one USD transfer a day.
+// Withdrawals of more than 90% of the available balance within an hour of a deposit of at
+// least 10000 are blocked.
+// user1 and user2 are registered and active, and "frozen" is registered but deactivated.
+// user1 and user2 are fully KYC verified; "newbie", who has a 500 USD wallet, is not and
+// may hold at most 1000, withdraw 200 and send or receive 100 at once. newbie's KYC
+// submissions "kyc-newbie" for the basic level, which lifts the caps, and "kyc-reject"
+// await review.
+// The sanctions list holds "Ivan Sidorov" and "Acme Trading LLC", known by the address
+// "0xabc123". The registered user "ivan", named "Ivan Sidorow", has an empty USD wallet and
+// an open compliance case, "case-ivan".
+// It returns credentials by name: the "user1" API key may do anything with user1's wallet,
+// "reader" may only read it, and "nobody" belongs to a user without a wallet. "user1-jwt"
+// is a read-only bearer token for user1, "admin-jwt" one for "ops" with the admin role and
+// every permission, "admin2-jwt" one for a second admin, and "forged-jwt" is signed by a key
+// the server does not trust. "newbie" may do anything with newbie's wallet.
+func newTestHandler(t *testing.T) (http.Handler, map[string]testCredentials) {
+	t.Helper()
+
+	now := time.Now()
+	walletRepo := &memoryWalletRepository{wallets: map[string]wallet.Wallet{
+		"user1":  {UserID: "user1", Balance: 10000, Held: 2000, Currency: "USD", CreatedAt: now, UpdatedAt: now},
+		"user2":  {UserID: "user2", Balance: 20000, Held: 2000, Currency: "USD", CreatedAt: now, UpdatedAt: now},
+		"newbie": {UserID: "newbie", Balance: 500, Currency: "USD", CreatedAt: now, UpdatedAt: now},
+		"ivan":   {UserID: "ivan", Currency: "USD", CreatedAt: now, UpdatedAt: now},
+
+		escrow.AccountID("USD"): {UserID: escrow.AccountID("USD"), Balance: 4000, Currency: "USD", CreatedAt: now, UpdatedAt: now},
+		"house:USD":             {UserID: "house:USD", Balance: 100000, Currency: "USD", CreatedAt: now, UpdatedAt: now},
+	}}
+	settledCreatedAt := time.Date(2024, 6, 29, 12, 0, 0, 0, time.UTC)
+	settledCompletedAt := time.Date(2024, 7, 1, 12, 0, 0, 0, time.UTC)
+	transactionRepo := &memoryTransactionRepository{txs: []transaction.Transaction{
+		{ID: "tx-transfer", FromUserID: "user2", ToUserID: "user1", Amount: 1000, Currency: "USD", Type: transaction.TransactionTypeTransfer, Status: transaction.StatusCompleted, CreatedAt: now},
+		{ID: "tx-deposit", ToUserID: "user1", Amount: 1000, Currency: "USD", Type: transaction.TransactionTypeDeposit, Status: transaction.StatusCompleted, CreatedAt: now},
+		{ID: "tx-user2", ToUserID: "user2", Amount: 1000, Currency: "USD", Type: transaction.TransactionTypeDeposit, Status: transaction.StatusCompleted, CreatedAt: now},
+		{ID: "tx-pending", FromUserID: "user1", Amount: 1000, Currency: "USD", Type: transaction.TransactionTypeWithdraw, Status: transaction.StatusPending, CreatedAt: now},
+		{ID: "tx-payout", FromUserID: "user2", Amount: 1000, Currency: "USD", Type: transaction.TransactionTypeWithdraw, Status: transaction.StatusPending, CreatedAt: now},
+		{ID: "tx-approve", FromUserID: "user1", Amount: 1000, Currency: "USD", Type: transaction.TransactionTypeWithdraw, Status: transaction.StatusAwaitingApproval, StatusReason: "wallet created recently", CreatedAt: now},
+		{ID: "tx-reject", FromUserID: "user2", Amount: 1000, Currency: "USD", Type: transaction.TransactionTypeWithdraw, Status: transaction.StatusAwaitingApproval, StatusReason: "wallet created recently", CreatedAt: now},
+		{ID: "tx-settled", FromUserID: "user2", Amount: 500, Currency: "USD", Type: transaction.TransactionTypeWithdraw, Status: transaction.StatusCompleted, CreatedAt: settledCreatedAt, CompletedAt: &settledCompletedAt},
+	}}
+	adjustmentRepo := &memoryAdjustmentRepository{}
+	for _, id := range []string{"adj-approve", "adj-reject"} {
+		a, err := adjustment.NewAdjustment(id, "user2", adjustment.DirectionDebit, 5000, "USD", adjustment.ReasonReversal, "duplicate deposit", "ops")
+		require.NoError(t, err)
+		adjustmentRepo.adjustments = append(adjustmentRepo.adjustments, a)
+	}
+
+	escrowRepo := &memoryEscrowRepository{}
+	for _, e := range []struct {
+		id, payerID, payeeID, disputedBy string
+	}{{"esc-release", "user1", "user2", ""}, {"esc-refund", "user2", "user1", ""}, {"esc-dispute", "user1", "user2", ""}, {"esc-resolve", "user2", "user1", "user1"}} {
+		esc, err := escrow.NewEscrow(e.id, e.payerID, e.payeeID, 1000, "USD", "goods delivered", escrow.ExpiryRelease, now.Add(time.Hour), now)
+		require.NoError(t, err)
+		esc.FundingTransactionID = "tx-" + e.id
+		if e.disputedBy != "" {
+			require.NoError(t, esc.Dispute(e.disputedBy, "goods not delivered", now))
+		}
+		escrowRepo.escrows = append(escrowRepo.escrows, esc)
+	}
+
+	interestProduct, err := interest.NewProduct("USD", "house:USD", 100, []interest.Tier{{From: 0, APY: 5}})
+	require.NoError(t, err)
+	interestRepo := &memoryInterestRepository{accruals: []interest.Accrual{
+		{UserID: "user1", Currency: "USD", Day: time.Date(2024, 2, 10, 0, 0, 0, 0, time.UTC), Balance: 10000, Micros: 1_300_000},
+		{UserID: "user1", Currency: "USD", Day: time.Date(2024, 2, 11, 0, 0, 0, 0, time.UTC), Balance: 10000, Micros: 1_300_000},
+	}}
+
+	paymentRequestRepo := &memoryPaymentRequestRepository{}
+	for _, p := range []struct {
+		id, requesterID, payerID string
+	}{{"pr-accept", "user2", "user1"}, {"pr-decline", "user2", "user1"}, {"pr-cancel", "user1", "user2"}, {"pr-open", "user2", ""}} {
+		req, err := payment.NewRequest(p.id, "tok-"+p.id, p.requesterID, p.payerID, 500, "USD", "dinner", now.Add(time.Hour), now)
+		require.NoError(t, err)
+		paymentRequestRepo.requests = append(paymentRequestRepo.requests, req)
+	}
+
+	webhookRepo := &memoryWebhookRepository{subscriptions: []webhook.Subscription{
+		{ID: "wh-user1", UserID: "user1", URL: "https://partner.test/hooks", EventTypes: []event.Type{event.TypeFundsDeposited}, Secret: "whsec_test", CreatedAt: now},
+		{ID: "wh-user2", UserID: "user2", URL: "https://partner.test/hooks", EventTypes: []event.Type{event.TypeFundsDeposited}, Secret: "whsec_test", CreatedAt: now},
+	}}
+	for _, d := range []webhook.Delivery{
+		{ID: "whd-dead", SubscriptionID: "wh-user1", UserID: "user1", Status: webhook.DeliveryDead, Attempts: 10},
+		{ID: "whd-pending", SubscriptionID: "wh-user1", UserID: "user1", Status: webhook.DeliveryPending},
+		{ID: "whd-user2", SubscriptionID: "wh-user2", UserID: "user2", Status: webhook.DeliveryDead, Attempts: 10},
+	} {
+		d.EventID, d.EventType, d.CreatedAt, d.UpdatedAt, d.NextAttemptAt = "ev-"+d.ID, event.TypeFundsDeposited, now, now, now
+		webhookRepo.deliveries = append(webhookRepo.deliveries, d)
+	}
+	webhookRepo.attempts = []webhook.Attempt{{DeliveryID: "whd-dead", AttemptedAt: now, StatusCode: 500, Error: "unexpected status 500", Duration: 20 * time.Millisecond}}
+
+	drain, err := risk.NewRule(risk.RuleConfig{ID: "drain", Kind: risk.KindDrainAfterDeposit, Decision: risk.DecisionBlock, Window: time.Hour, Ratio: 0.9, MinAmount: 10000})
+	require.NoError(t, err)
+
+	userRepo := &memoryUserRepository{users: map[string]user.User{}}
+	for _, id := range []string{"user1", "user2", "frozen", "newbie"} {
+		u, err := user.NewUser(id, id, id+"@example.test")
+		require.NoError(t, err)
+		if id == "frozen" {
+			u.Status = user.StatusDeactivated
+		}
+		userRepo.users[id] = u
+	}
+	ivan, err := user.NewUser("ivan", "Ivan Sidorow", "ivan@example.test")
+	require.NoError(t, err)
+	userRepo.users["ivan"] = ivan
+	userService := user.NewUserService(userRepo)
+
+	kycRepo := &memoryKYCRepository{levels: map[string]kyc.Level{"user1": kyc.LevelFull, "user2": kyc.LevelFull}}
+	documents := &memoryDocumentStore{documents: map[string][]byte{}}
+	for _, s := range []struct {
+		id    string
+		level kyc.Level
+	}{{"kyc-newbie", kyc.LevelBasic}, {"kyc-reject", kyc.LevelFull}} {
+		sub, err := kyc.NewSubmission(s.id, "newbie", s.level, "passport", "passport.png", "image/png", 4)
+		require.NoError(t, err)
+		kycRepo.submissions = append(kycRepo.submissions, sub)
+		documents.documents[s.id] = []byte("scan")
+	}
+	kycService := kyc.NewKYCService(kycRepo, documents, kyc.Policy{
+		kyc.LevelNone:  {MaxBalance: 1000, MaxWithdrawal: 200, MaxTransfer: 100},
+		kyc.LevelBasic: {MaxBalance: 100000},
+	})
+
+	sanctionsList, err := sanctions.NewList([]sanctions.Entry{
+		{ID: "sdn-1", Name: "Ivan Sidorov", Program: "SDN"},
+		{ID: "sdn-2", Name: "Acme Trading LLC", Identifiers: []string{"0xabc123"}, Program: "SDN"},
+	}, sanctions.DefaultThreshold)
+	require.NoError(t, err)
+	sanctionsRepo := &memorySanctionsRepository{cases: []sanctions.Case{
+		sanctions.NewCase("case-ivan", sanctions.Subject{Type: sanctions.SubjectUser, ID: "ivan", Name: ivan.Name}, sanctions.Match{EntryID: "sdn-1", EntryName: "Ivan Sidorov", Score: 0.92}, "transfer"),
+	}}
+
+	walletService := wallet.NewWalletService(walletRepo)
+	walletUC := usecase.NewWalletUseCase(usecase.WalletDependencies{
+		Wallets:      walletService,
+		Transactions: transaction.NewTransactionService(transactionRepo),
+		TxManager:    passthroughTransactionManager{},
+		Audit:        audit.NewAuditService(&memoryAuditRepository{}, walletService),
+		Events:       event.NewEventService(&memoryOutboxRepository{}),
+		Limits: limit.NewLimitService(&memoryLimitRepository{
+			overrides: map[string][]limit.Limit{"user2": {{Operation: limit.OperationTransfer, Currency: "USD", Period: limit.PeriodDaily, MaxCount: 1}}},
+		}, map[limit.Tier][]limit.Limit{
+			"standard": {{Operation: limit.OperationWithdrawal, Currency: "USD", Period: limit.PeriodMonthly, MaxCount: 1000}},
+		}, "standard"),
+		Risk:      risk.NewRiskService(&memoryRiskRepository{wallets: walletRepo, transactions: transactionRepo}, []risk.Rule{drain}),
+		Users:     userService,
+		KYC:       kycService,
+		Sanctions: sanctions.NewSanctionsService(sanctionsRepo, sanctionsList),
+		Snapshots: snapshot.NewSnapshotService(&memorySnapshotRepository{
+			snapshots: []snapshot.Snapshot{
+				{UserID: "user1", Currency: "USD", Balance: 2500, TakenAt: time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC)},
+			},
+			totals: []snapshot.Total{
+				{Currency: "USD", Balance: 10000, Wallets: 2, TakenAt: time.Date(2024, 3, 15, 0, 0, 0, 0, time.UTC)},
+				{Currency: "USD", Balance: 10000, Wallets: 2, TakenAt: time.Date(2024, 3, 16, 0, 0, 0, 0, time.UTC)},
+			},
+		}),
+		Reserves:         reserves.NewReservesService(&memoryReservesRepository{}),
+		WithdrawalPolicy: usecase.WithdrawalPolicy{Thresholds: map[string]int64{"USD": 5000}, ApprovalTTL: time.Hour},
+	})
+	_, err = walletUC.PublishLiabilities(context.Background(), now)
+	require.NoError(t, err)
+
+	apiKeyRepo := &memoryAPIKeyRepository{keys: map[string]auth.APIKey{}, nonces: map[string]bool{}}
+	secrets, err := auth.NewSecretBox(strings.Repeat("ab", auth.SecretKeySize))
+	require.NoError(t, err)
+	apiKeyService := auth.NewAPIKeyService(apiKeyRepo, apiKeyRepo, secrets)
+	credentials := map[string]testCredentials{}
+	issue := func(name, userID string, permissions ...auth.Permission) {
+		key, secret, err := apiKeyService.IssueAPIKey(context.Background(), userID, permissions)
+		require.NoError(t, err)
+		credentials[name] = testCredentials{keyID: key.ID, secret: secret}
+	}
+	issue("user1", "user1", auth.PermissionRead, auth.PermissionTrade, auth.PermissionWithdraw)
+	issue("reader", "user1", auth.PermissionRead)
+	issue("nobody", "nobody", auth.PermissionRead, auth.PermissionTrade, auth.PermissionWithdraw)
+	issue("newbie", "newbie", auth.PermissionRead, auth.PermissionTrade, auth.PermissionWithdraw)
+
+	signingKey, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	require.NoError(t, err)
+	forgingKey, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	require.NoError(t, err)
+	mint := func(name string, key *ecdsa.PrivateKey, claims jwt.MapClaims) {
+		claims["iss"] = "https://idp.test"
+		claims["aud"] = "wallet-api"
+		claims["exp"] = now.Add(time.Hour).Unix()
+		token := jwt.NewWithClaims(jwt.SigningMethodES256, claims)
+		token.Header["kid"] = "test"
+		signed, err := token.SignedString(key)
+		require.NoError(t, err)
+		credentials[name] = testCredentials{token: signed}
+	}
+	mint("user1-jwt", signingKey, jwt.MapClaims{"sub": "user1", "scope": "wallet:read"})
+	mint("admin-jwt", signingKey, jwt.MapClaims{"sub": "ops", "roles": []string{auth.RoleAdmin}, "scope": "wallet:read wallet:trade wallet:withdraw"})
+	mint("admin2-jwt", signingKey, jwt.MapClaims{"sub": "ops2", "roles": []string{auth.RoleAdmin}, "scope": "wallet:read"})
+	mint("forged-jwt", forgingKey, jwt.MapClaims{"sub": "ops", "roles": []string{auth.RoleAdmin}, "scope": "wallet:read"})
+
+	verifier, err := oidc.NewVerifier(staticKeys{"test": &signingKey.PublicKey}, oidc.VerifierConfig{Issuer: "https://idp.test", Audience: "wallet-api"})
+	require.NoError(t, err)
+	authenticator := ChainAuthenticator{NewAPIKeyAuthenticator(apiKeyService), NewBearerAuthenticator(verifier)}
+	adminUC := usecase.NewAdminUseCase(walletUC, adjustment.NewAdjustmentService(adjustmentRepo), period.NewPeriodService(&memoryPeriodRepository{}), 1000)
+	webhookUC := usecase.NewWebhookUseCase(
+		webhook.NewWebhookService(webhookRepo, webhookRepo, webhook.DefaultRetryPolicy),
+		nil,
+		passthroughTransactionManager{},
+		10,
+		time.Second,
+	)
+	return NewRouter(authenticator, NewHandler(walletUC), NewAdminHandler(adminUC), NewWebhookHandler(webhookUC), NewUserHandler(usecase.NewUserUseCase(userService, kycService)), NewEscrowHandler(usecase.NewEscrowUseCase(walletUC, escrow.NewEscrowService(escrowRepo))),
+		NewInterestHandler(usecase.NewInterestUseCase(walletUC, interest.NewInterestService(interestRepo, []interest.Product{interestProduct}))),
+		NewPaymentRequestHandler(usecase.NewPaymentRequestUseCase(walletUC, payment.NewRequestService(paymentRequestRepo)))), credentials
+}
+
+func loadOpenAPIRouter(t *testing.T) (*openapi3.T, routers.Router) {
+	t.Helper()
+
+	doc, err := openapi3.NewLoader().LoadFromData(openAPISpec)
+	require.NoError(t, err)
+	require.NoError(t, doc.Validate(context.Background()))
+
+	router, err := legacy.NewRouter(doc)
+	require.NoError(t, err)
+	return doc, router
+}
+
+func TestHandler_OpenAPIConformance(t *testing.T) {
+	doc, specRouter := loadOpenAPIRouter(t)
+	handler, credentials := newTestHandler(t)
+	covered := map[string]bool{}
+
+	tests := []struct {
+		name       string
+		method     string
+		target     string
+		body       string
+		wantStatus int
+		// as names the credentials that sign the request; empty means "user1" and
+		// "anonymous" sends none.
+		as string
+		// invalidRequest marks requests that intentionally violate the spec.
+		invalidRequest bool
+	}{
+		{name: "deposit", method: http.MethodPost, target: "/wallet/deposit", body: `{"user_id":"user1","amount":1000,"currency":"USD"}`, wantStatus: http.StatusOK},
+		{name: "deposit to own wallet by default", method: http.MethodPost, target: "/wallet/deposit", body: `{"amount":1000,"currency":"USD"}`, wantStatus: http.StatusOK},
+		{name: "deposit invalid amount", method: http.MethodPost, target: "/wallet/deposit", body: `{"user_id":"user1","amount":-1,"currency":"USD"}`, wantStatus: http.StatusBadRequest},
+		{name: "deposit unknown wallet", method: http.MethodPost, target: "/wallet/deposit", body: `{"amount":1000,"currency":"USD"}`, as: "nobody", wantStatus: http.StatusNotFound},
+		{name: "deposit to another wallet", method: http.MethodPost, target: "/wallet/deposit", body: `{"user_id":"user2","amount":1000,"currency":"USD"}`, wantStatus: http.StatusForbidden},
+		{name: "deposit to another wallet as admin", method: http.MethodPost, target: "/wallet/deposit", body: `{"user_id":"user2","amount":1000,"currency":"USD"}`, as: "admin-jwt", wantStatus: http.StatusOK},
+		{name: "deposit with read-only token", method: http.MethodPost, target: "/wallet/deposit", body: `{"amount":1000,"currency":"USD"}`, as: "user1-jwt", wantStatus: http.StatusForbidden},
+		{name: "deposit without credentials", method: http.MethodPost, target: "/wallet/deposit", body: `{"user_id":"user1","amount":1000,"currency":"USD"}`, as: "anonymous", wantStatus: http.StatusUnauthorized},
+		{name: "deposit malformed body", method: http.MethodPost, target: "/wallet/deposit", body: `{`, wantStatus: http.StatusBadRequest, invalidRequest: true},
+		{name: "withdraw", method: http.MethodPost, target: "/wallet/withdraw", body: `{"user_id":"user1","amount":500,"currency":"USD"}`, wantStatus: http.StatusOK},
+		{name: "withdraw insufficient funds", method: http.MethodPost, target: "/wallet/withdraw", body: `{"user_id":"user1","amount":99999999,"currency":"USD"}`, wantStatus: http.StatusBadRequest},
+		{name: "withdraw above the approval threshold", method: http.MethodPost, target: "/wallet/withdraw", body: `{"amount":6000,"currency":"USD"}`, wantStatus: http.StatusAccepted},
+		{name: "deposit before draining the wallet", method: http.MethodPost, target: "/wallet/deposit", body: `{"user_id":"user2","amount":50000,"currency":"USD"}`, as: "admin-jwt", wantStatus: http.StatusOK},
+		{name: "withdraw blocked by risk rules", method: http.MethodPost, target: "/wallet/withdraw", body: `{"user_id":"user2","amount":65000,"currency":"USD"}`, as: "admin-jwt", wantStatus: http.StatusForbidden},
+		{name: "withdraw with read-only key", method: http.MethodPost, target: "/wallet/withdraw", body: `{"user_id":"user1","amount":500,"currency":"USD"}`, as: "reader", wantStatus: http.StatusForbidden},
+		{name: "request withdrawal", method: http.MethodPost, target: "/wallet/withdrawals", body: `{"amount":500,"currency":"USD"}`, wantStatus: http.StatusCreated},
+		{name: "request withdrawal without funds", method: http.MethodPost, target: "/wallet/withdrawals", body: `{"amount":99999999,"currency":"USD"}`, wantStatus: http.StatusBadRequest},
+		{name: "request withdrawal with read-only key", method: http.MethodPost, target: "/wallet/withdrawals", body: `{"amount":500,"currency":"USD"}`, as: "reader", wantStatus: http.StatusForbidden},
+		{name: "transfer", method: http.MethodPost, target: "/wallet/transfer", body: `{"from_user_id":"user1","to_user_id":"user2","amount":200,"currency":"USD"}`, wantStatus: http.StatusOK},
+		{name: "transfer unknown recipient", method: http.MethodPost, target: "/wallet/transfer", body: `{"from_user_id":"user1","to_user_id":"nobody","amount":200,"currency":"USD"}`, wantStatus: http.StatusNotFound},
+		{name: "transfer to deactivated user", method: http.MethodPost, target: "/wallet/transfer", body: `{"from_user_id":"user1","to_user_id":"frozen","amount":200,"currency":"USD"}`, wantStatus: http.StatusConflict},
+		{name: "transfer from another wallet", method: http.MethodPost, target: "/wallet/transfer", body: `{"from_user_id":"user2","to_user_id":"user1","amount":200,"currency":"USD"}`, wantStatus: http.StatusForbidden},
+		{name: "batch best effort", method: http.MethodPost, target: "/wallet/transfers/batch", body: `{"mode":"best_effort","transfers":[{"from_user_id":"user1","to_user_id":"user2","amount":100,"currency":"USD"},{"from_user_id":"user1","to_user_id":"nobody","amount":100,"currency":"USD"}]}`, wantStatus: http.StatusOK},
+		{name: "batch atomic", method: http.MethodPost, target: "/wallet/transfers/batch", body: `{"mode":"atomic","transfers":[{"to_user_id":"user2","amount":100,"currency":"USD"}]}`, wantStatus: http.StatusOK},
+		{name: "batch atomic rolled back", method: http.MethodPost, target: "/wallet/transfers/batch", body: `{"mode":"atomic","transfers":[{"from_user_id":"user1","to_user_id":"user2","amount":100,"currency":"USD"},{"from_user_id":"user1","to_user_id":"user2","amount":99999999,"currency":"USD"}]}`, wantStatus: http.StatusUnprocessableEntity},
+		{name: "transfer above the limit", method: http.MethodPost, target: "/wallet/transfer", body: `{"from_user_id":"user2","to_user_id":"user1","amount":200,"currency":"USD"}`, as: "admin-jwt", wantStatus: http.StatusUnprocessableEntity},
+		{name: "batch from another wallet", method: http.MethodPost, target: "/wallet/transfers/batch", body: `{"mode":"atomic","transfers":[{"from_user_id":"user2","to_user_id":"user1","amount":100,"currency":"USD"}]}`, wantStatus: http.StatusForbidden},
+		{name: "split pay by percentages", method: http.MethodPost, target: "/wallet/transfers/split", body: `{"amount":150,"currency":"USD","shares":[{"user_id":"user2","percent":33.33},{"user_id":"newbie","percent":66.67}]}`, wantStatus: http.StatusOK},
+		{name: "split pay fixed amounts", method: http.MethodPost, target: "/wallet/transfers/split", body: `{"direction":"pay","user_id":"user1","currency":"USD","shares":[{"user_id":"user2","amount":100},{"user_id":"newbie","amount":50}]}`, wantStatus: http.StatusOK},
+		{name: "split collect as admin", method: http.MethodPost, target: "/wallet/transfers/split", body: `{"direction":"collect","user_id":"user2","amount":100,"currency":"USD","remainder":"last","shares":[{"user_id":"user1","percent":50},{"user_id":"newbie","percent":50}]}`, as: "admin-jwt", wantStatus: http.StatusOK},
+		{name: "split collect from another wallet", method: http.MethodPost, target: "/wallet/transfers/split", body: `{"direction":"collect","currency":"USD","shares":[{"user_id":"user2","amount":100}]}`, wantStatus: http.StatusForbidden},
+		{name: "split percentages short of 100", method: http.MethodPost, target: "/wallet/transfers/split", body: `{"amount":100,"currency":"USD","shares":[{"user_id":"user2","percent":50}]}`, wantStatus: http.StatusBadRequest},
+		{name: "split percentage with three decimals", method: http.MethodPost, target: "/wallet/transfers/split", body: `{"amount":1000,"currency":"USD","shares":[{"user_id":"user2","percent":33.333},{"user_id":"newbie","percent":66.667}]}`, wantStatus: http.StatusBadRequest},
+		{name: "split without funds", method: http.MethodPost, target: "/wallet/transfers/split", body: `{"currency":"USD","shares":[{"user_id":"user2","amount":99999999}]}`, wantStatus: http.StatusBadRequest},
+		{name: "split rolled back by unknown recipient", method: http.MethodPost, target: "/wallet/transfers/split", body: `{"currency":"USD","shares":[{"user_id":"user2","amount":100},{"user_id":"nobody","amount":100}]}`, wantStatus: http.StatusNotFound},
+		{name: "split invalid direction", method: http.MethodPost, target: "/wallet/transfers/split", body: `{"direction":"swap","currency":"USD","shares":[{"user_id":"user2","amount":100}]}`, wantStatus: http.StatusBadRequest, invalidRequest: true},
+		{name: "batch invalid mode", method: http.MethodPost, target: "/wallet/transfers/batch", body: `{"mode":"sometimes","transfers":[{"from_user_id":"user1","to_user_id":"user2","amount":100,"currency":"USD"}]}`, wantStatus: http.StatusBadRequest, invalidRequest: true},
+		{name: "balance", method: http.MethodGet, target: "/wallet/user1/balance", wantStatus: http.StatusOK},
+		{name: "balance with read-only key", method: http.MethodGet, target: "/wallet/user1/balance", as: "reader", wantStatus: http.StatusOK},
+		{name: "balance unknown wallet", method: http.MethodGet, target: "/wallet/nobody/balance", as: "nobody", wantStatus: http.StatusNotFound},
+		{name: "balance of another wallet", method: http.MethodGet, target: "/wallet/user2/balance", wantStatus: http.StatusForbidden},
+		{name: "balance with bearer token", method: http.MethodGet, target: "/wallet/user1/balance", as: "user1-jwt", wantStatus: http.StatusOK},
+		{name: "balance of another wallet with bearer token", method: http.MethodGet, target: "/wallet/user2/balance", as: "user1-jwt", wantStatus: http.StatusForbidden},
+		{name: "limits", method: http.MethodGet, target: "/wallet/user1/limits", wantStatus: http.StatusOK},
+		{name: "limits of another wallet", method: http.MethodGet, target: "/wallet/user2/limits", wantStatus: http.StatusForbidden},
+		{name: "limits of another wallet as admin", method: http.MethodGet, target: "/wallet/user2/limits", as: "admin-jwt", wantStatus: http.StatusOK},
+		{name: "reserves proof", method: http.MethodGet, target: "/wallet/user1/reserves-proof", wantStatus: http.StatusOK},
+		{name: "reserves proof of another wallet", method: http.MethodGet, target: "/wallet/user2/reserves-proof", wantStatus: http.StatusForbidden},
+		{name: "reserves proof without a wallet", method: http.MethodGet, target: "/wallet/nobody/reserves-proof", as: "nobody", wantStatus: http.StatusNotFound},
+		{name: "published liabilities", method: http.MethodGet, target: "/reserves", wantStatus: http.StatusOK},
+		{name: "balance of another wallet as admin", method: http.MethodGet, target: "/wallet/user2/balance", as: "admin-jwt", wantStatus: http.StatusOK},
+		{name: "balance with forged token", method: http.MethodGet, target: "/wallet/user2/balance", as: "forged-jwt", wantStatus: http.StatusUnauthorized},
+		{name: "balance without credentials", method: http.MethodGet, target: "/wallet/user1/balance", as: "anonymous", wantStatus: http.StatusUnauthorized},
+		{name: "balance as of a past date", method: http.MethodGet, target: "/wallet/user1/balance?as_of=2024-06-30", wantStatus: http.StatusOK},
+		{name: "balance as of a timestamp before any snapshot", method: http.MethodGet, target: "/wallet/user1/balance?as_of=2020-01-01T00:00:00Z", wantStatus: http.StatusOK},
+		{name: "balance as of an invalid time", method: http.MethodGet, target: "/wallet/user1/balance?as_of=yesterday", wantStatus: http.StatusBadRequest},
+		{name: "balance as of a future time", method: http.MethodGet, target: "/wallet/user1/balance?as_of=2999-01-01", wantStatus: http.StatusBadRequest},
+		{name: "balance as of for unknown wallet", method: http.MethodGet, target: "/wallet/nobody/balance?as_of=2024-06-30", as: "nobody", wantStatus: http.StatusNotFound},
+		{name: "transactions", method: http.MethodGet, target: "/wallet/user1/transactions?limit=5&offset=0", wantStatus: http.StatusOK},
+		{name: "transactions invalid limit", method: http.MethodGet, target: "/wallet/user1/transactions?limit=abc", wantStatus: http.StatusBadRequest, invalidRequest: true},
+		{name: "transactions of another wallet", method: http.MethodGet, target: "/wallet/user2/transactions", wantStatus: http.StatusForbidden},
+		{name: "statement csv", method: http.MethodGet, target: "/wallet/user1/statement?from=2000-01-01&format=csv", wantStatus: http.StatusOK},
+		{name: "statement jsonl", method: http.MethodGet, target: "/wallet/user1/statement?from=2000-01-01&format=jsonl", wantStatus: http.StatusOK},
+		{name: "statement html", method: http.MethodGet, target: "/wallet/user1/statement?from=2000-01-01&format=html", wantStatus: http.StatusOK},
+		{name: "statement invalid range", method: http.MethodGet, target: "/wallet/user1/statement?from=2001-01-01&to=2000-01-01", wantStatus: http.StatusBadRequest},
+		{name: "statement unknown wallet", method: http.MethodGet, target: "/wallet/nobody/statement?from=2000-01-01", as: "nobody", wantStatus: http.StatusNotFound},
+		{name: "statement of another wallet", method: http.MethodGet, target: "/wallet/user2/statement?from=2000-01-01", wantStatus: http.StatusForbidden},
+		{name: "get transaction", method: http.MethodGet, target: "/transactions/tx-transfer", as: "reader", wantStatus: http.StatusOK},
+		{name: "get transaction of other users", method: http.MethodGet, target: "/transactions/tx-user2", wantStatus: http.StatusForbidden},
+		{name: "get unknown transaction", method: http.MethodGet, target: "/transactions/missing", wantStatus: http.StatusNotFound},
+		{name: "refund part of transfer", method: http.MethodPost, target: "/transactions/tx-transfer/refund", body: `{"amount":400}`, wantStatus: http.StatusCreated},
+		{name: "refund more than is left", method: http.MethodPost, target: "/transactions/tx-transfer/refund", body: `{"amount":700}`, wantStatus: http.StatusBadRequest},
+		{name: "refund deposit", method: http.MethodPost, target: "/transactions/tx-deposit/refund", body: `{}`, wantStatus: http.StatusConflict},
+		{name: "refund with read-only key", method: http.MethodPost, target: "/transactions/tx-transfer/refund", body: `{}`, as: "reader", wantStatus: http.StatusForbidden},
+		{name: "cancel withdrawal with read-only key", method: http.MethodPost, target: "/transactions/tx-pending/cancel", as: "reader", wantStatus: http.StatusForbidden},
+		{name: "cancel withdrawal", method: http.MethodPost, target: "/transactions/tx-pending/cancel", wantStatus: http.StatusOK},
+		{name: "cancel cancelled withdrawal", method: http.MethodPost, target: "/transactions/tx-pending/cancel", wantStatus: http.StatusConflict},
+		{name: "cancel deposit", method: http.MethodPost, target: "/transactions/tx-deposit/cancel", wantStatus: http.StatusConflict},
+		{name: "cancel withdrawal of another user", method: http.MethodPost, target: "/transactions/tx-payout/cancel", wantStatus: http.StatusForbidden},
+		{name: "admin search pending transactions", method: http.MethodGet, target: "/admin/transactions?status=pending", as: "admin-jwt", wantStatus: http.StatusOK},
+		{name: "admin mark withdrawal processing", method: http.MethodPost, target: "/admin/transactions/tx-payout/status", body: `{"status":"processing"}`, as: "admin-jwt", wantStatus: http.StatusOK},
+		{name: "admin cancel processing withdrawal", method: http.MethodPost, target: "/admin/transactions/tx-payout/status", body: `{"status":"cancelled"}`, as: "admin-jwt", wantStatus: http.StatusConflict},
+		{name: "admin complete withdrawal", method: http.MethodPost, target: "/admin/transactions/tx-payout/status", body: `{"status":"completed"}`, as: "admin-jwt", wantStatus: http.StatusOK},
+		{name: "admin set invalid status", method: http.MethodPost, target: "/admin/transactions/tx-payout/status", body: `{"status":"settled"}`, as: "admin-jwt", wantStatus: http.StatusBadRequest, invalidRequest: true},
+		{name: "admin set status without admin role", method: http.MethodPost, target: "/admin/transactions/tx-payout/status", body: `{"status":"failed"}`, wantStatus: http.StatusForbidden},
+		{name: "admin approve withdrawal", method: http.MethodPost, target: "/admin/transactions/tx-approve/approve", body: `{}`, as: "admin-jwt", wantStatus: http.StatusOK},
+		{name: "admin approve completed withdrawal", method: http.MethodPost, target: "/admin/transactions/tx-approve/approve", body: `{}`, as: "admin-jwt", wantStatus: http.StatusConflict},
+		{name: "admin reject withdrawal without reason", method: http.MethodPost, target: "/admin/transactions/tx-reject/reject", body: `{}`, as: "admin-jwt", wantStatus: http.StatusBadRequest},
+		{name: "admin reject withdrawal", method: http.MethodPost, target: "/admin/transactions/tx-reject/reject", body: `{"reason":"unverified destination"}`, as: "admin-jwt", wantStatus: http.StatusOK},
+		{name: "admin approve withdrawal without admin role", method: http.MethodPost, target: "/admin/transactions/tx-reject/approve", body: `{}`, wantStatus: http.StatusForbidden},
+		{name: "admin reverse transaction", method: http.MethodPost, target: "/admin/transactions/tx-deposit/reverse", body: `{"amount":0}`, as: "admin-jwt", wantStatus: http.StatusCreated},
+		{name: "admin reverse reversed transaction", method: http.MethodPost, target: "/admin/transactions/tx-deposit/reverse", body: `{}`, as: "admin-jwt", wantStatus: http.StatusBadRequest},
+		{name: "admin reverse without admin role", method: http.MethodPost, target: "/admin/transactions/tx-deposit/reverse", body: `{}`, wantStatus: http.StatusForbidden},
+		{name: "admin credit applied immediately", method: http.MethodPost, target: "/admin/adjustments", body: `{"user_id":"user1","direction":"credit","amount":500,"currency":"USD","reason_code":"goodwill","note":"late payout"}`, as: "admin-jwt", wantStatus: http.StatusCreated},
+		{name: "admin debit held for approval", method: http.MethodPost, target: "/admin/adjustments", body: `{"user_id":"user1","direction":"debit","amount":5000,"currency":"USD","reason_code":"correction"}`, as: "admin-jwt", wantStatus: http.StatusCreated},
+		{name: "admin adjustment without reason", method: http.MethodPost, target: "/admin/adjustments", body: `{"user_id":"user1","direction":"credit","amount":500,"currency":"USD"}`, as: "admin-jwt", wantStatus: http.StatusBadRequest, invalidRequest: true},
+		{name: "admin adjustment of unknown wallet", method: http.MethodPost, target: "/admin/adjustments", body: `{"user_id":"nobody","direction":"credit","amount":500,"currency":"USD","reason_code":"goodwill"}`, as: "admin-jwt", wantStatus: http.StatusNotFound},
+		{name: "admin adjustment without admin role", method: http.MethodPost, target: "/admin/adjustments", body: `{"user_id":"user1","direction":"credit","amount":500,"currency":"USD","reason_code":"goodwill"}`, wantStatus: http.StatusForbidden},
+		{name: "admin list pending adjustments", method: http.MethodGet, target: "/admin/adjustments?status=pending", as: "admin-jwt", wantStatus: http.StatusOK},
+		{name: "admin get adjustment", method: http.MethodGet, target: "/admin/adjustments/adj-approve", as: "admin-jwt", wantStatus: http.StatusOK},
+		{name: "admin get unknown adjustment", method: http.MethodGet, target: "/admin/adjustments/missing", as: "admin-jwt", wantStatus: http.StatusNotFound},
+		{name: "admin approve own adjustment", method: http.MethodPost, target: "/admin/adjustments/adj-approve/approve", as: "admin-jwt", wantStatus: http.StatusForbidden},
+		{name: "admin approve adjustment", method: http.MethodPost, target: "/admin/adjustments/adj-approve/approve", as: "admin2-jwt", wantStatus: http.StatusOK},
+		{name: "admin approve decided adjustment", method: http.MethodPost, target: "/admin/adjustments/adj-approve/approve", as: "admin2-jwt", wantStatus: http.StatusConflict},
+		{name: "admin reject adjustment", method: http.MethodPost, target: "/admin/adjustments/adj-reject/reject", as: "admin2-jwt", wantStatus: http.StatusOK},
+		{name: "admin search wallets", method: http.MethodGet, target: "/admin/wallets?user_id_prefix=user&min_balance=0&currency=USD", as: "admin-jwt", wantStatus: http.StatusOK},
+		{name: "admin search wallets invalid balance", method: http.MethodGet, target: "/admin/wallets?min_balance=abc", as: "admin-jwt", wantStatus: http.StatusBadRequest, invalidRequest: true},
+		{name: "admin search transactions", method: http.MethodGet, target: "/admin/transactions?type=ADJUSTMENT&user_id=user1&from=2000-01-01", as: "admin-jwt", wantStatus: http.StatusOK},
+		{name: "admin search transactions invalid range", method: http.MethodGet, target: "/admin/transactions?min_amount=500&max_amount=100", as: "admin-jwt", wantStatus: http.StatusBadRequest},
+		{name: "admin list audit entries", method: http.MethodGet, target: "/admin/audit?action=adjustment.approve&user_id=user2", as: "admin-jwt", wantStatus: http.StatusOK},
+		{name: "admin list audit entries invalid action", method: http.MethodGet, target: "/admin/audit?action=wallet.delete", as: "admin-jwt", wantStatus: http.StatusBadRequest, invalidRequest: true},
+		{name: "admin list audit entries without admin role", method: http.MethodGet, target: "/admin/audit", wantStatus: http.StatusForbidden},
+		{name: "admin list blocking risk assessments", method: http.MethodGet, target: "/admin/risk/assessments?decision=block&user_id=user2", as: "admin-jwt", wantStatus: http.StatusOK},
+		{name: "admin list risk assessments invalid decision", method: http.MethodGet, target: "/admin/risk/assessments?decision=maybe", as: "admin-jwt", wantStatus: http.StatusBadRequest, invalidRequest: true},
+		{name: "admin list risk assessments without admin role", method: http.MethodGet, target: "/admin/risk/assessments", wantStatus: http.StatusForbidden},
+		{name: "admin verify audit log", method: http.MethodGet, target: "/admin/audit/verify", as: "admin-jwt", wantStatus: http.StatusOK},
+		{name: "admin deactivate user", method: http.MethodPost, target: "/admin/users/user2/status", body: `{"status":"deactivated"}`, as: "admin-jwt", wantStatus: http.StatusOK},
+		{name: "transfer to user deactivated by admin", method: http.MethodPost, target: "/wallet/transfer", body: `{"to_user_id":"user2","amount":1,"currency":"USD"}`, wantStatus: http.StatusConflict},
+		{name: "admin reactivate user", method: http.MethodPost, target: "/admin/users/user2/status", body: `{"status":"active"}`, as: "admin-jwt", wantStatus: http.StatusOK},
+		{name: "admin set invalid user status", method: http.MethodPost, target: "/admin/users/user2/status", body: `{"status":"banned"}`, as: "admin-jwt", wantStatus: http.StatusBadRequest, invalidRequest: true},
+		{name: "admin set status of unknown user", method: http.MethodPost, target: "/admin/users/missing/status", body: `{"status":"active"}`, as: "admin-jwt", wantStatus: http.StatusNotFound},
+		{name: "admin set user status without admin role", method: http.MethodPost, target: "/admin/users/user1/status", body: `{"status":"deactivated"}`, wantStatus: http.StatusForbidden},
+		{name: "register user", method: http.MethodPost, target: "/users", body: `{"name":"Nobody","email":"Nobody@Example.test"}`, as: "nobody", wantStatus: http.StatusCreated},
+		{name: "register registered user", method: http.MethodPost, target: "/users", body: `{"name":"User One","email":"one@example.test"}`, wantStatus: http.StatusConflict},
+		{name: "register user with taken email", method: http.MethodPost, target: "/users", body: `{"user_id":"user3","name":"User Three","email":"user1@example.test"}`, as: "admin-jwt", wantStatus: http.StatusConflict},
+		{name: "register user with invalid email", method: http.MethodPost, target: "/users", body: `{"user_id":"user3","name":"User Three","email":"user3"}`, as: "admin-jwt", wantStatus: http.StatusBadRequest},
+		{name: "register another user", method: http.MethodPost, target: "/users", body: `{"user_id":"user3","name":"User Three","email":"user3@example.test"}`, wantStatus: http.StatusForbidden},
+		{name: "get user", method: http.MethodGet, target: "/users/user1", as: "reader", wantStatus: http.StatusOK},
+		{name: "get another user", method: http.MethodGet, target: "/users/user2", wantStatus: http.StatusForbidden},
+		{name: "get unknown user as admin", method: http.MethodGet, target: "/users/missing", as: "admin-jwt", wantStatus: http.StatusNotFound},
+		{name: "update user", method: http.MethodPatch, target: "/users/user1", body: `{"name":"User One"}`, wantStatus: http.StatusOK},
+		{name: "update user with taken email", method: http.MethodPatch, target: "/users/user1", body: `{"email":"user2@example.test"}`, wantStatus: http.StatusConflict},
+		{name: "deposit above the KYC balance cap", method: http.MethodPost, target: "/wallet/deposit", body: `{"amount":600,"currency":"USD"}`, as: "newbie", wantStatus: http.StatusForbidden},
+		{name: "withdraw above the KYC cap", method: http.MethodPost, target: "/wallet/withdraw", body: `{"amount":300,"currency":"USD"}`, as: "newbie", wantStatus: http.StatusForbidden},
+		{name: "transfer to unverified user above the KYC threshold", method: http.MethodPost, target: "/wallet/transfer", body: `{"to_user_id":"newbie","amount":150,"currency":"USD"}`, wantStatus: http.StatusForbidden},
+		{name: "transfer to unverified user within the KYC threshold", method: http.MethodPost, target: "/wallet/transfer", body: `{"to_user_id":"newbie","amount":50,"currency":"USD"}`, wantStatus: http.StatusOK},
+		{name: "get KYC status", method: http.MethodGet, target: "/users/newbie/kyc", as: "newbie", wantStatus: http.StatusOK},
+		{name: "get KYC status of another user", method: http.MethodGet, target: "/users/newbie/kyc", wantStatus: http.StatusForbidden},
+		{name: "submit KYC document", method: http.MethodPost, target: "/users/newbie/kyc/submissions", body: `{"level":"full","document_type":"utility_bill","file_name":"bill.pdf","content_type":"application/pdf","content":"c2Nhbg=="}`, as: "newbie", wantStatus: http.StatusCreated},
+		{name: "submit KYC document without content", method: http.MethodPost, target: "/users/newbie/kyc/submissions", body: `{"level":"full","document_type":"utility_bill"}`, as: "newbie", wantStatus: http.StatusBadRequest, invalidRequest: true},
+		{name: "submit KYC document for an invalid level", method: http.MethodPost, target: "/users/newbie/kyc/submissions", body: `{"level":"gold","document_type":"passport","content":"c2Nhbg=="}`, as: "newbie", wantStatus: http.StatusBadRequest, invalidRequest: true},
+		{name: "admin list pending KYC submissions", method: http.MethodGet, target: "/admin/kyc/submissions?status=pending&user_id=newbie", as: "admin-jwt", wantStatus: http.StatusOK},
+		{name: "admin list KYC submissions invalid status", method: http.MethodGet, target: "/admin/kyc/submissions?status=lost", as: "admin-jwt", wantStatus: http.StatusBadRequest, invalidRequest: true},
+		{name: "admin download KYC document", method: http.MethodGet, target: "/admin/kyc/submissions/kyc-newbie/document", as: "admin-jwt", wantStatus: http.StatusOK},
+		{name: "admin download unknown KYC document", method: http.MethodGet, target: "/admin/kyc/submissions/missing/document", as: "admin-jwt", wantStatus: http.StatusNotFound},
+		{name: "admin approve KYC submission", method: http.MethodPost, target: "/admin/kyc/submissions/kyc-newbie/approve", body: `{}`, as: "admin-jwt", wantStatus: http.StatusOK},
+		{name: "admin approve reviewed KYC submission", method: http.MethodPost, target: "/admin/kyc/submissions/kyc-newbie/approve", body: `{}`, as: "admin-jwt", wantStatus: http.StatusConflict},
+		{name: "admin reject KYC submission without reason", method: http.MethodPost, target: "/admin/kyc/submissions/kyc-reject/reject", body: `{}`, as: "admin-jwt", wantStatus: http.StatusBadRequest},
+		{name: "admin reject KYC submission", method: http.MethodPost, target: "/admin/kyc/submissions/kyc-reject/reject", body: `{"reason":"blurry scan"}`, as: "admin-jwt", wantStatus: http.StatusOK},
+		{name: "admin approve KYC submission without admin role", method: http.MethodPost, target: "/admin/kyc/submissions/kyc-reject/approve", body: `{}`, wantStatus: http.StatusForbidden},
+		{name: "deposit after KYC approval", method: http.MethodPost, target: "/wallet/deposit", body: `{"amount":600,"currency":"USD"}`, as: "newbie", wantStatus: http.StatusOK},
+		{name: "admin set KYC level", method: http.MethodPost, target: "/admin/users/newbie/kyc", body: `{"level":"none","reason":"document expired"}`, as: "admin-jwt", wantStatus: http.StatusOK},
+		{name: "admin set KYC level of unknown user", method: http.MethodPost, target: "/admin/users/missing/kyc", body: `{"level":"basic","reason":"verified in branch"}`, as: "admin-jwt", wantStatus: http.StatusNotFound},
+		{name: "admin set KYC level without admin role", method: http.MethodPost, target: "/admin/users/newbie/kyc", body: `{"level":"full","reason":"trust me"}`, wantStatus: http.StatusForbidden},
+		{name: "transfer to sanctioned user", method: http.MethodPost, target: "/wallet/transfer", body: `{"to_user_id":"ivan","amount":50,"currency":"USD"}`, wantStatus: http.StatusForbidden},
+		{name: "withdraw to sanctioned destination", method: http.MethodPost, target: "/wallet/withdraw", body: `{"amount":100,"currency":"USD","destination":"0xABC123"}`, wantStatus: http.StatusForbidden},
+		{name: "request withdrawal to sanctioned destination", method: http.MethodPost, target: "/wallet/withdrawals", body: `{"amount":100,"currency":"USD","destination":"Acme Trading, LLC"}`, wantStatus: http.StatusForbidden},
+		{name: "withdraw to unlisted destination", method: http.MethodPost, target: "/wallet/withdraw", body: `{"amount":100,"currency":"USD","destination":"Jane Roe"}`, wantStatus: http.StatusOK},
+		{name: "admin list open sanctions cases", method: http.MethodGet, target: "/admin/sanctions/cases?status=open", as: "admin-jwt", wantStatus: http.StatusOK},
+		{name: "admin list sanctions cases invalid status", method: http.MethodGet, target: "/admin/sanctions/cases?status=lost", as: "admin-jwt", wantStatus: http.StatusBadRequest, invalidRequest: true},
+		{name: "admin clear sanctions case without reason", method: http.MethodPost, target: "/admin/sanctions/cases/case-ivan/clear", body: `{"reason":" "}`, as: "admin-jwt", wantStatus: http.StatusBadRequest},
+		{name: "admin clear sanctions case without admin role", method: http.MethodPost, target: "/admin/sanctions/cases/case-ivan/clear", body: `{"reason":"different date of birth"}`, wantStatus: http.StatusForbidden},
+		{name: "admin clear sanctions case", method: http.MethodPost, target: "/admin/sanctions/cases/case-ivan/clear", body: `{"reason":"different date of birth"}`, as: "admin-jwt", wantStatus: http.StatusOK},
+		{name: "admin clear cleared sanctions case", method: http.MethodPost, target: "/admin/sanctions/cases/case-ivan/clear", body: `{"reason":"different date of birth"}`, as: "admin-jwt", wantStatus: http.StatusConflict},
+		{name: "admin clear unknown sanctions case", method: http.MethodPost, target: "/admin/sanctions/cases/missing/clear", body: `{"reason":"different date of birth"}`, as: "admin-jwt", wantStatus: http.StatusNotFound},
+		{name: "transfer to user cleared by compliance", method: http.MethodPost, target: "/wallet/transfer", body: `{"to_user_id":"ivan","amount":50,"currency":"USD"}`, wantStatus: http.StatusOK},
+		{name: "admin end-of-day report", method: http.MethodGet, target: "/admin/reports/eod?date=2024-03-15", as: "admin-jwt", wantStatus: http.StatusOK},
+		{name: "admin end-of-day report without snapshots", method: http.MethodGet, target: "/admin/reports/eod?date=2024-03-20", as: "admin-jwt", wantStatus: http.StatusNotFound},
+		{name: "admin end-of-day report of an unfinished day", method: http.MethodGet, target: "/admin/reports/eod?date=2999-01-01", as: "admin-jwt", wantStatus: http.StatusBadRequest},
+		{name: "admin end-of-day report invalid date", method: http.MethodGet, target: "/admin/reports/eod?date=yesterday", as: "admin-jwt", wantStatus: http.StatusBadRequest, invalidRequest: true},
+		{name: "admin end-of-day report without admin role", method: http.MethodGet, target: "/admin/reports/eod?date=2024-03-15", wantStatus: http.StatusForbidden},
+		{name: "admin close month without admin role", method: http.MethodPost, target: "/admin/periods/2024-01/close", wantStatus: http.StatusForbidden},
+		{name: "admin close month", method: http.MethodPost, target: "/admin/periods/2024-01/close", as: "admin-jwt", wantStatus: http.StatusOK},
+		{name: "admin close closed month", method: http.MethodPost, target: "/admin/periods/2024-01/close", as: "admin-jwt", wantStatus: http.StatusConflict},
+		{name: "admin close unfinished month", method: http.MethodPost, target: "/admin/periods/2999-01/close", as: "admin-jwt", wantStatus: http.StatusBadRequest},
+		{name: "admin close invalid month", method: http.MethodPost, target: "/admin/periods/january/close", as: "admin-jwt", wantStatus: http.StatusBadRequest, invalidRequest: true},
+		{name: "create escrow", method: http.MethodPost, target: "/escrows", body: `{"payee_id":"user2","amount":500,"currency":"USD","conditions":"goods delivered","on_expiry":"refund","expires_at":"2999-01-01T00:00:00Z"}`, wantStatus: http.StatusCreated},
+		{name: "create escrow for oneself", method: http.MethodPost, target: "/escrows", body: `{"payee_id":"user1","amount":500,"currency":"USD","expires_at":"2999-01-01T00:00:00Z"}`, wantStatus: http.StatusBadRequest},
+		{name: "create escrow in another currency", method: http.MethodPost, target: "/escrows", body: `{"payee_id":"user2","amount":500,"currency":"EUR","expires_at":"2999-01-01T00:00:00Z"}`, wantStatus: http.StatusBadRequest},
+		{name: "create escrow already expired", method: http.MethodPost, target: "/escrows", body: `{"payee_id":"user2","amount":500,"currency":"USD","expires_at":"2020-01-01T00:00:00Z"}`, wantStatus: http.StatusBadRequest},
+		{name: "create escrow without funds", method: http.MethodPost, target: "/escrows", body: `{"payee_id":"user2","amount":99999999,"currency":"USD","expires_at":"2999-01-01T00:00:00Z"}`, wantStatus: http.StatusBadRequest},
+		{name: "create escrow from another wallet", method: http.MethodPost, target: "/escrows", body: `{"payer_id":"user2","payee_id":"user1","amount":500,"currency":"USD","expires_at":"2999-01-01T00:00:00Z"}`, wantStatus: http.StatusForbidden},
+		{name: "create escrow with read-only key", method: http.MethodPost, target: "/escrows", body: `{"payee_id":"user2","amount":500,"currency":"USD","expires_at":"2999-01-01T00:00:00Z"}`, as: "reader", wantStatus: http.StatusForbidden},
+		{name: "list escrows", method: http.MethodGet, target: "/escrows?status=funded", wantStatus: http.StatusOK},
+		{name: "list escrows of another user", method: http.MethodGet, target: "/escrows?user_id=user2", wantStatus: http.StatusForbidden},
+		{name: "list escrows invalid status", method: http.MethodGet, target: "/escrows?status=lost", wantStatus: http.StatusBadRequest, invalidRequest: true},
+		{name: "get escrow", method: http.MethodGet, target: "/escrows/esc-release", as: "reader", wantStatus: http.StatusOK},
+		{name: "get escrow of other users", method: http.MethodGet, target: "/escrows/esc-release", as: "nobody", wantStatus: http.StatusForbidden},
+		{name: "get unknown escrow", method: http.MethodGet, target: "/escrows/missing", wantStatus: http.StatusNotFound},
+		{name: "refund escrow as its payer", method: http.MethodPost, target: "/escrows/esc-release/refund", wantStatus: http.StatusForbidden},
+		{name: "release escrow", method: http.MethodPost, target: "/escrows/esc-release/release", wantStatus: http.StatusOK},
+		{name: "release released escrow", method: http.MethodPost, target: "/escrows/esc-release/release", wantStatus: http.StatusConflict},
+		{name: "refund escrow", method: http.MethodPost, target: "/escrows/esc-refund/refund", wantStatus: http.StatusOK},
+		{name: "dispute escrow without reason", method: http.MethodPost, target: "/escrows/esc-dispute/dispute", body: `{"reason":""}`, wantStatus: http.StatusBadRequest},
+		{name: "dispute escrow", method: http.MethodPost, target: "/escrows/esc-dispute/dispute", body: `{"reason":"goods not delivered"}`, wantStatus: http.StatusOK},
+		{name: "dispute disputed escrow", method: http.MethodPost, target: "/escrows/esc-dispute/dispute", body: `{"reason":"goods not delivered"}`, wantStatus: http.StatusConflict},
+		{name: "release disputed escrow", method: http.MethodPost, target: "/escrows/esc-dispute/release", wantStatus: http.StatusConflict},
+		{name: "admin resolve escrow without admin role", method: http.MethodPost, target: "/admin/escrows/esc-resolve/resolve", body: `{"outcome":"release"}`, wantStatus: http.StatusForbidden},
+		{name: "admin resolve escrow invalid outcome", method: http.MethodPost, target: "/admin/escrows/esc-resolve/resolve", body: `{"outcome":"split"}`, as: "admin-jwt", wantStatus: http.StatusBadRequest, invalidRequest: true},
+		{name: "admin resolve escrow", method: http.MethodPost, target: "/admin/escrows/esc-resolve/resolve", body: `{"outcome":"release"}`, as: "admin-jwt", wantStatus: http.StatusOK},
+		{name: "admin resolve settled escrow", method: http.MethodPost, target: "/admin/escrows/esc-resolve/resolve", body: `{"outcome":"refund"}`, as: "admin-jwt", wantStatus: http.StatusConflict},
+		{name: "admin resolve unknown escrow", method: http.MethodPost, target: "/admin/escrows/missing/resolve", body: `{"outcome":"refund"}`, as: "admin-jwt", wantStatus: http.StatusNotFound},
+		{name: "admin accrue interest without admin role", method: http.MethodPost, target: "/admin/interest/accruals?date=2024-03-20", wantStatus: http.StatusForbidden},
+		{name: "admin accrue interest invalid date", method: http.MethodPost, target: "/admin/interest/accruals?date=yesterday", as: "admin-jwt", wantStatus: http.StatusBadRequest, invalidRequest: true},
+		{name: "admin accrue interest for unfinished day", method: http.MethodPost, target: "/admin/interest/accruals?date=2999-01-01", as: "admin-jwt", wantStatus: http.StatusBadRequest},
+		{name: "admin accrue interest", method: http.MethodPost, target: "/admin/interest/accruals?date=2024-03-20", as: "admin-jwt", wantStatus: http.StatusOK},
+		{name: "admin pay interest invalid month", method: http.MethodPost, target: "/admin/interest/payouts?month=2024-3", as: "admin-jwt", wantStatus: http.StatusBadRequest, invalidRequest: true},
+		{name: "admin pay interest for unfinished month", method: http.MethodPost, target: "/admin/interest/payouts?month=2999-01", as: "admin-jwt", wantStatus: http.StatusBadRequest},
+		{name: "admin pay interest", method: http.MethodPost, target: "/admin/interest/payouts?month=2024-02", as: "admin-jwt", wantStatus: http.StatusOK},
+		{name: "admin pay interest again", method: http.MethodPost, target: "/admin/interest/payouts?month=2024-02", as: "admin-jwt", wantStatus: http.StatusOK},
+		{name: "list interest payouts", method: http.MethodGet, target: "/interest/payouts", as: "reader", wantStatus: http.StatusOK},
+		{name: "list interest payouts of another user", method: http.MethodGet, target: "/interest/payouts?user_id=user2", wantStatus: http.StatusForbidden},
+		{name: "create payment request", method: http.MethodPost, target: "/payment-requests", body: `{"payer_id":"user2","amount":500,"currency":"USD","memo":"dinner","expires_at":"2999-01-01T00:00:00Z"}`, wantStatus: http.StatusCreated},
+		{name: "create open payment request", method: http.MethodPost, target: "/payment-requests", body: `{"amount":500,"currency":"USD","expires_at":"2999-01-01T00:00:00Z"}`, wantStatus: http.StatusCreated},
+		{name: "create payment request from oneself", method: http.MethodPost, target: "/payment-requests", body: `{"payer_id":"user1","amount":500,"currency":"USD","expires_at":"2999-01-01T00:00:00Z"}`, wantStatus: http.StatusBadRequest},
+		{name: "create payment request in another currency", method: http.MethodPost, target: "/payment-requests", body: `{"payer_id":"user2","amount":500,"currency":"EUR","expires_at":"2999-01-01T00:00:00Z"}`, wantStatus: http.StatusBadRequest},
+		{name: "create payment request already expired", method: http.MethodPost, target: "/payment-requests", body: `{"payer_id":"user2","amount":500,"currency":"USD","expires_at":"2020-01-01T00:00:00Z"}`, wantStatus: http.StatusBadRequest},
+		{name: "create payment request for another user", method: http.MethodPost, target: "/payment-requests", body: `{"requester_id":"user2","payer_id":"user1","amount":500,"currency":"USD","expires_at":"2999-01-01T00:00:00Z"}`, wantStatus: http.StatusForbidden},
+		{name: "create payment request with read-only key", method: http.MethodPost, target: "/payment-requests", body: `{"payer_id":"user2","amount":500,"currency":"USD","expires_at":"2999-01-01T00:00:00Z"}`, as: "reader", wantStatus: http.StatusForbidden},
+		{name: "list payment requests", method: http.MethodGet, target: "/payment-requests?status=pending", as: "reader", wantStatus: http.StatusOK},
+		{name: "list payment requests of another user", method: http.MethodGet, target: "/payment-requests?user_id=user2", wantStatus: http.StatusForbidden},
+		{name: "list payment requests invalid status", method: http.MethodGet, target: "/payment-requests?status=lost", wantStatus: http.StatusBadRequest, invalidRequest: true},
+		{name: "get payment request", method: http.MethodGet, target: "/payment-requests/pr-accept", as: "reader", wantStatus: http.StatusOK},
+		{name: "get payment request of other users", method: http.MethodGet, target: "/payment-requests/pr-accept", as: "nobody", wantStatus: http.StatusForbidden},
+		{name: "get unknown payment request", method: http.MethodGet, target: "/payment-requests/missing", wantStatus: http.StatusNotFound},
+		{name: "preview payment request", method: http.MethodGet, target: "/pay/tok-pr-accept", as: "anonymous", wantStatus: http.StatusOK},
+		{name: "preview unknown payment request", method: http.MethodGet, target: "/pay/unknown", as: "anonymous", wantStatus: http.StatusNotFound},
+		{name: "accept payment request with read-only key", method: http.MethodPost, target: "/payment-requests/pr-accept/accept", as: "reader", wantStatus: http.StatusForbidden},
+		{name: "accept payment request", method: http.MethodPost, target: "/payment-requests/pr-accept/accept", wantStatus: http.StatusOK},
+		{name: "accept paid payment request", method: http.MethodPost, target: "/payment-requests/pr-accept/accept", wantStatus: http.StatusConflict},
+		{name: "accept own payment request", method: http.MethodPost, target: "/payment-requests/pr-cancel/accept", wantStatus: http.StatusBadRequest},
+		{name: "accept open payment request", method: http.MethodPost, target: "/payment-requests/pr-open/accept", wantStatus: http.StatusOK},
+		{name: "cancel payment request as its payer", method: http.MethodPost, target: "/payment-requests/pr-decline/cancel", wantStatus: http.StatusForbidden},
+		{name: "decline payment request", method: http.MethodPost, target: "/payment-requests/pr-decline/decline", wantStatus: http.StatusOK},
+		{name: "decline payment request as its requester", method: http.MethodPost, target: "/payment-requests/pr-cancel/decline", wantStatus: http.StatusForbidden},
+		{name: "cancel payment request", method: http.MethodPost, target: "/payment-requests/pr-cancel/cancel", wantStatus: http.StatusOK},
+		{name: "cancel cancelled payment request", method: http.MethodPost, target: "/payment-requests/pr-cancel/cancel", wantStatus: http.StatusConflict},
+		{name: "admin list closed months", method: http.MethodGet, target: "/admin/periods", as: "admin-jwt", wantStatus: http.StatusOK},
+		{name: "admin adjustment back-dated into closed month", method: http.MethodPost, target: "/admin/adjustments", body: `{"user_id":"user1","direction":"credit","amount":100,"currency":"USD","reason_code":"correction","effective_at":"2024-01-15"}`, as: "admin-jwt", wantStatus: http.StatusConflict},
+		{name: "admin adjustment back-dated into open month", method: http.MethodPost, target: "/admin/adjustments", body: `{"user_id":"user1","direction":"credit","amount":100,"currency":"USD","reason_code":"correction","effective_at":"2024-02-15T10:00:00Z"}`, as: "admin-jwt", wantStatus: http.StatusCreated},
+		{name: "admin adjustment dated in the future", method: http.MethodPost, target: "/admin/adjustments", body: `{"user_id":"user1","direction":"credit","amount":100,"currency":"USD","reason_code":"correction","effective_at":"2999-01-01"}`, as: "admin-jwt", wantStatus: http.StatusBadRequest},
+		{name: "admin adjustment invalid effective date", method: http.MethodPost, target: "/admin/adjustments", body: `{"user_id":"user1","direction":"credit","amount":100,"currency":"USD","reason_code":"correction","effective_at":"soon"}`, as: "admin-jwt", wantStatus: http.StatusBadRequest},
+		{name: "create webhook subscription", method: http.MethodPost, target: "/webhooks/subscriptions", body: `{"url":"https://partner.test/hooks","event_types":["FundsDeposited","FundsTransferred"]}`, wantStatus: http.StatusCreated},
+		{name: "create webhook subscription with read-only key", method: http.MethodPost, target: "/webhooks/subscriptions", body: `{"url":"https://partner.test/hooks","event_types":["FundsDeposited"]}`, as: "reader", wantStatus: http.StatusCreated},
+		{name: "create webhook subscription with invalid url", method: http.MethodPost, target: "/webhooks/subscriptions", body: `{"url":"ftp://partner.test","event_types":["FundsDeposited"]}`, wantStatus: http.StatusBadRequest},
+		{name: "create webhook subscription for another user", method: http.MethodPost, target: "/webhooks/subscriptions", body: `{"user_id":"user2","url":"https://partner.test/hooks","event_types":["FundsDeposited"]}`, wantStatus: http.StatusForbidden},
+		{name: "list webhook subscriptions", method: http.MethodGet, target: "/webhooks/subscriptions", wantStatus: http.StatusOK},
+		{name: "list webhook subscriptions of another user as admin", method: http.MethodGet, target: "/webhooks/subscriptions?user_id=user2", as: "admin-jwt", wantStatus: http.StatusOK},
+		{name: "delete webhook subscription of another user", method: http.MethodDelete, target: "/webhooks/subscriptions/wh-user2", wantStatus: http.StatusNotFound},
+		{name: "list webhook deliveries", method: http.MethodGet, target: "/webhooks/deliveries?status=dead&limit=5", wantStatus: http.StatusOK},
+		{name: "list webhook deliveries invalid status", method: http.MethodGet, target: "/webhooks/deliveries?status=lost", wantStatus: http.StatusBadRequest, invalidRequest: true},
+		{name: "list webhook attempts", method: http.MethodGet, target: "/webhooks/deliveries/whd-dead/attempts", wantStatus: http.StatusOK},
+		{name: "list webhook attempts of another user", method: http.MethodGet, target: "/webhooks/deliveries/whd-user2/attempts", wantStatus: http.StatusNotFound},
+		{name: "replay webhook delivery", method: http.MethodPost, target: "/webhooks/deliveries/whd-dead/replay", wantStatus: http.StatusAccepted},
+		{name: "replay pending webhook delivery", method: http.MethodPost, target: "/webhooks/deliveries/whd-pending/replay", wantStatus: http.StatusConflict},
+		{name: "replay webhook delivery of another user", method: http.MethodPost, target: "/webhooks/deliveries/whd-user2/replay", wantStatus: http.StatusNotFound},
+		{name: "delete webhook subscription", method: http.MethodDelete, target: "/webhooks/subscriptions/wh-user1", wantStatus: http.StatusNoContent},
+		{name: "openapi document", method: http.MethodGet, target: "/openapi.json", as: "anonymous", wantStatus: http.StatusOK},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			ctx := context.Background()
+			newRequest := func() *http.Request {
+				var body io.Reader
+				if tt.body != "" {
+					body = strings.NewReader(tt.body)
+				}
+				req := httptest.NewRequest(tt.method, tt.target, body)
+				if tt.body != "" {
+					req.Header.Set("Content-Type", "application/json")
+				}
+				switch tt.as {
+				case "anonymous":
+				case "":
+					credentials["user1"].sign(req, tt.body)
+				default:
+					credentials[tt.as].sign(req, tt.body)
+				}
+				return req
+			}
+
+			req := newRequest()
+			route, pathParams, err := specRouter.FindRoute(req)
+			require.NoError(t, err, "route is not documented in openapi.json")
+
+			requestInput := &openapi3filter.RequestValidationInput{
+				Request:    req,
+				PathParams: pathParams,
+				Route:      route,
+				Options: &openapi3filter.Options{
+					IncludeResponseStatus: true,
+					// Signatures are verified by the handler itself.
+					AuthenticationFunc: openapi3filter.NoopAuthenticationFunc,
+				},
+			}
+			err = openapi3filter.ValidateRequest(ctx, requestInput)
+			if tt.invalidRequest {
+				require.Error(t, err, "request was expected to violate the spec")
+			} else {
+				require.NoError(t, err)
+			}
+
+			rec := httptest.NewRecorder()
+			handler.ServeHTTP(rec, newRequest())
+			require.Equal(t, tt.wantStatus, rec.Code, rec.Body.String())
+
+			err = openapi3filter.ValidateResponse(ctx, &openapi3filter.ResponseValidationInput{
+				RequestValidationInput: requestInput,
+				Status:                 rec.Code,
+				Header:                 rec.Header(),
+				Body:                   io.NopCloser(bytes.NewReader(rec.Body.Bytes())),
+				Options:                &openapi3filter.Options{IncludeResponseStatus: true},
+			})
+			require.NoError(t, err)
+
+			covered[tt.method+" "+route.Path] = true
+		})
+	}
+
+	for path, item := range doc.Paths.Map() {
+		for method := range item.Operations() {
+			require.True(t, covered[method+" "+path], "%s %s is documented but not exercised", method, path)
+		}
+	}
+}
+
+func TestRequireAuthentication_RejectsReplayedAndTamperedRequests(t *testing.T) {
+	handler, credentials := newTestHandler(t)
+	body := `{"amount":100,"currency":"USD"}`
+
+	req := httptest.NewRequest(http.MethodPost, "/wallet/deposit", strings.NewReader(body))
+	credentials["user1"].sign(req, body)
+	replay := req.Clone(context.Background())
+
+	rec := httptest.NewRecorder()
+	handler.ServeHTTP(rec, req)
+	require.Equal(t, http.StatusOK, rec.Code, rec.Body.String())
+
+	replay.Body = io.NopCloser(strings.NewReader(body))
+	rec = httptest.NewRecorder()
+	handler.ServeHTTP(rec, replay)
+	require.Equal(t, http.StatusUnauthorized, rec.Code, "a nonce must not be accepted twice")
+
+	tampered := httptest.NewRequest(http.MethodPost, "/wallet/deposit", strings.NewReader(`{"amount":999999,"currency":"USD"}`))
+	credentials["user1"].sign(tampered, body)
+	rec = httptest.NewRecorder()
+	handler.ServeHTTP(rec, tampered)
+	require.Equal(t, http.StatusUnauthorized, rec.Code, "the signature must cover the body")
+}
+
+func TestHandler_AuditsMutations(t *testing.T) {
+	handler, credentials := newTestHandler(t)
+	do := func(as, method, target, body, requestID string) *httptest.ResponseRecorder {
+		req := httptest.NewRequest(method, target, strings.NewReader(body))
+		if requestID != "" {
+			req.Header.Set(HeaderRequestID, requestID)
+		}
+		credentials[as].sign(req, body)
+		rec := httptest.NewRecorder()
+		handler.ServeHTTP(rec, req)
+		return rec
+	}
+
+	rec := do("user1", http.MethodPost, "/wallet/transfer", `{"to_user_id":"user2","amount":300,"currency":"USD"}`, "req-transfer")
+	require.Equal(t, http.StatusOK, rec.Code, rec.Body.String())
+	assert.Equal(t, "req-transfer", rec.Header().Get(HeaderRequestID))
+
+	rec = do("user1", http.MethodPost, "/wallet/withdraw", `{"amount":99999999,"currency":"USD"}`, "")
+	require.Equal(t, http.StatusBadRequest, rec.Code, rec.Body.String())
+	failedRequestID := rec.Header().Get(HeaderRequestID)
+	assert.NotEmpty(t, failedRequestID, "a request ID is generated when the client sends none")
+
+	var entries []AuditEntryResponse
+	rec = do("admin-jwt", http.MethodGet, "/admin/audit?request_id=req-transfer", "", "")
+	require.Equal(t, http.StatusOK, rec.Code, rec.Body.String())
+	require.NoError(t, json.Unmarshal(rec.Body.Bytes(), &entries))
+	require.Len(t, entries, 1)
+	transfer := entries[0]
+	assert.Equal(t, "wallet.transfer", transfer.Action)
+	assert.Equal(t, "success", transfer.Outcome)
+	assert.Equal(t, "user1", transfer.Actor)
+	assert.Equal(t, "192.0.2.1", transfer.SourceIP)
+	assert.NotEmpty(t, transfer.TransactionID)
+	require.Len(t, transfer.Balances, 2)
+	assert.Equal(t, int64(10000), *transfer.Balances[0].Before)
+	assert.Equal(t, int64(9700), *transfer.Balances[0].After)
+	assert.Equal(t, int64(20300), *transfer.Balances[1].After)
+
+	rec = do("admin-jwt", http.MethodGet, "/admin/audit?request_id="+failedRequestID, "", "")
+	require.NoError(t, json.Unmarshal(rec.Body.Bytes(), &entries))
+	require.Len(t, entries, 1)
+	assert.Equal(t, "failure", entries[0].Outcome)
+	assert.Equal(t, "insufficient funds", entries[0].Error)
+	assert.Equal(t, *entries[0].Balances[0].Before, *entries[0].Balances[0].After)
+
+	var report AuditChainResponse
+	rec = do("admin-jwt", http.MethodGet, "/admin/audit/verify", "", "")
+	require.NoError(t, json.Unmarshal(rec.Body.Bytes(), &report))
+	assert.True(t, report.Valid)
+	assert.Equal(t, int64(2), report.Entries)
+}
+
+func TestHandler_BalanceAsOfCountsTransactionsWhenCompleted(t *testing.T) {
+	handler, credentials := newTestHandler(t)
+	req := httptest.NewRequest(http.MethodGet, "/wallet/user2/balance?as_of=2024-06-30T00:00:00Z", nil)
+	credentials["admin-jwt"].sign(req, "")
+	rec := httptest.NewRecorder()
+	handler.ServeHTTP(rec, req)
+	require.Equal(t, http.StatusOK, rec.Code, rec.Body.String())
+
+	var balance BalanceResponse
+	require.NoError(t, json.Unmarshal(rec.Body.Bytes(), &balance))
+	assert.Equal(t, int64(20500), balance.Balance, "tx-settled was created before the cutoff but completed after it")
+}
+
+func TestHandler_WebhookSubscriptionSecret(t *testing.T) {
+	handler, credentials := newTestHandler(t)
+	do := func(method, target, body string) *httptest.ResponseRecorder {
+		req := httptest.NewRequest(method, target, strings.NewReader(body))
+		credentials["user1"].sign(req, body)
+		rec := httptest.NewRecorder()
+		handler.ServeHTTP(rec, req)
+		return rec
+	}
+
+	rec := do(http.MethodPost, "/webhooks/subscriptions", `{"url":"https://partner.test/new","event_types":["FundsWithdrawn"]}`)
+	require.Equal(t, http.StatusCreated, rec.Code, rec.Body.String())
+	var created WebhookSubscriptionResponse
+	require.NoError(t, json.Unmarshal(rec.Body.Bytes(), &created))
+	assert.Equal(t, "user1", created.UserID)
+	assert.True(t, strings.HasPrefix(created.Secret, "whsec_"))
+
+	rec = do(http.MethodGet, "/webhooks/subscriptions", "")
+	require.Equal(t, http.StatusOK, rec.Code, rec.Body.String())
+	var subs []WebhookSubscriptionResponse
+	require.NoError(t, json.Unmarshal(rec.Body.Bytes(), &subs))
+	require.Len(t, subs, 2)
+	assert.Equal(t, created.ID, subs[1].ID)
+	for _, sub := range subs {
+		assert.Empty(t, sub.Secret, "secrets are only returned on creation")
+	}
+}
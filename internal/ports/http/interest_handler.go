@@ -0,0 +1,111 @@
+package http
+
+import (
+	"net/http"
+	"time"
+
+	"exchange/internal/domain/auth"
+	"exchange/internal/usecase"
+)
+
+// InterestHandler serves users' interest payouts under /interest and lets admins rerun
+// the accrual of a day or the payout of a month under /admin/interest. Reruns only fill
+// in what is missing, so they never accrue or pay twice.
+type InterestHandler struct {
+	InterestUC *usecase.InterestUseCase
+}
+
+func NewInterestHandler(interestUC *usecase.InterestUseCase) *InterestHandler {
+	return &InterestHandler{
+		InterestUC: interestUC,
+	}
+}
+
+func (h *InterestHandler) RegisterRoutes(mux *http.ServeMux) {
+	mux.HandleFunc("/interest/payouts", h.listPayoutsHandler)
+	mux.HandleFunc("/admin/interest/accruals", requireRole(auth.RoleAdmin, h.accrueHandler))
+	mux.HandleFunc("/admin/interest/payouts", requireRole(auth.RoleAdmin, h.payHandler))
+}
+
+func (h *InterestHandler) listPayoutsHandler(w http.ResponseWriter, r *http.Request) {
+	// GET /interest/payouts?user_id=&limit=10&offset=0
+	if r.Method != http.MethodGet {
+		http.Error(w, "method not allowed", http.StatusMethodNotAllowed)
+		return
+	}
+
+	query := r.URL.Query()
+	limit, offset, err := parsePagination(query)
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+
+	userID, err := actingUserID(r, query.Get("user_id"), auth.PermissionRead)
+	if err != nil {
+		handleError(w, err)
+		return
+	}
+
+	payouts, err := h.InterestUC.ListPayouts(r.Context(), userID, limit, offset)
+	if err != nil {
+		handleError(w, err)
+		return
+	}
+
+	resp := make([]InterestPayoutResponse, 0, len(payouts))
+	for _, p := range payouts {
+		resp = append(resp, newInterestPayoutResponse(p))
+	}
+	writeJSON(w, resp)
+}
+
+func (h *InterestHandler) accrueHandler(w http.ResponseWriter, r *http.Request) {
+	// POST /admin/interest/accruals?date=2024-03-15
+	if r.Method != http.MethodPost {
+		http.Error(w, "method not allowed", http.StatusMethodNotAllowed)
+		return
+	}
+
+	day, err := time.Parse("2006-01-02", r.URL.Query().Get("date"))
+	if err != nil {
+		http.Error(w, "invalid date value, expected YYYY-MM-DD", http.StatusBadRequest)
+		return
+	}
+
+	accrued, err := h.InterestUC.AccrueInterest(r.Context(), day)
+	if err != nil {
+		handleError(w, err)
+		return
+	}
+	writeJSON(w, InterestAccrualRunResponse{
+		Date:    day.Format("2006-01-02"),
+		Accrued: accrued,
+	})
+}
+
+func (h *InterestHandler) payHandler(w http.ResponseWriter, r *http.Request) {
+	// POST /admin/interest/payouts?month=2024-03
+	if r.Method != http.MethodPost {
+		http.Error(w, "method not allowed", http.StatusMethodNotAllowed)
+		return
+	}
+
+	month, err := time.Parse("2006-01", r.URL.Query().Get("month"))
+	if err != nil {
+		http.Error(w, "invalid month value, expected YYYY-MM", http.StatusBadRequest)
+		return
+	}
+
+	payouts, err := h.InterestUC.PayInterest(r.Context(), month)
+	if err != nil {
+		handleError(w, err)
+		return
+	}
+
+	resp := make([]InterestPayoutResponse, 0, len(payouts))
+	for _, p := range payouts {
+		resp = append(resp, newInterestPayoutResponse(p))
+	}
+	writeJSON(w, resp)
+}
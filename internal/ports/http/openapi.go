@@ -0,0 +1,22 @@
+package http
+
+import (
+	_ "embed"
+	"net/http"
+)
+
+// openAPISpec documents every route registered by Handler. handler_test.go validates
+// the handlers against it, so it must be updated together with routes and DTOs.
+//
+//go:embed openapi.json
+var openAPISpec []byte
+
+func (h *Handler) openAPIHandler(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodGet {
+		http.Error(w, "method not allowed", http.StatusMethodNotAllowed)
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json; charset=utf-8")
+	w.Write(openAPISpec)
+}
@@ -0,0 +1,185 @@
+package http
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"strings"
+
+	"exchange/internal/domain/auth"
+	"exchange/internal/domain/payment"
+	"exchange/internal/usecase"
+)
+
+// PaymentRequestHandler serves payment requests under /payment-requests and the public
+// previews of their shareable links under /pay. The payer accepts or declines a request
+// and the requester cancels it; anyone but the requester may accept an open request.
+type PaymentRequestHandler struct {
+	PaymentRequestUC *usecase.PaymentRequestUseCase
+}
+
+func NewPaymentRequestHandler(paymentRequestUC *usecase.PaymentRequestUseCase) *PaymentRequestHandler {
+	return &PaymentRequestHandler{
+		PaymentRequestUC: paymentRequestUC,
+	}
+}
+
+func (h *PaymentRequestHandler) RegisterRoutes(mux *http.ServeMux) {
+	mux.HandleFunc("/payment-requests", h.paymentRequestsHandler)
+	mux.HandleFunc("/payment-requests/", h.paymentRequestHandler)
+	mux.HandleFunc("/pay/", h.previewHandler)
+}
+
+func (h *PaymentRequestHandler) paymentRequestsHandler(w http.ResponseWriter, r *http.Request) {
+	// GET  /payment-requests?user_id=&status=pending&limit=10&offset=0
+	// POST /payment-requests
+	switch r.Method {
+	case http.MethodGet:
+		h.listPaymentRequestsHandler(w, r)
+	case http.MethodPost:
+		h.createPaymentRequestHandler(w, r)
+	default:
+		http.Error(w, "method not allowed", http.StatusMethodNotAllowed)
+	}
+}
+
+func (h *PaymentRequestHandler) createPaymentRequestHandler(w http.ResponseWriter, r *http.Request) {
+	var req PaymentRequestRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		http.Error(w, "invalid request body", http.StatusBadRequest)
+		return
+	}
+
+	requesterID, err := actingUserID(r, req.RequesterID, auth.PermissionTrade)
+	if err != nil {
+		handleError(w, err)
+		return
+	}
+
+	ctx := r.Context()
+	p, err := h.PaymentRequestUC.RequestPayment(ctx, usecase.PaymentRequest{
+		RequesterID: requesterID,
+		PayerID:     req.PayerID,
+		Amount:      req.Amount,
+		Currency:    req.Currency,
+		Memo:        req.Memo,
+		ExpiresAt:   req.ExpiresAt,
+	})
+	if err != nil {
+		handleError(w, err)
+		return
+	}
+	writeJSONStatus(w, http.StatusCreated, newPaymentRequestResponse(p))
+}
+
+func (h *PaymentRequestHandler) listPaymentRequestsHandler(w http.ResponseWriter, r *http.Request) {
+	query := r.URL.Query()
+	limit, offset, err := parsePagination(query)
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+
+	userID, err := actingUserID(r, query.Get("user_id"), auth.PermissionRead)
+	if err != nil {
+		handleError(w, err)
+		return
+	}
+
+	ctx := r.Context()
+	requests, err := h.PaymentRequestUC.ListPaymentRequests(ctx, payment.Filter{
+		UserID: userID,
+		Status: payment.Status(query.Get("status")),
+	}, limit, offset)
+	if err != nil {
+		handleError(w, err)
+		return
+	}
+
+	resp := make([]PaymentRequestResponse, 0, len(requests))
+	for _, p := range requests {
+		resp = append(resp, newPaymentRequestResponse(p))
+	}
+	writeJSON(w, resp)
+}
+
+func (h *PaymentRequestHandler) paymentRequestHandler(w http.ResponseWriter, r *http.Request) {
+	// GET  /payment-requests/{id}
+	// POST /payment-requests/{id}/accept
+	// POST /payment-requests/{id}/decline
+	// POST /payment-requests/{id}/cancel
+	segments := strings.Split(strings.TrimPrefix(r.URL.Path, "/payment-requests/"), "/")
+	if len(segments) > 2 || segments[0] == "" {
+		http.Error(w, "not found", http.StatusNotFound)
+		return
+	}
+	id := segments[0]
+
+	ctx := r.Context()
+	if len(segments) == 1 {
+		if r.Method != http.MethodGet {
+			http.Error(w, "method not allowed", http.StatusMethodNotAllowed)
+			return
+		}
+		p, err := h.PaymentRequestUC.GetPaymentRequest(ctx, id)
+		if err != nil {
+			handleError(w, err)
+			return
+		}
+		if err := authorizeParty(r, auth.PermissionRead, p.RequesterID, p.PayerID, p.PaidBy); err != nil {
+			handleError(w, err)
+			return
+		}
+		writeJSON(w, newPaymentRequestResponse(p))
+		return
+	}
+
+	if r.Method != http.MethodPost {
+		http.Error(w, "method not allowed", http.StatusMethodNotAllowed)
+		return
+	}
+	var act func(ctx context.Context, id, userID string) (payment.Request, error)
+	switch segments[1] {
+	case "accept":
+		act = h.PaymentRequestUC.AcceptPaymentRequest
+	case "decline":
+		act = h.PaymentRequestUC.DeclinePaymentRequest
+	case "cancel":
+		act = h.PaymentRequestUC.CancelPaymentRequest
+	default:
+		http.Error(w, "not found", http.StatusNotFound)
+		return
+	}
+
+	userID, err := actingUserID(r, "", auth.PermissionTrade)
+	if err != nil {
+		handleError(w, err)
+		return
+	}
+	p, err := act(ctx, id, userID)
+	if err != nil {
+		handleError(w, err)
+		return
+	}
+	writeJSON(w, newPaymentRequestResponse(p))
+}
+
+func (h *PaymentRequestHandler) previewHandler(w http.ResponseWriter, r *http.Request) {
+	// GET /pay/{token}
+	token := strings.TrimPrefix(r.URL.Path, "/pay/")
+	if token == "" || strings.Contains(token, "/") {
+		http.Error(w, "not found", http.StatusNotFound)
+		return
+	}
+	if r.Method != http.MethodGet {
+		http.Error(w, "method not allowed", http.StatusMethodNotAllowed)
+		return
+	}
+
+	p, err := h.PaymentRequestUC.PreviewPaymentRequest(r.Context(), token)
+	if err != nil {
+		handleError(w, err)
+		return
+	}
+	writeJSON(w, newPaymentRequestPreviewResponse(p))
+}
@@ -0,0 +1,47 @@
+package http
+
+import (
+	"net"
+	"net/http"
+
+	"exchange/internal/domain/audit"
+	"exchange/internal/domain/auth"
+
+	"github.com/gofrs/uuid"
+)
+
+// HeaderRequestID carries the request ID. A client-supplied value is kept so callers can
+// correlate their logs with the audit log; otherwise one is generated.
+const HeaderRequestID = "X-Request-ID"
+
+// maxRequestIDLength bounds client-supplied request IDs stored in the audit log.
+const maxRequestIDLength = 128
+
+// withAuditMetadata records who is making the request for the audit log and echoes the
+// request ID in the response. The source IP is the connection's peer address; forwarded
+// headers are ignored because clients can set them to anything.
+func withAuditMetadata(next http.Handler) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		requestID := r.Header.Get(HeaderRequestID)
+		if requestID == "" || len(requestID) > maxRequestIDLength {
+			id, err := uuid.NewV4()
+			if err != nil {
+				handleError(w, err)
+				return
+			}
+			requestID = id.String()
+		}
+		w.Header().Set(HeaderRequestID, requestID)
+
+		sourceIP, _, err := net.SplitHostPort(r.RemoteAddr)
+		if err != nil {
+			sourceIP = r.RemoteAddr
+		}
+
+		metadata := audit.Metadata{RequestID: requestID, SourceIP: sourceIP}
+		if principal, ok := auth.PrincipalFromContext(r.Context()); ok {
+			metadata.Actor = principal.UserID
+		}
+		next.ServeHTTP(w, r.WithContext(audit.WithMetadata(r.Context(), metadata)))
+	})
+}
@@ -2,8 +2,17 @@ package http
 
 import "net/http"
 
-func NewRouter(h *Handler) http.Handler {
+// RouteRegistrar adds a group of routes to the router.
+type RouteRegistrar interface {
+	RegisterRoutes(mux *http.ServeMux)
+}
+
+// NewRouter serves the routes of registrars to authenticated clients. The OpenAPI
+// document and the previews behind payment request links are public.
+func NewRouter(authenticator Authenticator, registrars ...RouteRegistrar) http.Handler {
 	mux := http.NewServeMux()
-	h.RegisterRoutes(mux)
-	return mux
+	for _, r := range registrars {
+		r.RegisterRoutes(mux)
+	}
+	return RequireAuthentication(authenticator, withAuditMetadata(mux), "/openapi.json", "/pay/")
 }
@@ -0,0 +1,281 @@
+package http
+
+import (
+	"encoding/csv"
+	"encoding/json"
+	"fmt"
+	"html/template"
+	"net/http"
+	"strconv"
+	"time"
+
+	"exchange/internal/usecase"
+)
+
+const (
+	StatementFormatCSV   = "csv"
+	StatementFormatJSONL = "jsonl"
+	StatementFormatHTML  = "html"
+
+	statementTimeLayout = "2006-01-02 15:04:05"
+	statementFlushEvery = 100
+
+	// statementWriteTimeout replaces the server's write timeout for statement exports.
+	statementWriteTimeout = 10 * time.Minute
+)
+
+// statementStream defers sending headers until the first line is written, so errors
+// raised before any output can still be reported with a proper status code.
+type statementStream struct {
+	w           http.ResponseWriter
+	contentType string
+	filename    string
+	buffer      interface{ Flush() }
+	started     bool
+	lines       int
+}
+
+func (s *statementStream) begin() {
+	if s.started {
+		return
+	}
+	s.started = true
+	s.w.Header().Set("Content-Type", s.contentType)
+	s.w.Header().Set("Content-Disposition", fmt.Sprintf("attachment; filename=%q", s.filename))
+	s.w.WriteHeader(http.StatusOK)
+}
+
+func (s *statementStream) Started() bool {
+	return s.started
+}
+
+func (s *statementStream) flush(force bool) {
+	s.lines++
+	if !force && s.lines%statementFlushEvery != 0 {
+		return
+	}
+	if s.buffer != nil {
+		s.buffer.Flush()
+	}
+	if f, ok := s.w.(http.Flusher); ok {
+		f.Flush()
+	}
+}
+
+type statementResponseWriter interface {
+	usecase.StatementWriter
+	Started() bool
+}
+
+func newStatementWriter(w http.ResponseWriter, format, userID string, from, to time.Time) (statementResponseWriter, error) {
+	filename := fmt.Sprintf("statement_%s_%s_%s.%s", userID, from.Format("20060102"), to.Format("20060102"), format)
+	switch format {
+	case StatementFormatCSV:
+		cw := csv.NewWriter(w)
+		return &csvStatementWriter{
+			statementStream: statementStream{w: w, contentType: "text/csv; charset=utf-8", filename: filename, buffer: cw},
+			csv:             cw,
+		}, nil
+	case StatementFormatJSONL:
+		return &jsonlStatementWriter{
+			statementStream: statementStream{w: w, contentType: "application/x-ndjson; charset=utf-8", filename: filename},
+			enc:             json.NewEncoder(w),
+		}, nil
+	case StatementFormatHTML:
+		return &htmlStatementWriter{
+			statementStream: statementStream{w: w, contentType: "text/html; charset=utf-8", filename: filename},
+		}, nil
+	default:
+		return nil, fmt.Errorf("unsupported statement format %q", format)
+	}
+}
+
+type csvStatementWriter struct {
+	statementStream
+	csv *csv.Writer
+}
+
+func (s *csvStatementWriter) WriteOpening(summary usecase.StatementSummary) error {
+	s.begin()
+	s.csv.Write([]string{"type", "transaction_id", "created_at", "transaction_type", "from_user_id", "to_user_id", "amount", "currency", "balance"})
+	s.csv.Write([]string{"OPENING", "", summary.From.Format(statementTimeLayout), "", "", "", "", summary.Currency, strconv.FormatInt(summary.Balance, 10)})
+	s.csv.Flush()
+	return s.csv.Error()
+}
+
+func (s *csvStatementWriter) WriteEntry(entry usecase.StatementEntry) error {
+	tx := entry.Transaction
+	s.csv.Write([]string{
+		"TRANSACTION",
+		tx.ID,
+		tx.CreatedAt.Format(statementTimeLayout),
+		string(tx.Type),
+		tx.FromUserID,
+		tx.ToUserID,
+		strconv.FormatInt(entry.Amount, 10),
+		tx.Currency,
+		strconv.FormatInt(entry.RunningBalance, 10),
+	})
+	s.flush(false)
+	return s.csv.Error()
+}
+
+func (s *csvStatementWriter) WriteClosing(summary usecase.StatementSummary) error {
+	s.csv.Write([]string{"CLOSING", "", summary.To.Format(statementTimeLayout), "", "", "", "", summary.Currency, strconv.FormatInt(summary.Balance, 10)})
+	s.flush(true)
+	return s.csv.Error()
+}
+
+type statementLine struct {
+	Type            string `json:"type"`
+	TransactionID   string `json:"transaction_id,omitempty"`
+	CreatedAt       string `json:"created_at,omitempty"`
+	TransactionType string `json:"transaction_type,omitempty"`
+	FromUserID      string `json:"from_user_id,omitempty"`
+	ToUserID        string `json:"to_user_id,omitempty"`
+	Amount          int64  `json:"amount"`
+	Currency        string `json:"currency"`
+	Balance         int64  `json:"balance"`
+}
+
+type jsonlStatementWriter struct {
+	statementStream
+	enc *json.Encoder
+}
+
+func (s *jsonlStatementWriter) WriteOpening(summary usecase.StatementSummary) error {
+	s.begin()
+	return s.enc.Encode(statementLine{
+		Type:      "OPENING",
+		CreatedAt: summary.From.Format(statementTimeLayout),
+		Currency:  summary.Currency,
+		Balance:   summary.Balance,
+	})
+}
+
+func (s *jsonlStatementWriter) WriteEntry(entry usecase.StatementEntry) error {
+	tx := entry.Transaction
+	err := s.enc.Encode(statementLine{
+		Type:            "TRANSACTION",
+		TransactionID:   tx.ID,
+		CreatedAt:       tx.CreatedAt.Format(statementTimeLayout),
+		TransactionType: string(tx.Type),
+		FromUserID:      tx.FromUserID,
+		ToUserID:        tx.ToUserID,
+		Amount:          entry.Amount,
+		Currency:        tx.Currency,
+		Balance:         entry.RunningBalance,
+	})
+	s.flush(false)
+	return err
+}
+
+func (s *jsonlStatementWriter) WriteClosing(summary usecase.StatementSummary) error {
+	err := s.enc.Encode(statementLine{
+		Type:      "CLOSING",
+		CreatedAt: summary.To.Format(statementTimeLayout),
+		Currency:  summary.Currency,
+		Balance:   summary.Balance,
+	})
+	s.flush(true)
+	return err
+}
+
+var (
+	statementHTMLHeader = template.Must(template.New("header").Parse(`<!DOCTYPE html>
+<html>
+<head>
+<meta charset="utf-8">
+<title>Statement {{.UserID}}</title>
+<style>
+@page { size: A4; margin: 15mm; }
+body { font-family: sans-serif; font-size: 10pt; }
+table { width: 100%; border-collapse: collapse; }
+thead { display: table-header-group; }
+tr { page-break-inside: avoid; }
+th, td { border-bottom: 1px solid #ccc; padding: 4px; text-align: left; }
+td.num, th.num { text-align: right; }
+</style>
+</head>
+<body>
+<h1>Account statement</h1>
+<p>User: {{.UserID}}<br>Period: {{.From}} &ndash; {{.To}}<br>Currency: {{.Currency}}</p>
+<p>Opening balance: {{.Balance}}</p>
+<table>
+<thead><tr><th>Date</th><th>Transaction</th><th>Type</th><th>From</th><th>To</th><th class="num">Amount</th><th class="num">Balance</th></tr></thead>
+<tbody>
+`))
+	statementHTMLRow = template.Must(template.New("row").Parse(
+		`<tr><td>{{.CreatedAt}}</td><td>{{.TransactionID}}</td><td>{{.TransactionType}}</td><td>{{.FromUserID}}</td><td>{{.ToUserID}}</td><td class="num">{{.Amount}}</td><td class="num">{{.Balance}}</td></tr>
+`))
+	statementHTMLFooter = template.Must(template.New("footer").Parse(`</tbody>
+</table>
+<p>Closing balance: {{.Balance}}</p>
+</body>
+</html>
+`))
+)
+
+type htmlStatementSummary struct {
+	UserID   string
+	Currency string
+	From     string
+	To       string
+	Balance  int64
+}
+
+func toHTMLStatementSummary(summary usecase.StatementSummary) htmlStatementSummary {
+	return htmlStatementSummary{
+		UserID:   summary.UserID,
+		Currency: summary.Currency,
+		From:     summary.From.Format(statementTimeLayout),
+		To:       summary.To.Format(statementTimeLayout),
+		Balance:  summary.Balance,
+	}
+}
+
+type htmlStatementWriter struct {
+	statementStream
+}
+
+func (s *htmlStatementWriter) WriteOpening(summary usecase.StatementSummary) error {
+	s.begin()
+	return statementHTMLHeader.Execute(s.w, toHTMLStatementSummary(summary))
+}
+
+func (s *htmlStatementWriter) WriteEntry(entry usecase.StatementEntry) error {
+	tx := entry.Transaction
+	err := statementHTMLRow.Execute(s.w, statementLine{
+		TransactionID:   tx.ID,
+		CreatedAt:       tx.CreatedAt.Format(statementTimeLayout),
+		TransactionType: string(tx.Type),
+		FromUserID:      tx.FromUserID,
+		ToUserID:        tx.ToUserID,
+		Amount:          entry.Amount,
+		Balance:         entry.RunningBalance,
+	})
+	s.flush(false)
+	return err
+}
+
+func (s *htmlStatementWriter) WriteClosing(summary usecase.StatementSummary) error {
+	err := statementHTMLFooter.Execute(s.w, toHTMLStatementSummary(summary))
+	s.flush(true)
+	return err
+}
+
+// parseStatementTime accepts either RFC 3339 timestamps or plain dates. A plain date used
+// as the end of the period covers that whole day.
+func parseStatementTime(value string, endOfPeriod bool) (time.Time, error) {
+	if t, err := time.Parse(time.RFC3339, value); err == nil {
+		return t, nil
+	}
+	t, err := time.Parse("2006-01-02", value)
+	if err != nil {
+		return time.Time{}, err
+	}
+	if endOfPeriod {
+		t = t.AddDate(0, 0, 1)
+	}
+	return t, nil
+}
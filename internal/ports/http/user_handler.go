@@ -0,0 +1,159 @@
+package http
+
+import (
+	"encoding/json"
+	"errors"
+	"net/http"
+	"strings"
+
+	"exchange/internal/domain/auth"
+	"exchange/internal/domain/kyc"
+	"exchange/internal/usecase"
+)
+
+// UserHandler serves user registration, profiles and KYC submissions under /users.
+// Callers manage only their own profile unless they have the admin role.
+type UserHandler struct {
+	UserUC *usecase.UserUseCase
+}
+
+func NewUserHandler(userUC *usecase.UserUseCase) *UserHandler {
+	return &UserHandler{
+		UserUC: userUC,
+	}
+}
+
+func (h *UserHandler) RegisterRoutes(mux *http.ServeMux) {
+	mux.HandleFunc("/users", h.registerHandler)
+	mux.HandleFunc("/users/", h.userHandler)
+}
+
+func (h *UserHandler) registerHandler(w http.ResponseWriter, r *http.Request) {
+	// POST /users
+	if r.Method != http.MethodPost {
+		http.Error(w, "method not allowed", http.StatusMethodNotAllowed)
+		return
+	}
+
+	var req RegisterUserRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		http.Error(w, "invalid request body", http.StatusBadRequest)
+		return
+	}
+
+	userID, err := actingUserID(r, req.UserID, auth.PermissionRead)
+	if err != nil {
+		handleError(w, err)
+		return
+	}
+
+	u, err := h.UserUC.Register(r.Context(), userID, req.Name, req.Email)
+	if err != nil {
+		handleError(w, err)
+		return
+	}
+
+	writeJSONStatus(w, http.StatusCreated, newUserResponse(u))
+}
+
+func (h *UserHandler) userHandler(w http.ResponseWriter, r *http.Request) {
+	// GET   /users/{user_id}
+	// PATCH /users/{user_id}
+	// GET   /users/{user_id}/kyc
+	// POST  /users/{user_id}/kyc/submissions
+	segments := strings.Split(strings.TrimPrefix(r.URL.Path, "/users/"), "/")
+	userID := segments[0]
+	if userID == "" {
+		http.Error(w, "not found", http.StatusNotFound)
+		return
+	}
+	switch {
+	case len(segments) == 1:
+	case len(segments) == 2 && segments[1] == "kyc":
+	case len(segments) == 3 && segments[1] == "kyc" && segments[2] == "submissions":
+	default:
+		http.Error(w, "not found", http.StatusNotFound)
+		return
+	}
+	if _, err := actingUserID(r, userID, auth.PermissionRead); err != nil {
+		handleError(w, err)
+		return
+	}
+
+	switch len(segments) {
+	case 2:
+		h.getKYCHandler(w, r, userID)
+		return
+	case 3:
+		h.submitKYCHandler(w, r, userID)
+		return
+	}
+
+	ctx := r.Context()
+	switch r.Method {
+	case http.MethodGet:
+		u, err := h.UserUC.GetUser(ctx, userID)
+		if err != nil {
+			handleError(w, err)
+			return
+		}
+		writeJSON(w, newUserResponse(u))
+	case http.MethodPatch:
+		var req UpdateUserRequest
+		if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+			http.Error(w, "invalid request body", http.StatusBadRequest)
+			return
+		}
+		u, err := h.UserUC.UpdateProfile(ctx, userID, req.Name, req.Email)
+		if err != nil {
+			handleError(w, err)
+			return
+		}
+		writeJSON(w, newUserResponse(u))
+	default:
+		http.Error(w, "method not allowed", http.StatusMethodNotAllowed)
+	}
+}
+
+func (h *UserHandler) getKYCHandler(w http.ResponseWriter, r *http.Request, userID string) {
+	if r.Method != http.MethodGet {
+		http.Error(w, "method not allowed", http.StatusMethodNotAllowed)
+		return
+	}
+
+	status, err := h.UserUC.GetKYC(r.Context(), userID)
+	if err != nil {
+		handleError(w, err)
+		return
+	}
+	writeJSON(w, newKYCResponse(userID, status))
+}
+
+// maxKYCRequestSize bounds a submission's body: the base64 encoded document and its
+// description.
+const maxKYCRequestSize = kyc.MaxDocumentSize/3*4 + 64<<10
+
+func (h *UserHandler) submitKYCHandler(w http.ResponseWriter, r *http.Request, userID string) {
+	if r.Method != http.MethodPost {
+		http.Error(w, "method not allowed", http.StatusMethodNotAllowed)
+		return
+	}
+
+	var req KYCSubmissionRequest
+	if err := json.NewDecoder(http.MaxBytesReader(w, r.Body, maxKYCRequestSize)).Decode(&req); err != nil {
+		var tooLarge *http.MaxBytesError
+		if errors.As(err, &tooLarge) {
+			handleError(w, kyc.ErrDocumentTooLarge)
+			return
+		}
+		http.Error(w, "invalid request body", http.StatusBadRequest)
+		return
+	}
+
+	s, err := h.UserUC.SubmitKYC(r.Context(), userID, kyc.Level(req.Level), req.DocumentType, req.FileName, req.ContentType, req.Content)
+	if err != nil {
+		handleError(w, err)
+		return
+	}
+	writeJSONStatus(w, http.StatusCreated, newKYCSubmissionResponse(s))
+}
@@ -0,0 +1,202 @@
+package http
+
+import (
+	"encoding/json"
+	"net/http"
+	"strings"
+
+	"exchange/internal/domain/auth"
+	"exchange/internal/domain/event"
+	"exchange/internal/domain/webhook"
+	"exchange/internal/usecase"
+)
+
+// WebhookHandler serves webhook subscriptions and the delivery dashboard under
+// /webhooks. Callers see and manage only their own subscriptions and deliveries.
+type WebhookHandler struct {
+	WebhookUC *usecase.WebhookUseCase
+}
+
+func NewWebhookHandler(webhookUC *usecase.WebhookUseCase) *WebhookHandler {
+	return &WebhookHandler{
+		WebhookUC: webhookUC,
+	}
+}
+
+func (h *WebhookHandler) RegisterRoutes(mux *http.ServeMux) {
+	mux.HandleFunc("/webhooks/subscriptions", h.subscriptionsHandler)
+	mux.HandleFunc("/webhooks/subscriptions/", h.subscriptionHandler)
+	mux.HandleFunc("/webhooks/deliveries", h.listDeliveriesHandler)
+	mux.HandleFunc("/webhooks/deliveries/", h.deliveryHandler)
+}
+
+func (h *WebhookHandler) subscriptionsHandler(w http.ResponseWriter, r *http.Request) {
+	// GET  /webhooks/subscriptions?user_id=
+	// POST /webhooks/subscriptions
+	switch r.Method {
+	case http.MethodGet:
+		h.listSubscriptionsHandler(w, r)
+	case http.MethodPost:
+		h.createSubscriptionHandler(w, r)
+	default:
+		http.Error(w, "method not allowed", http.StatusMethodNotAllowed)
+	}
+}
+
+func (h *WebhookHandler) createSubscriptionHandler(w http.ResponseWriter, r *http.Request) {
+	var req WebhookSubscriptionRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		http.Error(w, "invalid request body", http.StatusBadRequest)
+		return
+	}
+
+	userID, err := actingUserID(r, req.UserID, auth.PermissionRead)
+	if err != nil {
+		handleError(w, err)
+		return
+	}
+
+	eventTypes := make([]event.Type, 0, len(req.EventTypes))
+	for _, t := range req.EventTypes {
+		eventTypes = append(eventTypes, event.Type(t))
+	}
+
+	ctx := r.Context()
+	sub, err := h.WebhookUC.CreateSubscription(ctx, userID, req.URL, eventTypes)
+	if err != nil {
+		handleError(w, err)
+		return
+	}
+
+	// The secret is only ever shown here.
+	resp := newWebhookSubscriptionResponse(sub)
+	resp.Secret = sub.Secret
+	writeJSONStatus(w, http.StatusCreated, resp)
+}
+
+func (h *WebhookHandler) listSubscriptionsHandler(w http.ResponseWriter, r *http.Request) {
+	userID, err := actingUserID(r, r.URL.Query().Get("user_id"), auth.PermissionRead)
+	if err != nil {
+		handleError(w, err)
+		return
+	}
+
+	ctx := r.Context()
+	subs, err := h.WebhookUC.ListSubscriptions(ctx, userID)
+	if err != nil {
+		handleError(w, err)
+		return
+	}
+
+	resp := make([]WebhookSubscriptionResponse, 0, len(subs))
+	for _, sub := range subs {
+		resp = append(resp, newWebhookSubscriptionResponse(sub))
+	}
+	writeJSON(w, resp)
+}
+
+func (h *WebhookHandler) subscriptionHandler(w http.ResponseWriter, r *http.Request) {
+	// DELETE /webhooks/subscriptions/{id}?user_id=
+	id := strings.TrimPrefix(r.URL.Path, "/webhooks/subscriptions/")
+	if id == "" || strings.Contains(id, "/") {
+		http.Error(w, "not found", http.StatusNotFound)
+		return
+	}
+	if r.Method != http.MethodDelete {
+		http.Error(w, "method not allowed", http.StatusMethodNotAllowed)
+		return
+	}
+
+	userID, err := actingUserID(r, r.URL.Query().Get("user_id"), auth.PermissionRead)
+	if err != nil {
+		handleError(w, err)
+		return
+	}
+
+	ctx := r.Context()
+	if err := h.WebhookUC.DeleteSubscription(ctx, userID, id); err != nil {
+		handleError(w, err)
+		return
+	}
+
+	w.WriteHeader(http.StatusNoContent)
+}
+
+func (h *WebhookHandler) listDeliveriesHandler(w http.ResponseWriter, r *http.Request) {
+	// GET /webhooks/deliveries?user_id=&subscription_id=&status=&limit=10&offset=0
+	if r.Method != http.MethodGet {
+		http.Error(w, "method not allowed", http.StatusMethodNotAllowed)
+		return
+	}
+
+	query := r.URL.Query()
+	limit, offset, err := parsePagination(query)
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+
+	userID, err := actingUserID(r, query.Get("user_id"), auth.PermissionRead)
+	if err != nil {
+		handleError(w, err)
+		return
+	}
+
+	ctx := r.Context()
+	deliveries, err := h.WebhookUC.ListDeliveries(ctx, webhook.DeliveryFilter{
+		UserID:         userID,
+		SubscriptionID: query.Get("subscription_id"),
+		Status:         webhook.DeliveryStatus(query.Get("status")),
+	}, limit, offset)
+	if err != nil {
+		handleError(w, err)
+		return
+	}
+
+	resp := make([]WebhookDeliveryResponse, 0, len(deliveries))
+	for _, d := range deliveries {
+		resp = append(resp, newWebhookDeliveryResponse(d))
+	}
+	writeJSON(w, resp)
+}
+
+func (h *WebhookHandler) deliveryHandler(w http.ResponseWriter, r *http.Request) {
+	// GET  /webhooks/deliveries/{id}/attempts?user_id=
+	// POST /webhooks/deliveries/{id}/replay?user_id=
+	segments := strings.Split(strings.TrimPrefix(r.URL.Path, "/webhooks/deliveries/"), "/")
+	if len(segments) != 2 || segments[0] == "" {
+		http.Error(w, "not found", http.StatusNotFound)
+		return
+	}
+	id := segments[0]
+
+	userID, err := actingUserID(r, r.URL.Query().Get("user_id"), auth.PermissionRead)
+	if err != nil {
+		handleError(w, err)
+		return
+	}
+
+	ctx := r.Context()
+	switch {
+	case segments[1] == "attempts" && r.Method == http.MethodGet:
+		attempts, err := h.WebhookUC.ListAttempts(ctx, userID, id)
+		if err != nil {
+			handleError(w, err)
+			return
+		}
+		resp := make([]WebhookAttemptResponse, 0, len(attempts))
+		for _, a := range attempts {
+			resp = append(resp, newWebhookAttemptResponse(a))
+		}
+		writeJSON(w, resp)
+	case segments[1] == "replay" && r.Method == http.MethodPost:
+		d, err := h.WebhookUC.ReplayDelivery(ctx, userID, id)
+		if err != nil {
+			handleError(w, err)
+			return
+		}
+		writeJSONStatus(w, http.StatusAccepted, newWebhookDeliveryResponse(d))
+	default:
+		http.Error(w, "not found", http.StatusNotFound)
+	}
+}
@@ -0,0 +1,18 @@
+CREATE TABLE IF NOT EXISTS adjustments (
+    id TEXT PRIMARY KEY,
+    user_id TEXT NOT NULL,
+    direction TEXT NOT NULL,
+    amount BIGINT NOT NULL,
+    currency TEXT NOT NULL,
+    reason_code TEXT NOT NULL,
+    note TEXT NOT NULL DEFAULT '',
+    status TEXT NOT NULL,
+    requested_by TEXT NOT NULL,
+    decided_by TEXT,
+    transaction_id TEXT REFERENCES transactions (id),
+    created_at TIMESTAMP NOT NULL,
+    decided_at TIMESTAMP
+);
+
+CREATE INDEX IF NOT EXISTS idx_adjustments_user_id ON adjustments (user_id);
+CREATE INDEX IF NOT EXISTS idx_adjustments_status_created_at ON adjustments (status, created_at);
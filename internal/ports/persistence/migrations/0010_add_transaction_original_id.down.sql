@@ -0,0 +1,3 @@
+DROP INDEX IF EXISTS idx_transactions_original_id;
+
+ALTER TABLE transactions DROP COLUMN IF EXISTS original_id;
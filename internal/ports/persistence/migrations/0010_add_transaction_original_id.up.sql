@@ -0,0 +1,3 @@
+ALTER TABLE transactions ADD COLUMN IF NOT EXISTS original_id TEXT REFERENCES transactions (id);
+
+CREATE INDEX IF NOT EXISTS idx_transactions_original_id ON transactions (original_id);
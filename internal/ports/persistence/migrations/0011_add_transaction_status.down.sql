@@ -0,0 +1,10 @@
+ALTER TABLE wallets DROP CONSTRAINT IF EXISTS wallets_held_check;
+ALTER TABLE wallets DROP COLUMN IF EXISTS held;
+
+DROP INDEX IF EXISTS idx_transactions_status;
+
+ALTER TABLE transactions DROP COLUMN IF EXISTS cancelled_at;
+ALTER TABLE transactions DROP COLUMN IF EXISTS failed_at;
+ALTER TABLE transactions DROP COLUMN IF EXISTS completed_at;
+ALTER TABLE transactions DROP COLUMN IF EXISTS processing_at;
+ALTER TABLE transactions DROP COLUMN IF EXISTS status;
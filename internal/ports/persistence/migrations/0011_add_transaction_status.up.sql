@@ -0,0 +1,13 @@
+ALTER TABLE transactions ADD COLUMN IF NOT EXISTS status TEXT NOT NULL DEFAULT 'completed';
+ALTER TABLE transactions ADD COLUMN IF NOT EXISTS processing_at TIMESTAMP;
+ALTER TABLE transactions ADD COLUMN IF NOT EXISTS completed_at TIMESTAMP;
+ALTER TABLE transactions ADD COLUMN IF NOT EXISTS failed_at TIMESTAMP;
+ALTER TABLE transactions ADD COLUMN IF NOT EXISTS cancelled_at TIMESTAMP;
+
+-- Every transaction logged so far completed when it was created.
+UPDATE transactions SET completed_at = created_at WHERE status = 'completed' AND completed_at IS NULL;
+
+CREATE INDEX IF NOT EXISTS idx_transactions_status ON transactions (status) WHERE status IN ('pending', 'processing');
+
+ALTER TABLE wallets ADD COLUMN IF NOT EXISTS held BIGINT NOT NULL DEFAULT 0;
+ALTER TABLE wallets ADD CONSTRAINT wallets_held_check CHECK (held >= 0 AND held <= balance);
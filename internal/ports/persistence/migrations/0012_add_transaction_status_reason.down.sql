@@ -0,0 +1,4 @@
+DROP INDEX IF EXISTS idx_transactions_status;
+CREATE INDEX IF NOT EXISTS idx_transactions_status ON transactions (status) WHERE status IN ('pending', 'processing');
+
+ALTER TABLE transactions DROP COLUMN IF EXISTS status_reason;
@@ -0,0 +1,5 @@
+ALTER TABLE transactions ADD COLUMN IF NOT EXISTS status_reason TEXT NOT NULL DEFAULT '';
+
+-- The approval queue and the expiry worker look up transactions awaiting approval.
+DROP INDEX IF EXISTS idx_transactions_status;
+CREATE INDEX IF NOT EXISTS idx_transactions_status ON transactions (status, created_at) WHERE status IN ('awaiting_approval', 'pending', 'processing');
@@ -0,0 +1,3 @@
+DROP INDEX IF EXISTS idx_transactions_from_user_id_type_created_at;
+DROP TABLE IF EXISTS limit_overrides;
+DROP TABLE IF EXISTS account_tiers;
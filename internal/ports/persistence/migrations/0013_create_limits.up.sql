@@ -0,0 +1,18 @@
+CREATE TABLE IF NOT EXISTS account_tiers (
+    user_id TEXT PRIMARY KEY,
+    tier TEXT NOT NULL,
+    updated_at TIMESTAMP NOT NULL DEFAULT NOW()
+);
+
+CREATE TABLE IF NOT EXISTS limit_overrides (
+    user_id TEXT NOT NULL,
+    operation TEXT NOT NULL CHECK (operation IN ('withdrawal', 'transfer')),
+    currency TEXT NOT NULL,
+    period TEXT NOT NULL CHECK (period IN ('daily', 'monthly')),
+    max_amount BIGINT NOT NULL DEFAULT 0 CHECK (max_amount >= 0),
+    max_count INTEGER NOT NULL DEFAULT 0 CHECK (max_count >= 0),
+    PRIMARY KEY (user_id, operation, currency, period)
+);
+
+-- Limit checks add up a user's outgoing transactions of one type since the start of the period.
+CREATE INDEX IF NOT EXISTS idx_transactions_from_user_id_type_created_at ON transactions (from_user_id, type, created_at);
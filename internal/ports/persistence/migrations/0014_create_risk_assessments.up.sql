@@ -0,0 +1,18 @@
+CREATE TABLE IF NOT EXISTS risk_assessments (
+    id TEXT PRIMARY KEY,
+    user_id TEXT NOT NULL,
+    operation TEXT NOT NULL CHECK (operation IN ('withdrawal', 'transfer')),
+    counterparty_id TEXT NOT NULL,
+    amount BIGINT NOT NULL,
+    currency TEXT NOT NULL,
+    decision TEXT NOT NULL CHECK (decision IN ('allow', 'review', 'block')),
+    rule_ids JSONB NOT NULL,
+    created_at TIMESTAMP NOT NULL
+);
+
+CREATE INDEX IF NOT EXISTS idx_risk_assessments_user_id_created_at ON risk_assessments (user_id, created_at);
+CREATE INDEX IF NOT EXISTS idx_risk_assessments_decision_created_at ON risk_assessments (decision, created_at);
+
+-- Risk rules read every transaction of a user, sent or received, over their lookback.
+CREATE INDEX IF NOT EXISTS idx_transactions_to_user_id_created_at ON transactions (to_user_id, created_at);
+CREATE INDEX IF NOT EXISTS idx_transactions_from_user_id_created_at ON transactions (from_user_id, created_at);
@@ -0,0 +1,2 @@
+ALTER TABLE wallets DROP CONSTRAINT IF EXISTS fk_wallets_user_id;
+ALTER TABLE users DROP COLUMN IF EXISTS status;
@@ -0,0 +1,5 @@
+ALTER TABLE users ADD COLUMN IF NOT EXISTS status TEXT NOT NULL DEFAULT 'active' CHECK (status IN ('active', 'deactivated'));
+
+-- Wallets opened before users were enforced may belong to unregistered users, so the key is
+-- only checked for new wallets until it is validated with ALTER TABLE ... VALIDATE CONSTRAINT.
+ALTER TABLE wallets ADD CONSTRAINT fk_wallets_user_id FOREIGN KEY (user_id) REFERENCES users (user_id) NOT VALID;
@@ -0,0 +1,3 @@
+DROP TABLE IF EXISTS kyc_submissions;
+DROP TABLE IF EXISTS kyc_level_changes;
+ALTER TABLE users DROP COLUMN IF EXISTS kyc_level;
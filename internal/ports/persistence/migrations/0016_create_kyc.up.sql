@@ -0,0 +1,32 @@
+ALTER TABLE users ADD COLUMN IF NOT EXISTS kyc_level TEXT NOT NULL DEFAULT 'none' CHECK (kyc_level IN ('none', 'basic', 'full'));
+
+CREATE TABLE IF NOT EXISTS kyc_level_changes (
+    id TEXT PRIMARY KEY,
+    user_id TEXT NOT NULL REFERENCES users (user_id),
+    from_level TEXT NOT NULL,
+    to_level TEXT NOT NULL,
+    reason TEXT NOT NULL,
+    changed_by TEXT NOT NULL,
+    submission_id TEXT,
+    created_at TIMESTAMP NOT NULL
+);
+
+CREATE INDEX IF NOT EXISTS idx_kyc_level_changes_user_id_created_at ON kyc_level_changes (user_id, created_at);
+
+CREATE TABLE IF NOT EXISTS kyc_submissions (
+    id TEXT PRIMARY KEY,
+    user_id TEXT NOT NULL REFERENCES users (user_id),
+    level TEXT NOT NULL CHECK (level IN ('basic', 'full')),
+    document_type TEXT NOT NULL,
+    file_name TEXT NOT NULL DEFAULT '',
+    content_type TEXT NOT NULL,
+    size BIGINT NOT NULL,
+    status TEXT NOT NULL CHECK (status IN ('pending', 'approved', 'rejected')),
+    reason TEXT NOT NULL DEFAULT '',
+    reviewed_by TEXT,
+    created_at TIMESTAMP NOT NULL,
+    reviewed_at TIMESTAMP
+);
+
+CREATE INDEX IF NOT EXISTS idx_kyc_submissions_user_id_created_at ON kyc_submissions (user_id, created_at);
+CREATE INDEX IF NOT EXISTS idx_kyc_submissions_status_created_at ON kyc_submissions (status, created_at);
@@ -0,0 +1,18 @@
+CREATE TABLE IF NOT EXISTS sanctions_cases (
+    id TEXT PRIMARY KEY,
+    subject_type TEXT NOT NULL CHECK (subject_type IN ('user', 'destination')),
+    subject_id TEXT NOT NULL,
+    subject_name TEXT NOT NULL,
+    entry_id TEXT NOT NULL,
+    entry_name TEXT NOT NULL,
+    score DOUBLE PRECISION NOT NULL,
+    operation TEXT NOT NULL,
+    status TEXT NOT NULL CHECK (status IN ('open', 'cleared')),
+    reason TEXT NOT NULL DEFAULT '',
+    cleared_by TEXT,
+    created_at TIMESTAMP NOT NULL,
+    cleared_at TIMESTAMP
+);
+
+CREATE INDEX IF NOT EXISTS idx_sanctions_cases_subject_entry ON sanctions_cases (subject_type, subject_id, entry_id, created_at);
+CREATE INDEX IF NOT EXISTS idx_sanctions_cases_status_created_at ON sanctions_cases (status, created_at);
@@ -0,0 +1,7 @@
+CREATE TABLE IF NOT EXISTS balance_snapshots (
+    user_id TEXT NOT NULL,
+    currency TEXT NOT NULL,
+    balance BIGINT NOT NULL,
+    taken_at TIMESTAMP NOT NULL,
+    PRIMARY KEY (user_id, taken_at)
+);
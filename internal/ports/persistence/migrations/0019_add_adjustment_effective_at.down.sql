@@ -0,0 +1 @@
+ALTER TABLE adjustments DROP COLUMN IF EXISTS effective_at;
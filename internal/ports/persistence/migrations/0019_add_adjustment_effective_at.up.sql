@@ -0,0 +1,3 @@
+ALTER TABLE adjustments ADD COLUMN IF NOT EXISTS effective_at TIMESTAMP;
+UPDATE adjustments SET effective_at = created_at WHERE effective_at IS NULL;
+ALTER TABLE adjustments ALTER COLUMN effective_at SET NOT NULL;
@@ -0,0 +1,2 @@
+DROP TABLE IF EXISTS period_closes;
+DROP TABLE IF EXISTS balance_totals;
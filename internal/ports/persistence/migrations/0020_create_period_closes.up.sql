@@ -0,0 +1,14 @@
+CREATE TABLE IF NOT EXISTS balance_totals (
+    currency TEXT NOT NULL,
+    taken_at TIMESTAMP NOT NULL,
+    balance BIGINT NOT NULL,
+    wallets INTEGER NOT NULL,
+    PRIMARY KEY (taken_at, currency)
+);
+
+CREATE TABLE IF NOT EXISTS period_closes (
+    period_start TIMESTAMP PRIMARY KEY,
+    period_end TIMESTAMP NOT NULL,
+    closed_by TEXT NOT NULL,
+    closed_at TIMESTAMP NOT NULL
+);
@@ -0,0 +1,2 @@
+DROP TABLE IF EXISTS liability_leaves;
+DROP TABLE IF EXISTS liability_roots;
@@ -0,0 +1,18 @@
+CREATE TABLE IF NOT EXISTS liability_roots (
+    currency TEXT NOT NULL,
+    taken_at TIMESTAMP NOT NULL,
+    root_hash TEXT NOT NULL,
+    total BIGINT NOT NULL,
+    wallets INTEGER NOT NULL,
+    PRIMARY KEY (currency, taken_at)
+);
+
+CREATE TABLE IF NOT EXISTS liability_leaves (
+    currency TEXT NOT NULL,
+    taken_at TIMESTAMP NOT NULL,
+    user_id TEXT NOT NULL,
+    balance BIGINT NOT NULL,
+    nonce TEXT NOT NULL,
+    PRIMARY KEY (currency, taken_at, user_id),
+    FOREIGN KEY (currency, taken_at) REFERENCES liability_roots (currency, taken_at)
+);
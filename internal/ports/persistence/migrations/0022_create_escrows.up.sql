@@ -0,0 +1,22 @@
+CREATE TABLE IF NOT EXISTS escrows (
+    id TEXT PRIMARY KEY,
+    payer_id TEXT NOT NULL,
+    payee_id TEXT NOT NULL,
+    amount BIGINT NOT NULL CHECK (amount > 0),
+    currency TEXT NOT NULL,
+    conditions TEXT NOT NULL DEFAULT '',
+    on_expiry TEXT NOT NULL CHECK (on_expiry IN ('release', 'refund')),
+    expires_at TIMESTAMP NOT NULL,
+    status TEXT NOT NULL CHECK (status IN ('funded', 'disputed', 'released', 'refunded')),
+    disputed_by TEXT,
+    dispute_reason TEXT NOT NULL DEFAULT '',
+    funding_transaction_id TEXT NOT NULL REFERENCES transactions (id),
+    settlement_transaction_id TEXT REFERENCES transactions (id),
+    created_at TIMESTAMP NOT NULL,
+    updated_at TIMESTAMP NOT NULL,
+    settled_at TIMESTAMP
+);
+
+CREATE INDEX IF NOT EXISTS idx_escrows_payer_id_created_at ON escrows (payer_id, created_at);
+CREATE INDEX IF NOT EXISTS idx_escrows_payee_id_created_at ON escrows (payee_id, created_at);
+CREATE INDEX IF NOT EXISTS idx_escrows_funded_expires_at ON escrows (expires_at) WHERE status = 'funded';
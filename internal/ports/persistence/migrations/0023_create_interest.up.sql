@@ -0,0 +1,23 @@
+CREATE TABLE IF NOT EXISTS interest_accruals (
+    user_id TEXT NOT NULL,
+    day TIMESTAMP NOT NULL,
+    currency TEXT NOT NULL,
+    balance BIGINT NOT NULL,
+    micros BIGINT NOT NULL CHECK (micros > 0),
+    created_at TIMESTAMP NOT NULL,
+    PRIMARY KEY (user_id, day)
+);
+
+CREATE INDEX IF NOT EXISTS idx_interest_accruals_day ON interest_accruals (day);
+
+CREATE TABLE IF NOT EXISTS interest_payouts (
+    user_id TEXT NOT NULL,
+    month TIMESTAMP NOT NULL,
+    currency TEXT NOT NULL,
+    accrued BIGINT NOT NULL CHECK (accrued >= 0),
+    amount BIGINT NOT NULL CHECK (amount >= 0),
+    carry BIGINT NOT NULL CHECK (carry >= 0),
+    transaction_id TEXT REFERENCES transactions (id),
+    created_at TIMESTAMP NOT NULL,
+    PRIMARY KEY (user_id, month)
+);
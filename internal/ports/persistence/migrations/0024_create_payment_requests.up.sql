@@ -0,0 +1,20 @@
+CREATE TABLE IF NOT EXISTS payment_requests (
+    id TEXT PRIMARY KEY,
+    token TEXT NOT NULL UNIQUE,
+    requester_id TEXT NOT NULL,
+    payer_id TEXT,
+    amount BIGINT NOT NULL CHECK (amount > 0),
+    currency TEXT NOT NULL,
+    memo TEXT NOT NULL DEFAULT '',
+    expires_at TIMESTAMP NOT NULL,
+    status TEXT NOT NULL CHECK (status IN ('pending', 'paid', 'declined', 'cancelled', 'expired')),
+    paid_by TEXT,
+    transaction_id TEXT REFERENCES transactions (id),
+    created_at TIMESTAMP NOT NULL,
+    updated_at TIMESTAMP NOT NULL,
+    closed_at TIMESTAMP
+);
+
+CREATE INDEX IF NOT EXISTS idx_payment_requests_requester_id_created_at ON payment_requests (requester_id, created_at);
+CREATE INDEX IF NOT EXISTS idx_payment_requests_payer_id_created_at ON payment_requests (payer_id, created_at);
+CREATE INDEX IF NOT EXISTS idx_payment_requests_pending_expires_at ON payment_requests (expires_at) WHERE status = 'pending';
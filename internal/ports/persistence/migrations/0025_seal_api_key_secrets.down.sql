@@ -0,0 +1,8 @@
+ALTER TABLE api_keys DROP CONSTRAINT IF EXISTS api_keys_sealed_secret_check;
+
+UPDATE api_keys SET revoked_at = NOW() WHERE revoked_at IS NULL;
+
+ALTER TABLE api_keys ADD COLUMN IF NOT EXISTS secret_hash TEXT NOT NULL DEFAULT '';
+ALTER TABLE api_keys ALTER COLUMN secret_hash DROP DEFAULT;
+
+ALTER TABLE api_keys DROP COLUMN IF EXISTS sealed_secret;
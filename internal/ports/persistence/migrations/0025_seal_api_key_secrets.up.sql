@@ -0,0 +1,10 @@
+-- secret_hash was the HMAC key itself, so anyone who could read it could sign requests.
+-- Keys issued before secrets were sealed are revoked and must be reissued.
+ALTER TABLE api_keys ADD COLUMN IF NOT EXISTS sealed_secret BYTEA;
+
+UPDATE api_keys SET revoked_at = NOW() WHERE revoked_at IS NULL;
+
+ALTER TABLE api_keys DROP COLUMN IF EXISTS secret_hash;
+
+ALTER TABLE api_keys ADD CONSTRAINT api_keys_sealed_secret_check
+    CHECK (sealed_secret IS NOT NULL OR revoked_at IS NOT NULL);
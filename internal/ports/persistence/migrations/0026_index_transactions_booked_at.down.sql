@@ -0,0 +1,3 @@
+DROP INDEX IF EXISTS idx_transactions_booked_at;
+DROP INDEX IF EXISTS idx_transactions_to_user_id_booked_at;
+DROP INDEX IF EXISTS idx_transactions_from_user_id_booked_at;
@@ -0,0 +1,5 @@
+-- Balances, statements and reserves window completed transactions by when they completed,
+-- which for withdrawals can be long after they were created.
+CREATE INDEX IF NOT EXISTS idx_transactions_from_user_id_booked_at ON transactions (from_user_id, (COALESCE(completed_at, created_at))) WHERE status = 'completed';
+CREATE INDEX IF NOT EXISTS idx_transactions_to_user_id_booked_at ON transactions (to_user_id, (COALESCE(completed_at, created_at))) WHERE status = 'completed';
+CREATE INDEX IF NOT EXISTS idx_transactions_booked_at ON transactions ((COALESCE(completed_at, created_at))) WHERE status = 'completed';
@@ -0,0 +1,119 @@
+package persistence
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+
+	"exchange/internal/domain/adjustment"
+)
+
+// adjustmentColumns lists the columns read by scanAdjustment, in order.
+const adjustmentColumns = `id, user_id, direction, amount, currency, reason_code, note, status, requested_by, COALESCE(decided_by, ''), COALESCE(transaction_id, ''), effective_at, created_at, decided_at`
+
+func scanAdjustment(row rowScanner) (adjustment.Adjustment, error) {
+	var a adjustment.Adjustment
+	var direction, reason, status string
+	var decidedAt sql.NullTime
+	err := row.Scan(&a.ID, &a.UserID, &direction, &a.Amount, &a.Currency, &reason, &a.Note, &status,
+		&a.RequestedBy, &a.DecidedBy, &a.TransactionID, &a.EffectiveAt, &a.CreatedAt, &decidedAt)
+	if err != nil {
+		return adjustment.Adjustment{}, err
+	}
+	a.Direction = adjustment.Direction(direction)
+	a.Reason = adjustment.ReasonCode(reason)
+	a.Status = adjustment.Status(status)
+	if decidedAt.Valid {
+		a.DecidedAt = &decidedAt.Time
+	}
+	return a, nil
+}
+
+type PostgresAdjustmentRepository struct {
+	db *sql.DB
+}
+
+func NewPostgresAdjustmentRepository(db *sql.DB) *PostgresAdjustmentRepository {
+	return &PostgresAdjustmentRepository{
+		db: db,
+	}
+}
+
+func (r *PostgresAdjustmentRepository) CreateAdjustment(ctx context.Context, a adjustment.Adjustment) error {
+	query := `
+        INSERT INTO adjustments (id, user_id, direction, amount, currency, reason_code, note, status, requested_by, effective_at, created_at)
+        VALUES ($1, $2, $3, $4, $5, $6, $7, $8, $9, $10, $11)
+    `
+	_, err := executor(ctx, r.db).ExecContext(ctx, query,
+		a.ID, a.UserID, string(a.Direction), a.Amount, a.Currency, string(a.Reason), a.Note, string(a.Status), a.RequestedBy, a.EffectiveAt, a.CreatedAt,
+	)
+	return err
+}
+
+func (r *PostgresAdjustmentRepository) GetAdjustmentByID(ctx context.Context, id string) (adjustment.Adjustment, error) {
+	query := `
+        SELECT ` + adjustmentColumns + `
+        FROM adjustments
+        WHERE id = $1
+    `
+	a, err := scanAdjustment(executor(ctx, r.db).QueryRowContext(ctx, query, id))
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return adjustment.Adjustment{}, adjustment.ErrAdjustmentNotFound
+		}
+		return adjustment.Adjustment{}, err
+	}
+	return a, nil
+}
+
+func (r *PostgresAdjustmentRepository) ListAdjustments(ctx context.Context, status adjustment.Status, limit, offset int) ([]adjustment.Adjustment, error) {
+	var f queryFilter
+	if status != "" {
+		f.add("status = $%[1]d", string(status))
+	}
+
+	query := `
+        SELECT ` + adjustmentColumns + `
+        FROM adjustments
+        ` + f.where() + `
+        ORDER BY created_at DESC, id DESC
+        ` + f.page(limit, offset)
+	rows, err := executor(ctx, r.db).QueryContext(ctx, query, f.args...)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var results []adjustment.Adjustment
+	for rows.Next() {
+		a, err := scanAdjustment(rows)
+		if err != nil {
+			return nil, err
+		}
+		results = append(results, a)
+	}
+
+	return results, rows.Err()
+}
+
+func (r *PostgresAdjustmentRepository) DecideAdjustment(ctx context.Context, a adjustment.Adjustment) error {
+	query := `
+        UPDATE adjustments
+        SET status = $2, decided_by = NULLIF($3, ''), transaction_id = NULLIF($4, ''), decided_at = $5
+        WHERE id = $1 AND status = 'pending'
+    `
+	res, err := executor(ctx, r.db).ExecContext(ctx, query, a.ID, string(a.Status), a.DecidedBy, a.TransactionID, a.DecidedAt)
+	if err != nil {
+		return err
+	}
+
+	rowsAffected, err := res.RowsAffected()
+	if err != nil {
+		return err
+	}
+	if rowsAffected == 0 {
+		return adjustment.ErrAdjustmentNotPending
+	}
+
+	return nil
+}
@@ -0,0 +1,127 @@
+package persistence
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"strings"
+	"time"
+
+	"exchange/internal/domain/auth"
+)
+
+// PostgresAPIKeyRepository stores API keys and the nonces consumed by signed requests.
+type PostgresAPIKeyRepository struct {
+	db *sql.DB
+}
+
+func NewPostgresAPIKeyRepository(db *sql.DB) *PostgresAPIKeyRepository {
+	return &PostgresAPIKeyRepository{
+		db: db,
+	}
+}
+
+func (r *PostgresAPIKeyRepository) CreateAPIKey(ctx context.Context, key auth.APIKey) error {
+	query := `
+        INSERT INTO api_keys (id, user_id, sealed_secret, permissions, created_at)
+        VALUES ($1, $2, $3, $4, $5)
+    `
+	_, err := executor(ctx, r.db).ExecContext(ctx, query,
+		key.ID, key.UserID, key.Secret, joinPermissions(key.Permissions), key.CreatedAt,
+	)
+	return err
+}
+
+func (r *PostgresAPIKeyRepository) GetAPIKeyByID(ctx context.Context, id string) (auth.APIKey, error) {
+	query := `
+        SELECT id, user_id, sealed_secret, permissions, created_at, revoked_at
+        FROM api_keys
+        WHERE id = $1
+    `
+	var key auth.APIKey
+	var permissions string
+	var revokedAt sql.NullTime
+	err := executor(ctx, r.db).QueryRowContext(ctx, query, id).Scan(
+		&key.ID, &key.UserID, &key.Secret, &permissions, &key.CreatedAt, &revokedAt,
+	)
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return auth.APIKey{}, auth.ErrAPIKeyNotFound
+		}
+		return auth.APIKey{}, err
+	}
+	key.Permissions = splitPermissions(permissions)
+	if revokedAt.Valid {
+		key.RevokedAt = &revokedAt.Time
+	}
+	return key, nil
+}
+
+func (r *PostgresAPIKeyRepository) RevokeAPIKey(ctx context.Context, id string, revokedAt time.Time) error {
+	query := `
+        UPDATE api_keys
+        SET revoked_at = $2
+        WHERE id = $1 AND revoked_at IS NULL
+    `
+	res, err := executor(ctx, r.db).ExecContext(ctx, query, id, revokedAt)
+	if err != nil {
+		return err
+	}
+
+	rowsAffected, err := res.RowsAffected()
+	if err != nil {
+		return err
+	}
+	if rowsAffected == 0 {
+		return auth.ErrAPIKeyNotFound
+	}
+	return nil
+}
+
+func (r *PostgresAPIKeyRepository) RecordNonce(ctx context.Context, keyID, nonce string, expiresAt time.Time) error {
+	db := executor(ctx, r.db)
+
+	// Expired nonces can no longer be replayed because their timestamp is rejected first.
+	if _, err := db.ExecContext(ctx, `DELETE FROM api_key_nonces WHERE key_id = $1 AND expires_at < $2`, keyID, time.Now()); err != nil {
+		return err
+	}
+
+	query := `
+        INSERT INTO api_key_nonces (key_id, nonce, expires_at)
+        VALUES ($1, $2, $3)
+        ON CONFLICT (key_id, nonce) DO NOTHING
+    `
+	res, err := db.ExecContext(ctx, query, keyID, nonce, expiresAt)
+	if err != nil {
+		return err
+	}
+
+	rowsAffected, err := res.RowsAffected()
+	if err != nil {
+		return err
+	}
+	if rowsAffected == 0 {
+		return auth.ErrNonceReused
+	}
+	return nil
+}
+
+func joinPermissions(perms []auth.Permission) string {
+	s := make([]string, 0, len(perms))
+	for _, p := range perms {
+		s = append(s, string(p))
+	}
+	return strings.Join(s, ",")
+}
+
+func splitPermissions(s string) []auth.Permission {
+	if s == "" {
+		return nil
+	}
+	parts := strings.Split(s, ",")
+	perms := make([]auth.Permission, 0, len(parts))
+	for _, p := range parts {
+		perms = append(perms, auth.Permission(p))
+	}
+	return perms
+}
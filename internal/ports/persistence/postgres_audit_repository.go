@@ -0,0 +1,165 @@
+package persistence
+
+import (
+	"context"
+	"database/sql"
+	"encoding/json"
+
+	"exchange/internal/domain/audit"
+)
+
+// auditColumns lists the columns read by scanAuditEntry, in order.
+const auditColumns = `seq, actor, action, target, transaction_id, request_id, source_ip, balances, outcome, error, created_at, prev_hash, hash`
+
+func scanAuditEntry(row rowScanner) (audit.Entry, error) {
+	var e audit.Entry
+	var action, outcome string
+	var balances []byte
+	err := row.Scan(&e.Seq, &e.Actor, &action, &e.Target, &e.TransactionID, &e.RequestID, &e.SourceIP,
+		&balances, &outcome, &e.Error, &e.CreatedAt, &e.PrevHash, &e.Hash)
+	if err != nil {
+		return audit.Entry{}, err
+	}
+	e.Action = audit.Action(action)
+	e.Outcome = audit.Outcome(outcome)
+	if err := json.Unmarshal(balances, &e.Balances); err != nil {
+		return audit.Entry{}, err
+	}
+	if len(e.Balances) == 0 {
+		e.Balances = nil
+	}
+	return e, nil
+}
+
+type PostgresAuditRepository struct {
+	db        *sql.DB
+	txManager *PostgresTransactionManager
+}
+
+func NewPostgresAuditRepository(db *sql.DB) *PostgresAuditRepository {
+	return &PostgresAuditRepository{
+		db:        db,
+		txManager: NewPostgresTransactionManager(db),
+	}
+}
+
+// AppendEntry locks the chain head until the surrounding transaction ends, so concurrent
+// appends queue up behind it. Without a transaction in ctx it starts its own.
+func (r *PostgresAuditRepository) AppendEntry(ctx context.Context, e audit.Entry) (audit.Entry, error) {
+	err := r.txManager.Do(ctx, func(ctx context.Context) error {
+		exec := executor(ctx, r.db)
+
+		var prevSeq int64
+		var prevHash string
+		err := exec.QueryRowContext(ctx, `SELECT seq, hash FROM audit_chain_head WHERE id = 1 FOR UPDATE`).Scan(&prevSeq, &prevHash)
+		if err != nil {
+			return err
+		}
+
+		e = e.Seal(prevSeq, prevHash)
+		balances := e.Balances
+		if balances == nil {
+			balances = []audit.BalanceChange{}
+		}
+		balancesJSON, err := json.Marshal(balances)
+		if err != nil {
+			return err
+		}
+
+		query := `
+            INSERT INTO audit_log (` + auditColumns + `)
+            VALUES ($1, $2, $3, $4, $5, $6, $7, $8, $9, $10, $11, $12, $13)
+        `
+		_, err = exec.ExecContext(ctx, query,
+			e.Seq, e.Actor, string(e.Action), e.Target, e.TransactionID, e.RequestID, e.SourceIP,
+			balancesJSON, string(e.Outcome), e.Error, e.CreatedAt, e.PrevHash, e.Hash,
+		)
+		if err != nil {
+			return err
+		}
+
+		_, err = exec.ExecContext(ctx, `UPDATE audit_chain_head SET seq = $1, hash = $2 WHERE id = 1`, e.Seq, e.Hash)
+		return err
+	})
+	if err != nil {
+		return audit.Entry{}, err
+	}
+	return e, nil
+}
+
+func (r *PostgresAuditRepository) ListEntries(ctx context.Context, filter audit.Filter, limit, offset int) ([]audit.Entry, error) {
+	var f queryFilter
+	if filter.Actor != "" {
+		f.add("actor = $%[1]d", filter.Actor)
+	}
+	if filter.Action != "" {
+		f.add("action = $%[1]d", string(filter.Action))
+	}
+	if filter.UserID != "" {
+		contains, err := json.Marshal([]audit.BalanceChange{{UserID: filter.UserID}})
+		if err != nil {
+			return nil, err
+		}
+		f.add("balances @> $%[1]d::jsonb", string(contains))
+	}
+	if filter.TransactionID != "" {
+		f.add("transaction_id = $%[1]d", filter.TransactionID)
+	}
+	if filter.RequestID != "" {
+		f.add("request_id = $%[1]d", filter.RequestID)
+	}
+	if !filter.From.IsZero() {
+		f.add("created_at >= $%[1]d", filter.From)
+	}
+	if !filter.To.IsZero() {
+		f.add("created_at < $%[1]d", filter.To)
+	}
+
+	query := `
+        SELECT ` + auditColumns + `
+        FROM audit_log
+        ` + f.where() + `
+        ORDER BY seq DESC
+        ` + f.page(limit, offset)
+	rows, err := executor(ctx, r.db).QueryContext(ctx, query, f.args...)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var results []audit.Entry
+	for rows.Next() {
+		e, err := scanAuditEntry(rows)
+		if err != nil {
+			return nil, err
+		}
+		results = append(results, e)
+	}
+
+	return results, rows.Err()
+}
+
+func (r *PostgresAuditRepository) StreamEntries(ctx context.Context, fn func(audit.Entry) error) error {
+	query := `
+        SELECT ` + auditColumns + `
+        FROM audit_log
+        ORDER BY seq ASC
+    `
+	rows, err := executor(ctx, r.db).QueryContext(ctx, query)
+	if err != nil {
+		return err
+	}
+	defer rows.Close()
+
+	for rows.Next() {
+		e, err := scanAuditEntry(rows)
+		if err != nil {
+			return err
+		}
+		if err := fn(e); err != nil {
+			return err
+		}
+	}
+
+	return rows.Err()
+}
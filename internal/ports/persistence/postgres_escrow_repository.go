@@ -0,0 +1,141 @@
+package persistence
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"time"
+
+	"exchange/internal/domain/escrow"
+)
+
+// escrowColumns lists the columns read by scanEscrow, in order.
+const escrowColumns = `id, payer_id, payee_id, amount, currency, conditions, on_expiry, expires_at, status, COALESCE(disputed_by, ''), dispute_reason,
+        funding_transaction_id, COALESCE(settlement_transaction_id, ''), created_at, updated_at, settled_at`
+
+func scanEscrow(row rowScanner) (escrow.Escrow, error) {
+	var e escrow.Escrow
+	var onExpiry, status string
+	var settledAt sql.NullTime
+	err := row.Scan(&e.ID, &e.PayerID, &e.PayeeID, &e.Amount, &e.Currency, &e.Conditions, &onExpiry, &e.ExpiresAt, &status,
+		&e.DisputedBy, &e.DisputeReason, &e.FundingTransactionID, &e.SettlementTransactionID, &e.CreatedAt, &e.UpdatedAt, &settledAt)
+	if err != nil {
+		return escrow.Escrow{}, err
+	}
+	e.OnExpiry = escrow.ExpiryAction(onExpiry)
+	e.Status = escrow.Status(status)
+	if settledAt.Valid {
+		e.SettledAt = &settledAt.Time
+	}
+	return e, nil
+}
+
+type PostgresEscrowRepository struct {
+	db *sql.DB
+}
+
+func NewPostgresEscrowRepository(db *sql.DB) *PostgresEscrowRepository {
+	return &PostgresEscrowRepository{
+		db: db,
+	}
+}
+
+func (r *PostgresEscrowRepository) CreateEscrow(ctx context.Context, e escrow.Escrow) error {
+	query := `
+        INSERT INTO escrows (id, payer_id, payee_id, amount, currency, conditions, on_expiry, expires_at, status, funding_transaction_id, created_at, updated_at)
+        VALUES ($1, $2, $3, $4, $5, $6, $7, $8, $9, $10, $11, $12)
+    `
+	_, err := executor(ctx, r.db).ExecContext(ctx, query,
+		e.ID, e.PayerID, e.PayeeID, e.Amount, e.Currency, e.Conditions, string(e.OnExpiry), e.ExpiresAt, string(e.Status),
+		e.FundingTransactionID, e.CreatedAt, e.UpdatedAt,
+	)
+	return err
+}
+
+func (r *PostgresEscrowRepository) GetEscrowByID(ctx context.Context, id string) (escrow.Escrow, error) {
+	query := `
+        SELECT ` + escrowColumns + `
+        FROM escrows
+        WHERE id = $1
+    `
+	e, err := scanEscrow(executor(ctx, r.db).QueryRowContext(ctx, query, id))
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return escrow.Escrow{}, escrow.ErrEscrowNotFound
+		}
+		return escrow.Escrow{}, err
+	}
+	return e, nil
+}
+
+func (r *PostgresEscrowRepository) ListEscrows(ctx context.Context, filter escrow.Filter, limit, offset int) ([]escrow.Escrow, error) {
+	var f queryFilter
+	if filter.UserID != "" {
+		f.add("(payer_id = $%[1]d OR payee_id = $%[1]d)", filter.UserID)
+	}
+	if filter.Status != "" {
+		f.add("status = $%[1]d", string(filter.Status))
+	}
+
+	query := `
+        SELECT ` + escrowColumns + `
+        FROM escrows
+        ` + f.where() + `
+        ORDER BY created_at DESC, id DESC
+        ` + f.page(limit, offset)
+	return r.list(ctx, query, f.args...)
+}
+
+func (r *PostgresEscrowRepository) ListExpired(ctx context.Context, now time.Time, limit int) ([]escrow.Escrow, error) {
+	query := `
+        SELECT ` + escrowColumns + `
+        FROM escrows
+        WHERE status = 'funded' AND expires_at <= $1
+        ORDER BY expires_at, id
+        LIMIT $2
+    `
+	return r.list(ctx, query, now, limit)
+}
+
+func (r *PostgresEscrowRepository) list(ctx context.Context, query string, args ...any) ([]escrow.Escrow, error) {
+	rows, err := executor(ctx, r.db).QueryContext(ctx, query, args...)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var results []escrow.Escrow
+	for rows.Next() {
+		e, err := scanEscrow(rows)
+		if err != nil {
+			return nil, err
+		}
+		results = append(results, e)
+	}
+	return results, rows.Err()
+}
+
+func (r *PostgresEscrowRepository) UpdateEscrow(ctx context.Context, e escrow.Escrow, from escrow.Status) error {
+	query := `
+        UPDATE escrows
+        SET status = $3, disputed_by = NULLIF($4, ''), dispute_reason = $5, settlement_transaction_id = NULLIF($6, ''),
+            updated_at = $7, settled_at = $8
+        WHERE id = $1 AND status = $2
+    `
+	res, err := executor(ctx, r.db).ExecContext(ctx, query,
+		e.ID, string(from), string(e.Status), e.DisputedBy, e.DisputeReason, e.SettlementTransactionID, e.UpdatedAt, e.SettledAt,
+	)
+	if err != nil {
+		return err
+	}
+
+	rowsAffected, err := res.RowsAffected()
+	if err != nil {
+		return err
+	}
+	if rowsAffected == 0 {
+		return escrow.ErrEscrowSettled
+	}
+
+	return nil
+}
@@ -0,0 +1,147 @@
+package persistence
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"time"
+
+	"exchange/internal/domain/interest"
+
+	"github.com/jackc/pgx/v5/pgconn"
+)
+
+// interestPayoutColumns lists the columns read by scanInterestPayout, in order.
+const interestPayoutColumns = `user_id, currency, month, accrued, amount, carry, COALESCE(transaction_id, ''), created_at`
+
+func scanInterestPayout(row rowScanner) (interest.Payout, error) {
+	var p interest.Payout
+	err := row.Scan(&p.UserID, &p.Currency, &p.Month, &p.Accrued, &p.Amount, &p.Carry, &p.TransactionID, &p.CreatedAt)
+	if err != nil {
+		return interest.Payout{}, err
+	}
+	return p, nil
+}
+
+type PostgresInterestRepository struct {
+	db *sql.DB
+}
+
+func NewPostgresInterestRepository(db *sql.DB) *PostgresInterestRepository {
+	return &PostgresInterestRepository{
+		db: db,
+	}
+}
+
+func (r *PostgresInterestRepository) CreateAccrual(ctx context.Context, a interest.Accrual) error {
+	query := `
+        INSERT INTO interest_accruals (user_id, day, currency, balance, micros, created_at)
+        VALUES ($1, $2, $3, $4, $5, $6)
+    `
+	_, err := executor(ctx, r.db).ExecContext(ctx, query, a.UserID, a.Day, a.Currency, a.Balance, a.Micros, a.CreatedAt)
+	var pgErr *pgconn.PgError
+	if errors.As(err, &pgErr) && pgErr.Code == uniqueViolation {
+		return interest.ErrAlreadyAccrued
+	}
+	return err
+}
+
+func (r *PostgresInterestRepository) ListAccrualTotals(ctx context.Context, from, to time.Time) ([]interest.Total, error) {
+	query := `
+        SELECT user_id, currency, SUM(micros)
+        FROM interest_accruals
+        WHERE day >= $1 AND day < $2
+        GROUP BY user_id, currency
+        ORDER BY user_id, currency
+    `
+	rows, err := executor(ctx, r.db).QueryContext(ctx, query, from, to)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var totals []interest.Total
+	for rows.Next() {
+		var t interest.Total
+		if err := rows.Scan(&t.UserID, &t.Currency, &t.Micros); err != nil {
+			return nil, err
+		}
+		totals = append(totals, t)
+	}
+	return totals, rows.Err()
+}
+
+func (r *PostgresInterestRepository) CreatePayout(ctx context.Context, p interest.Payout) error {
+	query := `
+        INSERT INTO interest_payouts (user_id, month, currency, accrued, amount, carry, transaction_id, created_at)
+        VALUES ($1, $2, $3, $4, $5, $6, NULLIF($7, ''), $8)
+    `
+	_, err := executor(ctx, r.db).ExecContext(ctx, query,
+		p.UserID, p.Month, p.Currency, p.Accrued, p.Amount, p.Carry, p.TransactionID, p.CreatedAt,
+	)
+	var pgErr *pgconn.PgError
+	if errors.As(err, &pgErr) && pgErr.Code == uniqueViolation {
+		return interest.ErrAlreadyPaid
+	}
+	return err
+}
+
+func (r *PostgresInterestRepository) GetPayout(ctx context.Context, userID string, month time.Time) (interest.Payout, error) {
+	query := `
+        SELECT ` + interestPayoutColumns + `
+        FROM interest_payouts
+        WHERE user_id = $1 AND month = $2
+    `
+	return r.get(ctx, query, userID, month)
+}
+
+func (r *PostgresInterestRepository) GetLatestPayout(ctx context.Context, userID string, month time.Time) (interest.Payout, error) {
+	query := `
+        SELECT ` + interestPayoutColumns + `
+        FROM interest_payouts
+        WHERE user_id = $1 AND month < $2
+        ORDER BY month DESC
+        LIMIT 1
+    `
+	return r.get(ctx, query, userID, month)
+}
+
+func (r *PostgresInterestRepository) get(ctx context.Context, query string, args ...any) (interest.Payout, error) {
+	p, err := scanInterestPayout(executor(ctx, r.db).QueryRowContext(ctx, query, args...))
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return interest.Payout{}, interest.ErrPayoutNotFound
+		}
+		return interest.Payout{}, err
+	}
+	return p, nil
+}
+
+func (r *PostgresInterestRepository) ListPayouts(ctx context.Context, userID string, limit, offset int) ([]interest.Payout, error) {
+	var f queryFilter
+	if userID != "" {
+		f.add("user_id = $%[1]d", userID)
+	}
+
+	query := `
+        SELECT ` + interestPayoutColumns + `
+        FROM interest_payouts
+        ` + f.where() + `
+        ORDER BY month DESC, user_id
+        ` + f.page(limit, offset)
+	rows, err := executor(ctx, r.db).QueryContext(ctx, query, f.args...)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var payouts []interest.Payout
+	for rows.Next() {
+		p, err := scanInterestPayout(rows)
+		if err != nil {
+			return nil, err
+		}
+		payouts = append(payouts, p)
+	}
+	return payouts, rows.Err()
+}
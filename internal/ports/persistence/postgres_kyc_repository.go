@@ -0,0 +1,181 @@
+package persistence
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+
+	"exchange/internal/domain/kyc"
+)
+
+// kycSubmissionColumns lists the columns read by scanKYCSubmission, in order.
+const kycSubmissionColumns = `id, user_id, level, document_type, file_name, content_type, size, status, reason, COALESCE(reviewed_by, ''), created_at, reviewed_at`
+
+func scanKYCSubmission(row rowScanner) (kyc.Submission, error) {
+	var s kyc.Submission
+	var level, status string
+	var reviewedAt sql.NullTime
+	err := row.Scan(&s.ID, &s.UserID, &level, &s.DocumentType, &s.FileName, &s.ContentType, &s.Size, &status,
+		&s.Reason, &s.ReviewedBy, &s.CreatedAt, &reviewedAt)
+	if err != nil {
+		return kyc.Submission{}, err
+	}
+	s.Level = kyc.Level(level)
+	s.Status = kyc.SubmissionStatus(status)
+	if reviewedAt.Valid {
+		s.ReviewedAt = &reviewedAt.Time
+	}
+	return s, nil
+}
+
+type PostgresKYCRepository struct {
+	db *sql.DB
+}
+
+func NewPostgresKYCRepository(db *sql.DB) *PostgresKYCRepository {
+	return &PostgresKYCRepository{
+		db: db,
+	}
+}
+
+func (r *PostgresKYCRepository) GetLevel(ctx context.Context, userID string) (kyc.Level, error) {
+	var level string
+	err := executor(ctx, r.db).QueryRowContext(ctx, `SELECT kyc_level FROM users WHERE user_id = $1`, userID).Scan(&level)
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return kyc.LevelNone, nil
+		}
+		return "", err
+	}
+	return kyc.Level(level), nil
+}
+
+// SetLevel should run inside a database transaction so the level and its history stay
+// consistent.
+func (r *PostgresKYCRepository) SetLevel(ctx context.Context, change kyc.LevelChange) error {
+	exec := executor(ctx, r.db)
+	_, err := exec.ExecContext(ctx, `UPDATE users SET kyc_level = $2, updated_at = $3 WHERE user_id = $1`,
+		change.UserID, string(change.To), change.CreatedAt)
+	if err != nil {
+		return err
+	}
+
+	query := `
+        INSERT INTO kyc_level_changes (id, user_id, from_level, to_level, reason, changed_by, submission_id, created_at)
+        VALUES ($1, $2, $3, $4, $5, $6, NULLIF($7, ''), $8)
+    `
+	_, err = exec.ExecContext(ctx, query,
+		change.ID, change.UserID, string(change.From), string(change.To), change.Reason, change.ChangedBy, change.SubmissionID, change.CreatedAt,
+	)
+	return err
+}
+
+func (r *PostgresKYCRepository) ListLevelChanges(ctx context.Context, userID string) ([]kyc.LevelChange, error) {
+	query := `
+        SELECT id, user_id, from_level, to_level, reason, changed_by, COALESCE(submission_id, ''), created_at
+        FROM kyc_level_changes
+        WHERE user_id = $1
+        ORDER BY created_at ASC, id ASC
+    `
+	rows, err := executor(ctx, r.db).QueryContext(ctx, query, userID)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var results []kyc.LevelChange
+	for rows.Next() {
+		var c kyc.LevelChange
+		var from, to string
+		if err := rows.Scan(&c.ID, &c.UserID, &from, &to, &c.Reason, &c.ChangedBy, &c.SubmissionID, &c.CreatedAt); err != nil {
+			return nil, err
+		}
+		c.From = kyc.Level(from)
+		c.To = kyc.Level(to)
+		results = append(results, c)
+	}
+
+	return results, rows.Err()
+}
+
+func (r *PostgresKYCRepository) CreateSubmission(ctx context.Context, s kyc.Submission) error {
+	query := `
+        INSERT INTO kyc_submissions (id, user_id, level, document_type, file_name, content_type, size, status, created_at)
+        VALUES ($1, $2, $3, $4, $5, $6, $7, $8, $9)
+    `
+	_, err := executor(ctx, r.db).ExecContext(ctx, query,
+		s.ID, s.UserID, string(s.Level), s.DocumentType, s.FileName, s.ContentType, s.Size, string(s.Status), s.CreatedAt,
+	)
+	return err
+}
+
+func (r *PostgresKYCRepository) GetSubmissionByID(ctx context.Context, id string) (kyc.Submission, error) {
+	query := `
+        SELECT ` + kycSubmissionColumns + `
+        FROM kyc_submissions
+        WHERE id = $1
+    `
+	s, err := scanKYCSubmission(executor(ctx, r.db).QueryRowContext(ctx, query, id))
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return kyc.Submission{}, kyc.ErrSubmissionNotFound
+		}
+		return kyc.Submission{}, err
+	}
+	return s, nil
+}
+
+func (r *PostgresKYCRepository) ListSubmissions(ctx context.Context, filter kyc.Filter, limit, offset int) ([]kyc.Submission, error) {
+	var f queryFilter
+	if filter.UserID != "" {
+		f.add("user_id = $%[1]d", filter.UserID)
+	}
+	if filter.Status != "" {
+		f.add("status = $%[1]d", string(filter.Status))
+	}
+
+	query := `
+        SELECT ` + kycSubmissionColumns + `
+        FROM kyc_submissions
+        ` + f.where() + `
+        ORDER BY created_at DESC, id DESC
+        ` + f.page(limit, offset)
+	rows, err := executor(ctx, r.db).QueryContext(ctx, query, f.args...)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var results []kyc.Submission
+	for rows.Next() {
+		s, err := scanKYCSubmission(rows)
+		if err != nil {
+			return nil, err
+		}
+		results = append(results, s)
+	}
+
+	return results, rows.Err()
+}
+
+func (r *PostgresKYCRepository) ReviewSubmission(ctx context.Context, s kyc.Submission) error {
+	query := `
+        UPDATE kyc_submissions
+        SET status = $2, reason = $3, reviewed_by = $4, reviewed_at = $5
+        WHERE id = $1 AND status = 'pending'
+    `
+	res, err := executor(ctx, r.db).ExecContext(ctx, query, s.ID, string(s.Status), s.Reason, s.ReviewedBy, s.ReviewedAt)
+	if err != nil {
+		return err
+	}
+
+	rowsAffected, err := res.RowsAffected()
+	if err != nil {
+		return err
+	}
+	if rowsAffected == 0 {
+		return kyc.ErrSubmissionNotPending
+	}
+
+	return nil
+}
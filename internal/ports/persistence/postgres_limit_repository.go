@@ -0,0 +1,59 @@
+package persistence
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+
+	"exchange/internal/domain/limit"
+)
+
+type PostgresLimitRepository struct {
+	db *sql.DB
+}
+
+func NewPostgresLimitRepository(db *sql.DB) *PostgresLimitRepository {
+	return &PostgresLimitRepository{
+		db: db,
+	}
+}
+
+func (r *PostgresLimitRepository) GetTier(ctx context.Context, userID string) (limit.Tier, error) {
+	var tier string
+	err := executor(ctx, r.db).QueryRowContext(ctx, `SELECT tier FROM account_tiers WHERE user_id = $1`, userID).Scan(&tier)
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return "", nil
+		}
+		return "", err
+	}
+	return limit.Tier(tier), nil
+}
+
+func (r *PostgresLimitRepository) ListOverrides(ctx context.Context, userID string) ([]limit.Limit, error) {
+	query := `
+        SELECT operation, currency, period, max_amount, max_count
+        FROM limit_overrides
+        WHERE user_id = $1
+        ORDER BY operation, currency, period
+    `
+	rows, err := executor(ctx, r.db).QueryContext(ctx, query, userID)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var results []limit.Limit
+	for rows.Next() {
+		var l limit.Limit
+		var operation, period string
+		if err := rows.Scan(&operation, &l.Currency, &period, &l.MaxAmount, &l.MaxCount); err != nil {
+			return nil, err
+		}
+		l.Operation = limit.Operation(operation)
+		l.Period = limit.Period(period)
+		results = append(results, l)
+	}
+
+	return results, rows.Err()
+}
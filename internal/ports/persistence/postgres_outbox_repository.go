@@ -0,0 +1,86 @@
+package persistence
+
+import (
+	"context"
+	"database/sql"
+	"time"
+
+	"exchange/internal/domain/event"
+)
+
+// outboxRelayLock is the transaction-scoped advisory lock held by the relay that is
+// delivering events. Relays take turns rather than splitting the outbox between them,
+// since events of one aggregate published by two relays at once could arrive out of order.
+const outboxRelayLock = 0x6f7574626f78 // "outbox"
+
+type PostgresOutboxRepository struct {
+	db *sql.DB
+}
+
+func NewPostgresOutboxRepository(db *sql.DB) *PostgresOutboxRepository {
+	return &PostgresOutboxRepository{
+		db: db,
+	}
+}
+
+func (r *PostgresOutboxRepository) AppendEvent(ctx context.Context, e event.Event) error {
+	query := `
+        INSERT INTO outbox_events (id, type, aggregate_id, payload, occurred_at)
+        VALUES ($1, $2, $3, $4, $5)
+    `
+	_, err := executor(ctx, r.db).ExecContext(ctx, query, e.ID, string(e.Type), e.AggregateID, []byte(e.Payload), e.OccurredAt)
+	return err
+}
+
+// ListPendingEvents locks and returns the oldest pending events for the calling
+// transaction, or none while another relay holds the outbox.
+func (r *PostgresOutboxRepository) ListPendingEvents(ctx context.Context, limit int) ([]event.Event, error) {
+	var acquired bool
+	if err := executor(ctx, r.db).QueryRowContext(ctx, `SELECT pg_try_advisory_xact_lock($1)`, outboxRelayLock).Scan(&acquired); err != nil {
+		return nil, err
+	}
+	if !acquired {
+		return nil, nil
+	}
+
+	query := `
+        SELECT seq, id, type, aggregate_id, payload, occurred_at, attempts, last_error
+        FROM outbox_events
+        WHERE published_at IS NULL
+        ORDER BY seq ASC
+        LIMIT $1
+        FOR UPDATE
+    `
+	rows, err := executor(ctx, r.db).QueryContext(ctx, query, limit)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var results []event.Event
+	for rows.Next() {
+		var e event.Event
+		var eventType string
+		var payload []byte
+		if err := rows.Scan(&e.Seq, &e.ID, &eventType, &e.AggregateID, &payload, &e.OccurredAt, &e.Attempts, &e.LastError); err != nil {
+			return nil, err
+		}
+		e.Type = event.Type(eventType)
+		e.Payload = payload
+		results = append(results, e)
+	}
+
+	return results, rows.Err()
+}
+
+func (r *PostgresOutboxRepository) MarkEventPublished(ctx context.Context, id string, publishedAt time.Time) error {
+	query := `UPDATE outbox_events SET published_at = $2 WHERE id = $1`
+	_, err := executor(ctx, r.db).ExecContext(ctx, query, id, publishedAt)
+	return err
+}
+
+func (r *PostgresOutboxRepository) RecordEventFailure(ctx context.Context, id, lastError string) error {
+	query := `UPDATE outbox_events SET attempts = attempts + 1, last_error = $2 WHERE id = $1`
+	_, err := executor(ctx, r.db).ExecContext(ctx, query, id, lastError)
+	return err
+}
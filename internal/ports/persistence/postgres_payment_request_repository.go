@@ -0,0 +1,152 @@
+package persistence
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"time"
+
+	"exchange/internal/domain/payment"
+)
+
+// paymentRequestColumns lists the columns read by scanPaymentRequest, in order.
+const paymentRequestColumns = `id, token, requester_id, COALESCE(payer_id, ''), amount, currency, memo, expires_at, status,
+        COALESCE(paid_by, ''), COALESCE(transaction_id, ''), created_at, updated_at, closed_at`
+
+func scanPaymentRequest(row rowScanner) (payment.Request, error) {
+	var r payment.Request
+	var status string
+	var closedAt sql.NullTime
+	err := row.Scan(&r.ID, &r.Token, &r.RequesterID, &r.PayerID, &r.Amount, &r.Currency, &r.Memo, &r.ExpiresAt, &status,
+		&r.PaidBy, &r.TransactionID, &r.CreatedAt, &r.UpdatedAt, &closedAt)
+	if err != nil {
+		return payment.Request{}, err
+	}
+	r.Status = payment.Status(status)
+	if closedAt.Valid {
+		r.ClosedAt = &closedAt.Time
+	}
+	return r, nil
+}
+
+type PostgresPaymentRequestRepository struct {
+	db *sql.DB
+}
+
+func NewPostgresPaymentRequestRepository(db *sql.DB) *PostgresPaymentRequestRepository {
+	return &PostgresPaymentRequestRepository{
+		db: db,
+	}
+}
+
+func (r *PostgresPaymentRequestRepository) CreateRequest(ctx context.Context, req payment.Request) error {
+	query := `
+        INSERT INTO payment_requests (id, token, requester_id, payer_id, amount, currency, memo, expires_at, status, created_at, updated_at)
+        VALUES ($1, $2, $3, NULLIF($4, ''), $5, $6, $7, $8, $9, $10, $11)
+    `
+	_, err := executor(ctx, r.db).ExecContext(ctx, query,
+		req.ID, req.Token, req.RequesterID, req.PayerID, req.Amount, req.Currency, req.Memo, req.ExpiresAt, string(req.Status),
+		req.CreatedAt, req.UpdatedAt,
+	)
+	return err
+}
+
+func (r *PostgresPaymentRequestRepository) GetRequestByID(ctx context.Context, id string) (payment.Request, error) {
+	query := `
+        SELECT ` + paymentRequestColumns + `
+        FROM payment_requests
+        WHERE id = $1
+    `
+	return r.get(ctx, query, id)
+}
+
+func (r *PostgresPaymentRequestRepository) GetRequestByToken(ctx context.Context, token string) (payment.Request, error) {
+	query := `
+        SELECT ` + paymentRequestColumns + `
+        FROM payment_requests
+        WHERE token = $1
+    `
+	return r.get(ctx, query, token)
+}
+
+func (r *PostgresPaymentRequestRepository) get(ctx context.Context, query string, args ...any) (payment.Request, error) {
+	req, err := scanPaymentRequest(executor(ctx, r.db).QueryRowContext(ctx, query, args...))
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return payment.Request{}, payment.ErrRequestNotFound
+		}
+		return payment.Request{}, err
+	}
+	return req, nil
+}
+
+func (r *PostgresPaymentRequestRepository) ListRequests(ctx context.Context, filter payment.Filter, limit, offset int) ([]payment.Request, error) {
+	var f queryFilter
+	if filter.UserID != "" {
+		f.add("(requester_id = $%[1]d OR payer_id = $%[1]d OR paid_by = $%[1]d)", filter.UserID)
+	}
+	if filter.Status != "" {
+		f.add("status = $%[1]d", string(filter.Status))
+	}
+
+	query := `
+        SELECT ` + paymentRequestColumns + `
+        FROM payment_requests
+        ` + f.where() + `
+        ORDER BY created_at DESC, id DESC
+        ` + f.page(limit, offset)
+	return r.list(ctx, query, f.args...)
+}
+
+func (r *PostgresPaymentRequestRepository) ListExpired(ctx context.Context, now time.Time, limit int) ([]payment.Request, error) {
+	query := `
+        SELECT ` + paymentRequestColumns + `
+        FROM payment_requests
+        WHERE status = 'pending' AND expires_at <= $1
+        ORDER BY expires_at, id
+        LIMIT $2
+    `
+	return r.list(ctx, query, now, limit)
+}
+
+func (r *PostgresPaymentRequestRepository) list(ctx context.Context, query string, args ...any) ([]payment.Request, error) {
+	rows, err := executor(ctx, r.db).QueryContext(ctx, query, args...)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var results []payment.Request
+	for rows.Next() {
+		req, err := scanPaymentRequest(rows)
+		if err != nil {
+			return nil, err
+		}
+		results = append(results, req)
+	}
+	return results, rows.Err()
+}
+
+func (r *PostgresPaymentRequestRepository) UpdateRequest(ctx context.Context, req payment.Request, from payment.Status) error {
+	query := `
+        UPDATE payment_requests
+        SET status = $3, paid_by = NULLIF($4, ''), transaction_id = NULLIF($5, ''), updated_at = $6, closed_at = $7
+        WHERE id = $1 AND status = $2
+    `
+	res, err := executor(ctx, r.db).ExecContext(ctx, query,
+		req.ID, string(from), string(req.Status), req.PaidBy, req.TransactionID, req.UpdatedAt, req.ClosedAt,
+	)
+	if err != nil {
+		return err
+	}
+
+	rowsAffected, err := res.RowsAffected()
+	if err != nil {
+		return err
+	}
+	if rowsAffected == 0 {
+		return payment.ErrRequestClosed
+	}
+
+	return nil
+}
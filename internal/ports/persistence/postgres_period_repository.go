@@ -0,0 +1,87 @@
+package persistence
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+
+	"exchange/internal/domain/period"
+
+	"github.com/jackc/pgx/v5/pgconn"
+)
+
+// periodCloseColumns lists the columns read by scanPeriodClose, in order.
+const periodCloseColumns = `period_start, period_end, closed_by, closed_at`
+
+func scanPeriodClose(row rowScanner) (period.Close, error) {
+	var c period.Close
+	if err := row.Scan(&c.Start, &c.End, &c.ClosedBy, &c.ClosedAt); err != nil {
+		return period.Close{}, err
+	}
+	return c, nil
+}
+
+type PostgresPeriodRepository struct {
+	db *sql.DB
+}
+
+func NewPostgresPeriodRepository(db *sql.DB) *PostgresPeriodRepository {
+	return &PostgresPeriodRepository{
+		db: db,
+	}
+}
+
+func (r *PostgresPeriodRepository) CreateClose(ctx context.Context, c period.Close) error {
+	query := `
+        INSERT INTO period_closes (period_start, period_end, closed_by, closed_at)
+        VALUES ($1, $2, $3, $4)
+    `
+	_, err := executor(ctx, r.db).ExecContext(ctx, query, c.Start, c.End, c.ClosedBy, c.ClosedAt)
+	var pgErr *pgconn.PgError
+	if errors.As(err, &pgErr) && pgErr.Code == uniqueViolation {
+		return period.ErrAlreadyClosed
+	}
+	return err
+}
+
+func (r *PostgresPeriodRepository) GetLatestClose(ctx context.Context) (period.Close, error) {
+	query := `
+        SELECT ` + periodCloseColumns + `
+        FROM period_closes
+        ORDER BY period_end DESC
+        LIMIT 1
+    `
+	c, err := scanPeriodClose(executor(ctx, r.db).QueryRowContext(ctx, query))
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return period.Close{}, period.ErrCloseNotFound
+		}
+		return period.Close{}, err
+	}
+	return c, nil
+}
+
+func (r *PostgresPeriodRepository) ListCloses(ctx context.Context, limit, offset int) ([]period.Close, error) {
+	var f queryFilter
+	query := `
+        SELECT ` + periodCloseColumns + `
+        FROM period_closes
+        ORDER BY period_end DESC
+        ` + f.page(limit, offset)
+	rows, err := executor(ctx, r.db).QueryContext(ctx, query, f.args...)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var results []period.Close
+	for rows.Next() {
+		c, err := scanPeriodClose(rows)
+		if err != nil {
+			return nil, err
+		}
+		results = append(results, c)
+	}
+
+	return results, rows.Err()
+}
@@ -0,0 +1,125 @@
+package persistence
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"time"
+
+	"exchange/internal/domain/reserves"
+
+	"github.com/jackc/pgx/v5/pgconn"
+)
+
+// liabilityRootColumns lists the columns read by scanLiabilityRoot, in order.
+const liabilityRootColumns = `currency, root_hash, total, wallets, taken_at`
+
+func scanLiabilityRoot(row rowScanner) (reserves.Root, error) {
+	var root reserves.Root
+	if err := row.Scan(&root.Currency, &root.Hash, &root.Total, &root.Wallets, &root.TakenAt); err != nil {
+		return reserves.Root{}, err
+	}
+	return root, nil
+}
+
+type PostgresReservesRepository struct {
+	db *sql.DB
+}
+
+func NewPostgresReservesRepository(db *sql.DB) *PostgresReservesRepository {
+	return &PostgresReservesRepository{
+		db: db,
+	}
+}
+
+// CreateTree inserts the root before its leaves, so callers should run it in a
+// transaction to publish both or neither.
+func (r *PostgresReservesRepository) CreateTree(ctx context.Context, root reserves.Root, leaves []reserves.Leaf) error {
+	query := `
+        INSERT INTO liability_roots (currency, taken_at, root_hash, total, wallets)
+        VALUES ($1, $2, $3, $4, $5)
+    `
+	_, err := executor(ctx, r.db).ExecContext(ctx, query, root.Currency, root.TakenAt, root.Hash, root.Total, root.Wallets)
+	var pgErr *pgconn.PgError
+	if errors.As(err, &pgErr) && pgErr.Code == uniqueViolation {
+		return reserves.ErrAlreadyPublished
+	}
+	if err != nil {
+		return err
+	}
+
+	query = `
+        INSERT INTO liability_leaves (currency, taken_at, user_id, balance, nonce)
+        VALUES ($1, $2, $3, $4, $5)
+    `
+	for _, l := range leaves {
+		if _, err := executor(ctx, r.db).ExecContext(ctx, query, root.Currency, root.TakenAt, l.UserID, l.Balance, l.Nonce); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func (r *PostgresReservesRepository) GetLatestRoot(ctx context.Context, currency string) (reserves.Root, error) {
+	query := `
+        SELECT ` + liabilityRootColumns + `
+        FROM liability_roots
+        WHERE currency = $1
+        ORDER BY taken_at DESC
+        LIMIT 1
+    `
+	root, err := scanLiabilityRoot(executor(ctx, r.db).QueryRowContext(ctx, query, currency))
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return reserves.Root{}, reserves.ErrRootNotFound
+		}
+		return reserves.Root{}, err
+	}
+	return root, nil
+}
+
+func (r *PostgresReservesRepository) ListLatestRoots(ctx context.Context) ([]reserves.Root, error) {
+	query := `
+        SELECT DISTINCT ON (currency) ` + liabilityRootColumns + `
+        FROM liability_roots
+        ORDER BY currency, taken_at DESC
+    `
+	rows, err := executor(ctx, r.db).QueryContext(ctx, query)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var roots []reserves.Root
+	for rows.Next() {
+		root, err := scanLiabilityRoot(rows)
+		if err != nil {
+			return nil, err
+		}
+		roots = append(roots, root)
+	}
+	return roots, rows.Err()
+}
+
+func (r *PostgresReservesRepository) ListLeaves(ctx context.Context, currency string, takenAt time.Time) ([]reserves.Leaf, error) {
+	query := `
+        SELECT user_id, balance, nonce
+        FROM liability_leaves
+        WHERE currency = $1 AND taken_at = $2
+    `
+	rows, err := executor(ctx, r.db).QueryContext(ctx, query, currency, takenAt)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var leaves []reserves.Leaf
+	for rows.Next() {
+		var l reserves.Leaf
+		if err := rows.Scan(&l.UserID, &l.Balance, &l.Nonce); err != nil {
+			return nil, err
+		}
+		leaves = append(leaves, l)
+	}
+	return leaves, rows.Err()
+}
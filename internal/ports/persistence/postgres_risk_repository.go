@@ -0,0 +1,137 @@
+package persistence
+
+import (
+	"context"
+	"database/sql"
+	"encoding/json"
+	"errors"
+	"time"
+
+	"exchange/internal/domain/risk"
+	"exchange/internal/domain/transaction"
+)
+
+// riskAssessmentColumns lists the columns read by scanRiskAssessment, in order.
+const riskAssessmentColumns = `id, user_id, operation, counterparty_id, amount, currency, decision, rule_ids, created_at`
+
+func scanRiskAssessment(row rowScanner) (risk.Assessment, error) {
+	var a risk.Assessment
+	var operation, decision string
+	var ruleIDs []byte
+	err := row.Scan(&a.ID, &a.UserID, &operation, &a.CounterpartyID, &a.Amount, &a.Currency, &decision, &ruleIDs, &a.CreatedAt)
+	if err != nil {
+		return risk.Assessment{}, err
+	}
+	a.Operation = risk.Operation(operation)
+	a.Decision = risk.Decision(decision)
+	if err := json.Unmarshal(ruleIDs, &a.RuleIDs); err != nil {
+		return risk.Assessment{}, err
+	}
+	if len(a.RuleIDs) == 0 {
+		a.RuleIDs = nil
+	}
+	return a, nil
+}
+
+type PostgresRiskRepository struct {
+	db *sql.DB
+}
+
+func NewPostgresRiskRepository(db *sql.DB) *PostgresRiskRepository {
+	return &PostgresRiskRepository{
+		db: db,
+	}
+}
+
+// GetAvailableBalance returns 0 for a user without a wallet; the operation being assessed
+// reports the missing wallet itself.
+func (r *PostgresRiskRepository) GetAvailableBalance(ctx context.Context, userID string) (int64, error) {
+	var available int64
+	err := executor(ctx, r.db).QueryRowContext(ctx, `SELECT balance - held FROM wallets WHERE user_id = $1`, userID).Scan(&available)
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return 0, nil
+		}
+		return 0, err
+	}
+	return available, nil
+}
+
+func (r *PostgresRiskRepository) ListTransactionsSince(ctx context.Context, userID string, since time.Time) ([]transaction.Transaction, error) {
+	query := `
+        SELECT ` + transactionColumns + `
+        FROM transactions
+        WHERE (from_user_id = $1 OR to_user_id = $1)
+          AND created_at >= $2
+          AND status NOT IN ('failed', 'cancelled')
+        ORDER BY created_at ASC, id ASC
+    `
+	rows, err := executor(ctx, r.db).QueryContext(ctx, query, userID, since)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var results []transaction.Transaction
+	for rows.Next() {
+		tx, err := scanTransaction(rows)
+		if err != nil {
+			return nil, err
+		}
+		results = append(results, tx)
+	}
+
+	return results, rows.Err()
+}
+
+func (r *PostgresRiskRepository) CreateAssessment(ctx context.Context, a risk.Assessment) error {
+	ruleIDs := a.RuleIDs
+	if ruleIDs == nil {
+		ruleIDs = []string{}
+	}
+	encoded, err := json.Marshal(ruleIDs)
+	if err != nil {
+		return err
+	}
+
+	query := `
+        INSERT INTO risk_assessments (` + riskAssessmentColumns + `)
+        VALUES ($1, $2, $3, $4, $5, $6, $7, $8, $9)
+    `
+	_, err = executor(ctx, r.db).ExecContext(ctx, query,
+		a.ID, a.UserID, string(a.Operation), a.CounterpartyID, a.Amount, a.Currency, string(a.Decision), encoded, a.CreatedAt)
+	return err
+}
+
+func (r *PostgresRiskRepository) ListAssessments(ctx context.Context, filter risk.Filter, limit, offset int) ([]risk.Assessment, error) {
+	var f queryFilter
+	if filter.UserID != "" {
+		f.add("user_id = $%[1]d", filter.UserID)
+	}
+	if filter.Decision != "" {
+		f.add("decision = $%[1]d", string(filter.Decision))
+	}
+
+	query := `
+        SELECT ` + riskAssessmentColumns + `
+        FROM risk_assessments
+        ` + f.where() + `
+        ORDER BY created_at DESC, id DESC
+        ` + f.page(limit, offset)
+	rows, err := executor(ctx, r.db).QueryContext(ctx, query, f.args...)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var results []risk.Assessment
+	for rows.Next() {
+		a, err := scanRiskAssessment(rows)
+		if err != nil {
+			return nil, err
+		}
+		results = append(results, a)
+	}
+
+	return results, rows.Err()
+}
@@ -0,0 +1,139 @@
+package persistence
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+
+	"exchange/internal/domain/sanctions"
+)
+
+// sanctionsCaseColumns lists the columns read by scanSanctionsCase, in order.
+const sanctionsCaseColumns = `id, subject_type, subject_id, subject_name, entry_id, entry_name, score, operation, status, reason, COALESCE(cleared_by, ''), created_at, cleared_at`
+
+func scanSanctionsCase(row rowScanner) (sanctions.Case, error) {
+	var c sanctions.Case
+	var subjectType, status string
+	var clearedAt sql.NullTime
+	err := row.Scan(&c.ID, &subjectType, &c.SubjectID, &c.SubjectName, &c.EntryID, &c.EntryName, &c.Score, &c.Operation,
+		&status, &c.Reason, &c.ClearedBy, &c.CreatedAt, &clearedAt)
+	if err != nil {
+		return sanctions.Case{}, err
+	}
+	c.SubjectType = sanctions.SubjectType(subjectType)
+	c.Status = sanctions.CaseStatus(status)
+	if clearedAt.Valid {
+		c.ClearedAt = &clearedAt.Time
+	}
+	return c, nil
+}
+
+type PostgresSanctionsRepository struct {
+	db *sql.DB
+}
+
+func NewPostgresSanctionsRepository(db *sql.DB) *PostgresSanctionsRepository {
+	return &PostgresSanctionsRepository{
+		db: db,
+	}
+}
+
+func (r *PostgresSanctionsRepository) CreateCase(ctx context.Context, c sanctions.Case) error {
+	query := `
+        INSERT INTO sanctions_cases (id, subject_type, subject_id, subject_name, entry_id, entry_name, score, operation, status, created_at)
+        VALUES ($1, $2, $3, $4, $5, $6, $7, $8, $9, $10)
+    `
+	_, err := executor(ctx, r.db).ExecContext(ctx, query,
+		c.ID, string(c.SubjectType), c.SubjectID, c.SubjectName, c.EntryID, c.EntryName, c.Score, c.Operation, string(c.Status), c.CreatedAt,
+	)
+	return err
+}
+
+func (r *PostgresSanctionsRepository) GetCaseByID(ctx context.Context, id string) (sanctions.Case, error) {
+	query := `
+        SELECT ` + sanctionsCaseColumns + `
+        FROM sanctions_cases
+        WHERE id = $1
+    `
+	c, err := scanSanctionsCase(executor(ctx, r.db).QueryRowContext(ctx, query, id))
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return sanctions.Case{}, sanctions.ErrCaseNotFound
+		}
+		return sanctions.Case{}, err
+	}
+	return c, nil
+}
+
+func (r *PostgresSanctionsRepository) FindCase(ctx context.Context, subjectType sanctions.SubjectType, subjectID, entryID string) (sanctions.Case, error) {
+	query := `
+        SELECT ` + sanctionsCaseColumns + `
+        FROM sanctions_cases
+        WHERE subject_type = $1 AND subject_id = $2 AND entry_id = $3
+        ORDER BY created_at DESC, id DESC
+        LIMIT 1
+    `
+	c, err := scanSanctionsCase(executor(ctx, r.db).QueryRowContext(ctx, query, string(subjectType), subjectID, entryID))
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return sanctions.Case{}, sanctions.ErrCaseNotFound
+		}
+		return sanctions.Case{}, err
+	}
+	return c, nil
+}
+
+func (r *PostgresSanctionsRepository) ListCases(ctx context.Context, filter sanctions.Filter, limit, offset int) ([]sanctions.Case, error) {
+	var f queryFilter
+	if filter.SubjectID != "" {
+		f.add("subject_id = $%[1]d", filter.SubjectID)
+	}
+	if filter.Status != "" {
+		f.add("status = $%[1]d", string(filter.Status))
+	}
+
+	query := `
+        SELECT ` + sanctionsCaseColumns + `
+        FROM sanctions_cases
+        ` + f.where() + `
+        ORDER BY created_at DESC, id DESC
+        ` + f.page(limit, offset)
+	rows, err := executor(ctx, r.db).QueryContext(ctx, query, f.args...)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var results []sanctions.Case
+	for rows.Next() {
+		c, err := scanSanctionsCase(rows)
+		if err != nil {
+			return nil, err
+		}
+		results = append(results, c)
+	}
+
+	return results, rows.Err()
+}
+
+func (r *PostgresSanctionsRepository) ClearCase(ctx context.Context, c sanctions.Case) error {
+	query := `
+        UPDATE sanctions_cases
+        SET status = $2, reason = $3, cleared_by = $4, cleared_at = $5
+        WHERE id = $1 AND status = 'open'
+    `
+	res, err := executor(ctx, r.db).ExecContext(ctx, query, c.ID, string(c.Status), c.Reason, c.ClearedBy, c.ClearedAt)
+	if err != nil {
+		return err
+	}
+
+	rowsAffected, err := res.RowsAffected()
+	if err != nil {
+		return err
+	}
+	if rowsAffected == 0 {
+		return sanctions.ErrCaseNotOpen
+	}
+
+	return nil
+}
@@ -0,0 +1,83 @@
+package persistence
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"time"
+
+	"exchange/internal/domain/snapshot"
+)
+
+type PostgresSnapshotRepository struct {
+	db *sql.DB
+}
+
+func NewPostgresSnapshotRepository(db *sql.DB) *PostgresSnapshotRepository {
+	return &PostgresSnapshotRepository{
+		db: db,
+	}
+}
+
+func (r *PostgresSnapshotRepository) CreateSnapshot(ctx context.Context, s snapshot.Snapshot) error {
+	query := `
+        INSERT INTO balance_snapshots (user_id, currency, balance, taken_at)
+        VALUES ($1, $2, $3, $4)
+        ON CONFLICT (user_id, taken_at) DO NOTHING
+    `
+	_, err := executor(ctx, r.db).ExecContext(ctx, query, s.UserID, s.Currency, s.Balance, s.TakenAt)
+	return err
+}
+
+func (r *PostgresSnapshotRepository) GetLatestSnapshot(ctx context.Context, userID string, at time.Time) (snapshot.Snapshot, error) {
+	query := `
+        SELECT user_id, currency, balance, taken_at
+        FROM balance_snapshots
+        WHERE user_id = $1 AND taken_at <= $2
+        ORDER BY taken_at DESC
+        LIMIT 1
+    `
+	var s snapshot.Snapshot
+	err := executor(ctx, r.db).QueryRowContext(ctx, query, userID, at).Scan(&s.UserID, &s.Currency, &s.Balance, &s.TakenAt)
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return snapshot.Snapshot{}, snapshot.ErrSnapshotNotFound
+		}
+		return snapshot.Snapshot{}, err
+	}
+	return s, nil
+}
+
+func (r *PostgresSnapshotRepository) CreateTotal(ctx context.Context, t snapshot.Total) error {
+	query := `
+        INSERT INTO balance_totals (currency, taken_at, balance, wallets)
+        VALUES ($1, $2, $3, $4)
+        ON CONFLICT (taken_at, currency) DO NOTHING
+    `
+	_, err := executor(ctx, r.db).ExecContext(ctx, query, t.Currency, t.TakenAt, t.Balance, t.Wallets)
+	return err
+}
+
+func (r *PostgresSnapshotRepository) ListTotals(ctx context.Context, at time.Time) ([]snapshot.Total, error) {
+	query := `
+        SELECT currency, balance, wallets, taken_at
+        FROM balance_totals
+        WHERE taken_at = $1
+        ORDER BY currency
+    `
+	rows, err := executor(ctx, r.db).QueryContext(ctx, query, at)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var totals []snapshot.Total
+	for rows.Next() {
+		var t snapshot.Total
+		if err := rows.Scan(&t.Currency, &t.Balance, &t.Wallets, &t.TakenAt); err != nil {
+			return nil, err
+		}
+		totals = append(totals, t)
+	}
+	return totals, rows.Err()
+}
@@ -14,8 +14,25 @@ func NewPostgresTransactionManager(db *sql.DB) *PostgresTransactionManager {
 	return &PostgresTransactionManager{db: db}
 }
 
+// Do runs fn inside a database transaction. When ctx already carries a transaction, fn
+// joins it instead of starting a new one, so use cases can be composed atomically.
 func (tm *PostgresTransactionManager) Do(ctx context.Context, fn func(ctx context.Context) error) error {
-	tx, err := tm.db.BeginTx(ctx, nil)
+	return tm.run(ctx, nil, fn)
+}
+
+// ReadOnly runs fn inside a read-only REPEATABLE READ transaction, so every query of fn
+// sees the same snapshot of the database. Like Do, fn joins a transaction ctx already
+// carries.
+func (tm *PostgresTransactionManager) ReadOnly(ctx context.Context, fn func(ctx context.Context) error) error {
+	return tm.run(ctx, &sql.TxOptions{Isolation: sql.LevelRepeatableRead, ReadOnly: true}, fn)
+}
+
+func (tm *PostgresTransactionManager) run(ctx context.Context, opts *sql.TxOptions, fn func(ctx context.Context) error) error {
+	if _, ok := GetTxFromContext(ctx); ok {
+		return fn(ctx)
+	}
+
+	tx, err := tm.db.BeginTx(ctx, opts)
 	if err != nil {
 		return fmt.Errorf("failed to begin transaction: %w", err)
 	}
@@ -47,3 +64,19 @@ func GetTxFromContext(ctx context.Context) (*sql.Tx, bool) {
 	tx, ok := v.(*sql.Tx)
 	return tx, ok
 }
+
+// dbExecutor is the subset of *sql.DB and *sql.Tx used by the repositories.
+type dbExecutor interface {
+	ExecContext(ctx context.Context, query string, args ...any) (sql.Result, error)
+	QueryContext(ctx context.Context, query string, args ...any) (*sql.Rows, error)
+	QueryRowContext(ctx context.Context, query string, args ...any) *sql.Row
+}
+
+// executor returns the transaction started by PostgresTransactionManager.Do if ctx carries
+// one, and db otherwise.
+func executor(ctx context.Context, db *sql.DB) dbExecutor {
+	if tx, ok := GetTxFromContext(ctx); ok {
+		return tx
+	}
+	return db
+}
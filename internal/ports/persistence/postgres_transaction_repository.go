@@ -4,10 +4,44 @@ import (
 	"context"
 	"database/sql"
 	"errors"
+	"time"
 
 	"exchange/internal/domain/transaction"
 )
 
+// transactionColumns lists the columns read by scanTransaction, in order.
+const transactionColumns = `id, from_user_id, to_user_id, amount, currency, type, COALESCE(batch_id, ''), COALESCE(original_id, ''),
+        status, status_reason, created_at, processing_at, completed_at, failed_at, cancelled_at`
+
+type rowScanner interface {
+	Scan(dest ...any) error
+}
+
+func scanTransaction(row rowScanner) (transaction.Transaction, error) {
+	var tx transaction.Transaction
+	var tType, status string
+	var processingAt, completedAt, failedAt, cancelledAt sql.NullTime
+	err := row.Scan(&tx.ID, &tx.FromUserID, &tx.ToUserID, &tx.Amount, &tx.Currency, &tType, &tx.BatchID, &tx.OriginalID,
+		&status, &tx.StatusReason, &tx.CreatedAt, &processingAt, &completedAt, &failedAt, &cancelledAt)
+	if err != nil {
+		return transaction.Transaction{}, err
+	}
+	tx.Type = transaction.TransactionType(tType)
+	tx.Status = transaction.Status(status)
+	tx.ProcessingAt = timePtr(processingAt)
+	tx.CompletedAt = timePtr(completedAt)
+	tx.FailedAt = timePtr(failedAt)
+	tx.CancelledAt = timePtr(cancelledAt)
+	return tx, nil
+}
+
+func timePtr(t sql.NullTime) *time.Time {
+	if !t.Valid {
+		return nil
+	}
+	return &t.Time
+}
+
 type PostgresTransactionRepository struct {
 	db *sql.DB
 }
@@ -20,45 +54,133 @@ func NewPostgresTransactionRepository(db *sql.DB) *PostgresTransactionRepository
 
 func (r *PostgresTransactionRepository) CreateTransaction(ctx context.Context, tx transaction.Transaction) error {
 	query := `
-        INSERT INTO transactions (id, from_user_id, to_user_id, amount, currency, type, created_at)
-        VALUES ($1, $2, $3, $4, $5, $6, $7)
+        INSERT INTO transactions (id, from_user_id, to_user_id, amount, currency, type, batch_id, original_id, status, status_reason, created_at, completed_at)
+        VALUES ($1, $2, $3, $4, $5, $6, NULLIF($7, ''), NULLIF($8, ''), $9, $10, $11, $12)
     `
-	_, err := r.db.ExecContext(ctx, query,
-		tx.ID, tx.FromUserID, tx.ToUserID, tx.Amount, tx.Currency, string(tx.Type), tx.CreatedAt,
+	_, err := executor(ctx, r.db).ExecContext(ctx, query,
+		tx.ID, tx.FromUserID, tx.ToUserID, tx.Amount, tx.Currency, string(tx.Type), tx.BatchID, tx.OriginalID,
+		string(tx.Status), tx.StatusReason, tx.CreatedAt, tx.CompletedAt,
 	)
 	return err
 }
 
+func (r *PostgresTransactionRepository) UpdateTransactionStatus(ctx context.Context, tx transaction.Transaction) error {
+	query := `
+        UPDATE transactions
+        SET status = $2, status_reason = $3, processing_at = $4, completed_at = $5, failed_at = $6, cancelled_at = $7
+        WHERE id = $1
+    `
+	res, err := executor(ctx, r.db).ExecContext(ctx, query,
+		tx.ID, string(tx.Status), tx.StatusReason, tx.ProcessingAt, tx.CompletedAt, tx.FailedAt, tx.CancelledAt,
+	)
+	if err != nil {
+		return err
+	}
+
+	rowsAffected, err := res.RowsAffected()
+	if err != nil {
+		return err
+	}
+	if rowsAffected == 0 {
+		return transaction.ErrTransactionNotFound
+	}
+	return nil
+}
+
 func (r *PostgresTransactionRepository) GetTransactionByID(ctx context.Context, id string) (transaction.Transaction, error) {
 	query := `
-        SELECT id, from_user_id, to_user_id, amount, currency, type, created_at
+        SELECT ` + transactionColumns + `
         FROM transactions
         WHERE id = $1
     `
-	var tx transaction.Transaction
-	var tType string
-	err := r.db.QueryRowContext(ctx, query, id).Scan(
-		&tx.ID, &tx.FromUserID, &tx.ToUserID, &tx.Amount, &tx.Currency, &tType, &tx.CreatedAt,
-	)
+	tx, err := scanTransaction(executor(ctx, r.db).QueryRowContext(ctx, query, id))
 	if err != nil {
 		if errors.Is(err, sql.ErrNoRows) {
 			return transaction.Transaction{}, transaction.ErrTransactionNotFound
 		}
 		return transaction.Transaction{}, err
 	}
-	tx.Type = transaction.TransactionType(tType)
 	return tx, nil
 }
 
+func (r *PostgresTransactionRepository) LockTransactionByID(ctx context.Context, id string) (transaction.Transaction, error) {
+	query := `
+        SELECT ` + transactionColumns + `
+        FROM transactions
+        WHERE id = $1
+        FOR UPDATE
+    `
+	tx, err := scanTransaction(executor(ctx, r.db).QueryRowContext(ctx, query, id))
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return transaction.Transaction{}, transaction.ErrTransactionNotFound
+		}
+		return transaction.Transaction{}, err
+	}
+	return tx, nil
+}
+
+func (r *PostgresTransactionRepository) ListTransactionsByOriginalID(ctx context.Context, originalID string) ([]transaction.Transaction, error) {
+	query := `
+        SELECT ` + transactionColumns + `
+        FROM transactions
+        WHERE original_id = $1
+        ORDER BY created_at ASC, id ASC
+    `
+	rows, err := executor(ctx, r.db).QueryContext(ctx, query, originalID)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var results []transaction.Transaction
+	for rows.Next() {
+		tx, err := scanTransaction(rows)
+		if err != nil {
+			return nil, err
+		}
+		results = append(results, tx)
+	}
+
+	return results, rows.Err()
+}
+
 func (r *PostgresTransactionRepository) ListTransactionsByUserID(ctx context.Context, userID string, limit, offset int) ([]transaction.Transaction, error) {
 	query := `
-        SELECT id, from_user_id, to_user_id, amount, currency, type, created_at
+        SELECT ` + transactionColumns + `
         FROM transactions
         WHERE from_user_id = $1 OR to_user_id = $1
-        ORDER BY created_at DESC
+        ORDER BY created_at DESC, id DESC
         LIMIT $2 OFFSET $3
     `
-	rows, err := r.db.QueryContext(ctx, query, userID, limit, offset)
+	rows, err := executor(ctx, r.db).QueryContext(ctx, query, userID, limit, offset)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var results []transaction.Transaction
+	for rows.Next() {
+		tx, err := scanTransaction(rows)
+		if err != nil {
+			return nil, err
+		}
+		results = append(results, tx)
+	}
+
+	return results, rows.Err()
+}
+
+func (r *PostgresTransactionRepository) ListTransactionsByUserIDBefore(ctx context.Context, userID string, before transaction.Cursor, limit int) ([]transaction.Transaction, error) {
+	query := `
+        SELECT ` + transactionColumns + `
+        FROM transactions
+        WHERE (from_user_id = $1 OR to_user_id = $1)
+          AND (created_at, id) < ($2, $3)
+        ORDER BY created_at DESC, id DESC
+        LIMIT $4
+    `
+	rows, err := executor(ctx, r.db).QueryContext(ctx, query, userID, before.CreatedAt, before.ID, limit)
 	if err != nil {
 		return nil, err
 	}
@@ -66,14 +188,171 @@ func (r *PostgresTransactionRepository) ListTransactionsByUserID(ctx context.Con
 
 	var results []transaction.Transaction
 	for rows.Next() {
-		var tx transaction.Transaction
-		var tType string
-		if err := rows.Scan(&tx.ID, &tx.FromUserID, &tx.ToUserID, &tx.Amount, &tx.Currency, &tType, &tx.CreatedAt); err != nil {
+		tx, err := scanTransaction(rows)
+		if err != nil {
 			return nil, err
 		}
-		tx.Type = transaction.TransactionType(tType)
 		results = append(results, tx)
 	}
 
 	return results, rows.Err()
 }
+
+func (r *PostgresTransactionRepository) StreamTransactionsByUserID(ctx context.Context, userID string, from, to time.Time, fn func(transaction.Transaction) error) error {
+	query := `
+        SELECT ` + transactionColumns + `
+        FROM transactions
+        WHERE (from_user_id = $1 OR to_user_id = $1)
+          AND COALESCE(completed_at, created_at) >= $2 AND COALESCE(completed_at, created_at) < $3
+          AND status = 'completed'
+        ORDER BY COALESCE(completed_at, created_at) ASC, id ASC
+    `
+	rows, err := executor(ctx, r.db).QueryContext(ctx, query, userID, from, to)
+	if err != nil {
+		return err
+	}
+	defer rows.Close()
+
+	for rows.Next() {
+		tx, err := scanTransaction(rows)
+		if err != nil {
+			return err
+		}
+		if err := fn(tx); err != nil {
+			return err
+		}
+	}
+
+	return rows.Err()
+}
+
+func (r *PostgresTransactionRepository) SearchTransactions(ctx context.Context, filter transaction.SearchFilter, limit, offset int) ([]transaction.Transaction, error) {
+	var f queryFilter
+	if filter.UserID != "" {
+		f.add("(from_user_id = $%[1]d OR to_user_id = $%[1]d)", filter.UserID)
+	}
+	if filter.Type != "" {
+		f.add("type = $%[1]d", string(filter.Type))
+	}
+	if filter.BatchID != "" {
+		f.add("batch_id = $%[1]d", filter.BatchID)
+	}
+	if filter.Status != "" {
+		f.add("status = $%[1]d", string(filter.Status))
+	}
+	if !filter.From.IsZero() {
+		f.add("created_at >= $%[1]d", filter.From)
+	}
+	if !filter.To.IsZero() {
+		f.add("created_at < $%[1]d", filter.To)
+	}
+	if filter.MinAmount > 0 {
+		f.add("amount >= $%[1]d", filter.MinAmount)
+	}
+	if filter.MaxAmount > 0 {
+		f.add("amount <= $%[1]d", filter.MaxAmount)
+	}
+
+	query := `
+        SELECT ` + transactionColumns + `
+        FROM transactions
+        ` + f.where() + `
+        ORDER BY created_at DESC, id DESC
+        ` + f.page(limit, offset)
+	rows, err := executor(ctx, r.db).QueryContext(ctx, query, f.args...)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var results []transaction.Transaction
+	for rows.Next() {
+		tx, err := scanTransaction(rows)
+		if err != nil {
+			return nil, err
+		}
+		results = append(results, tx)
+	}
+
+	return results, rows.Err()
+}
+
+func (r *PostgresTransactionRepository) SumNetAmountSince(ctx context.Context, userID string, since time.Time) (int64, error) {
+	query := `
+        SELECT COALESCE(SUM(CASE WHEN to_user_id = $1 THEN amount ELSE 0 END), 0)
+             - COALESCE(SUM(CASE WHEN from_user_id = $1 THEN amount ELSE 0 END), 0)
+        FROM transactions
+        WHERE (from_user_id = $1 OR to_user_id = $1)
+          AND COALESCE(completed_at, created_at) >= $2
+          AND status = 'completed'
+    `
+	var net int64
+	if err := executor(ctx, r.db).QueryRowContext(ctx, query, userID, since).Scan(&net); err != nil {
+		return 0, err
+	}
+	return net, nil
+}
+
+func (r *PostgresTransactionRepository) SumNetAmountBetween(ctx context.Context, userID string, from, to time.Time) (int64, error) {
+	query := `
+        SELECT COALESCE(SUM(CASE WHEN to_user_id = $1 THEN amount ELSE 0 END), 0)
+             - COALESCE(SUM(CASE WHEN from_user_id = $1 THEN amount ELSE 0 END), 0)
+        FROM transactions
+        WHERE (from_user_id = $1 OR to_user_id = $1)
+          AND COALESCE(completed_at, created_at) >= $2
+          AND COALESCE(completed_at, created_at) < $3
+          AND status = 'completed'
+    `
+	var net int64
+	if err := executor(ctx, r.db).QueryRowContext(ctx, query, userID, from, to).Scan(&net); err != nil {
+		return 0, err
+	}
+	return net, nil
+}
+
+func (r *PostgresTransactionRepository) SumNetFlowBetween(ctx context.Context, from, to time.Time) (map[string]int64, error) {
+	query := `
+        SELECT currency,
+               COALESCE(SUM(CASE WHEN to_user_id <> '' THEN amount ELSE 0 END), 0)
+             - COALESCE(SUM(CASE WHEN from_user_id <> '' THEN amount ELSE 0 END), 0)
+        FROM transactions
+        WHERE COALESCE(completed_at, created_at) >= $1
+          AND COALESCE(completed_at, created_at) < $2
+          AND status = 'completed'
+        GROUP BY currency
+    `
+	rows, err := executor(ctx, r.db).QueryContext(ctx, query, from, to)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	flows := make(map[string]int64)
+	for rows.Next() {
+		var currency string
+		var net int64
+		if err := rows.Scan(&currency, &net); err != nil {
+			return nil, err
+		}
+		flows[currency] = net
+	}
+	return flows, rows.Err()
+}
+
+func (r *PostgresTransactionRepository) SumOutgoingSince(ctx context.Context, userID string, tType transaction.TransactionType, currency string, since time.Time) (int64, int, error) {
+	query := `
+        SELECT COALESCE(SUM(amount), 0), COUNT(*)
+        FROM transactions
+        WHERE from_user_id = $1
+          AND type = $2
+          AND UPPER(currency) = UPPER($3)
+          AND created_at >= $4
+          AND status NOT IN ('failed', 'cancelled')
+    `
+	var amount int64
+	var count int
+	if err := executor(ctx, r.db).QueryRowContext(ctx, query, userID, tType, currency, since).Scan(&amount, &count); err != nil {
+		return 0, 0, err
+	}
+	return amount, count, nil
+}
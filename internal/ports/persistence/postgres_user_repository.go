@@ -0,0 +1,92 @@
+package persistence
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+
+	"exchange/internal/domain/user"
+
+	"github.com/jackc/pgx/v5/pgconn"
+)
+
+// uniqueViolation is the SQLSTATE Postgres reports when a unique constraint is violated.
+const uniqueViolation = "23505"
+
+// userConstraintError translates violations of the users table's unique constraints.
+func userConstraintError(err error) error {
+	var pgErr *pgconn.PgError
+	if !errors.As(err, &pgErr) || pgErr.Code != uniqueViolation {
+		return err
+	}
+	switch pgErr.ConstraintName {
+	case "users_pkey":
+		return user.ErrUserExists
+	case "users_email_key":
+		return user.ErrEmailTaken
+	default:
+		return err
+	}
+}
+
+type PostgresUserRepository struct {
+	db *sql.DB
+}
+
+func NewPostgresUserRepository(db *sql.DB) *PostgresUserRepository {
+	return &PostgresUserRepository{
+		db: db,
+	}
+}
+
+func (r *PostgresUserRepository) CreateUser(ctx context.Context, u user.User) error {
+	query := `
+        INSERT INTO users (user_id, "name", email, status, created_at, updated_at)
+        VALUES ($1, $2, $3, $4, $5, $6)
+    `
+	_, err := executor(ctx, r.db).ExecContext(ctx, query,
+		u.ID, u.Name, u.Email, string(u.Status), u.CreatedAt, u.UpdatedAt,
+	)
+	return userConstraintError(err)
+}
+
+func (r *PostgresUserRepository) GetUserByID(ctx context.Context, id string) (user.User, error) {
+	query := `
+        SELECT user_id, "name", email, status, created_at, updated_at
+        FROM users
+        WHERE user_id = $1
+    `
+	var u user.User
+	var status string
+	err := executor(ctx, r.db).QueryRowContext(ctx, query, id).Scan(
+		&u.ID, &u.Name, &u.Email, &status, &u.CreatedAt, &u.UpdatedAt,
+	)
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return user.User{}, user.ErrUserNotFound
+		}
+		return user.User{}, err
+	}
+	u.Status = user.Status(status)
+	return u, nil
+}
+
+func (r *PostgresUserRepository) UpdateUser(ctx context.Context, u user.User) error {
+	query := `
+        UPDATE users
+        SET "name" = $2, email = $3, status = $4, updated_at = $5
+        WHERE user_id = $1
+    `
+	result, err := executor(ctx, r.db).ExecContext(ctx, query, u.ID, u.Name, u.Email, string(u.Status), u.UpdatedAt)
+	if err != nil {
+		return userConstraintError(err)
+	}
+	n, err := result.RowsAffected()
+	if err != nil {
+		return err
+	}
+	if n == 0 {
+		return user.ErrUserNotFound
+	}
+	return nil
+}
@@ -4,6 +4,7 @@ import (
 	"context"
 	"database/sql"
 	"errors"
+	"strings"
 	"time"
 
 	"exchange/internal/domain/wallet"
@@ -21,24 +22,44 @@ func NewPostgresWalletRepository(db *sql.DB) *PostgresWalletRepository {
 
 func (r *PostgresWalletRepository) CreateWallet(ctx context.Context, w wallet.Wallet) error {
 	query := `
-        INSERT INTO wallets (user_id, balance, currency, created_at, updated_at)
-        VALUES ($1, $2, $3, $4, $5)
+        INSERT INTO wallets (user_id, balance, held, currency, created_at, updated_at)
+        VALUES ($1, $2, $3, $4, $5, $6)
     `
-	_, err := r.db.ExecContext(ctx, query,
-		w.UserID, w.Balance, w.Currency, w.CreatedAt, w.UpdatedAt,
+	_, err := executor(ctx, r.db).ExecContext(ctx, query,
+		w.UserID, w.Balance, w.Held, w.Currency, w.CreatedAt, w.UpdatedAt,
 	)
 	return err
 }
 
 func (r *PostgresWalletRepository) GetWalletByUserID(ctx context.Context, userID string) (wallet.Wallet, error) {
 	query := `
-        SELECT user_id, balance, currency, created_at, updated_at
+        SELECT user_id, balance, held, currency, created_at, updated_at
         FROM wallets
         WHERE user_id = $1
     `
 	var w wallet.Wallet
-	err := r.db.QueryRowContext(ctx, query, userID).Scan(
-		&w.UserID, &w.Balance, &w.Currency, &w.CreatedAt, &w.UpdatedAt,
+	err := executor(ctx, r.db).QueryRowContext(ctx, query, userID).Scan(
+		&w.UserID, &w.Balance, &w.Held, &w.Currency, &w.CreatedAt, &w.UpdatedAt,
+	)
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return wallet.Wallet{}, wallet.ErrWalletNotFound
+		}
+		return wallet.Wallet{}, err
+	}
+	return w, nil
+}
+
+func (r *PostgresWalletRepository) LockWalletByUserID(ctx context.Context, userID string) (wallet.Wallet, error) {
+	query := `
+        SELECT user_id, balance, held, currency, created_at, updated_at
+        FROM wallets
+        WHERE user_id = $1
+        FOR UPDATE
+    `
+	var w wallet.Wallet
+	err := executor(ctx, r.db).QueryRowContext(ctx, query, userID).Scan(
+		&w.UserID, &w.Balance, &w.Held, &w.Currency, &w.CreatedAt, &w.UpdatedAt,
 	)
 	if err != nil {
 		if errors.Is(err, sql.ErrNoRows) {
@@ -52,10 +73,10 @@ func (r *PostgresWalletRepository) GetWalletByUserID(ctx context.Context, userID
 func (r *PostgresWalletRepository) UpdateWallet(ctx context.Context, w wallet.Wallet) error {
 	query := `
         UPDATE wallets
-        SET balance = $2, updated_at = $3
+        SET balance = $2, held = $3, updated_at = $4
         WHERE user_id = $1
     `
-	res, err := r.db.ExecContext(ctx, query, w.UserID, w.Balance, time.Now())
+	res, err := executor(ctx, r.db).ExecContext(ctx, query, w.UserID, w.Balance, w.Held, time.Now())
 	if err != nil {
 		return err
 	}
@@ -70,3 +91,44 @@ func (r *PostgresWalletRepository) UpdateWallet(ctx context.Context, w wallet.Wa
 
 	return nil
 }
+
+func (r *PostgresWalletRepository) SearchWallets(ctx context.Context, filter wallet.SearchFilter, limit, offset int) ([]wallet.Wallet, error) {
+	var f queryFilter
+	if filter.UserIDPrefix != "" {
+		// Escape LIKE wildcards so the prefix is matched literally.
+		prefix := strings.NewReplacer(`\`, `\\`, "%", `\%`, "_", `\_`).Replace(filter.UserIDPrefix)
+		f.add("user_id LIKE $%[1]d", prefix+"%")
+	}
+	if filter.Currency != "" {
+		f.add("currency = $%[1]d", filter.Currency)
+	}
+	if filter.MinBalance != nil {
+		f.add("balance >= $%[1]d", *filter.MinBalance)
+	}
+	if filter.MaxBalance != nil {
+		f.add("balance <= $%[1]d", *filter.MaxBalance)
+	}
+
+	query := `
+        SELECT user_id, balance, held, currency, created_at, updated_at
+        FROM wallets
+        ` + f.where() + `
+        ORDER BY user_id
+        ` + f.page(limit, offset)
+	rows, err := executor(ctx, r.db).QueryContext(ctx, query, f.args...)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var results []wallet.Wallet
+	for rows.Next() {
+		var w wallet.Wallet
+		if err := rows.Scan(&w.UserID, &w.Balance, &w.Held, &w.Currency, &w.CreatedAt, &w.UpdatedAt); err != nil {
+			return nil, err
+		}
+		results = append(results, w)
+	}
+
+	return results, rows.Err()
+}
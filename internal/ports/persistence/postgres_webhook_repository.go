@@ -0,0 +1,282 @@
+package persistence
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"strings"
+	"time"
+
+	"exchange/internal/domain/event"
+	"exchange/internal/domain/webhook"
+)
+
+// subscriptionColumns lists the columns read by scanSubscription, in order.
+const subscriptionColumns = `id, user_id, url, event_types, secret, created_at`
+
+func scanSubscription(row rowScanner) (webhook.Subscription, error) {
+	var s webhook.Subscription
+	var eventTypes string
+	if err := row.Scan(&s.ID, &s.UserID, &s.URL, &eventTypes, &s.Secret, &s.CreatedAt); err != nil {
+		return webhook.Subscription{}, err
+	}
+	for _, t := range strings.Split(eventTypes, ",") {
+		s.EventTypes = append(s.EventTypes, event.Type(t))
+	}
+	return s, nil
+}
+
+// deliveryColumns lists the columns read by scanDelivery, in order.
+const deliveryColumns = `id, subscription_id, user_id, event_id, event_type, body, status, attempts, next_attempt_at, last_error, created_at, updated_at`
+
+func scanDelivery(row rowScanner) (webhook.Delivery, error) {
+	var d webhook.Delivery
+	var eventType, status string
+	err := row.Scan(&d.ID, &d.SubscriptionID, &d.UserID, &d.EventID, &eventType, &d.Body, &status,
+		&d.Attempts, &d.NextAttemptAt, &d.LastError, &d.CreatedAt, &d.UpdatedAt)
+	if err != nil {
+		return webhook.Delivery{}, err
+	}
+	d.EventType = event.Type(eventType)
+	d.Status = webhook.DeliveryStatus(status)
+	return d, nil
+}
+
+// PostgresWebhookRepository stores webhook subscriptions, their deliveries and the
+// attempts made to send them.
+type PostgresWebhookRepository struct {
+	db *sql.DB
+}
+
+func NewPostgresWebhookRepository(db *sql.DB) *PostgresWebhookRepository {
+	return &PostgresWebhookRepository{
+		db: db,
+	}
+}
+
+func (r *PostgresWebhookRepository) CreateSubscription(ctx context.Context, s webhook.Subscription) error {
+	types := make([]string, 0, len(s.EventTypes))
+	for _, t := range s.EventTypes {
+		types = append(types, string(t))
+	}
+
+	query := `
+        INSERT INTO webhook_subscriptions (id, user_id, url, event_types, secret, created_at)
+        VALUES ($1, $2, $3, $4, $5, $6)
+    `
+	_, err := executor(ctx, r.db).ExecContext(ctx, query, s.ID, s.UserID, s.URL, strings.Join(types, ","), s.Secret, s.CreatedAt)
+	return err
+}
+
+func (r *PostgresWebhookRepository) GetSubscriptionByID(ctx context.Context, id string) (webhook.Subscription, error) {
+	query := `
+        SELECT ` + subscriptionColumns + `
+        FROM webhook_subscriptions
+        WHERE id = $1
+    `
+	s, err := scanSubscription(executor(ctx, r.db).QueryRowContext(ctx, query, id))
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return webhook.Subscription{}, webhook.ErrSubscriptionNotFound
+		}
+		return webhook.Subscription{}, err
+	}
+	return s, nil
+}
+
+func (r *PostgresWebhookRepository) ListSubscriptionsByUserID(ctx context.Context, userID string) ([]webhook.Subscription, error) {
+	query := `
+        SELECT ` + subscriptionColumns + `
+        FROM webhook_subscriptions
+        WHERE user_id = $1
+        ORDER BY created_at ASC, id ASC
+    `
+	return r.listSubscriptions(ctx, query, userID)
+}
+
+func (r *PostgresWebhookRepository) ListSubscriptionsByEventType(ctx context.Context, t event.Type) ([]webhook.Subscription, error) {
+	query := `
+        SELECT ` + subscriptionColumns + `
+        FROM webhook_subscriptions
+        WHERE $1 = ANY (string_to_array(event_types, ','))
+        ORDER BY created_at ASC, id ASC
+    `
+	return r.listSubscriptions(ctx, query, string(t))
+}
+
+func (r *PostgresWebhookRepository) listSubscriptions(ctx context.Context, query string, args ...any) ([]webhook.Subscription, error) {
+	rows, err := executor(ctx, r.db).QueryContext(ctx, query, args...)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var results []webhook.Subscription
+	for rows.Next() {
+		s, err := scanSubscription(rows)
+		if err != nil {
+			return nil, err
+		}
+		results = append(results, s)
+	}
+
+	return results, rows.Err()
+}
+
+func (r *PostgresWebhookRepository) DeleteSubscription(ctx context.Context, id string) error {
+	res, err := executor(ctx, r.db).ExecContext(ctx, `DELETE FROM webhook_subscriptions WHERE id = $1`, id)
+	if err != nil {
+		return err
+	}
+
+	rowsAffected, err := res.RowsAffected()
+	if err != nil {
+		return err
+	}
+	if rowsAffected == 0 {
+		return webhook.ErrSubscriptionNotFound
+	}
+
+	return nil
+}
+
+// CreateDelivery ignores a second delivery of the same event to the same subscription,
+// which happens when the relay publishes an event again after a crash.
+func (r *PostgresWebhookRepository) CreateDelivery(ctx context.Context, d webhook.Delivery) error {
+	query := `
+        INSERT INTO webhook_deliveries (id, subscription_id, user_id, event_id, event_type, body, status, attempts, next_attempt_at, last_error, created_at, updated_at)
+        VALUES ($1, $2, $3, $4, $5, $6, $7, $8, $9, $10, $11, $12)
+        ON CONFLICT (subscription_id, event_id) DO NOTHING
+    `
+	_, err := executor(ctx, r.db).ExecContext(ctx, query,
+		d.ID, d.SubscriptionID, d.UserID, d.EventID, string(d.EventType), d.Body, string(d.Status),
+		d.Attempts, d.NextAttemptAt, d.LastError, d.CreatedAt, d.UpdatedAt,
+	)
+	return err
+}
+
+func (r *PostgresWebhookRepository) GetDeliveryByID(ctx context.Context, id string) (webhook.Delivery, error) {
+	query := `
+        SELECT ` + deliveryColumns + `
+        FROM webhook_deliveries
+        WHERE id = $1
+    `
+	d, err := scanDelivery(executor(ctx, r.db).QueryRowContext(ctx, query, id))
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return webhook.Delivery{}, webhook.ErrDeliveryNotFound
+		}
+		return webhook.Delivery{}, err
+	}
+	return d, nil
+}
+
+func (r *PostgresWebhookRepository) ListDeliveries(ctx context.Context, filter webhook.DeliveryFilter, limit, offset int) ([]webhook.Delivery, error) {
+	var f queryFilter
+	if filter.UserID != "" {
+		f.add("user_id = $%[1]d", filter.UserID)
+	}
+	if filter.SubscriptionID != "" {
+		f.add("subscription_id = $%[1]d", filter.SubscriptionID)
+	}
+	if filter.Status != "" {
+		f.add("status = $%[1]d", string(filter.Status))
+	}
+
+	query := `
+        SELECT ` + deliveryColumns + `
+        FROM webhook_deliveries
+        ` + f.where() + `
+        ORDER BY created_at DESC, id DESC
+        ` + f.page(limit, offset)
+	return r.listDeliveries(ctx, query, f.args...)
+}
+
+func (r *PostgresWebhookRepository) ListDueDeliveries(ctx context.Context, now time.Time, limit int) ([]webhook.Delivery, error) {
+	query := `
+        SELECT ` + deliveryColumns + `
+        FROM webhook_deliveries
+        WHERE status = 'pending' AND next_attempt_at <= $1
+        ORDER BY next_attempt_at ASC, id ASC
+        LIMIT $2
+        FOR UPDATE SKIP LOCKED
+    `
+	return r.listDeliveries(ctx, query, now, limit)
+}
+
+func (r *PostgresWebhookRepository) listDeliveries(ctx context.Context, query string, args ...any) ([]webhook.Delivery, error) {
+	rows, err := executor(ctx, r.db).QueryContext(ctx, query, args...)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var results []webhook.Delivery
+	for rows.Next() {
+		d, err := scanDelivery(rows)
+		if err != nil {
+			return nil, err
+		}
+		results = append(results, d)
+	}
+
+	return results, rows.Err()
+}
+
+func (r *PostgresWebhookRepository) UpdateDelivery(ctx context.Context, d webhook.Delivery) error {
+	query := `
+        UPDATE webhook_deliveries
+        SET status = $2, attempts = $3, next_attempt_at = $4, last_error = $5, updated_at = $6
+        WHERE id = $1
+    `
+	res, err := executor(ctx, r.db).ExecContext(ctx, query, d.ID, string(d.Status), d.Attempts, d.NextAttemptAt, d.LastError, d.UpdatedAt)
+	if err != nil {
+		return err
+	}
+
+	rowsAffected, err := res.RowsAffected()
+	if err != nil {
+		return err
+	}
+	if rowsAffected == 0 {
+		return webhook.ErrDeliveryNotFound
+	}
+
+	return nil
+}
+
+func (r *PostgresWebhookRepository) RecordAttempt(ctx context.Context, a webhook.Attempt) error {
+	query := `
+        INSERT INTO webhook_attempts (delivery_id, attempted_at, status_code, error, duration_ms)
+        VALUES ($1, $2, $3, $4, $5)
+    `
+	_, err := executor(ctx, r.db).ExecContext(ctx, query, a.DeliveryID, a.AttemptedAt, a.StatusCode, a.Error, a.Duration.Milliseconds())
+	return err
+}
+
+func (r *PostgresWebhookRepository) ListAttempts(ctx context.Context, deliveryID string) ([]webhook.Attempt, error) {
+	query := `
+        SELECT delivery_id, attempted_at, status_code, error, duration_ms
+        FROM webhook_attempts
+        WHERE delivery_id = $1
+        ORDER BY id ASC
+    `
+	rows, err := executor(ctx, r.db).QueryContext(ctx, query, deliveryID)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var results []webhook.Attempt
+	for rows.Next() {
+		var a webhook.Attempt
+		var durationMs int64
+		if err := rows.Scan(&a.DeliveryID, &a.AttemptedAt, &a.StatusCode, &a.Error, &durationMs); err != nil {
+			return nil, err
+		}
+		a.Duration = time.Duration(durationMs) * time.Millisecond
+		results = append(results, a)
+	}
+
+	return results, rows.Err()
+}
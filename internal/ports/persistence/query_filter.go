@@ -0,0 +1,33 @@
+package persistence
+
+import (
+	"fmt"
+	"strings"
+)
+
+// queryFilter collects optional WHERE conditions together with their arguments.
+type queryFilter struct {
+	conditions []string
+	args       []any
+}
+
+// add appends a condition whose placeholders are written as %[1]d, for example
+// "created_at >= $%[1]d", and bound to arg.
+func (f *queryFilter) add(condition string, arg any) {
+	f.args = append(f.args, arg)
+	f.conditions = append(f.conditions, fmt.Sprintf(condition, len(f.args)))
+}
+
+// where returns the WHERE clause, or an empty string when there are no conditions.
+func (f *queryFilter) where() string {
+	if len(f.conditions) == 0 {
+		return ""
+	}
+	return "WHERE " + strings.Join(f.conditions, " AND ")
+}
+
+// page appends LIMIT and OFFSET placeholders and returns the clause.
+func (f *queryFilter) page(limit, offset int) string {
+	f.args = append(f.args, limit, offset)
+	return fmt.Sprintf("LIMIT $%d OFFSET $%d", len(f.args)-1, len(f.args))
+}
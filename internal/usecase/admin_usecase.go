@@ -0,0 +1,271 @@
+package usecase
+
+import (
+	"context"
+	"time"
+
+	"exchange/internal/domain/adjustment"
+	"exchange/internal/domain/audit"
+	"exchange/internal/domain/kyc"
+	"exchange/internal/domain/period"
+	"exchange/internal/domain/risk"
+	"exchange/internal/domain/sanctions"
+	"exchange/internal/domain/transaction"
+	"exchange/internal/domain/user"
+	"exchange/internal/domain/wallet"
+)
+
+// AdjustmentRequest describes a manual balance change requested by an admin.
+type AdjustmentRequest struct {
+	UserID    string
+	Direction adjustment.Direction
+	Amount    int64
+	Currency  string
+	Reason    adjustment.ReasonCode
+	Note      string
+	// EffectiveAt back-dates the adjustment to correct an earlier period; zero books it now.
+	EffectiveAt time.Time
+}
+
+// AdminUseCase implements back-office operations. Balance changes go through
+// WalletUseCase so they are recorded in the wallet's transaction history.
+type AdminUseCase struct {
+	walletUC          *WalletUseCase
+	adjustmentService adjustment.AdjustmentServiceInterface
+	periodService     period.PeriodServiceInterface
+	approvalThreshold int64
+}
+
+// NewAdminUseCase returns an AdminUseCase that applies adjustments of up to
+// approvalThreshold immediately and holds larger ones for a second admin's approval.
+func NewAdminUseCase(walletUC *WalletUseCase, aService adjustment.AdjustmentServiceInterface, pService period.PeriodServiceInterface, approvalThreshold int64) *AdminUseCase {
+	return &AdminUseCase{
+		walletUC:          walletUC,
+		adjustmentService: aService,
+		periodService:     pService,
+		approvalThreshold: approvalThreshold,
+	}
+}
+
+// RequestAdjustment records an adjustment on behalf of adminID and applies it unless its
+// amount exceeds the approval threshold, in which case it stays pending. Adjustments cannot
+// be back-dated into a closed period.
+func (uc *AdminUseCase) RequestAdjustment(ctx context.Context, adminID string, req AdjustmentRequest) (adjustment.Adjustment, error) {
+	var result adjustment.Adjustment
+	err := uc.walletUC.audited(ctx, audit.ActionAdjustmentRequest, []string{req.UserID}, func(ctx context.Context, e *audit.Entry) error {
+		a, err := uc.adjustmentService.RequestAdjustment(ctx, req.UserID, req.Direction, req.Amount, req.Currency, req.Reason, req.Note, adminID, req.EffectiveAt)
+		if err != nil {
+			return err
+		}
+		e.Target = a.ID
+		if err := uc.periodService.CheckOpen(ctx, a.EffectiveAt); err != nil {
+			return err
+		}
+		if a.Amount > uc.approvalThreshold {
+			result = a
+			return nil
+		}
+
+		result, err = uc.apply(ctx, a, "")
+		e.TransactionID = result.TransactionID
+		return err
+	})
+	if err != nil {
+		return adjustment.Adjustment{}, err
+	}
+	return result, nil
+}
+
+// ApproveAdjustment applies a pending adjustment. The approver must not be the admin who
+// requested it.
+func (uc *AdminUseCase) ApproveAdjustment(ctx context.Context, adminID, id string) (adjustment.Adjustment, error) {
+	return uc.decide(ctx, audit.ActionAdjustmentApprove, adminID, id, func(ctx context.Context, a adjustment.Adjustment) (adjustment.Adjustment, error) {
+		return uc.apply(ctx, a, adminID)
+	})
+}
+
+func (uc *AdminUseCase) RejectAdjustment(ctx context.Context, adminID, id string) (adjustment.Adjustment, error) {
+	return uc.decide(ctx, audit.ActionAdjustmentReject, adminID, id, func(ctx context.Context, a adjustment.Adjustment) (adjustment.Adjustment, error) {
+		return uc.adjustmentService.MarkRejected(ctx, a, adminID)
+	})
+}
+
+// decide loads adjustment id, checks that adminID may decide it and runs fn on it as an
+// audited action. A concurrent decision is caught when fn stores its own.
+func (uc *AdminUseCase) decide(ctx context.Context, action audit.Action, adminID, id string, fn func(ctx context.Context, a adjustment.Adjustment) (adjustment.Adjustment, error)) (adjustment.Adjustment, error) {
+	a, err := uc.adjustmentService.GetAdjustment(ctx, id)
+	if err != nil {
+		entry := audit.NewEntry(action)
+		entry.Target = id
+		uc.walletUC.recordFailure(ctx, entry, err)
+		return adjustment.Adjustment{}, err
+	}
+
+	var result adjustment.Adjustment
+	err = uc.walletUC.audited(ctx, action, []string{a.UserID}, func(ctx context.Context, e *audit.Entry) error {
+		e.Target = a.ID
+		if err := a.CanBeDecidedBy(adminID); err != nil {
+			return err
+		}
+
+		result, err = fn(ctx, a)
+		e.TransactionID = result.TransactionID
+		return err
+	})
+	if err != nil {
+		return adjustment.Adjustment{}, err
+	}
+	return result, nil
+}
+
+// apply changes the balance and marks a applied; callers must run it inside txManager.Do.
+// The ADJUSTMENT transaction is booked on a's effective date, so a back-dated adjustment
+// lands in the period it corrects. Marking fails if a was decided concurrently, which rolls
+// the balance change back. A pending adjustment whose period was closed in the meantime can
+// only be rejected.
+func (uc *AdminUseCase) apply(ctx context.Context, a adjustment.Adjustment, decidedBy string) (adjustment.Adjustment, error) {
+	if err := uc.periodService.CheckOpen(ctx, a.EffectiveAt); err != nil {
+		return adjustment.Adjustment{}, err
+	}
+	tx, err := uc.walletUC.adjust(ctx, a.UserID, a.Direction == adjustment.DirectionCredit, a.Amount, a.Currency, transaction.EffectiveAt(a.EffectiveAt))
+	if err != nil {
+		return adjustment.Adjustment{}, err
+	}
+	return uc.adjustmentService.MarkApplied(ctx, a, decidedBy, tx.ID)
+}
+
+func (uc *AdminUseCase) GetAdjustment(ctx context.Context, id string) (adjustment.Adjustment, error) {
+	return uc.adjustmentService.GetAdjustment(ctx, id)
+}
+
+func (uc *AdminUseCase) ListAdjustments(ctx context.Context, status adjustment.Status, limit, offset int) ([]adjustment.Adjustment, error) {
+	return uc.adjustmentService.ListAdjustments(ctx, status, limit, offset)
+}
+
+func (uc *AdminUseCase) SearchWallets(ctx context.Context, filter wallet.SearchFilter, limit, offset int) ([]wallet.Wallet, error) {
+	return uc.walletUC.walletService.SearchWallets(ctx, filter, limit, offset)
+}
+
+func (uc *AdminUseCase) SearchTransactions(ctx context.Context, filter transaction.SearchFilter, limit, offset int) ([]transaction.Transaction, error) {
+	return uc.walletUC.transactionService.SearchTransactions(ctx, filter, limit, offset)
+}
+
+// ReverseTransaction undoes amount of a transaction, or whatever is left of it when amount
+// is 0, for example to correct a deposit credited in error.
+func (uc *AdminUseCase) ReverseTransaction(ctx context.Context, id string, amount int64) (transaction.Transaction, error) {
+	return uc.walletUC.Reverse(ctx, id, amount)
+}
+
+// UpdateTransactionStatus records the progress of an asynchronous transaction, such as a
+// withdrawal paid out by a bank.
+func (uc *AdminUseCase) UpdateTransactionStatus(ctx context.Context, id string, status transaction.Status) (transaction.Transaction, error) {
+	return uc.walletUC.UpdateTransactionStatus(ctx, id, status)
+}
+
+// ReviewWithdrawal approves or, for reason, rejects a withdrawal awaiting approval.
+func (uc *AdminUseCase) ReviewWithdrawal(ctx context.Context, id string, approved bool, reason string) (transaction.Transaction, error) {
+	return uc.walletUC.ReviewWithdrawal(ctx, id, approved, reason)
+}
+
+// SetUserStatus deactivates a user, which stops them from opening wallets and taking part
+// in transfers, or activates them again.
+func (uc *AdminUseCase) SetUserStatus(ctx context.Context, userID string, status user.Status) (user.User, error) {
+	var result user.User
+	err := uc.walletUC.audited(ctx, audit.ActionUserStatus, nil, func(ctx context.Context, e *audit.Entry) error {
+		e.Target = userID
+		u, err := uc.walletUC.userService.SetStatus(ctx, userID, status)
+		result = u
+		return err
+	})
+	if err != nil {
+		return user.User{}, err
+	}
+	return result, nil
+}
+
+// ReviewKYCSubmission approves a pending KYC submission, raising its user to the level they
+// asked for, or rejects it for reason.
+func (uc *AdminUseCase) ReviewKYCSubmission(ctx context.Context, adminID, id string, approved bool, reason string) (kyc.Submission, error) {
+	action := audit.ActionKYCReject
+	if approved {
+		action = audit.ActionKYCApprove
+	}
+	var result kyc.Submission
+	err := uc.walletUC.audited(ctx, action, nil, func(ctx context.Context, e *audit.Entry) error {
+		e.Target = id
+		s, err := uc.walletUC.kycService.Review(ctx, id, adminID, approved, reason)
+		result = s
+		return err
+	})
+	if err != nil {
+		return kyc.Submission{}, err
+	}
+	return result, nil
+}
+
+// SetKYCLevel moves a user to level by hand, for instance when their documents expired.
+func (uc *AdminUseCase) SetKYCLevel(ctx context.Context, adminID, userID string, level kyc.Level, reason string) (kyc.LevelChange, error) {
+	var result kyc.LevelChange
+	err := uc.walletUC.audited(ctx, audit.ActionKYCLevel, nil, func(ctx context.Context, e *audit.Entry) error {
+		e.Target = userID
+		if _, err := uc.walletUC.userService.GetUser(ctx, userID); err != nil {
+			return err
+		}
+		c, err := uc.walletUC.kycService.SetLevel(ctx, userID, level, adminID, reason)
+		result = c
+		return err
+	})
+	if err != nil {
+		return kyc.LevelChange{}, err
+	}
+	return result, nil
+}
+
+func (uc *AdminUseCase) ListKYCSubmissions(ctx context.Context, filter kyc.Filter, limit, offset int) ([]kyc.Submission, error) {
+	return uc.walletUC.kycService.ListSubmissions(ctx, filter, limit, offset)
+}
+
+// GetKYCDocument returns a submission with the document that came with it.
+func (uc *AdminUseCase) GetKYCDocument(ctx context.Context, id string) (kyc.Submission, []byte, error) {
+	s, err := uc.walletUC.kycService.GetSubmission(ctx, id)
+	if err != nil {
+		return kyc.Submission{}, nil, err
+	}
+	content, err := uc.walletUC.kycService.GetDocument(ctx, s)
+	if err != nil {
+		return kyc.Submission{}, nil, err
+	}
+	return s, content, nil
+}
+
+func (uc *AdminUseCase) ListSanctionsCases(ctx context.Context, filter sanctions.Filter, limit, offset int) ([]sanctions.Case, error) {
+	return uc.walletUC.sanctionsService.ListCases(ctx, filter, limit, offset)
+}
+
+// ClearSanctionsCase marks a sanctions hit as a false positive, letting its subject through
+// the screening again.
+func (uc *AdminUseCase) ClearSanctionsCase(ctx context.Context, adminID, id, reason string) (sanctions.Case, error) {
+	var result sanctions.Case
+	err := uc.walletUC.audited(ctx, audit.ActionClearSanctions, nil, func(ctx context.Context, e *audit.Entry) error {
+		e.Target = id
+		c, err := uc.walletUC.sanctionsService.ClearCase(ctx, id, adminID, reason)
+		result = c
+		return err
+	})
+	if err != nil {
+		return sanctions.Case{}, err
+	}
+	return result, nil
+}
+
+func (uc *AdminUseCase) ListAuditEntries(ctx context.Context, filter audit.Filter, limit, offset int) ([]audit.Entry, error) {
+	return uc.walletUC.auditService.ListEntries(ctx, filter, limit, offset)
+}
+
+func (uc *AdminUseCase) ListRiskAssessments(ctx context.Context, filter risk.Filter, limit, offset int) ([]risk.Assessment, error) {
+	return uc.walletUC.riskService.ListAssessments(ctx, filter, limit, offset)
+}
+
+func (uc *AdminUseCase) VerifyAuditLog(ctx context.Context) (audit.ChainReport, error) {
+	return uc.walletUC.auditService.VerifyChain(ctx)
+}
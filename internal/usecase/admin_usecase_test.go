@@ -0,0 +1,163 @@
+package usecase
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"exchange/internal/domain/adjustment"
+	"exchange/internal/domain/audit"
+	"exchange/internal/domain/transaction"
+	"exchange/internal/domain/wallet"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+)
+
+type MockAdjustmentService struct {
+	mock.Mock
+}
+
+func (m *MockAdjustmentService) RequestAdjustment(ctx context.Context, userID string, direction adjustment.Direction, amount int64, currency string, reason adjustment.ReasonCode, note, requestedBy string, effectiveAt time.Time) (adjustment.Adjustment, error) {
+	args := m.Called(ctx, userID, direction, amount, currency, reason, note, requestedBy, effectiveAt)
+	return args.Get(0).(adjustment.Adjustment), args.Error(1)
+}
+
+func (m *MockAdjustmentService) GetAdjustment(ctx context.Context, id string) (adjustment.Adjustment, error) {
+	args := m.Called(ctx, id)
+	return args.Get(0).(adjustment.Adjustment), args.Error(1)
+}
+
+func (m *MockAdjustmentService) ListAdjustments(ctx context.Context, status adjustment.Status, limit, offset int) ([]adjustment.Adjustment, error) {
+	args := m.Called(ctx, status, limit, offset)
+	return args.Get(0).([]adjustment.Adjustment), args.Error(1)
+}
+
+func (m *MockAdjustmentService) MarkApplied(ctx context.Context, a adjustment.Adjustment, decidedBy, transactionID string) (adjustment.Adjustment, error) {
+	args := m.Called(ctx, a, decidedBy, transactionID)
+	return args.Get(0).(adjustment.Adjustment), args.Error(1)
+}
+
+func (m *MockAdjustmentService) MarkRejected(ctx context.Context, a adjustment.Adjustment, decidedBy string) (adjustment.Adjustment, error) {
+	args := m.Called(ctx, a, decidedBy)
+	return args.Get(0).(adjustment.Adjustment), args.Error(1)
+}
+
+func TestAdminUseCase_Adjustments(t *testing.T) {
+	ctx := context.Background()
+
+	newUseCase := func() (*AdminUseCase, *MockWalletService, *MockTransactionService, *MockAdjustmentService) {
+		mockWalletService := new(MockWalletService)
+		mockTransactionService := new(MockTransactionService)
+		mockAdjustmentService := new(MockAdjustmentService)
+		mockTxManager := new(MockTransactionManager)
+		mockTxManager.DoFn = func(ctx context.Context, fn func(context.Context) error) error {
+			return fn(ctx)
+		}
+		walletUC := NewWalletUseCase(testDependencies(mockWalletService, mockTransactionService, mockTxManager))
+		return NewAdminUseCase(walletUC, mockAdjustmentService, closedThrough{}, 1000), mockWalletService, mockTransactionService, mockAdjustmentService
+	}
+	applied := func(a adjustment.Adjustment, decidedBy, txID string) adjustment.Adjustment {
+		now := time.Now()
+		a.Status = adjustment.StatusApplied
+		a.DecidedBy = decidedBy
+		a.TransactionID = txID
+		a.DecidedAt = &now
+		return a
+	}
+
+	t.Run("small credit is applied immediately", func(t *testing.T) {
+		useCase, mockWalletService, mockTransactionService, mockAdjustmentService := newUseCase()
+		pending := adjustment.Adjustment{ID: "adj1", UserID: "user1", Direction: adjustment.DirectionCredit, Amount: 500, Currency: "USD", Reason: adjustment.ReasonGoodwill, Status: adjustment.StatusPending, RequestedBy: "admin1"}
+
+		mockAdjustmentService.On("RequestAdjustment", ctx, "user1", adjustment.DirectionCredit, int64(500), "USD", adjustment.ReasonGoodwill, "", "admin1", time.Time{}).Return(pending, nil)
+		mockWalletService.On("Deposit", ctx, "user1", int64(500)).Return(nil)
+		mockTransactionService.On("LogTransaction", ctx, "", "user1", int64(500), "USD", transaction.TransactionTypeAdjustment).Return(transaction.Transaction{ID: "tx1"}, nil)
+		mockAdjustmentService.On("MarkApplied", ctx, pending, "", "tx1").Return(applied(pending, "", "tx1"), nil)
+
+		a, err := useCase.RequestAdjustment(ctx, "admin1", AdjustmentRequest{UserID: "user1", Direction: adjustment.DirectionCredit, Amount: 500, Currency: "USD", Reason: adjustment.ReasonGoodwill})
+
+		assert.NoError(t, err)
+		assert.Equal(t, adjustment.StatusApplied, a.Status)
+		assert.Equal(t, "tx1", a.TransactionID)
+		mockWalletService.AssertExpectations(t)
+		mockTransactionService.AssertExpectations(t)
+	})
+
+	t.Run("large debit waits for approval", func(t *testing.T) {
+		useCase, mockWalletService, _, mockAdjustmentService := newUseCase()
+		pending := adjustment.Adjustment{ID: "adj2", UserID: "user1", Direction: adjustment.DirectionDebit, Amount: 5000, Currency: "USD", Reason: adjustment.ReasonReversal, Status: adjustment.StatusPending, RequestedBy: "admin1"}
+
+		mockAdjustmentService.On("RequestAdjustment", ctx, "user1", adjustment.DirectionDebit, int64(5000), "USD", adjustment.ReasonReversal, "duplicate", "admin1", time.Time{}).Return(pending, nil)
+
+		a, err := useCase.RequestAdjustment(ctx, "admin1", AdjustmentRequest{UserID: "user1", Direction: adjustment.DirectionDebit, Amount: 5000, Currency: "USD", Reason: adjustment.ReasonReversal, Note: "duplicate"})
+
+		assert.NoError(t, err)
+		assert.Equal(t, adjustment.StatusPending, a.Status)
+		mockWalletService.AssertNotCalled(t, "Withdraw", mock.Anything, mock.Anything, mock.Anything)
+	})
+
+	t.Run("approval by a second admin applies the debit", func(t *testing.T) {
+		useCase, mockWalletService, mockTransactionService, mockAdjustmentService := newUseCase()
+		pending := adjustment.Adjustment{ID: "adj2", UserID: "user1", Direction: adjustment.DirectionDebit, Amount: 5000, Currency: "USD", Reason: adjustment.ReasonReversal, Status: adjustment.StatusPending, RequestedBy: "admin1"}
+
+		mockAdjustmentService.On("GetAdjustment", ctx, "adj2").Return(pending, nil)
+		mockWalletService.On("Withdraw", ctx, "user1", int64(5000)).Return(nil)
+		mockTransactionService.On("LogTransaction", ctx, "user1", "", int64(5000), "USD", transaction.TransactionTypeAdjustment).Return(transaction.Transaction{ID: "tx2"}, nil)
+		mockAdjustmentService.On("MarkApplied", ctx, pending, "admin2", "tx2").Return(applied(pending, "admin2", "tx2"), nil)
+
+		a, err := useCase.ApproveAdjustment(ctx, "admin2", "adj2")
+
+		assert.NoError(t, err)
+		assert.Equal(t, "admin2", a.DecidedBy)
+		mockWalletService.AssertExpectations(t)
+
+		entries := useCase.walletUC.auditService.(*auditRecorder).entries
+		require.Len(t, entries, 1)
+		assert.Equal(t, audit.ActionAdjustmentApprove, entries[0].Action)
+		assert.Equal(t, "adj2", entries[0].Target)
+		assert.Equal(t, "tx2", entries[0].TransactionID)
+	})
+
+	t.Run("requester cannot approve their own adjustment", func(t *testing.T) {
+		useCase, mockWalletService, _, mockAdjustmentService := newUseCase()
+		pending := adjustment.Adjustment{ID: "adj2", UserID: "user1", Direction: adjustment.DirectionDebit, Amount: 5000, Status: adjustment.StatusPending, RequestedBy: "admin1"}
+		mockAdjustmentService.On("GetAdjustment", ctx, "adj2").Return(pending, nil)
+
+		_, err := useCase.ApproveAdjustment(ctx, "admin1", "adj2")
+
+		assert.Equal(t, adjustment.ErrSelfApproval, err)
+		mockWalletService.AssertNotCalled(t, "Withdraw", mock.Anything, mock.Anything, mock.Anything)
+
+		entries := useCase.walletUC.auditService.(*auditRecorder).entries
+		require.Len(t, entries, 1)
+		assert.Equal(t, audit.OutcomeFailure, entries[0].Outcome)
+	})
+
+	t.Run("insufficient funds leaves the adjustment pending", func(t *testing.T) {
+		useCase, mockWalletService, _, mockAdjustmentService := newUseCase()
+		pending := adjustment.Adjustment{ID: "adj2", UserID: "user1", Direction: adjustment.DirectionDebit, Amount: 5000, Status: adjustment.StatusPending, RequestedBy: "admin1"}
+		mockAdjustmentService.On("GetAdjustment", ctx, "adj2").Return(pending, nil)
+		mockWalletService.On("Withdraw", ctx, "user1", int64(5000)).Return(wallet.ErrInsufficientFunds)
+
+		_, err := useCase.ApproveAdjustment(ctx, "admin2", "adj2")
+
+		assert.Equal(t, wallet.ErrInsufficientFunds, err)
+		mockAdjustmentService.AssertNotCalled(t, "MarkApplied", mock.Anything, mock.Anything, mock.Anything, mock.Anything)
+	})
+
+	t.Run("reject", func(t *testing.T) {
+		useCase, _, _, mockAdjustmentService := newUseCase()
+		pending := adjustment.Adjustment{ID: "adj2", Status: adjustment.StatusPending, RequestedBy: "admin1"}
+		rejected := pending
+		rejected.Status = adjustment.StatusRejected
+		mockAdjustmentService.On("GetAdjustment", ctx, "adj2").Return(pending, nil)
+		mockAdjustmentService.On("MarkRejected", ctx, pending, "admin2").Return(rejected, nil)
+
+		a, err := useCase.RejectAdjustment(ctx, "admin2", "adj2")
+
+		assert.NoError(t, err)
+		assert.Equal(t, adjustment.StatusRejected, a.Status)
+	})
+}
@@ -0,0 +1,157 @@
+package usecase
+
+import (
+	"context"
+
+	"exchange/internal/domain/audit"
+	"exchange/internal/domain/transaction"
+)
+
+// MaxBatchTransfers is the largest number of transfers accepted in one batch.
+const MaxBatchTransfers = 500
+
+type BatchMode string
+
+const (
+	// BatchModeAtomic executes every transfer in one database transaction: any failure
+	// rolls the whole batch back.
+	BatchModeAtomic BatchMode = "atomic"
+	// BatchModeBestEffort executes every transfer independently and reports each outcome.
+	BatchModeBestEffort BatchMode = "best_effort"
+)
+
+type TransferItem struct {
+	FromUserID string
+	ToUserID   string
+	Amount     int64
+	Currency   string
+}
+
+type TransferItemResult struct {
+	TransactionID string
+	Err           error
+}
+
+type BatchTransferResult struct {
+	BatchID string
+	Mode    BatchMode
+	Results []TransferItemResult
+}
+
+// Failed returns the number of items that were not executed.
+func (r BatchTransferResult) Failed() int {
+	failed := 0
+	for _, result := range r.Results {
+		if result.Err != nil {
+			failed++
+		}
+	}
+	return failed
+}
+
+// BatchTransfer executes items under a shared batch ID recorded on every resulting
+// transaction. Validation errors of the batch itself are returned as an error; failures
+// of individual transfers are reported per item in the result.
+func (uc *WalletUseCase) BatchTransfer(ctx context.Context, mode BatchMode, items []TransferItem) (BatchTransferResult, error) {
+	if len(items) == 0 {
+		return BatchTransferResult{}, transaction.ErrEmptyBatch
+	}
+	if len(items) > MaxBatchTransfers {
+		return BatchTransferResult{}, transaction.ErrBatchTooLarge
+	}
+	if mode != BatchModeAtomic && mode != BatchModeBestEffort {
+		return BatchTransferResult{}, transaction.ErrInvalidBatchMode
+	}
+
+	batchID, err := transaction.NewBatchID()
+	if err != nil {
+		return BatchTransferResult{}, err
+	}
+
+	result := BatchTransferResult{
+		BatchID: batchID,
+		Mode:    mode,
+		Results: make([]TransferItemResult, len(items)),
+	}
+
+	if mode == BatchModeBestEffort {
+		for i, item := range items {
+			if _, err := uc.screen(ctx, transferRiskRequest(item.FromUserID, item.ToUserID, item.Amount, item.Currency)); err != nil {
+				result.Results[i] = TransferItemResult{Err: err}
+				entry := audit.NewEntry(audit.ActionBatchTransfer, item.FromUserID, item.ToUserID)
+				entry.Target = batchID
+				uc.recordFailure(ctx, entry, err)
+				continue
+			}
+			err := uc.audited(ctx, audit.ActionBatchTransfer, []string{item.FromUserID, item.ToUserID}, func(ctx context.Context, e *audit.Entry) error {
+				e.Target = batchID
+				tx, err := uc.transfer(ctx, item.FromUserID, item.ToUserID, item.Amount, item.Currency, transaction.WithBatchID(batchID))
+				result.Results[i].TransactionID = tx.ID
+				e.TransactionID = tx.ID
+				return err
+			})
+			if err != nil {
+				result.Results[i] = TransferItemResult{Err: err}
+			}
+		}
+		return result, nil
+	}
+
+	result.Results = uc.transferAtomically(ctx, audit.ActionBatchTransfer, batchID, items)
+	return result, nil
+}
+
+// transferAtomically executes items in one database transaction under batchID, auditing
+// each as action, and returns the outcome of each. If any item fails, none is executed:
+// the failing one reports why and the others report transaction.ErrBatchRolledBack.
+func (uc *WalletUseCase) transferAtomically(ctx context.Context, action audit.Action, batchID string, items []TransferItem) []TransferItemResult {
+	results := make([]TransferItemResult, len(items))
+
+	// Every item is assessed before any runs, so a blocked item stops the batch without
+	// starting the database transaction.
+	var err error
+	failedIndex := -1
+	for i, item := range items {
+		if _, err = uc.screen(ctx, transferRiskRequest(item.FromUserID, item.ToUserID, item.Amount, item.Currency)); err != nil {
+			failedIndex = i
+			break
+		}
+	}
+	if err == nil {
+		err = uc.txManager.Do(ctx, func(ctx context.Context) error {
+			for i, item := range items {
+				entry := uc.auditService.Begin(ctx, action, item.FromUserID, item.ToUserID)
+				entry.Target = batchID
+				tx, err := uc.transfer(ctx, item.FromUserID, item.ToUserID, item.Amount, item.Currency, transaction.WithBatchID(batchID))
+				if err != nil {
+					failedIndex = i
+					return err
+				}
+				entry.TransactionID = tx.ID
+				if err := uc.auditService.RecordSuccess(ctx, entry); err != nil {
+					failedIndex = i
+					return err
+				}
+				results[i].TransactionID = tx.ID
+			}
+			return nil
+		})
+	}
+	if err != nil {
+		// Items other than the failing or blocked one are reported as rolled back; if the
+		// commit itself failed, every item carries that error. The success entries were
+		// rolled back with the batch, so every item is audited as failed.
+		for i, item := range items {
+			itemErr := transaction.ErrBatchRolledBack
+			if failedIndex < 0 || i == failedIndex {
+				itemErr = err
+			}
+			results[i] = TransferItemResult{Err: itemErr}
+
+			entry := audit.NewEntry(action, item.FromUserID, item.ToUserID)
+			entry.Target = batchID
+			uc.recordFailure(ctx, entry, itemErr)
+		}
+	}
+	return results
+}
@@ -0,0 +1,124 @@
+package usecase
+
+import (
+	"context"
+	"testing"
+
+	"exchange/internal/domain/audit"
+	"exchange/internal/domain/transaction"
+	"exchange/internal/domain/wallet"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+)
+
+func TestWalletUseCase_BatchTransfer(t *testing.T) {
+	ctx := context.Background()
+	items := []TransferItem{
+		{FromUserID: "user1", ToUserID: "user2", Amount: 100, Currency: "USD"},
+		{FromUserID: "user1", ToUserID: "user3", Amount: 200, Currency: "USD"},
+		{FromUserID: "user1", ToUserID: "user4", Amount: 300, Currency: "USD"},
+	}
+
+	newUseCase := func() (*WalletUseCase, *MockWalletService, *MockTransactionService, *MockTransactionManager) {
+		mockWalletService := new(MockWalletService)
+		mockTransactionService := new(MockTransactionService)
+		mockTxManager := new(MockTransactionManager)
+		mockTxManager.DoFn = func(ctx context.Context, fn func(context.Context) error) error {
+			return fn(ctx)
+		}
+		return NewWalletUseCase(testDependencies(mockWalletService, mockTransactionService, mockTxManager)), mockWalletService, mockTransactionService, mockTxManager
+	}
+
+	t.Run("best effort reports each item", func(t *testing.T) {
+		useCase, mockWalletService, mockTransactionService, _ := newUseCase()
+
+		mockWalletService.On("Withdraw", ctx, "user1", int64(100)).Return(nil)
+		mockWalletService.On("Deposit", ctx, "user2", int64(100)).Return(nil)
+		mockWalletService.On("Withdraw", ctx, "user1", int64(200)).Return(wallet.ErrInsufficientFunds)
+		mockWalletService.On("Withdraw", ctx, "user1", int64(300)).Return(nil)
+		mockWalletService.On("Deposit", ctx, "user4", int64(300)).Return(nil)
+		mockTransactionService.On("LogTransaction", ctx, "user1", "user2", int64(100), "USD", transaction.TransactionTypeTransfer).Return(transaction.Transaction{ID: "tx1"}, nil)
+		mockTransactionService.On("LogTransaction", ctx, "user1", "user4", int64(300), "USD", transaction.TransactionTypeTransfer).Return(transaction.Transaction{ID: "tx3"}, nil)
+
+		result, err := useCase.BatchTransfer(ctx, BatchModeBestEffort, items)
+
+		assert.NoError(t, err)
+		assert.NotEmpty(t, result.BatchID)
+		assert.Equal(t, BatchModeBestEffort, result.Mode)
+		assert.Equal(t, "tx1", result.Results[0].TransactionID)
+		assert.NoError(t, result.Results[0].Err)
+		assert.ErrorIs(t, result.Results[1].Err, wallet.ErrInsufficientFunds)
+		assert.Equal(t, "tx3", result.Results[2].TransactionID)
+		assert.Equal(t, 1, result.Failed())
+		mockWalletService.AssertExpectations(t)
+		mockTransactionService.AssertExpectations(t)
+	})
+
+	t.Run("atomic success records the batch id", func(t *testing.T) {
+		useCase, mockWalletService, mockTransactionService, _ := newUseCase()
+
+		mockWalletService.On("Withdraw", ctx, "user1", mock.Anything).Return(nil)
+		mockWalletService.On("Deposit", ctx, mock.Anything, mock.Anything).Return(nil)
+		mockTransactionService.On("LogTransaction", ctx, "user1", mock.Anything, mock.Anything, "USD", transaction.TransactionTypeTransfer).Return(transaction.Transaction{ID: "tx"}, nil)
+
+		result, err := useCase.BatchTransfer(ctx, BatchModeAtomic, items)
+
+		assert.NoError(t, err)
+		assert.Equal(t, 0, result.Failed())
+		assert.Len(t, result.Results, 3)
+		mockTransactionService.AssertNumberOfCalls(t, "LogTransaction", 3)
+	})
+
+	t.Run("atomic failure rolls back every item", func(t *testing.T) {
+		useCase, mockWalletService, mockTransactionService, mockTxManager := newUseCase()
+
+		calls := 0
+		mockTxManager.DoFn = func(ctx context.Context, fn func(context.Context) error) error {
+			calls++
+			return fn(ctx)
+		}
+		mockWalletService.On("Withdraw", ctx, "user1", int64(100)).Return(nil)
+		mockWalletService.On("Deposit", ctx, "user2", int64(100)).Return(nil)
+		mockWalletService.On("Withdraw", ctx, "user1", int64(200)).Return(nil)
+		mockWalletService.On("Deposit", ctx, "user3", int64(200)).Return(wallet.ErrWalletNotFound)
+		mockTransactionService.On("LogTransaction", ctx, "user1", "user2", int64(100), "USD", transaction.TransactionTypeTransfer).Return(transaction.Transaction{ID: "tx1"}, nil)
+
+		result, err := useCase.BatchTransfer(ctx, BatchModeAtomic, items)
+
+		assert.NoError(t, err)
+		assert.Equal(t, 1, calls, "atomic batches must run in a single transaction")
+		assert.Equal(t, 3, result.Failed())
+		assert.Empty(t, result.Results[0].TransactionID)
+		assert.ErrorIs(t, result.Results[0].Err, transaction.ErrBatchRolledBack)
+		assert.ErrorIs(t, result.Results[1].Err, wallet.ErrWalletNotFound)
+		assert.ErrorIs(t, result.Results[2].Err, transaction.ErrBatchRolledBack)
+		mockWalletService.AssertNotCalled(t, "Withdraw", ctx, "user1", int64(300))
+
+		// The recorder does not roll back, so the first item's success entry stays; the
+		// database drops it together with the batch.
+		entries := useCase.auditService.(*auditRecorder).entries
+		require.Len(t, entries, 4)
+		for _, e := range entries[1:] {
+			assert.Equal(t, audit.OutcomeFailure, e.Outcome)
+			assert.Equal(t, audit.ActionBatchTransfer, e.Action)
+			assert.Equal(t, result.BatchID, e.Target)
+		}
+		assert.Equal(t, "user3", entries[2].Balances[1].UserID)
+		assert.Equal(t, wallet.ErrWalletNotFound.Error(), entries[2].Error)
+	})
+
+	t.Run("invalid batches", func(t *testing.T) {
+		useCase, _, _, _ := newUseCase()
+
+		_, err := useCase.BatchTransfer(ctx, BatchModeAtomic, nil)
+		assert.ErrorIs(t, err, transaction.ErrEmptyBatch)
+
+		_, err = useCase.BatchTransfer(ctx, BatchModeAtomic, make([]TransferItem, MaxBatchTransfers+1))
+		assert.ErrorIs(t, err, transaction.ErrBatchTooLarge)
+
+		_, err = useCase.BatchTransfer(ctx, BatchMode("sometimes"), items)
+		assert.ErrorIs(t, err, transaction.ErrInvalidBatchMode)
+	})
+}
@@ -0,0 +1,257 @@
+package usecase
+
+import (
+	"context"
+	"errors"
+	"log"
+	"time"
+
+	"exchange/internal/domain/audit"
+	"exchange/internal/domain/escrow"
+	"exchange/internal/domain/limit"
+	"exchange/internal/domain/transaction"
+	"exchange/internal/domain/wallet"
+)
+
+// EscrowRequest describes an escrow a payer funds for a payee.
+type EscrowRequest struct {
+	PayerID    string
+	PayeeID    string
+	Amount     int64
+	Currency   string
+	Conditions string
+	OnExpiry   escrow.ExpiryAction
+	ExpiresAt  time.Time
+}
+
+// EscrowUseCase holds payers' funds in the escrow account of their currency until they
+// are released to the payee or refunded. Funds move through WalletUseCase so they are
+// recorded in both wallets' transaction history.
+type EscrowUseCase struct {
+	walletUC      *WalletUseCase
+	escrowService escrow.EscrowServiceInterface
+}
+
+func NewEscrowUseCase(walletUC *WalletUseCase, eService escrow.EscrowServiceInterface) *EscrowUseCase {
+	return &EscrowUseCase{
+		walletUC:      walletUC,
+		escrowService: eService,
+	}
+}
+
+// CreateEscrow moves the amount from the payer's wallet into the escrow account. Funding
+// an escrow is screened and limited like a transfer to the payee, as that is where the
+// funds end up unless they are refunded.
+func (uc *EscrowUseCase) CreateEscrow(ctx context.Context, req EscrowRequest) (escrow.Escrow, error) {
+	if _, err := uc.walletUC.screen(ctx, transferRiskRequest(req.PayerID, req.PayeeID, req.Amount, req.Currency)); err != nil {
+		uc.walletUC.recordFailure(ctx, audit.NewEntry(audit.ActionEscrowCreate, req.PayerID, req.PayeeID), err)
+		return escrow.Escrow{}, err
+	}
+
+	var result escrow.Escrow
+	err := uc.walletUC.audited(ctx, audit.ActionEscrowCreate, []string{req.PayerID, req.PayeeID}, func(ctx context.Context, e *audit.Entry) error {
+		esc, err := uc.escrowService.New(req.PayerID, req.PayeeID, req.Amount, req.Currency, req.Conditions, req.OnExpiry, req.ExpiresAt)
+		if err != nil {
+			return err
+		}
+		e.Target = esc.ID
+		for _, userID := range []string{esc.PayerID, esc.PayeeID} {
+			if err := uc.walletUC.userService.CheckActive(ctx, userID); err != nil {
+				return err
+			}
+			w, err := uc.walletUC.walletService.GetWallet(ctx, userID)
+			if err != nil {
+				return err
+			}
+			if w.Currency != esc.Currency {
+				return escrow.ErrCurrencyMismatch
+			}
+		}
+		if err := uc.walletUC.checkTransferCaps(ctx, esc.PayerID, esc.PayeeID, esc.Amount); err != nil {
+			return err
+		}
+		if err := uc.walletUC.checkLimits(ctx, esc.PayerID, limit.OperationTransfer, esc.Amount, esc.Currency); err != nil {
+			return err
+		}
+
+		if err := uc.walletUC.walletService.Withdraw(ctx, esc.PayerID, esc.Amount); err != nil {
+			return err
+		}
+		if err := uc.depositToAccount(ctx, esc.Currency, esc.Amount); err != nil {
+			return err
+		}
+		tx, err := uc.walletUC.logTransaction(ctx, esc.PayerID, escrow.AccountID(esc.Currency), esc.Amount, esc.Currency, transaction.TransactionTypeEscrow)
+		if err != nil {
+			return err
+		}
+		e.TransactionID = tx.ID
+
+		esc.FundingTransactionID = tx.ID
+		if err := uc.escrowService.Create(ctx, esc); err != nil {
+			return err
+		}
+		result = esc
+		return nil
+	})
+	if err != nil {
+		return escrow.Escrow{}, err
+	}
+	return result, nil
+}
+
+// depositToAccount credits the escrow account of currency, opening it on first use. The
+// account is locked first so concurrent escrows do not overwrite each other's balance.
+func (uc *EscrowUseCase) depositToAccount(ctx context.Context, currency string, amount int64) error {
+	accountID := escrow.AccountID(currency)
+	_, err := uc.walletUC.walletService.LockWallet(ctx, accountID)
+	if errors.Is(err, wallet.ErrWalletNotFound) {
+		_, err = uc.walletUC.walletService.CreateNewWallet(ctx, accountID, currency)
+	}
+	if err != nil {
+		return err
+	}
+	return uc.walletUC.walletService.Deposit(ctx, accountID, amount)
+}
+
+// ReleaseEscrow pays a funded escrow to its payee, as its payer confirms.
+func (uc *EscrowUseCase) ReleaseEscrow(ctx context.Context, id string) (escrow.Escrow, error) {
+	return uc.settle(ctx, audit.ActionEscrowRelease, id, escrow.StatusReleased, uc.escrowService.Settle)
+}
+
+// RefundEscrow returns a funded escrow to its payer, as its payee gives up the payment.
+func (uc *EscrowUseCase) RefundEscrow(ctx context.Context, id string) (escrow.Escrow, error) {
+	return uc.settle(ctx, audit.ActionEscrowRefund, id, escrow.StatusRefunded, uc.escrowService.Settle)
+}
+
+// ResolveEscrow lets an admin release a funded or disputed escrow to its payee, or refund
+// it to its payer.
+func (uc *EscrowUseCase) ResolveEscrow(ctx context.Context, id string, release bool) (escrow.Escrow, error) {
+	to := escrow.StatusRefunded
+	if release {
+		to = escrow.StatusReleased
+	}
+	return uc.settle(ctx, audit.ActionEscrowResolve, id, to, uc.escrowService.Resolve)
+}
+
+// settle moves the funds of escrow id out of the escrow account to whoever receives them
+// in status to, and records the outcome with record. The escrow account is locked before
+// the escrow is read, so concurrent settlements neither overwrite the account's balance
+// nor pay out the same escrow twice.
+func (uc *EscrowUseCase) settle(ctx context.Context, action audit.Action, id string, to escrow.Status, record func(ctx context.Context, e escrow.Escrow, to escrow.Status, transactionID string) (escrow.Escrow, error)) (escrow.Escrow, error) {
+	current, err := uc.escrowService.GetEscrow(ctx, id)
+	if err != nil {
+		return escrow.Escrow{}, err
+	}
+
+	var result escrow.Escrow
+	err = uc.walletUC.audited(ctx, action, []string{current.PayerID, current.PayeeID}, func(ctx context.Context, e *audit.Entry) error {
+		e.Target = id
+		accountID := escrow.AccountID(current.Currency)
+		if _, err := uc.walletUC.walletService.LockWallet(ctx, accountID); err != nil {
+			return err
+		}
+		esc, err := uc.escrowService.GetEscrow(ctx, id)
+		if err != nil {
+			return err
+		}
+		if esc.Status.Settled() {
+			return escrow.ErrEscrowSettled
+		}
+		recipient := esc.Recipient(to)
+		if to == escrow.StatusReleased {
+			if err := uc.walletUC.checkBalanceCap(ctx, recipient, esc.Amount); err != nil {
+				return err
+			}
+		}
+
+		if err := uc.walletUC.walletService.Withdraw(ctx, accountID, esc.Amount); err != nil {
+			return err
+		}
+		if err := uc.walletUC.walletService.Deposit(ctx, recipient, esc.Amount); err != nil {
+			return err
+		}
+		tx, err := uc.walletUC.logTransaction(ctx, accountID, recipient, esc.Amount, esc.Currency, transaction.TransactionTypeEscrow)
+		if err != nil {
+			return err
+		}
+		e.TransactionID = tx.ID
+
+		result, err = record(ctx, esc, to, tx.ID)
+		return err
+	})
+	if err != nil {
+		return escrow.Escrow{}, err
+	}
+	return result, nil
+}
+
+// DisputeEscrow stops a funded escrow from expiring on behalf of userID, one of its
+// parties, until an admin resolves it.
+func (uc *EscrowUseCase) DisputeEscrow(ctx context.Context, id, userID, reason string) (escrow.Escrow, error) {
+	current, err := uc.escrowService.GetEscrow(ctx, id)
+	if err != nil {
+		return escrow.Escrow{}, err
+	}
+
+	var result escrow.Escrow
+	err = uc.walletUC.audited(ctx, audit.ActionEscrowDispute, []string{current.PayerID, current.PayeeID}, func(ctx context.Context, e *audit.Entry) error {
+		e.Target = id
+		result, err = uc.escrowService.Dispute(ctx, current, userID, reason)
+		return err
+	})
+	if err != nil {
+		return escrow.Escrow{}, err
+	}
+	return result, nil
+}
+
+func (uc *EscrowUseCase) GetEscrow(ctx context.Context, id string) (escrow.Escrow, error) {
+	return uc.escrowService.GetEscrow(ctx, id)
+}
+
+func (uc *EscrowUseCase) ListEscrows(ctx context.Context, filter escrow.Filter, limit, offset int) ([]escrow.Escrow, error) {
+	return uc.escrowService.ListEscrows(ctx, filter, limit, offset)
+}
+
+// ExpireEscrows settles up to one batch of funded escrows that expired by now as each of
+// them says, and returns how many it settled. Escrows settled or disputed in the meantime
+// are skipped.
+func (uc *EscrowUseCase) ExpireEscrows(ctx context.Context, now time.Time) (int, error) {
+	expired, err := uc.escrowService.ListExpired(ctx, now, expiryBatchSize)
+	if err != nil {
+		return 0, err
+	}
+
+	settled := 0
+	for _, esc := range expired {
+		_, err := uc.settle(ctx, audit.ActionEscrowExpire, esc.ID, esc.OnExpiry.Status(), uc.escrowService.Settle)
+		if errors.Is(err, escrow.ErrEscrowSettled) || errors.Is(err, escrow.ErrEscrowDisputed) {
+			continue
+		}
+		if err != nil {
+			return settled, err
+		}
+		settled++
+	}
+	return settled, nil
+}
+
+// RunEscrowExpiry settles expired escrows until ctx is cancelled. It keeps going while
+// full batches are found and otherwise waits interval before looking again.
+func (uc *EscrowUseCase) RunEscrowExpiry(ctx context.Context, interval time.Duration) {
+	for {
+		n, err := uc.ExpireEscrows(ctx, time.Now())
+		if err != nil && ctx.Err() == nil {
+			log.Println("escrow expiry:", err)
+		}
+		if err == nil && n == expiryBatchSize {
+			continue
+		}
+
+		select {
+		case <-ctx.Done():
+			return
+		case <-time.After(interval):
+		}
+	}
+}
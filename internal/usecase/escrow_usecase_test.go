@@ -0,0 +1,226 @@
+package usecase
+
+import (
+	"context"
+	"sort"
+	"sync"
+	"testing"
+	"time"
+
+	"exchange/internal/domain/escrow"
+	"exchange/internal/domain/limit"
+	"exchange/internal/domain/transaction"
+	"exchange/internal/domain/wallet"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+)
+
+// escrowStore keeps escrows in memory for the use case tests.
+type escrowStore struct {
+	mu      sync.Mutex
+	escrows map[string]escrow.Escrow
+}
+
+func (s *escrowStore) CreateEscrow(ctx context.Context, e escrow.Escrow) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if s.escrows == nil {
+		s.escrows = make(map[string]escrow.Escrow)
+	}
+	s.escrows[e.ID] = e
+	return nil
+}
+
+func (s *escrowStore) GetEscrowByID(ctx context.Context, id string) (escrow.Escrow, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	e, ok := s.escrows[id]
+	if !ok {
+		return escrow.Escrow{}, escrow.ErrEscrowNotFound
+	}
+	return e, nil
+}
+
+func (s *escrowStore) ListEscrows(ctx context.Context, filter escrow.Filter, limit, offset int) ([]escrow.Escrow, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	var result []escrow.Escrow
+	for _, e := range s.escrows {
+		if filter.UserID != "" && e.PayerID != filter.UserID && e.PayeeID != filter.UserID {
+			continue
+		}
+		if filter.Status != "" && e.Status != filter.Status {
+			continue
+		}
+		result = append(result, e)
+	}
+	sort.Slice(result, func(i, j int) bool { return result[i].ID < result[j].ID })
+	return result, nil
+}
+
+func (s *escrowStore) ListExpired(ctx context.Context, now time.Time, limit int) ([]escrow.Escrow, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	var result []escrow.Escrow
+	for _, e := range s.escrows {
+		if e.Expired(now) {
+			result = append(result, e)
+		}
+	}
+	return result, nil
+}
+
+func (s *escrowStore) UpdateEscrow(ctx context.Context, e escrow.Escrow, from escrow.Status) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if s.escrows[e.ID].Status != from {
+		return escrow.ErrEscrowSettled
+	}
+	s.escrows[e.ID] = e
+	return nil
+}
+
+func TestEscrowUseCase(t *testing.T) {
+	ctx := context.Background()
+	account := escrow.AccountID("USD")
+	amount := int64(500)
+
+	newLimitedUseCase := func(limits fixedLimits) (*EscrowUseCase, *MockWalletService, *MockTransactionService) {
+		mockWalletService := new(MockWalletService)
+		mockTransactionService := new(MockTransactionService)
+		mockTxManager := new(MockTransactionManager)
+		mockTxManager.DoFn = func(ctx context.Context, fn func(context.Context) error) error {
+			return fn(ctx)
+		}
+		deps := testDependencies(mockWalletService, mockTransactionService, mockTxManager)
+		deps.Limits = limits
+		walletUC := NewWalletUseCase(deps)
+
+		mockWalletService.On("GetWallet", ctx, "payer").Return(wallet.Wallet{UserID: "payer", Balance: 1000, Currency: "USD"}, nil)
+		mockWalletService.On("GetWallet", ctx, "payee").Return(wallet.Wallet{UserID: "payee", Currency: "USD"}, nil)
+		mockWalletService.On("Withdraw", ctx, mock.Anything, amount).Return(nil)
+		mockWalletService.On("Deposit", ctx, mock.Anything, amount).Return(nil)
+		mockTransactionService.On("LogTransaction", ctx, mock.Anything, mock.Anything, amount, "USD", transaction.TransactionTypeEscrow).
+			Return(transaction.Transaction{ID: "tx1", Type: transaction.TransactionTypeEscrow}, nil)
+
+		return NewEscrowUseCase(walletUC, escrow.NewEscrowService(new(escrowStore))), mockWalletService, mockTransactionService
+	}
+	newUseCase := func() (*EscrowUseCase, *MockWalletService, *MockTransactionService) {
+		return newLimitedUseCase(nil)
+	}
+	request := EscrowRequest{
+		PayerID:   "payer",
+		PayeeID:   "payee",
+		Amount:    amount,
+		Currency:  "USD",
+		ExpiresAt: time.Now().Add(time.Hour),
+	}
+
+	t.Run("funding opens the escrow account", func(t *testing.T) {
+		useCase, mockWalletService, mockTransactionService := newUseCase()
+		mockWalletService.On("LockWallet", ctx, account).Return(wallet.Wallet{}, wallet.ErrWalletNotFound)
+		mockWalletService.On("CreateNewWallet", ctx, account, "USD").Return(wallet.Wallet{UserID: account, Currency: "USD"}, nil)
+
+		e, err := useCase.CreateEscrow(ctx, request)
+
+		require.NoError(t, err)
+		assert.Equal(t, escrow.StatusFunded, e.Status)
+		assert.Equal(t, "tx1", e.FundingTransactionID)
+		mockWalletService.AssertCalled(t, "Withdraw", ctx, "payer", amount)
+		mockWalletService.AssertCalled(t, "Deposit", ctx, account, amount)
+		mockTransactionService.AssertCalled(t, "LogTransaction", ctx, "payer", account, amount, "USD", transaction.TransactionTypeEscrow)
+	})
+
+	t.Run("funding counts towards the transfer limit", func(t *testing.T) {
+		useCase, mockWalletService, mockTransactionService := newLimitedUseCase(fixedLimits{
+			{Operation: limit.OperationTransfer, Currency: "USD", Period: limit.PeriodDaily, MaxAmount: 800},
+		})
+		mockWalletService.On("LockWallet", ctx, mock.Anything).Return(wallet.Wallet{}, nil)
+		mockTransactionService.On("GetOutgoingSince", ctx, "payer", transaction.TransactionTypeTransfer, "USD", mock.Anything).Return(int64(0), 0, nil)
+		mockTransactionService.On("GetOutgoingSince", ctx, "payer", transaction.TransactionTypeEscrow, "USD", mock.Anything).Return(int64(0), 0, nil).Once()
+		mockTransactionService.On("GetOutgoingSince", ctx, "payer", transaction.TransactionTypeEscrow, "USD", mock.Anything).Return(amount, 1, nil)
+
+		_, err := useCase.CreateEscrow(ctx, request)
+		require.NoError(t, err)
+
+		_, err = useCase.CreateEscrow(ctx, request)
+
+		assert.ErrorIs(t, err, limit.ErrLimitExceeded)
+		mockWalletService.AssertNumberOfCalls(t, "Withdraw", 1)
+	})
+
+	t.Run("currency of another wallet", func(t *testing.T) {
+		useCase, mockWalletService, _ := newUseCase()
+		req := request
+		req.Currency = "EUR"
+
+		_, err := useCase.CreateEscrow(ctx, req)
+
+		assert.Equal(t, escrow.ErrCurrencyMismatch, err)
+		mockWalletService.AssertNotCalled(t, "Withdraw", ctx, "payer", amount)
+	})
+
+	t.Run("release pays the payee", func(t *testing.T) {
+		useCase, mockWalletService, mockTransactionService := newUseCase()
+		mockWalletService.On("LockWallet", ctx, account).Return(wallet.Wallet{UserID: account}, nil)
+		e, err := useCase.CreateEscrow(ctx, request)
+		require.NoError(t, err)
+
+		released, err := useCase.ReleaseEscrow(ctx, e.ID)
+
+		require.NoError(t, err)
+		assert.Equal(t, escrow.StatusReleased, released.Status)
+		// The escrow account is locked both to fund and to release the escrow.
+		mockWalletService.AssertNumberOfCalls(t, "LockWallet", 2)
+		mockWalletService.AssertCalled(t, "Withdraw", ctx, account, amount)
+		mockWalletService.AssertCalled(t, "Deposit", ctx, "payee", amount)
+		mockTransactionService.AssertCalled(t, "LogTransaction", ctx, account, "payee", amount, "USD", transaction.TransactionTypeEscrow)
+
+		_, err = useCase.RefundEscrow(ctx, e.ID)
+		assert.Equal(t, escrow.ErrEscrowSettled, err)
+	})
+
+	t.Run("dispute awaits an admin", func(t *testing.T) {
+		useCase, mockWalletService, _ := newUseCase()
+		mockWalletService.On("LockWallet", ctx, account).Return(wallet.Wallet{UserID: account}, nil)
+		e, err := useCase.CreateEscrow(ctx, request)
+		require.NoError(t, err)
+
+		_, err = useCase.DisputeEscrow(ctx, e.ID, "payee", "goods not delivered")
+		require.NoError(t, err)
+
+		_, err = useCase.ReleaseEscrow(ctx, e.ID)
+		assert.Equal(t, escrow.ErrEscrowDisputed, err)
+
+		resolved, err := useCase.ResolveEscrow(ctx, e.ID, false)
+		require.NoError(t, err)
+		assert.Equal(t, escrow.StatusRefunded, resolved.Status)
+		mockWalletService.AssertCalled(t, "Deposit", ctx, "payer", amount)
+	})
+
+	t.Run("expiry settles as agreed", func(t *testing.T) {
+		useCase, mockWalletService, _ := newUseCase()
+		mockWalletService.On("LockWallet", ctx, account).Return(wallet.Wallet{UserID: account}, nil)
+		req := request
+		req.OnExpiry = escrow.ExpiryRefund
+		refunded, err := useCase.CreateEscrow(ctx, req)
+		require.NoError(t, err)
+		disputed, err := useCase.CreateEscrow(ctx, request)
+		require.NoError(t, err)
+		_, err = useCase.DisputeEscrow(ctx, disputed.ID, "payer", "wrong goods")
+		require.NoError(t, err)
+
+		n, err := useCase.ExpireEscrows(ctx, req.ExpiresAt)
+
+		require.NoError(t, err)
+		assert.Equal(t, 1, n)
+		e, err := useCase.GetEscrow(ctx, refunded.ID)
+		require.NoError(t, err)
+		assert.Equal(t, escrow.StatusRefunded, e.Status)
+		e, err = useCase.GetEscrow(ctx, disputed.ID)
+		require.NoError(t, err)
+		assert.Equal(t, escrow.StatusDisputed, e.Status)
+	})
+}
@@ -0,0 +1,82 @@
+package usecase
+
+import (
+	"context"
+	"log"
+	"time"
+
+	"exchange/internal/domain/event"
+)
+
+// EventRelay delivers the events in the outbox to a Publisher at least once, in the order
+// they were recorded.
+type EventRelay struct {
+	eventService event.EventServiceInterface
+	publisher    event.Publisher
+	txManager    TransactionManager
+	batchSize    int
+}
+
+func NewEventRelay(eService event.EventServiceInterface, publisher event.Publisher, txManager TransactionManager, batchSize int) *EventRelay {
+	return &EventRelay{
+		eventService: eService,
+		publisher:    publisher,
+		txManager:    txManager,
+		batchSize:    batchSize,
+	}
+}
+
+// RelayPending publishes up to one batch of pending events and returns how many were
+// delivered. The batch stays locked in a database transaction while it is published and
+// only one relay at a time gets a batch, so servers running side by side take turns
+// rather than reordering events; in-process subscribers join that transaction through
+// ctx. Delivery stops at the first failure so no later event overtakes it, and
+// the failed event is retried on the next call. If marking events as published fails,
+// they are delivered again later.
+func (r *EventRelay) RelayPending(ctx context.Context) (int, error) {
+	published := 0
+	var publishErr error
+	err := r.txManager.Do(ctx, func(ctx context.Context) error {
+		published = 0
+		events, err := r.eventService.ListPendingEvents(ctx, r.batchSize)
+		if err != nil {
+			return err
+		}
+
+		for _, e := range events {
+			if err := r.publisher.Publish(ctx, e); err != nil {
+				publishErr = err
+				return r.eventService.MarkFailed(ctx, e, err)
+			}
+			if err := r.eventService.MarkPublished(ctx, e); err != nil {
+				return err
+			}
+			published++
+		}
+		return nil
+	})
+	if err != nil {
+		return 0, err
+	}
+	return published, publishErr
+}
+
+// Run relays events until ctx is cancelled. It keeps going while full batches are found
+// and otherwise waits interval before polling again.
+func (r *EventRelay) Run(ctx context.Context, interval time.Duration) {
+	for {
+		n, err := r.RelayPending(ctx)
+		if err != nil && ctx.Err() == nil {
+			log.Println("event relay:", err)
+		}
+		if err == nil && n == r.batchSize {
+			continue
+		}
+
+		select {
+		case <-ctx.Done():
+			return
+		case <-time.After(interval):
+		}
+	}
+}
@@ -0,0 +1,84 @@
+package usecase
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"exchange/internal/domain/event"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// flakyPublisher fails the events listed in failures once each.
+type flakyPublisher struct {
+	delivered []string
+	failures  map[string]bool
+}
+
+func (p *flakyPublisher) Publish(_ context.Context, e event.Event) error {
+	if p.failures[e.ID] {
+		delete(p.failures, e.ID)
+		return errors.New("broker unavailable")
+	}
+	p.delivered = append(p.delivered, e.ID)
+	return nil
+}
+
+func TestEventRelay_RelayPending(t *testing.T) {
+	ctx := context.Background()
+	events := new(eventRecorder)
+	for _, userID := range []string{"user1", "user2", "user3"} {
+		require.NoError(t, events.Record(ctx, event.TypeWalletCreated, userID, event.WalletCreated{UserID: userID, Currency: "USD"}))
+	}
+	publisher := &flakyPublisher{failures: map[string]bool{"ev2": true}}
+	mockTxManager := new(MockTransactionManager)
+	mockTxManager.DoFn = func(ctx context.Context, fn func(context.Context) error) error {
+		return fn(ctx)
+	}
+	relay := NewEventRelay(events, publisher, mockTxManager, 2)
+
+	n, err := relay.RelayPending(ctx)
+	assert.EqualError(t, err, "broker unavailable")
+	assert.Equal(t, 1, n)
+	assert.Equal(t, []string{"ev1"}, publisher.delivered, "events after a failure must wait for it")
+	assert.Equal(t, "broker unavailable", events.failed["ev2"])
+
+	n, err = relay.RelayPending(ctx)
+	assert.NoError(t, err)
+	assert.Equal(t, 2, n)
+
+	n, err = relay.RelayPending(ctx)
+	assert.NoError(t, err)
+	assert.Equal(t, 0, n)
+	assert.Equal(t, []string{"ev1", "ev2", "ev3"}, publisher.delivered)
+}
+
+func TestEventRelay_RolledBackBatchIsRedelivered(t *testing.T) {
+	ctx := context.Background()
+	events := new(eventRecorder)
+	require.NoError(t, events.Record(ctx, event.TypeWalletCreated, "user1", event.WalletCreated{UserID: "user1", Currency: "USD"}))
+	publisher := &flakyPublisher{}
+	mockTxManager := new(MockTransactionManager)
+	mockTxManager.DoFn = func(ctx context.Context, fn func(context.Context) error) error {
+		published := len(events.published)
+		if err := fn(ctx); err != nil {
+			return err
+		}
+		// The commit fails: the published marks are lost.
+		events.published = events.published[:published]
+		return errors.New("commit failed")
+	}
+	relay := NewEventRelay(events, publisher, mockTxManager, 10)
+
+	_, err := relay.RelayPending(ctx)
+	assert.Error(t, err)
+
+	mockTxManager.DoFn = func(ctx context.Context, fn func(context.Context) error) error {
+		return fn(ctx)
+	}
+	_, err = relay.RelayPending(ctx)
+	assert.NoError(t, err)
+	assert.Equal(t, []string{"ev1", "ev1"}, publisher.delivered, "delivery is at least once")
+}
@@ -0,0 +1,174 @@
+package usecase
+
+import (
+	"context"
+	"errors"
+	"log"
+	"strings"
+	"time"
+
+	"exchange/internal/domain/audit"
+	"exchange/internal/domain/escrow"
+	"exchange/internal/domain/interest"
+	"exchange/internal/domain/snapshot"
+	"exchange/internal/domain/transaction"
+)
+
+// InterestUseCase accrues interest on end-of-day balances and pays it out monthly from
+// the house account of each currency's product. Payouts move through WalletUseCase so
+// they are recorded in both wallets' transaction history.
+type InterestUseCase struct {
+	walletUC        *WalletUseCase
+	interestService interest.InterestServiceInterface
+}
+
+func NewInterestUseCase(walletUC *WalletUseCase, iService interest.InterestServiceInterface) *InterestUseCase {
+	return &InterestUseCase{
+		walletUC:        walletUC,
+		interestService: iService,
+	}
+}
+
+// AccrueInterest records the interest every wallet earned over day, a UTC calendar day,
+// on its balance at the end of the day, and returns how many accruals it recorded.
+// Wallets already accrued for day are skipped, so a rerun only fills in what is missing.
+// Escrow and house accounts hold the exchange's own funds and earn nothing.
+func (uc *InterestUseCase) AccrueInterest(ctx context.Context, day time.Time) (int, error) {
+	if !day.Equal(interest.Day(day)) || day.AddDate(0, 0, 1).After(time.Now()) {
+		return 0, interest.ErrInvalidDay
+	}
+
+	var snaps []snapshot.Snapshot
+	err := uc.walletUC.eachWalletSnapshot(ctx, day.AddDate(0, 0, 1), func(snap snapshot.Snapshot) {
+		if strings.HasPrefix(snap.UserID, escrow.AccountPrefix) || uc.walletUC.houseAccounts[snap.UserID] {
+			return
+		}
+		if _, ok := uc.interestService.Product(snap.Currency); ok {
+			snaps = append(snaps, snap)
+		}
+	})
+	if err != nil {
+		return 0, err
+	}
+
+	accrued := 0
+	for _, snap := range snaps {
+		a, err := uc.interestService.Accrue(ctx, snap.UserID, snap.Currency, day, snap.Balance)
+		if errors.Is(err, interest.ErrAlreadyAccrued) {
+			continue
+		}
+		if err != nil {
+			return accrued, err
+		}
+		if a.Micros > 0 {
+			accrued++
+		}
+	}
+	return accrued, nil
+}
+
+// PayInterest pays every wallet the interest it accrued over month, the first instant of
+// a UTC calendar month, and returns the payouts it made. Wallets already paid for month
+// are skipped, so a rerun never pays twice.
+func (uc *InterestUseCase) PayInterest(ctx context.Context, month time.Time) ([]interest.Payout, error) {
+	totals, err := uc.interestService.ListAccrualTotals(ctx, month)
+	if err != nil {
+		return nil, err
+	}
+
+	var payouts []interest.Payout
+	for _, total := range totals {
+		_, err := uc.interestService.GetPayout(ctx, total.UserID, month)
+		if err == nil {
+			continue
+		}
+		if !errors.Is(err, interest.ErrPayoutNotFound) {
+			return payouts, err
+		}
+
+		p, err := uc.payout(ctx, total, month)
+		if errors.Is(err, interest.ErrAlreadyPaid) {
+			continue
+		}
+		if err != nil {
+			return payouts, err
+		}
+		payouts = append(payouts, p)
+	}
+	return payouts, nil
+}
+
+// payout credits total to its wallet from the house account and records the payout. The
+// fraction of a minor unit is carried over to the next month, and nothing moves when the
+// amount is less than one minor unit. The house account is locked first, so concurrent
+// payouts do not overwrite its balance.
+func (uc *InterestUseCase) payout(ctx context.Context, total interest.Total, month time.Time) (interest.Payout, error) {
+	product, ok := uc.interestService.Product(total.Currency)
+	if !ok {
+		return interest.Payout{}, interest.ErrNoProduct
+	}
+
+	var result interest.Payout
+	err := uc.walletUC.audited(ctx, audit.ActionInterestPayout, []string{product.HouseAccount, total.UserID}, func(ctx context.Context, e *audit.Entry) error {
+		if _, err := uc.walletUC.walletService.LockWallet(ctx, product.HouseAccount); err != nil {
+			return err
+		}
+		p, err := uc.interestService.NewPayout(ctx, total, month)
+		if err != nil {
+			return err
+		}
+		if p.Amount > 0 {
+			if err := uc.walletUC.walletService.Withdraw(ctx, product.HouseAccount, p.Amount); err != nil {
+				return err
+			}
+			if err := uc.walletUC.walletService.Deposit(ctx, p.UserID, p.Amount); err != nil {
+				return err
+			}
+			tx, err := uc.walletUC.logTransaction(ctx, product.HouseAccount, p.UserID, p.Amount, p.Currency, transaction.TransactionTypeInterest)
+			if err != nil {
+				return err
+			}
+			e.TransactionID = tx.ID
+			p.TransactionID = tx.ID
+		}
+
+		if err := uc.interestService.RecordPayout(ctx, p); err != nil {
+			return err
+		}
+		result = p
+		return nil
+	})
+	if err != nil {
+		return interest.Payout{}, err
+	}
+	return result, nil
+}
+
+func (uc *InterestUseCase) ListPayouts(ctx context.Context, userID string, limit, offset int) ([]interest.Payout, error) {
+	return uc.interestService.ListPayouts(ctx, userID, limit, offset)
+}
+
+// RunInterest accrues the interest of each day once it has ended, and pays out the
+// previous month on the first day of the next, until ctx is cancelled.
+func (uc *InterestUseCase) RunInterest(ctx context.Context, delay time.Duration) {
+	runAtCutoffs(ctx, 24*time.Hour, delay, func(cutoff time.Time) {
+		cutoff = cutoff.UTC()
+		day := cutoff.AddDate(0, 0, -1)
+		if _, err := uc.AccrueInterest(ctx, day); err != nil && ctx.Err() == nil {
+			log.Println("interest accrual:", err)
+			return
+		}
+		if cutoff.Day() != 1 {
+			return
+		}
+
+		payouts, err := uc.PayInterest(ctx, interest.Month(day))
+		if err != nil {
+			if ctx.Err() == nil {
+				log.Println("interest payout:", err)
+			}
+			return
+		}
+		log.Printf("paid interest for %s to %d wallets", day.Format("2006-01"), len(payouts))
+	})
+}
@@ -0,0 +1,225 @@
+package usecase
+
+import (
+	"context"
+	"sort"
+	"sync"
+	"testing"
+	"time"
+
+	"exchange/internal/domain/escrow"
+	"exchange/internal/domain/interest"
+	"exchange/internal/domain/transaction"
+	"exchange/internal/domain/wallet"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+)
+
+// interestStore keeps accruals and payouts in memory for the use case tests.
+type interestStore struct {
+	mu       sync.Mutex
+	accruals []interest.Accrual
+	payouts  []interest.Payout
+}
+
+func (s *interestStore) CreateAccrual(ctx context.Context, a interest.Accrual) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	for _, existing := range s.accruals {
+		if existing.UserID == a.UserID && existing.Day.Equal(a.Day) {
+			return interest.ErrAlreadyAccrued
+		}
+	}
+	s.accruals = append(s.accruals, a)
+	return nil
+}
+
+func (s *interestStore) ListAccrualTotals(ctx context.Context, from, to time.Time) ([]interest.Total, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	byUser := make(map[string]interest.Total)
+	for _, a := range s.accruals {
+		if a.Day.Before(from) || !a.Day.Before(to) {
+			continue
+		}
+		total := byUser[a.UserID]
+		total.UserID, total.Currency = a.UserID, a.Currency
+		total.Micros += a.Micros
+		byUser[a.UserID] = total
+	}
+	var totals []interest.Total
+	for _, total := range byUser {
+		totals = append(totals, total)
+	}
+	sort.Slice(totals, func(i, j int) bool { return totals[i].UserID < totals[j].UserID })
+	return totals, nil
+}
+
+func (s *interestStore) CreatePayout(ctx context.Context, p interest.Payout) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	for _, existing := range s.payouts {
+		if existing.UserID == p.UserID && existing.Month.Equal(p.Month) {
+			return interest.ErrAlreadyPaid
+		}
+	}
+	s.payouts = append(s.payouts, p)
+	return nil
+}
+
+func (s *interestStore) GetPayout(ctx context.Context, userID string, month time.Time) (interest.Payout, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	for _, p := range s.payouts {
+		if p.UserID == userID && p.Month.Equal(month) {
+			return p, nil
+		}
+	}
+	return interest.Payout{}, interest.ErrPayoutNotFound
+}
+
+func (s *interestStore) GetLatestPayout(ctx context.Context, userID string, month time.Time) (interest.Payout, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	var latest *interest.Payout
+	for i, p := range s.payouts {
+		if p.UserID == userID && p.Month.Before(month) && (latest == nil || p.Month.After(latest.Month)) {
+			latest = &s.payouts[i]
+		}
+	}
+	if latest == nil {
+		return interest.Payout{}, interest.ErrPayoutNotFound
+	}
+	return *latest, nil
+}
+
+func (s *interestStore) ListPayouts(ctx context.Context, userID string, limit, offset int) ([]interest.Payout, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	var result []interest.Payout
+	for _, p := range s.payouts {
+		if userID == "" || p.UserID == userID {
+			result = append(result, p)
+		}
+	}
+	return result, nil
+}
+
+func TestInterestUseCase(t *testing.T) {
+	ctx := context.Background()
+	march30 := time.Date(2024, 3, 30, 0, 0, 0, 0, time.UTC)
+	march31 := time.Date(2024, 3, 31, 0, 0, 0, 0, time.UTC)
+	march := interest.Month(march30)
+
+	product, err := interest.NewProduct("USD", "house", 100, []interest.Tier{{From: 0, APY: 5}})
+	require.NoError(t, err)
+	daily := product.DailyMicros(1_000_000)
+	require.Positive(t, daily)
+	owed := 2 * daily / interest.MicrosPerUnit
+
+	mockWalletService := new(MockWalletService)
+	mockTransactionService := new(MockTransactionService)
+	mockTxManager := new(MockTransactionManager)
+	mockTxManager.DoFn = func(ctx context.Context, fn func(context.Context) error) error {
+		return fn(ctx)
+	}
+	audits := new(auditRecorder)
+	deps := testDependencies(mockWalletService, mockTransactionService, mockTxManager)
+	deps.Audit = audits
+	deps.HouseAccounts = []string{"house", "treasury"}
+	walletUC := NewWalletUseCase(deps)
+	store := new(interestStore)
+	useCase := NewInterestUseCase(walletUC, interest.NewInterestService(store, []interest.Product{product}))
+
+	wallets := []wallet.Wallet{
+		{UserID: "house", Balance: 1_000_000_000, Currency: "USD"},
+		{UserID: "saver", Balance: 1_000_000, Currency: "USD"},
+		{UserID: "small", Balance: 50, Currency: "USD"},
+		{UserID: "euro", Balance: 1_000_000, Currency: "EUR"},
+		{UserID: escrow.AccountID("USD"), Balance: 1_000_000, Currency: "USD"},
+		{UserID: "treasury", Balance: 1_000_000, Currency: "USD"},
+	}
+	mockWalletService.On("SearchWallets", ctx, wallet.SearchFilter{}, snapshotBatchSize, 0).Return(wallets, nil)
+	for _, w := range wallets {
+		mockWalletService.On("LockWallet", ctx, w.UserID).Return(w, nil)
+	}
+	mockTransactionService.On("GetNetAmountSince", ctx, mock.Anything, mock.Anything).Return(int64(0), nil)
+	mockWalletService.On("Withdraw", ctx, "house", owed).Return(nil)
+	mockWalletService.On("Deposit", ctx, "saver", owed).Return(nil)
+	mockTransactionService.On("LogTransaction", ctx, "house", "saver", owed, "USD", transaction.TransactionTypeInterest).
+		Return(transaction.Transaction{ID: "tx1", Type: transaction.TransactionTypeInterest}, nil)
+
+	for _, day := range []time.Time{march30, march31} {
+		n, err := useCase.AccrueInterest(ctx, day)
+
+		require.NoError(t, err)
+		assert.Equal(t, 1, n)
+	}
+	require.Len(t, store.accruals, 2)
+	assert.Equal(t, "saver", store.accruals[0].UserID)
+	assert.Equal(t, daily, store.accruals[0].Micros)
+	for _, a := range store.accruals {
+		assert.NotEqual(t, "treasury", a.UserID, "house accounts earn no interest")
+	}
+
+	t.Run("rerun of an accrued day", func(t *testing.T) {
+		n, err := useCase.AccrueInterest(ctx, march31)
+
+		require.NoError(t, err)
+		assert.Zero(t, n)
+		assert.Len(t, store.accruals, 2)
+	})
+
+	t.Run("day not at midnight", func(t *testing.T) {
+		_, err := useCase.AccrueInterest(ctx, march31.Add(time.Hour))
+
+		assert.Equal(t, interest.ErrInvalidDay, err)
+	})
+
+	t.Run("day not over yet", func(t *testing.T) {
+		_, err := useCase.AccrueInterest(ctx, interest.Day(time.Now()))
+
+		assert.Equal(t, interest.ErrInvalidDay, err)
+	})
+
+	t.Run("monthly payout", func(t *testing.T) {
+		houseLocks := func() int {
+			n := 0
+			for _, call := range mockWalletService.Calls {
+				if call.Method == "LockWallet" && call.Arguments.String(1) == "house" {
+					n++
+				}
+			}
+			return n
+		}
+		locked := houseLocks()
+
+		payouts, err := useCase.PayInterest(ctx, march)
+
+		require.NoError(t, err)
+		require.Len(t, payouts, 1)
+		assert.Equal(t, "saver", payouts[0].UserID)
+		assert.Equal(t, owed, payouts[0].Amount)
+		assert.Equal(t, 2*daily%interest.MicrosPerUnit, payouts[0].Carry)
+		assert.Equal(t, "tx1", payouts[0].TransactionID)
+		mockTransactionService.AssertCalled(t, "LogTransaction", ctx, "house", "saver", owed, "USD", transaction.TransactionTypeInterest)
+		assert.Equal(t, "tx1", audits.entries[len(audits.entries)-1].TransactionID)
+		assert.Equal(t, locked+1, houseLocks(), "the house account is locked before it is debited")
+	})
+
+	t.Run("rerun of a paid month", func(t *testing.T) {
+		payouts, err := useCase.PayInterest(ctx, march)
+
+		require.NoError(t, err)
+		assert.Empty(t, payouts)
+		mockWalletService.AssertNumberOfCalls(t, "Withdraw", 1)
+	})
+
+	t.Run("unfinished month", func(t *testing.T) {
+		_, err := useCase.PayInterest(ctx, interest.Month(time.Now()))
+
+		assert.Equal(t, interest.ErrInvalidMonth, err)
+	})
+}
@@ -0,0 +1,49 @@
+package usecase
+
+import (
+	"context"
+)
+
+// checkBalanceCap fails when crediting amount to userID's wallet would take it over the
+// balance cap of their KYC level. It locks the wallet before reading the balance, so
+// concurrent credits are checked one after the other and cannot both slip under the cap;
+// callers must run it inside txManager.Do before moving the funds.
+func (uc *WalletUseCase) checkBalanceCap(ctx context.Context, userID string, amount int64) error {
+	caps, err := uc.kycService.GetCaps(ctx, userID)
+	if err != nil {
+		return err
+	}
+	if caps.MaxBalance == 0 {
+		return nil
+	}
+	w, err := uc.walletService.LockWallet(ctx, userID)
+	if err != nil {
+		return err
+	}
+	return caps.CheckBalance(w.Balance + amount)
+}
+
+// checkWithdrawalCap fails when amount is more than userID's KYC level lets them withdraw
+// at once.
+func (uc *WalletUseCase) checkWithdrawalCap(ctx context.Context, userID string, amount int64) error {
+	caps, err := uc.kycService.GetCaps(ctx, userID)
+	if err != nil {
+		return err
+	}
+	return caps.CheckWithdrawal(amount)
+}
+
+// checkTransferCaps fails when amount is more than the KYC level of either the sender or
+// the recipient lets them transfer at once.
+func (uc *WalletUseCase) checkTransferCaps(ctx context.Context, fromUserID, toUserID string, amount int64) error {
+	for _, userID := range []string{fromUserID, toUserID} {
+		caps, err := uc.kycService.GetCaps(ctx, userID)
+		if err != nil {
+			return err
+		}
+		if err := caps.CheckTransfer(amount); err != nil {
+			return err
+		}
+	}
+	return nil
+}
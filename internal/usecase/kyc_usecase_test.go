@@ -0,0 +1,183 @@
+// kyc_usecase_test.go
+package usecase
+
+import (
+	"context"
+	"sync"
+	"testing"
+	"time"
+
+	"exchange/internal/domain/audit"
+	"exchange/internal/domain/kyc"
+	"exchange/internal/domain/transaction"
+	"exchange/internal/domain/wallet"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+)
+
+// lockingWallets keeps balances in memory and, like the database, holds the lock
+// LockWallet takes on a wallet until the surrounding transaction ends. Its Do is the
+// transaction manager.
+type lockingWallets struct {
+	MockWalletService
+	mu       sync.Mutex
+	balances map[string]int64
+	locks    map[string]*sync.Mutex
+}
+
+type heldLocksKey struct{}
+
+func (w *lockingWallets) Do(ctx context.Context, fn func(context.Context) error) error {
+	var held []*sync.Mutex
+	err := fn(context.WithValue(ctx, heldLocksKey{}, &held))
+	for _, l := range held {
+		l.Unlock()
+	}
+	return err
+}
+
+func (w *lockingWallets) GetWallet(ctx context.Context, userID string) (wallet.Wallet, error) {
+	w.mu.Lock()
+	defer w.mu.Unlock()
+	return wallet.Wallet{UserID: userID, Balance: w.balances[userID], Currency: "USD"}, nil
+}
+
+func (w *lockingWallets) LockWallet(ctx context.Context, userID string) (wallet.Wallet, error) {
+	w.mu.Lock()
+	l, ok := w.locks[userID]
+	if !ok {
+		l = new(sync.Mutex)
+		w.locks[userID] = l
+	}
+	w.mu.Unlock()
+
+	l.Lock()
+	held := ctx.Value(heldLocksKey{}).(*[]*sync.Mutex)
+	*held = append(*held, l)
+	return w.GetWallet(ctx, userID)
+}
+
+func (w *lockingWallets) Deposit(ctx context.Context, userID string, amount int64) error {
+	// Give a concurrent credit time to read the balance before this one lands.
+	time.Sleep(10 * time.Millisecond)
+	w.mu.Lock()
+	defer w.mu.Unlock()
+	w.balances[userID] += amount
+	return nil
+}
+
+func TestWalletUseCase_KYCCaps(t *testing.T) {
+	ctx := context.Background()
+	caps := fixedKYC{"newbie": {MaxBalance: 1000, MaxWithdrawal: 200, MaxTransfer: 100}}
+
+	newUseCase := func() (*WalletUseCase, *MockWalletService, *MockTransactionManager) {
+		mockWalletService := new(MockWalletService)
+		mockTxManager := new(MockTransactionManager)
+		mockTxManager.DoFn = func(ctx context.Context, fn func(context.Context) error) error {
+			return fn(ctx)
+		}
+		deps := testDependencies(mockWalletService, new(MockTransactionService), mockTxManager)
+		deps.KYC = caps
+		return NewWalletUseCase(deps), mockWalletService, mockTxManager
+	}
+
+	t.Run("deposit above the balance cap", func(t *testing.T) {
+		useCase, mockWalletService, _ := newUseCase()
+		mockWalletService.On("Deposit", ctx, "newbie", int64(600)).Return(nil)
+		mockWalletService.On("LockWallet", ctx, "newbie").Return(wallet.Wallet{UserID: "newbie", Balance: 1100}, nil)
+
+		err := useCase.Deposit(ctx, "newbie", 600, "USD")
+
+		assert.ErrorIs(t, err, kyc.ErrBalanceCapExceeded)
+		entries := useCase.auditService.(*auditRecorder).entries
+		require.Len(t, entries, 1)
+		assert.Equal(t, audit.OutcomeFailure, entries[0].Outcome)
+	})
+
+	t.Run("withdrawal above the cap", func(t *testing.T) {
+		useCase, mockWalletService, _ := newUseCase()
+
+		_, err := useCase.Withdraw(ctx, "newbie", 201, "USD", "")
+
+		assert.ErrorIs(t, err, kyc.ErrWithdrawalCapExceeded)
+		mockWalletService.AssertNotCalled(t, "Withdraw", mock.Anything, mock.Anything, mock.Anything)
+	})
+
+	t.Run("pending withdrawal above the cap", func(t *testing.T) {
+		useCase, mockWalletService, _ := newUseCase()
+
+		_, err := useCase.RequestWithdrawal(ctx, "newbie", 201, "USD", "")
+
+		assert.ErrorIs(t, err, kyc.ErrWithdrawalCapExceeded)
+		mockWalletService.AssertNotCalled(t, "Hold", mock.Anything, mock.Anything, mock.Anything)
+	})
+
+	t.Run("transfer to an unverified user above the threshold", func(t *testing.T) {
+		useCase, mockWalletService, _ := newUseCase()
+
+		err := useCase.Transfer(ctx, "user1", "newbie", 101, "USD")
+
+		assert.ErrorIs(t, err, kyc.ErrTransferCapExceeded)
+		mockWalletService.AssertNotCalled(t, "Withdraw", mock.Anything, mock.Anything, mock.Anything)
+	})
+
+	t.Run("transfer from an unverified user above the threshold", func(t *testing.T) {
+		useCase, _, _ := newUseCase()
+
+		err := useCase.Transfer(ctx, "newbie", "user1", 101, "USD")
+
+		assert.ErrorIs(t, err, kyc.ErrTransferCapExceeded)
+	})
+
+	t.Run("transfer filling the recipient above the balance cap", func(t *testing.T) {
+		useCase, mockWalletService, _ := newUseCase()
+		mockWalletService.On("Withdraw", ctx, "user1", int64(100)).Return(nil)
+		mockWalletService.On("Deposit", ctx, "newbie", int64(100)).Return(nil)
+		mockWalletService.On("LockWallet", ctx, "newbie").Return(wallet.Wallet{UserID: "newbie", Balance: 1050}, nil)
+
+		err := useCase.Transfer(ctx, "user1", "newbie", 100, "USD")
+
+		assert.ErrorIs(t, err, kyc.ErrBalanceCapExceeded)
+	})
+
+	t.Run("concurrent deposits above the balance cap", func(t *testing.T) {
+		wallets := &lockingWallets{balances: map[string]int64{"newbie": 500}, locks: map[string]*sync.Mutex{}}
+		mockTransactionService := new(MockTransactionService)
+		mockTransactionService.On("LogTransaction", mock.Anything, "", "newbie", int64(300), "USD", transaction.TransactionTypeDeposit).
+			Return(transaction.Transaction{ID: "tx1"}, nil)
+		mockTxManager := &MockTransactionManager{DoFn: wallets.Do}
+		deps := testDependencies(wallets, mockTransactionService, mockTxManager)
+		deps.KYC = caps
+		useCase := NewWalletUseCase(deps)
+
+		errs := make([]error, 2)
+		var wg sync.WaitGroup
+		for i := range errs {
+			wg.Add(1)
+			go func() {
+				defer wg.Done()
+				errs[i] = useCase.Deposit(ctx, "newbie", 300, "USD")
+			}()
+		}
+		wg.Wait()
+
+		assert.Equal(t, int64(800), wallets.balances["newbie"], "only one of the deposits fits under the cap")
+		assert.ElementsMatch(t, []error{nil, kyc.ErrBalanceCapExceeded}, errs)
+	})
+
+	t.Run("admin reviews a submission", func(t *testing.T) {
+		useCase, _, _ := newUseCase()
+		adminUC := NewAdminUseCase(useCase, nil, closedThrough{}, 1000)
+
+		s, err := adminUC.ReviewKYCSubmission(ctx, "ops", "kyc1", false, "blurry scan")
+
+		require.NoError(t, err)
+		assert.Equal(t, kyc.SubmissionRejected, s.Status)
+		entries := useCase.auditService.(*auditRecorder).entries
+		require.Len(t, entries, 1)
+		assert.Equal(t, audit.ActionKYCReject, entries[0].Action)
+		assert.Equal(t, "kyc1", entries[0].Target)
+	})
+}
@@ -0,0 +1,85 @@
+package usecase
+
+import (
+	"context"
+	"time"
+
+	"exchange/internal/domain/limit"
+	"exchange/internal/domain/transaction"
+)
+
+// limitedTypes maps each operation a limit can cap to the transactions that use it up.
+// Funding an escrow pays another user, so it counts as a transfer.
+var limitedTypes = map[limit.Operation][]transaction.TransactionType{
+	limit.OperationWithdrawal: {transaction.TransactionTypeWithdraw},
+	limit.OperationTransfer:   {transaction.TransactionTypeTransfer, transaction.TransactionTypeEscrow},
+}
+
+// GetLimits returns the limits that apply to userID with their usage in the current period.
+func (uc *WalletUseCase) GetLimits(ctx context.Context, userID string) ([]limit.Allowance, error) {
+	if _, err := uc.walletService.GetWallet(ctx, userID); err != nil {
+		return nil, err
+	}
+	limits, err := uc.limitService.GetLimits(ctx, userID)
+	if err != nil {
+		return nil, err
+	}
+
+	now := time.Now()
+	allowances := make([]limit.Allowance, 0, len(limits))
+	for _, l := range limits {
+		a, err := uc.allowance(ctx, userID, l, now)
+		if err != nil {
+			return nil, err
+		}
+		allowances = append(allowances, a)
+	}
+	return allowances, nil
+}
+
+// checkLimits returns a *limit.ExceededError if userID sending amount of currency by op
+// would exceed one of their limits. It locks the wallet before adding up the usage, so
+// concurrent operations of the same user are checked one after the other and cannot both
+// slip under a cap; callers must run it inside txManager.Do before moving the funds.
+func (uc *WalletUseCase) checkLimits(ctx context.Context, userID string, op limit.Operation, amount int64, currency string) error {
+	limits, err := uc.limitService.GetLimits(ctx, userID)
+	if err != nil {
+		return err
+	}
+
+	now := time.Now()
+	locked := false
+	for _, l := range limits {
+		if !l.Applies(op, currency) {
+			continue
+		}
+		if !locked {
+			if _, err := uc.walletService.LockWallet(ctx, userID); err != nil {
+				return err
+			}
+			locked = true
+		}
+
+		a, err := uc.allowance(ctx, userID, l, now)
+		if err != nil {
+			return err
+		}
+		if err := a.Check(amount); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func (uc *WalletUseCase) allowance(ctx context.Context, userID string, l limit.Limit, now time.Time) (limit.Allowance, error) {
+	var usage limit.Usage
+	for _, tType := range limitedTypes[l.Operation] {
+		amount, count, err := uc.transactionService.GetOutgoingSince(ctx, userID, tType, l.Currency, l.Period.Start(now))
+		if err != nil {
+			return limit.Allowance{}, err
+		}
+		usage.Amount += amount
+		usage.Count += count
+	}
+	return limit.NewAllowance(l, usage, now), nil
+}
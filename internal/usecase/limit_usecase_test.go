@@ -0,0 +1,102 @@
+// limit_usecase_test.go
+package usecase
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"exchange/internal/domain/limit"
+	"exchange/internal/domain/transaction"
+	"exchange/internal/domain/wallet"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+)
+
+func TestWalletUseCase_Limits(t *testing.T) {
+	ctx := context.Background()
+	limits := fixedLimits{
+		{Operation: limit.OperationWithdrawal, Currency: "USD", Period: limit.PeriodDaily, MaxAmount: 1000, MaxCount: 5},
+		{Operation: limit.OperationTransfer, Currency: "USD", Period: limit.PeriodMonthly, MaxCount: 2},
+	}
+
+	newUseCase := func() (*WalletUseCase, *MockWalletService, *MockTransactionService) {
+		mockWalletService := new(MockWalletService)
+		mockTransactionService := new(MockTransactionService)
+		mockTxManager := new(MockTransactionManager)
+		mockTxManager.DoFn = func(ctx context.Context, fn func(context.Context) error) error {
+			return fn(ctx)
+		}
+		deps := testDependencies(mockWalletService, mockTransactionService, mockTxManager)
+		deps.Limits = limits
+		return NewWalletUseCase(deps), mockWalletService, mockTransactionService
+	}
+
+	t.Run("withdrawal within the limit", func(t *testing.T) {
+		useCase, mockWalletService, mockTransactionService := newUseCase()
+		mockWalletService.On("LockWallet", ctx, "user1").Return(wallet.Wallet{UserID: "user1"}, nil)
+		mockTransactionService.On("GetOutgoingSince", ctx, "user1", transaction.TransactionTypeWithdraw, "USD", mock.Anything).Return(int64(600), 1, nil)
+		mockWalletService.On("Withdraw", ctx, "user1", int64(400)).Return(nil)
+		mockTransactionService.On("LogTransaction", ctx, "user1", "", int64(400), "USD", transaction.TransactionTypeWithdraw).Return(transaction.Transaction{ID: "tx1"}, nil)
+
+		_, err := useCase.Withdraw(ctx, "user1", 400, "USD", "")
+
+		require.NoError(t, err)
+		mockWalletService.AssertExpectations(t)
+	})
+
+	t.Run("withdrawal above the limit", func(t *testing.T) {
+		useCase, mockWalletService, mockTransactionService := newUseCase()
+		mockWalletService.On("LockWallet", ctx, "user1").Return(wallet.Wallet{UserID: "user1"}, nil)
+		mockTransactionService.On("GetOutgoingSince", ctx, "user1", transaction.TransactionTypeWithdraw, "USD", mock.Anything).Return(int64(600), 1, nil)
+
+		_, err := useCase.Withdraw(ctx, "user1", 401, "USD", "")
+
+		assert.ErrorIs(t, err, limit.ErrLimitExceeded)
+		var exceeded *limit.ExceededError
+		require.True(t, errors.As(err, &exceeded))
+		assert.Equal(t, int64(400), exceeded.Allowance.RemainingAmount())
+		assert.Equal(t, 4, exceeded.Allowance.RemainingCount())
+		mockWalletService.AssertNotCalled(t, "Withdraw", mock.Anything, mock.Anything, mock.Anything)
+	})
+
+	t.Run("transfer count used up", func(t *testing.T) {
+		useCase, mockWalletService, mockTransactionService := newUseCase()
+		mockWalletService.On("LockWallet", ctx, "user1").Return(wallet.Wallet{UserID: "user1"}, nil)
+		mockTransactionService.On("GetOutgoingSince", ctx, "user1", transaction.TransactionTypeTransfer, "USD", mock.Anything).Return(int64(100), 1, nil)
+		mockTransactionService.On("GetOutgoingSince", ctx, "user1", transaction.TransactionTypeEscrow, "USD", mock.Anything).Return(int64(50), 1, nil)
+
+		err := useCase.Transfer(ctx, "user1", "user2", 1, "USD")
+
+		assert.ErrorIs(t, err, limit.ErrLimitExceeded)
+	})
+
+	t.Run("other currency is not limited", func(t *testing.T) {
+		useCase, mockWalletService, mockTransactionService := newUseCase()
+		mockWalletService.On("Withdraw", ctx, "user1", int64(5000)).Return(nil)
+		mockTransactionService.On("LogTransaction", ctx, "user1", "", int64(5000), "EUR", transaction.TransactionTypeWithdraw).Return(transaction.Transaction{ID: "tx1"}, nil)
+
+		_, err := useCase.Withdraw(ctx, "user1", 5000, "EUR", "")
+
+		require.NoError(t, err)
+		mockWalletService.AssertNotCalled(t, "LockWallet", mock.Anything, mock.Anything)
+	})
+
+	t.Run("get limits", func(t *testing.T) {
+		useCase, mockWalletService, mockTransactionService := newUseCase()
+		mockWalletService.On("GetWallet", ctx, "user1").Return(wallet.Wallet{UserID: "user1"}, nil)
+		mockTransactionService.On("GetOutgoingSince", ctx, "user1", transaction.TransactionTypeWithdraw, "USD", mock.Anything).Return(int64(600), 1, nil)
+		mockTransactionService.On("GetOutgoingSince", ctx, "user1", transaction.TransactionTypeTransfer, "USD", mock.Anything).Return(int64(100), 1, nil)
+		mockTransactionService.On("GetOutgoingSince", ctx, "user1", transaction.TransactionTypeEscrow, "USD", mock.Anything).Return(int64(0), 0, nil)
+
+		allowances, err := useCase.GetLimits(ctx, "user1")
+
+		require.NoError(t, err)
+		require.Len(t, allowances, 2)
+		assert.Equal(t, limit.Usage{Amount: 600, Count: 1}, allowances[0].Used)
+		assert.Equal(t, int64(-1), allowances[1].RemainingAmount())
+		assert.Equal(t, 1, allowances[1].RemainingCount())
+	})
+}
@@ -0,0 +1,209 @@
+package usecase
+
+import (
+	"context"
+	"errors"
+	"log"
+	"time"
+
+	"exchange/internal/domain/audit"
+	"exchange/internal/domain/payment"
+)
+
+// PaymentRequest describes the money a requester asks a payer for. An empty PayerID
+// makes the request open to anyone who has its link.
+type PaymentRequest struct {
+	RequesterID string
+	PayerID     string
+	Amount      int64
+	Currency    string
+	Memo        string
+	ExpiresAt   time.Time
+}
+
+// PaymentRequestUseCase lets users request money from each other. Paying a request is a
+// transfer from the payer to the requester, made through WalletUseCase so it is screened,
+// capped and limited like any other transfer.
+type PaymentRequestUseCase struct {
+	walletUC       *WalletUseCase
+	requestService payment.RequestServiceInterface
+}
+
+func NewPaymentRequestUseCase(walletUC *WalletUseCase, rService payment.RequestServiceInterface) *PaymentRequestUseCase {
+	return &PaymentRequestUseCase{
+		walletUC:       walletUC,
+		requestService: rService,
+	}
+}
+
+// RequestPayment creates a pending request in the currency of the requester's wallet.
+func (uc *PaymentRequestUseCase) RequestPayment(ctx context.Context, req PaymentRequest) (payment.Request, error) {
+	userIDs := []string{req.RequesterID}
+	if req.PayerID != "" {
+		userIDs = append(userIDs, req.PayerID)
+	}
+
+	var result payment.Request
+	err := uc.walletUC.audited(ctx, audit.ActionPaymentRequest, userIDs, func(ctx context.Context, e *audit.Entry) error {
+		for _, userID := range userIDs {
+			if err := uc.walletUC.userService.CheckActive(ctx, userID); err != nil {
+				return err
+			}
+		}
+		w, err := uc.walletUC.walletService.GetWallet(ctx, req.RequesterID)
+		if err != nil {
+			return err
+		}
+		if w.Currency != req.Currency {
+			return payment.ErrCurrencyMismatch
+		}
+
+		result, err = uc.requestService.Create(ctx, req.RequesterID, req.PayerID, req.Amount, req.Currency, req.Memo, req.ExpiresAt)
+		e.Target = result.ID
+		return err
+	})
+	if err != nil {
+		return payment.Request{}, err
+	}
+	return result, nil
+}
+
+// AcceptPaymentRequest pays request id from payerID's wallet: its payer, or anyone but
+// the requester when the request is open.
+func (uc *PaymentRequestUseCase) AcceptPaymentRequest(ctx context.Context, id, payerID string) (payment.Request, error) {
+	current, err := uc.requestService.GetRequest(ctx, id)
+	if err != nil {
+		return payment.Request{}, err
+	}
+	if err := uc.requestService.Payable(current, payerID); err != nil {
+		return payment.Request{}, err
+	}
+	if _, err := uc.walletUC.screen(ctx, transferRiskRequest(payerID, current.RequesterID, current.Amount, current.Currency)); err != nil {
+		uc.walletUC.recordFailure(ctx, audit.NewEntry(audit.ActionPaymentPay, payerID, current.RequesterID), err)
+		return payment.Request{}, err
+	}
+
+	var result payment.Request
+	err = uc.walletUC.audited(ctx, audit.ActionPaymentPay, []string{payerID, current.RequesterID}, func(ctx context.Context, e *audit.Entry) error {
+		e.Target = id
+		r, err := uc.requestService.GetRequest(ctx, id)
+		if err != nil {
+			return err
+		}
+		if err := uc.requestService.Payable(r, payerID); err != nil {
+			return err
+		}
+		w, err := uc.walletUC.walletService.GetWallet(ctx, payerID)
+		if err != nil {
+			return err
+		}
+		if w.Currency != r.Currency {
+			return payment.ErrCurrencyMismatch
+		}
+
+		tx, err := uc.walletUC.transfer(ctx, payerID, r.RequesterID, r.Amount, r.Currency)
+		if err != nil {
+			return err
+		}
+		e.TransactionID = tx.ID
+
+		result, err = uc.requestService.Pay(ctx, r, payerID, tx.ID)
+		return err
+	})
+	if err != nil {
+		return payment.Request{}, err
+	}
+	return result, nil
+}
+
+// DeclinePaymentRequest refuses request id on behalf of its payer.
+func (uc *PaymentRequestUseCase) DeclinePaymentRequest(ctx context.Context, id, userID string) (payment.Request, error) {
+	return uc.close(ctx, audit.ActionPaymentDecline, id, func(ctx context.Context, r payment.Request) (payment.Request, error) {
+		return uc.requestService.Decline(ctx, r, userID)
+	})
+}
+
+// CancelPaymentRequest withdraws request id on behalf of its requester.
+func (uc *PaymentRequestUseCase) CancelPaymentRequest(ctx context.Context, id, userID string) (payment.Request, error) {
+	return uc.close(ctx, audit.ActionPaymentCancel, id, func(ctx context.Context, r payment.Request) (payment.Request, error) {
+		return uc.requestService.Cancel(ctx, r, userID)
+	})
+}
+
+// close closes request id with fn, which moves no funds.
+func (uc *PaymentRequestUseCase) close(ctx context.Context, action audit.Action, id string, fn func(ctx context.Context, r payment.Request) (payment.Request, error)) (payment.Request, error) {
+	current, err := uc.requestService.GetRequest(ctx, id)
+	if err != nil {
+		return payment.Request{}, err
+	}
+	userIDs := []string{current.RequesterID}
+	if current.PayerID != "" {
+		userIDs = append(userIDs, current.PayerID)
+	}
+
+	var result payment.Request
+	err = uc.walletUC.audited(ctx, action, userIDs, func(ctx context.Context, e *audit.Entry) error {
+		e.Target = id
+		result, err = fn(ctx, current)
+		return err
+	})
+	if err != nil {
+		return payment.Request{}, err
+	}
+	return result, nil
+}
+
+func (uc *PaymentRequestUseCase) GetPaymentRequest(ctx context.Context, id string) (payment.Request, error) {
+	return uc.requestService.GetRequest(ctx, id)
+}
+
+// PreviewPaymentRequest resolves the token of a request's shareable link.
+func (uc *PaymentRequestUseCase) PreviewPaymentRequest(ctx context.Context, token string) (payment.Request, error) {
+	return uc.requestService.GetRequestByToken(ctx, token)
+}
+
+func (uc *PaymentRequestUseCase) ListPaymentRequests(ctx context.Context, filter payment.Filter, limit, offset int) ([]payment.Request, error) {
+	return uc.requestService.ListRequests(ctx, filter, limit, offset)
+}
+
+// ExpirePaymentRequests closes up to one batch of pending requests that expired by now,
+// and returns how many it closed. Requests closed in the meantime are skipped.
+func (uc *PaymentRequestUseCase) ExpirePaymentRequests(ctx context.Context, now time.Time) (int, error) {
+	expired, err := uc.requestService.ListExpired(ctx, now, expiryBatchSize)
+	if err != nil {
+		return 0, err
+	}
+
+	closed := 0
+	for _, r := range expired {
+		_, err := uc.requestService.Expire(ctx, r, now)
+		if errors.Is(err, payment.ErrRequestClosed) {
+			continue
+		}
+		if err != nil {
+			return closed, err
+		}
+		closed++
+	}
+	return closed, nil
+}
+
+// RunPaymentRequestExpiry closes expired requests until ctx is cancelled. It keeps going
+// while full batches are found and otherwise waits interval before looking again.
+func (uc *PaymentRequestUseCase) RunPaymentRequestExpiry(ctx context.Context, interval time.Duration) {
+	for {
+		n, err := uc.ExpirePaymentRequests(ctx, time.Now())
+		if err != nil && ctx.Err() == nil {
+			log.Println("payment request expiry:", err)
+		}
+		if err == nil && n == expiryBatchSize {
+			continue
+		}
+
+		select {
+		case <-ctx.Done():
+			return
+		case <-time.After(interval):
+		}
+	}
+}
@@ -0,0 +1,219 @@
+package usecase
+
+import (
+	"context"
+	"sync"
+	"testing"
+	"time"
+
+	"exchange/internal/domain/payment"
+	"exchange/internal/domain/transaction"
+	"exchange/internal/domain/wallet"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+)
+
+// requestStore keeps payment requests in memory for the use case tests.
+type requestStore struct {
+	mu       sync.Mutex
+	requests []payment.Request
+}
+
+func (s *requestStore) CreateRequest(ctx context.Context, r payment.Request) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.requests = append(s.requests, r)
+	return nil
+}
+
+func (s *requestStore) GetRequestByID(ctx context.Context, id string) (payment.Request, error) {
+	return s.find(func(r payment.Request) bool { return r.ID == id })
+}
+
+func (s *requestStore) GetRequestByToken(ctx context.Context, token string) (payment.Request, error) {
+	return s.find(func(r payment.Request) bool { return r.Token == token })
+}
+
+func (s *requestStore) find(match func(r payment.Request) bool) (payment.Request, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	for _, r := range s.requests {
+		if match(r) {
+			return r, nil
+		}
+	}
+	return payment.Request{}, payment.ErrRequestNotFound
+}
+
+func (s *requestStore) ListRequests(ctx context.Context, filter payment.Filter, limit, offset int) ([]payment.Request, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	var result []payment.Request
+	for _, r := range s.requests {
+		if filter.UserID != "" && r.RequesterID != filter.UserID && r.PayerID != filter.UserID && r.PaidBy != filter.UserID {
+			continue
+		}
+		if filter.Status != "" && r.Status != filter.Status {
+			continue
+		}
+		result = append(result, r)
+	}
+	return result, nil
+}
+
+func (s *requestStore) ListExpired(ctx context.Context, now time.Time, limit int) ([]payment.Request, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	var result []payment.Request
+	for _, r := range s.requests {
+		if r.Expired(now) {
+			result = append(result, r)
+		}
+	}
+	return result, nil
+}
+
+func (s *requestStore) UpdateRequest(ctx context.Context, r payment.Request, from payment.Status) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	for i, stored := range s.requests {
+		if stored.ID == r.ID {
+			if stored.Status != from {
+				return payment.ErrRequestClosed
+			}
+			s.requests[i] = r
+			return nil
+		}
+	}
+	return payment.ErrRequestNotFound
+}
+
+func TestPaymentRequestUseCase(t *testing.T) {
+	ctx := context.Background()
+	amount := int64(500)
+
+	newUseCase := func() (*PaymentRequestUseCase, *MockWalletService, *MockTransactionService, *requestStore) {
+		mockWalletService := new(MockWalletService)
+		mockTransactionService := new(MockTransactionService)
+		mockTxManager := new(MockTransactionManager)
+		mockTxManager.DoFn = func(ctx context.Context, fn func(context.Context) error) error {
+			return fn(ctx)
+		}
+		walletUC := NewWalletUseCase(testDependencies(mockWalletService, mockTransactionService, mockTxManager))
+
+		for _, userID := range []string{"requester", "payer", "other"} {
+			mockWalletService.On("GetWallet", ctx, userID).Return(wallet.Wallet{UserID: userID, Balance: 1000, Currency: "USD"}, nil)
+		}
+		mockWalletService.On("Withdraw", ctx, mock.Anything, amount).Return(nil)
+		mockWalletService.On("Deposit", ctx, "requester", amount).Return(nil)
+		mockTransactionService.On("LogTransaction", ctx, mock.Anything, "requester", amount, "USD", transaction.TransactionTypeTransfer).
+			Return(transaction.Transaction{ID: "tx1", Type: transaction.TransactionTypeTransfer}, nil)
+
+		store := new(requestStore)
+		return NewPaymentRequestUseCase(walletUC, payment.NewRequestService(store)), mockWalletService, mockTransactionService, store
+	}
+	request := PaymentRequest{
+		RequesterID: "requester",
+		PayerID:     "payer",
+		Amount:      amount,
+		Currency:    "USD",
+		Memo:        "dinner",
+		ExpiresAt:   time.Now().Add(time.Hour),
+	}
+
+	t.Run("accepted by the payer", func(t *testing.T) {
+		useCase, mockWalletService, _, _ := newUseCase()
+		r, err := useCase.RequestPayment(ctx, request)
+		require.NoError(t, err)
+		assert.Equal(t, payment.StatusPending, r.Status)
+
+		preview, err := useCase.PreviewPaymentRequest(ctx, r.Token)
+		require.NoError(t, err)
+		assert.Equal(t, r.ID, preview.ID)
+
+		paid, err := useCase.AcceptPaymentRequest(ctx, r.ID, "payer")
+
+		require.NoError(t, err)
+		assert.Equal(t, payment.StatusPaid, paid.Status)
+		assert.Equal(t, "tx1", paid.TransactionID)
+		mockWalletService.AssertCalled(t, "Withdraw", ctx, "payer", amount)
+		mockWalletService.AssertCalled(t, "Deposit", ctx, "requester", amount)
+
+		_, err = useCase.AcceptPaymentRequest(ctx, r.ID, "payer")
+		assert.Equal(t, payment.ErrRequestClosed, err)
+	})
+
+	t.Run("accepted by someone else", func(t *testing.T) {
+		useCase, mockWalletService, _, _ := newUseCase()
+		r, err := useCase.RequestPayment(ctx, request)
+		require.NoError(t, err)
+
+		_, err = useCase.AcceptPaymentRequest(ctx, r.ID, "other")
+
+		assert.Equal(t, payment.ErrNotPayer, err)
+		mockWalletService.AssertNotCalled(t, "Withdraw", ctx, "other", amount)
+	})
+
+	t.Run("open request", func(t *testing.T) {
+		useCase, _, _, _ := newUseCase()
+		req := request
+		req.PayerID = ""
+		r, err := useCase.RequestPayment(ctx, req)
+		require.NoError(t, err)
+
+		_, err = useCase.AcceptPaymentRequest(ctx, r.ID, "requester")
+		assert.Equal(t, payment.ErrSameParty, err)
+
+		paid, err := useCase.AcceptPaymentRequest(ctx, r.ID, "other")
+		require.NoError(t, err)
+		assert.Equal(t, "other", paid.PaidBy)
+	})
+
+	t.Run("currency of another wallet", func(t *testing.T) {
+		useCase, _, _, store := newUseCase()
+		req := request
+		req.Currency = "EUR"
+
+		_, err := useCase.RequestPayment(ctx, req)
+
+		assert.Equal(t, payment.ErrCurrencyMismatch, err)
+		assert.Empty(t, store.requests)
+	})
+
+	t.Run("declined and cancelled", func(t *testing.T) {
+		useCase, _, _, _ := newUseCase()
+		declined, err := useCase.RequestPayment(ctx, request)
+		require.NoError(t, err)
+		cancelled, err := useCase.RequestPayment(ctx, request)
+		require.NoError(t, err)
+
+		_, err = useCase.DeclinePaymentRequest(ctx, declined.ID, "requester")
+		assert.Equal(t, payment.ErrNotPayer, err)
+		r, err := useCase.DeclinePaymentRequest(ctx, declined.ID, "payer")
+		require.NoError(t, err)
+		assert.Equal(t, payment.StatusDeclined, r.Status)
+
+		_, err = useCase.CancelPaymentRequest(ctx, cancelled.ID, "payer")
+		assert.Equal(t, payment.ErrNotRequester, err)
+		r, err = useCase.CancelPaymentRequest(ctx, cancelled.ID, "requester")
+		require.NoError(t, err)
+		assert.Equal(t, payment.StatusCancelled, r.Status)
+
+		_, err = useCase.AcceptPaymentRequest(ctx, cancelled.ID, "payer")
+		assert.Equal(t, payment.ErrRequestClosed, err)
+	})
+
+	t.Run("expiry", func(t *testing.T) {
+		useCase, _, _, store := newUseCase()
+		r, err := useCase.RequestPayment(ctx, request)
+		require.NoError(t, err)
+
+		n, err := useCase.ExpirePaymentRequests(ctx, r.ExpiresAt)
+
+		require.NoError(t, err)
+		assert.Equal(t, 1, n)
+		assert.Equal(t, payment.StatusExpired, store.requests[0].Status)
+	})
+}
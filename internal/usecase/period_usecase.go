@@ -0,0 +1,117 @@
+package usecase
+
+import (
+	"context"
+	"errors"
+	"slices"
+	"strings"
+	"time"
+
+	"exchange/internal/domain/audit"
+	"exchange/internal/domain/period"
+	"exchange/internal/domain/snapshot"
+)
+
+// ReconciliationLine compares how the total balance of one currency moved over a day with
+// the net of the day's transactions in it.
+type ReconciliationLine struct {
+	Currency   string
+	Opening    int64 // Opening is the total of the snapshots at the start of the day.
+	Closing    int64 // Closing is the total of the snapshots at the end of the day.
+	NetFlow    int64 // NetFlow is what the day's completed transactions credited minus what they debited.
+	Difference int64 // Difference is Closing - Opening - NetFlow; anything but 0 needs investigating.
+}
+
+// Reconciliation is the end-of-day report of Day, with a line per currency.
+type Reconciliation struct {
+	Day      time.Time
+	Lines    []ReconciliationLine
+	Balanced bool
+}
+
+// ClosePeriod closes the month containing month on behalf of adminID, after which no
+// adjustment can be back-dated into it. The month-end snapshot is the immutable record
+// of the closed month, so it is taken now if the snapshot job has not yet done so.
+func (uc *AdminUseCase) ClosePeriod(ctx context.Context, adminID string, month time.Time) (period.Close, error) {
+	var result period.Close
+	err := uc.walletUC.audited(ctx, audit.ActionPeriodClose, nil, func(ctx context.Context, e *audit.Entry) error {
+		c, err := uc.periodService.Close(ctx, month, adminID)
+		if err != nil {
+			return err
+		}
+		e.Target = c.Start.Format("2006-01")
+
+		_, err = uc.walletUC.snapshotService.Totals(ctx, c.End)
+		if errors.Is(err, snapshot.ErrSnapshotNotFound) {
+			_, err = uc.walletUC.SnapshotBalances(ctx, c.End)
+		}
+		if err != nil {
+			return err
+		}
+		result = c
+		return nil
+	})
+	if err != nil {
+		return period.Close{}, err
+	}
+	return result, nil
+}
+
+func (uc *AdminUseCase) ListPeriodCloses(ctx context.Context, limit, offset int) ([]period.Close, error) {
+	return uc.periodService.ListCloses(ctx, limit, offset)
+}
+
+// ReconcileDay compares, for every currency, the snapshot totals at the start and end of
+// the UTC day containing day with the net of the transactions in between. Both snapshots
+// must have been taken.
+func (uc *AdminUseCase) ReconcileDay(ctx context.Context, day time.Time) (Reconciliation, error) {
+	day = day.UTC()
+	start := time.Date(day.Year(), day.Month(), day.Day(), 0, 0, 0, 0, time.UTC)
+	end := start.AddDate(0, 0, 1)
+	if end.After(time.Now()) {
+		return Reconciliation{}, period.ErrPeriodNotEnded
+	}
+
+	opening, err := uc.walletUC.snapshotService.Totals(ctx, start)
+	if err != nil {
+		return Reconciliation{}, err
+	}
+	closing, err := uc.walletUC.snapshotService.Totals(ctx, end)
+	if err != nil {
+		return Reconciliation{}, err
+	}
+	flows, err := uc.walletUC.transactionService.GetNetFlowBetween(ctx, start, end)
+	if err != nil {
+		return Reconciliation{}, err
+	}
+
+	lines := make(map[string]*ReconciliationLine)
+	line := func(currency string) *ReconciliationLine {
+		if lines[currency] == nil {
+			lines[currency] = &ReconciliationLine{Currency: currency}
+		}
+		return lines[currency]
+	}
+	for _, t := range opening {
+		line(t.Currency).Opening = t.Balance
+	}
+	for _, t := range closing {
+		line(t.Currency).Closing = t.Balance
+	}
+	for currency, net := range flows {
+		line(currency).NetFlow = net
+	}
+
+	report := Reconciliation{Day: start, Balanced: true}
+	for _, l := range lines {
+		l.Difference = l.Closing - l.Opening - l.NetFlow
+		if l.Difference != 0 {
+			report.Balanced = false
+		}
+		report.Lines = append(report.Lines, *l)
+	}
+	slices.SortFunc(report.Lines, func(a, b ReconciliationLine) int {
+		return strings.Compare(a.Currency, b.Currency)
+	})
+	return report, nil
+}
@@ -0,0 +1,210 @@
+package usecase
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"exchange/internal/domain/adjustment"
+	"exchange/internal/domain/audit"
+	"exchange/internal/domain/period"
+	"exchange/internal/domain/snapshot"
+	"exchange/internal/domain/transaction"
+	"exchange/internal/domain/wallet"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+)
+
+// transactionLog keeps what LogTransaction returned, with its options applied.
+type transactionLog struct {
+	*MockTransactionService
+	logged []transaction.Transaction
+}
+
+func (l *transactionLog) LogTransaction(ctx context.Context, fromUserID, toUserID string, amount int64, currency string, tType transaction.TransactionType, opts ...transaction.Option) (transaction.Transaction, error) {
+	tx, err := l.MockTransactionService.LogTransaction(ctx, fromUserID, toUserID, amount, currency, tType, opts...)
+	l.logged = append(l.logged, tx)
+	return tx, err
+}
+
+func TestAdminUseCase_BackDatedAdjustments(t *testing.T) {
+	ctx := context.Background()
+	closedEnd := time.Date(2024, 4, 1, 0, 0, 0, 0, time.UTC)
+
+	newUseCase := func() (*AdminUseCase, *MockWalletService, *MockAdjustmentService) {
+		mockWalletService := new(MockWalletService)
+		mockAdjustmentService := new(MockAdjustmentService)
+		mockTxManager := new(MockTransactionManager)
+		mockTxManager.DoFn = func(ctx context.Context, fn func(context.Context) error) error {
+			return fn(ctx)
+		}
+		walletUC := NewWalletUseCase(testDependencies(mockWalletService, new(MockTransactionService), mockTxManager))
+		return NewAdminUseCase(walletUC, mockAdjustmentService, closedThrough(closedEnd), 1000), mockWalletService, mockAdjustmentService
+	}
+
+	t.Run("request into a closed period", func(t *testing.T) {
+		useCase, mockWalletService, mockAdjustmentService := newUseCase()
+		effectiveAt := time.Date(2024, 3, 31, 0, 0, 0, 0, time.UTC)
+		pending := adjustment.Adjustment{ID: "adj1", UserID: "user1", Direction: adjustment.DirectionCredit, Amount: 500, Currency: "USD", Status: adjustment.StatusPending, RequestedBy: "admin1", EffectiveAt: effectiveAt}
+		mockAdjustmentService.On("RequestAdjustment", ctx, "user1", adjustment.DirectionCredit, int64(500), "USD", adjustment.ReasonCorrection, "", "admin1", effectiveAt).Return(pending, nil)
+
+		_, err := useCase.RequestAdjustment(ctx, "admin1", AdjustmentRequest{UserID: "user1", Direction: adjustment.DirectionCredit, Amount: 500, Currency: "USD", Reason: adjustment.ReasonCorrection, EffectiveAt: effectiveAt})
+
+		assert.Equal(t, period.ErrPeriodClosed, err)
+		mockWalletService.AssertNotCalled(t, "Deposit", mock.Anything, mock.Anything, mock.Anything)
+
+		entries := useCase.walletUC.auditService.(*auditRecorder).entries
+		require.Len(t, entries, 1)
+		assert.Equal(t, audit.OutcomeFailure, entries[0].Outcome)
+	})
+
+	t.Run("credit is booked on its effective date", func(t *testing.T) {
+		mockWalletService := new(MockWalletService)
+		mockAdjustmentService := new(MockAdjustmentService)
+		transactions := &transactionLog{MockTransactionService: new(MockTransactionService)}
+		mockTxManager := &MockTransactionManager{DoFn: func(ctx context.Context, fn func(context.Context) error) error {
+			return fn(ctx)
+		}}
+		useCase := NewAdminUseCase(NewWalletUseCase(testDependencies(mockWalletService, transactions, mockTxManager)), mockAdjustmentService, closedThrough(closedEnd), 1000)
+
+		effectiveAt := closedEnd.Add(36 * time.Hour)
+		pending := adjustment.Adjustment{ID: "adj3", UserID: "user1", Direction: adjustment.DirectionCredit, Amount: 500, Currency: "USD", Status: adjustment.StatusPending, RequestedBy: "admin1", EffectiveAt: effectiveAt}
+		mockAdjustmentService.On("RequestAdjustment", ctx, "user1", adjustment.DirectionCredit, int64(500), "USD", adjustment.ReasonCorrection, "", "admin1", effectiveAt).Return(pending, nil)
+		mockWalletService.On("Deposit", ctx, "user1", int64(500)).Return(nil)
+		now := time.Now()
+		transactions.On("LogTransaction", ctx, "", "user1", int64(500), "USD", transaction.TransactionTypeAdjustment).
+			Return(transaction.Transaction{ID: "tx1", Status: transaction.StatusCompleted, CreatedAt: now, CompletedAt: &now}, nil)
+		mockAdjustmentService.On("MarkApplied", ctx, pending, "", "tx1").Return(pending, nil)
+
+		_, err := useCase.RequestAdjustment(ctx, "admin1", AdjustmentRequest{UserID: "user1", Direction: adjustment.DirectionCredit, Amount: 500, Currency: "USD", Reason: adjustment.ReasonCorrection, EffectiveAt: effectiveAt})
+
+		require.NoError(t, err)
+		require.Len(t, transactions.logged, 1)
+		assert.Equal(t, effectiveAt, transactions.logged[0].CreatedAt)
+		assert.Equal(t, effectiveAt, transactions.logged[0].BookedAt(), "the ledger entry lands in the period the adjustment corrects")
+	})
+
+	t.Run("approval after its period was closed", func(t *testing.T) {
+		useCase, mockWalletService, mockAdjustmentService := newUseCase()
+		pending := adjustment.Adjustment{ID: "adj2", UserID: "user1", Direction: adjustment.DirectionDebit, Amount: 5000, Status: adjustment.StatusPending, RequestedBy: "admin1", EffectiveAt: closedEnd.Add(-time.Hour)}
+		mockAdjustmentService.On("GetAdjustment", ctx, "adj2").Return(pending, nil)
+
+		_, err := useCase.ApproveAdjustment(ctx, "admin2", "adj2")
+
+		assert.Equal(t, period.ErrPeriodClosed, err)
+		mockWalletService.AssertNotCalled(t, "Withdraw", mock.Anything, mock.Anything, mock.Anything)
+	})
+}
+
+func TestAdminUseCase_ClosePeriod(t *testing.T) {
+	ctx := context.Background()
+	month := time.Date(2024, 3, 1, 0, 0, 0, 0, time.UTC)
+	monthEnd := time.Date(2024, 4, 1, 0, 0, 0, 0, time.UTC)
+
+	newUseCase := func(snapshots *snapshotRecorder, closed closedThrough) (*AdminUseCase, *MockWalletService, *MockTransactionService) {
+		mockWalletService := new(MockWalletService)
+		mockTransactionService := new(MockTransactionService)
+		mockTxManager := new(MockTransactionManager)
+		mockTxManager.DoFn = func(ctx context.Context, fn func(context.Context) error) error {
+			return fn(ctx)
+		}
+		deps := testDependencies(mockWalletService, mockTransactionService, mockTxManager)
+		deps.Snapshots = snapshots
+		walletUC := NewWalletUseCase(deps)
+		return NewAdminUseCase(walletUC, nil, closed, 1000), mockWalletService, mockTransactionService
+	}
+
+	t.Run("takes the missing month-end snapshot", func(t *testing.T) {
+		snapshots := new(snapshotRecorder)
+		useCase, mockWalletService, mockTransactionService := newUseCase(snapshots, closedThrough{})
+		w := wallet.Wallet{UserID: "user1", Balance: 5000, Currency: "USD"}
+		mockWalletService.On("SearchWallets", ctx, wallet.SearchFilter{}, snapshotBatchSize, 0).Return([]wallet.Wallet{w}, nil)
+		mockWalletService.On("LockWallet", ctx, "user1").Return(w, nil)
+		mockTransactionService.On("GetNetAmountSince", ctx, "user1", monthEnd).Return(int64(1000), nil)
+
+		c, err := useCase.ClosePeriod(ctx, "admin1", month)
+
+		require.NoError(t, err)
+		assert.Equal(t, month, c.Start)
+		assert.Equal(t, monthEnd, c.End)
+		assert.Equal(t, []snapshot.Total{{Currency: "USD", Balance: 4000, Wallets: 1, TakenAt: monthEnd}}, snapshots.totals)
+
+		entries := useCase.walletUC.auditService.(*auditRecorder).entries
+		require.Len(t, entries, 1)
+		assert.Equal(t, audit.ActionPeriodClose, entries[0].Action)
+		assert.Equal(t, "2024-03", entries[0].Target)
+	})
+
+	t.Run("keeps an existing month-end snapshot", func(t *testing.T) {
+		snapshots := &snapshotRecorder{totals: []snapshot.Total{{Currency: "USD", Balance: 4000, Wallets: 1, TakenAt: monthEnd}}}
+		useCase, mockWalletService, _ := newUseCase(snapshots, closedThrough{})
+
+		_, err := useCase.ClosePeriod(ctx, "admin1", month)
+
+		require.NoError(t, err)
+		mockWalletService.AssertNotCalled(t, "SearchWallets", mock.Anything, mock.Anything, mock.Anything, mock.Anything)
+		assert.Len(t, snapshots.totals, 1)
+	})
+
+	t.Run("already closed", func(t *testing.T) {
+		useCase, _, _ := newUseCase(new(snapshotRecorder), closedThrough(monthEnd))
+
+		_, err := useCase.ClosePeriod(ctx, "admin1", month)
+
+		assert.Equal(t, period.ErrAlreadyClosed, err)
+	})
+}
+
+func TestAdminUseCase_ReconcileDay(t *testing.T) {
+	ctx := context.Background()
+	start := time.Date(2024, 3, 15, 0, 0, 0, 0, time.UTC)
+	end := start.AddDate(0, 0, 1)
+
+	newUseCase := func(snapshots *snapshotRecorder) (*AdminUseCase, *MockTransactionService) {
+		mockTransactionService := new(MockTransactionService)
+		deps := testDependencies(new(MockWalletService), mockTransactionService, new(MockTransactionManager))
+		deps.Snapshots = snapshots
+		walletUC := NewWalletUseCase(deps)
+		return NewAdminUseCase(walletUC, nil, closedThrough{}, 1000), mockTransactionService
+	}
+
+	t.Run("compares snapshot totals with the net of the day", func(t *testing.T) {
+		useCase, mockTransactionService := newUseCase(&snapshotRecorder{totals: []snapshot.Total{
+			{Currency: "USD", Balance: 10000, Wallets: 2, TakenAt: start},
+			{Currency: "EUR", Balance: 300, Wallets: 1, TakenAt: start},
+			{Currency: "USD", Balance: 10700, Wallets: 2, TakenAt: end},
+			{Currency: "EUR", Balance: 250, Wallets: 1, TakenAt: end},
+			{Currency: "GBP", Balance: 90, Wallets: 1, TakenAt: end},
+		}})
+		mockTransactionService.On("GetNetFlowBetween", ctx, start, end).Return(map[string]int64{"USD": 700, "EUR": -40, "GBP": 90}, nil)
+
+		report, err := useCase.ReconcileDay(ctx, start.Add(13*time.Hour))
+
+		require.NoError(t, err)
+		assert.Equal(t, start, report.Day)
+		assert.False(t, report.Balanced)
+		assert.Equal(t, []ReconciliationLine{
+			{Currency: "EUR", Opening: 300, Closing: 250, NetFlow: -40, Difference: -10},
+			{Currency: "GBP", Opening: 0, Closing: 90, NetFlow: 90, Difference: 0},
+			{Currency: "USD", Opening: 10000, Closing: 10700, NetFlow: 700, Difference: 0},
+		}, report.Lines)
+	})
+
+	t.Run("missing snapshot", func(t *testing.T) {
+		useCase, _ := newUseCase(&snapshotRecorder{totals: []snapshot.Total{{Currency: "USD", Balance: 10000, TakenAt: start}}})
+
+		_, err := useCase.ReconcileDay(ctx, start)
+
+		assert.Equal(t, snapshot.ErrSnapshotNotFound, err)
+	})
+
+	t.Run("day not over", func(t *testing.T) {
+		useCase, _ := newUseCase(new(snapshotRecorder))
+
+		_, err := useCase.ReconcileDay(ctx, time.Now())
+
+		assert.Equal(t, period.ErrPeriodNotEnded, err)
+	})
+}
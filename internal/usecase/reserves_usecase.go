@@ -0,0 +1,82 @@
+package usecase
+
+import (
+	"context"
+	"errors"
+	"log"
+	"maps"
+	"slices"
+	"time"
+
+	"exchange/internal/domain/reserves"
+	"exchange/internal/domain/snapshot"
+)
+
+// PublishLiabilities snapshots every wallet at at and publishes, per currency, a Merkle
+// sum tree over the balances: its root hash and total are the liabilities the exchange
+// commits to, and each user can prove their balance is part of the total. House accounts
+// hold the exchange's own funds and are left out; escrow accounts hold users' funds and
+// are included. Currencies already published for at are skipped, so a rerun only fills
+// in what is missing.
+func (uc *WalletUseCase) PublishLiabilities(ctx context.Context, at time.Time) ([]reserves.Root, error) {
+	balances := make(map[string]map[string]int64)
+	err := uc.eachWalletSnapshot(ctx, at, func(snap snapshot.Snapshot) {
+		if uc.houseAccounts[snap.UserID] {
+			return
+		}
+		if balances[snap.Currency] == nil {
+			balances[snap.Currency] = make(map[string]int64)
+		}
+		balances[snap.Currency][snap.UserID] = snap.Balance
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	var roots []reserves.Root
+	for _, currency := range slices.Sorted(maps.Keys(balances)) {
+		var root reserves.Root
+		err := uc.txManager.Do(ctx, func(ctx context.Context) error {
+			var err error
+			root, err = uc.reservesService.Publish(ctx, currency, at, balances[currency])
+			return err
+		})
+		if errors.Is(err, reserves.ErrAlreadyPublished) {
+			continue
+		}
+		if err != nil {
+			return roots, err
+		}
+		roots = append(roots, root)
+	}
+	return roots, nil
+}
+
+// LiabilityRoots returns the newest published root of every currency.
+func (uc *WalletUseCase) LiabilityRoots(ctx context.Context) ([]reserves.Root, error) {
+	return uc.reservesService.LatestRoots(ctx)
+}
+
+// ReservesProof returns the proof that userID's balance is included in the newest
+// published liabilities of its wallet's currency.
+func (uc *WalletUseCase) ReservesProof(ctx context.Context, userID string) (reserves.Proof, error) {
+	w, err := uc.walletService.GetWallet(ctx, userID)
+	if err != nil {
+		return reserves.Proof{}, err
+	}
+	return uc.reservesService.Prove(ctx, userID, w.Currency)
+}
+
+// RunLiabilityPublishing publishes the liabilities at each multiple of interval until
+// ctx is cancelled.
+func (uc *WalletUseCase) RunLiabilityPublishing(ctx context.Context, interval, delay time.Duration) {
+	runAtCutoffs(ctx, interval, delay, func(cutoff time.Time) {
+		roots, err := uc.PublishLiabilities(ctx, cutoff)
+		if err != nil && ctx.Err() == nil {
+			log.Println("liability publishing:", err)
+		}
+		for _, root := range roots {
+			log.Printf("published liabilities: %s total %d over %d wallets, root %s", root.Currency, root.Total, root.Wallets, root.Hash)
+		}
+	})
+}
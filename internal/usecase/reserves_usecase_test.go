@@ -0,0 +1,92 @@
+package usecase
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"exchange/internal/domain/escrow"
+	"exchange/internal/domain/reserves"
+	"exchange/internal/domain/wallet"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestWalletUseCase_PublishLiabilities(t *testing.T) {
+	ctx := context.Background()
+	at := time.Date(2024, 3, 15, 0, 0, 0, 0, time.UTC)
+
+	mockWalletService := new(MockWalletService)
+	mockTransactionService := new(MockTransactionService)
+	mockTxManager := new(MockTransactionManager)
+	mockTxManager.DoFn = func(ctx context.Context, fn func(context.Context) error) error {
+		return fn(ctx)
+	}
+	snapshots := new(snapshotRecorder)
+	published := new(reservesRecorder)
+	deps := testDependencies(mockWalletService, mockTransactionService, mockTxManager)
+	deps.Snapshots = snapshots
+	deps.Reserves = published
+	deps.HouseAccounts = []string{"house"}
+	useCase := NewWalletUseCase(deps)
+
+	wallets := []wallet.Wallet{
+		{UserID: "user1", Balance: 5000, Currency: "USD"},
+		{UserID: "user2", Balance: 300, Currency: "EUR"},
+		{UserID: "user3", Balance: 1200, Currency: "USD"},
+		{UserID: "house", Balance: 1_000_000, Currency: "USD"},
+		{UserID: escrow.AccountID("USD"), Balance: 400, Currency: "USD"},
+	}
+	mockWalletService.On("SearchWallets", ctx, wallet.SearchFilter{}, snapshotBatchSize, 0).Return(wallets, nil)
+	for _, w := range wallets {
+		mockWalletService.On("LockWallet", ctx, w.UserID).Return(w, nil)
+		mockWalletService.On("GetWallet", ctx, w.UserID).Return(w, nil)
+	}
+	mockTransactionService.On("GetNetAmountSince", ctx, "user1", at).Return(int64(700), nil)
+	mockTransactionService.On("GetNetAmountSince", ctx, "user2", at).Return(int64(0), nil)
+	mockTransactionService.On("GetNetAmountSince", ctx, "user3", at).Return(int64(0), nil)
+	mockTransactionService.On("GetNetAmountSince", ctx, "house", at).Return(int64(0), nil)
+	mockTransactionService.On("GetNetAmountSince", ctx, escrow.AccountID("USD"), at).Return(int64(0), nil)
+
+	roots, err := useCase.PublishLiabilities(ctx, at)
+
+	require.NoError(t, err)
+	require.Len(t, roots, 2)
+	assert.Equal(t, "EUR", roots[0].Currency)
+	assert.Equal(t, int64(300), roots[0].Total)
+	assert.Equal(t, "USD", roots[1].Currency)
+	assert.Equal(t, int64(5900), roots[1].Total, "escrowed funds are owed, the house account's are not")
+	assert.Equal(t, 3, roots[1].Wallets)
+	assert.Len(t, snapshots.snapshots, 5)
+
+	t.Run("proof of a counted balance", func(t *testing.T) {
+		proof, err := useCase.ReservesProof(ctx, "user1")
+
+		require.NoError(t, err)
+		assert.Equal(t, int64(4300), proof.Leaf.Balance)
+		assert.Equal(t, roots[1], proof.Root)
+		assert.True(t, proof.Verify())
+	})
+
+	t.Run("house account left out", func(t *testing.T) {
+		_, err := useCase.ReservesProof(ctx, "house")
+
+		assert.Equal(t, reserves.ErrLeafNotFound, err)
+	})
+
+	t.Run("rerun of the same cutoff", func(t *testing.T) {
+		again, err := useCase.PublishLiabilities(ctx, at)
+
+		require.NoError(t, err)
+		assert.Empty(t, again)
+	})
+
+	t.Run("currency without published liabilities", func(t *testing.T) {
+		mockWalletService.On("GetWallet", ctx, "user4").Return(wallet.Wallet{UserID: "user4", Currency: "GBP"}, nil)
+
+		_, err := useCase.ReservesProof(ctx, "user4")
+
+		assert.Equal(t, reserves.ErrRootNotFound, err)
+	})
+}
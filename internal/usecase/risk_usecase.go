@@ -0,0 +1,45 @@
+package usecase
+
+import (
+	"context"
+
+	"exchange/internal/domain/risk"
+)
+
+// screen checks the parties of req against the sanctions list, then assesses req against
+// the risk rules, and returns sanctions.ErrBlocked or risk.ErrBlocked when either blocks
+// it. It runs before the operation's database transaction, so the compliance case and the
+// assessment stay recorded when the operation fails and is rolled back.
+func (uc *WalletUseCase) screen(ctx context.Context, req risk.Request) (risk.Assessment, error) {
+	if err := uc.screenSanctions(ctx, req); err != nil {
+		return risk.Assessment{}, err
+	}
+	a, err := uc.riskService.Assess(ctx, req)
+	if err != nil {
+		return risk.Assessment{}, err
+	}
+	if a.Decision == risk.DecisionBlock {
+		return a, risk.ErrBlocked
+	}
+	return a, nil
+}
+
+func withdrawalRiskRequest(userID string, amount int64, currency, destination string) risk.Request {
+	return risk.Request{
+		UserID:      userID,
+		Operation:   risk.OperationWithdrawal,
+		Destination: destination,
+		Amount:      amount,
+		Currency:    currency,
+	}
+}
+
+func transferRiskRequest(fromUserID, toUserID string, amount int64, currency string) risk.Request {
+	return risk.Request{
+		UserID:         fromUserID,
+		Operation:      risk.OperationTransfer,
+		CounterpartyID: toUserID,
+		Amount:         amount,
+		Currency:       currency,
+	}
+}
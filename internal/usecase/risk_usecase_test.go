@@ -0,0 +1,125 @@
+// risk_usecase_test.go
+package usecase
+
+import (
+	"context"
+	"testing"
+
+	"exchange/internal/domain/audit"
+	"exchange/internal/domain/risk"
+	"exchange/internal/domain/transaction"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+)
+
+func TestWalletUseCase_Risk(t *testing.T) {
+	ctx := context.Background()
+	blocked := fixedRisk{Decision: risk.DecisionBlock, RuleIDs: []string{"round-trip"}}
+	flagged := fixedRisk{Decision: risk.DecisionReview, RuleIDs: []string{"drain", "velocity"}}
+
+	newUseCase := func(r fixedRisk) (*WalletUseCase, *MockWalletService, *MockTransactionService, *MockTransactionManager) {
+		mockWalletService := new(MockWalletService)
+		mockTransactionService := new(MockTransactionService)
+		mockTxManager := new(MockTransactionManager)
+		mockTxManager.DoFn = func(ctx context.Context, fn func(context.Context) error) error {
+			return fn(ctx)
+		}
+		deps := testDependencies(mockWalletService, mockTransactionService, mockTxManager)
+		deps.Risk = r
+		return NewWalletUseCase(deps), mockWalletService, mockTransactionService, mockTxManager
+	}
+
+	t.Run("blocked withdrawal", func(t *testing.T) {
+		useCase, mockWalletService, _, mockTxManager := newUseCase(blocked)
+		mockTxManager.DoFn = nil
+
+		_, err := useCase.Withdraw(ctx, "user1", 100, "USD", "")
+
+		assert.ErrorIs(t, err, risk.ErrBlocked)
+		mockTxManager.AssertNotCalled(t, "Do", mock.Anything, mock.Anything)
+		mockWalletService.AssertNotCalled(t, "Withdraw", mock.Anything, mock.Anything, mock.Anything)
+		entries := useCase.auditService.(*auditRecorder).entries
+		require.Len(t, entries, 1)
+		assert.Equal(t, audit.ActionWithdraw, entries[0].Action)
+		assert.Equal(t, audit.OutcomeFailure, entries[0].Outcome)
+	})
+
+	t.Run("flagged withdrawal awaits approval", func(t *testing.T) {
+		useCase, mockWalletService, mockTransactionService, _ := newUseCase(flagged)
+		mockWalletService.On("Hold", ctx, "user1", int64(100)).Return(nil)
+		mockTransactionService.On("LogTransaction", ctx, "user1", "", int64(100), "USD", transaction.TransactionTypeWithdraw).
+			Return(transaction.Transaction{ID: "tx1"}, nil)
+
+		tx, err := useCase.Withdraw(ctx, "user1", 100, "USD", "")
+
+		require.NoError(t, err)
+		assert.Equal(t, transaction.StatusAwaitingApproval, tx.Status)
+		assert.Equal(t, "flagged by risk rules: drain, velocity", tx.StatusReason)
+		mockWalletService.AssertNotCalled(t, "Withdraw", mock.Anything, mock.Anything, mock.Anything)
+	})
+
+	t.Run("blocked withdrawal request", func(t *testing.T) {
+		useCase, mockWalletService, _, _ := newUseCase(blocked)
+
+		_, err := useCase.RequestWithdrawal(ctx, "user1", 100, "USD", "")
+
+		assert.ErrorIs(t, err, risk.ErrBlocked)
+		mockWalletService.AssertNotCalled(t, "Hold", mock.Anything, mock.Anything, mock.Anything)
+	})
+
+	t.Run("flagged transfer goes through", func(t *testing.T) {
+		useCase, mockWalletService, mockTransactionService, _ := newUseCase(flagged)
+		mockWalletService.On("Withdraw", ctx, "user1", int64(100)).Return(nil)
+		mockWalletService.On("Deposit", ctx, "user2", int64(100)).Return(nil)
+		mockTransactionService.On("LogTransaction", ctx, "user1", "user2", int64(100), "USD", transaction.TransactionTypeTransfer).
+			Return(transaction.Transaction{ID: "tx1"}, nil)
+
+		err := useCase.Transfer(ctx, "user1", "user2", 100, "USD")
+
+		require.NoError(t, err)
+		mockWalletService.AssertExpectations(t)
+	})
+
+	t.Run("blocked transfer", func(t *testing.T) {
+		useCase, mockWalletService, _, _ := newUseCase(blocked)
+
+		err := useCase.Transfer(ctx, "user1", "user2", 100, "USD")
+
+		assert.ErrorIs(t, err, risk.ErrBlocked)
+		mockWalletService.AssertNotCalled(t, "Withdraw", mock.Anything, mock.Anything, mock.Anything)
+		entries := useCase.auditService.(*auditRecorder).entries
+		require.Len(t, entries, 1)
+		assert.Equal(t, audit.ActionTransfer, entries[0].Action)
+	})
+
+	t.Run("blocked atomic batch", func(t *testing.T) {
+		useCase, _, _, mockTxManager := newUseCase(blocked)
+		mockTxManager.DoFn = nil
+		items := []TransferItem{
+			{FromUserID: "user1", ToUserID: "user2", Amount: 100, Currency: "USD"},
+			{FromUserID: "user1", ToUserID: "user3", Amount: 200, Currency: "USD"},
+		}
+
+		result, err := useCase.BatchTransfer(ctx, BatchModeAtomic, items)
+
+		require.NoError(t, err)
+		assert.ErrorIs(t, result.Results[0].Err, risk.ErrBlocked)
+		assert.ErrorIs(t, result.Results[1].Err, transaction.ErrBatchRolledBack)
+		mockTxManager.AssertNotCalled(t, "Do", mock.Anything, mock.Anything)
+		assert.Len(t, useCase.auditService.(*auditRecorder).entries, 2)
+	})
+
+	t.Run("blocked best effort batch", func(t *testing.T) {
+		useCase, _, _, _ := newUseCase(blocked)
+
+		result, err := useCase.BatchTransfer(ctx, BatchModeBestEffort, []TransferItem{{FromUserID: "user1", ToUserID: "user2", Amount: 100, Currency: "USD"}})
+
+		require.NoError(t, err)
+		assert.ErrorIs(t, result.Results[0].Err, risk.ErrBlocked)
+		entries := useCase.auditService.(*auditRecorder).entries
+		require.Len(t, entries, 1)
+		assert.Equal(t, result.BatchID, entries[0].Target)
+	})
+}
@@ -0,0 +1,34 @@
+package usecase
+
+import (
+	"context"
+	"errors"
+
+	"exchange/internal/domain/risk"
+	"exchange/internal/domain/sanctions"
+	"exchange/internal/domain/user"
+)
+
+// screenSanctions screens the users taking part in req, by their registered names, and
+// the destination of a withdrawal against the sanctions list. Unregistered users are left
+// for the operation itself to reject.
+func (uc *WalletUseCase) screenSanctions(ctx context.Context, req risk.Request) error {
+	var subjects []sanctions.Subject
+	for _, userID := range []string{req.UserID, req.CounterpartyID} {
+		if userID == "" {
+			continue
+		}
+		u, err := uc.userService.GetUser(ctx, userID)
+		if errors.Is(err, user.ErrUserNotFound) {
+			continue
+		}
+		if err != nil {
+			return err
+		}
+		subjects = append(subjects, sanctions.Subject{Type: sanctions.SubjectUser, ID: u.ID, Name: u.Name})
+	}
+	if req.Destination != "" {
+		subjects = append(subjects, sanctions.Subject{Type: sanctions.SubjectDestination, ID: req.Destination, Name: req.Destination})
+	}
+	return uc.sanctionsService.Screen(ctx, string(req.Operation), subjects...)
+}
@@ -0,0 +1,98 @@
+// sanctions_usecase_test.go
+package usecase
+
+import (
+	"context"
+	"testing"
+
+	"exchange/internal/domain/audit"
+	"exchange/internal/domain/sanctions"
+	"exchange/internal/domain/transaction"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+)
+
+func TestWalletUseCase_Sanctions(t *testing.T) {
+	ctx := context.Background()
+	listed := fixedSanctions{"sanctioned": true, "0xabc123": true}
+
+	newUseCase := func() (*WalletUseCase, *MockWalletService, *MockTransactionManager) {
+		mockWalletService := new(MockWalletService)
+		mockTxManager := new(MockTransactionManager)
+		deps := testDependencies(mockWalletService, new(MockTransactionService), mockTxManager)
+		deps.Sanctions = listed
+		return NewWalletUseCase(deps), mockWalletService, mockTxManager
+	}
+
+	t.Run("transfer to a sanctioned user", func(t *testing.T) {
+		useCase, mockWalletService, mockTxManager := newUseCase()
+
+		err := useCase.Transfer(ctx, "user1", "sanctioned", 100, "USD")
+
+		assert.ErrorIs(t, err, sanctions.ErrBlocked)
+		mockTxManager.AssertNotCalled(t, "Do", mock.Anything, mock.Anything)
+		mockWalletService.AssertNotCalled(t, "Withdraw", mock.Anything, mock.Anything, mock.Anything)
+		entries := useCase.auditService.(*auditRecorder).entries
+		require.Len(t, entries, 1)
+		assert.Equal(t, audit.ActionTransfer, entries[0].Action)
+		assert.Equal(t, audit.OutcomeFailure, entries[0].Outcome)
+	})
+
+	t.Run("transfer from a sanctioned user", func(t *testing.T) {
+		useCase, _, _ := newUseCase()
+
+		err := useCase.Transfer(ctx, "sanctioned", "user1", 100, "USD")
+
+		assert.ErrorIs(t, err, sanctions.ErrBlocked)
+	})
+
+	t.Run("withdrawal to a sanctioned destination", func(t *testing.T) {
+		useCase, mockWalletService, _ := newUseCase()
+
+		_, err := useCase.Withdraw(ctx, "user1", 100, "USD", "0xabc123")
+
+		assert.ErrorIs(t, err, sanctions.ErrBlocked)
+		mockWalletService.AssertNotCalled(t, "Withdraw", mock.Anything, mock.Anything, mock.Anything)
+	})
+
+	t.Run("withdrawal request to a sanctioned destination", func(t *testing.T) {
+		useCase, mockWalletService, _ := newUseCase()
+
+		_, err := useCase.RequestWithdrawal(ctx, "user1", 100, "USD", "0xabc123")
+
+		assert.ErrorIs(t, err, sanctions.ErrBlocked)
+		mockWalletService.AssertNotCalled(t, "Hold", mock.Anything, mock.Anything, mock.Anything)
+	})
+
+	t.Run("batch item to a sanctioned user", func(t *testing.T) {
+		useCase, _, _ := newUseCase()
+
+		result, err := useCase.BatchTransfer(ctx, BatchModeAtomic, []TransferItem{
+			{FromUserID: "user1", ToUserID: "user2", Amount: 100, Currency: "USD"},
+			{FromUserID: "user1", ToUserID: "sanctioned", Amount: 100, Currency: "USD"},
+		})
+
+		require.NoError(t, err)
+		assert.ErrorIs(t, result.Results[0].Err, transaction.ErrBatchRolledBack)
+		assert.ErrorIs(t, result.Results[1].Err, sanctions.ErrBlocked)
+	})
+
+	t.Run("admin clears a case", func(t *testing.T) {
+		useCase, _, mockTxManager := newUseCase()
+		mockTxManager.DoFn = func(ctx context.Context, fn func(context.Context) error) error {
+			return fn(ctx)
+		}
+		adminUC := NewAdminUseCase(useCase, nil, closedThrough{}, 1000)
+
+		c, err := adminUC.ClearSanctionsCase(ctx, "ops", "case1", "different date of birth")
+
+		require.NoError(t, err)
+		assert.Equal(t, sanctions.CaseCleared, c.Status)
+		entries := useCase.auditService.(*auditRecorder).entries
+		require.Len(t, entries, 1)
+		assert.Equal(t, audit.ActionClearSanctions, entries[0].Action)
+		assert.Equal(t, "case1", entries[0].Target)
+	})
+}
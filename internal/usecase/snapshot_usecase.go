@@ -0,0 +1,150 @@
+package usecase
+
+import (
+	"context"
+	"errors"
+	"log"
+	"maps"
+	"slices"
+	"time"
+
+	"exchange/internal/domain/snapshot"
+	"exchange/internal/domain/transaction"
+	"exchange/internal/domain/wallet"
+)
+
+// snapshotBatchSize is how many wallets SnapshotBalances reads at a time.
+const snapshotBatchSize = 100
+
+// BalanceAt returns the balance of userID's wallet at at. It starts from the newest
+// snapshot taken at or before at and adds the transactions since, so only the tail of
+// the history is replayed. Without a snapshot it falls back to the current balance minus
+// everything since at, like the opening balance of a statement.
+func (uc *WalletUseCase) BalanceAt(ctx context.Context, userID string, at time.Time) (snapshot.Snapshot, error) {
+	if at.After(time.Now()) {
+		return snapshot.Snapshot{}, transaction.ErrInvalidTimeRange
+	}
+
+	w, err := uc.walletService.GetWallet(ctx, userID)
+	if err != nil {
+		return snapshot.Snapshot{}, err
+	}
+	result := snapshot.Snapshot{UserID: userID, Currency: w.Currency, TakenAt: at}
+
+	snap, err := uc.snapshotService.Latest(ctx, userID, at)
+	switch {
+	case err == nil:
+		net, err := uc.transactionService.GetNetAmountBetween(ctx, userID, snap.TakenAt, at)
+		if err != nil {
+			return snapshot.Snapshot{}, err
+		}
+		result.Balance = snap.Balance + net
+	case errors.Is(err, snapshot.ErrSnapshotNotFound):
+		net, err := uc.transactionService.GetNetAmountSince(ctx, userID, at)
+		if err != nil {
+			return snapshot.Snapshot{}, err
+		}
+		result.Balance = w.Balance - net
+	default:
+		return snapshot.Snapshot{}, err
+	}
+	return result, nil
+}
+
+// SnapshotBalances records the balance of every wallet at at, followed by the total of
+// each currency, and returns how many wallets it went through. Recording the same at
+// twice is harmless.
+func (uc *WalletUseCase) SnapshotBalances(ctx context.Context, at time.Time) (int, error) {
+	totals := make(map[string]snapshot.Total)
+	count := 0
+	err := uc.eachWalletSnapshot(ctx, at, func(snap snapshot.Snapshot) {
+		total := totals[snap.Currency]
+		total.Balance += snap.Balance
+		total.Wallets++
+		totals[snap.Currency] = total
+		count++
+	})
+	if err != nil {
+		return count, err
+	}
+
+	for _, currency := range slices.Sorted(maps.Keys(totals)) {
+		total := totals[currency]
+		total.Currency = currency
+		total.TakenAt = at
+		if err := uc.snapshotService.RecordTotal(ctx, total); err != nil {
+			return count, err
+		}
+	}
+	return count, nil
+}
+
+// eachWalletSnapshot records the snapshot of every wallet at at and passes it to fn.
+// Each wallet is locked while its balance is derived so no transfer slips in between
+// reading the balance and the transactions since at.
+func (uc *WalletUseCase) eachWalletSnapshot(ctx context.Context, at time.Time, fn func(snapshot.Snapshot)) error {
+	for offset := 0; ; offset += snapshotBatchSize {
+		wallets, err := uc.walletService.SearchWallets(ctx, wallet.SearchFilter{}, snapshotBatchSize, offset)
+		if err != nil {
+			return err
+		}
+		for _, w := range wallets {
+			snap, err := uc.snapshotWallet(ctx, w.UserID, at)
+			if err != nil {
+				return err
+			}
+			fn(snap)
+		}
+		if len(wallets) < snapshotBatchSize {
+			return nil
+		}
+	}
+}
+
+func (uc *WalletUseCase) snapshotWallet(ctx context.Context, userID string, at time.Time) (snapshot.Snapshot, error) {
+	var snap snapshot.Snapshot
+	err := uc.txManager.Do(ctx, func(ctx context.Context) error {
+		w, err := uc.walletService.LockWallet(ctx, userID)
+		if err != nil {
+			return err
+		}
+		net, err := uc.transactionService.GetNetAmountSince(ctx, userID, at)
+		if err != nil {
+			return err
+		}
+		snap = snapshot.Snapshot{
+			UserID:   userID,
+			Currency: w.Currency,
+			Balance:  w.Balance - net,
+			TakenAt:  at,
+		}
+		return uc.snapshotService.Record(ctx, snap)
+	})
+	return snap, err
+}
+
+// RunBalanceSnapshots snapshots every wallet at each multiple of interval until ctx is
+// cancelled.
+func (uc *WalletUseCase) RunBalanceSnapshots(ctx context.Context, interval, delay time.Duration) {
+	runAtCutoffs(ctx, interval, delay, func(cutoff time.Time) {
+		if _, err := uc.SnapshotBalances(ctx, cutoff); err != nil && ctx.Err() == nil {
+			log.Println("balance snapshots:", err)
+		}
+	})
+}
+
+// runAtCutoffs calls fn with each multiple of interval until ctx is cancelled, starting
+// with the latest one. Each call is made delay after its cutoff so operations in flight
+// at the cutoff have settled.
+func runAtCutoffs(ctx context.Context, interval, delay time.Duration, fn func(cutoff time.Time)) {
+	for {
+		cutoff := time.Now().Add(-delay).Truncate(interval)
+		fn(cutoff)
+
+		select {
+		case <-ctx.Done():
+			return
+		case <-time.After(time.Until(cutoff.Add(interval + delay))):
+		}
+	}
+}
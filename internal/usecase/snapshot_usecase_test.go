@@ -0,0 +1,115 @@
+package usecase
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"exchange/internal/domain/snapshot"
+	"exchange/internal/domain/transaction"
+	"exchange/internal/domain/wallet"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+)
+
+func TestWalletUseCase_BalanceAt(t *testing.T) {
+	ctx := context.Background()
+	userID := "user1"
+	at := time.Date(2024, 3, 15, 12, 0, 0, 0, time.UTC)
+	takenAt := time.Date(2024, 3, 15, 0, 0, 0, 0, time.UTC)
+
+	setup := func(snapshots *snapshotRecorder) (*WalletUseCase, *MockWalletService, *MockTransactionService) {
+		mockWalletService := new(MockWalletService)
+		mockTransactionService := new(MockTransactionService)
+		mockWalletService.On("GetWallet", ctx, userID).Return(wallet.Wallet{UserID: userID, Balance: 5000, Currency: "USD"}, nil)
+		deps := testDependencies(mockWalletService, mockTransactionService, new(MockTransactionManager))
+		deps.Snapshots = snapshots
+		return NewWalletUseCase(deps), mockWalletService, mockTransactionService
+	}
+
+	t.Run("replays the tail since the nearest snapshot", func(t *testing.T) {
+		snapshots := &snapshotRecorder{snapshots: []snapshot.Snapshot{
+			{UserID: userID, Currency: "USD", Balance: 1000, TakenAt: takenAt.AddDate(0, 0, -1)},
+			{UserID: userID, Currency: "USD", Balance: 1200, TakenAt: takenAt},
+			{UserID: userID, Currency: "USD", Balance: 9000, TakenAt: takenAt.AddDate(0, 0, 1)},
+		}}
+		useCase, _, mockTransactionService := setup(snapshots)
+		mockTransactionService.On("GetNetAmountBetween", ctx, userID, takenAt, at).Return(int64(-200), nil)
+
+		got, err := useCase.BalanceAt(ctx, userID, at)
+
+		require.NoError(t, err)
+		assert.Equal(t, snapshot.Snapshot{UserID: userID, Currency: "USD", Balance: 1000, TakenAt: at}, got)
+		mockTransactionService.AssertNotCalled(t, "GetNetAmountSince", mock.Anything, mock.Anything, mock.Anything)
+	})
+
+	t.Run("falls back to the current balance without a snapshot", func(t *testing.T) {
+		useCase, _, mockTransactionService := setup(new(snapshotRecorder))
+		mockTransactionService.On("GetNetAmountSince", ctx, userID, at).Return(int64(700), nil)
+
+		got, err := useCase.BalanceAt(ctx, userID, at)
+
+		require.NoError(t, err)
+		assert.Equal(t, int64(4300), got.Balance)
+	})
+
+	t.Run("future time", func(t *testing.T) {
+		useCase, _, _ := setup(new(snapshotRecorder))
+
+		_, err := useCase.BalanceAt(ctx, userID, time.Now().Add(time.Hour))
+
+		assert.Equal(t, transaction.ErrInvalidTimeRange, err)
+	})
+
+	t.Run("wallet not found", func(t *testing.T) {
+		mockWalletService := new(MockWalletService)
+		mockWalletService.On("GetWallet", ctx, "ghost").Return(wallet.Wallet{}, wallet.ErrWalletNotFound)
+		useCase := NewWalletUseCase(testDependencies(mockWalletService, new(MockTransactionService), new(MockTransactionManager)))
+
+		_, err := useCase.BalanceAt(ctx, "ghost", at)
+
+		assert.Equal(t, wallet.ErrWalletNotFound, err)
+	})
+}
+
+func TestWalletUseCase_SnapshotBalances(t *testing.T) {
+	ctx := context.Background()
+	at := time.Date(2024, 3, 15, 0, 0, 0, 0, time.UTC)
+
+	mockWalletService := new(MockWalletService)
+	mockTransactionService := new(MockTransactionService)
+	mockTxManager := new(MockTransactionManager)
+	mockTxManager.DoFn = func(ctx context.Context, fn func(context.Context) error) error {
+		return fn(ctx)
+	}
+	snapshots := new(snapshotRecorder)
+	deps := testDependencies(mockWalletService, mockTransactionService, mockTxManager)
+	deps.Snapshots = snapshots
+	useCase := NewWalletUseCase(deps)
+
+	wallets := []wallet.Wallet{
+		{UserID: "user1", Balance: 5000, Currency: "USD"},
+		{UserID: "user2", Balance: 300, Currency: "EUR"},
+	}
+	mockWalletService.On("SearchWallets", ctx, wallet.SearchFilter{}, snapshotBatchSize, 0).Return(wallets, nil)
+	for _, w := range wallets {
+		mockWalletService.On("LockWallet", ctx, w.UserID).Return(w, nil)
+	}
+	mockTransactionService.On("GetNetAmountSince", ctx, "user1", at).Return(int64(700), nil)
+	mockTransactionService.On("GetNetAmountSince", ctx, "user2", at).Return(int64(0), nil)
+
+	n, err := useCase.SnapshotBalances(ctx, at)
+
+	require.NoError(t, err)
+	assert.Equal(t, 2, n)
+	assert.Equal(t, []snapshot.Snapshot{
+		{UserID: "user1", Currency: "USD", Balance: 4300, TakenAt: at},
+		{UserID: "user2", Currency: "EUR", Balance: 300, TakenAt: at},
+	}, snapshots.snapshots)
+	assert.Equal(t, []snapshot.Total{
+		{Currency: "EUR", Balance: 300, Wallets: 1, TakenAt: at},
+		{Currency: "USD", Balance: 4300, Wallets: 1, TakenAt: at},
+	}, snapshots.totals)
+}
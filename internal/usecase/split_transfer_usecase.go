@@ -0,0 +1,94 @@
+package usecase
+
+import (
+	"context"
+
+	"exchange/internal/domain/audit"
+	"exchange/internal/domain/transaction"
+)
+
+type SplitDirection string
+
+const (
+	// SplitPay has one sender pay every share to its user.
+	SplitPay SplitDirection = "pay"
+	// SplitCollect has every share's user pay it to one recipient.
+	SplitCollect SplitDirection = "collect"
+)
+
+// SplitRequest describes transfers between UserID and the users of Shares: from UserID to
+// each of them when paying, from each of them to UserID when collecting. Amount is the
+// total the shares' percentages apply to; with fixed amounts it may be 0.
+type SplitRequest struct {
+	Direction SplitDirection
+	UserID    string
+	Amount    int64
+	Currency  string
+	Shares    []transaction.Share
+	Remainder transaction.RemainderRule
+}
+
+type SplitResult struct {
+	GroupID   string
+	Direction SplitDirection
+	Transfers []SplitTransfer
+}
+
+type SplitTransfer struct {
+	FromUserID    string
+	ToUserID      string
+	Amount        int64
+	TransactionID string
+}
+
+// SplitTransfer allocates req's total to its shares and executes the resulting transfers
+// as one atomic unit, recording the group ID as the batch ID of every transaction. It
+// fails with the error of the first transfer that could not be made, in which case none
+// was.
+func (uc *WalletUseCase) SplitTransfer(ctx context.Context, req SplitRequest) (SplitResult, error) {
+	if req.Direction == "" {
+		req.Direction = SplitPay
+	}
+	if req.Direction != SplitPay && req.Direction != SplitCollect {
+		return SplitResult{}, transaction.ErrInvalidSplitDirection
+	}
+	if len(req.Shares) > MaxBatchTransfers {
+		return SplitResult{}, transaction.ErrBatchTooLarge
+	}
+	amounts, err := transaction.Allocate(req.Amount, req.Shares, req.Remainder)
+	if err != nil {
+		return SplitResult{}, err
+	}
+
+	groupID, err := transaction.NewBatchID()
+	if err != nil {
+		return SplitResult{}, err
+	}
+
+	items := make([]TransferItem, len(req.Shares))
+	for i, share := range req.Shares {
+		items[i] = TransferItem{FromUserID: req.UserID, ToUserID: share.UserID, Amount: amounts[i], Currency: req.Currency}
+		if req.Direction == SplitCollect {
+			items[i].FromUserID, items[i].ToUserID = share.UserID, req.UserID
+		}
+	}
+
+	result := SplitResult{
+		GroupID:   groupID,
+		Direction: req.Direction,
+		Transfers: make([]SplitTransfer, len(items)),
+	}
+	for i, itemResult := range uc.transferAtomically(ctx, audit.ActionSplitTransfer, groupID, items) {
+		if itemResult.Err != nil && itemResult.Err != transaction.ErrBatchRolledBack {
+			return SplitResult{}, itemResult.Err
+		}
+		item := items[i]
+		result.Transfers[i] = SplitTransfer{
+			FromUserID:    item.FromUserID,
+			ToUserID:      item.ToUserID,
+			Amount:        item.Amount,
+			TransactionID: itemResult.TransactionID,
+		}
+	}
+	return result, nil
+}
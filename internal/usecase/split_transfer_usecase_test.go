@@ -0,0 +1,97 @@
+package usecase
+
+import (
+	"context"
+	"testing"
+
+	"exchange/internal/domain/audit"
+	"exchange/internal/domain/transaction"
+	"exchange/internal/domain/wallet"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+)
+
+func TestWalletUseCase_SplitTransfer(t *testing.T) {
+	ctx := context.Background()
+	thirds := []transaction.Share{{UserID: "user2", BasisPoints: 3333}, {UserID: "user3", BasisPoints: 3333}, {UserID: "user4", BasisPoints: 3334}}
+
+	newUseCase := func() (*WalletUseCase, *MockWalletService, *MockTransactionService) {
+		mockWalletService := new(MockWalletService)
+		mockTransactionService := new(MockTransactionService)
+		mockTxManager := new(MockTransactionManager)
+		mockTxManager.DoFn = func(ctx context.Context, fn func(context.Context) error) error {
+			return fn(ctx)
+		}
+		return NewWalletUseCase(testDependencies(mockWalletService, mockTransactionService, mockTxManager)), mockWalletService, mockTransactionService
+	}
+
+	t.Run("pay by percentages", func(t *testing.T) {
+		useCase, mockWalletService, mockTransactionService := newUseCase()
+		mockWalletService.On("Withdraw", ctx, "user1", mock.Anything).Return(nil)
+		mockWalletService.On("Deposit", ctx, mock.Anything, mock.Anything).Return(nil)
+		mockTransactionService.On("LogTransaction", ctx, "user1", mock.Anything, mock.Anything, "USD", transaction.TransactionTypeTransfer).Return(transaction.Transaction{ID: "tx"}, nil)
+
+		result, err := useCase.SplitTransfer(ctx, SplitRequest{UserID: "user1", Amount: 100, Currency: "USD", Shares: thirds})
+
+		require.NoError(t, err)
+		assert.NotEmpty(t, result.GroupID)
+		assert.Equal(t, SplitPay, result.Direction)
+		assert.Equal(t, []SplitTransfer{
+			{FromUserID: "user1", ToUserID: "user2", Amount: 33, TransactionID: "tx"},
+			{FromUserID: "user1", ToUserID: "user3", Amount: 33, TransactionID: "tx"},
+			{FromUserID: "user1", ToUserID: "user4", Amount: 34, TransactionID: "tx"},
+		}, result.Transfers)
+		mockWalletService.AssertCalled(t, "Deposit", ctx, "user4", int64(34))
+		for _, e := range useCase.auditService.(*auditRecorder).entries {
+			assert.Equal(t, audit.ActionSplitTransfer, e.Action)
+			assert.Equal(t, result.GroupID, e.Target)
+		}
+	})
+
+	t.Run("collect fixed amounts", func(t *testing.T) {
+		useCase, mockWalletService, mockTransactionService := newUseCase()
+		mockWalletService.On("Withdraw", ctx, mock.Anything, mock.Anything).Return(nil)
+		mockWalletService.On("Deposit", ctx, "user1", mock.Anything).Return(nil)
+		mockTransactionService.On("LogTransaction", ctx, mock.Anything, "user1", mock.Anything, "USD", transaction.TransactionTypeTransfer).Return(transaction.Transaction{ID: "tx"}, nil)
+
+		result, err := useCase.SplitTransfer(ctx, SplitRequest{
+			Direction: SplitCollect,
+			UserID:    "user1",
+			Currency:  "USD",
+			Shares:    []transaction.Share{{UserID: "user2", Amount: 250}, {UserID: "user3", Amount: 750}},
+		})
+
+		require.NoError(t, err)
+		assert.Equal(t, SplitTransfer{FromUserID: "user3", ToUserID: "user1", Amount: 750, TransactionID: "tx"}, result.Transfers[1])
+		mockWalletService.AssertCalled(t, "Withdraw", ctx, "user2", int64(250))
+		mockWalletService.AssertCalled(t, "Withdraw", ctx, "user3", int64(750))
+	})
+
+	t.Run("failed share rolls back the split", func(t *testing.T) {
+		useCase, mockWalletService, mockTransactionService := newUseCase()
+		mockWalletService.On("Withdraw", ctx, "user1", mock.Anything).Return(nil)
+		mockWalletService.On("Deposit", ctx, "user2", mock.Anything).Return(nil)
+		mockWalletService.On("Deposit", ctx, "user3", mock.Anything).Return(wallet.ErrWalletNotFound)
+		mockTransactionService.On("LogTransaction", ctx, "user1", "user2", mock.Anything, "USD", transaction.TransactionTypeTransfer).Return(transaction.Transaction{ID: "tx"}, nil)
+
+		_, err := useCase.SplitTransfer(ctx, SplitRequest{UserID: "user1", Amount: 100, Currency: "USD", Shares: thirds})
+
+		assert.Equal(t, wallet.ErrWalletNotFound, err)
+		mockWalletService.AssertNotCalled(t, "Deposit", ctx, "user4", mock.Anything)
+	})
+
+	t.Run("invalid splits", func(t *testing.T) {
+		useCase, _, _ := newUseCase()
+
+		_, err := useCase.SplitTransfer(ctx, SplitRequest{Direction: "swap", UserID: "user1", Amount: 100, Currency: "USD", Shares: thirds})
+		assert.Equal(t, transaction.ErrInvalidSplitDirection, err)
+
+		_, err = useCase.SplitTransfer(ctx, SplitRequest{UserID: "user1", Amount: 100, Currency: "USD", Shares: thirds[:2]})
+		assert.Equal(t, transaction.ErrSharesMismatch, err)
+
+		_, err = useCase.SplitTransfer(ctx, SplitRequest{UserID: "user1", Currency: "USD", Shares: make([]transaction.Share, MaxBatchTransfers+1)})
+		assert.Equal(t, transaction.ErrBatchTooLarge, err)
+	})
+}
@@ -0,0 +1,86 @@
+package usecase
+
+import (
+	"context"
+	"time"
+
+	"exchange/internal/domain/transaction"
+)
+
+// StatementSummary describes the balance of a wallet at one edge of a statement period.
+type StatementSummary struct {
+	UserID   string
+	Currency string
+	From     time.Time
+	To       time.Time
+	Balance  int64
+}
+
+// StatementEntry is a single statement line: the transaction, its signed effect on the
+// wallet and the balance right after it was applied.
+type StatementEntry struct {
+	Transaction    transaction.Transaction
+	Amount         int64
+	RunningBalance int64
+}
+
+// StatementWriter receives a statement as it is produced, so callers can stream it
+// to their output format without buffering the whole period.
+type StatementWriter interface {
+	WriteOpening(summary StatementSummary) error
+	WriteEntry(entry StatementEntry) error
+	WriteClosing(summary StatementSummary) error
+}
+
+// ExportStatement writes the opening balance, every transaction in [from, to) with its
+// running balance, and the closing balance of userID's wallet to sw.
+//
+// The opening balance is derived from the current balance minus the net amount of all
+// transactions since from, so it also holds for wallets seeded without transactions.
+// The balance, the net amount and the transactions are read from one snapshot, so the
+// statement adds up even while the wallet is in use.
+func (uc *WalletUseCase) ExportStatement(ctx context.Context, userID string, from, to time.Time, sw StatementWriter) error {
+	if !from.Before(to) {
+		return transaction.ErrInvalidTimeRange
+	}
+
+	return uc.txManager.ReadOnly(ctx, func(ctx context.Context) error {
+		w, err := uc.walletService.GetWallet(ctx, userID)
+		if err != nil {
+			return err
+		}
+
+		netSinceFrom, err := uc.transactionService.GetNetAmountSince(ctx, userID, from)
+		if err != nil {
+			return err
+		}
+
+		summary := StatementSummary{
+			UserID:   userID,
+			Currency: w.Currency,
+			From:     from,
+			To:       to,
+			Balance:  w.Balance - netSinceFrom,
+		}
+		if err := sw.WriteOpening(summary); err != nil {
+			return err
+		}
+
+		running := summary.Balance
+		err = uc.transactionService.StreamTransactionHistory(ctx, userID, from, to, func(tx transaction.Transaction) error {
+			amount := tx.SignedAmountFor(userID)
+			running += amount
+			return sw.WriteEntry(StatementEntry{
+				Transaction:    tx,
+				Amount:         amount,
+				RunningBalance: running,
+			})
+		})
+		if err != nil {
+			return err
+		}
+
+		summary.Balance = running
+		return sw.WriteClosing(summary)
+	})
+}
@@ -0,0 +1,127 @@
+package usecase
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"exchange/internal/domain/transaction"
+	"exchange/internal/domain/wallet"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+)
+
+type recordingStatementWriter struct {
+	opening  StatementSummary
+	entries  []StatementEntry
+	closing  StatementSummary
+	closed   bool
+	entryErr error
+}
+
+func (w *recordingStatementWriter) WriteOpening(summary StatementSummary) error {
+	w.opening = summary
+	return nil
+}
+
+func (w *recordingStatementWriter) WriteEntry(entry StatementEntry) error {
+	if w.entryErr != nil {
+		return w.entryErr
+	}
+	w.entries = append(w.entries, entry)
+	return nil
+}
+
+func (w *recordingStatementWriter) WriteClosing(summary StatementSummary) error {
+	w.closing = summary
+	w.closed = true
+	return nil
+}
+
+func TestWalletUseCase_ExportStatement(t *testing.T) {
+	ctx := context.Background()
+	userID := "user1"
+	from := time.Date(2024, 3, 1, 0, 0, 0, 0, time.UTC)
+	to := time.Date(2024, 4, 1, 0, 0, 0, 0, time.UTC)
+
+	txs := []transaction.Transaction{
+		{ID: "tx1", ToUserID: userID, Amount: 1000, Currency: "USD", Type: transaction.TransactionTypeDeposit},
+		{ID: "tx2", FromUserID: userID, ToUserID: "user2", Amount: 300, Currency: "USD", Type: transaction.TransactionTypeTransfer},
+		{ID: "tx3", FromUserID: userID, Amount: 200, Currency: "USD", Type: transaction.TransactionTypeWithdraw},
+	}
+
+	t.Run("successful export", func(t *testing.T) {
+		mockWalletService := new(MockWalletService)
+		mockTransactionService := new(MockTransactionService)
+		mockTxManager := new(MockTransactionManager)
+		snapshots := 0
+		mockTxManager.DoFn = func(ctx context.Context, fn func(context.Context) error) error {
+			snapshots++
+			return fn(ctx)
+		}
+		useCase := NewWalletUseCase(testDependencies(mockWalletService, mockTransactionService, mockTxManager))
+
+		// Current balance 5000, with 700 of net movement since the start of the period
+		// (500 of it inside the period, 200 after it).
+		mockWalletService.On("GetWallet", ctx, userID).Return(wallet.Wallet{UserID: userID, Balance: 5000, Currency: "USD"}, nil)
+		mockTransactionService.On("GetNetAmountSince", ctx, userID, from).Return(int64(700), nil)
+		mockTransactionService.On("StreamTransactionHistory", ctx, userID, from, to, mock.Anything).Return(txs, nil)
+
+		sw := &recordingStatementWriter{}
+		err := useCase.ExportStatement(ctx, userID, from, to, sw)
+
+		assert.NoError(t, err)
+		assert.Equal(t, int64(4300), sw.opening.Balance)
+		assert.Equal(t, "USD", sw.opening.Currency)
+		assert.Len(t, sw.entries, 3)
+		assert.Equal(t, int64(5300), sw.entries[0].RunningBalance)
+		assert.Equal(t, int64(-300), sw.entries[1].Amount)
+		assert.Equal(t, int64(5000), sw.entries[1].RunningBalance)
+		assert.Equal(t, int64(4800), sw.entries[2].RunningBalance)
+		assert.True(t, sw.closed)
+		assert.Equal(t, int64(4800), sw.closing.Balance)
+		assert.Equal(t, 1, snapshots, "the statement must be read from a single snapshot")
+		mockWalletService.AssertExpectations(t)
+		mockTransactionService.AssertExpectations(t)
+	})
+
+	t.Run("invalid time range", func(t *testing.T) {
+		useCase := NewWalletUseCase(testDependencies(new(MockWalletService), new(MockTransactionService), new(MockTransactionManager)))
+
+		err := useCase.ExportStatement(ctx, userID, to, from, &recordingStatementWriter{})
+
+		assert.ErrorIs(t, err, transaction.ErrInvalidTimeRange)
+	})
+
+	t.Run("wallet not found", func(t *testing.T) {
+		mockWalletService := new(MockWalletService)
+		useCase := NewWalletUseCase(testDependencies(mockWalletService, new(MockTransactionService), new(MockTransactionManager)))
+
+		mockWalletService.On("GetWallet", ctx, "userempty").Return(wallet.Wallet{}, wallet.ErrWalletNotFound)
+
+		sw := &recordingStatementWriter{}
+		err := useCase.ExportStatement(ctx, "userempty", from, to, sw)
+
+		assert.ErrorIs(t, err, wallet.ErrWalletNotFound)
+		assert.False(t, sw.closed)
+	})
+
+	t.Run("writer failure stops the stream", func(t *testing.T) {
+		mockWalletService := new(MockWalletService)
+		mockTransactionService := new(MockTransactionService)
+		useCase := NewWalletUseCase(testDependencies(mockWalletService, mockTransactionService, new(MockTransactionManager)))
+
+		mockWalletService.On("GetWallet", ctx, userID).Return(wallet.Wallet{UserID: userID, Balance: 5000, Currency: "USD"}, nil)
+		mockTransactionService.On("GetNetAmountSince", ctx, userID, from).Return(int64(700), nil)
+		mockTransactionService.On("StreamTransactionHistory", ctx, userID, from, to, mock.Anything).Return(txs, nil)
+
+		writeErr := errors.New("client went away")
+		sw := &recordingStatementWriter{entryErr: writeErr}
+		err := useCase.ExportStatement(ctx, userID, from, to, sw)
+
+		assert.ErrorIs(t, err, writeErr)
+		assert.False(t, sw.closed)
+	})
+}
@@ -24,6 +24,12 @@ func (uc *TransactionUseCase) GetTransactionHistory(ctx context.Context, userID
 	return txs, nil
 }
 
+// GetTransactionHistoryBefore returns the page of userID's history that follows before,
+// newest first.
+func (uc *TransactionUseCase) GetTransactionHistoryBefore(ctx context.Context, userID string, before transaction.Cursor, limit int) ([]transaction.Transaction, error) {
+	return uc.transactionService.GetTransactionHistoryBefore(ctx, userID, before, limit)
+}
+
 func (uc *TransactionUseCase) GetTransactionByID(ctx context.Context, txID string) (transaction.Transaction, error) {
 	tx, err := uc.transactionService.GetTransactionByID(ctx, txID)
 	if err != nil {
@@ -3,6 +3,7 @@ package usecase
 import (
 	"context"
 	"testing"
+	"time"
 
 	"exchange/internal/domain/transaction"
 
@@ -19,16 +20,82 @@ func (m *MockTransactionService) GetTransactionHistory(ctx context.Context, user
 	return args.Get(0).([]transaction.Transaction), args.Error(1)
 }
 
+func (m *MockTransactionService) GetTransactionHistoryBefore(ctx context.Context, userID string, before transaction.Cursor, limit int) ([]transaction.Transaction, error) {
+	args := m.Called(ctx, userID, before, limit)
+	return args.Get(0).([]transaction.Transaction), args.Error(1)
+}
+
 func (m *MockTransactionService) GetTransactionByID(ctx context.Context, txID string) (transaction.Transaction, error) {
 	args := m.Called(ctx, txID)
 	return args.Get(0).(transaction.Transaction), args.Error(1)
 }
 
-func (m *MockTransactionService) LogTransaction(ctx context.Context, fromUserID, toUserID string, amount int64, currency string, tType transaction.TransactionType) (transaction.Transaction, error) {
-	args := m.Called(ctx, fromUserID, toUserID, amount, currency, tType)
+func (m *MockTransactionService) ListUndos(ctx context.Context, originalID string) ([]transaction.Transaction, error) {
+	args := m.Called(ctx, originalID)
+	return args.Get(0).([]transaction.Transaction), args.Error(1)
+}
+
+func (m *MockTransactionService) LogUndo(ctx context.Context, originalID string, tType transaction.TransactionType, amount int64) (transaction.Transaction, error) {
+	args := m.Called(ctx, originalID, tType, amount)
 	return args.Get(0).(transaction.Transaction), args.Error(1)
 }
 
+func (m *MockTransactionService) TransitionStatus(ctx context.Context, id string, to transaction.Status, reason string) (transaction.Transaction, error) {
+	args := m.Called(ctx, id, to, reason)
+	return args.Get(0).(transaction.Transaction), args.Error(1)
+}
+
+func (m *MockTransactionService) ReviewTransaction(ctx context.Context, id string, approved bool, reason string) (transaction.Transaction, error) {
+	args := m.Called(ctx, id, approved, reason)
+	return args.Get(0).(transaction.Transaction), args.Error(1)
+}
+
+func (m *MockTransactionService) LogTransaction(ctx context.Context, fromUserID, toUserID string, amount int64, currency string, tType transaction.TransactionType, opts ...transaction.Option) (transaction.Transaction, error) {
+	args := m.Called(ctx, fromUserID, toUserID, amount, currency, tType)
+	tx := args.Get(0).(transaction.Transaction)
+	for _, opt := range opts {
+		opt(&tx)
+	}
+	return tx, args.Error(1)
+}
+
+func (m *MockTransactionService) StreamTransactionHistory(ctx context.Context, userID string, from, to time.Time, fn func(transaction.Transaction) error) error {
+	args := m.Called(ctx, userID, from, to, fn)
+	if txs, ok := args.Get(0).([]transaction.Transaction); ok {
+		for _, tx := range txs {
+			if err := fn(tx); err != nil {
+				return err
+			}
+		}
+	}
+	return args.Error(1)
+}
+
+func (m *MockTransactionService) GetNetAmountSince(ctx context.Context, userID string, since time.Time) (int64, error) {
+	args := m.Called(ctx, userID, since)
+	return args.Get(0).(int64), args.Error(1)
+}
+
+func (m *MockTransactionService) GetNetAmountBetween(ctx context.Context, userID string, from, to time.Time) (int64, error) {
+	args := m.Called(ctx, userID, from, to)
+	return args.Get(0).(int64), args.Error(1)
+}
+
+func (m *MockTransactionService) GetNetFlowBetween(ctx context.Context, from, to time.Time) (map[string]int64, error) {
+	args := m.Called(ctx, from, to)
+	return args.Get(0).(map[string]int64), args.Error(1)
+}
+
+func (m *MockTransactionService) GetOutgoingSince(ctx context.Context, userID string, tType transaction.TransactionType, currency string, since time.Time) (int64, int, error) {
+	args := m.Called(ctx, userID, tType, currency, since)
+	return args.Get(0).(int64), args.Int(1), args.Error(2)
+}
+
+func (m *MockTransactionService) SearchTransactions(ctx context.Context, filter transaction.SearchFilter, limit, offset int) ([]transaction.Transaction, error) {
+	args := m.Called(ctx, filter, limit, offset)
+	return args.Get(0).([]transaction.Transaction), args.Error(1)
+}
+
 func TestTransactionUseCase_GetTransactionHistory(t *testing.T) {
 	mockService := new(MockTransactionService)
 	useCase := NewTransactionUseCase(mockService)
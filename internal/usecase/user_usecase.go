@@ -0,0 +1,74 @@
+package usecase
+
+import (
+	"context"
+
+	"exchange/internal/domain/kyc"
+	"exchange/internal/domain/user"
+)
+
+type UserUseCase struct {
+	userService user.UserServiceInterface
+	kycService  kyc.KYCServiceInterface
+}
+
+func NewUserUseCase(uService user.UserServiceInterface, kService kyc.KYCServiceInterface) *UserUseCase {
+	return &UserUseCase{
+		userService: uService,
+		kycService:  kService,
+	}
+}
+
+// Register records the profile of userID, who must be registered before opening a wallet.
+func (uc *UserUseCase) Register(ctx context.Context, userID, name, email string) (user.User, error) {
+	return uc.userService.Register(ctx, userID, name, email)
+}
+
+func (uc *UserUseCase) GetUser(ctx context.Context, userID string) (user.User, error) {
+	return uc.userService.GetUser(ctx, userID)
+}
+
+// UpdateProfile changes the user's name and email; an empty value keeps the current one.
+func (uc *UserUseCase) UpdateProfile(ctx context.Context, userID, name, email string) (user.User, error) {
+	return uc.userService.UpdateProfile(ctx, userID, name, email)
+}
+
+// KYCStatus is how far a user has been verified, what that lets them do and how they got
+// there.
+type KYCStatus struct {
+	Level       kyc.Level
+	Caps        kyc.Caps
+	History     []kyc.LevelChange
+	Submissions []kyc.Submission
+}
+
+// kycSubmissionsShown bounds the submissions listed in a user's KYC status.
+const kycSubmissionsShown = 20
+
+// GetKYC returns the KYC status of userID with their latest submissions.
+func (uc *UserUseCase) GetKYC(ctx context.Context, userID string) (KYCStatus, error) {
+	if _, err := uc.userService.GetUser(ctx, userID); err != nil {
+		return KYCStatus{}, err
+	}
+	level, err := uc.kycService.GetLevel(ctx, userID)
+	if err != nil {
+		return KYCStatus{}, err
+	}
+	history, err := uc.kycService.ListLevelChanges(ctx, userID)
+	if err != nil {
+		return KYCStatus{}, err
+	}
+	submissions, err := uc.kycService.ListSubmissions(ctx, kyc.Filter{UserID: userID}, kycSubmissionsShown, 0)
+	if err != nil {
+		return KYCStatus{}, err
+	}
+	return KYCStatus{Level: level, Caps: uc.kycService.CapsOf(level), History: history, Submissions: submissions}, nil
+}
+
+// SubmitKYC stores a document userID sent to reach level, for an admin to review.
+func (uc *UserUseCase) SubmitKYC(ctx context.Context, userID string, level kyc.Level, documentType, fileName, contentType string, content []byte) (kyc.Submission, error) {
+	if err := uc.userService.CheckActive(ctx, userID); err != nil {
+		return kyc.Submission{}, err
+	}
+	return uc.kycService.Submit(ctx, userID, level, documentType, fileName, contentType, content)
+}
@@ -0,0 +1,87 @@
+// user_usecase_test.go
+package usecase
+
+import (
+	"context"
+	"testing"
+
+	"exchange/internal/domain/audit"
+	"exchange/internal/domain/user"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+)
+
+func TestWalletUseCase_Users(t *testing.T) {
+	ctx := context.Background()
+	users := fixedUsers{"nobody": user.ErrUserNotFound, "frozen": user.ErrUserDeactivated}
+
+	newUseCase := func() (*WalletUseCase, *MockWalletService) {
+		mockWalletService := new(MockWalletService)
+		mockTxManager := new(MockTransactionManager)
+		mockTxManager.DoFn = func(ctx context.Context, fn func(context.Context) error) error {
+			return fn(ctx)
+		}
+		deps := testDependencies(mockWalletService, new(MockTransactionService), mockTxManager)
+		deps.Users = users
+		return NewWalletUseCase(deps), mockWalletService
+	}
+
+	t.Run("wallet of an unknown user", func(t *testing.T) {
+		useCase, mockWalletService := newUseCase()
+
+		_, err := useCase.CreateWallet(ctx, "nobody", "USD")
+
+		assert.ErrorIs(t, err, user.ErrUserNotFound)
+		mockWalletService.AssertNotCalled(t, "CreateNewWallet", mock.Anything, mock.Anything, mock.Anything)
+	})
+
+	t.Run("wallet of a deactivated user", func(t *testing.T) {
+		useCase, _ := newUseCase()
+
+		_, err := useCase.CreateWallet(ctx, "frozen", "USD")
+
+		assert.ErrorIs(t, err, user.ErrUserDeactivated)
+	})
+
+	t.Run("transfer to a deactivated user", func(t *testing.T) {
+		useCase, mockWalletService := newUseCase()
+
+		err := useCase.Transfer(ctx, "user1", "frozen", 100, "USD")
+
+		assert.ErrorIs(t, err, user.ErrUserDeactivated)
+		mockWalletService.AssertNotCalled(t, "Withdraw", mock.Anything, mock.Anything, mock.Anything)
+	})
+
+	t.Run("transfer from a deactivated user", func(t *testing.T) {
+		useCase, _ := newUseCase()
+
+		err := useCase.Transfer(ctx, "frozen", "user1", 100, "USD")
+
+		assert.ErrorIs(t, err, user.ErrUserDeactivated)
+	})
+
+	t.Run("batch transfer to an unknown user", func(t *testing.T) {
+		useCase, _ := newUseCase()
+
+		result, err := useCase.BatchTransfer(ctx, BatchModeBestEffort, []TransferItem{{FromUserID: "user1", ToUserID: "nobody", Amount: 100, Currency: "USD"}})
+
+		require.NoError(t, err)
+		assert.ErrorIs(t, result.Results[0].Err, user.ErrUserNotFound)
+	})
+
+	t.Run("admin deactivates a user", func(t *testing.T) {
+		useCase, _ := newUseCase()
+		adminUC := NewAdminUseCase(useCase, nil, closedThrough{}, 1000)
+
+		u, err := adminUC.SetUserStatus(ctx, "user1", user.StatusDeactivated)
+
+		require.NoError(t, err)
+		assert.Equal(t, user.StatusDeactivated, u.Status)
+		entries := useCase.auditService.(*auditRecorder).entries
+		require.Len(t, entries, 1)
+		assert.Equal(t, audit.ActionUserStatus, entries[0].Action)
+		assert.Equal(t, "user1", entries[0].Target)
+	})
+}
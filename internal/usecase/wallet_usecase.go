@@ -2,7 +2,19 @@ package usecase
 
 import (
 	"context"
+	"log"
+	"time"
+
+	"exchange/internal/domain/audit"
+	"exchange/internal/domain/event"
+	"exchange/internal/domain/kyc"
+	"exchange/internal/domain/limit"
+	"exchange/internal/domain/reserves"
+	"exchange/internal/domain/risk"
+	"exchange/internal/domain/sanctions"
+	"exchange/internal/domain/snapshot"
 	"exchange/internal/domain/transaction"
+	"exchange/internal/domain/user"
 	"exchange/internal/domain/wallet"
 )
 
@@ -10,70 +22,400 @@ type WalletServiceInterface interface {
 	CreateNewWallet(ctx context.Context, userID, currency string) (wallet.Wallet, error)
 	Deposit(ctx context.Context, userID string, amount int64) error
 	Withdraw(ctx context.Context, userID string, amount int64) error
+	Hold(ctx context.Context, userID string, amount int64) error
+	Release(ctx context.Context, userID string, amount int64) error
+	Capture(ctx context.Context, userID string, amount int64) error
 	GetBalance(ctx context.Context, userID string) (int64, error)
+	GetWallet(ctx context.Context, userID string) (wallet.Wallet, error)
+	LockWallet(ctx context.Context, userID string) (wallet.Wallet, error)
+	SearchWallets(ctx context.Context, filter wallet.SearchFilter, limit, offset int) ([]wallet.Wallet, error)
 }
 
 type TransactionServiceInterface interface {
-	LogTransaction(ctx context.Context, fromUserID, toUserID string, amount int64, currency string, tType transaction.TransactionType) (transaction.Transaction, error)
+	LogTransaction(ctx context.Context, fromUserID, toUserID string, amount int64, currency string, tType transaction.TransactionType, opts ...transaction.Option) (transaction.Transaction, error)
 	GetTransactionHistory(ctx context.Context, userID string, limit, offset int) ([]transaction.Transaction, error)
 	GetTransactionByID(ctx context.Context, id string) (transaction.Transaction, error)
+	ListUndos(ctx context.Context, originalID string) ([]transaction.Transaction, error)
+	LogUndo(ctx context.Context, originalID string, tType transaction.TransactionType, amount int64) (transaction.Transaction, error)
+	TransitionStatus(ctx context.Context, id string, to transaction.Status, reason string) (transaction.Transaction, error)
+	ReviewTransaction(ctx context.Context, id string, approved bool, reason string) (transaction.Transaction, error)
+	StreamTransactionHistory(ctx context.Context, userID string, from, to time.Time, fn func(transaction.Transaction) error) error
+	GetNetAmountSince(ctx context.Context, userID string, since time.Time) (int64, error)
+	GetNetAmountBetween(ctx context.Context, userID string, from, to time.Time) (int64, error)
+	GetNetFlowBetween(ctx context.Context, from, to time.Time) (map[string]int64, error)
+	GetOutgoingSince(ctx context.Context, userID string, tType transaction.TransactionType, currency string, since time.Time) (int64, int, error)
+	SearchTransactions(ctx context.Context, filter transaction.SearchFilter, limit, offset int) ([]transaction.Transaction, error)
 }
 
 type TransactionManager interface {
 	Do(ctx context.Context, fn func(ctx context.Context) error) error
+	// ReadOnly runs fn in a read-only transaction whose reads all see one snapshot.
+	ReadOnly(ctx context.Context, fn func(ctx context.Context) error) error
 }
 
 type WalletUseCase struct {
 	walletService      WalletServiceInterface
 	transactionService TransactionServiceInterface
 	txManager          TransactionManager
+	auditService       audit.AuditServiceInterface
+	eventService       event.EventServiceInterface
+	limitService       limit.LimitServiceInterface
+	riskService        risk.RiskServiceInterface
+	userService        user.UserServiceInterface
+	kycService         kyc.KYCServiceInterface
+	sanctionsService   sanctions.SanctionsServiceInterface
+	snapshotService    snapshot.SnapshotServiceInterface
+	reservesService    reserves.ReservesServiceInterface
+	withdrawalPolicy   WithdrawalPolicy
+	houseAccounts      map[string]bool
+}
+
+// WalletDependencies are the services and policy a WalletUseCase is built from. Every
+// service is required.
+type WalletDependencies struct {
+	Wallets          WalletServiceInterface
+	Transactions     TransactionServiceInterface
+	TxManager        TransactionManager
+	Audit            audit.AuditServiceInterface
+	Events           event.EventServiceInterface
+	Limits           limit.LimitServiceInterface
+	Risk             risk.RiskServiceInterface
+	Users            user.UserServiceInterface
+	KYC              kyc.KYCServiceInterface
+	Sanctions        sanctions.SanctionsServiceInterface
+	Snapshots        snapshot.SnapshotServiceInterface
+	Reserves         reserves.ReservesServiceInterface
+	WithdrawalPolicy WithdrawalPolicy
+	// HouseAccounts are the wallets holding the exchange's own funds, such as those
+	// interest is paid from. They are not owed to anyone and are left out of the
+	// published liabilities.
+	HouseAccounts []string
 }
 
-func NewWalletUseCase(
-	wService WalletServiceInterface,
-	tService TransactionServiceInterface,
-	txManager TransactionManager,
-) *WalletUseCase {
+func NewWalletUseCase(deps WalletDependencies) *WalletUseCase {
+	houseAccounts := make(map[string]bool, len(deps.HouseAccounts))
+	for _, id := range deps.HouseAccounts {
+		houseAccounts[id] = true
+	}
 	return &WalletUseCase{
-		walletService:      wService,
-		transactionService: tService,
-		txManager:          txManager,
+		walletService:      deps.Wallets,
+		transactionService: deps.Transactions,
+		txManager:          deps.TxManager,
+		auditService:       deps.Audit,
+		eventService:       deps.Events,
+		limitService:       deps.Limits,
+		riskService:        deps.Risk,
+		userService:        deps.Users,
+		kycService:         deps.KYC,
+		sanctionsService:   deps.Sanctions,
+		snapshotService:    deps.Snapshots,
+		reservesService:    deps.Reserves,
+		withdrawalPolicy:   deps.WithdrawalPolicy,
+		houseAccounts:      houseAccounts,
+	}
+}
+
+// CreateWallet opens a wallet for userID holding currency. The user must be registered
+// and active.
+func (uc *WalletUseCase) CreateWallet(ctx context.Context, userID, currency string) (wallet.Wallet, error) {
+	var result wallet.Wallet
+	err := uc.audited(ctx, audit.ActionWalletCreate, []string{userID}, func(ctx context.Context, e *audit.Entry) error {
+		if err := uc.userService.CheckActive(ctx, userID); err != nil {
+			return err
+		}
+		w, err := uc.walletService.CreateNewWallet(ctx, userID, currency)
+		if err != nil {
+			return err
+		}
+		result = w
+		return uc.eventService.Record(ctx, event.TypeWalletCreated, w.UserID, event.WalletCreated{UserID: w.UserID, Currency: w.Currency})
+	})
+	if err != nil {
+		return wallet.Wallet{}, err
 	}
+	return result, nil
 }
 
 func (uc *WalletUseCase) Deposit(ctx context.Context, userID string, amount int64, currency string) error {
-	return uc.txManager.Do(ctx, func(ctx context.Context) error {
+	return uc.audited(ctx, audit.ActionDeposit, []string{userID}, func(ctx context.Context, e *audit.Entry) error {
+		if err := uc.checkBalanceCap(ctx, userID, amount); err != nil {
+			return err
+		}
 		if err := uc.walletService.Deposit(ctx, userID, amount); err != nil {
 			return err
 		}
-		_, err := uc.transactionService.LogTransaction(ctx, "", userID, amount, currency, transaction.TransactionTypeDeposit)
+		tx, err := uc.logTransaction(ctx, "", userID, amount, currency, transaction.TransactionTypeDeposit)
+		e.TransactionID = tx.ID
 		return err
 	})
 }
 
-func (uc *WalletUseCase) Withdraw(ctx context.Context, userID string, amount int64, currency string) error {
-	return uc.txManager.Do(ctx, func(ctx context.Context) error {
+// Withdraw takes amount out of userID's wallet, unless the withdrawal policy or the risk
+// rules require an admin's approval, in which case the amount is held and the returned
+// transaction awaits approval instead of being completed. destination, if given, is
+// screened against the sanctions list along with the user.
+func (uc *WalletUseCase) Withdraw(ctx context.Context, userID string, amount int64, currency, destination string) (transaction.Transaction, error) {
+	assessment, err := uc.screen(ctx, withdrawalRiskRequest(userID, amount, currency, destination))
+	if err != nil {
+		uc.recordFailure(ctx, audit.NewEntry(audit.ActionWithdraw, userID), err)
+		return transaction.Transaction{}, err
+	}
+
+	var result transaction.Transaction
+	err = uc.audited(ctx, audit.ActionWithdraw, []string{userID}, func(ctx context.Context, e *audit.Entry) error {
+		if err := uc.checkWithdrawalCap(ctx, userID, amount); err != nil {
+			return err
+		}
+		if err := uc.checkLimits(ctx, userID, limit.OperationWithdrawal, amount, currency); err != nil {
+			return err
+		}
+		reason, err := uc.withdrawalApprovalReason(ctx, userID, amount, currency, assessment)
+		if err != nil {
+			return err
+		}
+		if reason != "" {
+			if err := uc.walletService.Hold(ctx, userID, amount); err != nil {
+				return err
+			}
+			result, err = uc.transactionService.LogTransaction(ctx, userID, "", amount, currency, transaction.TransactionTypeWithdraw, transaction.AwaitingApproval(reason))
+			e.TransactionID = result.ID
+			return err
+		}
+
 		if err := uc.walletService.Withdraw(ctx, userID, amount); err != nil {
 			return err
 		}
-		_, err := uc.transactionService.LogTransaction(ctx, userID, "", amount, currency, transaction.TransactionTypeWithdraw)
+		result, err = uc.logTransaction(ctx, userID, "", amount, currency, transaction.TransactionTypeWithdraw)
+		e.TransactionID = result.ID
 		return err
 	})
+	if err != nil {
+		return transaction.Transaction{}, err
+	}
+	return result, nil
 }
 
+// Transfer moves amount from fromUserID's wallet to toUserID's. Transfers the risk rules
+// flag for review go through; only the assessment records them.
 func (uc *WalletUseCase) Transfer(ctx context.Context, fromUserID, toUserID string, amount int64, currency string) error {
-	return uc.txManager.Do(ctx, func(ctx context.Context) error {
-		if err := uc.walletService.Withdraw(ctx, fromUserID, amount); err != nil {
-			return err
+	if _, err := uc.screen(ctx, transferRiskRequest(fromUserID, toUserID, amount, currency)); err != nil {
+		uc.recordFailure(ctx, audit.NewEntry(audit.ActionTransfer, fromUserID, toUserID), err)
+		return err
+	}
+	return uc.audited(ctx, audit.ActionTransfer, []string{fromUserID, toUserID}, func(ctx context.Context, e *audit.Entry) error {
+		tx, err := uc.transfer(ctx, fromUserID, toUserID, amount, currency)
+		e.TransactionID = tx.ID
+		return err
+	})
+}
+
+// Refund returns amount of a transfer from its recipient to its sender, or whatever is
+// left of it when amount is 0.
+func (uc *WalletUseCase) Refund(ctx context.Context, originalID string, amount int64) (transaction.Transaction, error) {
+	return uc.undo(ctx, audit.ActionRefund, transaction.TransactionTypeRefund, originalID, amount)
+}
+
+// Reverse undoes amount of any transaction other than a reversal or refund, or whatever
+// is left of it when amount is 0, by moving the funds back.
+func (uc *WalletUseCase) Reverse(ctx context.Context, originalID string, amount int64) (transaction.Transaction, error) {
+	return uc.undo(ctx, audit.ActionReverse, transaction.TransactionTypeReversal, originalID, amount)
+}
+
+func (uc *WalletUseCase) undo(ctx context.Context, action audit.Action, tType transaction.TransactionType, originalID string, amount int64) (transaction.Transaction, error) {
+	original, err := uc.transactionService.GetTransactionByID(ctx, originalID)
+	if err != nil {
+		return transaction.Transaction{}, err
+	}
+
+	var userIDs []string
+	for _, userID := range []string{original.ToUserID, original.FromUserID} {
+		if userID != "" {
+			userIDs = append(userIDs, userID)
 		}
+	}
 
-		if err := uc.walletService.Deposit(ctx, toUserID, amount); err != nil {
+	var result transaction.Transaction
+	err = uc.audited(ctx, action, userIDs, func(ctx context.Context, e *audit.Entry) error {
+		e.Target = originalID
+		tx, err := uc.transactionService.LogUndo(ctx, originalID, tType, amount)
+		if err != nil {
 			return err
 		}
+		if tx.FromUserID != "" {
+			if err := uc.walletService.Withdraw(ctx, tx.FromUserID, tx.Amount); err != nil {
+				return err
+			}
+		}
+		if tx.ToUserID != "" {
+			if err := uc.walletService.Deposit(ctx, tx.ToUserID, tx.Amount); err != nil {
+				return err
+			}
+		}
+		result = tx
+		e.TransactionID = tx.ID
+		return uc.recordTransactionEvent(ctx, tx)
+	})
+	if err != nil {
+		return transaction.Transaction{}, err
+	}
+	return result, nil
+}
 
-		_, err := uc.transactionService.LogTransaction(ctx, fromUserID, toUserID, amount, currency, transaction.TransactionTypeTransfer)
-		return err
+// TransactionDetails is a transaction together with the reversals and refunds of it.
+type TransactionDetails struct {
+	Transaction transaction.Transaction
+	Undos       []transaction.Transaction
+}
+
+// UndoneAmount returns how much of the transaction has been reversed or refunded.
+func (d TransactionDetails) UndoneAmount() int64 {
+	return transaction.UndoneAmount(d.Undos)
+}
+
+func (uc *WalletUseCase) GetTransaction(ctx context.Context, id string) (TransactionDetails, error) {
+	tx, err := uc.transactionService.GetTransactionByID(ctx, id)
+	if err != nil {
+		return TransactionDetails{}, err
+	}
+	undos, err := uc.transactionService.ListUndos(ctx, id)
+	if err != nil {
+		return TransactionDetails{}, err
+	}
+	return TransactionDetails{Transaction: tx, Undos: undos}, nil
+}
+
+// audited runs fn in a database transaction and records it in the audit log with the
+// balances of userIDs: in the same transaction when fn succeeds, so the entry commits with
+// the change, and after the rollback when it fails. fn fills in the entry's target and
+// transaction ID.
+func (uc *WalletUseCase) audited(ctx context.Context, action audit.Action, userIDs []string, fn func(ctx context.Context, e *audit.Entry) error) error {
+	entry := audit.NewEntry(action, userIDs...)
+	err := uc.txManager.Do(ctx, func(ctx context.Context) error {
+		entry = uc.auditService.Begin(ctx, action, userIDs...)
+		if err := fn(ctx, &entry); err != nil {
+			return err
+		}
+		return uc.auditService.RecordSuccess(ctx, entry)
 	})
+	if err != nil {
+		uc.recordFailure(ctx, entry, err)
+	}
+	return err
+}
+
+// recordFailure appends a failed action to the audit log. The action has already failed,
+// so an error writing the entry is only logged.
+func (uc *WalletUseCase) recordFailure(ctx context.Context, e audit.Entry, cause error) {
+	if err := uc.auditService.RecordFailure(ctx, e, cause); err != nil {
+		log.Printf("failed to audit %s: %v", e.Action, err)
+	}
+}
+
+// transfer moves the funds between two registered and active users, within the caps of
+// their KYC levels, and logs the transaction; callers must run it inside txManager.Do.
+func (uc *WalletUseCase) transfer(ctx context.Context, fromUserID, toUserID string, amount int64, currency string, opts ...transaction.Option) (transaction.Transaction, error) {
+	for _, userID := range []string{fromUserID, toUserID} {
+		if err := uc.userService.CheckActive(ctx, userID); err != nil {
+			return transaction.Transaction{}, err
+		}
+	}
+	if err := uc.checkTransferCaps(ctx, fromUserID, toUserID, amount); err != nil {
+		return transaction.Transaction{}, err
+	}
+	if err := uc.checkBalanceCap(ctx, toUserID, amount); err != nil {
+		return transaction.Transaction{}, err
+	}
+	if err := uc.checkLimits(ctx, fromUserID, limit.OperationTransfer, amount, currency); err != nil {
+		return transaction.Transaction{}, err
+	}
+	if err := uc.walletService.Withdraw(ctx, fromUserID, amount); err != nil {
+		return transaction.Transaction{}, err
+	}
+
+	if err := uc.walletService.Deposit(ctx, toUserID, amount); err != nil {
+		return transaction.Transaction{}, err
+	}
+
+	return uc.logTransaction(ctx, fromUserID, toUserID, amount, currency, transaction.TransactionTypeTransfer, opts...)
+}
+
+// logTransaction logs a transaction and records the matching event in the outbox;
+// callers must run it inside txManager.Do.
+func (uc *WalletUseCase) logTransaction(ctx context.Context, fromUserID, toUserID string, amount int64, currency string, tType transaction.TransactionType, opts ...transaction.Option) (transaction.Transaction, error) {
+	tx, err := uc.transactionService.LogTransaction(ctx, fromUserID, toUserID, amount, currency, tType, opts...)
+	if err != nil {
+		return transaction.Transaction{}, err
+	}
+
+	if err := uc.recordTransactionEvent(ctx, tx); err != nil {
+		return transaction.Transaction{}, err
+	}
+	return tx, nil
+}
+
+// recordTransactionEvent records the event describing tx in the outbox; callers must run
+// it inside txManager.Do.
+func (uc *WalletUseCase) recordTransactionEvent(ctx context.Context, tx transaction.Transaction) error {
+	eventType, aggregateID, payload := transactionEvent(tx)
+	return uc.eventService.Record(ctx, eventType, aggregateID, payload)
+}
+
+// transactionEvent describes tx as a domain event about the wallet it was initiated from.
+func transactionEvent(tx transaction.Transaction) (event.Type, string, any) {
+	switch {
+	case tx.FromUserID != "" && tx.ToUserID != "":
+		payload := event.FundsTransferred{
+			FromUserID:            tx.FromUserID,
+			ToUserID:              tx.ToUserID,
+			Amount:                tx.Amount,
+			Currency:              tx.Currency,
+			TransactionID:         tx.ID,
+			BatchID:               tx.BatchID,
+			OriginalTransactionID: tx.OriginalID,
+		}
+		if tx.Type != transaction.TransactionTypeTransfer {
+			payload.TransactionType = string(tx.Type)
+		}
+		return event.TypeFundsTransferred, tx.FromUserID, payload
+	case tx.ToUserID != "":
+		return event.TypeFundsDeposited, tx.ToUserID, event.FundsDeposited{
+			UserID:                tx.ToUserID,
+			Amount:                tx.Amount,
+			Currency:              tx.Currency,
+			TransactionID:         tx.ID,
+			TransactionType:       string(tx.Type),
+			OriginalTransactionID: tx.OriginalID,
+		}
+	default:
+		return event.TypeFundsWithdrawn, tx.FromUserID, event.FundsWithdrawn{
+			UserID:                tx.FromUserID,
+			Amount:                tx.Amount,
+			Currency:              tx.Currency,
+			TransactionID:         tx.ID,
+			TransactionType:       string(tx.Type),
+			OriginalTransactionID: tx.OriginalID,
+		}
+	}
+}
+
+// adjust credits or debits userID's wallet by hand and logs an ADJUSTMENT transaction;
+// callers must run it inside txManager.Do.
+func (uc *WalletUseCase) adjust(ctx context.Context, userID string, credit bool, amount int64, currency string, opts ...transaction.Option) (transaction.Transaction, error) {
+	if credit {
+		if err := uc.walletService.Deposit(ctx, userID, amount); err != nil {
+			return transaction.Transaction{}, err
+		}
+		return uc.logTransaction(ctx, "", userID, amount, currency, transaction.TransactionTypeAdjustment, opts...)
+	}
+
+	if err := uc.walletService.Withdraw(ctx, userID, amount); err != nil {
+		return transaction.Transaction{}, err
+	}
+	return uc.logTransaction(ctx, userID, "", amount, currency, transaction.TransactionTypeAdjustment, opts...)
+}
+
+func (uc *WalletUseCase) GetWallet(ctx context.Context, userID string) (wallet.Wallet, error) {
+	return uc.walletService.GetWallet(ctx, userID)
 }
 
 func (uc *WalletUseCase) GetBalance(ctx context.Context, userID string) (int64, error) {
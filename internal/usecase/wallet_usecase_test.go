@@ -3,13 +3,29 @@ package usecase
 
 import (
 	"context"
+	"encoding/json"
+	"fmt"
+	"slices"
+	"sync"
 	"testing"
-
+	"time"
+
+	"exchange/internal/domain/audit"
+	"exchange/internal/domain/event"
+	"exchange/internal/domain/kyc"
+	"exchange/internal/domain/limit"
+	"exchange/internal/domain/period"
+	"exchange/internal/domain/reserves"
+	"exchange/internal/domain/risk"
+	"exchange/internal/domain/sanctions"
+	"exchange/internal/domain/snapshot"
 	"exchange/internal/domain/transaction"
+	"exchange/internal/domain/user"
 	"exchange/internal/domain/wallet"
 
 	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
 )
 
 type MockWalletService struct {
@@ -31,11 +47,282 @@ func (m *MockWalletService) Withdraw(ctx context.Context, userID string, amount
 	return args.Error(0)
 }
 
+func (m *MockWalletService) Hold(ctx context.Context, userID string, amount int64) error {
+	args := m.Called(ctx, userID, amount)
+	return args.Error(0)
+}
+
+func (m *MockWalletService) Release(ctx context.Context, userID string, amount int64) error {
+	args := m.Called(ctx, userID, amount)
+	return args.Error(0)
+}
+
+func (m *MockWalletService) Capture(ctx context.Context, userID string, amount int64) error {
+	args := m.Called(ctx, userID, amount)
+	return args.Error(0)
+}
+
 func (m *MockWalletService) GetBalance(ctx context.Context, userID string) (int64, error) {
 	args := m.Called(ctx, userID)
 	return args.Get(0).(int64), args.Error(1)
 }
 
+func (m *MockWalletService) GetWallet(ctx context.Context, userID string) (wallet.Wallet, error) {
+	args := m.Called(ctx, userID)
+	return args.Get(0).(wallet.Wallet), args.Error(1)
+}
+
+func (m *MockWalletService) LockWallet(ctx context.Context, userID string) (wallet.Wallet, error) {
+	args := m.Called(ctx, userID)
+	return args.Get(0).(wallet.Wallet), args.Error(1)
+}
+
+// fixedLimits gives every user the same limits.
+type fixedLimits []limit.Limit
+
+func (l fixedLimits) GetLimits(ctx context.Context, userID string) ([]limit.Limit, error) {
+	return l, nil
+}
+
+// fixedRisk assesses every request with the same decision and rule IDs; the zero value
+// allows everything.
+type fixedRisk risk.Assessment
+
+func (f fixedRisk) Assess(ctx context.Context, req risk.Request) (risk.Assessment, error) {
+	a := risk.Assessment(f)
+	if a.Decision == "" {
+		a.Decision = risk.DecisionAllow
+	}
+	a.UserID, a.Operation, a.CounterpartyID, a.Amount, a.Currency = req.UserID, req.Operation, req.CounterpartyID, req.Amount, req.Currency
+	return a, nil
+}
+
+func (f fixedRisk) ListAssessments(ctx context.Context, filter risk.Filter, limit, offset int) ([]risk.Assessment, error) {
+	return nil, nil
+}
+
+// fixedUsers fails CheckActive for the listed users with their error; everyone else is
+// registered and active.
+type fixedUsers map[string]error
+
+func (f fixedUsers) Register(ctx context.Context, id, name, email string) (user.User, error) {
+	return user.NewUser(id, name, email)
+}
+
+func (f fixedUsers) GetUser(ctx context.Context, id string) (user.User, error) {
+	switch err := f[id]; err {
+	case nil:
+		return user.User{ID: id, Status: user.StatusActive}, nil
+	case user.ErrUserDeactivated:
+		return user.User{ID: id, Status: user.StatusDeactivated}, nil
+	default:
+		return user.User{}, err
+	}
+}
+
+func (f fixedUsers) UpdateProfile(ctx context.Context, id, name, email string) (user.User, error) {
+	return f.GetUser(ctx, id)
+}
+
+func (f fixedUsers) SetStatus(ctx context.Context, id string, status user.Status) (user.User, error) {
+	u, err := f.GetUser(ctx, id)
+	u.Status = status
+	return u, err
+}
+
+func (f fixedUsers) CheckActive(ctx context.Context, id string) error {
+	return f[id]
+}
+
+// fixedKYC caps the listed users; everyone else is fully verified and uncapped.
+type fixedKYC map[string]kyc.Caps
+
+func (f fixedKYC) GetLevel(ctx context.Context, userID string) (kyc.Level, error) {
+	if _, ok := f[userID]; ok {
+		return kyc.LevelNone, nil
+	}
+	return kyc.LevelFull, nil
+}
+
+func (f fixedKYC) GetCaps(ctx context.Context, userID string) (kyc.Caps, error) {
+	return f[userID], nil
+}
+
+func (f fixedKYC) CapsOf(level kyc.Level) kyc.Caps {
+	return kyc.Caps{}
+}
+
+func (f fixedKYC) ListLevelChanges(ctx context.Context, userID string) ([]kyc.LevelChange, error) {
+	return nil, nil
+}
+
+func (f fixedKYC) SetLevel(ctx context.Context, userID string, level kyc.Level, changedBy, reason string) (kyc.LevelChange, error) {
+	return kyc.LevelChange{UserID: userID, To: level, ChangedBy: changedBy, Reason: reason}, nil
+}
+
+func (f fixedKYC) Submit(ctx context.Context, userID string, level kyc.Level, documentType, fileName, contentType string, content []byte) (kyc.Submission, error) {
+	return kyc.NewSubmission("kyc1", userID, level, documentType, fileName, contentType, int64(len(content)))
+}
+
+func (f fixedKYC) GetSubmission(ctx context.Context, id string) (kyc.Submission, error) {
+	return kyc.Submission{}, kyc.ErrSubmissionNotFound
+}
+
+func (f fixedKYC) ListSubmissions(ctx context.Context, filter kyc.Filter, limit, offset int) ([]kyc.Submission, error) {
+	return nil, nil
+}
+
+func (f fixedKYC) GetDocument(ctx context.Context, s kyc.Submission) ([]byte, error) {
+	return nil, kyc.ErrDocumentNotFound
+}
+
+func (f fixedKYC) Review(ctx context.Context, id, reviewerID string, approved bool, reason string) (kyc.Submission, error) {
+	s, err := kyc.NewSubmission(id, "user1", kyc.LevelBasic, "passport", "", "", 1)
+	if err != nil {
+		return kyc.Submission{}, err
+	}
+	return s, s.Review(reviewerID, approved, reason)
+}
+
+// fixedSanctions blocks the listed user IDs and destinations; everyone else passes the
+// screening.
+type fixedSanctions map[string]bool
+
+func (f fixedSanctions) Screen(ctx context.Context, operation string, subjects ...sanctions.Subject) error {
+	for _, s := range subjects {
+		if f[s.ID] {
+			return sanctions.ErrBlocked
+		}
+	}
+	return nil
+}
+
+func (f fixedSanctions) GetCase(ctx context.Context, id string) (sanctions.Case, error) {
+	return sanctions.Case{}, sanctions.ErrCaseNotFound
+}
+
+func (f fixedSanctions) ListCases(ctx context.Context, filter sanctions.Filter, limit, offset int) ([]sanctions.Case, error) {
+	return nil, nil
+}
+
+func (f fixedSanctions) ClearCase(ctx context.Context, id, adminID, reason string) (sanctions.Case, error) {
+	c := sanctions.Case{ID: id, Status: sanctions.CaseOpen}
+	return c, c.Clear(adminID, reason)
+}
+
+// snapshotRecorder keeps recorded balance snapshots in memory.
+type snapshotRecorder struct {
+	snapshots []snapshot.Snapshot
+	totals    []snapshot.Total
+}
+
+func (r *snapshotRecorder) Record(ctx context.Context, s snapshot.Snapshot) error {
+	r.snapshots = append(r.snapshots, s)
+	return nil
+}
+
+func (r *snapshotRecorder) Latest(ctx context.Context, userID string, at time.Time) (snapshot.Snapshot, error) {
+	var latest snapshot.Snapshot
+	found := false
+	for _, s := range r.snapshots {
+		if s.UserID == userID && !s.TakenAt.After(at) && (!found || s.TakenAt.After(latest.TakenAt)) {
+			latest, found = s, true
+		}
+	}
+	if !found {
+		return snapshot.Snapshot{}, snapshot.ErrSnapshotNotFound
+	}
+	return latest, nil
+}
+
+func (r *snapshotRecorder) RecordTotal(ctx context.Context, t snapshot.Total) error {
+	r.totals = append(r.totals, t)
+	return nil
+}
+
+func (r *snapshotRecorder) Totals(ctx context.Context, at time.Time) ([]snapshot.Total, error) {
+	var totals []snapshot.Total
+	for _, t := range r.totals {
+		if t.TakenAt.Equal(at) {
+			totals = append(totals, t)
+		}
+	}
+	if len(totals) == 0 {
+		return nil, snapshot.ErrSnapshotNotFound
+	}
+	return totals, nil
+}
+
+// closedThrough closes every period before it; the zero value leaves them all open.
+type closedThrough time.Time
+
+func (c closedThrough) Close(ctx context.Context, month time.Time, adminID string) (period.Close, error) {
+	closed, err := period.NewClose(month, adminID, time.Now())
+	if err != nil {
+		return period.Close{}, err
+	}
+	if !closed.End.After(time.Time(c)) {
+		return period.Close{}, period.ErrAlreadyClosed
+	}
+	return closed, nil
+}
+
+func (c closedThrough) ListCloses(ctx context.Context, limit, offset int) ([]period.Close, error) {
+	return nil, nil
+}
+
+func (c closedThrough) CheckOpen(ctx context.Context, at time.Time) error {
+	if at.Before(time.Time(c)) {
+		return period.ErrPeriodClosed
+	}
+	return nil
+}
+
+// reservesRecorder keeps the newest published liability tree of each currency in memory.
+type reservesRecorder struct {
+	trees map[string]*reserves.Tree
+}
+
+func (r *reservesRecorder) Publish(ctx context.Context, currency string, takenAt time.Time, balances map[string]int64) (reserves.Root, error) {
+	if tree, ok := r.trees[currency]; ok && tree.Root().TakenAt.Equal(takenAt) {
+		return reserves.Root{}, reserves.ErrAlreadyPublished
+	}
+	var leaves []reserves.Leaf
+	for userID, balance := range balances {
+		leaves = append(leaves, reserves.Leaf{UserID: userID, Balance: balance, Nonce: "nonce-" + userID})
+	}
+	tree, err := reserves.NewTree(currency, takenAt, leaves)
+	if err != nil {
+		return reserves.Root{}, err
+	}
+	if r.trees == nil {
+		r.trees = make(map[string]*reserves.Tree)
+	}
+	r.trees[currency] = tree
+	return tree.Root(), nil
+}
+
+func (r *reservesRecorder) LatestRoots(ctx context.Context) ([]reserves.Root, error) {
+	var roots []reserves.Root
+	for _, tree := range r.trees {
+		roots = append(roots, tree.Root())
+	}
+	return roots, nil
+}
+
+func (r *reservesRecorder) Prove(ctx context.Context, userID, currency string) (reserves.Proof, error) {
+	tree, ok := r.trees[currency]
+	if !ok {
+		return reserves.Proof{}, reserves.ErrRootNotFound
+	}
+	return tree.Prove(userID)
+}
+
+func (m *MockWalletService) SearchWallets(ctx context.Context, filter wallet.SearchFilter, limit, offset int) ([]wallet.Wallet, error) {
+	args := m.Called(ctx, filter, limit, offset)
+	return args.Get(0).([]wallet.Wallet), args.Error(1)
+}
+
 type MockTransactionManager struct {
 	mock.Mock
 	DoFn func(ctx context.Context, fn func(ctx context.Context) error) error
@@ -49,12 +336,94 @@ func (m *MockTransactionManager) Do(ctx context.Context, fn func(ctx context.Con
 	return args.Error(0)
 }
 
+func (m *MockTransactionManager) ReadOnly(ctx context.Context, fn func(ctx context.Context) error) error {
+	if m.DoFn != nil {
+		return m.DoFn(ctx, fn)
+	}
+	return fn(ctx)
+}
+
+// auditRecorder is an in-memory audit service that keeps the recorded entries in order.
+type auditRecorder struct {
+	mu      sync.Mutex
+	entries []audit.Entry
+}
+
+func (r *auditRecorder) Begin(_ context.Context, action audit.Action, userIDs ...string) audit.Entry {
+	return audit.NewEntry(action, userIDs...)
+}
+
+func (r *auditRecorder) RecordSuccess(_ context.Context, e audit.Entry) error {
+	e.Outcome = audit.OutcomeSuccess
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	r.entries = append(r.entries, e)
+	return nil
+}
+
+func (r *auditRecorder) RecordFailure(_ context.Context, e audit.Entry, cause error) error {
+	e.Outcome = audit.OutcomeFailure
+	e.Error = cause.Error()
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	r.entries = append(r.entries, e)
+	return nil
+}
+
+func (r *auditRecorder) ListEntries(_ context.Context, _ audit.Filter, _, _ int) ([]audit.Entry, error) {
+	return r.entries, nil
+}
+
+func (r *auditRecorder) VerifyChain(_ context.Context) (audit.ChainReport, error) {
+	return audit.ChainReport{Entries: int64(len(r.entries)), Valid: true}, nil
+}
+
+// eventRecorder is an in-memory event service that keeps the recorded events in order.
+type eventRecorder struct {
+	events    []event.Event
+	published []string
+	failed    map[string]string
+}
+
+// Record does not validate the event: most mocked transactions carry only an ID.
+func (r *eventRecorder) Record(_ context.Context, t event.Type, aggregateID string, payload any) error {
+	data, err := json.Marshal(payload)
+	if err != nil {
+		return err
+	}
+	r.events = append(r.events, event.Event{ID: fmt.Sprintf("ev%d", len(r.events)+1), Type: t, AggregateID: aggregateID, Payload: data})
+	return nil
+}
+
+func (r *eventRecorder) ListPendingEvents(_ context.Context, limit int) ([]event.Event, error) {
+	var pending []event.Event
+	for _, e := range r.events {
+		if !slices.Contains(r.published, e.ID) && len(pending) < limit {
+			pending = append(pending, e)
+		}
+	}
+	return pending, nil
+}
+
+func (r *eventRecorder) MarkPublished(_ context.Context, e event.Event) error {
+	r.published = append(r.published, e.ID)
+	return nil
+}
+
+func (r *eventRecorder) MarkFailed(_ context.Context, e event.Event, cause error) error {
+	if r.failed == nil {
+		r.failed = map[string]string{}
+	}
+	r.failed[e.ID] = cause.Error()
+	return nil
+}
+
 func TestWalletUseCase_Deposit(t *testing.T) {
 	mockWalletService := new(MockWalletService)
 	mockTransactionService := new(MockTransactionService)
 	mockTxManager := new(MockTransactionManager)
 
-	useCase := NewWalletUseCase(mockWalletService, mockTransactionService, mockTxManager)
+	useCase := NewWalletUseCase(testDependencies(mockWalletService, mockTransactionService, mockTxManager))
 
 	ctx := context.Background()
 	userID := "user1"
@@ -109,7 +478,7 @@ func TestWalletUseCase_Withdraw(t *testing.T) {
 	mockTransactionService := new(MockTransactionService)
 	mockTxManager := new(MockTransactionManager)
 
-	useCase := NewWalletUseCase(mockWalletService, mockTransactionService, mockTxManager)
+	useCase := NewWalletUseCase(testDependencies(mockWalletService, mockTransactionService, mockTxManager))
 
 	ctx := context.Background()
 	userID := "user1"
@@ -133,7 +502,7 @@ func TestWalletUseCase_Withdraw(t *testing.T) {
 		}
 		mockTransactionService.On("LogTransaction", ctx, userID, "", amount, currency, transaction.TransactionTypeWithdraw).Return(expectedTx, nil)
 
-		err := useCase.Withdraw(ctx, userID, amount, currency)
+		_, err := useCase.Withdraw(ctx, userID, amount, currency, "")
 
 		assert.NoError(t, err)
 		mockTxManager.AssertExpectations(t)
@@ -151,7 +520,7 @@ func TestWalletUseCase_Withdraw(t *testing.T) {
 
 		mockTransactionService.On("LogTransaction", mock.Anything, mock.Anything, mock.Anything, mock.Anything, mock.Anything, mock.Anything).Return(transaction.Transaction{}, nil).Maybe()
 
-		err := useCase.Withdraw(ctx, userID, invalidAmount, currency)
+		_, err := useCase.Withdraw(ctx, userID, invalidAmount, currency, "")
 
 		assert.ErrorIs(t, err, wallet.ErrInvalidAmount)
 		mockTxManager.AssertExpectations(t)
@@ -165,7 +534,7 @@ func TestWalletUseCase_Transfer(t *testing.T) {
 	mockTransactionService := new(MockTransactionService)
 	mockTxManager := new(MockTransactionManager)
 
-	useCase := NewWalletUseCase(mockWalletService, mockTransactionService, mockTxManager)
+	useCase := NewWalletUseCase(testDependencies(mockWalletService, mockTransactionService, mockTxManager))
 
 	ctx := context.Background()
 	fromUserID := "user1"
@@ -204,7 +573,7 @@ func TestWalletUseCase_GetBalance(t *testing.T) {
 	mockTransactionService := new(MockTransactionService)
 	mockTxManager := new(MockTransactionManager)
 
-	useCase := NewWalletUseCase(mockWalletService, mockTransactionService, mockTxManager)
+	useCase := NewWalletUseCase(testDependencies(mockWalletService, mockTransactionService, mockTxManager))
 
 	ctx := context.Background()
 	userID := "user1"
@@ -232,3 +601,259 @@ func TestWalletUseCase_GetBalance(t *testing.T) {
 		mockWalletService.AssertExpectations(t)
 	})
 }
+
+func TestWalletUseCase_Audit(t *testing.T) {
+	ctx := context.Background()
+	mockWalletService := new(MockWalletService)
+	mockTransactionService := new(MockTransactionService)
+	mockTxManager := new(MockTransactionManager)
+	mockTxManager.DoFn = func(ctx context.Context, fn func(context.Context) error) error {
+		return fn(ctx)
+	}
+	recorder := new(auditRecorder)
+	deps := testDependencies(mockWalletService, mockTransactionService, mockTxManager)
+	deps.Audit = recorder
+	useCase := NewWalletUseCase(deps)
+
+	mockWalletService.On("Withdraw", ctx, "user1", int64(300)).Return(nil)
+	mockWalletService.On("Deposit", ctx, "user2", int64(300)).Return(nil)
+	mockTransactionService.On("LogTransaction", ctx, "user1", "user2", int64(300), "USD", transaction.TransactionTypeTransfer).Return(transaction.Transaction{ID: "tx1"}, nil)
+	mockWalletService.On("Withdraw", ctx, "user1", int64(5000)).Return(wallet.ErrInsufficientFunds)
+
+	assert.NoError(t, useCase.Transfer(ctx, "user1", "user2", 300, "USD"))
+	_, err := useCase.Withdraw(ctx, "user1", 5000, "USD", "")
+	assert.ErrorIs(t, err, wallet.ErrInsufficientFunds)
+
+	require.Len(t, recorder.entries, 2)
+	assert.Equal(t, audit.ActionTransfer, recorder.entries[0].Action)
+	assert.Equal(t, audit.OutcomeSuccess, recorder.entries[0].Outcome)
+	assert.Equal(t, "tx1", recorder.entries[0].TransactionID)
+	assert.Equal(t, []audit.BalanceChange{{UserID: "user1"}, {UserID: "user2"}}, recorder.entries[0].Balances)
+	assert.Equal(t, audit.ActionWithdraw, recorder.entries[1].Action)
+	assert.Equal(t, audit.OutcomeFailure, recorder.entries[1].Outcome)
+	assert.Equal(t, wallet.ErrInsufficientFunds.Error(), recorder.entries[1].Error)
+}
+
+func TestWalletUseCase_Events(t *testing.T) {
+	ctx := context.Background()
+	mockWalletService := new(MockWalletService)
+	mockTransactionService := new(MockTransactionService)
+	mockTxManager := new(MockTransactionManager)
+	mockTxManager.DoFn = func(ctx context.Context, fn func(context.Context) error) error {
+		return fn(ctx)
+	}
+	events := new(eventRecorder)
+	deps := testDependencies(mockWalletService, mockTransactionService, mockTxManager)
+	deps.Events = events
+	useCase := NewWalletUseCase(deps)
+
+	mockWalletService.On("CreateNewWallet", ctx, "user3", "USD").Return(wallet.Wallet{UserID: "user3", Currency: "USD"}, nil)
+	mockWalletService.On("Deposit", ctx, "user3", int64(500)).Return(nil)
+	mockTransactionService.On("LogTransaction", ctx, "", "user3", int64(500), "USD", transaction.TransactionTypeDeposit).
+		Return(transaction.Transaction{ID: "tx1", ToUserID: "user3", Amount: 500, Currency: "USD", Type: transaction.TransactionTypeDeposit}, nil)
+	mockWalletService.On("Withdraw", ctx, "user3", int64(200)).Return(nil)
+	mockWalletService.On("Deposit", ctx, "user1", int64(200)).Return(nil)
+	mockTransactionService.On("LogTransaction", ctx, "user3", "user1", int64(200), "USD", transaction.TransactionTypeTransfer).
+		Return(transaction.Transaction{ID: "tx2", FromUserID: "user3", ToUserID: "user1", Amount: 200, Currency: "USD", Type: transaction.TransactionTypeTransfer}, nil)
+
+	_, err := useCase.CreateWallet(ctx, "user3", "USD")
+	require.NoError(t, err)
+	require.NoError(t, useCase.Deposit(ctx, "user3", 500, "USD"))
+	require.NoError(t, useCase.Transfer(ctx, "user3", "user1", 200, "USD"))
+
+	require.Len(t, events.events, 3)
+	assert.Equal(t, event.TypeWalletCreated, events.events[0].Type)
+	assert.Equal(t, event.TypeFundsDeposited, events.events[1].Type)
+	assert.JSONEq(t, `{"user_id":"user3","amount":500,"currency":"USD","transaction_id":"tx1","transaction_type":"DEPOSIT"}`, string(events.events[1].Payload))
+	assert.Equal(t, event.TypeFundsTransferred, events.events[2].Type)
+	assert.Equal(t, "user3", events.events[2].AggregateID)
+	assert.JSONEq(t, `{"from_user_id":"user3","to_user_id":"user1","amount":200,"currency":"USD","transaction_id":"tx2"}`, string(events.events[2].Payload))
+}
+
+func TestWalletUseCase_Refund(t *testing.T) {
+	ctx := context.Background()
+	original := transaction.Transaction{ID: "tx1", FromUserID: "user1", ToUserID: "user2", Amount: 1000, Currency: "USD", Type: transaction.TransactionTypeTransfer}
+
+	newUseCase := func() (*WalletUseCase, *MockWalletService, *MockTransactionService) {
+		mockWalletService := new(MockWalletService)
+		mockTransactionService := new(MockTransactionService)
+		mockTxManager := new(MockTransactionManager)
+		mockTxManager.DoFn = func(ctx context.Context, fn func(context.Context) error) error {
+			return fn(ctx)
+		}
+		mockTransactionService.On("GetTransactionByID", ctx, "tx1").Return(original, nil)
+		return NewWalletUseCase(testDependencies(mockWalletService, mockTransactionService, mockTxManager)), mockWalletService, mockTransactionService
+	}
+
+	t.Run("partial refund moves the funds back", func(t *testing.T) {
+		useCase, mockWalletService, mockTransactionService := newUseCase()
+		refund := transaction.Transaction{ID: "tx2", FromUserID: "user2", ToUserID: "user1", Amount: 400, Currency: "USD", Type: transaction.TransactionTypeRefund, OriginalID: "tx1"}
+		mockTransactionService.On("LogUndo", ctx, "tx1", transaction.TransactionTypeRefund, int64(400)).Return(refund, nil)
+		mockWalletService.On("Withdraw", ctx, "user2", int64(400)).Return(nil)
+		mockWalletService.On("Deposit", ctx, "user1", int64(400)).Return(nil)
+
+		tx, err := useCase.Refund(ctx, "tx1", 400)
+
+		require.NoError(t, err)
+		assert.Equal(t, refund, tx)
+		mockWalletService.AssertExpectations(t)
+
+		entries := useCase.auditService.(*auditRecorder).entries
+		require.Len(t, entries, 1)
+		assert.Equal(t, audit.ActionRefund, entries[0].Action)
+		assert.Equal(t, "tx1", entries[0].Target)
+		assert.Equal(t, "tx2", entries[0].TransactionID)
+		assert.Equal(t, []audit.BalanceChange{{UserID: "user2"}, {UserID: "user1"}}, entries[0].Balances)
+
+		events := useCase.eventService.(*eventRecorder).events
+		require.Len(t, events, 1)
+		assert.JSONEq(t, `{"from_user_id":"user2","to_user_id":"user1","amount":400,"currency":"USD","transaction_id":"tx2","transaction_type":"REFUND","original_transaction_id":"tx1"}`, string(events[0].Payload))
+	})
+
+	t.Run("recipient without the funds", func(t *testing.T) {
+		useCase, mockWalletService, mockTransactionService := newUseCase()
+		reversal := transaction.Transaction{ID: "tx2", FromUserID: "user2", ToUserID: "user1", Amount: 1000, Currency: "USD", Type: transaction.TransactionTypeReversal, OriginalID: "tx1"}
+		mockTransactionService.On("LogUndo", ctx, "tx1", transaction.TransactionTypeReversal, int64(0)).Return(reversal, nil)
+		mockWalletService.On("Withdraw", ctx, "user2", int64(1000)).Return(wallet.ErrInsufficientFunds)
+
+		_, err := useCase.Reverse(ctx, "tx1", 0)
+
+		assert.ErrorIs(t, err, wallet.ErrInsufficientFunds)
+		mockWalletService.AssertNotCalled(t, "Deposit", mock.Anything, mock.Anything, mock.Anything)
+		assert.Empty(t, useCase.eventService.(*eventRecorder).events)
+		entries := useCase.auditService.(*auditRecorder).entries
+		require.Len(t, entries, 1)
+		assert.Equal(t, audit.ActionReverse, entries[0].Action)
+		assert.Equal(t, audit.OutcomeFailure, entries[0].Outcome)
+	})
+
+	t.Run("unknown original", func(t *testing.T) {
+		useCase, _, mockTransactionService := newUseCase()
+		mockTransactionService.On("GetTransactionByID", ctx, "missing").Return(transaction.Transaction{}, transaction.ErrTransactionNotFound)
+
+		_, err := useCase.Refund(ctx, "missing", 0)
+
+		assert.ErrorIs(t, err, transaction.ErrTransactionNotFound)
+		mockTransactionService.AssertNotCalled(t, "LogUndo", mock.Anything, mock.Anything, mock.Anything, mock.Anything)
+	})
+}
+
+func TestWalletUseCase_Withdrawals(t *testing.T) {
+	ctx := context.Background()
+	pending := transaction.Transaction{ID: "tx1", FromUserID: "user1", Amount: 500, Currency: "USD", Type: transaction.TransactionTypeWithdraw, Status: transaction.StatusPending}
+
+	newUseCase := func() (*WalletUseCase, *MockWalletService, *MockTransactionService) {
+		mockWalletService := new(MockWalletService)
+		mockTransactionService := new(MockTransactionService)
+		mockTxManager := new(MockTransactionManager)
+		mockTxManager.DoFn = func(ctx context.Context, fn func(context.Context) error) error {
+			return fn(ctx)
+		}
+		mockTransactionService.On("GetTransactionByID", ctx, "tx1").Return(pending, nil)
+		return NewWalletUseCase(testDependencies(mockWalletService, mockTransactionService, mockTxManager)), mockWalletService, mockTransactionService
+	}
+	withStatus := func(status transaction.Status) transaction.Transaction {
+		tx := pending
+		tx.Status = status
+		return tx
+	}
+
+	t.Run("request holds the funds", func(t *testing.T) {
+		useCase, mockWalletService, mockTransactionService := newUseCase()
+		mockWalletService.On("Hold", ctx, "user1", int64(500)).Return(nil)
+		mockTransactionService.On("LogTransaction", ctx, "user1", "", int64(500), "USD", transaction.TransactionTypeWithdraw).
+			Return(transaction.Transaction{ID: "tx1", FromUserID: "user1", Amount: 500, Currency: "USD", Type: transaction.TransactionTypeWithdraw, Status: transaction.StatusCompleted}, nil)
+
+		tx, err := useCase.RequestWithdrawal(ctx, "user1", 500, "USD", "")
+
+		require.NoError(t, err)
+		assert.Equal(t, transaction.StatusPending, tx.Status)
+		mockWalletService.AssertNotCalled(t, "Withdraw", mock.Anything, mock.Anything, mock.Anything)
+		assert.Empty(t, useCase.eventService.(*eventRecorder).events, "no funds have moved yet")
+		entries := useCase.auditService.(*auditRecorder).entries
+		require.Len(t, entries, 1)
+		assert.Equal(t, audit.ActionRequestWithdrawal, entries[0].Action)
+		assert.Equal(t, "tx1", entries[0].TransactionID)
+	})
+
+	t.Run("request without available funds", func(t *testing.T) {
+		useCase, mockWalletService, mockTransactionService := newUseCase()
+		mockWalletService.On("Hold", ctx, "user1", int64(500)).Return(wallet.ErrInsufficientFunds)
+
+		_, err := useCase.RequestWithdrawal(ctx, "user1", 500, "USD", "")
+
+		assert.ErrorIs(t, err, wallet.ErrInsufficientFunds)
+		mockTransactionService.AssertNotCalled(t, "LogTransaction", mock.Anything, mock.Anything, mock.Anything, mock.Anything, mock.Anything, mock.Anything)
+	})
+
+	t.Run("completion captures the funds", func(t *testing.T) {
+		useCase, mockWalletService, mockTransactionService := newUseCase()
+		mockTransactionService.On("TransitionStatus", ctx, "tx1", transaction.StatusCompleted, "").Return(withStatus(transaction.StatusCompleted), nil)
+		mockWalletService.On("Capture", ctx, "user1", int64(500)).Return(nil)
+
+		tx, err := useCase.UpdateTransactionStatus(ctx, "tx1", transaction.StatusCompleted)
+
+		require.NoError(t, err)
+		assert.Equal(t, transaction.StatusCompleted, tx.Status)
+		mockWalletService.AssertExpectations(t)
+		events := useCase.eventService.(*eventRecorder).events
+		require.Len(t, events, 1)
+		assert.Equal(t, event.TypeFundsWithdrawn, events[0].Type)
+	})
+
+	t.Run("cancellation releases the funds", func(t *testing.T) {
+		useCase, mockWalletService, mockTransactionService := newUseCase()
+		mockTransactionService.On("TransitionStatus", ctx, "tx1", transaction.StatusCancelled, "").Return(withStatus(transaction.StatusCancelled), nil)
+		mockWalletService.On("Release", ctx, "user1", int64(500)).Return(nil)
+
+		_, err := useCase.CancelWithdrawal(ctx, "tx1")
+
+		require.NoError(t, err)
+		mockWalletService.AssertExpectations(t)
+		assert.Empty(t, useCase.eventService.(*eventRecorder).events)
+		entries := useCase.auditService.(*auditRecorder).entries
+		require.Len(t, entries, 1)
+		assert.Equal(t, audit.ActionCancelWithdrawal, entries[0].Action)
+	})
+
+	t.Run("processing keeps the funds held", func(t *testing.T) {
+		useCase, mockWalletService, mockTransactionService := newUseCase()
+		mockTransactionService.On("TransitionStatus", ctx, "tx1", transaction.StatusProcessing, "").Return(withStatus(transaction.StatusProcessing), nil)
+
+		_, err := useCase.UpdateTransactionStatus(ctx, "tx1", transaction.StatusProcessing)
+
+		require.NoError(t, err)
+		mockWalletService.AssertNotCalled(t, "Capture", mock.Anything, mock.Anything, mock.Anything)
+		mockWalletService.AssertNotCalled(t, "Release", mock.Anything, mock.Anything, mock.Anything)
+	})
+
+	t.Run("invalid transition", func(t *testing.T) {
+		useCase, mockWalletService, mockTransactionService := newUseCase()
+		mockTransactionService.On("TransitionStatus", ctx, "tx1", transaction.StatusCancelled, "").Return(transaction.Transaction{}, transaction.ErrInvalidStatusTransition)
+
+		_, err := useCase.CancelWithdrawal(ctx, "tx1")
+
+		assert.ErrorIs(t, err, transaction.ErrInvalidStatusTransition)
+		mockWalletService.AssertNotCalled(t, "Release", mock.Anything, mock.Anything, mock.Anything)
+	})
+}
+
+// testDependencies returns the dependencies of a WalletUseCase around the given services,
+// with fakes that allow everything and record nothing for the rest. Tests replace the
+// fakes they exercise.
+func testDependencies(wService WalletServiceInterface, tService TransactionServiceInterface, txManager TransactionManager) WalletDependencies {
+	return WalletDependencies{
+		Wallets:      wService,
+		Transactions: tService,
+		TxManager:    txManager,
+		Audit:        new(auditRecorder),
+		Events:       new(eventRecorder),
+		Limits:       fixedLimits(nil),
+		Risk:         fixedRisk{},
+		Users:        fixedUsers(nil),
+		KYC:          fixedKYC(nil),
+		Sanctions:    fixedSanctions(nil),
+		Snapshots:    new(snapshotRecorder),
+		Reserves:     new(reservesRecorder),
+	}
+}
@@ -0,0 +1,157 @@
+package usecase
+
+import (
+	"context"
+	"log"
+	"strconv"
+	"time"
+
+	"exchange/internal/domain/event"
+	"exchange/internal/domain/webhook"
+)
+
+// WebhookUseCase manages webhook subscriptions and sends their deliveries.
+type WebhookUseCase struct {
+	webhookService webhook.WebhookServiceInterface
+	sender         webhook.Sender
+	txManager      TransactionManager
+	batchSize      int
+	// lease is how long a claimed batch is held back from other dispatchers: long enough
+	// to send every delivery in it.
+	lease time.Duration
+	now   func() time.Time
+}
+
+// claimMargin is added to the time a batch may take to send, so a batch that is still
+// being recorded is not claimed again.
+const claimMargin = time.Minute
+
+// NewWebhookUseCase returns a use case that dispatches batches of batchSize deliveries
+// through sender, whose requests give up after sendTimeout.
+func NewWebhookUseCase(wService webhook.WebhookServiceInterface, sender webhook.Sender, txManager TransactionManager, batchSize int, sendTimeout time.Duration) *WebhookUseCase {
+	return &WebhookUseCase{
+		webhookService: wService,
+		sender:         sender,
+		txManager:      txManager,
+		batchSize:      batchSize,
+		lease:          time.Duration(batchSize)*sendTimeout + claimMargin,
+		now:            time.Now,
+	}
+}
+
+func (uc *WebhookUseCase) CreateSubscription(ctx context.Context, userID, url string, eventTypes []event.Type) (webhook.Subscription, error) {
+	return uc.webhookService.CreateSubscription(ctx, userID, url, eventTypes)
+}
+
+func (uc *WebhookUseCase) ListSubscriptions(ctx context.Context, userID string) ([]webhook.Subscription, error) {
+	return uc.webhookService.ListSubscriptions(ctx, userID)
+}
+
+func (uc *WebhookUseCase) DeleteSubscription(ctx context.Context, userID, id string) error {
+	return uc.webhookService.DeleteSubscription(ctx, userID, id)
+}
+
+func (uc *WebhookUseCase) ListDeliveries(ctx context.Context, filter webhook.DeliveryFilter, limit, offset int) ([]webhook.Delivery, error) {
+	return uc.webhookService.ListDeliveries(ctx, filter, limit, offset)
+}
+
+func (uc *WebhookUseCase) ListAttempts(ctx context.Context, userID, deliveryID string) ([]webhook.Attempt, error) {
+	return uc.webhookService.ListAttempts(ctx, userID, deliveryID)
+}
+
+// ReplayDelivery queues one of userID's finished deliveries to be sent again by the
+// next dispatch.
+func (uc *WebhookUseCase) ReplayDelivery(ctx context.Context, userID, id string) (webhook.Delivery, error) {
+	return uc.webhookService.ReplayDelivery(ctx, userID, id)
+}
+
+// HandleEvent queues deliveries of e to the matching subscriptions. Subscribe it to the
+// in-process publisher so the deliveries are created in the relay's transaction.
+func (uc *WebhookUseCase) HandleEvent(ctx context.Context, e event.Event) error {
+	return uc.webhookService.Enqueue(ctx, e)
+}
+
+// DispatchDue sends up to one batch of due deliveries and returns how many were
+// attempted. The batch is claimed in one short transaction, sent outside of any, and
+// the attempts are recorded in another, so no database transaction or lock is held
+// while subscribers respond. Claimed deliveries are skipped by other dispatchers until
+// the claim expires; if the attempts can not be recorded, the batch is sent again then.
+// A failed attempt is retried later according to the retry policy; it does not stop
+// the batch.
+func (uc *WebhookUseCase) DispatchDue(ctx context.Context) (int, error) {
+	var deliveries []webhook.Delivery
+	var subs []webhook.Subscription
+	err := uc.txManager.Do(ctx, func(ctx context.Context) error {
+		var err error
+		deliveries, err = uc.webhookService.ClaimDueDeliveries(ctx, uc.batchSize, uc.lease)
+		if err != nil {
+			return err
+		}
+		subs = make([]webhook.Subscription, len(deliveries))
+		for i, d := range deliveries {
+			if subs[i], err = uc.webhookService.GetSubscription(ctx, d.SubscriptionID); err != nil {
+				return err
+			}
+		}
+		return nil
+	})
+	if err != nil || len(deliveries) == 0 {
+		return 0, err
+	}
+
+	attempts := make([]webhook.Attempt, len(deliveries))
+	for i, d := range deliveries {
+		attempts[i] = uc.send(ctx, subs[i], d)
+	}
+
+	err = uc.txManager.Do(ctx, func(ctx context.Context) error {
+		for i, d := range deliveries {
+			if _, err := uc.webhookService.RecordAttempt(ctx, d, attempts[i]); err != nil {
+				return err
+			}
+		}
+		return nil
+	})
+	if err != nil {
+		return 0, err
+	}
+	return len(deliveries), nil
+}
+
+func (uc *WebhookUseCase) send(ctx context.Context, sub webhook.Subscription, d webhook.Delivery) webhook.Attempt {
+	start := uc.now()
+	status, err := uc.sender.Send(ctx, webhook.NewRequest(sub, d, start.Unix()))
+	a := webhook.Attempt{
+		DeliveryID:  d.ID,
+		AttemptedAt: start,
+		StatusCode:  status,
+		Duration:    uc.now().Sub(start),
+	}
+	switch {
+	case err != nil:
+		a.Error = err.Error()
+	case !a.Succeeded():
+		a.Error = "unexpected status " + strconv.Itoa(status)
+	}
+	return a
+}
+
+// Run dispatches deliveries until ctx is cancelled. It keeps going while full batches
+// are found and otherwise waits interval before polling again.
+func (uc *WebhookUseCase) Run(ctx context.Context, interval time.Duration) {
+	for {
+		n, err := uc.DispatchDue(ctx)
+		if err != nil && ctx.Err() == nil {
+			log.Println("webhook dispatcher:", err)
+		}
+		if err == nil && n == uc.batchSize {
+			continue
+		}
+
+		select {
+		case <-ctx.Done():
+			return
+		case <-time.After(interval):
+		}
+	}
+}
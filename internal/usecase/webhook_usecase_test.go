@@ -0,0 +1,307 @@
+package usecase
+
+import (
+	"bytes"
+	"context"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"slices"
+	"strconv"
+	"sync"
+	"testing"
+	"time"
+
+	"exchange/internal/adapters/sender"
+	"exchange/internal/domain/event"
+	"exchange/internal/domain/webhook"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// webhookStore keeps subscriptions, deliveries and attempts in memory.
+type webhookStore struct {
+	subscriptions []webhook.Subscription
+	deliveries    []webhook.Delivery
+	attempts      []webhook.Attempt
+}
+
+func (s *webhookStore) CreateSubscription(_ context.Context, sub webhook.Subscription) error {
+	s.subscriptions = append(s.subscriptions, sub)
+	return nil
+}
+
+func (s *webhookStore) GetSubscriptionByID(_ context.Context, id string) (webhook.Subscription, error) {
+	for _, sub := range s.subscriptions {
+		if sub.ID == id {
+			return sub, nil
+		}
+	}
+	return webhook.Subscription{}, webhook.ErrSubscriptionNotFound
+}
+
+func (s *webhookStore) ListSubscriptionsByUserID(_ context.Context, userID string) ([]webhook.Subscription, error) {
+	var results []webhook.Subscription
+	for _, sub := range s.subscriptions {
+		if sub.UserID == userID {
+			results = append(results, sub)
+		}
+	}
+	return results, nil
+}
+
+func (s *webhookStore) ListSubscriptionsByEventType(_ context.Context, t event.Type) ([]webhook.Subscription, error) {
+	var results []webhook.Subscription
+	for _, sub := range s.subscriptions {
+		if slices.Contains(sub.EventTypes, t) {
+			results = append(results, sub)
+		}
+	}
+	return results, nil
+}
+
+func (s *webhookStore) DeleteSubscription(_ context.Context, id string) error {
+	s.subscriptions = slices.DeleteFunc(s.subscriptions, func(sub webhook.Subscription) bool { return sub.ID == id })
+	s.deliveries = slices.DeleteFunc(s.deliveries, func(d webhook.Delivery) bool { return d.SubscriptionID == id })
+	return nil
+}
+
+func (s *webhookStore) CreateDelivery(_ context.Context, d webhook.Delivery) error {
+	s.deliveries = append(s.deliveries, d)
+	return nil
+}
+
+func (s *webhookStore) GetDeliveryByID(_ context.Context, id string) (webhook.Delivery, error) {
+	for _, d := range s.deliveries {
+		if d.ID == id {
+			return d, nil
+		}
+	}
+	return webhook.Delivery{}, webhook.ErrDeliveryNotFound
+}
+
+func (s *webhookStore) ListDeliveries(_ context.Context, filter webhook.DeliveryFilter, limit, offset int) ([]webhook.Delivery, error) {
+	return s.deliveries, nil
+}
+
+func (s *webhookStore) ListDueDeliveries(_ context.Context, now time.Time, limit int) ([]webhook.Delivery, error) {
+	var results []webhook.Delivery
+	for _, d := range s.deliveries {
+		if d.Status == webhook.DeliveryPending && !d.NextAttemptAt.After(now) && len(results) < limit {
+			results = append(results, d)
+		}
+	}
+	return results, nil
+}
+
+func (s *webhookStore) UpdateDelivery(_ context.Context, d webhook.Delivery) error {
+	for i := range s.deliveries {
+		if s.deliveries[i].ID == d.ID {
+			s.deliveries[i] = d
+			return nil
+		}
+	}
+	return webhook.ErrDeliveryNotFound
+}
+
+func (s *webhookStore) RecordAttempt(_ context.Context, a webhook.Attempt) error {
+	s.attempts = append(s.attempts, a)
+	return nil
+}
+
+func (s *webhookStore) ListAttempts(_ context.Context, deliveryID string) ([]webhook.Attempt, error) {
+	var results []webhook.Attempt
+	for _, a := range s.attempts {
+		if a.DeliveryID == deliveryID {
+			results = append(results, a)
+		}
+	}
+	return results, nil
+}
+
+// subscriber is an httptest endpoint that answers with the queued status codes and
+// checks the signature of every request.
+type subscriber struct {
+	t        *testing.T
+	secret   string
+	mu       sync.Mutex
+	statuses []int
+	received []string
+}
+
+func (s *subscriber) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	body, err := io.ReadAll(r.Body)
+	require.NoError(s.t, err)
+	ts, err := strconv.ParseInt(r.Header.Get(sender.HeaderTimestamp), 10, 64)
+	require.NoError(s.t, err)
+	assert.Equal(s.t, webhook.Sign(s.secret, ts, body), r.Header.Get(sender.HeaderSignature))
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.received = append(s.received, r.Header.Get(sender.HeaderEventID))
+	status := http.StatusOK
+	if len(s.statuses) > 0 {
+		status, s.statuses = s.statuses[0], s.statuses[1:]
+	}
+	w.WriteHeader(status)
+}
+
+// endpointSender POSTs every delivery to url, standing in for the subscriber's public
+// host, since sender.HTTP refuses the loopback addresses httptest servers listen on.
+type endpointSender struct {
+	url string
+}
+
+func (s endpointSender) Send(ctx context.Context, req webhook.Request) (int, error) {
+	httpReq, err := http.NewRequestWithContext(ctx, http.MethodPost, s.url, bytes.NewReader(req.Body))
+	if err != nil {
+		return 0, err
+	}
+	httpReq.Header.Set(sender.HeaderEventID, req.EventID)
+	httpReq.Header.Set(sender.HeaderTimestamp, strconv.FormatInt(req.Timestamp, 10))
+	httpReq.Header.Set(sender.HeaderSignature, req.Signature)
+	resp, err := http.DefaultClient.Do(httpReq)
+	if err != nil {
+		return 0, err
+	}
+	resp.Body.Close()
+	return resp.StatusCode, nil
+}
+
+func TestWebhookUseCase_Dispatch(t *testing.T) {
+	ctx := context.Background()
+	store := new(webhookStore)
+	service := webhook.NewWebhookService(store, store, webhook.RetryPolicy{MaxAttempts: 2})
+	mockTxManager := new(MockTransactionManager)
+	mockTxManager.DoFn = func(ctx context.Context, fn func(context.Context) error) error {
+		return fn(ctx)
+	}
+	endpoint := &subscriber{t: t, statuses: []int{http.StatusInternalServerError, http.StatusServiceUnavailable}}
+	srv := httptest.NewServer(endpoint)
+	defer srv.Close()
+	useCase := NewWebhookUseCase(service, endpointSender{url: srv.URL}, mockTxManager, 10, time.Second)
+
+	sub, err := useCase.CreateSubscription(ctx, "user1", "https://partner.test/hooks", []event.Type{event.TypeFundsDeposited})
+	require.NoError(t, err)
+	endpoint.secret = sub.Secret
+
+	deposit, err := event.NewEvent("ev1", event.TypeFundsDeposited, "user1", event.FundsDeposited{UserID: "user1", Amount: 100})
+	require.NoError(t, err)
+	other, err := event.NewEvent("ev2", event.TypeFundsDeposited, "user2", event.FundsDeposited{UserID: "user2", Amount: 100})
+	require.NoError(t, err)
+	require.NoError(t, useCase.HandleEvent(ctx, deposit))
+	require.NoError(t, useCase.HandleEvent(ctx, other))
+	require.Len(t, store.deliveries, 1, "only the owner's events are delivered")
+	deliveryID := store.deliveries[0].ID
+
+	n, err := useCase.DispatchDue(ctx)
+	require.NoError(t, err)
+	assert.Equal(t, 1, n)
+	assert.Equal(t, webhook.DeliveryPending, store.deliveries[0].Status)
+
+	n, err = useCase.DispatchDue(ctx)
+	require.NoError(t, err)
+	assert.Equal(t, 1, n)
+	assert.Equal(t, webhook.DeliveryDead, store.deliveries[0].Status)
+	assert.Equal(t, "unexpected status 503", store.deliveries[0].LastError)
+
+	n, err = useCase.DispatchDue(ctx)
+	require.NoError(t, err)
+	assert.Zero(t, n, "dead deliveries are not retried")
+
+	_, err = useCase.ReplayDelivery(ctx, "user2", deliveryID)
+	assert.Equal(t, webhook.ErrDeliveryNotFound, err)
+	_, err = useCase.ReplayDelivery(ctx, "user1", deliveryID)
+	require.NoError(t, err)
+
+	n, err = useCase.DispatchDue(ctx)
+	require.NoError(t, err)
+	assert.Equal(t, 1, n)
+	assert.Equal(t, webhook.DeliverySucceeded, store.deliveries[0].Status)
+
+	attempts, err := useCase.ListAttempts(ctx, "user1", deliveryID)
+	require.NoError(t, err)
+	require.Len(t, attempts, 3)
+	assert.Equal(t, []int{500, 503, 200}, []int{attempts[0].StatusCode, attempts[1].StatusCode, attempts[2].StatusCode})
+	assert.Equal(t, []string{"ev1", "ev1", "ev1"}, endpoint.received)
+}
+
+func TestWebhookUseCase_DispatchUnreachable(t *testing.T) {
+	ctx := context.Background()
+	store := new(webhookStore)
+	service := webhook.NewWebhookService(store, store, webhook.DefaultRetryPolicy)
+	mockTxManager := new(MockTransactionManager)
+	mockTxManager.DoFn = func(ctx context.Context, fn func(context.Context) error) error {
+		return fn(ctx)
+	}
+	srv := httptest.NewServer(http.NotFoundHandler())
+	srv.Close()
+	useCase := NewWebhookUseCase(service, endpointSender{url: srv.URL}, mockTxManager, 10, time.Second)
+
+	_, err := useCase.CreateSubscription(ctx, "user1", "https://partner.test/hooks", []event.Type{event.TypeWalletCreated})
+	require.NoError(t, err)
+	created, err := event.NewEvent("ev1", event.TypeWalletCreated, "user1", event.WalletCreated{UserID: "user1", Currency: "USD"})
+	require.NoError(t, err)
+	require.NoError(t, useCase.HandleEvent(ctx, created))
+
+	n, err := useCase.DispatchDue(ctx)
+	require.NoError(t, err)
+	assert.Equal(t, 1, n)
+
+	d := store.deliveries[0]
+	assert.Equal(t, webhook.DeliveryPending, d.Status)
+	assert.NotEmpty(t, d.LastError)
+	assert.True(t, d.NextAttemptAt.After(time.Now()), "failed deliveries back off")
+	assert.Zero(t, store.attempts[0].StatusCode)
+}
+
+// probeSender answers every delivery with 200 after running onSend.
+type probeSender struct {
+	onSend func(ctx context.Context)
+}
+
+func (s probeSender) Send(ctx context.Context, _ webhook.Request) (int, error) {
+	s.onSend(ctx)
+	return http.StatusOK, nil
+}
+
+func TestWebhookUseCase_DispatchOutsideTransaction(t *testing.T) {
+	ctx := context.Background()
+	store := new(webhookStore)
+	service := webhook.NewWebhookService(store, store, webhook.DefaultRetryPolicy)
+	inTx := false
+	transactions := 0
+	mockTxManager := new(MockTransactionManager)
+	mockTxManager.DoFn = func(ctx context.Context, fn func(context.Context) error) error {
+		inTx = true
+		defer func() { inTx = false }()
+		transactions++
+		return fn(ctx)
+	}
+
+	var useCase *WebhookUseCase
+	sent := 0
+	useCase = NewWebhookUseCase(service, probeSender{onSend: func(ctx context.Context) {
+		sent++
+		assert.False(t, inTx, "deliveries are sent outside the transaction")
+
+		// Another dispatcher running meanwhile finds the claimed delivery not yet due.
+		n, err := useCase.DispatchDue(ctx)
+		require.NoError(t, err)
+		assert.Zero(t, n)
+	}}, mockTxManager, 10, time.Second)
+
+	_, err := useCase.CreateSubscription(ctx, "user1", "https://partner.test/hooks", []event.Type{event.TypeWalletCreated})
+	require.NoError(t, err)
+	created, err := event.NewEvent("ev1", event.TypeWalletCreated, "user1", event.WalletCreated{UserID: "user1", Currency: "USD"})
+	require.NoError(t, err)
+	require.NoError(t, useCase.HandleEvent(ctx, created))
+
+	n, err := useCase.DispatchDue(ctx)
+	require.NoError(t, err)
+	assert.Equal(t, 1, n)
+	assert.Equal(t, 1, sent)
+	assert.Equal(t, webhook.DeliverySucceeded, store.deliveries[0].Status)
+	assert.Equal(t, 3, transactions, "one to claim, one to record, and the other dispatcher's claim")
+}
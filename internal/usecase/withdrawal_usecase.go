@@ -0,0 +1,216 @@
+package usecase
+
+import (
+	"context"
+	"errors"
+	"log"
+	"strings"
+	"time"
+
+	"exchange/internal/domain/audit"
+	"exchange/internal/domain/limit"
+	"exchange/internal/domain/risk"
+	"exchange/internal/domain/transaction"
+)
+
+// expiryBatchSize is how many stale withdrawals ExpireWithdrawals cancels at a time.
+const expiryBatchSize = 100
+
+// WithdrawalPolicy decides which withdrawals wait for an admin's approval. The zero value
+// lets every withdrawal through.
+type WithdrawalPolicy struct {
+	// Thresholds maps a currency code to the largest withdrawal in it made without approval.
+	Thresholds map[string]int64
+	// NewWalletAge is how long after a wallet is created all its withdrawals need approval.
+	NewWalletAge time.Duration
+	// ApprovalTTL is how long a withdrawal awaits approval before it expires; zero means never.
+	ApprovalTTL time.Duration
+}
+
+// withdrawalApprovalReason returns why withdrawing amount of currency from userID's wallet
+// needs approval under the withdrawal policy or its risk assessment, or "" when it does not.
+func (uc *WalletUseCase) withdrawalApprovalReason(ctx context.Context, userID string, amount int64, currency string, assessment risk.Assessment) (string, error) {
+	policy := uc.withdrawalPolicy
+	if threshold, ok := policy.Thresholds[strings.ToUpper(currency)]; ok && amount > threshold {
+		return "amount above the approval threshold", nil
+	}
+	if assessment.Decision == risk.DecisionReview {
+		return "flagged by risk rules: " + strings.Join(assessment.RuleIDs, ", "), nil
+	}
+	if policy.NewWalletAge > 0 {
+		w, err := uc.walletService.GetWallet(ctx, userID)
+		if err != nil {
+			return "", err
+		}
+		if time.Since(w.CreatedAt) < policy.NewWalletAge {
+			return "wallet created recently", nil
+		}
+	}
+	return "", nil
+}
+
+// RequestWithdrawal logs a pending withdrawal to be paid out by an external system, such as
+// a bank, to destination if given, and holds the funds until it completes, fails or is
+// cancelled. Only a block by the sanctions screening or the risk rules stops it, as the
+// withdrawal is settled by hand anyway.
+func (uc *WalletUseCase) RequestWithdrawal(ctx context.Context, userID string, amount int64, currency, destination string) (transaction.Transaction, error) {
+	if _, err := uc.screen(ctx, withdrawalRiskRequest(userID, amount, currency, destination)); err != nil {
+		uc.recordFailure(ctx, audit.NewEntry(audit.ActionRequestWithdrawal, userID), err)
+		return transaction.Transaction{}, err
+	}
+
+	var result transaction.Transaction
+	err := uc.audited(ctx, audit.ActionRequestWithdrawal, []string{userID}, func(ctx context.Context, e *audit.Entry) error {
+		if err := uc.checkWithdrawalCap(ctx, userID, amount); err != nil {
+			return err
+		}
+		if err := uc.checkLimits(ctx, userID, limit.OperationWithdrawal, amount, currency); err != nil {
+			return err
+		}
+		if err := uc.walletService.Hold(ctx, userID, amount); err != nil {
+			return err
+		}
+		tx, err := uc.transactionService.LogTransaction(ctx, userID, "", amount, currency, transaction.TransactionTypeWithdraw, transaction.AsPending())
+		if err != nil {
+			return err
+		}
+		result = tx
+		e.TransactionID = tx.ID
+		return nil
+	})
+	if err != nil {
+		return transaction.Transaction{}, err
+	}
+	return result, nil
+}
+
+// CancelWithdrawal cancels a pending withdrawal and releases the held funds.
+func (uc *WalletUseCase) CancelWithdrawal(ctx context.Context, id string) (transaction.Transaction, error) {
+	return uc.settle(ctx, audit.ActionCancelWithdrawal, id, func(ctx context.Context) (transaction.Transaction, error) {
+		return uc.transactionService.TransitionStatus(ctx, id, transaction.StatusCancelled, "")
+	})
+}
+
+// UpdateTransactionStatus moves a pending or processing transaction to status, capturing
+// the held funds when it completes and releasing them when it fails or is cancelled.
+func (uc *WalletUseCase) UpdateTransactionStatus(ctx context.Context, id string, status transaction.Status) (transaction.Transaction, error) {
+	return uc.settle(ctx, audit.ActionTransactionStatus, id, func(ctx context.Context) (transaction.Transaction, error) {
+		return uc.transactionService.TransitionStatus(ctx, id, status, "")
+	})
+}
+
+// ReviewWithdrawal completes a withdrawal awaiting approval and takes the held funds out
+// of the wallet when approved, or fails it for reason and releases them when rejected.
+func (uc *WalletUseCase) ReviewWithdrawal(ctx context.Context, id string, approved bool, reason string) (transaction.Transaction, error) {
+	action := audit.ActionRejectWithdrawal
+	if approved {
+		action = audit.ActionApproveWithdrawal
+	}
+	return uc.settle(ctx, action, id, func(ctx context.Context) (transaction.Transaction, error) {
+		return uc.transactionService.ReviewTransaction(ctx, id, approved, reason)
+	})
+}
+
+// ExpireWithdrawals cancels up to one batch of withdrawals that have awaited approval for
+// longer than the policy's ApprovalTTL, releasing their funds, and returns how many it
+// cancelled. Withdrawals reviewed in the meantime are skipped.
+func (uc *WalletUseCase) ExpireWithdrawals(ctx context.Context, now time.Time) (int, error) {
+	if uc.withdrawalPolicy.ApprovalTTL <= 0 {
+		return 0, nil
+	}
+
+	filter := transaction.SearchFilter{
+		Type:   transaction.TransactionTypeWithdraw,
+		Status: transaction.StatusAwaitingApproval,
+		To:     now.Add(-uc.withdrawalPolicy.ApprovalTTL),
+	}
+	stale, err := uc.transactionService.SearchTransactions(ctx, filter, expiryBatchSize, 0)
+	if err != nil {
+		return 0, err
+	}
+
+	expired := 0
+	for _, tx := range stale {
+		_, err := uc.settle(ctx, audit.ActionExpireWithdrawal, tx.ID, func(ctx context.Context) (transaction.Transaction, error) {
+			return uc.transactionService.TransitionStatus(ctx, tx.ID, transaction.StatusCancelled, "approval expired")
+		})
+		if errors.Is(err, transaction.ErrInvalidStatusTransition) {
+			continue
+		}
+		if err != nil {
+			return expired, err
+		}
+		expired++
+	}
+	return expired, nil
+}
+
+// RunWithdrawalExpiry expires stale withdrawals until ctx is cancelled. It keeps going
+// while full batches are found and otherwise waits interval before looking again.
+func (uc *WalletUseCase) RunWithdrawalExpiry(ctx context.Context, interval time.Duration) {
+	for {
+		n, err := uc.ExpireWithdrawals(ctx, time.Now())
+		if err != nil && ctx.Err() == nil {
+			log.Println("withdrawal expiry:", err)
+		}
+		if err == nil && n == expiryBatchSize {
+			continue
+		}
+
+		select {
+		case <-ctx.Done():
+			return
+		case <-time.After(interval):
+		}
+	}
+}
+
+// settle runs transition, which moves the transaction id to a new status, and then
+// captures the held funds if it completed or releases them if it failed or was cancelled.
+func (uc *WalletUseCase) settle(ctx context.Context, action audit.Action, id string, transition func(ctx context.Context) (transaction.Transaction, error)) (transaction.Transaction, error) {
+	current, err := uc.transactionService.GetTransactionByID(ctx, id)
+	if err != nil {
+		return transaction.Transaction{}, err
+	}
+
+	var userIDs []string
+	for _, userID := range []string{current.FromUserID, current.ToUserID} {
+		if userID != "" {
+			userIDs = append(userIDs, userID)
+		}
+	}
+
+	var result transaction.Transaction
+	err = uc.audited(ctx, action, userIDs, func(ctx context.Context, e *audit.Entry) error {
+		e.TransactionID = id
+		tx, err := transition(ctx)
+		if err != nil {
+			return err
+		}
+		result = tx
+
+		switch tx.Status {
+		case transaction.StatusCompleted:
+			if tx.FromUserID != "" {
+				if err := uc.walletService.Capture(ctx, tx.FromUserID, tx.Amount); err != nil {
+					return err
+				}
+			}
+			if tx.ToUserID != "" {
+				if err := uc.walletService.Deposit(ctx, tx.ToUserID, tx.Amount); err != nil {
+					return err
+				}
+			}
+			return uc.recordTransactionEvent(ctx, tx)
+		case transaction.StatusFailed, transaction.StatusCancelled:
+			if tx.FromUserID != "" {
+				return uc.walletService.Release(ctx, tx.FromUserID, tx.Amount)
+			}
+		}
+		return nil
+	})
+	if err != nil {
+		return transaction.Transaction{}, err
+	}
+	return result, nil
+}
@@ -0,0 +1,155 @@
+// withdrawal_usecase_test.go
+package usecase
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"exchange/internal/domain/audit"
+	"exchange/internal/domain/event"
+	"exchange/internal/domain/transaction"
+	"exchange/internal/domain/wallet"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+)
+
+func TestWalletUseCase_WithdrawalApproval(t *testing.T) {
+	ctx := context.Background()
+	policy := WithdrawalPolicy{
+		Thresholds:   map[string]int64{"USD": 1000},
+		NewWalletAge: 24 * time.Hour,
+		ApprovalTTL:  time.Hour,
+	}
+	awaiting := transaction.Transaction{ID: "tx1", FromUserID: "user1", Amount: 5000, Currency: "USD", Type: transaction.TransactionTypeWithdraw, Status: transaction.StatusAwaitingApproval}
+	oldWallet := wallet.Wallet{UserID: "user1", Balance: 10000, CreatedAt: time.Now().Add(-48 * time.Hour)}
+
+	newUseCase := func() (*WalletUseCase, *MockWalletService, *MockTransactionService) {
+		mockWalletService := new(MockWalletService)
+		mockTransactionService := new(MockTransactionService)
+		mockTxManager := new(MockTransactionManager)
+		mockTxManager.DoFn = func(ctx context.Context, fn func(context.Context) error) error {
+			return fn(ctx)
+		}
+		mockTransactionService.On("GetTransactionByID", ctx, "tx1").Return(awaiting, nil)
+		deps := testDependencies(mockWalletService, mockTransactionService, mockTxManager)
+		deps.WithdrawalPolicy = policy
+		return NewWalletUseCase(deps), mockWalletService, mockTransactionService
+	}
+	withStatus := func(status transaction.Status, reason string) transaction.Transaction {
+		tx := awaiting
+		tx.Status = status
+		tx.StatusReason = reason
+		return tx
+	}
+
+	t.Run("below the threshold", func(t *testing.T) {
+		useCase, mockWalletService, mockTransactionService := newUseCase()
+		mockWalletService.On("GetWallet", ctx, "user1").Return(oldWallet, nil)
+		mockWalletService.On("Withdraw", ctx, "user1", int64(1000)).Return(nil)
+		mockTransactionService.On("LogTransaction", ctx, "user1", "", int64(1000), "USD", transaction.TransactionTypeWithdraw).
+			Return(transaction.Transaction{ID: "tx2", Status: transaction.StatusCompleted}, nil)
+
+		tx, err := useCase.Withdraw(ctx, "user1", 1000, "USD", "")
+
+		require.NoError(t, err)
+		assert.Equal(t, transaction.StatusCompleted, tx.Status)
+		mockWalletService.AssertNotCalled(t, "Hold", mock.Anything, mock.Anything, mock.Anything)
+	})
+
+	t.Run("above the threshold", func(t *testing.T) {
+		useCase, mockWalletService, mockTransactionService := newUseCase()
+		mockWalletService.On("Hold", ctx, "user1", int64(5000)).Return(nil)
+		mockTransactionService.On("LogTransaction", ctx, "user1", "", int64(5000), "usd", transaction.TransactionTypeWithdraw).
+			Return(transaction.Transaction{ID: "tx1", Status: transaction.StatusCompleted}, nil)
+
+		tx, err := useCase.Withdraw(ctx, "user1", 5000, "usd", "")
+
+		require.NoError(t, err)
+		assert.Equal(t, transaction.StatusAwaitingApproval, tx.Status)
+		assert.Equal(t, "amount above the approval threshold", tx.StatusReason)
+		mockWalletService.AssertNotCalled(t, "Withdraw", mock.Anything, mock.Anything, mock.Anything)
+		assert.Empty(t, useCase.eventService.(*eventRecorder).events, "no funds have moved yet")
+		entries := useCase.auditService.(*auditRecorder).entries
+		require.Len(t, entries, 1)
+		assert.Equal(t, "tx1", entries[0].TransactionID)
+	})
+
+	t.Run("from a new wallet", func(t *testing.T) {
+		useCase, mockWalletService, mockTransactionService := newUseCase()
+		mockWalletService.On("GetWallet", ctx, "user1").Return(wallet.Wallet{UserID: "user1", CreatedAt: time.Now()}, nil)
+		mockWalletService.On("Hold", ctx, "user1", int64(100)).Return(nil)
+		mockTransactionService.On("LogTransaction", ctx, "user1", "", int64(100), "EUR", transaction.TransactionTypeWithdraw).
+			Return(transaction.Transaction{ID: "tx1"}, nil)
+
+		tx, err := useCase.Withdraw(ctx, "user1", 100, "EUR", "")
+
+		require.NoError(t, err)
+		assert.Equal(t, transaction.StatusAwaitingApproval, tx.Status)
+		assert.Equal(t, "wallet created recently", tx.StatusReason)
+	})
+
+	t.Run("without available funds", func(t *testing.T) {
+		useCase, mockWalletService, mockTransactionService := newUseCase()
+		mockWalletService.On("Hold", ctx, "user1", int64(5000)).Return(wallet.ErrInsufficientFunds)
+
+		_, err := useCase.Withdraw(ctx, "user1", 5000, "USD", "")
+
+		assert.ErrorIs(t, err, wallet.ErrInsufficientFunds)
+		mockTransactionService.AssertNotCalled(t, "LogTransaction", mock.Anything, mock.Anything, mock.Anything, mock.Anything, mock.Anything, mock.Anything)
+	})
+
+	t.Run("approval captures the funds", func(t *testing.T) {
+		useCase, mockWalletService, mockTransactionService := newUseCase()
+		mockTransactionService.On("ReviewTransaction", ctx, "tx1", true, "").Return(withStatus(transaction.StatusCompleted, ""), nil)
+		mockWalletService.On("Capture", ctx, "user1", int64(5000)).Return(nil)
+
+		tx, err := useCase.ReviewWithdrawal(ctx, "tx1", true, "")
+
+		require.NoError(t, err)
+		assert.Equal(t, transaction.StatusCompleted, tx.Status)
+		mockWalletService.AssertExpectations(t)
+		events := useCase.eventService.(*eventRecorder).events
+		require.Len(t, events, 1)
+		assert.Equal(t, event.TypeFundsWithdrawn, events[0].Type)
+		entries := useCase.auditService.(*auditRecorder).entries
+		require.Len(t, entries, 1)
+		assert.Equal(t, audit.ActionApproveWithdrawal, entries[0].Action)
+	})
+
+	t.Run("rejection releases the funds", func(t *testing.T) {
+		useCase, mockWalletService, mockTransactionService := newUseCase()
+		mockTransactionService.On("ReviewTransaction", ctx, "tx1", false, "suspicious").Return(withStatus(transaction.StatusFailed, "suspicious"), nil)
+		mockWalletService.On("Release", ctx, "user1", int64(5000)).Return(nil)
+
+		tx, err := useCase.ReviewWithdrawal(ctx, "tx1", false, "suspicious")
+
+		require.NoError(t, err)
+		assert.Equal(t, "suspicious", tx.StatusReason)
+		mockWalletService.AssertExpectations(t)
+		assert.Empty(t, useCase.eventService.(*eventRecorder).events)
+		entries := useCase.auditService.(*auditRecorder).entries
+		require.Len(t, entries, 1)
+		assert.Equal(t, audit.ActionRejectWithdrawal, entries[0].Action)
+	})
+
+	t.Run("expiry cancels stale requests", func(t *testing.T) {
+		useCase, mockWalletService, mockTransactionService := newUseCase()
+		now := time.Now()
+		filter := transaction.SearchFilter{Type: transaction.TransactionTypeWithdraw, Status: transaction.StatusAwaitingApproval, To: now.Add(-time.Hour)}
+		reviewed := transaction.Transaction{ID: "tx2"}
+		mockTransactionService.On("SearchTransactions", ctx, filter, expiryBatchSize, 0).Return([]transaction.Transaction{reviewed, awaiting}, nil)
+		mockTransactionService.On("GetTransactionByID", ctx, "tx2").Return(reviewed, nil)
+		mockTransactionService.On("TransitionStatus", ctx, "tx2", transaction.StatusCancelled, "approval expired").Return(transaction.Transaction{}, transaction.ErrInvalidStatusTransition)
+		mockTransactionService.On("TransitionStatus", ctx, "tx1", transaction.StatusCancelled, "approval expired").Return(withStatus(transaction.StatusCancelled, "approval expired"), nil)
+		mockWalletService.On("Release", ctx, "user1", int64(5000)).Return(nil)
+
+		n, err := useCase.ExpireWithdrawals(ctx, now)
+
+		require.NoError(t, err)
+		assert.Equal(t, 1, n)
+		mockWalletService.AssertExpectations(t)
+	})
+}